@@ -119,6 +119,7 @@ func createManager(config *rest.Config, namespace string) (manager.Manager, erro
 
 func createCsiOptions() dtcsi.CSIOptions {
 	return dtcsi.CSIOptions{
+		NodeId:  os.Getenv(env.NodeName),
 		RootDir: dtcsi.DataPath,
 	}
 }
@@ -0,0 +1,13 @@
+package inject_preview
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newInMemoryClient serves the seed objects to the webhook of the preview.
+// Everything the webhook writes, e.g. the secrets it replicates into the pod namespace, only ends up in memory and never reaches the cluster.
+func newInMemoryClient(objects ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+}
@@ -0,0 +1,231 @@
+package inject_preview
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
+	"github.com/Dynatrace/dynatrace-operator/pkg/version"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	podmutation "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+const (
+	use = "inject-preview"
+
+	filenameFlagName           = "filename"
+	filenameFlagShorthand      = "f"
+	dynakubeFlagName           = "dynakube"
+	dynakubeFlagShorthand      = "d"
+	namespaceFlagName          = "namespace"
+	namespaceFlagShorthand     = "n"
+	podNamespaceFlagName       = "pod-namespace"
+	resourcesFlagName          = "resources"
+	resourcesFlagShorthand     = "r"
+	webhookImageFlagName       = "webhook-image"
+	clusterIDFlagName          = "cluster-id"
+	defaultPodNamespace        = "default"
+	defaultPreviewWebhookImage = "docker.io/dynatrace/dynatrace-operator"
+)
+
+var (
+	filenameFlagValue     string
+	dynakubeFlagValue     string
+	namespaceFlagValue    string
+	podNamespaceFlagValue string
+	resourcesFlagValue    []string
+	webhookImageFlagValue string
+	clusterIDFlagValue    string
+
+	log = logd.Get().WithName("inject-preview")
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: "Preview the mutation of a pod by the webhook",
+		Long: "Runs the pod mutation of the webhook for the given pod or workload manifest and prints the resulting JSON patch, " +
+			"together with the reasons if (a part of) the injection would be skipped. " +
+			"The objects needed by the webhook are read from the cluster, or only from the files given via --resources, in which case no cluster is needed.",
+		RunE:         run(),
+		SilenceUsage: true,
+	}
+
+	addFlags(cmd)
+
+	return cmd
+}
+
+func addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&filenameFlagValue, filenameFlagName, filenameFlagShorthand, "", "Manifest of the pod or workload (Deployment, StatefulSet, DaemonSet, ReplicaSet, Job, CronJob) to preview.")
+	cmd.Flags().StringVarP(&dynakubeFlagValue, dynakubeFlagName, dynakubeFlagShorthand, "", "DynaKube the namespace of the pod is assigned to, defaults to the assignment in the cluster or the only DynaKube of the resources.")
+	cmd.Flags().StringVarP(&namespaceFlagValue, namespaceFlagName, namespaceFlagShorthand, env.DefaultNamespace(), "Namespace of the operator and the DynaKubes.")
+	cmd.Flags().StringVar(&podNamespaceFlagValue, podNamespaceFlagName, "", "Namespace of the pod, defaults to the namespace of the manifest or \""+defaultPodNamespace+"\".")
+	cmd.Flags().StringSliceVarP(&resourcesFlagValue, resourcesFlagName, resourcesFlagShorthand, nil, "Files with the objects the webhook reads (DynaKubes, Namespaces, Secrets, ...), instead of reading them from the cluster.")
+	cmd.Flags().StringVar(&webhookImageFlagValue, webhookImageFlagName, "", "Image of the webhook used for the init-container, defaults to the image of the webhook deployment in the cluster.")
+	cmd.Flags().StringVar(&clusterIDFlagValue, clusterIDFlagName, "", "UID of the kube-system namespace, defaults to the one of the cluster.")
+
+	_ = cmd.MarkFlagRequired(filenameFlagName)
+}
+
+func run() func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		// keep stdout free for the result
+		logd.SetOutput(os.Stderr)
+		version.LogVersion()
+
+		pod, workload, err := podFromManifest(filenameFlagValue)
+		if err != nil {
+			return err
+		}
+
+		podNamespace := getPodNamespace(pod)
+
+		var seed *seedObjects
+		if len(resourcesFlagValue) > 0 {
+			seed, err = seedFromFiles(resourcesFlagValue...)
+		} else {
+			seed, err = seedFromCluster(cmd.Context(), namespaceFlagValue, podNamespace)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if workload != nil {
+			seed.objects = append(seed.objects, workload)
+		}
+
+		result, err := runPreview(cmd.Context(), seed, pod, podNamespace)
+		if err != nil {
+			return err
+		}
+
+		return writeResult(os.Stdout, result)
+	}
+}
+
+func getPodNamespace(pod *corev1.Pod) string {
+	switch {
+	case podNamespaceFlagValue != "":
+		return podNamespaceFlagValue
+	case pod.Namespace != "":
+		return pod.Namespace
+	default:
+		return defaultPodNamespace
+	}
+}
+
+func runPreview(ctx context.Context, seed *seedObjects, pod *corev1.Pod, podNamespace string) (*podmutation.PreviewResult, error) {
+	objects, err := assignNamespace(seed.objects, podNamespace, namespaceFlagValue, dynakubeFlagValue)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookImage := webhookImageFlagValue
+	if webhookImage == "" {
+		webhookImage = seed.webhookImage
+	}
+
+	if webhookImage == "" {
+		webhookImage = defaultPreviewWebhookImage + ":" + version.Version
+	}
+
+	clusterID := clusterIDFlagValue
+	if clusterID == "" {
+		clusterID = seed.clusterID
+	}
+
+	return podmutation.Preview(ctx, newInMemoryClient(objects...), pod, podNamespace, podmutation.PreviewConfig{
+		WebhookNamespace: namespaceFlagValue,
+		WebhookImage:     webhookImage,
+		ClusterID:        clusterID,
+	})
+}
+
+// assignNamespace labels the namespace of the pod with the DynaKube, as the operator would do, so the webhook picks it up.
+// If no DynaKube is given, the existing assignment is kept, or the only DynaKube of the objects is used.
+func assignNamespace(objects []client.Object, podNamespace, dkNamespace, dkName string) ([]client.Object, error) {
+	var namespace *corev1.Namespace
+
+	dkNames := []string{}
+
+	for _, obj := range objects {
+		switch typed := obj.(type) {
+		case *corev1.Namespace:
+			if typed.Name == podNamespace {
+				namespace = typed
+			}
+		case *dynakube.DynaKube:
+			if typed.Namespace == "" {
+				typed.Namespace = dkNamespace
+			}
+
+			if typed.Namespace == dkNamespace {
+				dkNames = append(dkNames, typed.Name)
+			}
+		}
+	}
+
+	if namespace == nil {
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: podNamespace}}
+		objects = append(objects, namespace)
+	}
+
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+
+	switch {
+	case dkName != "":
+		namespace.Labels[dtwebhook.InjectionInstanceLabel] = dkName
+	case namespace.Labels[dtwebhook.InjectionInstanceLabel] != "":
+		// assigned by the operator
+	case len(dkNames) == 1:
+		namespace.Labels[dtwebhook.InjectionInstanceLabel] = dkNames[0]
+	default:
+		return nil, errors.Errorf("namespace %s is not assigned to a DynaKube and %d DynaKubes were found in %s, select one with --%s", podNamespace, len(dkNames), dkNamespace, dynakubeFlagName)
+	}
+
+	return objects, nil
+}
+
+func writeResult(out io.Writer, result *podmutation.PreviewResult) error {
+	rawResult, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = fmt.Fprintln(out, string(rawResult))
+
+	return errors.WithStack(err)
+}
+
+func getClusterAPIReader() (client.Reader, error) {
+	kubeConfig, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	k8scluster, err := cluster.New(kubeConfig, func(opts *cluster.Options) {
+		opts.Scheme = scheme.Scheme
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return k8scluster.GetAPIReader(), nil
+}
@@ -0,0 +1,108 @@
+package inject_preview
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	podmutation "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testDkNamespace  = "dynatrace"
+	testPodNamespace = "shop"
+)
+
+func TestAssignNamespace(t *testing.T) {
+	newDynakube := func(name string) *dynakube.DynaKube {
+		return &dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testDkNamespace}}
+	}
+
+	getAssignment := func(t *testing.T, objects []client.Object) string {
+		for _, obj := range objects {
+			if namespace, ok := obj.(*corev1.Namespace); ok && namespace.Name == testPodNamespace {
+				return namespace.Labels[dtwebhook.InjectionInstanceLabel]
+			}
+		}
+
+		t.Fatal("namespace of the pod is missing")
+
+		return ""
+	}
+
+	t.Run("use the given DynaKube", func(t *testing.T) {
+		objects, err := assignNamespace([]client.Object{newDynakube("a"), newDynakube("b")}, testPodNamespace, testDkNamespace, "b")
+		require.NoError(t, err)
+		assert.Equal(t, "b", getAssignment(t, objects))
+	})
+
+	t.Run("use the only DynaKube", func(t *testing.T) {
+		objects, err := assignNamespace([]client.Object{newDynakube("a")}, testPodNamespace, testDkNamespace, "")
+		require.NoError(t, err)
+		assert.Equal(t, "a", getAssignment(t, objects))
+	})
+
+	t.Run("keep the existing assignment", func(t *testing.T) {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   testPodNamespace,
+			Labels: map[string]string{dtwebhook.InjectionInstanceLabel: "b"},
+		}}
+
+		objects, err := assignNamespace([]client.Object{newDynakube("a"), newDynakube("b"), namespace}, testPodNamespace, testDkNamespace, "")
+		require.NoError(t, err)
+		assert.Equal(t, "b", getAssignment(t, objects))
+	})
+
+	t.Run("fail if the DynaKube is ambiguous", func(t *testing.T) {
+		_, err := assignNamespace([]client.Object{newDynakube("a"), newDynakube("b")}, testPodNamespace, testDkNamespace, "")
+		require.Error(t, err)
+	})
+}
+
+func TestReadSeedObjects(t *testing.T) {
+	clt := fake.NewClient(
+		&dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: "dk", Namespace: testDkNamespace}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testPodNamespace}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "kube-system-uid"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "dk", Namespace: testDkNamespace}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: dtwebhook.DeploymentName, Namespace: testDkNamespace},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: dtwebhook.WebhookContainerName, Image: "webhook-image"}},
+			}}},
+		},
+	)
+
+	seed, err := readSeedObjects(context.Background(), clt, testDkNamespace, testPodNamespace)
+	require.NoError(t, err)
+
+	assert.Equal(t, "kube-system-uid", seed.clusterID)
+	assert.Equal(t, "webhook-image", seed.webhookImage)
+
+	names := []string{}
+	for _, obj := range seed.objects {
+		names = append(names, obj.GetNamespace()+"/"+obj.GetName())
+	}
+
+	assert.ElementsMatch(t, []string{testDkNamespace + "/dk", "/" + testPodNamespace, testDkNamespace + "/dk"}, names)
+}
+
+func TestWriteResult(t *testing.T) {
+	var out bytes.Buffer
+
+	require.NoError(t, writeResult(&out, &podmutation.PreviewResult{
+		Reasons: map[string]string{"oneagent.dynatrace.com/reason": "EmptyConnectionInfo"},
+	}))
+
+	assert.JSONEq(t, `{"reasons":{"oneagent.dynatrace.com/reason":"EmptyConnectionInfo"},"patch":null,"injected":false}`, out.String())
+}
@@ -0,0 +1,131 @@
+package inject_preview
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// readObjects decodes all objects of the given (multi-document) YAML or JSON files.
+// Older DynaKube versions are converted to the current one, as the webhook only reads that one.
+func readObjects(paths ...string) ([]client.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme.Scheme).UniversalDeserializer()
+	objects := []client.Object{}
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+
+		for {
+			document, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, errors.WithMessagef(err, "failed to read %s", path)
+			}
+
+			if len(bytes.TrimSpace(document)) == 0 {
+				continue
+			}
+
+			decoded, _, err := decoder.Decode(document, nil, nil)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to decode an object of %s", path)
+			}
+
+			obj, ok := decoded.(client.Object)
+			if !ok {
+				return nil, errors.Errorf("unsupported object of kind %s in %s", decoded.GetObjectKind().GroupVersionKind().Kind, path)
+			}
+
+			if convertible, ok := obj.(conversion.Convertible); ok && obj.GetObjectKind().GroupVersionKind().Kind == "DynaKube" {
+				dk := &dynakube.DynaKube{}
+				if err := convertible.ConvertTo(dk); err != nil {
+					return nil, errors.WithMessagef(err, "failed to convert DynaKube %s", obj.GetName())
+				}
+
+				obj = dk
+			}
+
+			objects = append(objects, obj)
+		}
+	}
+
+	return objects, nil
+}
+
+// podFromManifest returns the pod of the manifest, for workloads the pod of their template.
+// The workload is returned as well, it is set as owner of the pod so the metadata-enrichment can resolve it.
+func podFromManifest(path string) (*corev1.Pod, client.Object, error) {
+	objects, err := readObjects(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(objects) != 1 {
+		return nil, nil, errors.Errorf("%s must contain exactly one pod or workload, found %d objects", path, len(objects))
+	}
+
+	var template *corev1.PodTemplateSpec
+
+	switch workload := objects[0].(type) {
+	case *corev1.Pod:
+		return workload, nil, nil
+	case *appsv1.Deployment:
+		template = &workload.Spec.Template
+	case *appsv1.StatefulSet:
+		template = &workload.Spec.Template
+	case *appsv1.DaemonSet:
+		template = &workload.Spec.Template
+	case *appsv1.ReplicaSet:
+		template = &workload.Spec.Template
+	case *batchv1.Job:
+		template = &workload.Spec.Template
+	case *batchv1.CronJob:
+		template = &workload.Spec.JobTemplate.Spec.Template
+	default:
+		return nil, nil, errors.Errorf("unsupported kind %s in %s, expected a pod or workload", objects[0].GetObjectKind().GroupVersionKind().Kind, path)
+	}
+
+	workload := objects[0]
+	gvk := workload.GetObjectKind().GroupVersionKind()
+
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Pod",
+		},
+		ObjectMeta: *template.ObjectMeta.DeepCopy(),
+		Spec:       *template.Spec.DeepCopy(),
+	}
+	pod.GenerateName = workload.GetName() + "-"
+	pod.Namespace = workload.GetNamespace()
+	pod.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Name:       workload.GetName(),
+			Controller: ptr.To(true),
+		},
+	}
+
+	return pod, workload, nil
+}
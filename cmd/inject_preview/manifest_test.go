@@ -0,0 +1,112 @@
+package inject_preview
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	testDeploymentManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: shop
+spec:
+  selector:
+    matchLabels:
+      app: app
+  template:
+    metadata:
+      labels:
+        app: app
+    spec:
+      containers:
+        - name: app
+          image: nginx
+`
+	testResourcesManifest = `apiVersion: dynatrace.com/v1beta3
+kind: DynaKube
+metadata:
+  name: dk
+  namespace: dynatrace
+spec:
+  apiUrl: https://test.live.dynatrace.com/api
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: kube-system
+  uid: kube-system-uid
+---
+`
+)
+
+func TestReadObjects(t *testing.T) {
+	t.Run("read multiple documents and convert DynaKubes", func(t *testing.T) {
+		objects, err := readObjects(writeManifest(t, testResourcesManifest))
+		require.NoError(t, err)
+		require.Len(t, objects, 2)
+
+		dk, ok := objects[0].(*dynakube.DynaKube)
+		require.True(t, ok)
+		assert.Equal(t, "https://test.live.dynatrace.com/api", dk.Spec.APIURL)
+
+		assert.IsType(t, &corev1.Namespace{}, objects[1])
+	})
+
+	t.Run("fail on unknown kinds", func(t *testing.T) {
+		_, err := readObjects(writeManifest(t, "apiVersion: example.com/v1\nkind: Unknown\nmetadata:\n  name: test\n"))
+		require.Error(t, err)
+	})
+}
+
+func TestPodFromManifest(t *testing.T) {
+	t.Run("use the template of workloads", func(t *testing.T) {
+		pod, workload, err := podFromManifest(writeManifest(t, testDeploymentManifest))
+		require.NoError(t, err)
+
+		assert.IsType(t, &appsv1.Deployment{}, workload)
+		assert.Equal(t, "shop", pod.Namespace)
+		assert.Equal(t, "app-", pod.GenerateName)
+		assert.Equal(t, map[string]string{"app": "app"}, pod.Labels)
+		require.Len(t, pod.Spec.Containers, 1)
+		require.Len(t, pod.OwnerReferences, 1)
+		assert.Equal(t, "Deployment", pod.OwnerReferences[0].Kind)
+		assert.Equal(t, "apps/v1", pod.OwnerReferences[0].APIVersion)
+		assert.Equal(t, "app", pod.OwnerReferences[0].Name)
+	})
+
+	t.Run("use pods as they are", func(t *testing.T) {
+		pod, workload, err := podFromManifest(writeManifest(t, "apiVersion: v1\nkind: Pod\nmetadata:\n  name: test\nspec:\n  containers:\n    - name: app\n      image: nginx\n"))
+		require.NoError(t, err)
+
+		assert.Nil(t, workload)
+		assert.Equal(t, "test", pod.Name)
+	})
+
+	t.Run("fail on multiple objects", func(t *testing.T) {
+		_, _, err := podFromManifest(writeManifest(t, testResourcesManifest))
+		require.Error(t, err)
+	})
+
+	t.Run("fail on objects that are not workloads", func(t *testing.T) {
+		_, _, err := podFromManifest(writeManifest(t, "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: test\n"))
+		require.Error(t, err)
+	})
+}
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
@@ -0,0 +1,117 @@
+package inject_preview
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/container"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubesystem"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// seedObjects are the objects the in-memory client of the preview is created with, plus the values the webhook usually takes from the cluster.
+type seedObjects struct {
+	webhookImage string
+	clusterID    string
+	objects      []client.Object
+}
+
+func seedFromFiles(paths ...string) (*seedObjects, error) {
+	objects, err := readObjects(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := &seedObjects{objects: objects}
+
+	for _, obj := range objects {
+		if namespace, ok := obj.(*corev1.Namespace); ok && namespace.Name == kubesystem.Namespace {
+			seed.clusterID = string(namespace.UID)
+		}
+	}
+
+	return seed, nil
+}
+
+// seedFromCluster reads everything the webhook may read for the pod: the DynaKubes, the namespace of the pod
+// and the Secrets and ConfigMaps of the operator and pod namespace.
+// Nothing is written to the cluster, the webhook only works on the copies.
+func seedFromCluster(ctx context.Context, dkNamespace, podNamespace string) (*seedObjects, error) {
+	apiReader, err := getClusterAPIReader()
+	if err != nil {
+		return nil, err
+	}
+
+	return readSeedObjects(ctx, apiReader, dkNamespace, podNamespace)
+}
+
+func readSeedObjects(ctx context.Context, apiReader client.Reader, dkNamespace, podNamespace string) (*seedObjects, error) {
+	seed := &seedObjects{}
+
+	var dkList dynakube.DynaKubeList
+	if err := apiReader.List(ctx, &dkList, client.InNamespace(dkNamespace)); err != nil {
+		return nil, errors.WithMessagef(err, "failed to list the DynaKubes in %s", dkNamespace)
+	}
+
+	for i := range dkList.Items {
+		seed.objects = append(seed.objects, &dkList.Items[i])
+	}
+
+	var namespace corev1.Namespace
+
+	err := apiReader.Get(ctx, client.ObjectKey{Name: podNamespace}, &namespace)
+	if err == nil {
+		seed.objects = append(seed.objects, &namespace)
+	} else if !k8serrors.IsNotFound(err) {
+		return nil, errors.WithMessagef(err, "failed to get namespace %s", podNamespace)
+	}
+
+	for _, namespaceName := range []string{dkNamespace, podNamespace} {
+		var secrets corev1.SecretList
+		if err := apiReader.List(ctx, &secrets, client.InNamespace(namespaceName)); err != nil {
+			return nil, errors.WithMessagef(err, "failed to list the secrets in %s", namespaceName)
+		}
+
+		for i := range secrets.Items {
+			seed.objects = append(seed.objects, &secrets.Items[i])
+		}
+
+		var configMaps corev1.ConfigMapList
+		if err := apiReader.List(ctx, &configMaps, client.InNamespace(namespaceName)); err != nil {
+			return nil, errors.WithMessagef(err, "failed to list the configmaps in %s", namespaceName)
+		}
+
+		for i := range configMaps.Items {
+			seed.objects = append(seed.objects, &configMaps.Items[i])
+		}
+
+		if dkNamespace == podNamespace {
+			break
+		}
+	}
+
+	if clusterID, err := kubesystem.GetUID(ctx, apiReader); err == nil {
+		seed.clusterID = string(clusterID)
+	} else {
+		log.Info("failed to get the cluster UID, use --cluster-id to set it", "error", err.Error())
+	}
+
+	var webhookDeployment appsv1.Deployment
+
+	err = apiReader.Get(ctx, client.ObjectKey{Name: dtwebhook.DeploymentName, Namespace: dkNamespace}, &webhookDeployment)
+	if err == nil {
+		webhookContainer := container.FindContainerInPodSpec(&webhookDeployment.Spec.Template.Spec, dtwebhook.WebhookContainerName)
+		if webhookContainer != nil {
+			seed.webhookImage = webhookContainer.Image
+		}
+	} else {
+		log.Info("failed to get the webhook deployment, use --webhook-image to set the image", "error", err.Error())
+	}
+
+	return seed, nil
+}
@@ -22,7 +22,9 @@ import (
 	csiProvisioner "github.com/Dynatrace/dynatrace-operator/cmd/csi/provisioner"
 	"github.com/Dynatrace/dynatrace-operator/cmd/csi/registrar"
 	csiServer "github.com/Dynatrace/dynatrace-operator/cmd/csi/server"
+	injectPreview "github.com/Dynatrace/dynatrace-operator/cmd/inject_preview"
 	"github.com/Dynatrace/dynatrace-operator/cmd/operator"
+	releaseLock "github.com/Dynatrace/dynatrace-operator/cmd/release_lock"
 	"github.com/Dynatrace/dynatrace-operator/cmd/standalone"
 	startupProbe "github.com/Dynatrace/dynatrace-operator/cmd/startup_probe"
 	supportArchive "github.com/Dynatrace/dynatrace-operator/cmd/support_archive"
@@ -62,6 +64,8 @@ func main() {
 		standalone.NewStandaloneCommand(),
 		troubleshoot.New(),
 		supportArchive.New(),
+		injectPreview.New(),
+		releaseLock.New(),
 		startupProbe.New(),
 		csiInit.New(),
 		csiProvisioner.New(),
@@ -0,0 +1,158 @@
+package release_lock
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/releaselock"
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
+	"github.com/Dynatrace/dynatrace-operator/pkg/version"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	use = "release-lock"
+
+	dynakubeFlagName       = "dynakube"
+	dynakubeFlagShorthand  = "d"
+	namespaceFlagName      = "namespace"
+	namespaceFlagShorthand = "n"
+	nameFlagName           = "name"
+	applyFlagName          = "apply"
+)
+
+var (
+	dynakubeFlagValue  string
+	namespaceFlagValue string
+	nameFlagValue      string
+	applyFlagValue     bool
+
+	log = logd.Get().WithName("release-lock")
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: "Export the images of a DynaKube as release lock",
+		Long: "Collects the images the components of the DynaKube currently run, pinned by their digests, and prints them as release lock ConfigMap. " +
+			"Images are exported with their references before any registry mirror, code modules downloaded from the tenant are pinned by their version. " +
+			"Reference the ConfigMap in the releaseLock field of a DynaKube to pin all of its components to these images, e.g. on another cluster.",
+		RunE:         run(),
+		SilenceUsage: true,
+	}
+
+	addFlags(cmd)
+
+	return cmd
+}
+
+func addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&dynakubeFlagValue, dynakubeFlagName, dynakubeFlagShorthand, "", "DynaKube to export the release lock of.")
+	cmd.Flags().StringVarP(&namespaceFlagValue, namespaceFlagName, namespaceFlagShorthand, env.DefaultNamespace(), "Namespace of the DynaKube and the release lock.")
+	cmd.Flags().StringVar(&nameFlagValue, nameFlagName, "", "Name of the release lock ConfigMap, defaults to the name of the DynaKube with the suffix \""+releaselock.ConfigMapSuffix+"\".")
+	cmd.Flags().BoolVar(&applyFlagValue, applyFlagName, false, "Create or update the release lock ConfigMap in the cluster, besides printing it.")
+
+	_ = cmd.MarkFlagRequired(dynakubeFlagName)
+}
+
+func run() func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		// keep stdout free for the result
+		logd.SetOutput(os.Stderr)
+		version.LogVersion()
+
+		kubeConfig, err := config.GetConfig()
+		if err != nil {
+			return err
+		}
+
+		clt, err := client.New(kubeConfig, client.Options{Scheme: scheme.Scheme})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		configMap, err := exportReleaseLock(cmd.Context(), clt)
+		if err != nil {
+			return err
+		}
+
+		if applyFlagValue {
+			err = applyReleaseLock(cmd.Context(), clt, configMap)
+			if err != nil {
+				return err
+			}
+		}
+
+		return writeReleaseLock(os.Stdout, configMap)
+	}
+}
+
+func exportReleaseLock(ctx context.Context, apiReader client.Reader) (*corev1.ConfigMap, error) {
+	var dk dynakube.DynaKube
+
+	err := apiReader.Get(ctx, client.ObjectKey{Name: dynakubeFlagValue, Namespace: namespaceFlagValue}, &dk)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get DynaKube %s", dynakubeFlagValue)
+	}
+
+	resolve, err := releaselock.NewDigestResolverForDynaKube(ctx, apiReader, &dk)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := releaselock.Export(ctx, &dk, resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	return releaselock.NewConfigMap(getConfigMapName(), namespaceFlagValue, images)
+}
+
+func getConfigMapName() string {
+	if nameFlagValue != "" {
+		return nameFlagValue
+	}
+
+	return dynakubeFlagValue + releaselock.ConfigMapSuffix
+}
+
+func applyReleaseLock(ctx context.Context, clt client.Client, configMap *corev1.ConfigMap) error {
+	var existing corev1.ConfigMap
+
+	err := clt.Get(ctx, client.ObjectKeyFromObject(configMap), &existing)
+	if k8serrors.IsNotFound(err) {
+		log.Info("creating release lock", "name", configMap.Name, "namespace", configMap.Namespace)
+
+		return errors.WithStack(clt.Create(ctx, configMap.DeepCopy()))
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log.Info("updating release lock", "name", configMap.Name, "namespace", configMap.Namespace)
+
+	existing.Data = configMap.Data
+
+	return errors.WithStack(clt.Update(ctx, &existing))
+}
+
+func writeReleaseLock(out io.Writer, configMap *corev1.ConfigMap) error {
+	rawConfigMap, err := yaml.Marshal(configMap)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = fmt.Fprint(out, string(rawConfigMap))
+
+	return errors.WithStack(err)
+}
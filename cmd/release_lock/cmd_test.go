@@ -0,0 +1,57 @@
+package release_lock
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
+	releaselockcontroller "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/releaselock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const testImage = "public.ecr.aws/dynatrace/dynatrace-k8s-node-config-collector:latest@sha256:7ece13a07a20c77a31cc36906a10ebc90bd47970905ee61e8ed491b7f4c5d62f"
+
+func TestApplyReleaseLock(t *testing.T) {
+	configMap, err := releaselockcontroller.NewConfigMap("lock", "dynatrace", releaselock.Images{KSPM: testImage})
+	require.NoError(t, err)
+
+	clt := fake.NewClient()
+
+	t.Run("create", func(t *testing.T) {
+		require.NoError(t, applyReleaseLock(t.Context(), clt, configMap))
+
+		var created corev1.ConfigMap
+		require.NoError(t, clt.Get(t.Context(), client.ObjectKeyFromObject(configMap), &created))
+		assert.Equal(t, configMap.Data, created.Data)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		updatedConfigMap, err := releaselockcontroller.NewConfigMap("lock", "dynatrace", releaselock.Images{LogMonitoring: testImage})
+		require.NoError(t, err)
+
+		require.NoError(t, applyReleaseLock(t.Context(), clt, updatedConfigMap))
+
+		var updated corev1.ConfigMap
+		require.NoError(t, clt.Get(t.Context(), client.ObjectKeyFromObject(configMap), &updated))
+		assert.Equal(t, map[string]string{"logMonitoring": testImage}, updated.Data)
+	})
+}
+
+func TestWriteReleaseLock(t *testing.T) {
+	configMap, err := releaselockcontroller.NewConfigMap("lock", "dynatrace", releaselock.Images{KSPM: testImage})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, writeReleaseLock(&out, configMap))
+
+	var written corev1.ConfigMap
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &written))
+
+	assert.Equal(t, "ConfigMap", written.Kind)
+	assert.Equal(t, configMap.Data, written.Data)
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/cmd/support_archive/remote_command"
@@ -38,6 +40,11 @@ const (
 	loadsimFilesFlagName           = "loadsim-files"
 	collectManagedLogsFlagName     = "managed-logs"
 	numEventsFlagName              = "num-events"
+	includeCollectorsFlagName      = "include"
+	excludeCollectorsFlagName      = "exclude"
+	sinceFlagName                  = "since"
+	redactFlagName                 = "redact"
+	nodesFlagName                  = "nodes"
 	defaultSimFileSize             = 10
 	DefaultNumEvents               = 300
 )
@@ -56,8 +63,23 @@ var (
 	collectManagedLogsFlagValue bool
 	delayFlagValue              int
 	NumEventsFlagValue          int
+	includeCollectorsFlagValue  []string
+	excludeCollectorsFlagValue  []string
+	sinceFlagValue              time.Duration
+	redactFlagValue             bool
+	nodesFlagValue              []string
 )
 
+var collectorNames = []string{
+	operatorVersionCollectorName,
+	logCollectorName,
+	diagLogCollectorName,
+	nodeCollectorName,
+	k8sResourceCollectorName,
+	troubleshootCollectorName,
+	loadSimCollectorName,
+}
+
 func New() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:  use,
@@ -77,10 +99,20 @@ func addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVar(&collectManagedLogsFlagValue, collectManagedLogsFlagName, true, "Add logs from rolled out pods to the support archive.")
 	cmd.PersistentFlags().IntVar(&delayFlagValue, delayFlagName, 0, "Delay start of support-archive collection. Useful for standalone execution with 'kubectl run'")
 	cmd.PersistentFlags().IntVar(&NumEventsFlagValue, numEventsFlagName, DefaultNumEvents, fmt.Sprintf("Number of events to be fetched (default %d)", DefaultNumEvents))
+	cmd.PersistentFlags().StringSliceVar(&includeCollectorsFlagValue, includeCollectorsFlagName, nil, fmt.Sprintf("Only run the given collectors (one of %s).", strings.Join(collectorNames, ", ")))
+	cmd.PersistentFlags().StringSliceVar(&excludeCollectorsFlagValue, excludeCollectorsFlagName, nil, "Skip the given collectors.")
+	cmd.PersistentFlags().DurationVar(&sinceFlagValue, sinceFlagName, 0, "Only collect pod and OneAgent logs newer than a relative duration like 5s, 2m, or 3h. Defaults to all logs.")
+	cmd.PersistentFlags().StringSliceVar(&nodesFlagValue, nodesFlagName, nil, "Only collect the CSI driver state and OneAgent logs of the given nodes. Defaults to all nodes.")
+	cmd.PersistentFlags().BoolVar(&redactFlagValue, redactFlagName, true, "Scrub tokens, proxy credentials, tenant UUIDs and IP addresses from the collected files.")
 }
 
 func run() func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
+		enabledCollectors, err := getEnabledCollectors(includeCollectorsFlagValue, excludeCollectorsFlagValue)
+		if err != nil {
+			return err
+		}
+
 		time.Sleep(time.Duration(delayFlagValue) * time.Second)
 
 		logBuffer := bytes.Buffer{}
@@ -93,12 +125,20 @@ func run() func(*cobra.Command, []string) error {
 			return err
 		}
 
-		supportArchive := newZipArchive(archiveTargetFile)
+		zipArchive := newZipArchive(archiveTargetFile)
 
 		defer archiveTargetFile.Close()
-		defer supportArchive.Close()
+		defer zipArchive.Close()
+
+		var supportArchive archiver = zipArchive
 
-		err = runCollectors(log, supportArchive)
+		var redactingArchive *redactingArchive
+		if redactFlagValue {
+			redactingArchive = newRedactingArchive(zipArchive)
+			supportArchive = redactingArchive
+		}
+
+		err = runCollectors(log, supportArchive, enabledCollectors)
 		if err != nil {
 			return err
 		}
@@ -108,6 +148,10 @@ func run() func(*cobra.Command, []string) error {
 		// make sure to run this collector at the very end
 		newSupportArchiveOutputCollector(log, supportArchive, &logBuffer).Do()
 
+		if redactingArchive != nil {
+			return redactingArchive.writeManifest()
+		}
+
 		return nil
 	}
 }
@@ -137,7 +181,30 @@ func getAppNameLabel(ctx context.Context, pods clientgocorev1.PodInterface) stri
 	return defaultOperatorAppName
 }
 
-func runCollectors(log logd.Logger, supportArchive archiver) error {
+// getEnabledCollectors returns the names of the collectors to run, an empty include list enables all collectors.
+func getEnabledCollectors(include []string, exclude []string) (map[string]bool, error) {
+	for _, name := range append(append([]string{}, include...), exclude...) {
+		if !slices.Contains(collectorNames, name) {
+			return nil, errors.Errorf("unknown collector '%s', must be one of %s", name, strings.Join(collectorNames, ", "))
+		}
+	}
+
+	enabled := map[string]bool{}
+
+	for _, name := range collectorNames {
+		if len(include) == 0 || slices.Contains(include, name) {
+			enabled[name] = true
+		}
+	}
+
+	for _, name := range exclude {
+		delete(enabled, name)
+	}
+
+	return enabled, nil
+}
+
+func runCollectors(log logd.Logger, supportArchive archiver, enabledCollectors map[string]bool) error {
 	ctx := context.Background()
 
 	kubeConfig, err := config.GetConfig()
@@ -163,14 +230,21 @@ func runCollectors(log logd.Logger, supportArchive archiver) error {
 	fileSize := loadsimFileSizeFlagValue * Mebi
 	collectors := []collector{
 		newOperatorVersionCollector(log, supportArchive),
-		newLogCollector(ctx, log, supportArchive, pods, appName, collectManagedLogsFlagValue),
+		newLogCollector(ctx, log, supportArchive, pods, appName, collectManagedLogsFlagValue, sinceFlagValue),
 		newFsLogCollector(ctx, kubeConfig, &remote_command.DefaultExecutor{}, log, supportArchive, pods, appName, collectManagedLogsFlagValue),
+		newNodeCollector(ctx, kubeConfig, &remote_command.DefaultExecutor{}, log, supportArchive, pods, appName, nodesFlagValue, sinceFlagValue),
 		newK8sObjectCollector(ctx, log, supportArchive, namespaceFlagValue, appName, apiReader, discoveryClient),
 		newTroubleshootCollector(ctx, log, supportArchive, namespaceFlagValue, apiReader, *kubeConfig),
 		newLoadSimCollector(ctx, log, supportArchive, fileSize, loadsimFilesFlagValue, clientSet.CoreV1().Pods(namespaceFlagValue)),
 	}
 
 	for _, c := range collectors {
+		if !enabledCollectors[c.Name()] {
+			logInfof(log, "Skipping %s", c.Name())
+
+			continue
+		}
+
 		if err := c.Do(); err != nil {
 			logErrorf(log, err, "%s failed", c.Name())
 		}
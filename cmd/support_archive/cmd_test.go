@@ -7,6 +7,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -41,3 +42,35 @@ func TestGetAppName(t *testing.T) {
 	t.Setenv(env.PodName, alternativeOperatorName)
 	assert.Equal(t, alternativeOperatorName, getAppNameLabel(context.TODO(), fakeClientSet.CoreV1().Pods(alternativeNamespace)))
 }
+
+func TestGetEnabledCollectors(t *testing.T) {
+	t.Run("all collectors by default", func(t *testing.T) {
+		enabled, err := getEnabledCollectors(nil, nil)
+		require.NoError(t, err)
+		assert.Len(t, enabled, len(collectorNames))
+	})
+
+	t.Run("include", func(t *testing.T) {
+		enabled, err := getEnabledCollectors([]string{logCollectorName, k8sResourceCollectorName}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{logCollectorName: true, k8sResourceCollectorName: true}, enabled)
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		enabled, err := getEnabledCollectors(nil, []string{loadSimCollectorName})
+		require.NoError(t, err)
+		assert.Len(t, enabled, len(collectorNames)-1)
+		assert.False(t, enabled[loadSimCollectorName])
+	})
+
+	t.Run("exclude wins over include", func(t *testing.T) {
+		enabled, err := getEnabledCollectors([]string{logCollectorName, troubleshootCollectorName}, []string{troubleshootCollectorName})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{logCollectorName: true}, enabled)
+	})
+
+	t.Run("unknown collector", func(t *testing.T) {
+		_, err := getEnabledCollectors([]string{"unknown"}, nil)
+		require.Error(t, err)
+	})
+}
@@ -2,8 +2,10 @@ package support_archive
 
 const OperatorVersionFileName = "operator-version.txt"
 const SupportArchiveOutputFileName = "supportarchive_console.log"
+const RedactionManifestFileName = "redaction-manifest.json"
 
 const LogsDirectoryName = "logs"
+const NodesDirectoryName = "nodes"
 const ManifestsDirectoryName = "manifests"
 const InjectedNamespacesManifestsDirectoryName = "injected_namespaces"
 const CRDDirectoryName = "crds"
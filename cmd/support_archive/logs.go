@@ -3,6 +3,7 @@ package support_archive
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
@@ -23,9 +24,10 @@ type logCollector struct {
 	pods               clientgocorev1.PodInterface
 	appName            string
 	collectManagedLogs bool
+	since              time.Duration
 }
 
-func newLogCollector(context context.Context, log logd.Logger, supportArchive archiver, pods clientgocorev1.PodInterface, appName string, collectManagedLogs bool) collector { //nolint:revive // argument-limit doesn't apply to constructors
+func newLogCollector(context context.Context, log logd.Logger, supportArchive archiver, pods clientgocorev1.PodInterface, appName string, collectManagedLogs bool, since time.Duration) collector { //nolint:revive // argument-limit doesn't apply to constructors
 	return logCollector{
 		collectorCommon: collectorCommon{
 			log:            log,
@@ -35,6 +37,7 @@ func newLogCollector(context context.Context, log logd.Logger, supportArchive ar
 		pods:               pods,
 		appName:            appName,
 		collectManagedLogs: collectManagedLogs,
+		since:              since,
 	}
 }
 
@@ -101,6 +104,12 @@ func (lc logCollector) collectPodLogs(pod *corev1.Pod) {
 			Container: container.Name,
 			Follow:    false,
 		}
+
+		if lc.since > 0 {
+			sinceSeconds := int64(lc.since.Seconds())
+			podLogOpts.SinceSeconds = &sinceSeconds
+		}
+
 		lc.collectContainerLogs(pod, container, podLogOpts)
 
 		podLogOpts.Previous = true
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
 	corev1mock "github.com/Dynatrace/dynatrace-operator/test/mocks/k8s.io/client-go/kubernetes/typed/core/v1"
@@ -60,7 +61,8 @@ func testLogCollection(t *testing.T, collectManagedLogs bool) {
 		supportArchive,
 		fakeClientSet.CoreV1().Pods("dynatrace"),
 		defaultOperatorAppName,
-		collectManagedLogs)
+		collectManagedLogs,
+		0)
 
 	require.NoError(t, logCollector.Do())
 
@@ -115,7 +117,8 @@ func TestLogCollectorPodListError(t *testing.T) {
 		supportArchive,
 		mockedPods,
 		defaultOperatorAppName,
-		true)
+		true,
+		0)
 	require.Error(t, logCollector.Do())
 }
 
@@ -158,7 +161,7 @@ func TestLogCollectorGetPodFail(t *testing.T) {
 		Get(ctx, "oneagent", metav1.GetOptions{}).
 		Return(nil, assert.AnError)
 
-	logCollector := newLogCollector(ctx, newSupportArchiveLogger(&logBuffer), supportArchive, mockedPods, defaultOperatorAppName, true)
+	logCollector := newLogCollector(ctx, newSupportArchiveLogger(&logBuffer), supportArchive, mockedPods, defaultOperatorAppName, true, 0)
 	require.NoError(t, logCollector.Do())
 }
 
@@ -231,7 +234,7 @@ func TestLogCollectorGetLogsFail(t *testing.T) {
 		NotBefore(getLogsPod2Container2Call).
 		Return(nil, assert.AnError)
 
-	logCollector := newLogCollector(ctx, newSupportArchiveLogger(&logBuffer), supportArchive, mockedPods, defaultOperatorAppName, true)
+	logCollector := newLogCollector(ctx, newSupportArchiveLogger(&logBuffer), supportArchive, mockedPods, defaultOperatorAppName, true, 0)
 	require.NoError(t, logCollector.Do())
 
 	assert.Contains(t, logBuffer.String(), "Unable to retrieve log stream for pod pod1, container container1")
@@ -292,7 +295,7 @@ func TestLogCollectorNoAbortOnError(t *testing.T) {
 		NotBefore(getLogsPod2Container2Call).
 		Return(nil, assert.AnError)
 
-	logCollector := newLogCollector(ctx, newSupportArchiveLogger(&logBuffer), supportArchive, mockedPods, defaultOperatorAppName, true)
+	logCollector := newLogCollector(ctx, newSupportArchiveLogger(&logBuffer), supportArchive, mockedPods, defaultOperatorAppName, true, 0)
 	require.NoError(t, logCollector.Do())
 
 	assertNoErrorOnClose(t, supportArchive)
@@ -303,6 +306,44 @@ func TestLogCollectorNoAbortOnError(t *testing.T) {
 	assert.Equal(t, "logs/pod2/container2.log", zipReader.File[1].Name)
 }
 
+func TestLogCollectorSince(t *testing.T) {
+	ctx := context.Background()
+
+	fakeClientSet := fake.NewSimpleClientset(createPod("pod1", labels.AppNameLabel))
+
+	logBuffer := bytes.Buffer{}
+
+	buffer := bytes.Buffer{}
+	supportArchive := newZipArchive(bufio.NewWriter(&buffer))
+	defer assertNoErrorOnClose(t, supportArchive)
+
+	mockedPods := corev1mock.NewPodInterface(t)
+	listOptionsAppName := createPodListOptions(labels.AppNameLabel)
+
+	mockedPods.EXPECT().
+		List(ctx, listOptionsAppName).
+		Return(fakeClientSet.CoreV1().Pods("dynatrace").List(ctx, listOptionsAppName))
+	mockedPods.EXPECT().
+		Get(ctx, "pod1", metav1.GetOptions{}).
+		Return(fakeClientSet.CoreV1().Pods("dynatrace").Get(ctx, "pod1", metav1.GetOptions{}))
+
+	sinceSeconds := int64(7200)
+
+	for _, container := range []string{"container1", "container2"} {
+		for _, previous := range []bool{false, true} {
+			logOptions := createGetPodLogOptions(container, previous)
+			logOptions.SinceSeconds = &sinceSeconds
+
+			mockedPods.EXPECT().
+				GetLogs("pod1", logOptions).
+				Return(nil)
+		}
+	}
+
+	logCollector := newLogCollector(ctx, newSupportArchiveLogger(&logBuffer), supportArchive, mockedPods, defaultOperatorAppName, false, 2*time.Hour)
+	require.NoError(t, logCollector.Do())
+}
+
 func createPod(name string, labelKey string) *corev1.Pod {
 	const namespace = "dynatrace"
 
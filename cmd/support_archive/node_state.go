@@ -0,0 +1,457 @@
+package support_archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/cmd/support_archive/remote_command"
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	clientgocorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	nodeCollectorName = "nodeCollector"
+
+	csiDriverComponentLabel = "csi-driver"
+	csiServerContainerName  = "server"
+
+	oneAgentContainerName = "dynatrace-oneagent"
+	oneAgentLogDir        = "/mnt/root/var/log/dynatrace/oneagent"
+
+	csiTreeFileName        = "tree.txt"
+	csiVersionsFileName    = "versions.txt"
+	csiMountsFileName      = "mounts.txt"
+	csiStaleMountsFileName = "stale-mounts.txt"
+)
+
+// nodeCollector gathers node-local state, the CSI driver's view of the node and the OneAgent host logs.
+type nodeCollector struct {
+	ctx                   context.Context
+	pods                  clientgocorev1.PodInterface
+	remoteCommandExecutor remote_command.Executor
+	config                *rest.Config
+	collectorCommon
+	appName string
+	nodes   []string
+	since   time.Duration
+	path    metadata.PathResolver
+}
+
+func newNodeCollector(context context.Context, config *rest.Config, command remote_command.Executor, log logd.Logger, supportArchive archiver, pods clientgocorev1.PodInterface, appName string, nodes []string, since time.Duration) collector { //nolint:revive
+	return nodeCollector{
+		collectorCommon: collectorCommon{
+			log:            log,
+			supportArchive: supportArchive,
+		},
+		ctx:                   context,
+		config:                config,
+		pods:                  pods,
+		appName:               appName,
+		nodes:                 nodes,
+		since:                 since,
+		remoteCommandExecutor: command,
+		path:                  metadata.PathResolver{RootDir: dtcsi.DataPath},
+	}
+}
+
+func (nc nodeCollector) Name() string {
+	return nodeCollectorName
+}
+
+func (nc nodeCollector) Do() error {
+	if !installconfig.GetModules().Supportability {
+		logInfof(nc.log, "%s", installconfig.GetModuleValidationErrorMessage("Node State Collection"))
+
+		return nil
+	}
+
+	logInfof(nc.log, "Starting node state collection")
+
+	csiPods, err := nc.getPodList(map[string]string{
+		labels.AppNameLabel:      nc.appName,
+		labels.AppComponentLabel: csiDriverComponentLabel,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range csiPods {
+		nc.collectCSIState(pod)
+	}
+
+	oneAgentPods, err := nc.getPodList(map[string]string{
+		labels.AppNameLabel:      labels.OneAgentComponentLabel,
+		labels.AppManagedByLabel: nc.appName,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range oneAgentPods {
+		nc.collectOneAgentLogs(pod)
+	}
+
+	return nil
+}
+
+// getPodList returns the pods matching the given labels running on the selected nodes, all nodes are selected if none are given.
+func (nc nodeCollector) getPodList(matchLabels map[string]string) ([]corev1.Pod, error) {
+	listOptions := metav1.ListOptions{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "pod",
+		},
+		LabelSelector: apilabels.Set(matchLabels).String(),
+	}
+
+	podList, err := nc.pods.List(nc.ctx, listOptions)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	pods := []corev1.Pod{}
+
+	for _, pod := range podList.Items {
+		if len(nc.nodes) == 0 || slices.Contains(nc.nodes, pod.Spec.NodeName) {
+			pods = append(pods, pod)
+		}
+	}
+
+	return pods, nil
+}
+
+// collectCSIState gathers the state of the CSI driver of a node.
+// The server container is based on ubi-micro, so only the coreutils are available, everything else is done on this side.
+func (nc nodeCollector) collectCSIState(pod corev1.Pod) {
+	tree, err := nc.listTree(pod)
+	if err != nil {
+		logErrorf(nc.log, err, "failed to list the CSI driver directory tree, podName: %s", pod.Name)
+
+		return
+	}
+
+	nc.addFile(BuildNodeZipFilePath(pod.Spec.NodeName, "csi", csiTreeFileName), strings.Join(tree, "\n"))
+
+	versions, versionsErr := nc.listVersions(pod, tree)
+	if versionsErr != nil {
+		logErrorf(nc.log, versionsErr, "failed to list the installed CodeModules, podName: %s", pod.Name)
+	} else {
+		nc.addFile(BuildNodeZipFilePath(pod.Spec.NodeName, "csi", csiVersionsFileName), versions)
+	}
+
+	if slices.Contains(tree, nc.path.InventoryFile()) {
+		inventory, err := nc.exec(pod, csiServerContainerName, "cat '"+nc.path.InventoryFile()+"'")
+		if err != nil {
+			logErrorf(nc.log, err, "failed to read the CodeModule inventory, podName: %s", pod.Name)
+		} else {
+			nc.addFile(BuildNodeZipFilePath(pod.Spec.NodeName, "csi", dtcsi.InventoryFileName), inventory)
+		}
+	}
+
+	allMounts, err := nc.exec(pod, csiServerContainerName, "cat /proc/mounts")
+	if err != nil {
+		logErrorf(nc.log, err, "failed to list the app-mount overlays, podName: %s", pod.Name)
+
+		return
+	}
+
+	mounts := filterMounts(allMounts, nc.path.RootDir)
+	nc.addFile(BuildNodeZipFilePath(pod.Spec.NodeName, "csi", csiMountsFileName), mounts)
+
+	staleMounts, err := findStaleMounts(nc.path, tree, versions, versionsErr, mounts)
+	if err != nil {
+		logErrorf(nc.log, err, "failed to look for stale app-mounts, podName: %s", pod.Name)
+
+		return
+	}
+
+	nc.addFile(BuildNodeZipFilePath(pod.Spec.NodeName, "csi", csiStaleMountsFileName), strings.Join(staleMounts, "\n"))
+
+	logInfof(nc.log, "Successfully collected CSI driver state of node %s", pod.Spec.NodeName)
+}
+
+// listTree lists the paths below the root directory of the CSI driver, without descending into the CodeModules and the overlay layers.
+// The directories that can be large are only listed one level deep, everything else recursively.
+func (nc nodeCollector) listTree(pod corev1.Pod) ([]string, error) {
+	tree := []string{nc.path.RootDir}
+
+	rootEntries, err := nc.list(pod, "ls -1Ap", nc.path.RootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	tree = append(tree, rootEntries...)
+
+	for _, entry := range rootEntries {
+		if !strings.HasSuffix(entry, "/") {
+			continue
+		}
+
+		dir := strings.TrimSuffix(entry, "/")
+
+		var entries []string
+
+		switch dir {
+		case nc.path.AgentSharedBinaryDirBase(), nc.path.AgentQuarantineDir(), nc.path.AgentJobWorkDirBase():
+			entries, err = nc.list(pod, "ls -1Ap", dir)
+		case nc.path.AppMountsBaseDir():
+			entries, err = nc.listAppMounts(pod)
+		default:
+			entries, err = nc.list(pod, "ls -RAp", dir)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tree = append(tree, entries...)
+	}
+
+	for i := range tree {
+		tree[i] = strings.TrimSuffix(tree[i], "/")
+	}
+
+	return tree, nil
+}
+
+// listAppMounts lists the app-mount volumes and their overlay layers, but not the content of the layers.
+func (nc nodeCollector) listAppMounts(pod corev1.Pod) ([]string, error) {
+	volumes, err := nc.list(pod, "ls -1Ap", nc.path.AppMountsBaseDir())
+	if err != nil {
+		return nil, err
+	}
+
+	volumeDirs := []string{}
+
+	for _, volume := range volumes {
+		if strings.HasSuffix(volume, "/") {
+			volumeDirs = append(volumeDirs, strings.TrimSuffix(volume, "/"))
+		}
+	}
+
+	if len(volumeDirs) == 0 {
+		return volumes, nil
+	}
+
+	layers, err := nc.list(pod, "ls -1Ap", volumeDirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(volumes, layers...), nil
+}
+
+// list runs the given ls command for the directories and returns the listed entries as full paths, directories end with a "/".
+func (nc nodeCollector) list(pod corev1.Pod, lsCommand string, dirs ...string) ([]string, error) {
+	output, err := nc.exec(pod, csiServerContainerName, lsCommand+" "+quoteAll(dirs))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLsOutput(output, dirs[0]), nil
+}
+
+// listVersions returns the disk usage of each of the installed CodeModules.
+func (nc nodeCollector) listVersions(pod corev1.Pod, tree []string) (string, error) {
+	agentDirs := []string{}
+
+	for _, path := range tree {
+		if filepath.Dir(path) == nc.path.AgentSharedBinaryDirBase() {
+			agentDirs = append(agentDirs, path)
+		}
+	}
+
+	if len(agentDirs) == 0 {
+		return "", nil
+	}
+
+	return nc.exec(pod, csiServerContainerName, "du -sk "+quoteAll(agentDirs))
+}
+
+// parseLsOutput turns the output of ls into full paths.
+// With several directories or -R, the entries of each directory follow a "<dir>:" header, otherwise they belong to the given directory.
+func parseLsOutput(output string, dir string) []string {
+	paths := []string{}
+
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case line == "":
+			continue
+		case strings.HasSuffix(line, ":") && strings.HasPrefix(line, "/"):
+			dir = strings.TrimSuffix(line, ":")
+		default:
+			paths = append(paths, filepath.Join(dir, line)+trailingSlash(line))
+		}
+	}
+
+	return paths
+}
+
+func trailingSlash(entry string) string {
+	if strings.HasSuffix(entry, "/") {
+		return "/"
+	}
+
+	return ""
+}
+
+func quoteAll(paths []string) string {
+	quoted := make([]string, 0, len(paths))
+	for _, path := range paths {
+		quoted = append(quoted, "'"+path+"'")
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// filterMounts returns the lines of /proc/mounts, that are mounted below the root directory of the CSI driver.
+func filterMounts(mounts string, rootDir string) string {
+	filtered := []string{}
+
+	for _, line := range strings.Split(mounts, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && strings.HasPrefix(fields[1], rootDir+"/") {
+			filtered = append(filtered, line)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return ""
+	}
+
+	return strings.Join(filtered, "\n") + "\n"
+}
+
+func (nc nodeCollector) collectOneAgentLogs(pod corev1.Pod) {
+	findCommand := "find " + oneAgentLogDir + " -type f -name '*.log'"
+	if nc.since > 0 {
+		findCommand += fmt.Sprintf(" -mmin -%d", int(nc.since.Minutes())+1)
+	}
+
+	logFiles, err := nc.exec(pod, oneAgentContainerName, "if [ -d '"+oneAgentLogDir+"' ]; then "+findCommand+" ; fi")
+	if err != nil {
+		logErrorf(nc.log, err, "OneAgent log files lookup failed, podName: %s", pod.Name)
+
+		return
+	}
+
+	for _, logFile := range strings.Split(logFiles, "\n") {
+		logFile = strings.TrimSpace(logFile)
+		if logFile == "" {
+			continue
+		}
+
+		content, err := nc.exec(pod, oneAgentContainerName, "cat '"+logFile+"'")
+		if err != nil {
+			logErrorf(nc.log, err, "failed to copy %s from pod: %s", logFile, pod.Name)
+
+			continue
+		}
+
+		relativePath := strings.TrimPrefix(logFile, oneAgentLogDir+"/")
+		nc.addFile(BuildNodeZipFilePath(pod.Spec.NodeName, "oneagent", relativePath), content)
+	}
+
+	logInfof(nc.log, "Successfully collected OneAgent logs of node %s", pod.Spec.NodeName)
+}
+
+func (nc nodeCollector) exec(pod corev1.Pod, containerName string, command string) (string, error) {
+	stdOut, stdErr, err := nc.remoteCommandExecutor.Exec(nc.ctx, nc.config, pod.Name, pod.Namespace, containerName, []string{"/usr/bin/sh", "-c", command})
+	if err != nil {
+		if stdErr != nil && stdErr.Len() > 0 {
+			return "", errors.WithMessage(err, strings.TrimSpace(stdErr.String()))
+		}
+
+		return "", err
+	}
+
+	return stdOut.String(), nil
+}
+
+func (nc nodeCollector) addFile(zipFilePath string, content string) {
+	err := nc.supportArchive.addFile(zipFilePath, bytes.NewBufferString(content))
+	if err != nil {
+		logErrorf(nc.log, err, "error writing to tarball")
+	}
+}
+
+// findStaleMounts compares the app-mount directories, the installed CodeModules and the active overlay mounts of a node.
+// Without the installed CodeModules, every mount would be reported as stale, so the error of listing them is returned instead.
+func findStaleMounts(path metadata.PathResolver, tree []string, versions string, versionsErr error, mounts string) ([]string, error) {
+	if versionsErr != nil {
+		return nil, errors.WithMessage(versionsErr, "installed CodeModules are unknown")
+	}
+
+	appMounts := map[string]bool{}
+
+	for _, treePath := range tree {
+		if filepath.Dir(treePath) == path.AppMountsBaseDir() {
+			appMounts[filepath.Base(treePath)] = false
+		}
+	}
+
+	installedAgents := map[string]bool{}
+
+	for _, line := range strings.Split(versions, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 { //nolint:mnd
+			installedAgents[fields[1]] = true
+		}
+	}
+
+	staleMounts := []string{}
+
+	for _, line := range strings.Split(mounts, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != "overlay" { //nolint:mnd
+			continue
+		}
+
+		var lowerDir, upperDir string
+
+		for _, opt := range strings.Split(fields[3], ",") {
+			switch {
+			case strings.HasPrefix(opt, "lowerdir="):
+				lowerDir = strings.TrimPrefix(opt, "lowerdir=")
+			case strings.HasPrefix(opt, "upperdir="):
+				upperDir = strings.TrimPrefix(opt, "upperdir=")
+			}
+		}
+
+		volumeID := filepath.Base(filepath.Dir(upperDir))
+		if _, ok := appMounts[volumeID]; ok {
+			appMounts[volumeID] = true
+		}
+
+		if lowerDir != "" && !installedAgents[lowerDir] {
+			staleMounts = append(staleMounts, fmt.Sprintf("%s: mounted CodeModule %s is no longer installed", fields[1], lowerDir))
+		}
+	}
+
+	for volumeID, mounted := range appMounts {
+		if !mounted {
+			staleMounts = append(staleMounts, fmt.Sprintf("%s: app-mount has no active overlay mount", path.AppMountForID(volumeID)))
+		}
+	}
+
+	slices.Sort(staleMounts)
+
+	return staleMounts, nil
+}
+
+func BuildNodeZipFilePath(nodeName string, fileNames ...string) string {
+	return filepath.Join(append([]string{NodesDirectoryName, nodeName}, fileNames...)...)
+}
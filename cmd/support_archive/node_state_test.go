@@ -0,0 +1,242 @@
+package support_archive
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	mocks "github.com/Dynatrace/dynatrace-operator/test/mocks/cmd/remote_command"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	testNodeName      = "node-1"
+	testOtherNodeName = "node-2"
+
+	csiTreeOutput = `/data
+/data/codemodules
+/data/appmounts
+/data/_dynakubes
+/data/inventory.json
+/data/codemodules/1.2.3
+/data/appmounts/csi-mounted
+/data/appmounts/csi-leftover
+/data/appmounts/csi-mounted/mapped
+/data/appmounts/csi-mounted/var
+/data/appmounts/csi-mounted/work
+/data/_dynakubes/dk
+/data/_dynakubes/dk/latest`
+	csiVersionsOutput = "1024\t/data/codemodules/1.2.3\n"
+	csiMountsOutput   = "overlay /data/appmounts/csi-mounted/mapped overlay rw,relatime,lowerdir=/data/codemodules/1.2.3,upperdir=/data/appmounts/csi-mounted/var,workdir=/data/appmounts/csi-mounted/work 0 0\n" +
+		"overlay /data/appmounts/csi-old/mapped overlay rw,relatime,lowerdir=/data/codemodules/1.0.0,upperdir=/data/appmounts/csi-old/var,workdir=/data/appmounts/csi-old/work 0 0\n"
+	inventoryOutput = `{"agents":{}}`
+)
+
+func TestNodeCollector(t *testing.T) {
+	fakeClientSet := fake.NewSimpleClientset(
+		createNodePod("csi-driver-1", testNodeName, map[string]string{
+			labels.AppNameLabel:      defaultOperatorAppName,
+			labels.AppComponentLabel: csiDriverComponentLabel,
+		}),
+		createNodePod("csi-driver-2", testOtherNodeName, map[string]string{
+			labels.AppNameLabel:      defaultOperatorAppName,
+			labels.AppComponentLabel: csiDriverComponentLabel,
+		}),
+		createNodePod("oneagent-1", testNodeName, map[string]string{
+			labels.AppNameLabel:      labels.OneAgentComponentLabel,
+			labels.AppManagedByLabel: defaultOperatorAppName,
+		}),
+	)
+
+	logBuffer := bytes.Buffer{}
+
+	buffer := bytes.Buffer{}
+	supportArchive := newZipArchive(bufio.NewWriter(&buffer))
+
+	rce := mocks.NewExecutor(t)
+	path := metadata.PathResolver{RootDir: dtcsi.DataPath}
+
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "ls -1Ap '"+path.RootDir+"'", "codemodules/\nappmounts/\n_dynakubes/\ninventory.json\n")
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "ls -1Ap '"+path.AgentSharedBinaryDirBase()+"'", "1.2.3/\n")
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "ls -1Ap '"+path.AppMountsBaseDir()+"'", "csi-mounted/\ncsi-leftover/\n")
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "ls -1Ap '"+path.AppMountForID("csi-mounted")+"' '"+path.AppMountForID("csi-leftover")+"'",
+		"/data/appmounts/csi-mounted:\nmapped/\nvar/\nwork/\n\n/data/appmounts/csi-leftover:\n")
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "ls -RAp '"+path.DynaKubesBaseDir()+"'", "/data/_dynakubes:\ndk/\n\n/data/_dynakubes/dk:\nlatest\n")
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "du -sk '"+path.AgentSharedBinaryDirForAgent("1.2.3")+"'", csiVersionsOutput)
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "cat '"+path.InventoryFile()+"'", inventoryOutput)
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "cat /proc/mounts", "proc /proc proc rw,nosuid 0 0\n"+csiMountsOutput)
+	expectExec(rce, "oneagent-1", oneAgentContainerName, "if [ -d '"+oneAgentLogDir+"' ]; then find "+oneAgentLogDir+" -type f -name '*.log' ; fi", oneAgentLogDir+"/oneagentos.log\n"+oneAgentLogDir+"/installer/installation.log\n")
+	expectExec(rce, "oneagent-1", oneAgentContainerName, "cat '"+oneAgentLogDir+"/oneagentos.log'", "os log")
+	expectExec(rce, "oneagent-1", oneAgentContainerName, "cat '"+oneAgentLogDir+"/installer/installation.log'", "installer log")
+
+	nodeCollector := newNodeCollector(context.Background(),
+		nil,
+		rce,
+		newSupportArchiveLogger(&logBuffer),
+		supportArchive,
+		fakeClientSet.CoreV1().Pods("dynatrace"),
+		defaultOperatorAppName,
+		[]string{testNodeName},
+		0)
+
+	require.NoError(t, nodeCollector.Do())
+	require.NoError(t, supportArchive.Close())
+
+	zipReader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range zipReader.File {
+		files[file.Name] = readZipFile(t, file)
+	}
+
+	assert.Equal(t, map[string]string{
+		"nodes/node-1/csi/tree.txt":                        csiTreeOutput,
+		"nodes/node-1/csi/versions.txt":                    csiVersionsOutput,
+		"nodes/node-1/csi/inventory.json":                  inventoryOutput,
+		"nodes/node-1/csi/mounts.txt":                      csiMountsOutput,
+		"nodes/node-1/csi/stale-mounts.txt":                "/data/appmounts/csi-leftover: app-mount has no active overlay mount\n/data/appmounts/csi-old/mapped: mounted CodeModule /data/codemodules/1.0.0 is no longer installed",
+		"nodes/node-1/oneagent/oneagentos.log":             "os log",
+		"nodes/node-1/oneagent/installer/installation.log": "installer log",
+	}, files)
+}
+
+func TestNodeCollectorExecError(t *testing.T) {
+	fakeClientSet := fake.NewSimpleClientset(
+		createNodePod("csi-driver-1", testNodeName, map[string]string{
+			labels.AppNameLabel:      defaultOperatorAppName,
+			labels.AppComponentLabel: csiDriverComponentLabel,
+		}),
+	)
+
+	logBuffer := bytes.Buffer{}
+
+	buffer := bytes.Buffer{}
+	supportArchive := newZipArchive(bufio.NewWriter(&buffer))
+	defer assertNoErrorOnClose(t, supportArchive)
+
+	rce := mocks.NewExecutor(t)
+	rce.On("Exec", mock.Anything, mock.Anything, "csi-driver-1", "dynatrace", csiServerContainerName, []string{"/usr/bin/sh", "-c", "ls -1Ap '/data'"}).
+		Return(&bytes.Buffer{}, bytes.NewBufferString("ls: cannot access '/data': No such file or directory"), errors.New("command terminated with exit code 2"))
+
+	nodeCollector := newNodeCollector(context.Background(),
+		nil,
+		rce,
+		newSupportArchiveLogger(&logBuffer),
+		supportArchive,
+		fakeClientSet.CoreV1().Pods("dynatrace"),
+		defaultOperatorAppName,
+		nil,
+		0)
+
+	require.NoError(t, nodeCollector.Do())
+	assert.Contains(t, logBuffer.String(), "failed to list the CSI driver directory tree")
+	assert.Contains(t, logBuffer.String(), "No such file or directory")
+}
+
+func TestNodeCollectorVersionsError(t *testing.T) {
+	fakeClientSet := fake.NewSimpleClientset(
+		createNodePod("csi-driver-1", testNodeName, map[string]string{
+			labels.AppNameLabel:      defaultOperatorAppName,
+			labels.AppComponentLabel: csiDriverComponentLabel,
+		}),
+	)
+
+	logBuffer := bytes.Buffer{}
+
+	buffer := bytes.Buffer{}
+	supportArchive := newZipArchive(bufio.NewWriter(&buffer))
+
+	rce := mocks.NewExecutor(t)
+	path := metadata.PathResolver{RootDir: dtcsi.DataPath}
+
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "ls -1Ap '"+path.RootDir+"'", "codemodules/\n")
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "ls -1Ap '"+path.AgentSharedBinaryDirBase()+"'", "1.2.3/\n")
+	rce.On("Exec", mock.Anything, mock.Anything, "csi-driver-1", "dynatrace", csiServerContainerName, []string{"/usr/bin/sh", "-c", "du -sk '" + path.AgentSharedBinaryDirForAgent("1.2.3") + "'"}).
+		Return(&bytes.Buffer{}, bytes.NewBufferString("du: cannot read directory"), errors.New("command terminated with exit code 1"))
+	expectExec(rce, "csi-driver-1", csiServerContainerName, "cat /proc/mounts", csiMountsOutput)
+
+	nodeCollector := newNodeCollector(context.Background(),
+		nil,
+		rce,
+		newSupportArchiveLogger(&logBuffer),
+		supportArchive,
+		fakeClientSet.CoreV1().Pods("dynatrace"),
+		defaultOperatorAppName,
+		nil,
+		0)
+
+	require.NoError(t, nodeCollector.Do())
+	require.NoError(t, supportArchive.Close())
+
+	zipReader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.NoError(t, err)
+
+	fileNames := []string{}
+	for _, file := range zipReader.File {
+		fileNames = append(fileNames, file.Name)
+	}
+
+	assert.ElementsMatch(t, []string{"nodes/node-1/csi/tree.txt", "nodes/node-1/csi/mounts.txt"}, fileNames)
+	assert.Contains(t, logBuffer.String(), "failed to look for stale app-mounts")
+}
+
+func TestNodeCollectorSince(t *testing.T) {
+	fakeClientSet := fake.NewSimpleClientset(
+		createNodePod("oneagent-1", testNodeName, map[string]string{
+			labels.AppNameLabel:      labels.OneAgentComponentLabel,
+			labels.AppManagedByLabel: defaultOperatorAppName,
+		}),
+	)
+
+	logBuffer := bytes.Buffer{}
+
+	buffer := bytes.Buffer{}
+	supportArchive := newZipArchive(bufio.NewWriter(&buffer))
+	defer assertNoErrorOnClose(t, supportArchive)
+
+	rce := mocks.NewExecutor(t)
+	expectExec(rce, "oneagent-1", oneAgentContainerName, "if [ -d '"+oneAgentLogDir+"' ]; then find "+oneAgentLogDir+" -type f -name '*.log' -mmin -121 ; fi", "")
+
+	nodeCollector := newNodeCollector(context.Background(),
+		nil,
+		rce,
+		newSupportArchiveLogger(&logBuffer),
+		supportArchive,
+		fakeClientSet.CoreV1().Pods("dynatrace"),
+		defaultOperatorAppName,
+		nil,
+		2*time.Hour)
+
+	require.NoError(t, nodeCollector.Do())
+}
+
+func expectExec(rce *mocks.Executor, podName string, containerName string, command string, output string) {
+	rce.On("Exec", mock.Anything, mock.Anything, podName, "dynatrace", containerName, []string{"/usr/bin/sh", "-c", command}).
+		Return(bytes.NewBufferString(output), &bytes.Buffer{}, nil)
+}
+
+func createNodePod(name string, nodeName string, podLabels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "dynatrace",
+			Labels:    podLabels,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+	}
+}
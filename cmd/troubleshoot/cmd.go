@@ -2,6 +2,8 @@ package troubleshoot
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 
@@ -31,12 +33,14 @@ const (
 var (
 	dynakubeFlagValue  string
 	namespaceFlagValue string
+	outputFlagValue    string
 )
 
 func New() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:  use,
-		RunE: run(),
+		Use:          use,
+		RunE:         run(),
+		SilenceUsage: true,
 	}
 
 	addFlags(cmd)
@@ -47,6 +51,7 @@ func New() *cobra.Command {
 func addFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&dynakubeFlagValue, dynakubeFlagName, dynakubeFlagShorthand, "", "Specify a different Dynakube name.")
 	cmd.PersistentFlags().StringVarP(&namespaceFlagValue, namespaceFlagName, namespaceFlagShorthand, env.DefaultNamespace(), "Specify a different Namespace.")
+	cmd.PersistentFlags().StringVarP(&outputFlagValue, outputFlagName, outputFlagShorthand, outputText, "Output format of the results: text, json, yaml or junit. The logs are written to stderr for every format except text.")
 }
 
 func clusterOptions(opts *cluster.Options) {
@@ -55,6 +60,17 @@ func clusterOptions(opts *cluster.Options) {
 
 func run() func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
+		if !isValidOutputFormat(outputFlagValue) {
+			return errors.Errorf("unsupported output format '%s'", outputFlagValue)
+		}
+
+		var logOutput io.Writer = os.Stdout
+		if outputFlagValue != outputText {
+			// keep stdout free for the machine-readable results
+			logOutput = os.Stderr
+			logd.SetOutput(logOutput)
+		}
+
 		version.LogVersion()
 		logd.LogBaseLoggerSettings()
 
@@ -63,43 +79,78 @@ func run() func(*cobra.Command, []string) error {
 			return err
 		}
 
-		log := NewTroubleshootLoggerToWriter(os.Stdout)
+		log := NewTroubleshootLoggerToWriter(logOutput)
 
-		RunTroubleshootCmd(cmd.Context(), log, namespaceFlagValue, kubeConfig)
+		results := RunTroubleshootCmd(cmd.Context(), log, namespaceFlagValue, kubeConfig)
+
+		err = results.write(os.Stdout, outputFlagValue)
+		if err != nil {
+			return err
+		}
+
+		if results.Failed() {
+			return errChecksFailed
+		}
 
 		return nil
 	}
 }
 
-func RunTroubleshootCmd(ctx context.Context, log logd.Logger, namespaceName string, kubeConfig *rest.Config) {
+// RunTroubleshootCmd runs all the checks, logs their progress and returns their results.
+func RunTroubleshootCmd(ctx context.Context, log logd.Logger, namespaceName string, kubeConfig *rest.Config) *Results {
+	results := &Results{}
+
 	err := checkOneAgentAPM(log, kubeConfig)
+	results.addFromError(checkNameOneAgentAPM, "", "OneAgentAPM does not exist", err, oneAgentAPMRemediation)
+
 	if err != nil {
 		logErrorf(log, "prerequisite checks failed, aborting (%v)", err)
 
-		return
+		return results
 	}
 
 	apiReader, err := GetK8SClusterAPIReader(kubeConfig)
 	if err != nil {
-		return
+		results.addFromError(checkNameKubernetesAPI, "", "", err, kubernetesAPIRemediation)
+
+		return results
 	}
 
 	err = checkNamespace(ctx, log, apiReader, namespaceName)
+	results.addFromError(checkNameNamespace, "", fmt.Sprintf("namespace '%s' exists", namespaceName), err, namespaceRemediation())
+
 	if err != nil {
 		logErrorf(log, "prerequisite checks failed, aborting (%v)", err)
 
-		return
+		return results
 	}
 
 	dks, err := getDynakubes(ctx, log, apiReader, namespaceName, dynakubeFlagValue)
 
-	if checkCRD(log, err) != nil {
+	crdErr := checkCRD(log, err)
+	if crdErr != nil {
+		results.addFromError(checkNameCRD, "", "", crdErr, crdRemediation)
 		logErrorf(log, "error during getting dynakubes: %v", err)
 
-		return
+		return results
 	}
 
-	runChecksForAllDynakubes(ctx, log, apiReader, &http.Client{}, dks)
+	results.addFromError(checkNameCRD, "", "CRD for Dynakube exists", nil, "")
+
+	if len(dks) == 0 {
+		results.add(CheckResult{
+			Check:       checkNameDynaKube,
+			Status:      StatusFailed,
+			Message:     fmt.Sprintf("no Dynakubes found in namespace '%s'", namespaceName),
+			Remediation: dynakubeNotValidMessage(),
+		})
+
+		return results
+	}
+
+	runChecksForAllDynakubes(ctx, log, apiReader, &http.Client{}, dks, results)
+
+	return results
 }
 
 func GetK8SClusterAPIReader(kubeConfig *rest.Config) (client.Reader, error) {
@@ -111,45 +162,55 @@ func GetK8SClusterAPIReader(kubeConfig *rest.Config) (client.Reader, error) {
 	return k8scluster.GetAPIReader(), nil
 }
 
-func runChecksForAllDynakubes(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, httpClient *http.Client, dynakubes []dynakube.DynaKube) {
+func runChecksForAllDynakubes(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, httpClient *http.Client, dynakubes []dynakube.DynaKube, results *Results) { //nolint:revive // argument-limit
 	for _, dk := range dynakubes {
-		err := runChecksForDynakube(ctx, baseLog, apiReader, httpClient, dk)
+		err := runChecksForDynakube(ctx, baseLog, apiReader, httpClient, dk, results)
 		if err != nil {
 			logErrorf(baseLog, "Error in DynaKube %s/%s", dk.Namespace, dk.Name)
 		}
 	}
 }
 
-func runChecksForDynakube(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, httpClient *http.Client, dk dynakube.DynaKube) error {
+func runChecksForDynakube(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, httpClient *http.Client, dk dynakube.DynaKube, results *Results) error { //nolint:revive // argument-limit
 	log := baseLog.WithName(dynakubeCheckLoggerName)
+	dkName := dk.Namespace + "/" + dk.Name
 
 	logNewCheckf(log, "checking if '%s:%s' Dynakube is configured correctly", dk.Namespace, dk.Name)
 	logInfof(log, "using '%s:%s' Dynakube", dk.Namespace, dk.Name)
 
 	pullSecret, err := checkDynakube(ctx, baseLog, apiReader, &dk)
 	if err != nil {
-		return errors.Wrapf(err, "'%s:%s' Dynakube isn't valid. %s",
+		err = errors.Wrapf(err, "'%s:%s' Dynakube isn't valid. %s",
 			dk.Namespace, dk.Name, dynakubeNotValidMessage())
+		results.addFromError(checkNameDynaKube, dkName, "", err, dynakubeRemediation)
+
+		return err
 	}
 
 	logOkf(log, "'%s:%s' Dynakube is valid", dk.Namespace, dk.Name)
+	results.addFromError(checkNameDynaKube, dkName, "Dynakube is valid", nil, "")
 
 	keychain, err := dockerkeychain.NewDockerKeychain(ctx, apiReader, pullSecret)
 	if err != nil {
+		results.addFromError(checkNameImagePull, dkName, "", err, imagePullRemediation)
+
 		return err
 	}
 
 	transport, err := createTransport(ctx, apiReader, &dk, httpClient)
 	if err != nil {
+		results.addFromError(checkNameImagePull, dkName, "", err, imagePullRemediation)
+
 		return err
 	}
 
-	err = verifyAllImagesAvailable(ctx, log, keychain, transport, &dk)
-	if err != nil {
-		return err
+	for _, result := range verifyAllImagesAvailable(ctx, log, keychain, transport, &dk) {
+		results.add(result)
 	}
 
-	return checkProxySettings(ctx, log, apiReader, &dk)
+	results.add(checkProxySettings(ctx, log, apiReader, &dk))
+
+	return nil
 }
 
 func createTransport(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube, httpClient *http.Client) (*http.Transport, error) {
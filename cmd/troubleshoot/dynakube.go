@@ -3,7 +3,9 @@ package troubleshoot
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dtpullsecret"
@@ -137,6 +139,32 @@ func checkDynatraceApiTokenScopes(ctx context.Context, baseLog logd.Logger, apiR
 
 	logInfof(log, "token scopes are valid")
 
+	return checkTokenExpiration(log, tokens, dk, time.Now())
+}
+
+func checkTokenExpiration(log logd.Logger, tokens token.Tokens, dk *dynakube.DynaKube, now time.Time) error {
+	warningThreshold := exp.NewFlags(dk.Annotations).GetTokenExpiryWarningThresholds()[0]
+
+	for _, tokenStatus := range tokens.ExpirationStatus() {
+		if tokenStatus.ExpirationDate == nil {
+			logInfof(log, "token '%s' doesn't expire", tokenStatus.Type)
+
+			continue
+		}
+
+		expirationDate := tokenStatus.ExpirationDate.UTC().Format(time.RFC3339)
+		remaining := tokenStatus.ExpirationDate.Sub(now)
+
+		switch {
+		case remaining <= 0:
+			return errors.Errorf("token '%s' expired at %s", tokenStatus.Type, expirationDate)
+		case remaining <= warningThreshold:
+			logWarningf(log, "token '%s' expires at %s, in %s, rotate it soon", tokenStatus.Type, expirationDate, remaining.Round(time.Minute))
+		default:
+			logInfof(log, "token '%s' expires at %s", tokenStatus.Type, expirationDate)
+		}
+	}
+
 	return nil
 }
 
@@ -3,12 +3,15 @@ package troubleshoot
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -140,6 +143,29 @@ func TestDynatraceSecret(t *testing.T) {
 	})
 }
 
+func TestTokenExpiration(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	dk := testNewDynakubeBuilder(testNamespace, testDynakube).build()
+
+	createTokens := func(expirationDate *time.Time) token.Tokens {
+		apiToken := token.Token{Type: dtclient.ApiToken, Value: testApiToken, ExpirationDate: expirationDate}
+
+		return token.Tokens{dtclient.ApiToken: &apiToken}.AddFeatureScopesToTokens()
+	}
+
+	t.Run("token without expiration date", func(t *testing.T) {
+		require.NoError(t, checkTokenExpiration(getNullLogger(t), createTokens(nil), dk, now))
+	})
+	t.Run("token expires soon", func(t *testing.T) {
+		expirationDate := now.Add(48 * time.Hour)
+		require.NoError(t, checkTokenExpiration(getNullLogger(t), createTokens(&expirationDate), dk, now))
+	})
+	t.Run("token expired", func(t *testing.T) {
+		expirationDate := now.Add(-time.Hour)
+		require.ErrorContains(t, checkTokenExpiration(getNullLogger(t), createTokens(&expirationDate), dk, now), "token 'apiToken' expired at 2026-10-01T11:00:00Z")
+	})
+}
+
 func TestPullSecret(t *testing.T) {
 	t.Run("custom pull secret exists", func(t *testing.T) {
 		dk := testNewDynakubeBuilder(testNamespace, testDynakube).withCustomPullSecret(testSecretName).build()
@@ -2,6 +2,7 @@ package troubleshoot
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
@@ -30,29 +31,42 @@ type Auths struct {
 
 type ImagePullFunc func(image string) error
 
-func verifyAllImagesAvailable(ctx context.Context, baseLog logd.Logger, keychain authn.Keychain, transport *http.Transport, dk *dynakube.DynaKube) error {
+func verifyAllImagesAvailable(ctx context.Context, baseLog logd.Logger, keychain authn.Keychain, transport *http.Transport, dk *dynakube.DynaKube) []CheckResult {
 	log := baseLog.WithName("imagepull")
 
 	imagePullFunc := CreateImagePullFunc(ctx, keychain, transport)
 
+	var results []CheckResult
+
 	if dk.OneAgent().IsDaemonsetRequired() {
-		verifyImageIsAvailable(log, imagePullFunc, dk, componentOneAgent, false)
-		verifyImageIsAvailable(log, imagePullFunc, dk, componentCodeModules, true)
+		results = append(results,
+			verifyImageIsAvailable(log, imagePullFunc, dk, componentOneAgent, false),
+			verifyImageIsAvailable(log, imagePullFunc, dk, componentCodeModules, true),
+		)
 	}
 
 	if dk.ActiveGate().IsEnabled() {
-		verifyImageIsAvailable(log, imagePullFunc, dk, componentActiveGate, false)
+		results = append(results, verifyImageIsAvailable(log, imagePullFunc, dk, componentActiveGate, false))
 	}
 
-	return nil
+	return results
 }
 
-func verifyImageIsAvailable(log logd.Logger, pullImage ImagePullFunc, dk *dynakube.DynaKube, comp component, proxyWarning bool) {
+func verifyImageIsAvailable(log logd.Logger, pullImage ImagePullFunc, dk *dynakube.DynaKube, comp component, proxyWarning bool) CheckResult {
+	result := CheckResult{
+		Check:    checkNameImagePull,
+		DynaKube: dk.Namespace + "/" + dk.Name,
+	}
+
 	image, isCustomImage := comp.getImage(dk)
 	if comp.SkipImageCheck(image) {
 		logErrorf(log, "Unknown %s image", comp.String())
 
-		return
+		result.Status = StatusFailed
+		result.Message = fmt.Sprintf("Unknown %s image", comp.String())
+		result.Remediation = imagePullRemediation
+
+		return result
 	}
 
 	componentName := comp.Name(isCustomImage)
@@ -61,23 +75,40 @@ func verifyImageIsAvailable(log logd.Logger, pullImage ImagePullFunc, dk *dynaku
 	if image == "" {
 		logInfof(log, "No %s image configured", componentName)
 
-		return
+		result.Status = StatusSkipped
+		result.Message = fmt.Sprintf("No %s image configured", componentName)
+
+		return result
 	}
 
+	result.Status = StatusPassed
+
 	if dk.HasProxy() && proxyWarning {
 		logWarningf(log, "Proxy setting in Dynakube is ignored for %s image due to technical limitations.", componentName)
+
+		result.Status = StatusWarning
 	}
 
 	if getEnvProxySettings() != nil {
 		logWarningf(log, "Proxy settings in environment might interfere when pulling %s image in troubleshoot mode.", componentName)
+
+		result.Status = StatusWarning
 	}
 
 	err := pullImage(image)
 	if err != nil {
 		logErrorf(log, "Pulling %s image %s failed: %v", componentName, image, err)
+
+		result.Status = StatusFailed
+		result.Message = fmt.Sprintf("Pulling %s image %s failed: %v", componentName, image, err)
+		result.Remediation = imagePullRemediation
 	} else {
 		logOkf(log, "%s image %s can be successfully pulled", componentName, image)
+
+		result.Message = fmt.Sprintf("%s image %s can be successfully pulled", componentName, image)
 	}
+
+	return result
 }
 
 func CreateImagePullFunc(ctx context.Context, keychain authn.Keychain, transport *http.Transport) ImagePullFunc {
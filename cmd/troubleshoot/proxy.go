@@ -2,6 +2,7 @@ package troubleshoot
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
@@ -9,9 +10,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func checkProxySettings(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, dk *dynakube.DynaKube) error {
+func checkProxySettings(ctx context.Context, baseLog logd.Logger, apiReader client.Reader, dk *dynakube.DynaKube) CheckResult {
 	log := baseLog.WithName("proxy")
 
+	result := CheckResult{
+		Check:    checkNameProxy,
+		DynaKube: dk.Namespace + "/" + dk.Name,
+	}
+
 	var proxyURL string
 
 	logNewCheckf(log, "Analyzing proxy settings ...")
@@ -29,7 +35,11 @@ func checkProxySettings(ctx context.Context, baseLog logd.Logger, apiReader clie
 		if err != nil {
 			logErrorf(log, "Unexpected error when reading proxy settings from Dynakube: %v", err)
 
-			return nil
+			result.Status = StatusFailed
+			result.Message = fmt.Sprintf("Unexpected error when reading proxy settings from Dynakube: %v", err)
+			result.Remediation = proxyRemediation
+
+			return result
 		}
 	}
 
@@ -39,9 +49,17 @@ func checkProxySettings(ctx context.Context, baseLog logd.Logger, apiReader clie
 
 	if !proxySettingsAvailable {
 		logOkf(log, "No proxy settings found.")
+
+		result.Status = StatusPassed
+		result.Message = "No proxy settings found."
+
+		return result
 	}
 
-	return nil
+	result.Status = StatusWarning
+	result.Message = "Proxy settings found, they don't apply to all image pulls, see the log for details."
+
+	return result
 }
 
 func checkEnvironmentProxySettings(log logd.Logger, proxyURL string) bool {
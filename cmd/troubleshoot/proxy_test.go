@@ -17,8 +17,10 @@ func TestCheckProxySettings(t *testing.T) {
 		t.Setenv("HTTP_PROXY", "")
 		t.Setenv("HTTPS_PROXY", "")
 
+		var result CheckResult
+
 		logOutput := runWithTestLogger(func(logger logd.Logger) {
-			checkProxySettings(context.Background(), logger, nil, &dynakube.DynaKube{})
+			result = checkProxySettings(context.Background(), logger, nil, &dynakube.DynaKube{})
 		})
 
 		require.NotContains(t, logOutput, "Unexpected error")
@@ -26,15 +28,20 @@ func TestCheckProxySettings(t *testing.T) {
 		assert.NotContains(t, logOutput, "HTTPS_PROXY")
 		assert.NotContains(t, logOutput, "Dynakube")
 		assert.Contains(t, logOutput, "No proxy settings found.")
+		assert.Equal(t, StatusPassed, result.Status)
 	})
 	t.Run("HTTP_PROXY", func(t *testing.T) {
 		t.Setenv("HTTP_PROXY", "foobar:1234")
 		t.Setenv("HTTPS_PROXY", "")
 
+		var result CheckResult
+
 		logOutput := runWithTestLogger(func(logger logd.Logger) {
-			checkProxySettings(context.Background(), logger, nil, &dynakube.DynaKube{})
+			result = checkProxySettings(context.Background(), logger, nil, &dynakube.DynaKube{})
 		})
 
+		assert.Equal(t, StatusWarning, result.Status)
+
 		require.NotContains(t, logOutput, "Unexpected error")
 		assert.Contains(t, logOutput, "HTTP_PROXY")
 		assert.NotContains(t, logOutput, "HTTPS_PROXY")
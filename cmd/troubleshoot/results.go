@@ -0,0 +1,186 @@
+package troubleshoot
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputFlagName      = "output"
+	outputFlagShorthand = "o"
+
+	outputText  = "text"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputJUnit = "junit"
+
+	junitSuiteName = "dynatrace-operator-troubleshoot"
+)
+
+type CheckStatus string
+
+const (
+	StatusPassed  CheckStatus = "passed"
+	StatusWarning CheckStatus = "warning"
+	StatusFailed  CheckStatus = "failed"
+	StatusSkipped CheckStatus = "skipped"
+)
+
+const (
+	checkNameOneAgentAPM   = "oneAgentAPM"
+	checkNameKubernetesAPI = "kubernetesAPI"
+	checkNameNamespace     = "namespace"
+	checkNameCRD           = "crd"
+	checkNameDynaKube      = dynakubeCheckLoggerName
+	checkNameImagePull     = "imagepull"
+	checkNameProxy         = "proxy"
+)
+
+const (
+	oneAgentAPMRemediation   = "Delete the OneAgentAPM objects, or fully install the oneAgent operator."
+	kubernetesAPIRemediation = "Check the kubeconfig and that the Kubernetes API is reachable."
+	crdRemediation           = "Install the Dynakube CRD that matches the version of the operator."
+	dynakubeRemediation      = "Check the tokens and pull secret referenced by the Dynakube and that the API URL is reachable."
+	imagePullRemediation     = "Check that the image exists and that the pull secret of the Dynakube grants access to its registry."
+	proxyRemediation         = "Check the proxy settings in the Dynakube and in the environment."
+)
+
+var errChecksFailed = errors.New("troubleshoot checks failed")
+
+// CheckResult is the machine-readable outcome of a single check.
+type CheckResult struct {
+	Check       string      `json:"check"`
+	Status      CheckStatus `json:"status"`
+	Message     string      `json:"message"`
+	DynaKube    string      `json:"dynakube,omitempty"`
+	Remediation string      `json:"remediation,omitempty"`
+}
+
+type Results struct {
+	Checks []CheckResult `json:"checks"`
+}
+
+func (results *Results) add(result CheckResult) {
+	results.Checks = append(results.Checks, result)
+}
+
+// addFromError records a passed check, or a failed one if an error is given.
+func (results *Results) addFromError(check, dkName, okMessage string, err error, remediation string) {
+	if err != nil {
+		results.add(CheckResult{Check: check, Status: StatusFailed, Message: err.Error(), DynaKube: dkName, Remediation: remediation})
+
+		return
+	}
+
+	results.add(CheckResult{Check: check, Status: StatusPassed, Message: okMessage, DynaKube: dkName})
+}
+
+func (results *Results) Failed() bool {
+	return slices.ContainsFunc(results.Checks, func(result CheckResult) bool {
+		return result.Status == StatusFailed
+	})
+}
+
+func namespaceRemediation() string {
+	return fmt.Sprintf("Provide the namespace the operator is installed in with '--%s <namespace>'.", namespaceFlagName)
+}
+
+func isValidOutputFormat(format string) bool {
+	return slices.Contains([]string{outputText, outputJSON, outputYAML, outputJUnit}, format)
+}
+
+// write prints the results in the given format, the text format is already printed by the logger while the checks run.
+func (results *Results) write(out io.Writer, format string) error {
+	var (
+		content []byte
+		err     error
+	)
+
+	switch format {
+	case outputJSON:
+		content, err = json.MarshalIndent(results, "", "  ")
+	case outputYAML:
+		content, err = yaml.Marshal(results)
+	case outputJUnit:
+		content, err = results.junit()
+	default:
+		return nil
+	}
+
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = fmt.Fprintln(out, string(content))
+
+	return errors.WithStack(err)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+func (results *Results) junit() ([]byte, error) {
+	suite := junitTestSuite{
+		Name:  junitSuiteName,
+		Tests: len(results.Checks),
+	}
+
+	for _, result := range results.Checks {
+		testCase := junitTestCase{
+			Name:      result.Check,
+			ClassName: junitSuiteName,
+			SystemOut: result.Message,
+		}
+
+		if result.DynaKube != "" {
+			testCase.Name = result.Check + " " + result.DynaKube
+		}
+
+		switch result.Status {
+		case StatusFailed:
+			suite.Failures++
+			testCase.Failure = &junitMessage{Message: result.Message, Content: result.Remediation}
+		case StatusSkipped:
+			suite.Skipped++
+			testCase.Skipped = &junitMessage{Message: result.Message}
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	content, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), content...), nil
+}
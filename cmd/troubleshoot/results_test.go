@@ -0,0 +1,76 @@
+package troubleshoot
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func createTestResults() *Results {
+	results := &Results{}
+	results.addFromError(checkNameNamespace, "", "namespace 'dynatrace' exists", nil, "")
+	results.add(CheckResult{Check: checkNameImagePull, Status: StatusSkipped, Message: "No OneAgent image configured", DynaKube: "dynatrace/dk"})
+	results.addFromError(checkNameDynaKube, "dynatrace/dk", "", assert.AnError, dynakubeRemediation)
+
+	return results
+}
+
+func TestResults(t *testing.T) {
+	t.Run("failed if any check failed", func(t *testing.T) {
+		assert.True(t, createTestResults().Failed())
+	})
+
+	t.Run("not failed if only warnings", func(t *testing.T) {
+		results := &Results{}
+		results.add(CheckResult{Check: checkNameProxy, Status: StatusWarning})
+
+		assert.False(t, results.Failed())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out := bytes.Buffer{}
+		require.NoError(t, createTestResults().write(&out, outputJSON))
+
+		var parsed Results
+		require.NoError(t, json.Unmarshal(out.Bytes(), &parsed))
+		assert.Equal(t, *createTestResults(), parsed)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		out := bytes.Buffer{}
+		require.NoError(t, createTestResults().write(&out, outputYAML))
+
+		var parsed Results
+		require.NoError(t, yaml.Unmarshal(out.Bytes(), &parsed))
+		assert.Equal(t, *createTestResults(), parsed)
+	})
+
+	t.Run("junit", func(t *testing.T) {
+		out := bytes.Buffer{}
+		require.NoError(t, createTestResults().write(&out, outputJUnit))
+
+		var parsed junitTestSuites
+		require.NoError(t, xml.Unmarshal(out.Bytes(), &parsed))
+		require.Len(t, parsed.Suites, 1)
+
+		suite := parsed.Suites[0]
+		assert.Equal(t, 3, suite.Tests)
+		assert.Equal(t, 1, suite.Failures)
+		assert.Equal(t, 1, suite.Skipped)
+		assert.Equal(t, "dynakube dynatrace/dk", suite.TestCases[2].Name)
+		require.NotNil(t, suite.TestCases[2].Failure)
+		assert.Equal(t, dynakubeRemediation, suite.TestCases[2].Failure.Content)
+	})
+
+	t.Run("text is only logged", func(t *testing.T) {
+		out := bytes.Buffer{}
+		require.NoError(t, createTestResults().write(&out, outputText))
+
+		assert.Empty(t, out.String())
+	})
+}
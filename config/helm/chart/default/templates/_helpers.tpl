@@ -84,3 +84,53 @@ startupProbe:
       "kspm": {{ .Values.rbac.kspm.create }}
     }
 {{- end -}}
+
+{{- define "dynatrace-operator.registry-mirror-json-env" -}}
+{{- if .Values.registryMirror -}}
+- name: registry-mirror.json
+  value: {{ .Values.registryMirror | toJson | quote }}
+{{- end }}
+{{- end -}}
+
+{{- define "dynatrace-operator.certificate-source-json-env" -}}
+{{- if .Values.certificateSource -}}
+- name: certificate-source.json
+  value: {{ .Values.certificateSource | toJson | quote }}
+{{- end }}
+{{- end -}}
+
+{{- define "dynatrace-operator.token-source-json-env" -}}
+{{- $tokenSource := pick (.Values.tokenSource | default dict) "vaultAddress" "oauthTokenUrls" -}}
+{{- if $tokenSource -}}
+- name: token-source.json
+  value: {{ $tokenSource | toJson | quote }}
+{{- end }}
+{{- end -}}
+
+{{- define "dynatrace-operator.token-source-volumes" -}}
+{{- if (.Values.tokenSource).serviceAccountTokenAudience }}
+- name: token-source-service-account-token
+  projected:
+    sources:
+      - serviceAccountToken:
+          path: token
+          audience: {{ .Values.tokenSource.serviceAccountTokenAudience }}
+          expirationSeconds: 3600
+{{- end }}
+{{- range (.Values.tokenSource).volumes }}
+- {{- toYaml . | nindent 2 }}
+{{- end }}
+{{- end -}}
+
+{{- define "dynatrace-operator.token-source-volume-mounts" -}}
+{{- if (.Values.tokenSource).serviceAccountTokenAudience }}
+- name: token-source-service-account-token
+  mountPath: /var/run/secrets/dynatrace.com/serviceaccount
+  readOnly: true
+{{- end }}
+{{- range (.Values.tokenSource).volumes }}
+- name: {{ .name }}
+  mountPath: /var/run/secrets/dynatrace.com/tokens/{{ .name }}
+  readOnly: true
+{{- end }}
+{{- end -}}
@@ -1,15 +1,20 @@
 package exp
 
 import (
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
 	FFPrefix = "feature.dynatrace.com/"
 
-	PublicRegistryKey = FFPrefix + "public-registry"
-	NoProxyKey        = FFPrefix + "no-proxy"
+	PublicRegistryKey               = FFPrefix + "public-registry"
+	NoProxyKey                      = FFPrefix + "no-proxy"
+	TokenExpiryWarningThresholdsKey = FFPrefix + "token-expiry-warning-thresholds"
 
 	// Deprecated: Dedicated field since v1beta2.
 	ApiRequestThresholdKey = FFPrefix + "dynatrace-api-request-threshold"
@@ -18,6 +23,9 @@ const (
 	failPhrase   = "fail"
 
 	DefaultMinRequestThresholdMinutes = 15
+
+	// DefaultTokenExpiryWarningThresholds warns 30 days, 7 days and 1 day before a token expires.
+	DefaultTokenExpiryWarningThresholds = "720h,168h,24h"
 )
 
 type FeatureFlags struct {
@@ -43,6 +51,44 @@ func (ff *FeatureFlags) GetNoProxy() string {
 	return ff.getRaw(NoProxyKey)
 }
 
+// GetTokenExpiryWarningThresholds returns the durations before the expiry of a token, at which a warning is raised, longest first.
+// The value is a comma separated list of durations, like "720h,168h,24h", the default is used if any of them is invalid.
+func (ff *FeatureFlags) GetTokenExpiryWarningThresholds() []time.Duration {
+	thresholds, err := parseDurations(ff.getRaw(TokenExpiryWarningThresholdsKey))
+	if err != nil || len(thresholds) == 0 {
+		thresholds, _ = parseDurations(DefaultTokenExpiryWarningThresholds)
+	}
+
+	slices.Sort(thresholds)
+	slices.Reverse(thresholds)
+
+	return slices.Compact(thresholds)
+}
+
+func parseDurations(raw string) ([]time.Duration, error) {
+	durations := []time.Duration{}
+
+	for _, value := range strings.Split(raw, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if duration <= 0 {
+			return nil, errors.Errorf("duration '%s' is not positive", value)
+		}
+
+		durations = append(durations, duration)
+	}
+
+	return durations, nil
+}
+
 func (ff *FeatureFlags) IsPublicRegistry() bool {
 	return ff.getBoolWithDefault(PublicRegistryKey, false)
 }
@@ -287,3 +287,44 @@ func TestGetFlagBoolWithDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTokenExpiryWarningThresholds(t *testing.T) {
+	defaultThresholds := []time.Duration{720 * time.Hour, 168 * time.Hour, 24 * time.Hour}
+
+	cases := []struct {
+		title string
+		in    string
+		out   []time.Duration
+	}{
+		{
+			title: "default",
+			in:    "",
+			out:   defaultThresholds,
+		},
+		{
+			title: "overrule, sorted and without duplicates",
+			in:    "1h, 48h,1h",
+			out:   []time.Duration{48 * time.Hour, time.Hour},
+		},
+		{
+			title: "invalid duration",
+			in:    "48h,tomorrow",
+			out:   defaultThresholds,
+		},
+		{
+			title: "negative duration",
+			in:    "-48h",
+			out:   defaultThresholds,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			ff := FeatureFlags{annotations: map[string]string{
+				TokenExpiryWarningThresholdsKey: c.in,
+			}}
+
+			assert.Equal(t, c.out, ff.GetTokenExpiryWarningThresholds())
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package maintenance
+
+import (
+	"time"
+	_ "time/tzdata" // the operator image doesn't ship the time zone database
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/cron"
+	"github.com/pkg/errors"
+)
+
+// Validate returns an error, if the time zone or one of the windows can't be parsed.
+func (schedule *Schedule) Validate() error {
+	_, err := schedule.location()
+	if err != nil {
+		return err
+	}
+
+	for _, window := range schedule.Windows {
+		_, err := cron.Parse(window.Start)
+		if err != nil {
+			return err
+		}
+
+		if window.Duration.Duration <= 0 {
+			return errors.Errorf("duration of window %q must be positive", window.Start)
+		}
+	}
+
+	return nil
+}
+
+// IsOpen returns true if one of the windows is open at the given time.
+// A nil schedule, or one without valid windows, is always open.
+func (schedule *Schedule) IsOpen(now time.Time) bool {
+	if schedule == nil {
+		return true
+	}
+
+	location, err := schedule.location()
+	if err != nil {
+		return true
+	}
+
+	now = now.In(location)
+	hasValidWindow := false
+
+	for _, window := range schedule.Windows {
+		start, err := cron.Parse(window.Start)
+		if err != nil || window.Duration.Duration <= 0 {
+			continue
+		}
+
+		hasValidWindow = true
+
+		lastStart := start.Next(now.Add(-window.Duration.Duration))
+		if !lastStart.IsZero() && !lastStart.After(now) {
+			return true
+		}
+	}
+
+	return !hasValidWindow
+}
+
+// NextOpening returns when the next window starts after the given time, or the zero time if the schedule is nil or never opens.
+func (schedule *Schedule) NextOpening(now time.Time) time.Time {
+	if schedule == nil {
+		return time.Time{}
+	}
+
+	location, err := schedule.location()
+	if err != nil {
+		return time.Time{}
+	}
+
+	var next time.Time
+
+	for _, window := range schedule.Windows {
+		start, err := cron.Parse(window.Start)
+		if err != nil {
+			continue
+		}
+
+		windowStart := start.Next(now.In(location))
+		if !windowStart.IsZero() && (next.IsZero() || windowStart.Before(next)) {
+			next = windowStart
+		}
+	}
+
+	return next
+}
+
+func (schedule *Schedule) location() (*time.Location, error) {
+	if schedule.TimeZone == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(schedule.TimeZone)
+
+	return location, errors.WithStack(err)
+}
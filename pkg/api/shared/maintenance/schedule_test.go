@@ -0,0 +1,53 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSchedule(t *testing.T) {
+	// Wednesday, 10:30 in UTC and 11:30 in Vienna
+	now := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC)
+	nightly := &Schedule{
+		TimeZone: "Europe/Vienna",
+		Windows: []Window{
+			{Start: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			{Start: "0 0 * * 6", Duration: metav1.Duration{Duration: 48 * time.Hour}},
+		},
+	}
+
+	t.Run("nil schedule is always open", func(t *testing.T) {
+		var schedule *Schedule
+
+		assert.True(t, schedule.IsOpen(now))
+		assert.True(t, schedule.NextOpening(now).IsZero())
+	})
+
+	t.Run("closed during business hours", func(t *testing.T) {
+		assert.False(t, nightly.IsOpen(now))
+		assert.Equal(t, time.Date(2025, time.January, 15, 21, 0, 0, 0, time.UTC), nightly.NextOpening(now).UTC())
+	})
+
+	t.Run("open after midnight", func(t *testing.T) {
+		assert.True(t, nightly.IsOpen(time.Date(2025, time.January, 15, 23, 30, 0, 0, time.UTC)))
+	})
+
+	t.Run("closed after the window ended", func(t *testing.T) {
+		assert.False(t, nightly.IsOpen(time.Date(2025, time.January, 16, 1, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("open on the weekend", func(t *testing.T) {
+		assert.True(t, nightly.IsOpen(time.Date(2025, time.January, 19, 12, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, nightly.Validate())
+		require.Error(t, (&Schedule{TimeZone: "Mars/Olympus"}).Validate())
+		require.Error(t, (&Schedule{Windows: []Window{{Start: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}}}}).Validate())
+		require.Error(t, (&Schedule{Windows: []Window{{Start: "0 22 * * *"}}}).Validate())
+	})
+}
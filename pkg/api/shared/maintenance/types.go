@@ -0,0 +1,26 @@
+package maintenance
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:generate=true
+type Schedule struct {
+	// Time zone of the windows as IANA name, e.g. Europe/Vienna (the default value is: UTC)
+	// +kubebuilder:example:="Europe/Vienna"
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Windows in which automatic updates are applied, updates found outside of them are pending until the next window starts
+	// +kubebuilder:validation:MinItems=1
+	Windows []Window `json:"windows"`
+}
+
+// +kubebuilder:object:generate=true
+type Window struct {
+	// Start of the window as cron expression (minute hour day-of-month month day-of-week), e.g. "0 22 * * 1-5" for 22:00 on weekdays
+	// +kubebuilder:example:="0 22 * * 1-5"
+	Start string `json:"start"`
+
+	// Duration of the window, e.g. 4h
+	Duration metav1.Duration `json:"duration"`
+}
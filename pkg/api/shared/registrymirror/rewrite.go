@@ -0,0 +1,125 @@
+package registrymirror
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Rewrite returns the image reference redirected according to the first spec with a matching rule.
+// The specs are given in the order of their precedence, nil specs are skipped.
+// Rewriting is idempotent, an image that already points to the mirror of its rule is returned as is.
+func Rewrite(image string, specs ...*Spec) string {
+	for _, spec := range specs {
+		rule, ok := spec.match(image)
+		if !ok {
+			continue
+		}
+
+		to := normalizePrefix(rule.To)
+		if hasPrefix(image, to) {
+			return image
+		}
+
+		return to + strings.TrimPrefix(image, normalizePrefix(rule.From))
+	}
+
+	return image
+}
+
+// Restore returns the original image reference of one redirected by Rewrite, so it can be used independent of the mirrors.
+// An image that wasn't redirected is returned as is, an image that could have been redirected from different references is an error.
+func Restore(image string, specs ...*Spec) (string, error) {
+	restored := image
+
+	for _, spec := range specs {
+		if spec == nil {
+			continue
+		}
+
+		for _, rule := range spec.Rules {
+			from, to := normalizePrefix(rule.From), normalizePrefix(rule.To)
+			if from == "" || to == "" || !hasPrefix(image, to) {
+				continue
+			}
+
+			candidate := from + strings.TrimPrefix(image, to)
+			if Rewrite(candidate, specs...) != image {
+				continue
+			}
+
+			if restored != image && restored != candidate {
+				return "", errors.Errorf("image %s could be redirected from %s and %s", image, restored, candidate)
+			}
+
+			restored = candidate
+		}
+	}
+
+	return restored, nil
+}
+
+// PullSecrets returns the names of the pull secrets of all rules, without duplicates.
+func PullSecrets(specs ...*Spec) []string {
+	var names []string
+
+	seen := map[string]bool{}
+
+	for _, spec := range specs {
+		if spec == nil {
+			continue
+		}
+
+		for _, rule := range spec.Rules {
+			if rule.PullSecret == "" || seen[rule.PullSecret] {
+				continue
+			}
+
+			seen[rule.PullSecret] = true
+
+			names = append(names, rule.PullSecret)
+		}
+	}
+
+	return names
+}
+
+func (spec *Spec) match(image string) (Rule, bool) {
+	var (
+		longest Rule
+		found   bool
+	)
+
+	if spec == nil {
+		return longest, false
+	}
+
+	for _, rule := range spec.Rules {
+		from := normalizePrefix(rule.From)
+		if from == "" || !hasPrefix(image, from) {
+			continue
+		}
+
+		if !found || len(from) > len(normalizePrefix(longest.From)) {
+			longest = rule
+			found = true
+		}
+	}
+
+	return longest, found
+}
+
+// hasPrefix returns true if the prefix covers complete path segments of the image, or its repository (followed by a tag or digest).
+func hasPrefix(image, prefix string) bool {
+	if !strings.HasPrefix(image, prefix) {
+		return false
+	}
+
+	rest := image[len(prefix):]
+
+	return rest == "" || strings.HasPrefix(rest, "/") || strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "@")
+}
+
+func normalizePrefix(prefix string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(prefix), "/*"), "/")
+}
@@ -0,0 +1,95 @@
+package registrymirror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewrite(t *testing.T) {
+	dynakubeMirror := &Spec{
+		Rules: []Rule{
+			{From: "public.ecr.aws/dynatrace/*", To: "registry.corp/dt"},
+			{From: "public.ecr.aws/dynatrace/dynatrace-oneagent", To: "registry.corp/oneagent/dynatrace-oneagent"},
+		},
+	}
+	operatorMirror := &Spec{
+		Rules: []Rule{
+			{From: "public.ecr.aws", To: "mirror.corp/ecr"},
+			{From: "docker.io/", To: "mirror.corp/hub/"},
+		},
+	}
+
+	t.Run("prefix is replaced", func(t *testing.T) {
+		assert.Equal(t, "registry.corp/dt/dynatrace-codemodules:1.2.3", Rewrite("public.ecr.aws/dynatrace/dynatrace-codemodules:1.2.3", dynakubeMirror))
+	})
+
+	t.Run("longest prefix wins", func(t *testing.T) {
+		assert.Equal(t, "registry.corp/oneagent/dynatrace-oneagent:1.2.3@sha256:abc", Rewrite("public.ecr.aws/dynatrace/dynatrace-oneagent:1.2.3@sha256:abc", dynakubeMirror))
+	})
+
+	t.Run("first spec with a matching rule wins", func(t *testing.T) {
+		assert.Equal(t, "registry.corp/dt/dynatrace-k8s-node-config-collector:1.0.0", Rewrite("public.ecr.aws/dynatrace/dynatrace-k8s-node-config-collector:1.0.0", dynakubeMirror, operatorMirror))
+		assert.Equal(t, "mirror.corp/ecr/other/image:1.0.0", Rewrite("public.ecr.aws/other/image:1.0.0", dynakubeMirror, operatorMirror))
+	})
+
+	t.Run("only complete path segments match", func(t *testing.T) {
+		assert.Equal(t, "public.ecr.aws/dynatrace-fake/image:1.0.0", Rewrite("public.ecr.aws/dynatrace-fake/image:1.0.0", dynakubeMirror))
+	})
+
+	t.Run("no matching rule", func(t *testing.T) {
+		assert.Equal(t, "quay.io/dynatrace/image:1.0.0", Rewrite("quay.io/dynatrace/image:1.0.0", dynakubeMirror, nil, operatorMirror))
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		nested := &Spec{Rules: []Rule{{From: "registry.corp", To: "registry.corp/mirror"}}}
+
+		once := Rewrite("registry.corp/image:1.0.0", nested)
+		assert.Equal(t, "registry.corp/mirror/image:1.0.0", once)
+		assert.Equal(t, once, Rewrite(once, nested))
+	})
+}
+
+func TestRestore(t *testing.T) {
+	dynakubeMirror := &Spec{
+		Rules: []Rule{
+			{From: "public.ecr.aws/dynatrace/*", To: "registry.corp/dt"},
+			{From: "public.ecr.aws/dynatrace/dynatrace-oneagent", To: "registry.corp/oneagent/dynatrace-oneagent"},
+		},
+	}
+	operatorMirror := &Spec{Rules: []Rule{{From: "public.ecr.aws", To: "mirror.corp/ecr"}}}
+
+	t.Run("rewritten images are restored", func(t *testing.T) {
+		for _, image := range []string{
+			"public.ecr.aws/dynatrace/dynatrace-codemodules:1.2.3",
+			"public.ecr.aws/dynatrace/dynatrace-oneagent:1.2.3@sha256:abc",
+			"public.ecr.aws/other/image:1.0.0",
+		} {
+			restored, err := Restore(Rewrite(image, dynakubeMirror, operatorMirror), dynakubeMirror, operatorMirror)
+			require.NoError(t, err)
+			assert.Equal(t, image, restored)
+		}
+	})
+
+	t.Run("image of no mirror is kept", func(t *testing.T) {
+		restored, err := Restore("quay.io/dynatrace/image:1.0.0", dynakubeMirror, nil, operatorMirror)
+		require.NoError(t, err)
+		assert.Equal(t, "quay.io/dynatrace/image:1.0.0", restored)
+	})
+
+	t.Run("ambiguous mirror", func(t *testing.T) {
+		shared := &Spec{Rules: []Rule{{From: "public.ecr.aws/dynatrace", To: "registry.corp/dt"}, {From: "docker.io/dynatrace", To: "registry.corp/dt"}}}
+
+		_, err := Restore("registry.corp/dt/image:1.0.0", shared)
+		require.Error(t, err)
+	})
+}
+
+func TestPullSecrets(t *testing.T) {
+	first := &Spec{Rules: []Rule{{From: "a", To: "b", PullSecret: "mirror"}, {From: "c", To: "d"}}}
+	second := &Spec{Rules: []Rule{{From: "e", To: "f", PullSecret: "mirror"}, {From: "g", To: "h", PullSecret: "other"}}}
+
+	assert.Equal(t, []string{"mirror", "other"}, PullSecrets(first, nil, second))
+	assert.Empty(t, PullSecrets(nil))
+}
@@ -0,0 +1,30 @@
+package registrymirror
+
+// +kubebuilder:object:generate=true
+
+// Spec redirects image references to registry mirrors.
+type Spec struct {
+	// Rewrite rules for the image references, the rule with the longest matching prefix is applied.
+	// +kubebuilder:validation:MinItems=1
+	Rules []Rule `json:"rules"`
+}
+
+// +kubebuilder:object:generate=true
+
+type Rule struct {
+	// Prefix of the image references that are redirected, e.g. public.ecr.aws/dynatrace.
+	// The prefix only matches complete path segments, a trailing /* is allowed.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	From string `json:"from"`
+
+	// Replacement of the prefix, e.g. registry.corp/dt.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	To string `json:"to"`
+
+	// Name of a pull secret of type kubernetes.io/dockerconfigjson with the credentials for the mirror.
+	// Its credentials are added to the pull secret generated for the DynaKube.
+	// +kubebuilder:validation:Optional
+	PullSecret string `json:"pullSecret,omitempty"`
+}
@@ -0,0 +1,49 @@
+// +kubebuilder:object:generate=true
+// +k8s:openapi-gen=true
+package status
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type RolloutPhase string
+
+const (
+	// RolloutCanaryPhase means the candidate is only used by the canary, until it was healthy for the soak time.
+	RolloutCanaryPhase RolloutPhase = "Canary"
+	// RolloutVerifiedPhase means the canary passed the health gates, the candidate is promoted with the next version update.
+	RolloutVerifiedPhase RolloutPhase = "Verified"
+	// RolloutPromotedPhase means the candidate is used everywhere.
+	RolloutPromotedPhase RolloutPhase = "Promoted"
+	// RolloutPausedPhase means the canary failed the health gates, it keeps the candidate but it isn't promoted.
+	RolloutPausedPhase RolloutPhase = "Paused"
+	// RolloutRolledBackPhase means the canary failed the health gates and was removed, the candidate isn't used anymore.
+	RolloutRolledBackPhase RolloutPhase = "RolledBack"
+)
+
+type RolloutStatus struct {
+	// Indicates when the canary of the candidate was started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	// Phase of the rollout (Canary, Verified, Promoted, Paused, RolledBack)
+	Phase RolloutPhase `json:"phase,omitempty"`
+	// Details about the current phase, e.g. why the canary failed
+	Message string `json:"message,omitempty"`
+	// Version that is rolled out
+	Candidate VersionStatus `json:"candidate,omitempty"`
+	// Version that was used before the rollout
+	Previous VersionStatus `json:"previous,omitempty"`
+	// Nodes the canary is deployed to
+	CanaryNodes []string `json:"canaryNodes,omitempty"`
+}
+
+// IsActive returns true while the candidate is used by the canary.
+func (rollout *RolloutStatus) IsActive() bool {
+	if rollout == nil {
+		return false
+	}
+
+	switch rollout.Phase {
+	case RolloutCanaryPhase, RolloutVerifiedPhase, RolloutPausedPhase:
+		return true
+	default:
+		return false
+	}
+}
@@ -26,4 +26,17 @@ type VersionStatus struct {
 	Version string `json:"version,omitempty"`
 	// Image type
 	Type string `json:"type,omitempty"`
+	// Update that is held back until the next maintenance window
+	Pending *PendingUpdate `json:"pending,omitempty"`
+}
+
+type PendingUpdate struct {
+	// Indicates when the update was found
+	DetectedAt *metav1.Time `json:"detectedAt,omitempty"`
+	// Indicates when the next maintenance window starts
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// Image ID of the pending update
+	ImageID string `json:"imageID,omitempty"`
+	// Version of the pending update
+	Version string `json:"version,omitempty"`
 }
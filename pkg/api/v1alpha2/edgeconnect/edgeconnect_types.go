@@ -6,6 +6,7 @@ package edgeconnect
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/image"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/proxy"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha2"
@@ -42,6 +43,10 @@ type EdgeConnectSpec struct { //nolint:revive
 	// Enables automatic restarts of EdgeConnect pods in case a new version is available (the default value is: true)
 	AutoUpdate *bool `json:"autoUpdate"`
 
+	// Restricts automatic updates of the EdgeConnect pods to maintenance windows
+	// +kubebuilder:validation:Optional
+	UpdateSchedule *maintenance.Schedule `json:"updateSchedule,omitempty"`
+
 	// Overrides the default image
 	ImageRef image.Ref `json:"imageRef,omitempty"`
 
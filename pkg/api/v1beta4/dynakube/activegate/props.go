@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/dtversion"
 )
 
@@ -24,6 +25,11 @@ func (ag *Spec) SetName(name string) {
 	ag.name = name
 }
 
+// SetPinnedImage sets the image the ActiveGate is pinned to by a release lock.
+func (ag *Spec) SetPinnedImage(image string) {
+	ag.pinnedImage = image
+}
+
 func (ag *Spec) SetAutomaticTLSCertificate(enabled bool) {
 	ag.automaticTLSCertificateEnabled = enabled
 }
@@ -145,9 +151,9 @@ func (ag *Spec) GetDefaultImage(version string) string {
 	return apiUrlHost + DefaultImageRegistrySubPath + ":" + tag
 }
 
-// CustomActiveGateImage provides the image reference for the ActiveGate provided in the Spec.
+// CustomActiveGateImage provides the image reference for the ActiveGate provided in the Spec, or the one pinned by a release lock.
 func (ag *Spec) GetCustomImage() string {
-	return ag.Image
+	return releaselock.PinOr(ag.pinnedImage, ag.Image)
 }
 
 // GetTerminationGracePeriodSeconds provides the configured value for the terminatGracePeriodSeconds parameter of the pod.
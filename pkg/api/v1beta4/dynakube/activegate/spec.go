@@ -1,6 +1,7 @@
 package activegate
 
 import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -81,8 +82,9 @@ type Spec struct {
 	// +kubebuild:validation:Optional
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 
-	name   string
-	apiUrl string
+	name        string
+	apiUrl      string
+	pinnedImage string
 
 	// The name of a secret containing ActiveGate TLS cert+key and password. If not set, self-signed certificate is used.
 	// server.p12: certificate+key pair in pkcs12 format
@@ -102,6 +104,12 @@ type Spec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Priority Class name",order=23,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:io.kubernetes:PriorityClass"}
 	PriorityClassName string `json:"priorityClassName,omitempty"`
 
+	// Restricts automatic ActiveGate updates to maintenance windows.
+	// +kubebuilder:validation:Optional
+	// +nullable
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Update Schedule",order=41,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	UpdateSchedule *maintenance.Schedule `json:"updateSchedule,omitempty"`
+
 	CapabilityProperties `json:",inline"`
 
 	// Activegate capabilities enabled (routing, kubernetes-monitoring, metrics-ingest, dynatrace-api)
@@ -19,6 +19,7 @@ limitations under the License.
 package activegate
 
 import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
 	"k8s.io/api/core/v1"
 )
@@ -104,6 +105,11 @@ func (in *Spec) DeepCopyInto(out *Spec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.UpdateSchedule != nil {
+		in, out := &in.UpdateSchedule, &out.UpdateSchedule
+		*out = new(maintenance.Schedule)
+		(*in).DeepCopyInto(*out)
+	}
 	in.CapabilityProperties.DeepCopyInto(&out.CapabilityProperties)
 	if in.Capabilities != nil {
 		in, out := &in.Capabilities, &out.Capabilities
@@ -9,6 +9,7 @@ func (dk *DynaKube) ActiveGate() *activegate.ActiveGate {
 	dk.Spec.ActiveGate.SetName(dk.Name)
 	dk.Spec.ActiveGate.SetAutomaticTLSCertificate(dk.FF().IsActiveGateAutomaticTLSCertificate())
 	dk.Spec.ActiveGate.SetExtensionsDependency(dk.IsExtensionsEnabled())
+	dk.Spec.ActiveGate.SetPinnedImage(dk.ReleaseLock().ActiveGate)
 
 	return &activegate.ActiveGate{
 		Spec:   &dk.Spec.ActiveGate,
@@ -9,6 +9,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/kspm"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
 	"github.com/pkg/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,9 +32,15 @@ type DynaKubeStatus struct { //nolint:revive
 	// Observed state of Metadata-Enrichment
 	MetadataEnrichment MetadataEnrichmentStatus `json:"metadataEnrichment,omitempty"`
 
+	// Summary of the injection into the pods of the namespaces monitored by the DynaKube
+	InjectionCoverage *InjectionCoverageStatus `json:"injectionCoverage,omitempty"`
+
 	// Observed state of Kspm
 	Kspm kspm.Status `json:"kspm,omitempty"`
 
+	// Release lock the components are pinned to
+	ReleaseLock *releaselock.Status `json:"releaseLock,omitempty"`
+
 	// UpdatedTimestamp indicates when the instance was last updated
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Last Updated"
@@ -62,6 +69,17 @@ type DynaKubeStatus struct { //nolint:revive
 type DynatraceApiStatus struct {
 	// Time of the last token request
 	LastTokenScopeRequest metav1.Time `json:"lastTokenScopeRequest,omitempty"`
+
+	// Tokens verified by the last token request
+	Tokens []TokenStatus `json:"tokens,omitempty"`
+}
+
+type TokenStatus struct {
+	// ExpirationDate of the token, not set if the token doesn't expire
+	ExpirationDate *metav1.Time `json:"expirationDate,omitempty"`
+
+	// Type of the token, like apiToken, paasToken or dataIngestToken
+	Type string `json:"type"`
 }
 
 func GetCacheValidMessage(functionName string, lastRequestTimestamp metav1.Time, timeout time.Duration) string {
@@ -73,6 +91,39 @@ func GetCacheValidMessage(functionName string, lastRequestTimestamp metav1.Time,
 		int(remaining.Minutes()))
 }
 
+type InjectionCoverageStatus struct {
+	// Time the summary was last computed
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Coverage of every enabled injection
+	Injections []InjectionCoverage `json:"injections,omitempty"`
+
+	// Number of running pods in the namespaces monitored by the DynaKube
+	Pods int32 `json:"pods"`
+}
+
+type InjectionCoverage struct {
+	// Name of the injection, oneagent or metadata-enrichment
+	Name string `json:"name"`
+
+	// Reasons the pods were not injected, sorted by the number of pods
+	Reasons []InjectionReasonCount `json:"reasons,omitempty"`
+
+	// Number of pods that were injected
+	Injected int32 `json:"injected"`
+
+	// Number of pods that were not injected, their reasons are listed in Reasons
+	NotInjected int32 `json:"notInjected"`
+
+	// Number of pods without any information of the webhook, e.g. created before their namespace was monitored or while the webhook was unavailable
+	Unknown int32 `json:"unknown"`
+}
+
+type InjectionReasonCount struct {
+	Reason string `json:"reason"`
+	Pods   int32  `json:"pods"`
+}
+
 type EnrichmentRuleType string
 
 const (
@@ -83,14 +134,31 @@ const (
 const MetadataPrefix string = "metadata.dynatrace.com/"
 
 type MetadataEnrichmentStatus struct {
+	// The effective rules, the tenant rules merged with the rules of the DynaKube.
 	Rules []EnrichmentRule `json:"rules,omitempty"`
+
+	// The rules of the tenant settings.
+	TenantRules []EnrichmentRule `json:"tenantRules,omitempty"`
+
+	// The last time the rules of the tenant settings were queried.
+	TenantRulesQueriedAt *metav1.Time `json:"tenantRulesQueriedAt,omitempty"`
 }
 
 type EnrichmentRule struct {
-	Type    EnrichmentRuleType `json:"type,omitempty"`
-	Source  string             `json:"source,omitempty"`
-	Target  string             `json:"target,omitempty"`
-	Enabled bool               `json:"enabled,omitempty"`
+	Type    EnrichmentRuleType  `json:"type,omitempty"`
+	Scope   EnrichmentRuleScope `json:"scope,omitempty"`
+	Source  string              `json:"source,omitempty"`
+	Target  string              `json:"target,omitempty"`
+	Enabled bool                `json:"enabled,omitempty"`
+}
+
+// GetScope returns the scope of the rule, rules without a scope read from the namespace.
+func (rule EnrichmentRule) GetScope() EnrichmentRuleScope {
+	if rule.Scope == "" {
+		return EnrichmentNamespaceScope
+	}
+
+	return rule.Scope
 }
 
 func (rule EnrichmentRule) ToAnnotationKey() string {
@@ -4,13 +4,17 @@
 package dynakube
 
 import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/registrymirror"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/egress"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/imageverification"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/kspm"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/logmonitoring"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/telemetryingest"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -97,6 +101,25 @@ type DynaKubeSpec struct { //nolint:revive
 	// +kubebuilder:validation:Optional
 	TelemetryIngest *telemetryingest.Spec `json:"telemetryIngest,omitempty"`
 
+	// When an EgressSpec is provided, the operator maintains egress allow-lists for the Dynatrace communication hosts.
+	// +kubebuilder:validation:Optional
+	Egress *egress.Spec `json:"egress,omitempty"`
+
+	// When an ImageVerificationSpec is provided, the images deployed by the operator are verified before they are used,
+	// either by their cosign signatures or by the registry they are pulled from.
+	// +kubebuilder:validation:Optional
+	ImageVerification *imageverification.Spec `json:"imageVerification,omitempty"`
+
+	// Redirects the images used for this DynaKube to registry mirrors.
+	// Takes precedence over the registry mirror configured for the whole operator.
+	// +kubebuilder:validation:Optional
+	RegistryMirror *registrymirror.Spec `json:"registryMirror,omitempty"`
+
+	// Name of a ConfigMap in the namespace of the DynaKube holding a release lock, as exported by the release-lock command.
+	// All components listed in the release lock are pinned to its images, instead of resolving their versions on their own.
+	// +kubebuilder:validation:Optional
+	ReleaseLock string `json:"releaseLock,omitempty"`
+
 	// General configuration about OneAgent instances.
 	// You can't enable more than one module (classicFullStack, cloudNativeFullStack, hostMonitoring, or applicationMonitoring).
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OneAgent",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
@@ -114,6 +137,10 @@ type DynaKubeSpec struct { //nolint:revive
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tenant specific secrets",order=2,xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
 	Tokens string `json:"tokens,omitempty"`
 
+	// Reads the tokens used for connecting to Dynatrace from an external source instead of the tokens secret.
+	// +kubebuilder:validation:Optional
+	TokenSource *tokensource.Spec `json:"tokenSource,omitempty"`
+
 	// Adds custom RootCAs from a configmap. Put the certificate under certs within your configmap.
 	// Note: Applies to Dynatrace Operator, OneAgent and ActiveGate.
 	// +kubebuilder:validation:Optional
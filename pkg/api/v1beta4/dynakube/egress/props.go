@@ -0,0 +1,72 @@
+package egress
+
+func (e *Egress) SetName(name string) {
+	e.name = name
+}
+
+func (e *Egress) SetNamespace(namespace string) {
+	e.namespace = namespace
+}
+
+func (e *Egress) IsEnabled() bool {
+	return e.Spec != nil
+}
+
+func (e *Egress) GetBackend() Backend {
+	if !e.IsEnabled() {
+		return ""
+	}
+
+	return e.Backend
+}
+
+// SelectsInjectedPods returns true if the egress policies also apply to the pods the OneAgent was injected into.
+func (e *Egress) SelectsInjectedPods() bool {
+	switch e.GetBackend() {
+	case NetworkPolicyBackend, CiliumBackend:
+		return e.InjectedPods
+	default:
+		return false
+	}
+}
+
+// NeedsParentRef returns true for the backends attaching routes to a user managed parent object.
+func (e *Egress) NeedsParentRef() bool {
+	return e.GetParentKind() != ""
+}
+
+// GetParentKind returns the kind of the object the routes of the backend are attached to.
+func (e *Egress) GetParentKind() string {
+	switch e.GetBackend() {
+	case LinkerdBackend:
+		return "EgressNetwork"
+	case GatewayAPIBackend:
+		return "Gateway"
+	default:
+		return ""
+	}
+}
+
+// GetParentName returns the name of the EgressNetwork or Gateway the routes are attached to.
+func (e *Egress) GetParentName() string {
+	if !e.IsEnabled() || e.ParentRef == nil {
+		return ""
+	}
+
+	return e.ParentRef.Name
+}
+
+// GetParentNamespace returns the namespace of the EgressNetwork or Gateway the routes are attached to,
+// defaulting to the namespace of the DynaKube.
+func (e *Egress) GetParentNamespace() string {
+	if !e.IsEnabled() || e.ParentRef == nil || e.ParentRef.Namespace == "" {
+		return e.namespace
+	}
+
+	return e.ParentRef.Namespace
+}
+
+// GetName returns the name used for the objects rendered for the DynaKube.
+func (e *Egress) GetName() string {
+	return e.name + "-egress"
+}
@@ -0,0 +1,56 @@
+package egress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParentRef(t *testing.T) {
+	newEgress := func(spec *Spec) *Egress {
+		e := &Egress{Spec: spec}
+		e.SetName("dynakube")
+		e.SetNamespace("dynatrace")
+
+		return e
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		e := newEgress(nil)
+
+		assert.False(t, e.IsEnabled())
+		assert.False(t, e.NeedsParentRef())
+		assert.Empty(t, e.GetParentName())
+		assert.Equal(t, "dynatrace", e.GetParentNamespace())
+	})
+
+	t.Run("backends without parent", func(t *testing.T) {
+		assert.False(t, newEgress(&Spec{Backend: NetworkPolicyBackend}).NeedsParentRef())
+		assert.False(t, newEgress(&Spec{Backend: CiliumBackend}).NeedsParentRef())
+	})
+
+	t.Run("parent namespace defaults to the dynakube namespace", func(t *testing.T) {
+		e := newEgress(&Spec{Backend: GatewayAPIBackend, ParentRef: &ParentRef{Name: "egress-gateway"}})
+
+		assert.True(t, e.NeedsParentRef())
+		assert.Equal(t, "Gateway", e.GetParentKind())
+		assert.Equal(t, "egress-gateway", e.GetParentName())
+		assert.Equal(t, "dynatrace", e.GetParentNamespace())
+	})
+
+	t.Run("explicit parent namespace", func(t *testing.T) {
+		e := newEgress(&Spec{Backend: LinkerdBackend, ParentRef: &ParentRef{Name: "all-egress", Namespace: "linkerd-egress"}})
+
+		assert.Equal(t, "EgressNetwork", e.GetParentKind())
+		assert.Equal(t, "linkerd-egress", e.GetParentNamespace())
+		assert.Equal(t, "dynakube-egress", e.GetName())
+	})
+}
+
+func TestSelectsInjectedPods(t *testing.T) {
+	assert.False(t, (&Egress{}).SelectsInjectedPods())
+	assert.False(t, (&Egress{Spec: &Spec{Backend: NetworkPolicyBackend}}).SelectsInjectedPods())
+	assert.True(t, (&Egress{Spec: &Spec{Backend: NetworkPolicyBackend, InjectedPods: true}}).SelectsInjectedPods())
+	assert.True(t, (&Egress{Spec: &Spec{Backend: CiliumBackend, InjectedPods: true}}).SelectsInjectedPods())
+	assert.False(t, (&Egress{Spec: &Spec{Backend: LinkerdBackend, InjectedPods: true}}).SelectsInjectedPods())
+}
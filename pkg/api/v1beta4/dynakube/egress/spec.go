@@ -0,0 +1,57 @@
+package egress
+
+type Egress struct {
+	*Spec
+
+	name      string
+	namespace string
+}
+
+// +kubebuilder:validation:Enum=NetworkPolicy;Cilium;Linkerd;GatewayAPI
+type Backend string
+
+const (
+	// NetworkPolicyBackend renders Kubernetes NetworkPolicies, FQDN hosts are resolved to their current IP addresses.
+	NetworkPolicyBackend Backend = "NetworkPolicy"
+
+	// CiliumBackend renders CiliumNetworkPolicies using toFQDNs for FQDN hosts.
+	CiliumBackend Backend = "Cilium"
+
+	// LinkerdBackend renders TLSRoutes attached to a Linkerd EgressNetwork.
+	LinkerdBackend Backend = "Linkerd"
+
+	// GatewayAPIBackend renders TLSRoutes attached to an egress Gateway, with an ExternalName Service per FQDN host.
+	GatewayAPIBackend Backend = "GatewayAPI"
+)
+
+// +kubebuilder:object:generate=true
+
+type Spec struct {
+	// The EgressNetwork (Linkerd) or Gateway (GatewayAPI) the generated routes are attached to.
+	// Required for the Linkerd and GatewayAPI backends.
+	// +kubebuilder:validation:Optional
+	ParentRef *ParentRef `json:"parentRef,omitempty"`
+
+	// The network layer the egress allow-lists for the Dynatrace communication hosts are rendered for.
+	// The NetworkPolicy and Cilium backends select the Dynatrace pods of the DynaKube namespace, DNS, the kube-apiserver and in-cluster destinations stay allowed.
+	// The NetworkPolicy backend allows the IP addresses the hosts resolve to, which are refreshed every 5 minutes.
+	// +kubebuilder:validation:Required
+	Backend Backend `json:"backend"`
+
+	// Also select the pods the OneAgent was injected into in the monitored namespaces, only used by the NetworkPolicy and Cilium backends.
+	// All other egress of these pods, except DNS, the kube-apiserver and in-cluster destinations, is blocked then.
+	// +kubebuilder:validation:Optional
+	InjectedPods bool `json:"injectedPods,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type ParentRef struct {
+	// Name of the parent object.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace of the parent object, defaults to the namespace of the DynaKube.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+}
@@ -0,0 +1,15 @@
+package dynakube
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/egress"
+)
+
+func (dk *DynaKube) Egress() *egress.Egress {
+	e := &egress.Egress{
+		Spec: dk.Spec.Egress,
+	}
+	e.SetName(dk.Name)
+	e.SetNamespace(dk.Namespace)
+
+	return e
+}
@@ -0,0 +1,34 @@
+package imageverification
+
+func (iv *ImageVerification) SetName(name string) {
+	iv.name = name
+}
+
+func (iv *ImageVerification) SetNamespace(namespace string) {
+	iv.namespace = namespace
+}
+
+func (iv *ImageVerification) IsEnabled() bool {
+	return iv.Spec != nil
+}
+
+// RequiresSignature returns true if images have to be signed, not only be pulled from a trusted registry.
+func (iv *ImageVerification) RequiresSignature() bool {
+	return iv.IsEnabled() && (iv.PublicKeysSecret != "" || len(iv.Keyless) > 0)
+}
+
+func (iv *ImageVerification) GetPublicKeysSecret() string {
+	if !iv.IsEnabled() {
+		return ""
+	}
+
+	return iv.PublicKeysSecret
+}
+
+func (iv *ImageVerification) GetTrustedRoot() string {
+	if !iv.IsEnabled() {
+		return ""
+	}
+
+	return iv.TrustedRoot
+}
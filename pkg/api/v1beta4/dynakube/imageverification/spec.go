@@ -0,0 +1,56 @@
+package imageverification
+
+type ImageVerification struct {
+	*Spec
+
+	name      string
+	namespace string
+}
+
+const (
+	// FulcioRootsKey is the key of the PEM encoded Fulcio CA certificates in the trusted root configmap.
+	FulcioRootsKey = "fulcio.pem"
+
+	// RekorPublicKeyKey is the key of the PEM encoded Rekor public key in the trusted root configmap.
+	RekorPublicKeyKey = "rekor.pub"
+)
+
+// +kubebuilder:object:generate=true
+
+type Spec struct {
+	// Name of a secret in the namespace of the DynaKube, every entry of it is a PEM encoded cosign public key.
+	// An image is accepted if it was signed with any of these keys.
+	// +kubebuilder:validation:Optional
+	PublicKeysSecret string `json:"publicKeysSecret,omitempty"`
+
+	// Identities of keyless (Fulcio certificate based) signatures that are accepted.
+	// An image is accepted if it was signed by any of these identities.
+	// +kubebuilder:validation:Optional
+	Keyless []KeylessIdentity `json:"keyless,omitempty"`
+
+	// Name of a configmap in the namespace of the DynaKube holding the Sigstore trusted root for keyless signatures.
+	// The Fulcio CA certificates go under fulcio.pem, the Rekor public key under rekor.pub.
+	// +kubebuilder:validation:Optional
+	TrustedRoot string `json:"trustedRoot,omitempty"`
+
+	// Registries (optionally including a repository path prefix) the images are allowed to be pulled from, e.g. public.ecr.aws/dynatrace.
+	// If empty, images from any registry are allowed.
+	// +kubebuilder:validation:Optional
+	TrustedRegistries []string `json:"trustedRegistries,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type KeylessIdentity struct {
+	// The OIDC issuer of the signing identity, e.g. https://token.actions.githubusercontent.com.
+	// +kubebuilder:validation:Required
+	Issuer string `json:"issuer"`
+
+	// The exact subject (email or URI) of the signing identity.
+	// +kubebuilder:validation:Optional
+	Subject string `json:"subject,omitempty"`
+
+	// A regular expression the subject (email or URI) of the signing identity has to match completely.
+	// +kubebuilder:validation:Optional
+	SubjectRegExp string `json:"subjectRegExp,omitempty"`
+}
@@ -0,0 +1,15 @@
+package dynakube
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/imageverification"
+)
+
+func (dk *DynaKube) ImageVerification() *imageverification.ImageVerification {
+	iv := &imageverification.ImageVerification{
+		Spec: dk.Spec.ImageVerification,
+	}
+	iv.SetName(dk.Name)
+	iv.SetNamespace(dk.Namespace)
+
+	return iv
+}
@@ -10,4 +10,41 @@ type MetadataEnrichment struct {
 	// The namespaces where you want Dynatrace Operator to inject enrichment.
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace Selector",xDescriptors="urn:alm:descriptor:com.tectonic.ui:selector:core:v1:Namespace"
 	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Enrichment rules in addition to the ones configured in the Dynatrace tenant settings.
+	// A rule replaces the tenant rule with the same target.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rules",xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Rules []MetadataEnrichmentRule `json:"rules,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Namespace;Pod;Workload;Node
+type EnrichmentRuleScope string
+
+const (
+	EnrichmentNamespaceScope EnrichmentRuleScope = "Namespace"
+	EnrichmentPodScope       EnrichmentRuleScope = "Pod"
+	EnrichmentWorkloadScope  EnrichmentRuleScope = "Workload"
+	EnrichmentNodeScope      EnrichmentRuleScope = "Node"
+)
+
+type MetadataEnrichmentRule struct {
+	// Whether a label or an annotation is read.
+	// +kubebuilder:validation:Enum=LABEL;ANNOTATION
+	// +kubebuilder:validation:Required
+	Type EnrichmentRuleType `json:"type"`
+
+	// The object the label or annotation is read from, `Namespace` by default.
+	// Node rules read from the node of the pod, if it's already known at admission (e.g. for DaemonSet pods),
+	// otherwise the labels are read from the nodeSelector of the pod.
+	// +kubebuilder:validation:Optional
+	Scope EnrichmentRuleScope `json:"scope,omitempty"`
+
+	// The key of the label or annotation.
+	// +kubebuilder:validation:Required
+	Source string `json:"source"`
+
+	// The name of the attribute, the value is added to the pod as `metadata.dynatrace.com/<target>` annotation.
+	// +kubebuilder:validation:Required
+	Target string `json:"target"`
 }
@@ -3,12 +3,16 @@ package oneagent
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/dtversion"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -17,6 +21,11 @@ const (
 	PodNameOsAgent                        = "oneagent"
 	DefaultOneAgentImageRegistrySubPath   = "/linux/oneagent"
 	storageVolumeDefaultHostPath          = "/var/opt/dynatrace"
+	CanaryDaemonSetSuffix                 = "-canary"
+
+	defaultRolloutSoakTime    = time.Hour
+	defaultRolloutMaxRestarts = int32(3)
+	defaultRolloutTimeout     = 30 * time.Minute
 )
 
 func NewOneAgent(spec *Spec, status *Status, codeModulesStatus *CodeModulesStatus, //nolint:revive
@@ -36,6 +45,11 @@ func NewOneAgent(spec *Spec, status *Status, codeModulesStatus *CodeModulesStatu
 	}
 }
 
+// SetReleaseLock sets the images the OneAgent and the code modules are pinned to.
+func (oa *OneAgent) SetReleaseLock(images releaselock.Images) {
+	oa.releaseLock = images
+}
+
 func (oa *OneAgent) IsCSIAvailable() bool {
 	return installconfig.GetModules().CSIDriver
 }
@@ -186,15 +200,15 @@ func (oa *OneAgent) GetCustomVersion() string {
 	return ""
 }
 
-// GetCustomImage provides the image reference for the OneAgent provided in the Spec.
+// GetCustomImage provides the image reference for the OneAgent provided in the Spec, or the one pinned by a release lock.
 func (oa *OneAgent) GetCustomImage() string {
 	switch {
 	case oa.IsClassicFullStackMode():
-		return oa.ClassicFullStack.Image
+		return releaselock.PinOr(oa.releaseLock.OneAgent, oa.ClassicFullStack.Image)
 	case oa.IsHostMonitoringMode():
-		return oa.HostMonitoring.Image
+		return releaselock.PinOr(oa.releaseLock.OneAgent, oa.HostMonitoring.Image)
 	case oa.IsCloudNativeFullstackMode():
-		return oa.CloudNativeFullStack.Image
+		return releaselock.PinOr(oa.releaseLock.OneAgent, oa.CloudNativeFullStack.Image)
 	}
 
 	return ""
@@ -284,20 +298,42 @@ func (oa *OneAgent) GetEndpoints() string {
 	return oa.ConnectionInfoStatus.Endpoints
 }
 
-// GetCustomCodeModulesImage provides the image reference for the CodeModules provided in the Spec.
+// GetCustomCodeModulesImage provides the image reference for the CodeModules provided in the Spec, or the one pinned by a release lock.
 func (oa *OneAgent) GetCustomCodeModulesImage() string {
 	if oa.IsCloudNativeFullstackMode() {
-		return oa.CloudNativeFullStack.CodeModulesImage
+		return releaselock.PinOr(oa.releaseLock.CodeModules, oa.CloudNativeFullStack.CodeModulesImage)
 	} else if oa.IsApplicationMonitoringMode() && (oa.IsCSIAvailable() || oa.featureBootstrapperInjection) {
-		return oa.ApplicationMonitoring.CodeModulesImage
+		return releaselock.PinOr(oa.releaseLock.CodeModules, oa.ApplicationMonitoring.CodeModulesImage)
 	}
 
 	return ""
 }
 
-// GetCustomCodeModulesVersion provides the version for the CodeModules provided in the Spec.
+// GetCodeModulesBundle provides the pre-staged CodeModules bundle set in the Spec, which is only usable together with the CSI driver.
+func (oa *OneAgent) GetCodeModulesBundle() *CodeModulesBundleSpec {
+	if oa.IsCloudNativeFullstackMode() {
+		return oa.CloudNativeFullStack.CodeModulesBundle
+	} else if oa.IsApplicationMonitoringMode() && oa.IsCSIAvailable() {
+		return oa.ApplicationMonitoring.CodeModulesBundle
+	}
+
+	return nil
+}
+
+// GetCodeModulesVerification provides the verification of downloaded CodeModules set in the Spec, which is only done by the CSI driver.
+func (oa *OneAgent) GetCodeModulesVerification() *CodeModulesVerificationSpec {
+	if oa.IsCloudNativeFullstackMode() {
+		return oa.CloudNativeFullStack.CodeModulesVerification
+	} else if oa.IsApplicationMonitoringMode() && oa.IsCSIAvailable() {
+		return oa.ApplicationMonitoring.CodeModulesVerification
+	}
+
+	return nil
+}
+
+// GetCustomCodeModulesVersion provides the version for the CodeModules provided in the Spec, or the one pinned by a release lock.
 func (oa *OneAgent) GetCustomCodeModulesVersion() string {
-	return oa.GetCustomVersion()
+	return releaselock.PinOr(oa.releaseLock.CodeModulesVersion, oa.GetCustomVersion())
 }
 
 // GetCodeModulesVersion provides version set in Status for the CodeModules.
@@ -348,3 +384,100 @@ func (oa *OneAgent) GetHostPath() string {
 
 	return ""
 }
+
+// IsOneAgentRolloutEnabled returns true if new OneAgent versions are deployed to canary nodes first.
+func (oa *OneAgent) IsOneAgentRolloutEnabled() bool {
+	return oa.Spec.Rollout != nil && (len(oa.Spec.Rollout.NodeSelector) > 0 || oa.Spec.Rollout.Percentage != nil)
+}
+
+// IsCodeModulesRolloutEnabled returns true if new CodeModules versions are injected into the canary namespaces first.
+func (oa *OneAgent) IsCodeModulesRolloutEnabled() bool {
+	return oa.Spec.Rollout != nil && oa.Spec.Rollout.NamespaceSelector != nil
+}
+
+func (oa *OneAgent) GetRolloutSoakTime() time.Duration {
+	if oa.Spec.Rollout == nil || oa.Spec.Rollout.SoakTime == nil {
+		return defaultRolloutSoakTime
+	}
+
+	return oa.Spec.Rollout.SoakTime.Duration
+}
+
+func (oa *OneAgent) GetRolloutTimeout() time.Duration {
+	if oa.Spec.Rollout == nil || oa.Spec.Rollout.Timeout == nil {
+		return defaultRolloutTimeout
+	}
+
+	return oa.Spec.Rollout.Timeout.Duration
+}
+
+func (oa *OneAgent) GetRolloutMaxRestarts() int32 {
+	if oa.Spec.Rollout == nil || oa.Spec.Rollout.MaxRestarts == nil {
+		return defaultRolloutMaxRestarts
+	}
+
+	return *oa.Spec.Rollout.MaxRestarts
+}
+
+func (oa *OneAgent) GetRolloutFailurePolicy() RolloutFailurePolicy {
+	if oa.Spec.Rollout == nil || oa.Spec.Rollout.FailurePolicy == "" {
+		return RolloutPauseFailurePolicy
+	}
+
+	return oa.Spec.Rollout.FailurePolicy
+}
+
+// GetCanaryDaemonsetName provides the name of the DaemonSet, that runs the new OneAgent version on the canary nodes.
+func (oa *OneAgent) GetCanaryDaemonsetName() string {
+	return oa.GetDaemonsetName() + CanaryDaemonSetSuffix
+}
+
+// IsCodeModulesCanaryNamespace returns true if the pods of the namespace get the candidate of the CodeModules rollout.
+func (oa *OneAgent) IsCodeModulesCanaryNamespace(namespace corev1.Namespace) bool {
+	if oa.GetActiveCodeModulesRollout() == nil {
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(oa.Spec.Rollout.NamespaceSelector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(namespace.Labels))
+}
+
+// GetCodeModulesVersionForNamespace provides the CodeModules version for the pods of the namespace, which is the candidate of the rollout for canary namespaces.
+func (oa *OneAgent) GetCodeModulesVersionForNamespace(namespace corev1.Namespace) string {
+	if oa.IsCodeModulesCanaryNamespace(namespace) {
+		return oa.CodeModulesStatus.Rollout.Candidate.Version
+	}
+
+	return oa.GetCodeModulesVersion()
+}
+
+// GetCodeModulesImageForNamespace provides the CodeModules image for the pods of the namespace, which is the candidate of the rollout for canary namespaces.
+func (oa *OneAgent) GetCodeModulesImageForNamespace(namespace corev1.Namespace) string {
+	if oa.IsCodeModulesCanaryNamespace(namespace) {
+		return oa.CodeModulesStatus.Rollout.Candidate.ImageID
+	}
+
+	return oa.GetCodeModulesImage()
+}
+
+// GetActiveCodeModulesRollout returns the rollout of the CodeModules, if its canary is running.
+func (oa *OneAgent) GetActiveCodeModulesRollout() *status.RolloutStatus {
+	if !oa.IsCodeModulesRolloutEnabled() || !oa.CodeModulesStatus.Rollout.IsActive() {
+		return nil
+	}
+
+	return oa.CodeModulesStatus.Rollout
+}
+
+// GetActiveRollout returns the rollout of the OneAgent, if its canary is running.
+func (oa *OneAgent) GetActiveRollout() *status.RolloutStatus {
+	if !oa.Status.Rollout.IsActive() {
+		return nil
+	}
+
+	return oa.Status.Rollout
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const testAPIURL = "http://test-endpoint/api"
@@ -125,6 +126,43 @@ func TestCodeModulesVersion(t *testing.T) {
 	})
 }
 
+func TestCodeModulesVersionForNamespace(t *testing.T) {
+	spec := &Spec{
+		Rollout: &RolloutSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+		},
+	}
+	codeModulesStatus := &CodeModulesStatus{
+		VersionStatus: status.VersionStatus{Version: "1.2.3", ImageID: "repo@sha256:123"},
+		Rollout: &status.RolloutStatus{
+			Phase:     status.RolloutCanaryPhase,
+			Candidate: status.VersionStatus{Version: "1.2.4", ImageID: "repo@sha256:124"},
+		},
+	}
+	canaryNamespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"canary": "true"}}}
+
+	t.Run("canary namespace uses candidate", func(t *testing.T) {
+		oneAgent := NewOneAgent(spec, &Status{}, codeModulesStatus, "", "", false, false, false)
+
+		assert.Equal(t, "1.2.4", oneAgent.GetCodeModulesVersionForNamespace(canaryNamespace))
+		assert.Equal(t, "repo@sha256:124", oneAgent.GetCodeModulesImageForNamespace(canaryNamespace))
+	})
+	t.Run("other namespace uses status", func(t *testing.T) {
+		oneAgent := NewOneAgent(spec, &Status{}, codeModulesStatus, "", "", false, false, false)
+
+		assert.Equal(t, "1.2.3", oneAgent.GetCodeModulesVersionForNamespace(corev1.Namespace{}))
+		assert.Equal(t, "repo@sha256:123", oneAgent.GetCodeModulesImageForNamespace(corev1.Namespace{}))
+	})
+	t.Run("canary namespace uses status after promotion", func(t *testing.T) {
+		promoted := codeModulesStatus.DeepCopy()
+		promoted.Rollout.Phase = status.RolloutPromotedPhase
+		oneAgent := NewOneAgent(spec, &Status{}, promoted, "", "", false, false, false)
+
+		assert.Equal(t, "1.2.3", oneAgent.GetCodeModulesVersionForNamespace(canaryNamespace))
+		assert.Equal(t, "repo@sha256:123", oneAgent.GetCodeModulesImageForNamespace(canaryNamespace))
+	})
+}
+
 func TestGetOneAgentEnvironment(t *testing.T) {
 	t.Run("get environment from classicFullstack", func(t *testing.T) {
 		oneAgent := OneAgent{
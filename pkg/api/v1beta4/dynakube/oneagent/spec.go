@@ -1,7 +1,9 @@
 package oneagent
 
 import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -14,6 +16,8 @@ type OneAgent struct {
 	name       string
 	apiUrlHost string
 
+	releaseLock releaselock.Images
+
 	featureOneAgentPrivileged        bool
 	featureBootstrapperInjection     bool
 	featureOneAgentSkipLivenessProbe bool
@@ -48,6 +52,69 @@ type Spec struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Host Group",order=5,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
 	HostGroup string `json:"hostGroup,omitempty"`
+
+	// Roll out new OneAgent and CodeModule versions to a canary first, they are only used everywhere once the canary was healthy for the soak time.
+	// +kubebuilder:validation:Optional
+	// +nullable
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rollout",order=6,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+
+	// Restricts automatic OneAgent and CodeModule updates to maintenance windows.
+	// +kubebuilder:validation:Optional
+	// +nullable
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Update Schedule",order=7,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	UpdateSchedule *maintenance.Schedule `json:"updateSchedule,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Pause;Rollback
+type RolloutFailurePolicy string
+
+const (
+	RolloutPauseFailurePolicy    RolloutFailurePolicy = "Pause"
+	RolloutRollbackFailurePolicy RolloutFailurePolicy = "Rollback"
+)
+
+// +kubebuilder:object:generate=true
+type RolloutSpec struct {
+	// The nodes the new OneAgent version is deployed to first.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Node Selector",order=1,xDescriptors="urn:alm:descriptor:com.tectonic.ui:selector:Node"
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// The percentage of the nodes (matching the nodeSelector, if set) the new OneAgent version is deployed to first.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Percentage",order=2,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	Percentage *int32 `json:"percentage,omitempty"`
+
+	// The namespaces whose pods get the new CodeModules version first.
+	// +kubebuilder:validation:Optional
+	// +nullable
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace Selector",order=3,xDescriptors="urn:alm:descriptor:com.tectonic.ui:selector:core:v1:Namespace"
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// How long the canary has to be healthy, before the new version is used everywhere. Defaults to 1h.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Soak Time",order=4,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	SoakTime *metav1.Duration `json:"soakTime,omitempty"`
+
+	// How long the canary may take to become ready, before it is considered failed. Defaults to 30m.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Timeout",order=5,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// The maximum number of container restarts of the canary pods, before the canary is considered failed. Defaults to 3.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Restarts",order=6,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	MaxRestarts *int32 `json:"maxRestarts,omitempty"`
+
+	// What happens if the canary fails: `Pause` keeps the canary on the new version for investigation, `Rollback` removes it. Defaults to `Pause`.
+	// In both cases the previous version stays in use everywhere else, until a newer version is available.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Failure Policy",order=7,xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Pause,urn:alm:descriptor:com.tectonic.ui:select:Rollback"
+	FailurePolicy RolloutFailurePolicy `json:"failurePolicy,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
@@ -159,13 +226,66 @@ type AppInjectionSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CodeModulesImage",order=12,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced","urn:alm:descriptor:com.tectonic.ui:text"}
 	CodeModulesImage string `json:"codeModulesImage,omitempty"`
 
+	// Use a pre-staged bundle, available on the nodes, to provide the OneAgent CodeModule binaries, instead of downloading them.
+	// Requires the CSI driver. The operator doesn't access the Dynatrace API with a bundle, so it can't be combined with components that need it,
+	// like host OneAgents, ActiveGate, log monitoring, extensions, KSPM or telemetry ingest.
+	// +kubebuilder:validation:Optional
+	// +nullable
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CodeModulesBundle",order=13,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	CodeModulesBundle *CodeModulesBundleSpec `json:"codeModulesBundle,omitempty"`
+
+	// Verify the OneAgent CodeModule archives downloaded from the Dynatrace cluster, before they are unpacked on the nodes.
+	// Requires the CSI driver, archives that fail the verification are quarantined and never used.
+	// +kubebuilder:validation:Optional
+	// +nullable
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CodeModulesVerification",order=14,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	CodeModulesVerification *CodeModulesVerificationSpec `json:"codeModulesVerification,omitempty"`
+
 	// Applicable only for applicationMonitoring or cloudNativeFullStack configuration types. The namespaces where you want Dynatrace Operator to inject.
 	// For more information, see Configure monitoring for namespaces and pods (https://www.dynatrace.com/support/help/setup-and-configuration/setup-on-container-platforms/kubernetes/get-started-with-kubernetes-monitoring/dto-config-options-k8s#annotate).
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace Selector",order=17,xDescriptors="urn:alm:descriptor:com.tectonic.ui:selector:core:v1:Namespace"
 	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 }
 
+// +kubebuilder:object:generate=true
+type CodeModulesBundleSpec struct {
+	// Path of the bundle as seen by the CSI driver, either an OCI image layout directory or a (gzipped) tarball of one.
+	// The bundle has to contain a signed manifest.json, providing the version, connection info and process module config of the CodeModules.
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Path",order=1,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	Path string `json:"path"`
+
+	// Name of the ConfigMap that holds the PEM encoded public keys, which are used to verify the signature of the bundle manifest.
+	// The ConfigMap has to be in the same namespace as the DynaKube, the keys are read from the `keys` field.
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trusted Keys",order=2,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	TrustedKeys string `json:"trustedKeys"`
+
+	// Name of a Secret, in the namespace of the DynaKube, with the `tenant-uuid`, `communication-endpoints` and optionally the `tenant-token` of the tenant.
+	// If set, the operator uses it as the OneAgent connection info, instead of the connection info the CSI driver publishes from the bundle.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Info Secret",order=3,xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
+	ConnectionInfoSecret string `json:"connectionInfoSecret,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+type CodeModulesVerificationSpec struct {
+	// Name of the ConfigMap, in the namespace of the DynaKube, with the hex SHA-256 digest of each version in `<version>.sha256`.
+	// Optionally `<version>.sig` holds a base64 detached signature or `<version>.bundle` a cosign bundle, verified against the PEM public keys in `keys`.
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ConfigMap",order=1,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	ConfigMap string `json:"configMap"`
+
+	// Fail the verification if there is no signature for a version, instead of only checking its digest.
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Require Signature",order=2,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	RequireSignature bool `json:"requireSignature,omitempty"`
+}
+
 // +kubebuilder:object:generate=true
 type CodeModulesStatus struct {
 	status.VersionStatus `json:",inline"`
+
+	// Rollout of a new CodeModules version to the canary namespaces
+	Rollout *status.RolloutStatus `json:"rollout,omitempty"`
 }
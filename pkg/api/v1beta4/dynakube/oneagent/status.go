@@ -25,6 +25,9 @@ type Status struct {
 
 	// Information about OneAgent's connections
 	ConnectionInfoStatus ConnectionInfoStatus `json:"connectionInfoStatus,omitempty"`
+
+	// Rollout of a new OneAgent version to the canary nodes
+	Rollout *status.RolloutStatus `json:"rollout,omitempty"`
 }
 
 // +kubebuilder:object:generate=true
@@ -19,8 +19,11 @@ limitations under the License.
 package oneagent
 
 import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	pkgv1 "github.com/google/go-containerregistry/pkg/v1"
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -28,9 +31,19 @@ func (in *AppInjectionSpec) DeepCopyInto(out *AppInjectionSpec) {
 	*out = *in
 	if in.InitResources != nil {
 		in, out := &in.InitResources, &out.InitResources
-		*out = new(v1.ResourceRequirements)
+		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CodeModulesBundle != nil {
+		in, out := &in.CodeModulesBundle, &out.CodeModulesBundle
+		*out = new(CodeModulesBundleSpec)
+		**out = **in
+	}
+	if in.CodeModulesVerification != nil {
+		in, out := &in.CodeModulesVerification, &out.CodeModulesVerification
+		*out = new(CodeModulesVerificationSpec)
+		**out = **in
+	}
 	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
 }
 
@@ -77,10 +90,30 @@ func (in *CloudNativeFullStackSpec) DeepCopy() *CloudNativeFullStackSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CodeModulesBundleSpec) DeepCopyInto(out *CodeModulesBundleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeModulesBundleSpec.
+func (in *CodeModulesBundleSpec) DeepCopy() *CodeModulesBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeModulesBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CodeModulesStatus) DeepCopyInto(out *CodeModulesStatus) {
 	*out = *in
 	in.VersionStatus.DeepCopyInto(&out.VersionStatus)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(status.RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeModulesStatus.
@@ -93,6 +126,21 @@ func (in *CodeModulesStatus) DeepCopy() *CodeModulesStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CodeModulesVerificationSpec) DeepCopyInto(out *CodeModulesVerificationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeModulesVerificationSpec.
+func (in *CodeModulesVerificationSpec) DeepCopy() *CodeModulesVerificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeModulesVerificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommunicationHostStatus) DeepCopyInto(out *CommunicationHostStatus) {
 	*out = *in
@@ -161,14 +209,14 @@ func (in *HostInjectSpec) DeepCopyInto(out *HostInjectSpec) {
 	in.OneAgentResources.DeepCopyInto(&out.OneAgentResources)
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -205,6 +253,53 @@ func (in *Instance) DeepCopy() *Instance {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SoakTime != nil {
+		in, out := &in.SoakTime, &out.SoakTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxRestarts != nil {
+		in, out := &in.MaxRestarts, &out.MaxRestarts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutSpec.
+func (in *RolloutSpec) DeepCopy() *RolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Spec) DeepCopyInto(out *Spec) {
 	*out = *in
@@ -228,6 +323,16 @@ func (in *Spec) DeepCopyInto(out *Spec) {
 		*out = new(HostInjectSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateSchedule != nil {
+		in, out := &in.UpdateSchedule, &out.UpdateSchedule
+		*out = new(maintenance.Schedule)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Spec.
@@ -261,6 +366,11 @@ func (in *Status) DeepCopyInto(out *Status) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.ConnectionInfoStatus.DeepCopyInto(&out.ConnectionInfoStatus)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(status.RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
@@ -15,6 +15,7 @@ func (dk *DynaKube) OneAgent() *oneagent.OneAgent {
 		dk.FF().SkipOneAgentLivenessProbe(),
 		dk.FF().IsNodeImagePull(),
 	)
+	oa.SetReleaseLock(dk.ReleaseLock())
 
 	return oa
 }
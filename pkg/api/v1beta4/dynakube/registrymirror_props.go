@@ -0,0 +1,29 @@
+package dynakube
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/registrymirror"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+)
+
+// RewriteImage redirects the image reference to the registry mirror of the DynaKube, or the one configured for the whole operator.
+func (dk *DynaKube) RewriteImage(image string) string {
+	if image == "" {
+		return ""
+	}
+
+	return registrymirror.Rewrite(image, dk.Spec.RegistryMirror, installconfig.GetRegistryMirror())
+}
+
+// RestoreImage returns the image reference before it was redirected to a registry mirror by RewriteImage.
+func (dk *DynaKube) RestoreImage(image string) (string, error) {
+	if image == "" {
+		return "", nil
+	}
+
+	return registrymirror.Restore(image, dk.Spec.RegistryMirror, installconfig.GetRegistryMirror())
+}
+
+// RegistryMirrorPullSecrets returns the names of the pull secrets holding the credentials for the registry mirrors.
+func (dk *DynaKube) RegistryMirrorPullSecrets() []string {
+	return registrymirror.PullSecrets(dk.Spec.RegistryMirror, installconfig.GetRegistryMirror())
+}
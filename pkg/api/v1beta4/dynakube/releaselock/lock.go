@@ -0,0 +1,50 @@
+package releaselock
+
+// +kubebuilder:object:generate=true
+
+// Images are the image references a release lock pins the components of a DynaKube to.
+// Components without an image are not pinned, code modules downloaded from the tenant are pinned by their version instead.
+type Images struct {
+	// +kubebuilder:validation:Optional
+	OneAgent string `json:"oneAgent,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	CodeModules string `json:"codeModules,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	CodeModulesVersion string `json:"codeModulesVersion,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ActiveGate string `json:"activeGate,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ExtensionExecutionController string `json:"extensionExecutionController,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	OpenTelemetryCollector string `json:"openTelemetryCollector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	LogMonitoring string `json:"logMonitoring,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	KSPM string `json:"kspm,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type Status struct {
+	// Name of the ConfigMap the release lock was read from.
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// Images the components are pinned to.
+	Images Images `json:"images,omitempty"`
+}
+
+// PinOr returns the pinned image, or the given image if the component is not pinned.
+func PinOr(pinned, image string) string {
+	if pinned != "" {
+		return pinned
+	}
+
+	return image
+}
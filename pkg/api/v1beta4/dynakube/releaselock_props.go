@@ -0,0 +1,15 @@
+package dynakube
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
+)
+
+// ReleaseLock returns the images the components are pinned to by the release lock of the DynaKube.
+// If no release lock is referenced, or it was not read yet, no component is pinned.
+func (dk *DynaKube) ReleaseLock() releaselock.Images {
+	if dk.Spec.ReleaseLock == "" || dk.Status.ReleaseLock == nil || dk.Status.ReleaseLock.ConfigMap != dk.Spec.ReleaseLock {
+		return releaselock.Images{}
+	}
+
+	return dk.Status.ReleaseLock.Images
+}
@@ -1,6 +1,11 @@
 package telemetryingest
 
-import "github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
+import (
+	"encoding/json"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
+	"github.com/pkg/errors"
+)
 
 const (
 	ServiceNameSuffix = "-telemetry-ingest"
@@ -23,6 +28,80 @@ func (spec *Spec) GetProtocols() otelcgen.Protocols {
 	return protocols
 }
 
+// GetPipelineOptions translates the pipeline customizations into otelcgen options, they are validated when the config is generated.
+func (spec *Spec) GetPipelineOptions() ([]otelcgen.Option, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	processors, err := toCustomComponents(spec.Processors)
+	if err != nil {
+		return nil, err
+	}
+
+	exporters, err := toCustomComponents(spec.Exporters)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []otelcgen.Option{
+		otelcgen.WithCustomProcessors(processors...),
+		otelcgen.WithCustomExporters(exporters...),
+	}
+
+	if spec.Batch != nil {
+		options = append(options, otelcgen.WithBatchOverrides(otelcgen.BatchOverrides{
+			Timeout:          spec.Batch.Timeout,
+			SendBatchSize:    toUint32(spec.Batch.SendBatchSize),
+			SendBatchMaxSize: toUint32(spec.Batch.SendBatchMaxSize),
+		}))
+	}
+
+	if spec.MemoryLimiter != nil {
+		options = append(options, otelcgen.WithMemoryLimiterOverrides(otelcgen.MemoryLimiterOverrides{
+			CheckInterval:        spec.MemoryLimiter.CheckInterval,
+			LimitPercentage:      toUint32(spec.MemoryLimiter.LimitPercentage),
+			SpikeLimitPercentage: toUint32(spec.MemoryLimiter.SpikeLimitPercentage),
+		}))
+	}
+
+	return options, nil
+}
+
+func toCustomComponents(components []ComponentSpec) ([]otelcgen.CustomComponent, error) {
+	customComponents := make([]otelcgen.CustomComponent, 0, len(components))
+
+	for _, component := range components {
+		customComponent := otelcgen.CustomComponent{
+			ID: component.ID,
+		}
+
+		for _, pipeline := range component.Pipelines {
+			customComponent.Pipelines = append(customComponent.Pipelines, string(pipeline))
+		}
+
+		if component.Config != nil && len(component.Config.Raw) > 0 {
+			if err := json.Unmarshal(component.Config.Raw, &customComponent.Config); err != nil {
+				return nil, errors.WithMessagef(err, "config of '%s' has to be an object", component.ID)
+			}
+		}
+
+		customComponents = append(customComponents, customComponent)
+	}
+
+	return customComponents, nil
+}
+
+func toUint32(value *int32) *uint32 {
+	if value == nil || *value < 0 {
+		return nil
+	}
+
+	converted := uint32(*value)
+
+	return &converted
+}
+
 func (ts *TelemetryIngest) SetName(name string) {
 	ts.name = name
 }
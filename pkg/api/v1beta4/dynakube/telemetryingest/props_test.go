@@ -0,0 +1,63 @@
+package telemetryingest
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestGetPipelineOptions(t *testing.T) {
+	t.Run("nil spec", func(t *testing.T) {
+		var spec *Spec
+
+		options, err := spec.GetPipelineOptions()
+		require.NoError(t, err)
+		assert.Empty(t, options)
+	})
+
+	t.Run("customizations end up in the config", func(t *testing.T) {
+		spec := &Spec{
+			Processors: []ComponentSpec{
+				{
+					ID:        "attributes/team",
+					Pipelines: []Pipeline{"logs"},
+					Config:    &apiextensionsv1.JSON{Raw: []byte(`{"actions":[{"key":"team","value":"checkout","action":"upsert"}]}`)},
+				},
+			},
+			Exporters: []ComponentSpec{
+				{ID: "debug"},
+			},
+			Batch:         &BatchSpec{SendBatchSize: ptr.To[int32](100)},
+			MemoryLimiter: &MemoryLimiterSpec{SpikeLimitPercentage: ptr.To[int32](10)},
+		}
+
+		options, err := spec.GetPipelineOptions()
+		require.NoError(t, err)
+
+		options = append(options, otelcgen.WithProcessors(), otelcgen.WithExporters(), otelcgen.WithServices())
+		config, err := otelcgen.NewConfig("", otelcgen.RegisteredProtocols, options...)
+		require.NoError(t, err)
+
+		marshaled, err := config.Marshal()
+		require.NoError(t, err)
+		assert.Contains(t, string(marshaled), "attributes/team:")
+		assert.Contains(t, string(marshaled), "debug: {}")
+		assert.Contains(t, string(marshaled), "send_batch_size: 100")
+		assert.Contains(t, string(marshaled), "spike_limit_percentage: 10")
+	})
+
+	t.Run("config has to be an object", func(t *testing.T) {
+		spec := &Spec{
+			Processors: []ComponentSpec{
+				{ID: "filter/a", Config: &apiextensionsv1.JSON{Raw: []byte(`"drop"`)}},
+			},
+		}
+
+		_, err := spec.GetPipelineOptions()
+		require.Error(t, err)
+	})
+}
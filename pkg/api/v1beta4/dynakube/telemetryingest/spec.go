@@ -1,5 +1,9 @@
 package telemetryingest
 
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
 type TelemetryIngest struct {
 	*Spec
 
@@ -9,6 +13,14 @@ type TelemetryIngest struct {
 // +kubebuilder:object:generate=true
 
 type Spec struct {
+	// Overrides for the settings of the batch processors.
+	// +kubebuilder:validation:Optional
+	Batch *BatchSpec `json:"batch,omitempty"`
+
+	// Overrides for the settings of the memory limiter processor.
+	// +kubebuilder:validation:Optional
+	MemoryLimiter *MemoryLimiterSpec `json:"memoryLimiter,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	ServiceName string `json:"serviceName,omitempty"`
 
@@ -17,4 +29,70 @@ type Spec struct {
 
 	// +kubebuilder:validation:Optional
 	Protocols []string `json:"protocols,omitempty"`
+
+	// Additional processors added to the pipelines before the data is batched, e.g. filter, attributes, resource, tail_sampling or probabilistic_sampler.
+	// +kubebuilder:validation:Optional
+	Processors []ComponentSpec `json:"processors,omitempty"`
+
+	// Additional exporters the pipelines send data to, next to Dynatrace, e.g. otlp, otlphttp or debug.
+	// +kubebuilder:validation:Optional
+	Exporters []ComponentSpec `json:"exporters,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type ComponentSpec struct {
+	// Configuration of the component, as documented for the OpenTelemetry Collector.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *apiextensionsv1.JSON `json:"config,omitempty"`
+
+	// ID of the component in the form type[/name], e.g. filter/drop-health-checks.
+	// +kubebuilder:validation:Required
+	ID string `json:"id"`
+
+	// Pipelines the component is added to, defaults to all pipelines supporting the component.
+	// +kubebuilder:validation:Optional
+	Pipelines []Pipeline `json:"pipelines,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=traces;metrics;logs
+type Pipeline string
+
+// +kubebuilder:object:generate=true
+
+type BatchSpec struct {
+	// Maximum time before a batch is sent, e.g. 10s.
+	// +kubebuilder:validation:Optional
+	Timeout string `json:"timeout,omitempty"`
+
+	// Number of items after which a batch is sent.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	SendBatchSize *int32 `json:"sendBatchSize,omitempty"`
+
+	// Upper limit of the batch size, 0 means no limit.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	SendBatchMaxSize *int32 `json:"sendBatchMaxSize,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type MemoryLimiterSpec struct {
+	// Time between memory usage measurements, e.g. 1s.
+	// +kubebuilder:validation:Optional
+	CheckInterval string `json:"checkInterval,omitempty"`
+
+	// Maximum amount of memory, in percent of the total memory, the collector may use.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	LimitPercentage *int32 `json:"limitPercentage,omitempty"`
+
+	// Expected maximum spike between measurements, in percent of the total memory.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	SpikeLimitPercentage *int32 `json:"spikeLimitPercentage,omitempty"`
 }
@@ -18,16 +18,117 @@ limitations under the License.
 
 package telemetryingest
 
-import ()
+import (
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchSpec) DeepCopyInto(out *BatchSpec) {
+	*out = *in
+	if in.SendBatchSize != nil {
+		in, out := &in.SendBatchSize, &out.SendBatchSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SendBatchMaxSize != nil {
+		in, out := &in.SendBatchMaxSize, &out.SendBatchMaxSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchSpec.
+func (in *BatchSpec) DeepCopy() *BatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(v1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pipelines != nil {
+		in, out := &in.Pipelines, &out.Pipelines
+		*out = make([]Pipeline, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSpec.
+func (in *ComponentSpec) DeepCopy() *ComponentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryLimiterSpec) DeepCopyInto(out *MemoryLimiterSpec) {
+	*out = *in
+	if in.LimitPercentage != nil {
+		in, out := &in.LimitPercentage, &out.LimitPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SpikeLimitPercentage != nil {
+		in, out := &in.SpikeLimitPercentage, &out.SpikeLimitPercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryLimiterSpec.
+func (in *MemoryLimiterSpec) DeepCopy() *MemoryLimiterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryLimiterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Spec) DeepCopyInto(out *Spec) {
 	*out = *in
+	if in.Batch != nil {
+		in, out := &in.Batch, &out.Batch
+		*out = new(BatchSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemoryLimiter != nil {
+		in, out := &in.MemoryLimiter, &out.MemoryLimiter
+		*out = new(MemoryLimiterSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Protocols != nil {
 		in, out := &in.Protocols, &out.Protocols
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Processors != nil {
+		in, out := &in.Processors, &out.Processors
+		*out = make([]ComponentSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Exporters != nil {
+		in, out := &in.Exporters, &out.Exporters
+		*out = make([]ComponentSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Spec.
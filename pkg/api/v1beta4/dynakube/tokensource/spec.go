@@ -0,0 +1,106 @@
+package tokensource
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultVaultMount is the mount of the KV secrets engine, if none is given.
+	DefaultVaultMount = "secret"
+
+	// DefaultOAuthTokenURL is the token endpoint of the Dynatrace SSO.
+	DefaultOAuthTokenURL = "https://sso.dynatrace.com/sso/oauth2/token"
+
+	// FileBasePath is the directory the Helm chart mounts the token volumes into, file sources have to be below it.
+	FileBasePath = "/var/run/secrets/dynatrace.com/tokens"
+
+	// ServiceAccountTokenPath is where the Helm chart mounts the projected service account token, with the audience set during install.
+	ServiceAccountTokenPath = "/var/run/secrets/dynatrace.com/serviceaccount/token"
+)
+
+// +kubebuilder:object:generate=true
+
+// Spec configures where the tokens of the DynaKube are read from, instead of the tokens secret.
+// File and Vault provide all tokens, OAuth provides the API token and can be combined with one of them.
+type Spec struct {
+	// Reads the tokens from files mounted into the operator and webhook, e.g. by the Vault Agent injector or the Secrets Store CSI driver.
+	// +kubebuilder:validation:Optional
+	File *File `json:"file,omitempty"`
+
+	// Reads the tokens from a Vault KV v2 secret through the Vault Agent running next to the operator and webhook.
+	// The address of the Vault Agent is set during install.
+	// +kubebuilder:validation:Optional
+	Vault *Vault `json:"vault,omitempty"`
+
+	// Exchanges the projected service account token of the operator for a short-lived Dynatrace OAuth access token, which is used as API token.
+	// The audience of the service account token is set during install.
+	// +kubebuilder:validation:Optional
+	OAuth *OAuth `json:"oauth,omitempty"`
+}
+
+// +kubebuilder:object:generate=true
+
+type File struct {
+	// Absolute path of the directory holding one file per token, named like the keys of the tokens secret, e.g. apiToken.
+	// It has to be below /var/run/secrets/dynatrace.com/tokens, where the Helm chart mounts the token volumes.
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+}
+
+// +kubebuilder:object:generate=true
+
+type Vault struct {
+	// Mount of the KV v2 secrets engine.
+	// Defaults to "secret".
+	// +kubebuilder:validation:Optional
+	Mount string `json:"mount,omitempty"`
+
+	// Path of the secret in the KV secrets engine, its keys are named like the keys of the tokens secret, e.g. apiToken.
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+}
+
+// +kubebuilder:object:generate=true
+
+type OAuth struct {
+	// ID of the OAuth client the service account token is federated with.
+	// +kubebuilder:validation:Required
+	ClientID string `json:"clientId"`
+
+	// Resource the access token is requested for, e.g. urn:dtenvironment:<environment-id>.
+	// +kubebuilder:validation:Optional
+	Resource string `json:"resource,omitempty"`
+
+	// Scopes requested for the access token.
+	// +kubebuilder:validation:Optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// URL of the token endpoint, it has to be on the host of the Dynatrace SSO or one of the token endpoints allowed during install.
+	// Defaults to "https://sso.dynatrace.com/sso/oauth2/token".
+	// +kubebuilder:validation:Optional
+	TokenURL string `json:"tokenUrl,omitempty"`
+}
+
+// GetMount returns the mount of the KV secrets engine.
+func (vault *Vault) GetMount() string {
+	if vault.Mount == "" {
+		return DefaultVaultMount
+	}
+
+	return vault.Mount
+}
+
+// GetTokenURL returns the URL of the token endpoint.
+func (oauth *OAuth) GetTokenURL() string {
+	if oauth.TokenURL == "" {
+		return DefaultOAuthTokenURL
+	}
+
+	return oauth.TokenURL
+}
+
+// IsBelowFileBasePath checks if the path is inside of the directory of the token volumes.
+func IsBelowFileBasePath(path string) bool {
+	return strings.HasPrefix(filepath.Clean(path), FileBasePath+"/")
+}
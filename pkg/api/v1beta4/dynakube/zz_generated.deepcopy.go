@@ -19,10 +19,15 @@ limitations under the License.
 package dynakube
 
 import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/registrymirror"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/egress"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/imageverification"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/kspm"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/logmonitoring"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/telemetryingest"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -121,7 +126,27 @@ func (in *DynaKubeSpec) DeepCopyInto(out *DynaKubeSpec) {
 		*out = new(telemetryingest.Spec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(egress.Spec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageVerification != nil {
+		in, out := &in.ImageVerification, &out.ImageVerification
+		*out = new(imageverification.Spec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryMirror != nil {
+		in, out := &in.RegistryMirror, &out.RegistryMirror
+		*out = new(registrymirror.Spec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.OneAgent.DeepCopyInto(&out.OneAgent)
+	if in.TokenSource != nil {
+		in, out := &in.TokenSource, &out.TokenSource
+		*out = new(tokensource.Spec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Templates.DeepCopyInto(&out.Templates)
 	in.ActiveGate.DeepCopyInto(&out.ActiveGate)
 }
@@ -143,7 +168,17 @@ func (in *DynaKubeStatus) DeepCopyInto(out *DynaKubeStatus) {
 	in.ActiveGate.DeepCopyInto(&out.ActiveGate)
 	in.CodeModules.DeepCopyInto(&out.CodeModules)
 	in.MetadataEnrichment.DeepCopyInto(&out.MetadataEnrichment)
+	if in.InjectionCoverage != nil {
+		in, out := &in.InjectionCoverage, &out.InjectionCoverage
+		*out = new(InjectionCoverageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Kspm = in.Kspm
+	if in.ReleaseLock != nil {
+		in, out := &in.ReleaseLock, &out.ReleaseLock
+		*out = new(releaselock.Status)
+		**out = **in
+	}
 	in.UpdatedTimestamp.DeepCopyInto(&out.UpdatedTimestamp)
 	in.DynatraceApi.DeepCopyInto(&out.DynatraceApi)
 	if in.Conditions != nil {
@@ -169,6 +204,13 @@ func (in *DynaKubeStatus) DeepCopy() *DynaKubeStatus {
 func (in *DynatraceApiStatus) DeepCopyInto(out *DynatraceApiStatus) {
 	*out = *in
 	in.LastTokenScopeRequest.DeepCopyInto(&out.LastTokenScopeRequest)
+	if in.Tokens != nil {
+		in, out := &in.Tokens, &out.Tokens
+		*out = make([]TokenStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynatraceApiStatus.
@@ -261,6 +303,67 @@ func (in *ExtensionsSpec) DeepCopy() *ExtensionsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionCoverage) DeepCopyInto(out *InjectionCoverage) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]InjectionReasonCount, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionCoverage.
+func (in *InjectionCoverage) DeepCopy() *InjectionCoverage {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionCoverage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionCoverageStatus) DeepCopyInto(out *InjectionCoverageStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Injections != nil {
+		in, out := &in.Injections, &out.Injections
+		*out = make([]InjectionCoverage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionCoverageStatus.
+func (in *InjectionCoverageStatus) DeepCopy() *InjectionCoverageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionCoverageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InjectionReasonCount) DeepCopyInto(out *InjectionReasonCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InjectionReasonCount.
+func (in *InjectionReasonCount) DeepCopy() *InjectionReasonCount {
+	if in == nil {
+		return nil
+	}
+	out := new(InjectionReasonCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetadataEnrichment) DeepCopyInto(out *MetadataEnrichment) {
 	*out = *in
@@ -270,6 +373,11 @@ func (in *MetadataEnrichment) DeepCopyInto(out *MetadataEnrichment) {
 		**out = **in
 	}
 	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]MetadataEnrichmentRule, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataEnrichment.
@@ -282,6 +390,21 @@ func (in *MetadataEnrichment) DeepCopy() *MetadataEnrichment {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataEnrichmentRule) DeepCopyInto(out *MetadataEnrichmentRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataEnrichmentRule.
+func (in *MetadataEnrichmentRule) DeepCopy() *MetadataEnrichmentRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataEnrichmentRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetadataEnrichmentStatus) DeepCopyInto(out *MetadataEnrichmentStatus) {
 	*out = *in
@@ -290,6 +413,15 @@ func (in *MetadataEnrichmentStatus) DeepCopyInto(out *MetadataEnrichmentStatus)
 		*out = make([]EnrichmentRule, len(*in))
 		copy(*out, *in)
 	}
+	if in.TenantRules != nil {
+		in, out := &in.TenantRules, &out.TenantRules
+		*out = make([]EnrichmentRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.TenantRulesQueriedAt != nil {
+		in, out := &in.TenantRulesQueriedAt, &out.TenantRulesQueriedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataEnrichmentStatus.
@@ -374,3 +506,22 @@ func (in *TemplatesSpec) DeepCopy() *TemplatesSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenStatus) DeepCopyInto(out *TokenStatus) {
+	*out = *in
+	if in.ExpirationDate != nil {
+		in, out := &in.ExpirationDate, &out.ExpirationDate
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenStatus.
+func (in *TokenStatus) DeepCopy() *TokenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
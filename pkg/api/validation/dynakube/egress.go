@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+)
+
+const (
+	errorEgressMissingParentRef   = `The Dynakube's specification specifies the %s egress backend, but no parentRef is configured. The generated routes need to be attached to an existing %s.`
+	warningEgressIgnoredParentRef = `The Dynakube's specification specifies an egress parentRef, which is ignored by the %s egress backend.`
+)
+
+func missingEgressParentRef(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	egress := dk.Egress()
+	if !egress.NeedsParentRef() || egress.GetParentName() != "" {
+		return ""
+	}
+
+	return fmt.Sprintf(errorEgressMissingParentRef, egress.GetBackend(), egress.GetParentKind())
+}
+
+func ignoredEgressParentRef(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	egress := dk.Egress()
+	if !egress.IsEnabled() || egress.NeedsParentRef() || egress.ParentRef == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(warningEgressIgnoredParentRef, egress.GetBackend())
+}
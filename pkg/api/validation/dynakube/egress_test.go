@@ -0,0 +1,53 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/egress"
+)
+
+func TestMissingEgressParentRef(t *testing.T) {
+	t.Run("no egress", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, createEgressDynakube(nil))
+	})
+
+	t.Run("backends without parent", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, createEgressDynakube(&egress.Spec{Backend: egress.NetworkPolicyBackend}))
+		assertAllowedWithoutWarnings(t, createEgressDynakube(&egress.Spec{Backend: egress.CiliumBackend}))
+	})
+
+	t.Run("backends with parent", func(t *testing.T) {
+		parentRef := &egress.ParentRef{Name: "egress", Namespace: "linkerd-egress"}
+
+		assertAllowedWithoutWarnings(t, createEgressDynakube(&egress.Spec{Backend: egress.LinkerdBackend, ParentRef: parentRef}))
+		assertAllowedWithoutWarnings(t, createEgressDynakube(&egress.Spec{Backend: egress.GatewayAPIBackend, ParentRef: parentRef}))
+	})
+
+	t.Run("missing parent", func(t *testing.T) {
+		assertDenied(t,
+			[]string{fmt.Sprintf(errorEgressMissingParentRef, egress.LinkerdBackend, "EgressNetwork")},
+			createEgressDynakube(&egress.Spec{Backend: egress.LinkerdBackend}))
+		assertDenied(t,
+			[]string{fmt.Sprintf(errorEgressMissingParentRef, egress.GatewayAPIBackend, "Gateway")},
+			createEgressDynakube(&egress.Spec{Backend: egress.GatewayAPIBackend, ParentRef: &egress.ParentRef{}}))
+	})
+}
+
+func TestIgnoredEgressParentRef(t *testing.T) {
+	assertAllowedWithWarnings(t, 1, createEgressDynakube(&egress.Spec{
+		Backend:   egress.CiliumBackend,
+		ParentRef: &egress.ParentRef{Name: "egress"},
+	}))
+}
+
+func createEgressDynakube(egressSpec *egress.Spec) *dynakube.DynaKube {
+	return &dynakube.DynaKube{
+		ObjectMeta: defaultDynakubeObjectMeta,
+		Spec: dynakube.DynaKubeSpec{
+			APIURL: testApiUrl,
+			Egress: egressSpec,
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+)
+
+const (
+	errorKeylessWithoutTrustedRoot = `The DynaKube's specification enables keyless image verification, but has no trustedRoot configmap containing the Fulcio certificates and the Rekor public key.`
+
+	errorKeylessWithoutSubject = `The DynaKube's specification has a keyless image verification identity of the issuer '%s' without subject or subjectRegExp, which would accept any signer of the issuer.`
+
+	errorInvalidKeylessSubjectRegExp = `The DynaKube's specification has an invalid subjectRegExp '%s' for keyless image verification: %s`
+)
+
+func keylessWithoutTrustedRoot(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	imageVerification := dk.ImageVerification()
+	if imageVerification.IsEnabled() && len(imageVerification.Keyless) > 0 && imageVerification.GetTrustedRoot() == "" {
+		log.Info("requested dynakube has keyless image verification without trusted root", "name", dk.Name, "namespace", dk.Namespace)
+
+		return errorKeylessWithoutTrustedRoot
+	}
+
+	return ""
+}
+
+func invalidKeylessIdentity(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	imageVerification := dk.ImageVerification()
+	if !imageVerification.IsEnabled() {
+		return ""
+	}
+
+	for _, identity := range imageVerification.Keyless {
+		if identity.Subject == "" && identity.SubjectRegExp == "" {
+			log.Info("requested dynakube has keyless image verification identity without subject", "name", dk.Name, "namespace", dk.Namespace)
+
+			return fmt.Sprintf(errorKeylessWithoutSubject, identity.Issuer)
+		}
+
+		if identity.SubjectRegExp == "" {
+			continue
+		}
+
+		if _, err := regexp.Compile(identity.SubjectRegExp); err != nil {
+			log.Info("requested dynakube has invalid keyless image verification subjectRegExp", "name", dk.Name, "namespace", dk.Namespace)
+
+			return fmt.Sprintf(errorInvalidKeylessSubjectRegExp, identity.SubjectRegExp, err.Error())
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/imageverification"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+)
+
+func TestImageVerification(t *testing.T) {
+	createDynakube := func(spec *imageverification.Spec) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+				},
+				ImageVerification: spec,
+			},
+		}
+	}
+
+	t.Run("public keys and trusted registries", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, createDynakube(&imageverification.Spec{
+			PublicKeysSecret:  "cosign-keys",
+			TrustedRegistries: []string{"public.ecr.aws/dynatrace"},
+		}))
+	})
+
+	t.Run("keyless with trusted root", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, createDynakube(&imageverification.Spec{
+			TrustedRoot: "sigstore-root",
+			Keyless: []imageverification.KeylessIdentity{
+				{Issuer: "https://token.actions.githubusercontent.com", SubjectRegExp: `https://github\.com/Dynatrace/.*`},
+			},
+		}))
+	})
+
+	t.Run("keyless without trusted root", func(t *testing.T) {
+		assertDenied(t, []string{errorKeylessWithoutTrustedRoot}, createDynakube(&imageverification.Spec{
+			Keyless: []imageverification.KeylessIdentity{
+				{Issuer: "https://token.actions.githubusercontent.com", Subject: "release@dynatrace.com"},
+			},
+		}))
+	})
+
+	t.Run("keyless without subject", func(t *testing.T) {
+		assertDenied(t, []string{"without subject or subjectRegExp"}, createDynakube(&imageverification.Spec{
+			TrustedRoot: "sigstore-root",
+			Keyless: []imageverification.KeylessIdentity{
+				{Issuer: "https://token.actions.githubusercontent.com"},
+			},
+		}))
+	})
+
+	t.Run("keyless with invalid subjectRegExp", func(t *testing.T) {
+		assertDenied(t, []string{"invalid subjectRegExp"}, createDynakube(&imageverification.Spec{
+			TrustedRoot: "sigstore-root",
+			Keyless: []imageverification.KeylessIdentity{
+				{Issuer: "https://token.actions.githubusercontent.com", SubjectRegExp: "(unclosed"},
+			},
+		}))
+	})
+}
@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	errorInvalidMetadataEnrichmentRule         = "The DynaKube's specification contains invalid metadata-enrichment rules, the source and target must be valid label/annotation keys: %s"
+	errorDuplicateMetadataEnrichmentRuleTarget = "The DynaKube's specification contains metadata-enrichment rules with the same target: %s"
+)
+
+func invalidMetadataEnrichmentRules(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	invalidRules := []string{}
+
+	for _, rule := range dk.Spec.MetadataEnrichment.Rules {
+		if len(validation.IsQualifiedName(rule.Source)) > 0 || len(validation.IsQualifiedName(dynakube.MetadataPrefix+rule.Target)) > 0 {
+			invalidRules = append(invalidRules, fmt.Sprintf("%s -> %s", rule.Source, rule.Target))
+		}
+	}
+
+	if len(invalidRules) > 0 {
+		return fmt.Sprintf(errorInvalidMetadataEnrichmentRule, strings.Join(invalidRules, ", "))
+	}
+
+	return ""
+}
+
+func duplicateMetadataEnrichmentRuleTargets(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	targets := map[string]bool{}
+	duplicates := []string{}
+
+	for _, rule := range dk.Spec.MetadataEnrichment.Rules {
+		if targets[rule.Target] {
+			duplicates = append(duplicates, rule.Target)
+		}
+
+		targets[rule.Target] = true
+	}
+
+	if len(duplicates) > 0 {
+		return fmt.Sprintf(errorDuplicateMetadataEnrichmentRuleTarget, strings.Join(duplicates, ", "))
+	}
+
+	return ""
+}
@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"k8s.io/utils/ptr"
+)
+
+func TestMetadataEnrichmentRules(t *testing.T) {
+	createDynakube := func(rules ...dynakube.MetadataEnrichmentRule) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				MetadataEnrichment: dynakube.MetadataEnrichment{
+					Enabled: ptr.To(true),
+					Rules:   rules,
+				},
+			},
+		}
+	}
+
+	t.Run("valid rules", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, createDynakube(
+			dynakube.MetadataEnrichmentRule{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentNodeScope, Source: "topology.kubernetes.io/zone", Target: "zone"},
+			dynakube.MetadataEnrichmentRule{Type: dynakube.EnrichmentAnnotationRule, Source: "cost-center", Target: "cost.center"},
+		))
+	})
+
+	t.Run("invalid source or target", func(t *testing.T) {
+		assertDenied(t, []string{fmt.Sprintf(errorInvalidMetadataEnrichmentRule, " -> zone, team -> my team")}, createDynakube(
+			dynakube.MetadataEnrichmentRule{Type: dynakube.EnrichmentLabelRule, Source: "", Target: "zone"},
+			dynakube.MetadataEnrichmentRule{Type: dynakube.EnrichmentLabelRule, Source: "team", Target: "my team"},
+		))
+	})
+
+	t.Run("duplicate targets", func(t *testing.T) {
+		assertDenied(t, []string{fmt.Sprintf(errorDuplicateMetadataEnrichmentRuleTarget, "team")}, createDynakube(
+			dynakube.MetadataEnrichmentRule{Type: dynakube.EnrichmentLabelRule, Source: "team", Target: "team"},
+			dynakube.MetadataEnrichmentRule{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentWorkloadScope, Source: "team", Target: "team"},
+		))
+	})
+}
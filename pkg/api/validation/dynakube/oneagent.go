@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/dtversion"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	"k8s.io/apimachinery/pkg/labels"
@@ -21,6 +22,14 @@ const (
 
 	errorImagePullRequiresCodeModulesImage = `The DynaKube specification enables node image pull, but the code modules image is not set.`
 
+	errorCodeModulesBundleWithoutCSI = `The DynaKube specification sets a code modules bundle, but the CSI driver is not enabled or node image pull is enabled.`
+
+	errorCodeModulesBundleWithImage = `The DynaKube specification sets both a code modules bundle and a code modules image, only one of them can be used.`
+
+	errorCodeModulesBundleWithTenantAccess = `The DynaKube specification sets a code modules bundle, which is used without access to the Dynatrace API, but also enables %s, which needs it.`
+
+	errorCodeModulesVerificationNotApplicable = `The DynaKube specification sets a code modules verification, which only applies to code modules that the CSI driver downloads from the Dynatrace cluster. It can't be combined with a code modules image, a code modules bundle or node image pull.`
+
 	errorNodeSelectorConflict = `The Dynakube specification conflicts with another Dynakube's OneAgent or Standalone-LogMonitoring. Only one Agent per node is supported.
 Use a nodeSelector to avoid this conflict. Conflicting DynaKubes: %s`
 
@@ -147,6 +156,76 @@ func missingCodeModulesImage(_ context.Context, v *Validator, dk *dynakube.DynaK
 	return ""
 }
 
+func invalidCodeModulesBundle(_ context.Context, v *Validator, dk *dynakube.DynaKube) string {
+	var appInjectionSpec *oneagent.AppInjectionSpec
+
+	switch {
+	case dk.OneAgent().IsCloudNativeFullstackMode():
+		appInjectionSpec = &dk.Spec.OneAgent.CloudNativeFullStack.AppInjectionSpec
+	case dk.OneAgent().IsApplicationMonitoringMode():
+		appInjectionSpec = &dk.Spec.OneAgent.ApplicationMonitoring.AppInjectionSpec
+	}
+
+	if appInjectionSpec == nil || appInjectionSpec.CodeModulesBundle == nil {
+		return ""
+	}
+
+	if !v.modules.CSIDriver || dk.FF().IsNodeImagePull() {
+		return errorCodeModulesBundleWithoutCSI
+	}
+
+	if appInjectionSpec.CodeModulesImage != "" {
+		return errorCodeModulesBundleWithImage
+	}
+
+	if component := tenantAccessComponent(dk); component != "" {
+		return fmt.Sprintf(errorCodeModulesBundleWithTenantAccess, component)
+	}
+
+	return ""
+}
+
+// tenantAccessComponent returns the first enabled component that needs access to the Dynatrace API.
+func tenantAccessComponent(dk *dynakube.DynaKube) string {
+	switch {
+	case dk.OneAgent().IsDaemonsetRequired():
+		return "host OneAgents"
+	case dk.ActiveGate().IsEnabled():
+		return "ActiveGate"
+	case dk.LogMonitoring().IsEnabled():
+		return "log monitoring"
+	case dk.IsExtensionsEnabled():
+		return "extensions"
+	case dk.KSPM().IsEnabled():
+		return "KSPM"
+	case dk.TelemetryIngest().IsEnabled():
+		return "telemetry ingest"
+	}
+
+	return ""
+}
+
+func invalidCodeModulesVerification(_ context.Context, v *Validator, dk *dynakube.DynaKube) string {
+	var appInjectionSpec *oneagent.AppInjectionSpec
+
+	switch {
+	case dk.OneAgent().IsCloudNativeFullstackMode():
+		appInjectionSpec = &dk.Spec.OneAgent.CloudNativeFullStack.AppInjectionSpec
+	case dk.OneAgent().IsApplicationMonitoringMode():
+		appInjectionSpec = &dk.Spec.OneAgent.ApplicationMonitoring.AppInjectionSpec
+	}
+
+	if appInjectionSpec == nil || appInjectionSpec.CodeModulesVerification == nil {
+		return ""
+	}
+
+	if !v.modules.CSIDriver || dk.FF().IsNodeImagePull() || appInjectionSpec.CodeModulesImage != "" || appInjectionSpec.CodeModulesBundle != nil {
+		return errorCodeModulesVerificationNotApplicable
+	}
+
+	return ""
+}
+
 func hasConflictingMatchLabels(labelMap, otherLabelMap map[string]string) bool {
 	if labelMap == nil || otherLabelMap == nil {
 		return true
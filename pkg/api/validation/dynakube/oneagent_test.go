@@ -6,6 +6,7 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
 	corev1 "k8s.io/api/core/v1"
@@ -856,3 +857,196 @@ func TestNoHostIdSourceArgument(t *testing.T) {
 		})
 	}
 }
+
+func TestInvalidCodeModulesBundle(t *testing.T) {
+	bundle := &oneagent.CodeModulesBundleSpec{
+		Path:        "/bundles/codemodules.tar.gz",
+		TrustedKeys: "bundle-keys",
+	}
+
+	t.Run("bundle with csi driver", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesBundle: bundle,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("bundle with host oneagents", func(t *testing.T) {
+		assertDenied(t, []string{fmt.Sprintf(errorCodeModulesBundleWithTenantAccess, "host OneAgents")}, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesBundle: bundle,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("bundle with activegate", func(t *testing.T) {
+		assertDenied(t, []string{fmt.Sprintf(errorCodeModulesBundleWithTenantAccess, "ActiveGate")}, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesBundle: bundle,
+						},
+					},
+				},
+				ActiveGate: activegate.Spec{
+					Capabilities: []activegate.CapabilityDisplayName{activegate.RoutingCapability.DisplayName},
+				},
+			},
+		})
+	})
+
+	t.Run("bundle without csi driver", func(t *testing.T) {
+		setupDisabledCSIEnv(t)
+
+		assertDenied(t, []string{errorCodeModulesBundleWithoutCSI}, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesBundle: bundle,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("bundle with node image pull", func(t *testing.T) {
+		assertDenied(t, []string{errorCodeModulesBundleWithoutCSI}, &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testName,
+				Namespace: testNamespace,
+				Annotations: map[string]string{
+					exp.OANodeImagePullKey: "true",
+				},
+			},
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesImage:  "testImage",
+							CodeModulesBundle: bundle,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("bundle with code modules image", func(t *testing.T) {
+		assertDenied(t, []string{errorCodeModulesBundleWithImage}, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesImage:  "testImage",
+							CodeModulesBundle: bundle,
+						},
+					},
+				},
+			},
+		})
+	})
+}
+
+func TestInvalidCodeModulesVerification(t *testing.T) {
+	verification := &oneagent.CodeModulesVerificationSpec{
+		ConfigMap: "codemodules-digests",
+	}
+
+	t.Run("verification with csi driver", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesVerification: verification,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("verification without csi driver", func(t *testing.T) {
+		setupDisabledCSIEnv(t)
+
+		assertDenied(t, []string{errorCodeModulesVerificationNotApplicable}, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesVerification: verification,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("verification with code modules image", func(t *testing.T) {
+		assertDenied(t, []string{errorCodeModulesVerificationNotApplicable}, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesImage:        "testImage",
+							CodeModulesVerification: verification,
+						},
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("verification with code modules bundle", func(t *testing.T) {
+		assertDenied(t, []string{errorCodeModulesVerificationNotApplicable}, &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesBundle: &oneagent.CodeModulesBundleSpec{
+								Path:        "/bundles/codemodules.tar.gz",
+								TrustedKeys: "bundle-keys",
+							},
+							CodeModulesVerification: verification,
+						},
+					},
+				},
+			},
+		})
+	})
+}
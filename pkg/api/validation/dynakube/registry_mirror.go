@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+)
+
+const (
+	errorInvalidRegistryMirrorPrefix = `The DynaKube's specification has an invalid registry mirror prefix '%s'. Prefixes are registry hosts optionally followed by a repository path, without scheme, tag or digest, e.g. registry.corp/dt.`
+
+	errorDuplicateRegistryMirrorRule = `The DynaKube's specification has more than one registry mirror rule for the prefix '%s'.`
+)
+
+func invalidRegistryMirrorRules(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if dk.Spec.RegistryMirror == nil {
+		return ""
+	}
+
+	seen := map[string]bool{}
+
+	for _, rule := range dk.Spec.RegistryMirror.Rules {
+		for _, prefix := range []string{rule.From, rule.To} {
+			if !isValidRegistryMirrorPrefix(prefix) {
+				log.Info("requested dynakube has invalid registry mirror prefix", "name", dk.Name, "namespace", dk.Namespace)
+
+				return fmt.Sprintf(errorInvalidRegistryMirrorPrefix, prefix)
+			}
+		}
+
+		from := strings.TrimSuffix(strings.TrimSuffix(rule.From, "*"), "/")
+		if seen[from] {
+			log.Info("requested dynakube has duplicate registry mirror rules", "name", dk.Name, "namespace", dk.Namespace)
+
+			return fmt.Sprintf(errorDuplicateRegistryMirrorRule, rule.From)
+		}
+
+		seen[from] = true
+	}
+
+	return ""
+}
+
+func isValidRegistryMirrorPrefix(prefix string) bool {
+	prefix = strings.TrimSuffix(strings.TrimSuffix(prefix, "*"), "/")
+
+	return prefix != "" && !strings.Contains(prefix, "://") && !strings.ContainsAny(prefix, " \t@*")
+}
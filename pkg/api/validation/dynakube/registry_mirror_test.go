@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/registrymirror"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+)
+
+func TestRegistryMirror(t *testing.T) {
+	createDynakube := func(rules ...registrymirror.Rule) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+				},
+				RegistryMirror: &registrymirror.Spec{Rules: rules},
+			},
+		}
+	}
+
+	t.Run("valid rules", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, createDynakube(
+			registrymirror.Rule{From: "public.ecr.aws/dynatrace/*", To: "registry.corp/dt/*", PullSecret: "corp"},
+			registrymirror.Rule{From: "docker.io", To: "registry.corp/docker"},
+		))
+	})
+
+	t.Run("prefix with scheme", func(t *testing.T) {
+		assertDenied(t, []string{"invalid registry mirror prefix 'https://registry.corp'"}, createDynakube(
+			registrymirror.Rule{From: "public.ecr.aws/dynatrace", To: "https://registry.corp"},
+		))
+	})
+
+	t.Run("prefix with digest", func(t *testing.T) {
+		assertDenied(t, []string{"invalid registry mirror prefix"}, createDynakube(
+			registrymirror.Rule{From: "public.ecr.aws/dynatrace/oneagent@sha256:abc", To: "registry.corp/dt"},
+		))
+	})
+
+	t.Run("duplicate rules", func(t *testing.T) {
+		assertDenied(t, []string{"more than one registry mirror rule"}, createDynakube(
+			registrymirror.Rule{From: "public.ecr.aws/dynatrace", To: "registry.corp/dt"},
+			registrymirror.Rule{From: "public.ecr.aws/dynatrace/*", To: "registry.other/dt"},
+		))
+	})
+}
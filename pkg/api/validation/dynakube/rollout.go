@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	errorRolloutWithoutCanary = `The DynaKube's specification enables a staged rollout, but doesn't select a canary. Set the nodeSelector or percentage for the OneAgent and/or the namespaceSelector for the code modules.`
+
+	errorInvalidRolloutNamespaceSelector = `The DynaKube's specification contains an invalid namespaceSelector for the staged rollout: `
+)
+
+func rolloutWithoutCanary(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if dk.OneAgent().Spec.Rollout == nil {
+		return ""
+	}
+
+	if !dk.OneAgent().IsOneAgentRolloutEnabled() && !dk.OneAgent().IsCodeModulesRolloutEnabled() {
+		return errorRolloutWithoutCanary
+	}
+
+	return ""
+}
+
+func invalidRolloutNamespaceSelector(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if !dk.OneAgent().IsCodeModulesRolloutEnabled() {
+		return ""
+	}
+
+	_, err := metav1.LabelSelectorAsSelector(dk.OneAgent().Spec.Rollout.NamespaceSelector)
+	if err != nil {
+		return errorInvalidRolloutNamespaceSelector + err.Error()
+	}
+
+	return ""
+}
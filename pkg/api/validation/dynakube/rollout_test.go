@@ -0,0 +1,63 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestRollout(t *testing.T) {
+	createDynakube := func(rollout *oneagent.RolloutSpec) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+					Rollout:          rollout,
+				},
+			},
+		}
+	}
+
+	t.Run("canary nodes", func(t *testing.T) {
+		assertAllowedWithoutWarnings(t, createDynakube(&oneagent.RolloutSpec{Percentage: ptr.To(int32(10))}))
+		assertAllowedWithoutWarnings(t, createDynakube(&oneagent.RolloutSpec{NodeSelector: map[string]string{"canary": "true"}}))
+	})
+
+	t.Run("no canary", func(t *testing.T) {
+		assertDenied(t, []string{errorRolloutWithoutCanary}, createDynakube(&oneagent.RolloutSpec{FailurePolicy: oneagent.RolloutRollbackFailurePolicy}))
+	})
+
+	t.Run("invalid namespaceSelector", func(t *testing.T) {
+		dk := createDynakube(&oneagent.RolloutSpec{NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "canary", Operator: "Unknown"}},
+		}})
+
+		assertDenied(t, []string{errorInvalidRolloutNamespaceSelector}, dk)
+	})
+
+	t.Run("namespaceSelector with CSI driver", func(t *testing.T) {
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: true})
+
+		dk := createDynakube(&oneagent.RolloutSpec{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}})
+		dk.Spec.OneAgent.ClassicFullStack = nil
+		dk.Spec.OneAgent.ApplicationMonitoring = &oneagent.ApplicationMonitoringSpec{}
+
+		assertAllowedWithoutWarnings(t, dk)
+	})
+
+	t.Run("namespaceSelector without CSI driver", func(t *testing.T) {
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: false})
+
+		dk := createDynakube(&oneagent.RolloutSpec{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}})
+		dk.Spec.OneAgent.ClassicFullStack = nil
+		dk.Spec.OneAgent.ApplicationMonitoring = &oneagent.ApplicationMonitoringSpec{}
+
+		assertAllowedWithoutWarnings(t, dk)
+	})
+}
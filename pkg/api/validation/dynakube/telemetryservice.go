@@ -24,6 +24,7 @@ const (
 	`
 	errorTelemetryIngestServiceNameInUse     = `The DynaKube's specification enables the TelemetryIngest feature, the telemetry service name is already used by other Dynakube.`
 	errorTelemetryIngestForbiddenServiceName = `The DynaKube's specification enables the TelemetryIngest feature, the telemetry service name is incorrect because of forbidden suffix.`
+	errorTelemetryIngestInvalidPipelines     = `The DynaKube's specification enables the TelemetryIngest feature, the pipeline customizations are invalid: %s`
 )
 
 func emptyTelemetryIngestProtocolsList(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
@@ -40,6 +41,26 @@ func emptyTelemetryIngestProtocolsList(_ context.Context, _ *Validator, dk *dyna
 	return ""
 }
 
+func invalidTelemetryIngestPipelines(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if !dk.TelemetryIngest().IsEnabled() {
+		return ""
+	}
+
+	options, err := dk.TelemetryIngest().GetPipelineOptions()
+	if err == nil {
+		options = append(options, otelcgen.WithProcessors(), otelcgen.WithExporters(), otelcgen.WithServices())
+		_, err = otelcgen.NewConfig("", dk.TelemetryIngest().GetProtocols(), options...)
+	}
+
+	if err != nil {
+		log.Info("requested dynakube has invalid TelemetryIngest pipeline customizations", "error", err.Error())
+
+		return fmt.Sprintf(errorTelemetryIngestInvalidPipelines, err.Error())
+	}
+
+	return ""
+}
+
 func unknownTelemetryIngestProtocols(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
 	if !dk.TelemetryIngest().IsEnabled() {
 		return ""
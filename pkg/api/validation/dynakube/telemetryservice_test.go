@@ -8,6 +8,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	agconsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/otelcgen"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -313,3 +314,55 @@ func TestForbiddenSuffix(t *testing.T) {
 			})
 	})
 }
+
+func TestTelemetryIngestPipelines(t *testing.T) {
+	t.Run(`valid customizations`, func(t *testing.T) {
+		assertAllowed(t,
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL: testApiUrl,
+					TelemetryIngest: &telemetryingest.Spec{
+						Processors: []telemetryingest.ComponentSpec{
+							{
+								ID:        "filter/drop-health-checks",
+								Pipelines: []telemetryingest.Pipeline{"traces"},
+								Config:    &apiextensionsv1.JSON{Raw: []byte(`{"traces":{"span":["attributes[\"http.route\"] == \"/healthz\""]}}`)},
+							},
+						},
+						Batch: &telemetryingest.BatchSpec{Timeout: "10s"},
+					},
+				},
+			})
+	})
+
+	t.Run(`unsupported processor`, func(t *testing.T) {
+		assertDenied(t,
+			[]string{"the pipeline customizations are invalid", "processor type 'groupbytrace' of 'groupbytrace' is not supported"},
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL: testApiUrl,
+					TelemetryIngest: &telemetryingest.Spec{
+						Processors: []telemetryingest.ComponentSpec{{ID: "groupbytrace"}},
+					},
+				},
+			})
+	})
+
+	t.Run(`config is not an object`, func(t *testing.T) {
+		assertDenied(t,
+			[]string{"config of 'otlp/backup' has to be an object"},
+			&dynakube.DynaKube{
+				ObjectMeta: defaultDynakubeObjectMeta,
+				Spec: dynakube.DynaKubeSpec{
+					APIURL: testApiUrl,
+					TelemetryIngest: &telemetryingest.Spec{
+						Exporters: []telemetryingest.ComponentSpec{
+							{ID: "otlp/backup", Config: &apiextensionsv1.JSON{Raw: []byte(`["endpoint"]`)}},
+						},
+					},
+				},
+			})
+	})
+}
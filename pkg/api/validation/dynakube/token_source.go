@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+)
+
+const (
+	errorMissingTokenSource = `The DynaKube's specification has a token source without file, vault or oauth.`
+
+	errorConflictingTokenSources = `The DynaKube's specification has a token source with both file and vault, only one of them can provide the tokens.`
+
+	errorRelativeTokenSourcePath = `The DynaKube's specification has a token source file path that is not absolute.`
+
+	errorTokenSourcePathOutsideBase = `The DynaKube's specification has a token source file path that is not below ` + tokensource.FileBasePath + `, where the token volumes are mounted.`
+
+	errorVaultAddressNotInstalled = `The DynaKube's specification has a vault token source, but no vault address was set during the install of the operator (tokenSource.vaultAddress in the Helm chart).`
+
+	errorOAuthTokenURLNotAllowed = `The DynaKube's specification has an oauth tokenUrl that is neither on the host of the Dynatrace SSO nor allowed during the install of the operator (tokenSource.oauthTokenUrls in the Helm chart).`
+
+	warningIgnoredTokensSecret = `The DynaKube's specification has a token source, the tokens secret set in tokens is ignored.`
+
+	errorTelemetryIngestWithTokenSource = `The DynaKube's specification has a token source and telemetryIngest enabled, the OpenTelemetry collector can only read the data ingest token from the tokens secret.`
+)
+
+func invalidTokenSource(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	source := dk.Spec.TokenSource
+	if source == nil {
+		return ""
+	}
+
+	switch {
+	case source.File == nil && source.Vault == nil && source.OAuth == nil:
+		log.Info("requested dynakube has an empty token source", "name", dk.Name, "namespace", dk.Namespace)
+
+		return errorMissingTokenSource
+	case source.File != nil && source.Vault != nil:
+		log.Info("requested dynakube has conflicting token sources", "name", dk.Name, "namespace", dk.Namespace)
+
+		return errorConflictingTokenSources
+	case source.File != nil && !filepath.IsAbs(source.File.Path):
+		log.Info("requested dynakube has a relative token source path", "name", dk.Name, "namespace", dk.Namespace)
+
+		return errorRelativeTokenSourcePath
+	case source.File != nil && !tokensource.IsBelowFileBasePath(source.File.Path):
+		log.Info("requested dynakube has a token source path outside of the token volumes", "name", dk.Name, "namespace", dk.Namespace)
+
+		return errorTokenSourcePathOutsideBase
+	case source.Vault != nil && installconfig.GetTokenSource().VaultAddress == "":
+		log.Info("requested dynakube has a vault token source, but no vault address was installed", "name", dk.Name, "namespace", dk.Namespace)
+
+		return errorVaultAddressNotInstalled
+	case source.OAuth != nil && !installconfig.GetTokenSource().IsAllowedOAuthTokenURL(source.OAuth.GetTokenURL()):
+		log.Info("requested dynakube has a token endpoint that is not allowed", "name", dk.Name, "namespace", dk.Namespace)
+
+		return errorOAuthTokenURLNotAllowed
+	}
+
+	return ""
+}
+
+func ignoredTokensSecret(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if dk.Spec.TokenSource != nil && dk.Spec.Tokens != "" {
+		return warningIgnoredTokensSecret
+	}
+
+	return ""
+}
+
+func telemetryIngestWithTokenSource(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	if dk.Spec.TokenSource != nil && dk.TelemetryIngest().IsEnabled() {
+		log.Info("requested dynakube has a token source and telemetryIngest enabled", "name", dk.Name, "namespace", dk.Namespace)
+
+		return errorTelemetryIngestWithTokenSource
+	}
+
+	return ""
+}
@@ -0,0 +1,88 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/telemetryingest"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+)
+
+func TestTokenSource(t *testing.T) {
+	createDynakube := func(source tokensource.Spec) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+				},
+				TokenSource: &source,
+			},
+		}
+	}
+
+	t.Run("valid sources", func(t *testing.T) {
+		installconfig.SetTokenSourceOverride(t, installconfig.TokenSource{VaultAddress: "http://127.0.0.1:8200"})
+
+		assertAllowedWithoutWarnings(t, createDynakube(tokensource.Spec{File: &tokensource.File{Path: "/var/run/secrets/dynatrace.com/tokens/dynakube"}}))
+		assertAllowedWithoutWarnings(t, createDynakube(tokensource.Spec{
+			Vault: &tokensource.Vault{Path: "dynatrace/tokens"},
+			OAuth: &tokensource.OAuth{ClientID: "dt0s02.client"},
+		}))
+	})
+
+	t.Run("empty source", func(t *testing.T) {
+		assertDenied(t, []string{errorMissingTokenSource}, createDynakube(tokensource.Spec{}))
+	})
+
+	t.Run("file and vault", func(t *testing.T) {
+		assertDenied(t, []string{errorConflictingTokenSources}, createDynakube(tokensource.Spec{
+			File:  &tokensource.File{Path: "/tokens"},
+			Vault: &tokensource.Vault{Path: "dynatrace/tokens"},
+		}))
+	})
+
+	t.Run("relative path", func(t *testing.T) {
+		assertDenied(t, []string{errorRelativeTokenSourcePath}, createDynakube(tokensource.Spec{File: &tokensource.File{Path: "tokens"}}))
+	})
+
+	t.Run("vault without installed address", func(t *testing.T) {
+		assertDenied(t, []string{errorVaultAddressNotInstalled}, createDynakube(tokensource.Spec{
+			Vault: &tokensource.Vault{Path: "dynatrace/tokens"},
+		}))
+	})
+
+	t.Run("token endpoint not allowed", func(t *testing.T) {
+		assertDenied(t, []string{errorOAuthTokenURLNotAllowed}, createDynakube(tokensource.Spec{
+			OAuth: &tokensource.OAuth{ClientID: "dt0s02.client", TokenURL: "https://attacker.example.com/token"},
+		}))
+
+		installconfig.SetTokenSourceOverride(t, installconfig.TokenSource{OAuthTokenURLs: []string{"https://sso-dev.dynatracelabs.com/sso/oauth2/token"}})
+
+		assertAllowedWithoutWarnings(t, createDynakube(tokensource.Spec{
+			OAuth: &tokensource.OAuth{ClientID: "dt0s02.client", TokenURL: "https://sso-dev.dynatracelabs.com/sso/oauth2/token"},
+		}))
+	})
+
+	t.Run("path outside of the token volumes", func(t *testing.T) {
+		assertDenied(t, []string{errorTokenSourcePathOutsideBase}, createDynakube(tokensource.Spec{File: &tokensource.File{Path: "/etc"}}))
+		assertDenied(t, []string{errorTokenSourcePathOutsideBase}, createDynakube(tokensource.Spec{File: &tokensource.File{Path: "/var/run/secrets/dynatrace.com/tokens/../../kubernetes.io"}}))
+	})
+
+	t.Run("telemetryIngest with token source", func(t *testing.T) {
+		dk := createDynakube(tokensource.Spec{OAuth: &tokensource.OAuth{ClientID: "dt0s02.client"}})
+		dk.Spec.TelemetryIngest = &telemetryingest.Spec{}
+
+		assertDenied(t, []string{errorTelemetryIngestWithTokenSource}, dk)
+	})
+
+	t.Run("tokens secret is ignored", func(t *testing.T) {
+		dk := createDynakube(tokensource.Spec{File: &tokensource.File{Path: "/var/run/secrets/dynatrace.com/tokens/dynakube"}})
+		dk.Spec.Tokens = "tokens"
+
+		assertAllowedWithWarnings(t, 1, dk)
+	})
+}
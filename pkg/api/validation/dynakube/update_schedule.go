@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+)
+
+const (
+	errorInvalidOneAgentUpdateSchedule = `The DynaKube's specification has an invalid updateSchedule for the OneAgent: `
+
+	errorInvalidActiveGateUpdateSchedule = `The DynaKube's specification has an invalid updateSchedule for the ActiveGate: `
+)
+
+func invalidOneAgentUpdateSchedule(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	return validateUpdateSchedule(dk.Spec.OneAgent.UpdateSchedule, errorInvalidOneAgentUpdateSchedule)
+}
+
+func invalidActiveGateUpdateSchedule(_ context.Context, _ *Validator, dk *dynakube.DynaKube) string {
+	return validateUpdateSchedule(dk.Spec.ActiveGate.UpdateSchedule, errorInvalidActiveGateUpdateSchedule)
+}
+
+func validateUpdateSchedule(schedule *maintenance.Schedule, message string) string {
+	if schedule == nil {
+		return ""
+	}
+
+	if err := schedule.Validate(); err != nil {
+		return message + err.Error()
+	}
+
+	return ""
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateSchedule(t *testing.T) {
+	validSchedule := &maintenance.Schedule{
+		TimeZone: "Europe/Vienna",
+		Windows:  []maintenance.Window{{Start: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}}},
+	}
+	invalidSchedule := &maintenance.Schedule{
+		TimeZone: "Europe/Atlantis",
+		Windows:  []maintenance.Window{{Start: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}}},
+	}
+
+	createDynakube := func(oneAgentSchedule, activeGateSchedule *maintenance.Schedule) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: defaultDynakubeObjectMeta,
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: testApiUrl,
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+					UpdateSchedule:   oneAgentSchedule,
+				},
+				ActiveGate: activegate.Spec{
+					Capabilities:   []activegate.CapabilityDisplayName{activegate.RoutingCapability.DisplayName},
+					UpdateSchedule: activeGateSchedule,
+				},
+			},
+		}
+	}
+
+	t.Run("valid schedules", func(t *testing.T) {
+		assertAllowed(t, createDynakube(validSchedule, validSchedule))
+	})
+
+	t.Run("invalid OneAgent schedule", func(t *testing.T) {
+		assertDenied(t, []string{errorInvalidOneAgentUpdateSchedule}, createDynakube(invalidSchedule, validSchedule))
+	})
+
+	t.Run("invalid ActiveGate schedule", func(t *testing.T) {
+		assertDenied(t, []string{errorInvalidActiveGateUpdateSchedule}, createDynakube(nil, invalidSchedule))
+	})
+}
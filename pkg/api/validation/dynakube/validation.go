@@ -46,6 +46,8 @@ var (
 		noResourcesAvailable,
 		imageFieldSetWithoutCSIFlag,
 		missingCodeModulesImage,
+		invalidCodeModulesBundle,
+		invalidCodeModulesVerification,
 		conflictingOneAgentVolumeStorageSettings,
 		nameViolatesDNS1035,
 		nameTooLong,
@@ -59,9 +61,22 @@ var (
 		emptyTelemetryIngestProtocolsList,
 		unknownTelemetryIngestProtocols,
 		duplicatedTelemetryIngestProtocols,
+		invalidTelemetryIngestPipelines,
 		invalidTelemetryIngestName,
 		forbiddenTelemetryIngestServiceNameSuffix,
 		conflictingTelemetryIngestServiceNames,
+		missingEgressParentRef,
+		invalidMetadataEnrichmentRules,
+		duplicateMetadataEnrichmentRuleTargets,
+		rolloutWithoutCanary,
+		invalidRolloutNamespaceSelector,
+		invalidOneAgentUpdateSchedule,
+		invalidActiveGateUpdateSchedule,
+		keylessWithoutTrustedRoot,
+		invalidKeylessIdentity,
+		invalidRegistryMirrorRules,
+		invalidTokenSource,
+		telemetryIngestWithTokenSource,
 	}
 	validatorWarningFuncs = []validatorFunc{
 		missingActiveGateMemoryLimit,
@@ -73,6 +88,8 @@ var (
 		logMonitoringWithoutK8SMonitoring,
 		kspmWithoutK8SMonitoring,
 		extensionsWithoutK8SMonitoring,
+		ignoredEgressParentRef,
+		ignoredTokensSecret,
 	}
 	updateValidatorErrorFuncs = []updateValidatorFunc{
 		IsMutatedApiUrl,
@@ -0,0 +1,23 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha2/edgeconnect"
+)
+
+const (
+	errorInvalidUpdateSchedule = `The EdgeConnect's specification has an invalid updateSchedule: `
+)
+
+func invalidUpdateSchedule(_ context.Context, _ *Validator, edgeConnectCR *edgeconnect.EdgeConnect) string {
+	if edgeConnectCR.Spec.UpdateSchedule == nil {
+		return ""
+	}
+
+	if err := edgeConnectCR.Spec.UpdateSchedule.Validate(); err != nil {
+		return errorInvalidUpdateSchedule + err.Error()
+	}
+
+	return ""
+}
@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1alpha2/edgeconnect"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateSchedule(t *testing.T) {
+	createEdgeConnect := func(schedule *maintenance.Schedule) *edgeconnect.EdgeConnect {
+		return &edgeconnect.EdgeConnect{
+			Spec: edgeconnect.EdgeConnectSpec{
+				ApiServer:      "tenant.apps.dynatrace.com",
+				UpdateSchedule: schedule,
+			},
+		}
+	}
+
+	t.Run("valid schedule", func(t *testing.T) {
+		assertAllowed(t, createEdgeConnect(&maintenance.Schedule{
+			TimeZone: "Europe/Vienna",
+			Windows:  []maintenance.Window{{Start: "0 22 * * 1-5", Duration: metav1.Duration{Duration: 4 * time.Hour}}},
+		}))
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		assertDenied(t, []string{errorInvalidUpdateSchedule}, createEdgeConnect(&maintenance.Schedule{
+			Windows: []maintenance.Window{{Start: "every night", Duration: metav1.Duration{Duration: 4 * time.Hour}}},
+		}))
+	})
+}
@@ -29,6 +29,7 @@ var validatorErrorFuncs = []validatorFunc{
 	checkHostPatternsValue,
 	isInvalidServiceName,
 	automationRequiresProvisionerValidation,
+	invalidUpdateSchedule,
 }
 
 func New(apiReader client.Reader, cfg *rest.Config) admission.CustomValidator {
@@ -66,11 +66,10 @@ func (dtc *dynatraceClient) createAuthTokenRequest(ctx context.Context, dynakube
 		return nil, err
 	}
 
-	request, err := createBaseRequest(
+	request, err := dtc.createBaseRequest(
 		ctx,
 		dtc.getActiveGateAuthTokenUrl(),
 		http.MethodPost,
-		dtc.apiToken,
 		bytes.NewReader(bodyData),
 	)
 	if err != nil {
@@ -60,8 +60,8 @@ type Client interface {
 	// Returns an error in case the lookup failed.
 	GetEntityIDForIP(ctx context.Context, ip string) (string, error)
 
-	// GetTokenScopes returns the list of scopes assigned to a token if successful.
-	GetTokenScopes(ctx context.Context, token string) (TokenScopes, error)
+	// GetTokenInfo returns the scopes assigned to a token and its expiration date if successful.
+	GetTokenInfo(ctx context.Context, token string) (TokenInfo, error)
 
 	// GetActiveGateConnectionInfo returns AgentTenantInfo for ActiveGate that holds UUID, Tenant Token and Endpoints
 	GetActiveGateConnectionInfo(ctx context.Context) (ActiveGateConnectionInfo, error)
@@ -155,6 +155,9 @@ func NewClient(url, apiToken, paasToken string, opts ...Option) (Client, error)
 		apiToken:  apiToken,
 		paasToken: paasToken,
 
+		apiTokenHeader:  ApiTokenHeader,
+		paasTokenHeader: ApiTokenHeader,
+
 		hostCache: make(map[string]hostInfo),
 		httpClient: &http.Client{
 			Transport: http.DefaultTransport.(*http.Transport).Clone(),
@@ -238,6 +241,20 @@ func Certs(certs []byte) Option {
 	}
 }
 
+// BearerApiToken creates an Option that sends the API token as bearer token, as needed for OAuth access tokens.
+func BearerApiToken() Option {
+	return func(c *dynatraceClient) {
+		c.apiTokenHeader = BearerTokenHeader
+	}
+}
+
+// BearerPaasToken creates an Option that sends the PaaS token as bearer token, as needed for OAuth access tokens.
+func BearerPaasToken() Option {
+	return func(c *dynatraceClient) {
+		c.paasTokenHeader = BearerTokenHeader
+	}
+}
+
 func NetworkZone(networkZone string) Option {
 	return func(c *dynatraceClient) {
 		c.networkZone = networkZone
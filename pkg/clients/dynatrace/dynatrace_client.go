@@ -14,7 +14,12 @@ import (
 	"github.com/pkg/errors"
 )
 
-const ApiTokenHeader = "Api-Token "
+const (
+	ApiTokenHeader = "Api-Token "
+
+	// BearerTokenHeader is used for OAuth access tokens.
+	BearerTokenHeader = "Bearer "
+)
 
 type HostNotFoundErr struct {
 	IP string
@@ -43,6 +48,9 @@ type dynatraceClient struct {
 	apiToken  string
 	paasToken string
 
+	apiTokenHeader  string
+	paasTokenHeader string
+
 	networkZone string
 
 	hostGroup string
@@ -73,13 +81,13 @@ func (dtc *dynatraceClient) makeRequest(ctx context.Context, url string, tokenTy
 			return nil, errors.Errorf("not able to set token since api token is empty for request: %s", url)
 		}
 
-		authHeader = ApiTokenHeader + dtc.apiToken
+		authHeader = dtc.apiTokenHeader + dtc.apiToken
 	case dynatracePaaSToken:
 		if dtc.paasToken == "" {
 			return nil, errors.Errorf("not able to set token since paas token is empty for request: %s", url)
 		}
 
-		authHeader = ApiTokenHeader + dtc.paasToken
+		authHeader = dtc.paasTokenHeader + dtc.paasToken
 	case installerUrlToken:
 		return dtc.httpClient.Do(req)
 	default:
@@ -91,14 +99,14 @@ func (dtc *dynatraceClient) makeRequest(ctx context.Context, url string, tokenTy
 	return dtc.httpClient.Do(req)
 }
 
-func createBaseRequest(ctx context.Context, url, method, apiToken string, body io.Reader) (*http.Request, error) {
+func (dtc *dynatraceClient) createBaseRequest(ctx context.Context, url, method string, body io.Reader) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error initializing http request")
 	}
 
 	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Authorization", ApiTokenHeader+apiToken)
+	req.Header.Add("Authorization", dtc.apiTokenHeader+dtc.apiToken)
 
 	if method == http.MethodPost {
 		req.Header.Add("Content-Type", "application/json")
@@ -65,6 +65,48 @@ func TestMakeRequest(t *testing.T) {
 	})
 }
 
+func TestAuthorizationHeader(t *testing.T) {
+	ctx := context.Background()
+
+	var authorization string
+
+	dynatraceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer dynatraceServer.Close()
+
+	t.Run("api token", func(t *testing.T) {
+		dc, err := NewClient(dynatraceServer.URL, apiToken, paasToken)
+		require.NoError(t, err)
+
+		resp, err := dc.(*dynatraceClient).makeRequest(ctx, dynatraceServer.URL, dynatraceApiToken)
+		require.NoError(t, err)
+
+		defer utils.CloseBodyAfterRequest(resp)
+
+		assert.Equal(t, ApiTokenHeader+apiToken, authorization)
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		dc, err := NewClient(dynatraceServer.URL, apiToken, paasToken, BearerApiToken())
+		require.NoError(t, err)
+
+		resp, err := dc.(*dynatraceClient).makeRequest(ctx, dynatraceServer.URL, dynatraceApiToken)
+		require.NoError(t, err)
+
+		defer utils.CloseBodyAfterRequest(resp)
+
+		assert.Equal(t, BearerTokenHeader+apiToken, authorization)
+
+		resp, err = dc.(*dynatraceClient).makeRequest(ctx, dynatraceServer.URL, dynatracePaaSToken)
+		require.NoError(t, err)
+
+		defer utils.CloseBodyAfterRequest(resp)
+
+		assert.Equal(t, ApiTokenHeader+paasToken, authorization)
+	})
+}
+
 func TestGetResponseOrServerError(t *testing.T) {
 	ctx := context.Background()
 
@@ -162,7 +204,7 @@ func TestDynatraceClientWithServer(t *testing.T) {
 	testActiveGateVersionGetLatestActiveGateVersion(t, dtc)
 	testCommunicationHostsGetCommunicationHosts(t, dtc)
 	testSendEvent(t, dtc)
-	testGetTokenScopes(t, dtc)
+	testGetTokenInfo(t, dtc)
 
 	testServerErrors(t)
 }
@@ -198,7 +240,7 @@ func handleRequest(request *http.Request, writer http.ResponseWriter) {
 	case "/v1/events":
 		handleSendEvent(request, writer)
 	case "/v2/apiTokens/lookup":
-		handleTokenScopes(request, writer)
+		handleTokenInfo(request, writer)
 	default:
 		writeError(writer, http.StatusBadRequest)
 	}
@@ -106,11 +106,10 @@ func (dtc *dynatraceClient) createLatestImageRequest(ctx context.Context, url st
 		return nil, err
 	}
 
-	request, err := createBaseRequest(
+	request, err := dtc.createBaseRequest(
 		ctx,
 		url,
 		http.MethodGet,
-		dtc.apiToken,
 		bytes.NewReader(bodyData),
 	)
 
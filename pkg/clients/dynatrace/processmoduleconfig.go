@@ -197,7 +197,7 @@ func (dtc *dynatraceClient) createProcessModuleConfigRequest(ctx context.Context
 
 	req.URL.RawQuery = query.Encode()
 	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", ApiTokenHeader+dtc.paasToken)
+	req.Header.Add("Authorization", dtc.paasTokenHeader+dtc.paasToken)
 
 	return req, nil
 }
@@ -12,16 +12,19 @@ import (
 
 const (
 	MarkedForTerminationEvent = "MARKED_FOR_TERMINATION"
+	AvailabilityEvent         = "AVAILABILITY_EVENT"
 )
 
 // EventData struct which defines what event payload should contain
 type EventData struct {
-	EventType     string               `json:"eventType"`
-	Description   string               `json:"description"`
-	Source        string               `json:"source"`
-	AttachRules   EventDataAttachRules `json:"attachRules"`
-	StartInMillis uint64               `json:"start"`
-	EndInMillis   uint64               `json:"end"`
+	CustomProperties map[string]string    `json:"customProperties,omitempty"`
+	EventType        string               `json:"eventType"`
+	Title            string               `json:"title,omitempty"`
+	Description      string               `json:"description"`
+	Source           string               `json:"source"`
+	AttachRules      EventDataAttachRules `json:"attachRules"`
+	StartInMillis    uint64               `json:"start"`
+	EndInMillis      uint64               `json:"end"`
 }
 
 type EventDataAttachRules struct {
@@ -48,7 +51,7 @@ func (dtc *dynatraceClient) SendEvent(ctx context.Context, eventData *EventData)
 	}
 
 	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Authorization", ApiTokenHeader+dtc.apiToken)
+	req.Header.Add("Authorization", dtc.apiTokenHeader+dtc.apiToken)
 
 	response, err := dtc.httpClient.Do(req)
 	if err != nil {
@@ -58,7 +58,7 @@ func (dtc *dynatraceClient) GetMonitoredEntitiesForKubeSystemUUID(ctx context.Co
 		return nil, errors.New("no kube-system namespace UUID given")
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getEntitiesUrl(), http.MethodGet, dtc.apiToken, nil)
+	req, err := dtc.createBaseRequest(ctx, dtc.getEntitiesUrl(), http.MethodGet, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +94,7 @@ func (dtc *dynatraceClient) GetSettingsForMonitoredEntity(ctx context.Context, m
 		return GetSettingsResponse{TotalCount: 0}, nil
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getSettingsUrl(true), http.MethodGet, dtc.apiToken, nil)
+	req, err := dtc.createBaseRequest(ctx, dtc.getSettingsUrl(true), http.MethodGet, nil)
 	if err != nil {
 		return GetSettingsResponse{}, err
 	}
@@ -128,7 +128,7 @@ func (dtc *dynatraceClient) GetSettingsForLogModule(ctx context.Context, monitor
 		return GetLogMonSettingsResponse{TotalCount: 0}, nil
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getSettingsUrl(true), http.MethodGet, dtc.apiToken, nil)
+	req, err := dtc.createBaseRequest(ctx, dtc.getSettingsUrl(true), http.MethodGet, nil)
 	if err != nil {
 		return GetLogMonSettingsResponse{}, err
 	}
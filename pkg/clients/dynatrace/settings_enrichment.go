@@ -42,7 +42,7 @@ func (dtc *dynatraceClient) GetRulesSettings(ctx context.Context, kubeSystemUUID
 		scope = globalScope
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getEffectiveSettingsUrl(true), http.MethodGet, dtc.apiToken, nil)
+	req, err := dtc.createBaseRequest(ctx, dtc.getEffectiveSettingsUrl(true), http.MethodGet, nil)
 	if err != nil {
 		return GetRulesSettingsResponse{}, err
 	}
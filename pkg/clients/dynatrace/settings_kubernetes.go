@@ -57,7 +57,7 @@ func (dtc *dynatraceClient) performCreateOrUpdateKubernetesSetting(ctx context.C
 		return "", err
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getSettingsUrl(false), http.MethodPost, dtc.apiToken, bytes.NewReader(bodyData))
+	req, err := dtc.createBaseRequest(ctx, dtc.getSettingsUrl(false), http.MethodPost, bytes.NewReader(bodyData))
 	if err != nil {
 		return "", err
 	}
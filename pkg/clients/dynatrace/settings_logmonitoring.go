@@ -48,7 +48,7 @@ func (dtc *dynatraceClient) performCreateLogMonSetting(ctx context.Context, body
 		return "", err
 	}
 
-	req, err := createBaseRequest(ctx, dtc.getSettingsUrl(false), http.MethodPost, dtc.apiToken, bytes.NewReader(bodyData))
+	req, err := dtc.createBaseRequest(ctx, dtc.getSettingsUrl(false), http.MethodPost, bytes.NewReader(bodyData))
 	if err != nil {
 		return "", err
 	}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/clients/utils"
 	"github.com/pkg/errors"
@@ -24,7 +25,14 @@ func (s TokenScopes) Contains(scope string) bool {
 	return false
 }
 
-func (dtc *dynatraceClient) GetTokenScopes(ctx context.Context, token string) (TokenScopes, error) {
+// TokenInfo is what the Dynatrace API knows about a token.
+type TokenInfo struct {
+	// ExpirationDate is nil, if the token doesn't expire.
+	ExpirationDate *time.Time  `json:"expirationDate,omitempty"`
+	Scopes         TokenScopes `json:"scopes"`
+}
+
+func (dtc *dynatraceClient) GetTokenInfo(ctx context.Context, token string) (TokenInfo, error) {
 	var model struct {
 		Token string `json:"token"`
 	}
@@ -33,12 +41,12 @@ func (dtc *dynatraceClient) GetTokenScopes(ctx context.Context, token string) (T
 
 	jsonStr, err := json.Marshal(model)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return TokenInfo{}, errors.WithStack(err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dtc.getTokensLookupUrl(), bytes.NewBuffer(jsonStr))
 	if err != nil {
-		return nil, errors.WithMessage(err, "error initializing http request")
+		return TokenInfo{}, errors.WithMessage(err, "error initializing http request")
 	}
 
 	req.Header.Add("Content-Type", "application/json")
@@ -46,29 +54,27 @@ func (dtc *dynatraceClient) GetTokenScopes(ctx context.Context, token string) (T
 
 	resp, err := dtc.httpClient.Do(req)
 	if err != nil {
-		return nil, errors.WithMessage(err, "error making post request to dynatrace api")
+		return TokenInfo{}, errors.WithMessage(err, "error making post request to dynatrace api")
 	}
 
 	defer utils.CloseBodyAfterRequest(resp)
 
 	data, err := dtc.getServerResponseData(resp)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return TokenInfo{}, errors.WithStack(err)
 	}
 
-	return dtc.readResponseForTokenScopes(data)
+	return dtc.readResponseForTokenInfo(data)
 }
 
-func (dtc *dynatraceClient) readResponseForTokenScopes(response []byte) (TokenScopes, error) {
-	var jr struct {
-		Scopes []string `json:"scopes"`
-	}
+func (dtc *dynatraceClient) readResponseForTokenInfo(response []byte) (TokenInfo, error) {
+	var info TokenInfo
 
-	if err := json.Unmarshal(response, &jr); err != nil {
-		log.Error(err, "unable to unmarshal token scopes response", "response", string(response))
+	if err := json.Unmarshal(response, &info); err != nil {
+		log.Error(err, "unable to unmarshal token lookup response", "response", string(response))
 
-		return nil, err
+		return TokenInfo{}, err
 	}
 
-	return jr.Scopes, nil
+	return info, nil
 }
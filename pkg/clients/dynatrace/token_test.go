@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -22,24 +23,32 @@ func TestToken_Contains(t *testing.T) {
 	assert.False(t, tokenscopes.Contains("invalid-scope"))
 }
 
-func testGetTokenScopes(t *testing.T, dynatraceClient Client) {
+func testGetTokenInfo(t *testing.T, dynatraceClient Client) {
 	ctx := context.Background()
 
 	t.Run("happy path", func(t *testing.T) {
-		scopes, err := dynatraceClient.GetTokenScopes(ctx, "good-token")
+		info, err := dynatraceClient.GetTokenInfo(ctx, "good-token")
 		require.NoError(t, err)
-		assert.ElementsMatch(t, []string{"DataExport", "LogExport"}, scopes)
+		assert.ElementsMatch(t, []string{"DataExport", "LogExport"}, info.Scopes)
+		assert.Nil(t, info.ExpirationDate)
+	})
+
+	t.Run("expiring token", func(t *testing.T) {
+		info, err := dynatraceClient.GetTokenInfo(ctx, "expiring-token")
+		require.NoError(t, err)
+		require.NotNil(t, info.ExpirationDate)
+		assert.Equal(t, time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC), info.ExpirationDate.UTC())
 	})
 
 	t.Run("sad path", func(t *testing.T) {
-		scopes, err := dynatraceClient.GetTokenScopes(ctx, "bad-token")
-		assert.Nil(t, scopes)
+		info, err := dynatraceClient.GetTokenInfo(ctx, "bad-token")
+		assert.Nil(t, info.Scopes)
 		require.Error(t, err)
 		assert.Exactly(t, ServerError{Code: 401, Message: "error received from server"}, errors.Cause(err))
 	})
 }
 
-func handleTokenScopes(request *http.Request, writer http.ResponseWriter) {
+func handleTokenInfo(request *http.Request, writer http.ResponseWriter) {
 	var model struct {
 		Token string `json:"token"`
 	}
@@ -76,6 +85,16 @@ func handleTokenScopes(request *http.Request, writer http.ResponseWriter) {
 				"LogExport"
 			]
 		}`))
+	case "expiring-token":
+		writer.WriteHeader(http.StatusOK)
+		_, _ = writer.Write([]byte(`{
+			"id": "dt0c01.ABC",
+			"name": "the-expiring-token",
+			"expirationDate": "2026-11-01T12:00:00.000Z",
+			"scopes": [
+				"DataExport"
+			]
+		}`))
 	default:
 		writeError(writer, http.StatusUnauthorized)
 	}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates/issuer"
 	k8ssecret "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/secret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	"github.com/pkg/errors"
@@ -84,6 +85,32 @@ func (certSecret *certificateSecret) validateCertificates(namespace string) erro
 	return nil
 }
 
+// issueCertificates gets the certificates from the issuer of the certificate source configured during install,
+// the CA it returns as trust bundle is injected into the webhook configurations the same way as the built-in one.
+func (certSecret *certificateSecret) issueCertificates(ctx context.Context, certIssuer issuer.Issuer, namespace string) error {
+	domain := getDomain(namespace)
+
+	data, err := certIssuer.Issue(ctx, issuer.Request{
+		Owner:       certSecret.owner,
+		Namespace:   namespace,
+		SecretName:  buildSecretName(),
+		CommonName:  domain,
+		DNSNames:    []string{domain},
+		Duration:    serverCertValidity,
+		RenewBefore: renewalThreshold,
+	}, certSecret.secret.Data)
+	if err != nil {
+		return err
+	}
+
+	certSecret.certificates = &Certs{
+		Domain: domain,
+		Data:   data,
+	}
+
+	return nil
+}
+
 func buildSecretName() string {
 	return fmt.Sprintf("%s%s", webhook.DeploymentName, secretPostfix)
 }
@@ -19,7 +19,8 @@ const intSerialNumberLimit = 128
 var serialNumberLimit = new(big.Int).Lsh(big.NewInt(1), intSerialNumberLimit)
 
 const (
-	renewalThreshold = 12 * time.Hour
+	renewalThreshold   = 12 * time.Hour
+	serverCertValidity = 7 * 24 * time.Hour
 
 	RootKey     = "ca.key"
 	RootCert    = "ca.crt"
@@ -206,7 +207,7 @@ func (cs *Certs) generateServerCerts(domain string, now time.Time) error {
 		DNSNames: []string{domain},
 
 		NotBefore: now,
-		NotAfter:  now.Add(7 * 24 * time.Hour),
+		NotAfter:  now.Add(serverCertValidity),
 
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates/issuer"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/eventfilter"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	"github.com/pkg/errors"
@@ -20,7 +21,8 @@ import (
 )
 
 const (
-	SuccessDuration = 3 * time.Hour
+	SuccessDuration        = 3 * time.Hour
+	NotIssuedRetryDuration = 30 * time.Second
 
 	dkCrdName                    = "dynakubes.dynatrace.com"
 	ecCrdName                    = "edgeconnects.dynatrace.com"
@@ -92,11 +94,25 @@ func (controller *WebhookCertificateController) Reconcile(ctx context.Context, r
 		return reconcile.Result{}, errors.WithStack(err)
 	}
 
-	err = certSecret.validateCertificates(controller.namespace)
+	certIssuer, err := issuer.New(controller.client, controller.apiReader, controller.namespace)
 	if err != nil {
 		return reconcile.Result{}, errors.WithStack(err)
 	}
 
+	if certIssuer == nil {
+		err = certSecret.validateCertificates(controller.namespace)
+	} else {
+		err = certSecret.issueCertificates(ctx, certIssuer, controller.namespace)
+	}
+
+	if errors.Is(err, issuer.ErrNotIssued) {
+		log.Info("waiting for the certificates to be issued")
+
+		return reconcile.Result{RequeueAfter: NotIssuedRetryDuration}, nil
+	} else if err != nil {
+		return reconcile.Result{}, errors.WithStack(err)
+	}
+
 	mutatingWebhookClientConfigs := getClientConfigsFromMutatingWebhook(mutatingWebhookConfiguration)
 	validatingWebhookConfigConfigs := getClientConfigsFromValidatingWebhook(validatingWebhookConfiguration)
 
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates/issuer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,6 +15,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -258,6 +261,68 @@ func createValidTestCertData(_ *testing.T) map[string][]byte {
 	return cert.Data
 }
 
+func TestReconcileCertificate_Issuer(t *testing.T) {
+	issuedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      expectedSecretName + issuer.IssuedSecretSuffix,
+			Namespace: testNamespace,
+		},
+		Data: map[string][]byte{
+			ServerCert: []byte("issued-cert"),
+			ServerKey:  []byte("issued-key"),
+			RootCert:   []byte("corporate-ca"),
+		},
+	}
+
+	t.Run("certificates are copied from the issuer and their CA is injected", func(t *testing.T) {
+		installconfig.SetCertificateSourceOverride(t, installconfig.CertificateSource{
+			Type:   installconfig.CertManagerCertificateSource,
+			Issuer: &installconfig.IssuerRef{Name: "corporate", Kind: installconfig.ClusterIssuerKind},
+		})
+
+		clt := newFakeClientBuilder().WithCRD().Build()
+		require.NoError(t, clt.Create(context.Background(), issuedSecret.DeepCopy()))
+
+		controller, request := prepareController(clt)
+
+		res, err := controller.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		assert.Equal(t, SuccessDuration, res.RequeueAfter)
+
+		secret := &corev1.Secret{}
+		err = clt.Get(context.Background(), client.ObjectKey{Name: expectedSecretName, Namespace: testNamespace}, secret)
+		require.NoError(t, err)
+		assert.Equal(t, issuedSecret.Data, secret.Data)
+
+		mutatingWebhookConfig := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		err = clt.Get(context.Background(), client.ObjectKey{Name: webhook.DeploymentName}, mutatingWebhookConfig)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("corporate-ca"), mutatingWebhookConfig.Webhooks[0].ClientConfig.CABundle)
+
+		crd := &apiv1.CustomResourceDefinition{}
+		err = clt.Get(context.Background(), client.ObjectKey{Name: dkCrdName}, crd)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("corporate-ca"), crd.Spec.Conversion.Webhook.ClientConfig.CABundle)
+	})
+
+	t.Run("reconcile is retried until the certificates are issued", func(t *testing.T) {
+		installconfig.SetCertificateSourceOverride(t, installconfig.CertificateSource{
+			Type:   installconfig.CertManagerCertificateSource,
+			Issuer: &installconfig.IssuerRef{Name: "corporate"},
+		})
+
+		clt := newFakeClientBuilder().WithCRD().Build()
+		controller, request := prepareController(clt)
+
+		res, err := controller.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		assert.Equal(t, NotIssuedRetryDuration, res.RequeueAfter)
+
+		err = clt.Get(context.Background(), client.ObjectKey{Name: expectedSecretName, Namespace: testNamespace}, &corev1.Secret{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+}
+
 func createTestSecret(_ *testing.T, certData map[string][]byte) *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
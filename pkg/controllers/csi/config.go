@@ -30,6 +30,11 @@ const (
 	SharedAppMountsDir   = "appmounts"
 	SharedDynaKubesDir   = "_dynakubes"
 	SharedAgentConfigDir = "config"
+	SharedQuarantineDir  = "_quarantine"
+	InventoryFileName    = "inventory.json"
+
+	// FallbackMarkerFileName marks a volume without CodeModules, the init-container has to download them instead
+	FallbackMarkerFileName = ".dynatrace-download-fallback"
 
 	DaemonSetName = "dynatrace-oneagent-csi-driver"
 
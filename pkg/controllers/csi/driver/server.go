@@ -56,6 +56,7 @@ type Server struct {
 	mounter mount.Interface
 
 	publishers map[string]csivolumes.Publisher
+	inventory  *metadata.InventoryStore
 	opts       dtcsi.CSIOptions
 	path       metadata.PathResolver
 }
@@ -64,11 +65,15 @@ var _ csi.IdentityServer = &Server{}
 var _ csi.NodeServer = &Server{}
 
 func NewServer(opts dtcsi.CSIOptions) *Server {
+	fs := afero.NewOsFs()
+	path := metadata.PathResolver{RootDir: opts.RootDir}
+
 	return &Server{
-		opts:    opts,
-		fs:      afero.Afero{Fs: afero.NewOsFs()},
-		mounter: mount.New(""),
-		path:    metadata.PathResolver{RootDir: opts.RootDir},
+		opts:      opts,
+		fs:        afero.Afero{Fs: fs},
+		mounter:   mount.New(""),
+		path:      path,
+		inventory: metadata.NewInventoryStore(fs, path),
 	}
 }
 
@@ -92,7 +97,7 @@ func (srv *Server) Start(ctx context.Context) error {
 	}
 
 	srv.publishers = map[string]csivolumes.Publisher{
-		appvolumes.Mode:  appvolumes.NewPublisher(srv.fs, srv.mounter, srv.path),
+		appvolumes.Mode:  appvolumes.NewPublisher(srv.fs, srv.mounter, srv.path, srv.inventory),
 		hostvolumes.Mode: hostvolumes.NewPublisher(srv.fs, srv.mounter, srv.path),
 	}
 
@@ -200,11 +205,15 @@ func (srv *Server) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpubli
 }
 
 func (srv *Server) unmount(volumeInfo csivolumes.VolumeInfo) {
+	defer srv.removeVolumeFromInventory(volumeInfo)
+
 	// targetPath always needs to be unmounted
 	if err := srv.mounter.Unmount(volumeInfo.TargetPath); err != nil {
 		log.Error(err, "Unmount failed", "path", volumeInfo.TargetPath)
 	}
 
+	srv.removeFallbackVolume(volumeInfo)
+
 	appMountDir := srv.path.AppMountForID(volumeInfo.VolumeID)
 
 	mappedDir := srv.path.AppMountMappedDir(volumeInfo.VolumeID) // Unmount follows symlinks, so no need to check for them here
@@ -250,6 +259,31 @@ func (srv *Server) unmount(volumeInfo csivolumes.VolumeInfo) {
 	}
 }
 
+// removeFallbackVolume removes the contents of a fallback volume, which is a plain directory and not a mount, so the kubelet can remove the target path.
+// The marker is only visible if nothing is mounted at the target path, so mounted volumes are never touched.
+func (srv *Server) removeFallbackVolume(volumeInfo csivolumes.VolumeInfo) {
+	marker := filepath.Join(volumeInfo.TargetPath, dtcsi.FallbackMarkerFileName)
+	if exists, _ := srv.fs.Exists(marker); !exists {
+		return
+	}
+
+	if err := srv.fs.RemoveAll(volumeInfo.TargetPath); err != nil {
+		log.Error(err, "failed to remove fallback volume", "path", volumeInfo.TargetPath)
+	}
+}
+
+// removeVolumeFromInventory drops the reference of the volume to its CodeModules, so the cleanup of the csi-provisioner can remove them once unused.
+func (srv *Server) removeVolumeFromInventory(volumeInfo csivolumes.VolumeInfo) {
+	err := srv.inventory.Update(func(inventory *metadata.Inventory) error {
+		inventory.RemoveVolume(volumeInfo.VolumeID)
+
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "failed to remove volume from the inventory", "volumeID", volumeInfo.VolumeID)
+	}
+}
+
 func (srv *Server) findPodInfoSymlink(volumeInfo csivolumes.VolumeInfo) string {
 	podInfoPath := srv.path.OverlayVarPodInfo(volumeInfo.VolumeID)
 
@@ -278,11 +312,43 @@ func (srv *Server) NodeGetInfo(context.Context, *csi.NodeGetInfoRequest) (*csi.N
 }
 
 func (srv *Server) NodeGetCapabilities(context.Context, *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
-	return &csi.NodeGetCapabilitiesResponse{Capabilities: []*csi.NodeServiceCapability{}}, nil
+	return &csi.NodeGetCapabilitiesResponse{Capabilities: []*csi.NodeServiceCapability{
+		newNodeServiceCapability(csi.NodeServiceCapability_RPC_GET_VOLUME_STATS),
+		newNodeServiceCapability(csi.NodeServiceCapability_RPC_VOLUME_CONDITION),
+	}}, nil
 }
 
-func (srv *Server) NodeGetVolumeStats(context.Context, *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "")
+func newNodeServiceCapability(rpcType csi.NodeServiceCapability_RPC_Type) *csi.NodeServiceCapability {
+	return &csi.NodeServiceCapability{
+		Type: &csi.NodeServiceCapability_Rpc{
+			Rpc: &csi.NodeServiceCapability_RPC{Type: rpcType},
+		},
+	}
+}
+
+// NodeGetVolumeStats is called periodically by the kubelet, the usage ends up in the volume metrics of the kubelet and abnormal volumes in the events of the pod.
+// The mode of the volume isn't part of the request, app volumes are recognized by their app mount dir.
+func (srv *Server) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
+	volumeInfo, err := csivolumes.ParseNodeGetVolumeStatsRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists, _ := srv.fs.DirExists(volumeInfo.TargetPath); !exists {
+		return nil, status.Error(codes.NotFound, "volume path not found: "+volumeInfo.TargetPath)
+	}
+
+	mode := hostvolumes.Mode
+	if exists, _ := srv.fs.DirExists(srv.path.AppMountForID(volumeInfo.VolumeID)); exists {
+		mode = appvolumes.Mode
+	}
+
+	publisher, ok := srv.publishers[mode]
+	if !ok {
+		return nil, status.Error(codes.Internal, "unknown csi mode, mode="+mode)
+	}
+
+	return publisher.GetVolumeStats(ctx, volumeInfo)
 }
 
 func (srv *Server) NodeExpandVolume(context.Context, *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
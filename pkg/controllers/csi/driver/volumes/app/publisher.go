@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 	"time"
 
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
 	csivolumes "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/driver/volumes"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/symlink"
@@ -36,20 +37,22 @@ import (
 	mount "k8s.io/mount-utils"
 )
 
-func NewPublisher(fs afero.Afero, mounter mount.Interface, path metadata.PathResolver) csivolumes.Publisher {
+func NewPublisher(fs afero.Afero, mounter mount.Interface, path metadata.PathResolver, inventory *metadata.InventoryStore) csivolumes.Publisher {
 	return &Publisher{
-		fs:      fs,
-		mounter: mounter,
-		path:    path,
-		time:    timeprovider.New(),
+		fs:        fs,
+		mounter:   mounter,
+		path:      path,
+		inventory: inventory,
+		time:      timeprovider.New(),
 	}
 }
 
 type Publisher struct {
-	fs      afero.Afero
-	mounter mount.Interface
-	time    *timeprovider.Provider
-	path    metadata.PathResolver
+	fs        afero.Afero
+	mounter   mount.Interface
+	time      *timeprovider.Provider
+	inventory *metadata.InventoryStore
+	path      metadata.PathResolver
 }
 
 func (pub *Publisher) PublishVolume(ctx context.Context, volumeCfg *csivolumes.VolumeConfig) (*csi.NodePublishVolumeResponse, error) {
@@ -60,6 +63,12 @@ func (pub *Publisher) PublishVolume(ctx context.Context, volumeCfg *csivolumes.V
 	}
 
 	if !pub.isCodeModuleAvailable(volumeCfg) {
+		if pub.isInstallRefused(volumeCfg) {
+			log.Info("CodeModule install was refused because of the disk quota, attaching fallback volume, init-container will download the CodeModule", "pod", volumeCfg.PodName)
+
+			return &csi.NodePublishVolumeResponse{}, pub.prepareFallbackVolume(volumeCfg)
+		}
+
 		return nil, status.Error(
 			codes.Unavailable,
 			"version or digest is not yet set, csi-provisioner hasn't finished setup yet for DynaKube: "+volumeCfg.DynakubeName,
@@ -101,9 +110,9 @@ func (pub *Publisher) hasRetryLimitReached(volumeCfg *csivolumes.VolumeConfig) b
 	return pub.time.Now().After(limit)
 }
 
-// isCodeModuleAvailable checks if the LatestAgentBinaryForDynaKube folder exists or not
+// isCodeModuleAvailable checks if the LatestAgentBinaryForDynaKube (or CanaryAgentBinaryForDynaKube) folder exists or not
 func (pub *Publisher) isCodeModuleAvailable(volumeCfg *csivolumes.VolumeConfig) bool {
-	binDir := pub.path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName)
+	binDir := pub.getAgentBinaryDir(volumeCfg)
 
 	stat, err := pub.fs.Stat(binDir)
 	if errors.Is(err, os.ErrNotExist) {
@@ -119,6 +128,41 @@ func (pub *Publisher) isCodeModuleAvailable(volumeCfg *csivolumes.VolumeConfig)
 	return stat.IsDir()
 }
 
+// getAgentBinaryDir returns the symlink to the CodeModules of the volume, pods in the canary namespaces of a rollout get the candidate
+func (pub *Publisher) getAgentBinaryDir(volumeCfg *csivolumes.VolumeConfig) string {
+	if volumeCfg.Canary {
+		return pub.path.CanaryAgentBinaryForDynaKube(volumeCfg.DynakubeName)
+	}
+
+	return pub.path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName)
+}
+
+// isInstallRefused checks if the csi-provisioner refused to install the CodeModule for the DynaKube, because it would not fit into the disk quota
+func (pub *Publisher) isInstallRefused(volumeCfg *csivolumes.VolumeConfig) bool {
+	inventory, err := pub.inventory.Read()
+	if err != nil {
+		log.Error(err, "failed to read the inventory", "dynakube", volumeCfg.DynakubeName)
+
+		return false
+	}
+
+	return inventory.IsRefused(volumeCfg.DynakubeName)
+}
+
+// prepareFallbackVolume leaves the volume as an empty directory, that is marked so the init-container downloads the CodeModule into it, like without the CSI driver
+func (pub *Publisher) prepareFallbackVolume(volumeCfg *csivolumes.VolumeConfig) error {
+	if err := pub.fs.MkdirAll(volumeCfg.TargetPath, os.ModePerm); err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("failed to create fallback volume: %s", err))
+	}
+
+	marker := filepath.Join(volumeCfg.TargetPath, dtcsi.FallbackMarkerFileName)
+	if err := pub.fs.WriteFile(marker, nil, os.ModePerm); err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("failed to mark fallback volume: %s", err))
+	}
+
+	return nil
+}
+
 func (pub *Publisher) mountCodeModule(volumeCfg *csivolumes.VolumeConfig) error {
 	mappedDir := pub.path.AppMountMappedDir(volumeCfg.VolumeID)
 
@@ -139,7 +183,7 @@ func (pub *Publisher) mountCodeModule(volumeCfg *csivolumes.VolumeConfig) error
 		return err
 	}
 
-	lowerDir := pub.path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName)
+	lowerDir := pub.getAgentBinaryDir(volumeCfg)
 
 	linker, ok := pub.fs.Fs.(afero.LinkReader)
 	if ok { // will only be !ok during unit testing
@@ -161,12 +205,19 @@ func (pub *Publisher) mountCodeModule(volumeCfg *csivolumes.VolumeConfig) error
 		return err
 	}
 
+	if err := pub.addVolumeToInventory(volumeCfg, lowerDir); err != nil {
+		return err
+	}
+
 	if err := pub.mounter.Mount("overlay", mappedDir, "overlay", overlayOptions); err != nil {
+		pub.removeVolumeFromInventory(volumeCfg)
+
 		return err
 	}
 
 	if err := pub.mounter.Mount(mappedDir, volumeCfg.TargetPath, "", []string{"bind"}); err != nil {
 		_ = pub.mounter.Unmount(mappedDir)
+		pub.removeVolumeFromInventory(volumeCfg)
 
 		return err
 	}
@@ -179,6 +230,39 @@ func (pub *Publisher) mountCodeModule(volumeCfg *csivolumes.VolumeConfig) error
 	return nil
 }
 
+// addVolumeToInventory records that the volume references the CodeModules in lowerDir, before it is mounted.
+// It is checked under the lock of the inventory that the CodeModules still exist, so the cleanup can't remove them in between.
+func (pub *Publisher) addVolumeToInventory(volumeCfg *csivolumes.VolumeConfig, lowerDir string) error {
+	return pub.inventory.Update(func(inventory *metadata.Inventory) error {
+		if exists, _ := pub.fs.DirExists(lowerDir); !exists {
+			return errors.Errorf("CodeModules %s were removed in the meantime", lowerDir)
+		}
+
+		agent := filepath.Base(lowerDir)
+
+		inventory.AddVolume(volumeCfg.VolumeID, metadata.VolumeEntry{
+			DynaKube:     volumeCfg.DynakubeName,
+			Agent:        agent,
+			PodName:      volumeCfg.PodName,
+			PodNamespace: volumeCfg.PodNamespace,
+		})
+		inventory.MarkUsed(agent, pub.time.Now().Time)
+
+		return nil
+	})
+}
+
+func (pub *Publisher) removeVolumeFromInventory(volumeCfg *csivolumes.VolumeConfig) {
+	err := pub.inventory.Update(func(inventory *metadata.Inventory) error {
+		inventory.RemoveVolume(volumeCfg.VolumeID)
+
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "failed to remove volume from the inventory", "volumeID", volumeCfg.VolumeID)
+	}
+}
+
 func (pub *Publisher) addPodInfoSymlink(volumeCfg *csivolumes.VolumeConfig) error {
 	appMountPodInfoDir := pub.path.AppMountPodInfoDir(volumeCfg.DynakubeName, volumeCfg.PodNamespace, volumeCfg.PodName)
 	if err := pub.fs.MkdirAll(appMountPodInfoDir, os.ModePerm); err != nil {
@@ -3,9 +3,11 @@ package app
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
 	csivolumes "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/driver/volumes"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
@@ -24,7 +26,7 @@ func TestPublishVolume(t *testing.T) {
 		mounter := mount.NewFakeMounter([]mount.MountPoint{})
 		volumeCfg := getTestVolumeConfig(t)
 
-		pub := NewPublisher(fs, mounter, path)
+		pub := NewPublisher(fs, mounter, path, metadata.NewInventoryStore(fs.Fs, path))
 
 		resp, err := pub.PublishVolume(ctx, &volumeCfg)
 		require.NoError(t, err)
@@ -58,7 +60,7 @@ func TestPublishVolume(t *testing.T) {
 		mounter := mount.NewFakeMounter([]mount.MountPoint{})
 		volumeCfg := getTestVolumeConfig(t)
 
-		pub := NewPublisher(fs, mounter, path)
+		pub := NewPublisher(fs, mounter, path, metadata.NewInventoryStore(fs.Fs, path))
 
 		resp, err := pub.PublishVolume(ctx, &volumeCfg)
 		require.Error(t, err)
@@ -67,6 +69,29 @@ func TestPublishVolume(t *testing.T) {
 		assert.Empty(t, mounter.MountPoints)
 	})
 
+	t.Run("early return - install refused because of quota => fallback volume", func(t *testing.T) {
+		fs := getTestFs(t)
+		mounter := mount.NewFakeMounter([]mount.MountPoint{})
+		volumeCfg := getTestVolumeConfig(t)
+		inventory := metadata.NewInventoryStore(fs.Fs, path)
+		require.NoError(t, inventory.Update(func(inventory *metadata.Inventory) error {
+			inventory.SetRefused(volumeCfg.DynakubeName, "1.2.3")
+
+			return nil
+		}))
+
+		pub := NewPublisher(fs, mounter, path, inventory)
+
+		resp, err := pub.PublishVolume(ctx, &volumeCfg)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+
+		assert.Empty(t, mounter.MountPoints)
+
+		exists, _ := fs.Exists(filepath.Join(volumeCfg.TargetPath, dtcsi.FallbackMarkerFileName))
+		assert.True(t, exists)
+	})
+
 	t.Run("early return (with error) - binary is just a file", func(t *testing.T) {
 		fs := getTestFs(t)
 		mounter := mount.NewFakeMounter([]mount.MountPoint{})
@@ -75,7 +100,7 @@ func TestPublishVolume(t *testing.T) {
 		require.NoError(t, err)
 		require.NoError(t, file.Close())
 
-		pub := NewPublisher(fs, mounter, path)
+		pub := NewPublisher(fs, mounter, path, metadata.NewInventoryStore(fs.Fs, path))
 
 		resp, err := pub.PublishVolume(ctx, &volumeCfg)
 		require.Error(t, err)
@@ -92,7 +117,7 @@ func TestPublishVolume(t *testing.T) {
 		binaryDir := path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName)
 		require.NoError(t, fs.MkdirAll(binaryDir, os.ModePerm))
 
-		pub := NewPublisher(fs, mounter, path)
+		pub := NewPublisher(fs, mounter, path, metadata.NewInventoryStore(fs.Fs, path))
 
 		resp, err := pub.PublishVolume(ctx, &volumeCfg)
 		require.Error(t, err)
@@ -115,7 +140,7 @@ func TestPublishVolume(t *testing.T) {
 		conf := []byte("testing")
 		require.NoError(t, fs.WriteFile(confFile, conf, os.ModePerm))
 
-		pub := NewPublisher(fs, mounter, path)
+		pub := NewPublisher(fs, mounter, path, metadata.NewInventoryStore(fs.Fs, path))
 
 		resp, err := pub.PublishVolume(ctx, &volumeCfg)
 		require.NoError(t, err)
@@ -161,6 +186,68 @@ func TestPublishVolume(t *testing.T) {
 		bindMount := mounter.MountPoints[1]
 		assert.Equal(t, "overlay", bindMount.Device) // this is set to "overlay" by the FakeMounter to mimic a linux FS
 		assert.Equal(t, volumeCfg.TargetPath, bindMount.Path)
+
+		// Volume recorded in the inventory
+		inventory, err := metadata.NewInventoryStore(fs.Fs, path).Read()
+		require.NoError(t, err)
+		require.Contains(t, inventory.Volumes, volumeCfg.VolumeID)
+		assert.Equal(t, volumeCfg.DynakubeName, inventory.Volumes[volumeCfg.VolumeID].DynaKube)
+	})
+
+	t.Run("canary volume => candidate is mounted", func(t *testing.T) {
+		fs := getTestFs(t)
+		mounter := mount.NewFakeMounter([]mount.MountPoint{})
+		volumeCfg := getTestVolumeConfig(t)
+		volumeCfg.Canary = true
+
+		require.NoError(t, fs.MkdirAll(path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName), os.ModePerm))
+
+		canaryDir := path.CanaryAgentBinaryForDynaKube(volumeCfg.DynakubeName)
+		require.NoError(t, fs.MkdirAll(canaryDir, os.ModePerm))
+		require.NoError(t, fs.WriteFile(path.AgentSharedRuxitAgentProcConf(volumeCfg.DynakubeName), []byte("testing"), os.ModePerm))
+
+		pub := NewPublisher(fs, mounter, path, metadata.NewInventoryStore(fs.Fs, path))
+
+		_, err := pub.PublishVolume(ctx, &volumeCfg)
+		require.NoError(t, err)
+
+		require.Len(t, mounter.MountPoints, 2)
+		assert.Contains(t, mounter.MountPoints[0].Opts[0], canaryDir) // lowerdir
+	})
+
+	t.Run("canary volume, candidate not yet installed => error, nothing mounted", func(t *testing.T) {
+		fs := getTestFs(t)
+		mounter := mount.NewFakeMounter([]mount.MountPoint{})
+		volumeCfg := getTestVolumeConfig(t)
+		volumeCfg.Canary = true
+
+		require.NoError(t, fs.MkdirAll(path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName), os.ModePerm))
+
+		pub := NewPublisher(fs, mounter, path, metadata.NewInventoryStore(fs.Fs, path))
+
+		_, err := pub.PublishVolume(ctx, &volumeCfg)
+		require.Error(t, err)
+		assert.Empty(t, mounter.MountPoints)
+	})
+
+	t.Run("CodeModules removed before mount => error, nothing mounted", func(t *testing.T) {
+		fs := getTestFs(t)
+		mounter := mount.NewFakeMounter([]mount.MountPoint{})
+		volumeCfg := getTestVolumeConfig(t)
+
+		pub := &Publisher{
+			fs:        fs,
+			mounter:   mounter,
+			path:      path,
+			inventory: metadata.NewInventoryStore(fs.Fs, path),
+		}
+
+		err := pub.addVolumeToInventory(&volumeCfg, path.AgentSharedBinaryDirForAgent("removed"))
+		require.Error(t, err)
+
+		inventory, err := pub.inventory.Read()
+		require.NoError(t, err)
+		assert.Empty(t, inventory.Volumes)
 	})
 }
 
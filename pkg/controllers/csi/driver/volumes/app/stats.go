@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	csivolumes "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/driver/volumes"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/spf13/afero"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	mount "k8s.io/mount-utils"
+)
+
+// GetVolumeStats reports the usage of the upper dir of the overlay, where the agent writes its logs, and checks that the overlay is still intact.
+// Volumes without CodeModules, like the fallback volume or the dummy volume after the mount attempts ran out, only report the usage of the target path.
+func (pub *Publisher) GetVolumeStats(_ context.Context, volumeInfo csivolumes.VolumeInfo) (*csi.NodeGetVolumeStatsResponse, error) {
+	mappedDir := pub.path.AppMountMappedDir(volumeInfo.VolumeID)
+
+	if exists, _ := pub.fs.DirExists(mappedDir); !exists {
+		usage, err := csivolumes.GetVolumeUsage(pub.fs, volumeInfo.TargetPath)
+		if err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get usage of volume: %s", err))
+		}
+
+		return &csi.NodeGetVolumeStatsResponse{
+			Usage:           usage,
+			VolumeCondition: &csi.VolumeCondition{Message: "no CodeModules are mounted into the volume"},
+		}, nil
+	}
+
+	usage, err := csivolumes.GetVolumeUsage(pub.fs, pub.path.AppMountVarDir(volumeInfo.VolumeID))
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get usage of overlay upper dir: %s", err))
+	}
+
+	problems := []string{}
+	problems = append(problems, pub.checkOverlayMount(mappedDir)...)
+	problems = append(problems, pub.checkLowerDir(volumeInfo.VolumeID)...)
+	problems = append(problems, pub.checkPodInfoSymlink(volumeInfo.VolumeID)...)
+
+	if len(problems) > 0 {
+		log.Info("app volume is abnormal", "volumeID", volumeInfo.VolumeID, "problems", problems)
+	}
+
+	return &csi.NodeGetVolumeStatsResponse{
+		Usage:           usage,
+		VolumeCondition: csivolumes.NewVolumeCondition(problems),
+	}, nil
+}
+
+func (pub *Publisher) checkOverlayMount(mappedDir string) []string {
+	mountPoints, err := pub.mounter.List()
+	if err != nil {
+		log.Error(err, "failed to list mount points")
+
+		return nil
+	}
+
+	if !slices.ContainsFunc(mountPoints, func(mountPoint mount.MountPoint) bool { return mountPoint.Path == mappedDir }) {
+		return []string{"overlay mount is missing"}
+	}
+
+	return nil
+}
+
+func (pub *Publisher) checkLowerDir(volumeID string) []string {
+	inventory, err := pub.inventory.Read()
+	if err != nil {
+		log.Error(err, "failed to read the inventory", "volumeID", volumeID)
+
+		return nil
+	}
+
+	volume, ok := inventory.Volumes[volumeID]
+	if !ok {
+		return []string{"volume is missing from the inventory"}
+	}
+
+	lowerDir := pub.path.AgentSharedBinaryDirForAgent(volume.Agent)
+
+	if isEmpty, err := pub.fs.IsEmpty(lowerDir); err != nil || isEmpty {
+		return []string{fmt.Sprintf("CodeModules %s used as lower dir are missing", volume.Agent)}
+	}
+
+	return nil
+}
+
+// checkPodInfoSymlink checks that the pod-info symlink, that the pod-info file of the volume names, still points to the volume.
+func (pub *Publisher) checkPodInfoSymlink(volumeID string) []string {
+	podInfo, err := pub.fs.ReadFile(pub.path.OverlayVarPodInfo(volumeID))
+	if err != nil {
+		return []string{"pod-info file is missing"}
+	}
+
+	linker, ok := pub.fs.Fs.(afero.LinkReader)
+	if !ok { // will only be !ok during unit testing
+		return nil
+	}
+
+	symlinkPath := string(podInfo)
+
+	target, err := linker.ReadlinkIfPossible(symlinkPath)
+	if err != nil || target != pub.path.AppMountForID(volumeID) {
+		return []string{fmt.Sprintf("pod-info symlink %s is stale", symlinkPath)}
+	}
+
+	return nil
+}
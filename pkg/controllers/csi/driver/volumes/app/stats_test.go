@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/mount-utils"
+)
+
+func TestGetVolumeStats(t *testing.T) {
+	ctx := context.Background()
+	path := metadata.PathResolver{}
+
+	setupPublishedVolume := func(t *testing.T) (*Publisher, *mount.FakeMounter) {
+		fs := getTestFs(t)
+		mounter := mount.NewFakeMounter([]mount.MountPoint{})
+		volumeCfg := getTestVolumeConfig(t)
+
+		binaryDir := path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName)
+		require.NoError(t, fs.MkdirAll(binaryDir, os.ModePerm))
+		require.NoError(t, fs.WriteFile(path.AgentSharedRuxitAgentProcConf(volumeCfg.DynakubeName), []byte("testing"), os.ModePerm))
+
+		// the lower dir is resolved via the symlink on the real filesystem, the inventory names the agent it points to
+		lowerDir := path.AgentSharedBinaryDirForAgent(filepath.Base(binaryDir))
+		require.NoError(t, fs.WriteFile(filepath.Join(lowerDir, "agent.so"), []byte("agent"), os.ModePerm))
+
+		pub := NewPublisher(fs, mounter, path, metadata.NewInventoryStore(fs.Fs, path)).(*Publisher)
+
+		_, err := pub.PublishVolume(ctx, &volumeCfg)
+		require.NoError(t, err)
+
+		return pub, mounter
+	}
+
+	t.Run("usage of the upper dir of a healthy volume", func(t *testing.T) {
+		pub, _ := setupPublishedVolume(t)
+		volumeCfg := getTestVolumeConfig(t)
+
+		require.NoError(t, pub.fs.WriteFile(filepath.Join(path.AppMountVarDir(volumeCfg.VolumeID), "log", "agent.log"), []byte("0123456789"), os.ModePerm))
+
+		resp, err := pub.GetVolumeStats(ctx, volumeCfg.VolumeInfo)
+		require.NoError(t, err)
+
+		podInfo := path.AppMountPodInfoDir(volumeCfg.DynakubeName, volumeCfg.PodNamespace, volumeCfg.PodName)
+		expectedBytes := int64(len("testing") + len(podInfo) + len("0123456789"))
+
+		require.Len(t, resp.GetUsage(), 2)
+		assert.Equal(t, csi.VolumeUsage_BYTES, resp.GetUsage()[0].GetUnit())
+		assert.Equal(t, expectedBytes, resp.GetUsage()[0].GetUsed())
+		assert.Equal(t, csi.VolumeUsage_INODES, resp.GetUsage()[1].GetUnit())
+		assert.Positive(t, resp.GetUsage()[1].GetUsed())
+
+		assert.False(t, resp.GetVolumeCondition().GetAbnormal())
+	})
+
+	t.Run("broken overlay is abnormal", func(t *testing.T) {
+		pub, mounter := setupPublishedVolume(t)
+		volumeCfg := getTestVolumeConfig(t)
+
+		require.NoError(t, mounter.Unmount(path.AppMountMappedDir(volumeCfg.VolumeID)))
+		require.NoError(t, pub.fs.RemoveAll(path.AgentSharedBinaryDirForAgent(filepath.Base(path.LatestAgentBinaryForDynaKube(volumeCfg.DynakubeName)))))
+		require.NoError(t, pub.fs.Remove(path.OverlayVarPodInfo(volumeCfg.VolumeID)))
+
+		resp, err := pub.GetVolumeStats(ctx, volumeCfg.VolumeInfo)
+		require.NoError(t, err)
+
+		condition := resp.GetVolumeCondition()
+		assert.True(t, condition.GetAbnormal())
+		assert.Contains(t, condition.GetMessage(), "overlay mount is missing")
+		assert.Contains(t, condition.GetMessage(), "used as lower dir are missing")
+		assert.Contains(t, condition.GetMessage(), "pod-info file is missing")
+	})
+
+	t.Run("volume without CodeModules reports the target path", func(t *testing.T) {
+		fs := getTestFs(t)
+		volumeCfg := getTestVolumeConfig(t)
+		require.NoError(t, fs.WriteFile(filepath.Join(volumeCfg.TargetPath, "fallback"), []byte("12345"), os.ModePerm))
+
+		pub := NewPublisher(fs, mount.NewFakeMounter([]mount.MountPoint{}), path, metadata.NewInventoryStore(fs.Fs, path))
+
+		resp, err := pub.GetVolumeStats(ctx, volumeCfg.VolumeInfo)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(5), resp.GetUsage()[0].GetUsed())
+		assert.False(t, resp.GetVolumeCondition().GetAbnormal())
+	})
+}
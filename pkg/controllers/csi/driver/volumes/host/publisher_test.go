@@ -2,6 +2,7 @@ package host
 
 import (
 	"context"
+	"os"
 	"testing"
 	"time"
 
@@ -50,6 +51,41 @@ func TestPublishVolume(t *testing.T) {
 	})
 }
 
+func TestGetVolumeStats(t *testing.T) {
+	ctx := context.Background()
+	path := metadata.PathResolver{}
+
+	t.Run("usage of the osagent storage", func(t *testing.T) {
+		fs := getTestFs(t)
+		mounter := mount.NewFakeMounter([]mount.MountPoint{})
+		volumeCfg := getTestVolumeConfig(t)
+		pub := NewPublisher(fs, mounter, path)
+
+		_, err := pub.PublishVolume(ctx, &volumeCfg)
+		require.NoError(t, err)
+
+		resp, err := pub.GetVolumeStats(ctx, volumeCfg.VolumeInfo)
+		require.NoError(t, err)
+
+		require.Len(t, resp.GetUsage(), 2)
+		assert.False(t, resp.GetVolumeCondition().GetAbnormal())
+	})
+
+	t.Run("missing mount is abnormal", func(t *testing.T) {
+		fs := getTestFs(t)
+		volumeCfg := getTestVolumeConfig(t)
+		require.NoError(t, fs.MkdirAll(volumeCfg.TargetPath, os.ModePerm))
+
+		pub := NewPublisher(fs, mount.NewFakeMounter([]mount.MountPoint{}), path)
+
+		resp, err := pub.GetVolumeStats(ctx, volumeCfg.VolumeInfo)
+		require.NoError(t, err)
+
+		assert.True(t, resp.GetVolumeCondition().GetAbnormal())
+		assert.Equal(t, "osagent storage mount is missing", resp.GetVolumeCondition().GetMessage())
+	})
+}
+
 func getTestFs(t *testing.T) afero.Afero {
 	t.Helper()
 
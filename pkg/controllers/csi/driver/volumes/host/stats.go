@@ -0,0 +1,35 @@
+package host
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	csivolumes "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/driver/volumes"
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/mount-utils"
+)
+
+// GetVolumeStats reports the usage of the osagent storage, that is bind mounted to the target path.
+func (pub *Publisher) GetVolumeStats(_ context.Context, volumeInfo csivolumes.VolumeInfo) (*csi.NodeGetVolumeStatsResponse, error) {
+	usage, err := csivolumes.GetVolumeUsage(pub.fs, volumeInfo.TargetPath)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get usage of volume: %s", err))
+	}
+
+	problems := []string{}
+
+	mountPoints, err := pub.mounter.List()
+	if err != nil {
+		log.Error(err, "failed to list mount points")
+	} else if !slices.ContainsFunc(mountPoints, func(mountPoint mount.MountPoint) bool { return mountPoint.Path == volumeInfo.TargetPath }) {
+		problems = append(problems, "osagent storage mount is missing")
+	}
+
+	return &csi.NodeGetVolumeStatsResponse{
+		Usage:           usage,
+		VolumeCondition: csivolumes.NewVolumeCondition(problems),
+	}, nil
+}
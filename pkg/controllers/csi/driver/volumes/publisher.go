@@ -8,4 +8,7 @@ import (
 
 type Publisher interface {
 	PublishVolume(ctx context.Context, volumeCfg *VolumeConfig) (*csi.NodePublishVolumeResponse, error)
+
+	// GetVolumeStats reports the usage of a published volume and if it is abnormal.
+	GetVolumeStats(ctx context.Context, volumeInfo VolumeInfo) (*csi.NodeGetVolumeStatsResponse, error)
 }
@@ -0,0 +1,63 @@
+package csivolumes
+
+import (
+	"os"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"golang.org/x/sys/unix"
+)
+
+// GetVolumeUsage returns the bytes and inodes used by the files in the given directory.
+// Files removed while walking the directory, like rotated agent logs, are skipped.
+// The capacity of the underlying filesystem is only known on the real filesystem, otherwise it is left empty.
+func GetVolumeUsage(fs afero.Afero, dir string) ([]*csi.VolumeUsage, error) {
+	var usedBytes, usedInodes int64
+
+	err := afero.Walk(fs, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path != dir {
+				return nil
+			}
+
+			return err
+		}
+
+		usedInodes++
+
+		if info.Mode().IsRegular() {
+			usedBytes += info.Size()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	bytesUsage := &csi.VolumeUsage{Unit: csi.VolumeUsage_BYTES, Used: usedBytes}
+	inodesUsage := &csi.VolumeUsage{Unit: csi.VolumeUsage_INODES, Used: usedInodes}
+
+	if _, ok := fs.Fs.(*afero.OsFs); ok {
+		var stat unix.Statfs_t
+		if err := unix.Statfs(dir, &stat); err == nil {
+			bytesUsage.Total = int64(stat.Blocks) * stat.Bsize     //nolint:gosec
+			bytesUsage.Available = int64(stat.Bavail) * stat.Bsize //nolint:gosec
+			inodesUsage.Total = int64(stat.Files)                  //nolint:gosec
+			inodesUsage.Available = int64(stat.Ffree)              //nolint:gosec
+		}
+	}
+
+	return []*csi.VolumeUsage{bytesUsage, inodesUsage}, nil
+}
+
+// NewVolumeCondition reports the volume as abnormal, if any problems were found.
+func NewVolumeCondition(problems []string) *csi.VolumeCondition {
+	if len(problems) == 0 {
+		return &csi.VolumeCondition{Message: "volume is healthy"}
+	}
+
+	return &csi.VolumeCondition{Abnormal: true, Message: strings.Join(problems, "; ")}
+}
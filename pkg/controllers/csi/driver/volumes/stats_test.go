@@ -0,0 +1,76 @@
+package csivolumes
+
+import (
+	"os"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVolumeUsage(t *testing.T) {
+	t.Run("files and dirs are counted", func(t *testing.T) {
+		fs := afero.Afero{Fs: afero.NewMemMapFs()}
+		require.NoError(t, fs.WriteFile("/volume/log/agent.log", []byte("0123456789"), os.ModePerm))
+		require.NoError(t, fs.WriteFile("/volume/pod-info", []byte("01234"), os.ModePerm))
+
+		usage, err := GetVolumeUsage(fs, "/volume")
+		require.NoError(t, err)
+
+		require.Len(t, usage, 2)
+		assert.Equal(t, &csi.VolumeUsage{Unit: csi.VolumeUsage_BYTES, Used: 15}, usage[0])
+		assert.Equal(t, &csi.VolumeUsage{Unit: csi.VolumeUsage_INODES, Used: 4}, usage[1])
+	})
+
+	t.Run("capacity of the real filesystem", func(t *testing.T) {
+		fs := afero.Afero{Fs: afero.NewOsFs()}
+
+		usage, err := GetVolumeUsage(fs, t.TempDir())
+		require.NoError(t, err)
+
+		assert.Positive(t, usage[0].GetTotal())
+		assert.Positive(t, usage[1].GetTotal())
+	})
+
+	t.Run("files removed while walking are skipped", func(t *testing.T) {
+		memFs := afero.NewMemMapFs()
+		fs := afero.Afero{Fs: removedFileFs{Fs: memFs, removed: "/volume/log/rotated.log"}}
+		require.NoError(t, fs.WriteFile("/volume/log/agent.log", []byte("0123456789"), os.ModePerm))
+		require.NoError(t, fs.WriteFile("/volume/log/rotated.log", []byte("01234"), os.ModePerm))
+
+		usage, err := GetVolumeUsage(fs, "/volume")
+		require.NoError(t, err)
+
+		assert.Equal(t, &csi.VolumeUsage{Unit: csi.VolumeUsage_BYTES, Used: 10}, usage[0])
+		assert.Equal(t, &csi.VolumeUsage{Unit: csi.VolumeUsage_INODES, Used: 3}, usage[1])
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		_, err := GetVolumeUsage(afero.Afero{Fs: afero.NewMemMapFs()}, "/volume")
+		require.Error(t, err)
+	})
+}
+
+// removedFileFs lists the removed file in its directory, but fails to stat it, as if it was removed in between.
+type removedFileFs struct {
+	afero.Fs
+	removed string
+}
+
+func (fs removedFileFs) Stat(name string) (os.FileInfo, error) {
+	if name == fs.removed {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+
+	return fs.Fs.Stat(name)
+}
+
+func TestNewVolumeCondition(t *testing.T) {
+	assert.False(t, NewVolumeCondition(nil).GetAbnormal())
+
+	condition := NewVolumeCondition([]string{"overlay mount is missing", "pod-info file is missing"})
+	assert.True(t, condition.GetAbnormal())
+	assert.Equal(t, "overlay mount is missing; pod-info file is missing", condition.GetMessage())
+}
@@ -17,6 +17,8 @@ const (
 	CSIVolumeAttributeModeField     = "mode"
 	CSIVolumeAttributeDynakubeField = "dynakube"
 	CSIVolumeAttributeRetryTimeout  = "retryTimeout"
+	// CSIVolumeAttributeCanaryField marks volumes of pods in the canary namespaces of a CodeModules rollout, they get the candidate
+	CSIVolumeAttributeCanaryField = "canary"
 )
 
 // Represents the basic information about a volume
@@ -33,6 +35,7 @@ type VolumeConfig struct {
 	Mode         string
 	DynakubeName string
 	RetryTimeout time.Duration
+	Canary       bool
 }
 
 // Transforms the NodePublishVolumeRequest into a VolumeConfig
@@ -102,6 +105,7 @@ func ParseNodePublishVolumeRequest(req *csi.NodePublishVolumeRequest) (VolumeCon
 	}
 
 	volumeConfig.RetryTimeout = retryTimeout
+	volumeConfig.Canary = volCtx[CSIVolumeAttributeCanaryField] == "true"
 
 	return volumeConfig, nil
 }
@@ -111,6 +115,19 @@ func ParseNodeUnpublishVolumeRequest(req *csi.NodeUnpublishVolumeRequest) (Volum
 	return newVolumeInfo(req)
 }
 
+// Transforms the NodeGetVolumeStatsRequest into a VolumeInfo, the volume path is the path the volume was published to
+func ParseNodeGetVolumeStatsRequest(req *csi.NodeGetVolumeStatsRequest) (VolumeInfo, error) {
+	return newVolumeInfo(volumeStatsRequest{req})
+}
+
+type volumeStatsRequest struct {
+	*csi.NodeGetVolumeStatsRequest
+}
+
+func (req volumeStatsRequest) GetTargetPath() string {
+	return req.GetVolumePath()
+}
+
 type baseRequest interface {
 	GetVolumeId() string
 	GetTargetPath() string
@@ -210,3 +210,24 @@ func TestCSIDriverServer_ParsePublishVolumeRequest(t *testing.T) {
 		assert.NotNil(t, volumeCfg.RetryTimeout)
 	})
 }
+
+func TestCSIDriverServer_ParseNodeGetVolumeStatsRequest(t *testing.T) {
+	t.Run("No volume path", func(t *testing.T) {
+		request := &csi.NodeGetVolumeStatsRequest{
+			VolumeId: testVolumeId,
+		}
+		_, err := ParseNodeGetVolumeStatsRequest(request)
+
+		require.EqualError(t, err, "rpc error: code = InvalidArgument desc = Target path missing in request")
+	})
+	t.Run("happy path", func(t *testing.T) {
+		request := &csi.NodeGetVolumeStatsRequest{
+			VolumeId:   testVolumeId,
+			VolumePath: testTargetPath,
+		}
+		volumeInfo, err := ParseNodeGetVolumeStatsRequest(request)
+
+		require.NoError(t, err)
+		assert.Equal(t, VolumeInfo{VolumeID: testVolumeId, TargetPath: testTargetPath}, volumeInfo)
+	})
+}
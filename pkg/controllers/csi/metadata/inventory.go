@@ -0,0 +1,397 @@
+package metadata
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Inventory is the node-local record of the installed CodeModules and of what references them.
+// It is shared between the csi-server, which records the published volumes, and the csi-provisioner,
+// which records the installed CodeModules and removes the ones that are no longer referenced.
+type Inventory struct {
+	// Agents are the installed CodeModules, keyed by the name of their directory (version or digest) in the shared binary directory.
+	Agents map[string]AgentEntry `json:"agents"`
+	// Volumes are the published app volumes, keyed by their volume ID.
+	Volumes map[string]VolumeEntry `json:"volumes"`
+	// Latest is the CodeModules currently provided for each DynaKube, keyed by the name of the DynaKube.
+	Latest map[string]string `json:"latest"`
+	// Canary is the candidate of the CodeModules rollout provided for the canary namespaces of each DynaKube, keyed by the name of the DynaKube.
+	Canary map[string]string `json:"canary,omitempty"`
+	// Refused is the CodeModules that were not installed for a DynaKube because of the disk quota, keyed by the name of the DynaKube.
+	Refused map[string]string `json:"refused,omitempty"`
+	// OtherSizeBytes is the disk usage of everything else in the data directory, like the upper dirs of the app-mounts and the config of the DynaKubes.
+	// It is measured by the cleanup, so it lags behind a bit.
+	OtherSizeBytes int64 `json:"otherSizeBytes,omitempty"`
+}
+
+type AgentEntry struct {
+	InstalledAt time.Time `json:"installedAt"`
+	LastUsedAt  time.Time `json:"lastUsedAt,omitempty"`
+	// VerifiedSHA256 is the digest the CodeModules archive was verified against during the install, empty if it was installed without verification.
+	VerifiedSHA256 string `json:"verifiedSha256,omitempty"`
+	SizeBytes      int64  `json:"sizeBytes"`
+}
+
+type VolumeEntry struct {
+	DynaKube     string `json:"dynakube,omitempty"`
+	Agent        string `json:"agent"`
+	PodName      string `json:"podName,omitempty"`
+	PodNamespace string `json:"podNamespace,omitempty"`
+}
+
+func NewInventory() *Inventory {
+	return &Inventory{
+		Agents:  map[string]AgentEntry{},
+		Volumes: map[string]VolumeEntry{},
+		Latest:  map[string]string{},
+		Canary:  map[string]string{},
+		Refused: map[string]string{},
+	}
+}
+
+func (inventory *Inventory) AddAgent(name string, sizeBytes int64, installedAt time.Time) {
+	inventory.Agents[name] = AgentEntry{InstalledAt: installedAt, LastUsedAt: installedAt, SizeBytes: sizeBytes}
+}
+
+// MarkUsed records when the CodeModules were last mounted or provided as the latest, which decides the order of eviction.
+func (inventory *Inventory) MarkUsed(name string, usedAt time.Time) {
+	agent, ok := inventory.Agents[name]
+	if !ok {
+		return
+	}
+
+	agent.LastUsedAt = usedAt
+	inventory.Agents[name] = agent
+}
+
+// SetVerified records the digest the CodeModules archive was verified against during the install.
+func (inventory *Inventory) SetVerified(name, sha256 string) {
+	agent, ok := inventory.Agents[name]
+	if !ok {
+		return
+	}
+
+	agent.VerifiedSHA256 = sha256
+	inventory.Agents[name] = agent
+}
+
+func (inventory *Inventory) HasAgent(name string) bool {
+	_, ok := inventory.Agents[name]
+
+	return ok
+}
+
+func (inventory *Inventory) RemoveAgent(name string) {
+	delete(inventory.Agents, name)
+}
+
+func (inventory *Inventory) SetLatest(dynakubeName, agent string) {
+	inventory.Latest[dynakubeName] = agent
+}
+
+func (inventory *Inventory) SetCanary(dynakubeName, agent string) {
+	inventory.Canary[dynakubeName] = agent
+}
+
+func (inventory *Inventory) RemoveCanary(dynakubeName string) {
+	delete(inventory.Canary, dynakubeName)
+}
+
+func (inventory *Inventory) RemoveDynaKube(dynakubeName string) {
+	delete(inventory.Latest, dynakubeName)
+	delete(inventory.Canary, dynakubeName)
+	delete(inventory.Refused, dynakubeName)
+}
+
+func (inventory *Inventory) SetRefused(dynakubeName, agent string) {
+	inventory.Refused[dynakubeName] = agent
+}
+
+func (inventory *Inventory) ClearRefused(dynakubeName string) {
+	delete(inventory.Refused, dynakubeName)
+}
+
+// IsRefused returns true if the last install for the DynaKube was refused because of the disk quota.
+func (inventory *Inventory) IsRefused(dynakubeName string) bool {
+	_, ok := inventory.Refused[dynakubeName]
+
+	return ok
+}
+
+func (inventory *Inventory) AddVolume(volumeID string, volume VolumeEntry) {
+	inventory.Volumes[volumeID] = volume
+}
+
+func (inventory *Inventory) RemoveVolume(volumeID string) {
+	delete(inventory.Volumes, volumeID)
+}
+
+// ReferenceCount returns how many volumes and DynaKubes, as their latest or canary, reference the given CodeModules.
+func (inventory *Inventory) ReferenceCount(agent string) int {
+	count := 0
+
+	for _, volume := range inventory.Volumes {
+		if volume.Agent == agent {
+			count++
+		}
+	}
+
+	for _, latest := range inventory.Latest {
+		if latest == agent {
+			count++
+		}
+	}
+
+	for _, canary := range inventory.Canary {
+		if canary == agent {
+			count++
+		}
+	}
+
+	return count
+}
+
+// UnreferencedAgents returns the sorted names of the installed CodeModules, that are neither mounted nor the latest or canary of any DynaKube.
+func (inventory *Inventory) UnreferencedAgents() []string {
+	var unreferenced []string
+
+	for name := range inventory.Agents {
+		if inventory.ReferenceCount(name) == 0 {
+			unreferenced = append(unreferenced, name)
+		}
+	}
+
+	slices.Sort(unreferenced)
+
+	return unreferenced
+}
+
+// LeastRecentlyUsedAgents returns the names of the unreferenced CodeModules, the least recently used first.
+func (inventory *Inventory) LeastRecentlyUsedAgents() []string {
+	unreferenced := inventory.UnreferencedAgents()
+
+	slices.SortStableFunc(unreferenced, func(a, b string) int {
+		return inventory.Agents[a].lastUsed().Compare(inventory.Agents[b].lastUsed())
+	})
+
+	return unreferenced
+}
+
+// TotalSize returns the size of all the installed CodeModules.
+func (inventory *Inventory) TotalSize() int64 {
+	var size int64
+
+	for _, agent := range inventory.Agents {
+		size += agent.SizeBytes
+	}
+
+	return size
+}
+
+// ReferencedSize returns the size of the installed CodeModules, that can't be removed, because they are referenced.
+func (inventory *Inventory) ReferencedSize() int64 {
+	var size int64
+
+	for name, agent := range inventory.Agents {
+		if inventory.ReferenceCount(name) > 0 {
+			size += agent.SizeBytes
+		}
+	}
+
+	return size
+}
+
+// LargestAgentSize returns the size of the largest installed CodeModules, which is the best guess for the size of the next install.
+func (inventory *Inventory) LargestAgentSize() int64 {
+	var size int64
+
+	for _, agent := range inventory.Agents {
+		size = max(size, agent.SizeBytes)
+	}
+
+	return size
+}
+
+// DiskUsage returns the size of the CodeModules referenced by each DynaKube, either as its latest, its canary or by its volumes.
+// CodeModules shared between DynaKubes are counted for each of them.
+func (inventory *Inventory) DiskUsage() map[string]int64 {
+	agentsPerDynaKube := map[string]map[string]bool{}
+
+	addReference := func(dynakubeName, agent string) {
+		if agentsPerDynaKube[dynakubeName] == nil {
+			agentsPerDynaKube[dynakubeName] = map[string]bool{}
+		}
+
+		agentsPerDynaKube[dynakubeName][agent] = true
+	}
+
+	for dynakubeName, agent := range inventory.Latest {
+		addReference(dynakubeName, agent)
+	}
+
+	for dynakubeName, agent := range inventory.Canary {
+		addReference(dynakubeName, agent)
+	}
+
+	for _, volume := range inventory.Volumes {
+		if volume.DynaKube != "" {
+			addReference(volume.DynaKube, volume.Agent)
+		}
+	}
+
+	usage := make(map[string]int64, len(agentsPerDynaKube))
+
+	for dynakubeName, agents := range agentsPerDynaKube {
+		for agent := range agents {
+			usage[dynakubeName] += inventory.Agents[agent].SizeBytes
+		}
+	}
+
+	return usage
+}
+
+func (agent AgentEntry) lastUsed() time.Time {
+	if agent.LastUsedAt.IsZero() {
+		return agent.InstalledAt
+	}
+
+	return agent.LastUsedAt
+}
+
+// InventoryStore reads and updates the inventory file, the csi-server and csi-provisioner both use it, so every update holds a file lock.
+type InventoryStore struct {
+	fs    afero.Fs
+	path  PathResolver
+	mutex sync.Mutex
+}
+
+func NewInventoryStore(fs afero.Fs, path PathResolver) *InventoryStore {
+	return &InventoryStore{
+		fs:   fs,
+		path: path,
+	}
+}
+
+func (store *InventoryStore) Exists() (bool, error) {
+	return afero.Exists(store.fs, store.path.InventoryFile())
+}
+
+// Read returns the current inventory, an empty one if there is no inventory file yet.
+func (store *InventoryStore) Read() (*Inventory, error) {
+	unlock, err := store.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return store.read()
+}
+
+// Update applies the given function to the current inventory and persists the result, unless the function returns an error.
+func (store *InventoryStore) Update(update func(inventory *Inventory) error) error {
+	unlock, err := store.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	inventory, err := store.read()
+	if err != nil {
+		return err
+	}
+
+	if err := update(inventory); err != nil {
+		return err
+	}
+
+	return store.write(inventory)
+}
+
+// Replace overwrites the inventory file, which is needed if it has to be rebuilt from the filesystem.
+func (store *InventoryStore) Replace(inventory *Inventory) error {
+	unlock, err := store.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return store.write(inventory)
+}
+
+func (store *InventoryStore) read() (*Inventory, error) {
+	content, err := afero.ReadFile(store.fs, store.path.InventoryFile())
+	if os.IsNotExist(err) {
+		return NewInventory(), nil
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	inventory := NewInventory()
+	if err := json.Unmarshal(content, inventory); err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse inventory file %s", store.path.InventoryFile())
+	}
+
+	// maps missing from the file are nil after unmarshalling
+	if inventory.Agents == nil {
+		inventory.Agents = map[string]AgentEntry{}
+	}
+
+	if inventory.Volumes == nil {
+		inventory.Volumes = map[string]VolumeEntry{}
+	}
+
+	if inventory.Latest == nil {
+		inventory.Latest = map[string]string{}
+	}
+
+	if inventory.Canary == nil {
+		inventory.Canary = map[string]string{}
+	}
+
+	if inventory.Refused == nil {
+		inventory.Refused = map[string]string{}
+	}
+
+	return inventory, nil
+}
+
+// write replaces the inventory file atomically, so a crash never leaves a partially written inventory behind.
+func (store *InventoryStore) write(inventory *Inventory) error {
+	content, err := json.Marshal(inventory)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	tmpPath := store.path.InventoryFile() + ".tmp"
+
+	if err := afero.WriteFile(store.fs, tmpPath, content, 0600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(store.fs.Rename(tmpPath, store.path.InventoryFile()))
+}
+
+// DirSize returns the summed up size of all files in the given directory.
+func DirSize(fs afero.Fs, path string) (int64, error) {
+	var size int64
+
+	err := afero.Walk(fs, path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, errors.WithStack(err)
+}
@@ -0,0 +1,42 @@
+package metadata
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"golang.org/x/sys/unix"
+)
+
+// lock makes sure that only one process (and goroutine) at a time works with the inventory file.
+// The csi-server and csi-provisioner run in different containers, so an in-process mutex is not enough,
+// the file lock is only possible on the real filesystem though.
+func (store *InventoryStore) lock() (func(), error) {
+	store.mutex.Lock()
+
+	if _, ok := store.fs.(*afero.OsFs); !ok {
+		return store.mutex.Unlock, nil
+	}
+
+	lockFile, err := os.OpenFile(store.path.InventoryLockFile(), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		store.mutex.Unlock()
+
+		return nil, errors.WithStack(err)
+	}
+
+	if err := unix.Flock(int(lockFile.Fd()), unix.LOCK_EX); err != nil { //nolint:gosec
+		_ = lockFile.Close()
+
+		store.mutex.Unlock()
+
+		return nil, errors.WithMessage(err, "failed to lock inventory file")
+	}
+
+	return func() {
+		_ = unix.Flock(int(lockFile.Fd()), unix.LOCK_UN) //nolint:gosec
+		_ = lockFile.Close()
+
+		store.mutex.Unlock()
+	}, nil
+}
@@ -0,0 +1,134 @@
+package metadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventory(t *testing.T) {
+	createInventory := func() *Inventory {
+		inventory := NewInventory()
+		inventory.AddAgent("shared", 10, time.Now())
+		inventory.AddAgent("mounted", 20, time.Now())
+		inventory.AddAgent("orphan", 40, time.Now())
+		inventory.SetLatest("dk-1", "shared")
+		inventory.SetLatest("dk-2", "shared")
+		inventory.AddVolume("volume-1", VolumeEntry{DynaKube: "dk-1", Agent: "mounted"})
+
+		return inventory
+	}
+
+	t.Run("reference count", func(t *testing.T) {
+		inventory := createInventory()
+
+		assert.Equal(t, 2, inventory.ReferenceCount("shared"))
+		assert.Equal(t, 1, inventory.ReferenceCount("mounted"))
+		assert.Equal(t, 0, inventory.ReferenceCount("orphan"))
+	})
+
+	t.Run("unreferenced agents", func(t *testing.T) {
+		inventory := createInventory()
+
+		assert.Equal(t, []string{"orphan"}, inventory.UnreferencedAgents())
+
+		inventory.RemoveVolume("volume-1")
+		inventory.RemoveDynaKube("dk-1")
+
+		assert.Equal(t, []string{"mounted", "orphan"}, inventory.UnreferencedAgents())
+
+		inventory.RemoveDynaKube("dk-2")
+
+		assert.Equal(t, []string{"mounted", "orphan", "shared"}, inventory.UnreferencedAgents())
+	})
+
+	t.Run("disk usage", func(t *testing.T) {
+		inventory := createInventory()
+
+		assert.Equal(t, map[string]int64{"dk-1": 30, "dk-2": 10}, inventory.DiskUsage())
+	})
+
+	t.Run("canary is referenced until it is removed", func(t *testing.T) {
+		inventory := createInventory()
+		inventory.SetCanary("dk-2", "orphan")
+
+		assert.Equal(t, 1, inventory.ReferenceCount("orphan"))
+		assert.Equal(t, map[string]int64{"dk-1": 30, "dk-2": 50}, inventory.DiskUsage())
+
+		inventory.RemoveCanary("dk-2")
+
+		assert.Equal(t, []string{"orphan"}, inventory.UnreferencedAgents())
+	})
+}
+
+func TestInventoryStore(t *testing.T) {
+	path := PathResolver{RootDir: "/data"}
+
+	t.Run("no file -> empty inventory", func(t *testing.T) {
+		store := NewInventoryStore(afero.NewMemMapFs(), path)
+
+		exists, err := store.Exists()
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		inventory, err := store.Read()
+		require.NoError(t, err)
+		assert.Equal(t, NewInventory(), inventory)
+	})
+
+	t.Run("update persists", func(t *testing.T) {
+		store := NewInventoryStore(afero.NewMemMapFs(), path)
+
+		err := store.Update(func(inventory *Inventory) error {
+			inventory.AddAgent("1.2.3", 42, time.Now())
+			inventory.SetLatest("dk", "1.2.3")
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		inventory, err := store.Read()
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), inventory.Agents["1.2.3"].SizeBytes)
+		assert.Equal(t, "1.2.3", inventory.Latest["dk"])
+		assert.NotNil(t, inventory.Volumes)
+	})
+
+	t.Run("failed update is not persisted", func(t *testing.T) {
+		store := NewInventoryStore(afero.NewMemMapFs(), path)
+
+		err := store.Update(func(inventory *Inventory) error {
+			inventory.AddAgent("1.2.3", 42, time.Now())
+
+			return assert.AnError
+		})
+		require.ErrorIs(t, err, assert.AnError)
+
+		exists, err := store.Exists()
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("corrupt file -> error", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, afero.WriteFile(fs, path.InventoryFile(), []byte("{"), 0600))
+
+		store := NewInventoryStore(fs, path)
+
+		_, err := store.Read()
+		require.Error(t, err)
+	})
+}
+
+func TestDirSize(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/agent/a", []byte("12345"), 0600))
+	require.NoError(t, afero.WriteFile(fs, "/agent/sub/b", []byte("123"), 0600))
+
+	size, err := DirSize(fs, "/agent")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), size)
+}
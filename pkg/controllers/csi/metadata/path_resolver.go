@@ -43,10 +43,33 @@ func (pr PathResolver) AgentSharedBinaryDirForAgent(versionOrDigest string) stri
 	return filepath.Join(pr.AgentSharedBinaryDirBase(), versionOrDigest)
 }
 
+// InventoryFile is the node-local record of the installed CodeModules and the volumes referencing them
+func (pr PathResolver) InventoryFile() string {
+	return pr.Base(dtcsi.InventoryFileName)
+}
+
+func (pr PathResolver) InventoryLockFile() string {
+	return pr.InventoryFile() + ".lock"
+}
+
+// AgentQuarantineDir is where downloads that failed their integrity verification are kept for inspection
+func (pr PathResolver) AgentQuarantineDir() string {
+	return pr.Base(dtcsi.SharedQuarantineDir)
+}
+
+func (pr PathResolver) AgentQuarantineDirForAgent(name string) string {
+	return filepath.Join(pr.AgentQuarantineDir(), name)
+}
+
 func (pr PathResolver) LatestAgentBinaryForDynaKube(dynakubeName string) string {
 	return filepath.Join(pr.DynaKubeDir(dynakubeName), "latest-codemodule")
 }
 
+// CanaryAgentBinaryForDynaKube is the candidate of the CodeModules rollout, that is mounted into the pods of the canary namespaces
+func (pr PathResolver) CanaryAgentBinaryForDynaKube(dynakubeName string) string {
+	return filepath.Join(pr.DynaKubeDir(dynakubeName), "canary-codemodule")
+}
+
 func (pr PathResolver) AgentTempUnzipRootDir() string {
 	return pr.Base("tmp_zip")
 }
@@ -0,0 +1,100 @@
+package csiprovisioner
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/bundle"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/processmoduleconfig"
+	k8sconfigmap "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/configmap"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// installAgentFromBundle installs the CodeModules from the pre-staged bundle of the DynaKube.
+// The version, connection info and process module config all come from the signed manifest of the bundle, so neither the tenant nor a registry is needed.
+func (provisioner *OneAgentProvisioner) installAgentFromBundle(ctx context.Context, dk dynakube.DynaKube) error {
+	bundleSpec := dk.OneAgent().GetCodeModulesBundle()
+
+	manifest, err := provisioner.readBundleManifest(ctx, dk, bundleSpec.Path, bundleSpec.TrustedKeys)
+	if err != nil {
+		return err
+	}
+
+	err = provisioner.publishBundleConnectionInfo(ctx, dk, *manifest)
+	if err != nil {
+		return err
+	}
+
+	targetDir := provisioner.path.AgentSharedBinaryDirForAgent(manifest.Version)
+
+	props := &bundle.Properties{
+		Manifest:     manifest,
+		Path:         bundleSpec.Path,
+		PathResolver: provisioner.path,
+		SizeCheck:    provisioner.quotaSizeCheck(ctx, dk, targetDir),
+	}
+
+	err = provisioner.checkQuota(ctx, dk, targetDir)
+	if err != nil {
+		return err
+	}
+
+	ready, err := provisioner.bundleInstallerBuilder(provisioner.fs, props).InstallAgent(ctx, targetDir)
+	if err != nil {
+		return err
+	}
+
+	if !ready {
+		return errNotReady
+	}
+
+	err = provisioner.addToInventory(ctx, dk, targetDir, nil)
+	if err != nil {
+		return err
+	}
+
+	err = provisioner.createLatestVersionSymlink(dk, targetDir)
+	if err != nil {
+		return err
+	}
+
+	processModuleConfig := manifest.AgentProcessModuleConfig().AddHostGroup(dk.OneAgent().GetHostGroup())
+
+	return processmoduleconfig.UpdateFromDir(provisioner.fs, provisioner.path.AgentConfigDir(dk.GetName()), targetDir, processModuleConfig)
+}
+
+func (provisioner *OneAgentProvisioner) readBundleManifest(ctx context.Context, dk dynakube.DynaKube, bundlePath, trustedKeysName string) (*bundle.Manifest, error) {
+	var trustedKeys corev1.ConfigMap
+
+	err := provisioner.apiReader.Get(ctx, client.ObjectKey{Name: trustedKeysName, Namespace: dk.Namespace}, &trustedKeys)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get trusted keys from %s configmap", trustedKeysName)
+	}
+
+	manifest, err := bundle.ReadManifest(provisioner.fs, bundlePath, []byte(trustedKeys.Data[bundle.TrustedKeysKey]))
+	if err != nil {
+		log.Info("failed to read the manifest of the CodeModules bundle", "dk", dk.GetName(), "bundle", bundlePath)
+
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// publishBundleConnectionInfo makes the connection info of the bundle available to the webhook, which can't read the bundle itself.
+// Every node reads the same bundle, so they all publish the same content.
+func (provisioner *OneAgentProvisioner) publishBundleConnectionInfo(ctx context.Context, dk dynakube.DynaKube, manifest bundle.Manifest) error {
+	connectionInfo, err := bundle.BuildConnectionInfoConfigMap(&dk, manifest)
+	if err != nil {
+		return err
+	}
+
+	_, err = k8sconfigmap.Query(provisioner.kubeClient, provisioner.apiReader, log).CreateOrUpdate(ctx, connectionInfo)
+	if err != nil {
+		return errors.WithMessage(err, "failed to publish the connection info of the CodeModules bundle")
+	}
+
+	return nil
+}
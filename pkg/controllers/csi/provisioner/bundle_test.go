@@ -0,0 +1,194 @@
+package csiprovisioner
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/bundle"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/processmoduleconfig"
+	installermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/injection/codemodule/installer"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testBundlePath        = "/bundles/codemodules"
+	testBundleVersion     = "1.2.3.20240101-000000"
+	testTrustedKeysConfig = "bundle-keys"
+)
+
+func TestReconcileWithBundle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dynakube with bundle => bundle installer used, dtclient not created, no error", func(t *testing.T) {
+		dk := createDynaKubeWithBundle(t)
+		prov := createProvisioner(t)
+		clt := fake.NewClient(dk, createSignedBundleManifest(t, prov.fs))
+		prov.apiReader = clt
+		prov.kubeClient = clt
+		installer := createSuccessfulInstaller(t)
+		prov.bundleInstallerBuilder = mockBundleInstallerBuilder(t, installer)
+		createBundlePMCSourceFile(t, prov)
+
+		result, err := prov.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(dk)})
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, defaultRequeueDuration, result.RequeueAfter)
+
+		installer.AssertCalled(t, "InstallAgent", mock.Anything, prov.path.AgentSharedBinaryDirForAgent(testBundleVersion))
+
+		var connectionInfo corev1.ConfigMap
+		require.NoError(t, clt.Get(ctx, client.ObjectKey{Name: bundle.ConnectionInfoConfigMapName(dk.Name), Namespace: dk.Namespace}, &connectionInfo))
+		assert.NotEmpty(t, connectionInfo.Data[connectioninfo.TenantUUIDKey])
+		assert.NotContains(t, connectionInfo.Data, connectioninfo.TenantTokenKey)
+	})
+
+	t.Run("bundle manifest not trusted => error, installer not used", func(t *testing.T) {
+		dk := createDynaKubeWithBundle(t)
+		untrustedKeys := createSignedBundleManifest(t, afero.NewMemMapFs())
+		prov := createProvisioner(t, dk, untrustedKeys)
+		_ = createSignedBundleManifest(t, prov.fs)
+
+		result, err := prov.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(dk)})
+		require.Error(t, err)
+		require.NotNil(t, result)
+	})
+
+	t.Run("trusted keys missing => error", func(t *testing.T) {
+		dk := createDynaKubeWithBundle(t)
+		prov := createProvisioner(t, dk)
+		_ = createSignedBundleManifest(t, prov.fs)
+
+		result, err := prov.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(dk)})
+		require.Error(t, err)
+		require.NotNil(t, result)
+	})
+}
+
+func TestInstallAgentFromBundle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("process module config is taken from the bundle manifest", func(t *testing.T) {
+		dk := createDynaKubeWithBundle(t)
+		dk.Spec.OneAgent.HostGroup = "test-group"
+		prov := createProvisioner(t)
+		prov.apiReader = fake.NewClient(dk, createSignedBundleManifest(t, prov.fs))
+		prov.bundleInstallerBuilder = mockBundleInstallerBuilder(t, createSuccessfulInstaller(t))
+		createBundlePMCSourceFile(t, prov)
+
+		err := prov.installAgent(ctx, *dk)
+		require.NoError(t, err)
+
+		ruxitAgentProc, err := afero.ReadFile(prov.fs, filepath.Join(prov.path.AgentConfigDir(dk.Name), processmoduleconfig.RuxitAgentProcPath))
+		require.NoError(t, err)
+		assert.Contains(t, string(ruxitAgentProc), "tenant test-tenant")
+		assert.Contains(t, string(ruxitAgentProc), "tenantToken test-token")
+		assert.Contains(t, string(ruxitAgentProc), "serverAddress {https://test-endpoint}")
+		assert.Contains(t, string(ruxitAgentProc), "hostGroup test-group")
+
+		inventory, err := prov.inventory.Read()
+		require.NoError(t, err)
+		assert.Len(t, inventory.Agents, 1)
+		assert.Equal(t, 1, inventory.ReferenceCount(inventory.Latest[dk.Name]))
+	})
+
+	t.Run("installer not ready => not ready error", func(t *testing.T) {
+		dk := createDynaKubeWithBundle(t)
+		prov := createProvisioner(t)
+		prov.apiReader = fake.NewClient(dk, createSignedBundleManifest(t, prov.fs))
+		prov.bundleInstallerBuilder = mockBundleInstallerBuilder(t, createNotReadyInstaller(t))
+
+		err := prov.installAgent(ctx, *dk)
+		require.ErrorIs(t, err, errNotReady)
+	})
+}
+
+func createDynaKubeWithBundle(t *testing.T) *dynakube.DynaKube {
+	t.Helper()
+
+	dk := createDynaKubeBase(t)
+	dk.Spec.OneAgent = oneagent.Spec{
+		CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{
+			AppInjectionSpec: oneagent.AppInjectionSpec{
+				CodeModulesBundle: &oneagent.CodeModulesBundleSpec{
+					Path:        testBundlePath,
+					TrustedKeys: testTrustedKeysConfig,
+				},
+			},
+		},
+	}
+
+	return dk
+}
+
+// createSignedBundleManifest writes a manifest, signed with a new key, to the fs and returns the ConfigMap that trusts the key.
+func createSignedBundleManifest(t *testing.T, fs afero.Fs) *corev1.ConfigMap {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	manifest, err := json.Marshal(bundle.Manifest{
+		Version: testBundleVersion,
+		Image:   "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		ConnectionInfo: bundle.ConnectionInfo{
+			TenantUUID:  "test-tenant",
+			TenantToken: "test-token",
+			Endpoints:   "https://test-endpoint",
+		},
+	})
+	require.NoError(t, err)
+
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, manifest))
+
+	require.NoError(t, fs.MkdirAll(testBundlePath, 0755))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(testBundlePath, bundle.ManifestFileName), manifest, 0644))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(testBundlePath, bundle.SignatureFileName), []byte(signature), 0644))
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testTrustedKeysConfig,
+			Namespace: "test-ns",
+		},
+		Data: map[string]string{
+			bundle.TrustedKeysKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		},
+	}
+}
+
+func createBundlePMCSourceFile(t *testing.T, prov OneAgentProvisioner) {
+	t.Helper()
+
+	pmcPath := filepath.Join(prov.path.AgentSharedBinaryDirForAgent(testBundleVersion), processmoduleconfig.RuxitAgentProcPath)
+	require.NoError(t, prov.fs.MkdirAll(filepath.Dir(pmcPath), 0755))
+	require.NoError(t, afero.WriteFile(prov.fs, pmcPath, []byte("[general]\nkey value\n"), 0644))
+}
+
+func mockBundleInstallerBuilder(t *testing.T, mockedInstaller *installermock.Installer) bundleInstallerBuilder {
+	t.Helper()
+
+	return func(_ afero.Fs, _ *bundle.Properties) installer.Installer {
+		return mockedInstaller
+	}
+}
@@ -0,0 +1,142 @@
+package csiprovisioner
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/quota"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/integrity"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/symlink"
+	"github.com/pkg/errors"
+)
+
+// installCanary provides the candidate of an active CodeModules rollout next to the latest CodeModules,
+// so the pods of the canary namespaces mount the candidate, while all other pods keep the latest.
+func (provisioner *OneAgentProvisioner) installCanary(ctx context.Context, dk dynakube.DynaKube) error {
+	canaryDk, ok := getCanaryDynaKube(dk)
+	if !ok {
+		return provisioner.removeCanary(dk)
+	}
+
+	targetDir := provisioner.getTargetDir(canaryDk)
+
+	agentInstaller, verification, err := provisioner.getInstaller(ctx, canaryDk, provisioner.canaryQuotaSizeCheck(targetDir))
+	if err != nil {
+		log.Info("failed to create CodeModule installer for the canary", "dk", dk.GetName())
+
+		return err
+	}
+
+	err = provisioner.checkCanaryQuota(targetDir)
+	if err != nil {
+		return err
+	}
+
+	ready, err := agentInstaller.InstallAgent(ctx, targetDir)
+	if err != nil {
+		return err
+	}
+
+	if !ready {
+		return errNotReady
+	}
+
+	err = provisioner.addCanaryToInventory(dk, targetDir, verification)
+	if err != nil {
+		return err
+	}
+
+	symlinkPath := provisioner.path.CanaryAgentBinaryForDynaKube(dk.GetName())
+	if err := symlink.Remove(provisioner.fs, symlinkPath); err != nil {
+		return err
+	}
+
+	return symlink.Create(provisioner.fs, targetDir, symlinkPath)
+}
+
+// getCanaryDynaKube returns a copy of the DynaKube, whose CodeModules status is the candidate of the active rollout,
+// so the candidate is installed the same way as the latest CodeModules.
+func getCanaryDynaKube(dk dynakube.DynaKube) (dynakube.DynaKube, bool) {
+	rollout := dk.OneAgent().GetActiveCodeModulesRollout()
+	if rollout == nil || (rollout.Candidate.Version == "" && rollout.Candidate.ImageID == "") {
+		return dk, false
+	}
+
+	canaryDk := *dk.DeepCopy()
+	canaryDk.Status.CodeModules.VersionStatus = rollout.Candidate
+
+	return canaryDk, true
+}
+
+// checkCanaryQuota refuses the install of the candidate before anything is downloaded, if it would not fit into the disk quota.
+// Unlike for the latest CodeModules, there is no fallback for the canary, so the install is retried until the cleanup made room for it.
+func (provisioner *OneAgentProvisioner) checkCanaryQuota(targetDir string) error {
+	return provisioner.checkCanaryQuotaForSize(targetDir, (*metadata.Inventory).LargestAgentSize)
+}
+
+// canaryQuotaSizeCheck checks the quota again with the size the installer knows from the download or the image manifest, see quotaSizeCheck.
+func (provisioner *OneAgentProvisioner) canaryQuotaSizeCheck(targetDir string) installer.SizeCheck {
+	return func(sizeBytes int64) error {
+		return provisioner.checkCanaryQuotaForSize(targetDir, fixedSize(sizeBytes))
+	}
+}
+
+func (provisioner *OneAgentProvisioner) checkCanaryQuotaForSize(targetDir string, size func(*metadata.Inventory) int64) error {
+	if !provisioner.quota.IsEnabled() {
+		return nil
+	}
+
+	inventory, err := provisioner.inventory.Read()
+	if err != nil {
+		return err
+	}
+
+	agent := filepath.Base(targetDir)
+	if inventory.HasAgent(agent) || provisioner.quota.Fits(inventory, size(inventory)) {
+		return nil
+	}
+
+	return errors.WithMessagef(quota.ErrExceeded, "canary CodeModules %s do not fit into the disk quota of %d bytes", agent, provisioner.quota.LimitBytes)
+}
+
+// addCanaryToInventory records the installed candidate as the canary of the DynaKube, so the cleanup keeps it until the rollout is over.
+func (provisioner *OneAgentProvisioner) addCanaryToInventory(dk dynakube.DynaKube, targetDir string, verification *integrity.Policy) error {
+	agent := filepath.Base(targetDir)
+
+	return provisioner.inventory.Update(func(inventory *metadata.Inventory) error {
+		if !inventory.HasAgent(agent) {
+			size, err := metadata.DirSize(provisioner.fs, targetDir)
+			if err != nil {
+				return err
+			}
+
+			inventory.AddAgent(agent, size, time.Now())
+
+			if verification != nil {
+				inventory.SetVerified(agent, verification.SHA256)
+			}
+		}
+
+		inventory.SetCanary(dk.GetName(), agent)
+		inventory.MarkUsed(agent, time.Now())
+
+		return nil
+	})
+}
+
+// removeCanary drops the candidate of a finished rollout, the cleanup removes it once it isn't mounted anymore.
+func (provisioner *OneAgentProvisioner) removeCanary(dk dynakube.DynaKube) error {
+	if err := symlink.Remove(provisioner.fs, provisioner.path.CanaryAgentBinaryForDynaKube(dk.GetName())); err != nil {
+		return err
+	}
+
+	return provisioner.inventory.Update(func(inventory *metadata.Inventory) error {
+		inventory.RemoveCanary(dk.GetName())
+
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+package csiprovisioner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/quota"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInstallCanary(t *testing.T) {
+	ctx := context.Background()
+	candidateImage := "test-image-candidate"
+
+	createCanaryDynaKube := func(t *testing.T, phase status.RolloutPhase) *dynakube.DynaKube {
+		dk := createDynaKubeWithImage(t)
+		dk.Spec.OneAgent.Rollout = &oneagent.RolloutSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+		}
+		dk.Status.CodeModules.Rollout = &status.RolloutStatus{
+			Phase:     phase,
+			Candidate: status.VersionStatus{ImageID: candidateImage},
+		}
+
+		return dk
+	}
+
+	// the mocked installer doesn't unpack anything
+	createCanaryTargetDir := func(t *testing.T, prov OneAgentProvisioner, dk *dynakube.DynaKube) string {
+		canaryDk, ok := getCanaryDynaKube(*dk)
+		require.True(t, ok)
+
+		targetDir := prov.getTargetDir(canaryDk)
+		require.NoError(t, prov.fs.MkdirAll(targetDir, 0755))
+
+		return targetDir
+	}
+
+	t.Run("candidate of an active rollout is installed as the canary", func(t *testing.T) {
+		dk := createCanaryDynaKube(t, status.RolloutCanaryPhase)
+		prov := createProvisioner(t, dk)
+		installer := createSuccessfulInstaller(t)
+		prov.imageInstallerBuilder = mockImageInstallerBuilder(t, installer)
+		targetDir := createCanaryTargetDir(t, prov, dk)
+
+		err := prov.installCanary(ctx, *dk)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, prov.getTargetDir(*dk), targetDir)
+		installer.AssertCalled(t, "InstallAgent", mock.Anything, targetDir)
+
+		inventory, err := prov.inventory.Read()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{dk.Name: "dGVzdC1pbWFnZS1jYW5kaWRhdGU="}, inventory.Canary)
+		assert.Positive(t, inventory.ReferenceCount("dGVzdC1pbWFnZS1jYW5kaWRhdGU="))
+	})
+
+	t.Run("candidate not ready yet => errNotReady", func(t *testing.T) {
+		dk := createCanaryDynaKube(t, status.RolloutCanaryPhase)
+		prov := createProvisioner(t, dk)
+		prov.imageInstallerBuilder = mockImageInstallerBuilder(t, createNotReadyInstaller(t))
+
+		err := prov.installCanary(ctx, *dk)
+		require.ErrorIs(t, err, errNotReady)
+	})
+
+	t.Run("candidate does not fit into the quota => refused by the size of the installer", func(t *testing.T) {
+		dk := createCanaryDynaKube(t, status.RolloutCanaryPhase)
+		prov := createProvisioner(t, dk)
+		prov.quota = quota.Quota{LimitBytes: 100, HighWatermark: 90, LowWatermark: 75}
+
+		canaryDk, ok := getCanaryDynaKube(*dk)
+		require.True(t, ok)
+
+		targetDir := prov.getTargetDir(canaryDk)
+		require.NoError(t, prov.checkCanaryQuota(targetDir))
+
+		err := prov.canaryQuotaSizeCheck(targetDir)(150)
+		require.ErrorIs(t, err, quota.ErrExceeded)
+	})
+
+	t.Run("canary is removed once the rollout is over", func(t *testing.T) {
+		dk := createCanaryDynaKube(t, status.RolloutCanaryPhase)
+		prov := createProvisioner(t, dk)
+		prov.imageInstallerBuilder = mockImageInstallerBuilder(t, createSuccessfulInstaller(t))
+		createCanaryTargetDir(t, prov, dk)
+
+		require.NoError(t, prov.installCanary(ctx, *dk))
+
+		dk.Status.CodeModules.Rollout.Phase = status.RolloutPromotedPhase
+
+		require.NoError(t, prov.installCanary(ctx, *dk))
+
+		inventory, err := prov.inventory.Read()
+		require.NoError(t, err)
+		assert.Empty(t, inventory.Canary)
+	})
+}
@@ -1,117 +1,318 @@
 package cleanup
 
 import (
-	"strings"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
-	"golang.org/x/exp/maps"
+	"github.com/spf13/afero"
 )
 
-func (c *Cleaner) removeUnusedBinaries(dks []dynakube.DynaKube, fsState fsState) {
-	c.removeOldBinarySymlinks(dks, fsState)
+// untrackedBinaryGracePeriod protects CodeModules that are being installed, but are not yet recorded in the inventory.
+const untrackedBinaryGracePeriod = time.Hour
 
-	keptBins, err := c.collectStillMountedBins()
+// removeUnusedBinaries removes the CodeModules that are neither mounted nor the latest of a DynaKube, according to the inventory.
+// If a disk quota is set, they are only removed once the quota requires it, the least recently used first.
+func (c *Cleaner) removeUnusedBinaries(dks []dynakube.DynaKube) {
+	if err := c.ensureInventory(); err != nil {
+		log.Error(err, "failed to prepare the inventory, skipping unused binaries cleanup")
+
+		return
+	}
+
+	mountedAgents, err := c.getMountedAgents()
 	if err != nil {
+		log.Error(err, "failed to list the mounted CodeModules, skipping unused binaries cleanup")
+
 		return
 	}
 
-	relevantLatestBins := c.collectRelevantLatestBins(dks)
+	relevantDks := map[string]bool{}
 
-	for k, v := range relevantLatestBins {
-		keptBins[k] = v
+	for _, dk := range dks {
+		if dk.OneAgent().IsAppInjectionNeeded() {
+			relevantDks[dk.Name] = true
+		}
 	}
 
-	c.removeOldSharedBinaries(keptBins)
+	var diskUsage map[string]int64
+
+	err = c.inventory.Update(func(inventory *metadata.Inventory) error {
+		for dkName := range inventory.Latest {
+			if !relevantDks[dkName] {
+				inventory.RemoveDynaKube(dkName)
+			}
+		}
+
+		for _, agent := range c.quota.Evictable(inventory) {
+			if mountedAgents[agent] {
+				log.Info("skipping removal of shared binary, it is still mounted", "agent", agent)
+
+				continue
+			}
+
+			binPath := c.path.AgentSharedBinaryDirForAgent(agent)
+
+			if err := c.fs.RemoveAll(binPath); err != nil {
+				log.Error(err, "failed to remove shared binary", "path", binPath)
+
+				continue
+			}
+
+			inventory.RemoveAgent(agent)
+			log.Info("removed unreferenced shared binary", "path", binPath)
+		}
+
+		if c.quota.IsEnabled() {
+			log.Info("disk usage of the data directory", "codeModulesBytes", inventory.TotalSize(), "otherBytes", inventory.OtherSizeBytes, "quotaBytes", c.quota.LimitBytes)
+		}
+
+		diskUsage = inventory.DiskUsage()
+
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "failed to update the inventory, skipping unused binaries cleanup")
+
+		return
+	}
+
+	setDiskUsageMetric(diskUsage)
 }
 
-func (c *Cleaner) removeOldSharedBinaries(keptBins map[string]bool) {
-	sharedBins, err := c.fs.ReadDir(c.path.AgentSharedBinaryDirBase())
+// removeUntrackedBinaries removes the directories in the shared binary directory that are not in the inventory, for example leftovers of a failed install.
+// Only directories that are older than untrackedBinaryGracePeriod are removed, to not interfere with an ongoing install.
+// Directories that are still referenced by a mounted volume are never removed.
+func (c *Cleaner) removeUntrackedBinaries() {
+	mountedAgents, err := c.getMountedAgents()
 	if err != nil {
-		log.Info("failed to list the shared binaries directory, skipping unused binaries cleanup")
+		log.Info("failed to list the mounted CodeModules, skipping untracked shared binaries cleanup", "err", err.Error())
 
 		return
 	}
 
-	for _, dir := range sharedBins {
-		sharedBinPath := c.path.AgentSharedBinaryDirForAgent(dir.Name())
+	err = c.inventory.Update(func(inventory *metadata.Inventory) error {
+		sharedBins, err := c.fs.ReadDir(c.path.AgentSharedBinaryDirBase())
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		for _, dir := range sharedBins {
+			if !dir.IsDir() || inventory.HasAgent(dir.Name()) || time.Since(dir.ModTime()) < untrackedBinaryGracePeriod {
+				continue
+			}
 
-		_, ok := keptBins[sharedBinPath]
-		if !ok {
-			err := c.fs.RemoveAll(sharedBinPath)
-			if err != nil {
-				log.Error(err, "failed to remove shared binary", "path", sharedBinPath)
+			if mountedAgents[dir.Name()] || inventory.ReferenceCount(dir.Name()) > 0 {
+				log.Info("skipping removal of untracked shared binary, it is still referenced by a volume", "agent", dir.Name())
 
 				continue
 			}
 
-			log.Info("removed old shared binary", "path", sharedBinPath)
+			binPath := c.path.AgentSharedBinaryDirForAgent(dir.Name())
+
+			if err := c.fs.RemoveAll(binPath); err != nil {
+				log.Error(err, "failed to remove untracked shared binary", "path", binPath)
+
+				continue
+			}
+
+			log.Info("removed untracked shared binary", "path", binPath)
 		}
+
+		return nil
+	})
+	if err != nil {
+		log.Info("failed to remove untracked shared binaries", "err", err.Error())
 	}
 }
 
-func (c *Cleaner) removeOldBinarySymlinks(dks []dynakube.DynaKube, fsState fsState) {
-	shouldBePresent := map[string]bool{}
-	for _, dk := range dks {
-		shouldBePresent[dk.Name] = true
+// ensureInventory makes sure there is a valid inventory file, that knows about everything that is in use.
+// On the first run it is reconciled with the filesystem and the mounts, even if the file already exists,
+// because the install and the publisher may have created it after an upgrade before the CodeModules that were already present got recorded.
+// A corrupt file is rebuilt from the filesystem alone.
+func (c *Cleaner) ensureInventory() error {
+	exists, err := c.inventory.Exists()
+	if err != nil {
+		return err
 	}
 
-	for _, dkDir := range fsState.binDks {
-		if _, ok := shouldBePresent[dkDir]; !ok {
-			latest := c.path.LatestAgentBinaryForDynaKube(dkDir)
-			if err := c.fs.Remove(latest); err == nil {
-				log.Info("removed old latest bin symlink", "path", latest)
+	if exists {
+		if _, err := c.inventory.Read(); err != nil {
+			log.Info("inventory file is corrupt, rebuilding it", "err", err.Error())
+
+			corruptPath := c.path.InventoryFile() + ".corrupt"
+			if err := c.fs.Rename(c.path.InventoryFile(), corruptPath); err != nil {
+				return err
 			}
+
+			c.inventorySeeded = false
 		}
+	} else {
+		c.inventorySeeded = false
 	}
 
-	for _, depDir := range fsState.deprecatedDks {
-		if _, ok := shouldBePresent[depDir]; !ok { // for the rare case where dk.Name == tenantUUID
-			latest := c.path.LatestAgentBinaryForDynaKube(depDir)
-			if err := c.fs.Remove(latest); err == nil {
-				log.Info("removed old deprecated latest bin symlink", "path", latest)
-			}
-		}
+	if c.inventorySeeded {
+		return nil
+	}
+
+	seed, err := c.bootstrapInventory()
+	if err != nil {
+		return err
+	}
+
+	err = c.inventory.Update(func(inventory *metadata.Inventory) error {
+		mergeInventory(inventory, seed)
+
+		log.Info("reconciled inventory with the filesystem", "agents", len(inventory.Agents), "volumes", len(inventory.Volumes))
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+
+	c.inventorySeeded = true
+
+	return nil
 }
 
-func (c *Cleaner) collectStillMountedBins() (map[string]bool, error) {
-	mountedBins := map[string]bool{}
+// mergeInventory adds everything from the seed that the inventory doesn't know about yet, the existing entries are kept as they are.
+func mergeInventory(inventory, seed *metadata.Inventory) {
+	for name, agent := range seed.Agents {
+		if !inventory.HasAgent(name) {
+			inventory.Agents[name] = agent
+		}
+	}
+
+	for volumeID, volume := range seed.Volumes {
+		if _, ok := inventory.Volumes[volumeID]; !ok {
+			inventory.AddVolume(volumeID, volume)
+		}
+	}
+
+	for dkName, agent := range seed.Latest {
+		if _, ok := inventory.Latest[dkName]; !ok {
+			inventory.SetLatest(dkName, agent)
+		}
+	}
+
+	for dkName, agent := range seed.Canary {
+		if _, ok := inventory.Canary[dkName]; !ok {
+			inventory.SetCanary(dkName, agent)
+		}
+	}
+}
 
+// getMountedAgents returns the CodeModules that are used as the lower dir of an overlay that is currently mounted.
+func (c *Cleaner) getMountedAgents() (map[string]bool, error) {
 	overlays, err := metadata.GetRelevantOverlayMounts(c.mounter, c.path.RootDir)
 	if err != nil {
-		log.Info("failed to list active overlay mounts, skipping unused binaries cleanup")
+		return nil, err
+	}
 
+	mountedAgents := map[string]bool{}
+	for _, overlay := range overlays {
+		mountedAgents[filepath.Base(overlay.LowerDir)] = true
+	}
+
+	return mountedAgents, nil
+}
+
+func (c *Cleaner) bootstrapInventory() (*metadata.Inventory, error) {
+	inventory := metadata.NewInventory()
+
+	sharedBins, err := c.fs.ReadDir(c.path.AgentSharedBinaryDirBase())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for _, dir := range sharedBins {
+		if !dir.IsDir() {
+			continue
+		}
+
+		size, err := metadata.DirSize(c.fs, c.path.AgentSharedBinaryDirForAgent(dir.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		inventory.AddAgent(dir.Name(), size, dir.ModTime())
+	}
+
+	overlays, err := metadata.GetRelevantOverlayMounts(c.mounter, c.path.RootDir)
+	if err != nil {
 		return nil, err
 	}
 
 	for _, overlay := range overlays {
-		mountedBins[overlay.LowerDir] = true
+		// the overlay is mounted at <root>/appmounts/<volume-id>/mapped
+		volumeID := filepath.Base(filepath.Dir(overlay.Path))
+		inventory.AddVolume(volumeID, metadata.VolumeEntry{Agent: filepath.Base(overlay.LowerDir)})
 	}
 
-	if len(mountedBins) > 0 {
-		log.Info("binaries to keep because they are still mounted", "paths", strings.Join(maps.Keys(mountedBins), ","))
+	dkDirs, err := c.fs.ReadDir(c.path.DynaKubesBaseDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
 	}
 
-	return mountedBins, nil
+	for _, dkDir := range dkDirs {
+		if agent := c.readSymlinkedAgent(c.path.LatestAgentBinaryForDynaKube(dkDir.Name())); agent != "" {
+			inventory.SetLatest(dkDir.Name(), agent)
+		}
+
+		if agent := c.readSymlinkedAgent(c.path.CanaryAgentBinaryForDynaKube(dkDir.Name())); agent != "" {
+			inventory.SetCanary(dkDir.Name(), agent)
+		}
+	}
+
+	return inventory, nil
 }
 
-func (c *Cleaner) collectRelevantLatestBins(dks []dynakube.DynaKube) map[string]bool {
-	latestBins := map[string]bool{}
+// readSymlinkedAgent returns the name of the CodeModules the symlink of the DynaKube points to, or "" if there is none.
+func (c *Cleaner) readSymlinkedAgent(symlinkPath string) string {
+	linker, ok := c.fs.Fs.(afero.LinkReader)
+	if !ok {
+		return ""
+	}
 
-	for _, dk := range dks {
-		if !dk.OneAgent().IsAppInjectionNeeded() {
-			continue
-		}
+	target, err := linker.ReadlinkIfPossible(symlinkPath)
+	if err != nil {
+		return ""
+	}
 
-		latestLink := c.path.LatestAgentBinaryForDynaKube(dk.Name)
+	return filepath.Base(target)
+}
 
-		c.addRelevantPath(latestLink, latestBins)
+func (c *Cleaner) removeOldBinarySymlinks(dks []dynakube.DynaKube, fsState fsState) {
+	shouldBePresent := map[string]bool{}
+	for _, dk := range dks {
+		shouldBePresent[dk.Name] = true
 	}
 
-	if len(latestBins) > 0 {
-		log.Info("binaries to keep because they are the latest for existing dynakubes", "paths", strings.Join(maps.Keys(latestBins), ","))
+	for _, dkDir := range fsState.binDks {
+		if _, ok := shouldBePresent[dkDir]; !ok {
+			latest := c.path.LatestAgentBinaryForDynaKube(dkDir)
+			if err := c.fs.Remove(latest); err == nil {
+				log.Info("removed old latest bin symlink", "path", latest)
+			}
+
+			canary := c.path.CanaryAgentBinaryForDynaKube(dkDir)
+			if err := c.fs.Remove(canary); err == nil {
+				log.Info("removed old canary bin symlink", "path", canary)
+			}
+		}
 	}
 
-	return latestBins
+	for _, depDir := range fsState.deprecatedDks {
+		if _, ok := shouldBePresent[depDir]; !ok { // for the rare case where dk.Name == tenantUUID
+			latest := c.path.LatestAgentBinaryForDynaKube(depDir)
+			if err := c.fs.Remove(latest); err == nil {
+				log.Info("removed old deprecated latest bin symlink", "path", latest)
+			}
+		}
+	}
 }
@@ -3,9 +3,12 @@ package cleanup
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/quota"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,149 +16,252 @@ import (
 )
 
 func TestRemoveUnusedBinaries(t *testing.T) {
-	// Not possible to test, as parts of it rely on turning symlinks into actual paths, and clearing up according to these paths.
-	// each individual part is testable
-	t.SkipNow()
-}
-
-func TestRemoveOldSharedBinaries(t *testing.T) {
 	t.Run("empty fs -> no panic", func(t *testing.T) {
 		cleaner := createCleaner(t)
 
-		keptBins := map[string]bool{}
-
-		cleaner.removeOldSharedBinaries(keptBins)
-	})
-	t.Run("empty shared dir -> no panic", func(t *testing.T) {
-		cleaner := createCleaner(t)
-		cleaner.fs.MkdirAll(cleaner.path.AgentSharedBinaryDirBase(), os.ModePerm)
-
-		keptBins := map[string]bool{}
-
-		cleaner.removeOldSharedBinaries(keptBins)
+		cleaner.removeUnusedBinaries(nil)
 	})
 
-	t.Run("empty keptBins -> remove all", func(t *testing.T) {
+	t.Run("only remove unreferenced binaries", func(t *testing.T) {
 		cleaner := createCleaner(t)
-		cleaner.fs.MkdirAll(cleaner.path.AgentSharedBinaryDirBase(), os.ModePerm)
+		dks := []dynakube.DynaKube{
+			createAppMonDk(t, "appmon", "url"),
+			createHostMonDk(t, "hostmon", "url"),
+		}
 
-		keptBins := map[string]bool{}
-		agentVersions := []string{"test1", "test2"}
+		for _, agent := range []string{"latest", "mounted", "orphan", "hostmon-latest", "removed-dk"} {
+			cleaner.createSharedBinDir(t, agent)
+		}
 
-		for _, folder := range agentVersions {
-			cleaner.createSharedBinDir(t, folder)
+		err := cleaner.inventory.Replace(&metadata.Inventory{
+			Agents: map[string]metadata.AgentEntry{
+				"latest":         {SizeBytes: 1},
+				"mounted":        {SizeBytes: 2},
+				"orphan":         {SizeBytes: 4},
+				"hostmon-latest": {SizeBytes: 8},
+				"removed-dk":     {SizeBytes: 16},
+			},
+			Volumes: map[string]metadata.VolumeEntry{
+				"volume-1": {DynaKube: "appmon", Agent: "mounted"},
+			},
+			Latest: map[string]string{
+				"appmon":  "latest",
+				"hostmon": "hostmon-latest",
+				"removed": "removed-dk",
+			},
+		})
+		require.NoError(t, err)
 
-			expectedDir := cleaner.path.AgentSharedBinaryDirForAgent(folder)
-			exists, _ := cleaner.fs.Exists(expectedDir)
-			require.True(t, exists)
-		}
+		cleaner.removeUnusedBinaries(dks)
 
-		cleaner.removeOldSharedBinaries(keptBins)
+		for _, agent := range []string{"latest", "mounted"} {
+			exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent(agent))
+			assert.True(t, exists, agent)
+		}
 
-		for _, folder := range agentVersions {
-			expectedDir := cleaner.path.AgentSharedBinaryDirForAgent(folder)
-			exists, _ := cleaner.fs.Exists(expectedDir)
-			require.False(t, exists)
+		for _, agent := range []string{"orphan", "hostmon-latest", "removed-dk"} {
+			exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent(agent))
+			assert.False(t, exists, agent)
 		}
+
+		inventory, err := cleaner.inventory.Read()
+		require.NoError(t, err)
+		assert.Len(t, inventory.Agents, 2)
+		assert.Equal(t, map[string]string{"appmon": "latest"}, inventory.Latest)
+		assert.Equal(t, map[string]int64{"appmon": 3}, inventory.DiskUsage())
 	})
 
-	t.Run("keptBins set -> only remove orphans", func(t *testing.T) {
+	t.Run("quota set -> keep unreferenced binaries until the high watermark is crossed", func(t *testing.T) {
 		cleaner := createCleaner(t)
-		cleaner.fs.MkdirAll(cleaner.path.AgentSharedBinaryDirBase(), os.ModePerm)
+		cleaner.quota = quota.Quota{LimitBytes: 100, HighWatermark: 90, LowWatermark: 60}
+		dks := []dynakube.DynaKube{createAppMonDk(t, "appmon", "url")}
 
-		keptBins := map[string]bool{
-			cleaner.path.AgentSharedBinaryDirForAgent("test1"): true,
-			cleaner.path.AgentSharedBinaryDirForAgent("test2"): true,
+		for _, agent := range []string{"latest", "old", "older"} {
+			cleaner.createSharedBinDir(t, agent)
 		}
-		agentVersions := []string{"test1", "test2"}
-		orphans := []string{"o1", "o2"}
 
-		for _, version := range append(agentVersions, orphans...) {
-			cleaner.createSharedBinDir(t, version)
+		now := time.Now()
+		err := cleaner.inventory.Replace(&metadata.Inventory{
+			Agents: map[string]metadata.AgentEntry{
+				"latest": {SizeBytes: 40, LastUsedAt: now},
+				"old":    {SizeBytes: 20, LastUsedAt: now.Add(-time.Hour)},
+				"older":  {SizeBytes: 20, LastUsedAt: now.Add(-2 * time.Hour)},
+			},
+			Latest: map[string]string{"appmon": "latest"},
+		})
+		require.NoError(t, err)
 
-			expectedDir := cleaner.path.AgentSharedBinaryDirForAgent(version)
-			exists, _ := cleaner.fs.Exists(expectedDir)
-			require.True(t, exists)
+		cleaner.removeUnusedBinaries(dks)
+
+		for _, agent := range []string{"latest", "old", "older"} {
+			exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent(agent))
+			assert.True(t, exists, agent)
 		}
 
-		cleaner.removeOldSharedBinaries(keptBins)
+		require.NoError(t, cleaner.inventory.Update(func(inventory *metadata.Inventory) error {
+			inventory.AddAgent("newer", 15, now)
 
-		for _, folder := range agentVersions {
-			expectedDir := cleaner.path.AgentSharedBinaryDirForAgent(folder)
-			exists, _ := cleaner.fs.Exists(expectedDir)
-			require.True(t, exists)
+			return nil
+		}))
+		cleaner.createSharedBinDir(t, "newer")
+
+		cleaner.removeUnusedBinaries(dks)
+
+		for _, agent := range []string{"latest", "newer"} {
+			exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent(agent))
+			assert.True(t, exists, agent)
 		}
 
-		for _, folder := range orphans {
-			expectedDir := cleaner.path.AgentSharedBinaryDirForAgent(folder)
-			exists, _ := cleaner.fs.Exists(expectedDir)
-			require.False(t, exists)
+		for _, agent := range []string{"old", "older"} {
+			exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent(agent))
+			assert.False(t, exists, agent)
 		}
 	})
-}
 
-func TestCollectStillMountedBins(t *testing.T) {
-	t.Run("0 mounts -> empty", func(t *testing.T) {
+	t.Run("missing inventory -> bootstrap, keep mounted binaries", func(t *testing.T) {
 		cleaner := createCleaner(t)
 
-		relevantBins, err := cleaner.collectStillMountedBins()
+		cleaner.createSharedBinDir(t, "mounted")
+		cleaner.createSharedBinDir(t, "orphan")
+
+		mockMountPoints(t, cleaner, mount.MountPoint{
+			Device: "overlay",
+			Path:   cleaner.path.AppMountMappedDir("volume-1"),
+			Type:   "overlay",
+			Opts: []string{
+				"lowerdir=" + cleaner.path.AgentSharedBinaryDirForAgent("mounted"),
+				"upperdir=...",
+				"workdir=...",
+			},
+		})
+
+		cleaner.removeUnusedBinaries(nil)
+
+		exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent("mounted"))
+		assert.True(t, exists)
 
+		exists, _ = cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent("orphan"))
+		assert.False(t, exists)
+
+		inventory, err := cleaner.inventory.Read()
 		require.NoError(t, err)
-		require.Empty(t, relevantBins)
+		assert.Equal(t, "mounted", inventory.Volumes["volume-1"].Agent)
+		assert.True(t, inventory.HasAgent("mounted"))
 	})
-	t.Run("get mounted bins", func(t *testing.T) {
+
+	t.Run("inventory created before the first run -> seed it, keep mounted binaries", func(t *testing.T) {
 		cleaner := createCleaner(t)
-		cleaner.path.RootDir = "special"
-		expectedPath := cleaner.path.RootDir + "/something"
-		expectedLowerDir := expectedPath + "/else"
 
-		relevantMountPoint := mount.MountPoint{
+		cleaner.createSharedBinDir(t, "pre-upgrade")
+		cleaner.createSharedBinDir(t, "new")
+
+		mockMountPoints(t, cleaner, mount.MountPoint{
 			Device: "overlay",
-			Path:   expectedPath,
+			Path:   cleaner.path.AppMountMappedDir("volume-1"),
 			Type:   "overlay",
 			Opts: []string{
-				"lowerdir=" + expectedLowerDir,
+				"lowerdir=" + cleaner.path.AgentSharedBinaryDirForAgent("pre-upgrade"),
 				"upperdir=...",
 				"workdir=...",
 			},
+		})
+
+		// the install of a new version created the file, before the cleanup ran the first time after the upgrade
+		require.NoError(t, cleaner.inventory.Update(func(inventory *metadata.Inventory) error {
+			inventory.AddAgent("new", 1, time.Now())
+			inventory.AddVolume("volume-2", metadata.VolumeEntry{Agent: "new"})
+
+			return nil
+		}))
+
+		cleaner.removeUnusedBinaries(nil)
+
+		for _, agent := range []string{"pre-upgrade", "new"} {
+			exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent(agent))
+			assert.True(t, exists, agent)
 		}
 
-		mockMountPoints(t, cleaner, relevantMountPoint)
+		inventory, err := cleaner.inventory.Read()
+		require.NoError(t, err)
+		assert.True(t, inventory.HasAgent("pre-upgrade"))
+		assert.Equal(t, "pre-upgrade", inventory.Volumes["volume-1"].Agent)
+		assert.Equal(t, "new", inventory.Volumes["volume-2"].Agent)
+		assert.True(t, cleaner.inventorySeeded)
+	})
+
+	t.Run("corrupt inventory -> rebuild", func(t *testing.T) {
+		cleaner := createCleaner(t)
+
+		cleaner.createSharedBinDir(t, "orphan")
+		require.NoError(t, cleaner.fs.WriteFile(cleaner.path.InventoryFile(), []byte("{not-json"), os.ModePerm))
+
+		cleaner.removeUnusedBinaries(nil)
+
+		exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent("orphan"))
+		assert.False(t, exists)
 
-		relevantBins, err := cleaner.collectStillMountedBins()
+		exists, _ = cleaner.fs.Exists(cleaner.path.InventoryFile() + ".corrupt")
+		assert.True(t, exists)
 
+		_, err := cleaner.inventory.Read()
 		require.NoError(t, err)
-		require.Len(t, relevantBins, 1)
-		assert.True(t, relevantBins[expectedLowerDir])
 	})
 }
 
-func TestCollectRelevantLatestBins(t *testing.T) {
-	t.Run("no dk -> do nothing", func(t *testing.T) {
+func TestRemoveUntrackedBinaries(t *testing.T) {
+	t.Run("empty fs -> no panic", func(t *testing.T) {
 		cleaner := createCleaner(t)
 
-		relevantBins := cleaner.collectRelevantLatestBins([]dynakube.DynaKube{})
-
-		require.Empty(t, relevantBins)
+		cleaner.removeUntrackedBinaries()
 	})
-	t.Run("no relevant dk -> do nothing", func(t *testing.T) {
+
+	t.Run("only remove old untracked binaries", func(t *testing.T) {
 		cleaner := createCleaner(t)
 
-		relevantBins := cleaner.collectRelevantLatestBins([]dynakube.DynaKube{
-			createHostMonDk(t, "hostmon", "url"),
+		for _, agent := range []string{"tracked", "untracked", "installing"} {
+			cleaner.createSharedBinDir(t, agent)
+		}
+
+		old := time.Now().Add(-2 * untrackedBinaryGracePeriod)
+		require.NoError(t, cleaner.fs.Chtimes(cleaner.path.AgentSharedBinaryDirForAgent("tracked"), old, old))
+		require.NoError(t, cleaner.fs.Chtimes(cleaner.path.AgentSharedBinaryDirForAgent("untracked"), old, old))
+
+		err := cleaner.inventory.Update(func(inventory *metadata.Inventory) error {
+			inventory.AddAgent("tracked", 0, old)
+
+			return nil
 		})
+		require.NoError(t, err)
+
+		cleaner.removeUntrackedBinaries()
+
+		for _, agent := range []string{"tracked", "installing"} {
+			exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent(agent))
+			assert.True(t, exists, agent)
+		}
 
-		require.Empty(t, relevantBins)
+		exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent("untracked"))
+		assert.False(t, exists)
 	})
 
-	t.Run("relevant dk -> try to resolve symlink", func(t *testing.T) {
+	t.Run("never remove mounted binaries", func(t *testing.T) {
 		cleaner := createCleaner(t)
+		cleaner.createSharedBinDir(t, "mounted")
 
-		relevantBins := cleaner.collectRelevantLatestBins([]dynakube.DynaKube{
-			createAppMonDk(t, "appmon", "url"),
+		old := time.Now().Add(-2 * untrackedBinaryGracePeriod)
+		require.NoError(t, cleaner.fs.Chtimes(cleaner.path.AgentSharedBinaryDirForAgent("mounted"), old, old))
+
+		mockMountPoints(t, cleaner, mount.MountPoint{
+			Device: "overlay",
+			Path:   cleaner.path.AppMountMappedDir("volume-1"),
+			Type:   "overlay",
+			Opts:   []string{"lowerdir=" + cleaner.path.AgentSharedBinaryDirForAgent("mounted")},
 		})
 
-		require.NotEmpty(t, relevantBins)
+		cleaner.removeUntrackedBinaries()
+
+		exists, _ := cleaner.fs.Exists(cleaner.path.AgentSharedBinaryDirForAgent("mounted"))
+		assert.True(t, exists)
 	})
 }
 
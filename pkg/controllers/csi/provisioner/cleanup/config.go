@@ -2,8 +2,32 @@ package cleanup
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
-	log = logd.Get().WithName("csi-cleanup")
+	log             = logd.Get().WithName("csi-cleanup")
+	diskUsageMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "dynatrace",
+		Subsystem: "csi_provisioner",
+		Name:      "codemodules_disk_usage_bytes",
+		Help:      "Disk space used by the CodeModules referenced by a DynaKube on the node",
+	}, []string{"dynakube"})
 )
+
+func init() {
+	metrics.Registry.MustRegister(diskUsageMetric)
+}
+
+func setDiskUsageMetric(diskUsage map[string]int64) {
+	diskUsageMetric.Reset()
+
+	for dkName, size := range diskUsage {
+		diskUsageMetric.WithLabelValues(dkName).Set(float64(size))
+	}
+
+	if len(diskUsage) > 0 {
+		log.Info("disk usage of the CodeModules per dynakube", "usage", diskUsage)
+	}
+}
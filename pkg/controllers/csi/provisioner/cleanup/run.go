@@ -4,8 +4,10 @@ import (
 	"context"
 	"os"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/quota"
 	"github.com/spf13/afero"
 	"k8s.io/mount-utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,7 +17,12 @@ type Cleaner struct {
 	fs        afero.Afero
 	apiReader client.Reader
 	mounter   mount.Interface
+	inventory *metadata.InventoryStore
 	path      metadata.PathResolver
+	quota     quota.Quota
+
+	// inventorySeeded is set once the inventory was reconciled with the filesystem and the mounts, which happens on the first run
+	inventorySeeded bool
 }
 
 // fsState collects all the "top-level" folders we care about and categorizes them
@@ -28,50 +35,65 @@ type fsState struct {
 	hostDks []string
 }
 
-func New(fs afero.Afero, apiReader client.Reader, path metadata.PathResolver, mounter mount.Interface) *Cleaner {
+func New(fs afero.Afero, apiReader client.Reader, path metadata.PathResolver, mounter mount.Interface, inventory *metadata.InventoryStore, quota quota.Quota) *Cleaner {
 	return &Cleaner{
 		fs:        fs,
 		apiReader: apiReader,
 		path:      path,
 		mounter:   mounter,
+		inventory: inventory,
+		quota:     quota,
 	}
 }
 
-// Run will only execute the cleanup logic if enough time has passed from the previous run, to not overload the IO of the node
+// Run removes the unreferenced (or evicted) CodeModules every time, as that only relies on the inventory.
+// The rest of the cleanup, that has to inspect the filesystem, only runs if enough time has passed from the previous run, to not overload the IO of the node
 func (c *Cleaner) Run(ctx context.Context) error {
+	dks, err := metadata.GetRelevantDynaKubes(ctx, c.apiReader)
+	if err != nil {
+		log.Info("failed to list available dynakubes, skipping cleanup")
+
+		return err
+	}
+
+	c.removeUnusedBinaries(dks)
+
 	tickerResetFunc := checkTicker()
 	if tickerResetFunc == nil {
 		return nil
 	}
 	defer tickerResetFunc()
 
-	return c.run(ctx)
+	return c.removeLeftovers(dks)
 }
 
 // InstantRun will always execute the cleanup logic ignoring the time passed from previous run
 func (c *Cleaner) InstantRun(ctx context.Context) error {
 	defer resetTickerAfterDelete()
 
-	return c.run(ctx)
-}
-
-func (c *Cleaner) run(ctx context.Context) error {
-	fsState, err := c.getFilesystemState()
+	dks, err := metadata.GetRelevantDynaKubes(ctx, c.apiReader)
 	if err != nil {
+		log.Info("failed to list available dynakubes, skipping cleanup")
+
 		return err
 	}
 
-	c.removeDeprecatedMounts(fsState)
+	c.removeUnusedBinaries(dks)
 
-	dks, err := metadata.GetRelevantDynaKubes(ctx, c.apiReader)
-	if err != nil {
-		log.Info("failed to list available dynakubes, skipping cleanup")
+	return c.removeLeftovers(dks)
+}
 
+func (c *Cleaner) removeLeftovers(dks []dynakube.DynaKube) error {
+	fsState, err := c.getFilesystemState()
+	if err != nil {
 		return err
 	}
 
+	c.removeDeprecatedMounts(fsState)
 	c.removeHostMounts(dks, fsState)
-	c.removeUnusedBinaries(dks, fsState)
+	c.removeOldBinarySymlinks(dks, fsState)
+	c.removeUntrackedBinaries()
+	c.updateOtherDiskUsage()
 
 	return nil
 }
@@ -98,7 +120,8 @@ func (c *Cleaner) getFilesystemState() (fsState fsState, err error) { //nolint:r
 			fileInfo.Name() == dtcsi.SharedAppMountsDir ||
 			fileInfo.Name() == dtcsi.SharedJobWorkDir ||
 			fileInfo.Name() == dtcsi.SharedDynaKubesDir ||
-			fileInfo.Name() == dtcsi.SharedAgentBinDir {
+			fileInfo.Name() == dtcsi.SharedAgentBinDir ||
+			fileInfo.Name() == dtcsi.SharedQuarantineDir {
 			continue
 		}
 
@@ -64,10 +64,11 @@ func TestGetFilesystemState(t *testing.T) {
 
 		cleaner.fs.Mkdir(cleaner.path.AgentSharedBinaryDirBase(), os.ModePerm)
 		cleaner.fs.Mkdir(cleaner.path.AppMountsBaseDir(), os.ModePerm)
+		cleaner.fs.Mkdir(cleaner.path.AgentQuarantineDir(), os.ModePerm)
 
 		files, err := cleaner.fs.ReadDir(cleaner.path.RootDir)
 		require.NoError(t, err)
-		assert.Len(t, files, 2)
+		assert.Len(t, files, 3)
 
 		fsState, err := cleaner.getFilesystemState()
 
@@ -76,7 +77,7 @@ func TestGetFilesystemState(t *testing.T) {
 
 		files, err = cleaner.fs.ReadDir(cleaner.path.RootDir)
 		require.NoError(t, err)
-		assert.Len(t, files, 2)
+		assert.Len(t, files, 3)
 	})
 
 	t.Run("get fsState", func(t *testing.T) {
@@ -145,11 +146,15 @@ func TestAddRelevantPath(t *testing.T) {
 func createCleaner(t *testing.T) *Cleaner {
 	t.Helper()
 
+	fs := afero.NewMemMapFs()
+	path := metadata.PathResolver{}
+
 	return &Cleaner{
-		fs:        afero.Afero{Fs: afero.NewMemMapFs()},
+		fs:        afero.Afero{Fs: fs},
 		mounter:   mount.NewFakeMounter(nil),
 		apiReader: fake.NewClient(),
-		path:      metadata.PathResolver{},
+		path:      path,
+		inventory: metadata.NewInventoryStore(fs, path),
 	}
 }
 
@@ -0,0 +1,62 @@
+package cleanup
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+)
+
+// updateOtherDiskUsage records how much of the data directory is used by something else than the CodeModules in the shared binary directory,
+// so the disk quota also covers the upper dirs of the app-mounts, the config of the DynaKubes and the host mounts.
+// The mount points are skipped, the overlays would count the CodeModules a second time.
+func (c *Cleaner) updateOtherDiskUsage() {
+	mountPoints, err := c.mounter.List()
+	if err != nil {
+		log.Info("failed to list the mount points, skipping disk usage measurement", "err", err.Error())
+
+		return
+	}
+
+	skipDirs := map[string]bool{
+		c.path.AgentSharedBinaryDirBase(): true,
+	}
+
+	for _, mountPoint := range mountPoints {
+		skipDirs[filepath.Clean(mountPoint.Path)] = true
+	}
+
+	var otherSize int64
+
+	err = c.fs.Walk(c.path.RootDir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) { // removed in the meantime, like an unpublished volume
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if info.IsDir() && skipDirs[filepath.Clean(path)] {
+			return filepath.SkipDir
+		}
+
+		if info.Mode().IsRegular() && path != c.path.InventoryFile() {
+			otherSize += info.Size()
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Info("failed to measure the disk usage of the data directory", "err", err.Error())
+
+		return
+	}
+
+	err = c.inventory.Update(func(inventory *metadata.Inventory) error {
+		inventory.OtherSizeBytes = otherSize
+
+		return nil
+	})
+	if err != nil {
+		log.Info("failed to record the disk usage of the data directory", "err", err.Error())
+	}
+}
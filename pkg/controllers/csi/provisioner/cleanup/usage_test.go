@@ -0,0 +1,44 @@
+package cleanup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/mount-utils"
+)
+
+func TestUpdateOtherDiskUsage(t *testing.T) {
+	t.Run("empty fs -> no panic", func(t *testing.T) {
+		cleaner := createCleaner(t)
+
+		cleaner.updateOtherDiskUsage()
+	})
+
+	t.Run("CodeModules and mount points are not counted", func(t *testing.T) {
+		cleaner := createCleaner(t)
+
+		writeFile := func(path string, size int) {
+			require.NoError(t, cleaner.fs.WriteFile(path, make([]byte, size), os.ModePerm))
+		}
+
+		writeFile(filepath.Join(cleaner.path.AgentSharedBinaryDirForAgent("1.2.3"), "agent.so"), 100)
+		writeFile(filepath.Join(cleaner.path.AppMountMappedDir("volume-1"), "agent.so"), 100)
+		writeFile(filepath.Join(cleaner.path.AppMountVarDir("volume-1"), "agent.log"), 10)
+		writeFile(cleaner.path.AgentSharedRuxitAgentProcConf("dk"), 5)
+
+		mockMountPoints(t, cleaner, mount.MountPoint{
+			Device: "overlay",
+			Path:   cleaner.path.AppMountMappedDir("volume-1"),
+			Type:   "overlay",
+		})
+
+		cleaner.updateOtherDiskUsage()
+
+		inventory, err := cleaner.inventory.Read()
+		require.NoError(t, err)
+		assert.Equal(t, int64(15), inventory.OtherSizeBytes)
+	})
+}
@@ -25,9 +25,11 @@ import (
 	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/cleanup"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/quota"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/bundle"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/image"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/job"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
@@ -35,6 +37,7 @@ import (
 	"github.com/spf13/afero"
 	batchv1 "k8s.io/api/batch/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/mount-utils"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,6 +54,7 @@ const (
 type urlInstallerBuilder func(afero.Fs, dtclient.Client, *url.Properties) installer.Installer
 type imageInstallerBuilder func(context.Context, afero.Fs, *image.Properties) (installer.Installer, error)
 type jobInstallerBuilder func(context.Context, afero.Fs, *job.Properties) installer.Installer
+type bundleInstallerBuilder func(afero.Fs, *bundle.Properties) installer.Installer
 
 // OneAgentProvisioner reconciles a DynaKube object
 type OneAgentProvisioner struct {
@@ -62,25 +66,37 @@ type OneAgentProvisioner struct {
 	urlInstallerBuilder    urlInstallerBuilder
 	imageInstallerBuilder  imageInstallerBuilder
 	jobInstallerBuilder    jobInstallerBuilder
+	bundleInstallerBuilder bundleInstallerBuilder
 	cleaner                *cleanup.Cleaner
+	inventory              *metadata.InventoryStore
+	recorder               record.EventRecorder
+	quota                  quota.Quota
 	path                   metadata.PathResolver
+	nodeName               string
 }
 
 // NewOneAgentProvisioner returns a new OneAgentProvisioner
 func NewOneAgentProvisioner(mgr manager.Manager, opts dtcsi.CSIOptions) *OneAgentProvisioner {
 	fs := afero.NewOsFs()
 	path := metadata.PathResolver{RootDir: opts.RootDir}
+	inventory := metadata.NewInventoryStore(fs, path)
+	diskQuota := quota.FromEnv()
 
 	return &OneAgentProvisioner{
 		apiReader:              mgr.GetAPIReader(),
 		kubeClient:             mgr.GetClient(),
 		fs:                     fs,
 		path:                   path,
+		inventory:              inventory,
+		recorder:               mgr.GetEventRecorderFor("dynatrace-csi-provisioner"),
+		quota:                  diskQuota,
+		nodeName:               opts.NodeId,
 		dynatraceClientBuilder: dynatraceclient.NewBuilder(mgr.GetAPIReader()),
 		urlInstallerBuilder:    url.NewUrlInstaller,
 		imageInstallerBuilder:  image.NewImageInstaller,
 		jobInstallerBuilder:    job.NewInstaller,
-		cleaner:                cleanup.New(afero.Afero{Fs: fs}, mgr.GetAPIReader(), path, mount.New("")),
+		bundleInstallerBuilder: bundle.NewInstaller,
+		cleaner:                cleanup.New(afero.Afero{Fs: fs}, mgr.GetAPIReader(), path, mount.New(""), inventory, diskQuota),
 	}
 }
 
@@ -130,17 +146,25 @@ func (provisioner *OneAgentProvisioner) Reconcile(ctx context.Context, request r
 		return reconcile.Result{RequeueAfter: longRequeueDuration}, nil
 	}
 
-	if dk.OneAgent().GetCodeModulesImage() == "" && dk.OneAgent().GetCodeModulesVersion() == "" {
+	if dk.OneAgent().GetCodeModulesBundle() == nil && dk.OneAgent().GetCodeModulesImage() == "" && dk.OneAgent().GetCodeModulesVersion() == "" {
 		log.Info("dynakube status is not yet ready, requeuing", "dynakube", dk.Name)
 
 		return reconcile.Result{RequeueAfter: shortRequeueDuration}, nil
 	}
 
 	err = provisioner.installAgent(ctx, dk)
+	if reportErr := provisioner.reportVerification(ctx, &dk, err); reportErr != nil {
+		log.Info("failed to report the CodeModules verification", "dynakube", dk.Name, "err", reportErr.Error())
+	}
+
 	if err != nil && errors.Is(err, errNotReady) {
 		log.Info(err.Error(), "dynakube", dk.Name)
 
 		return reconcile.Result{RequeueAfter: notReadyRequeueDuration}, nil
+	} else if err != nil && errors.Is(err, quota.ErrExceeded) {
+		_ = provisioner.cleaner.Run(ctx)
+
+		return reconcile.Result{RequeueAfter: defaultRequeueDuration}, nil
 	} else if err != nil {
 		return reconcile.Result{}, err
 	}
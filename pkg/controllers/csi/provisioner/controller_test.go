@@ -15,6 +15,7 @@ import (
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/cleanup"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/quota"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/processmoduleconfigsecret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
@@ -204,12 +205,15 @@ func createProvisioner(t *testing.T, objs ...client.Object) OneAgentProvisioner
 	fs := afero.NewMemMapFs()
 	path := metadata.PathResolver{}
 	apiReader := fake.NewClient(objs...)
+	inventory := metadata.NewInventoryStore(fs, path)
 
 	return OneAgentProvisioner{
-		fs:        fs,
-		path:      path,
-		apiReader: apiReader,
-		cleaner:   cleanup.New(afero.Afero{Fs: fs}, apiReader, path, mount.NewFakeMounter(nil)),
+		fs:         fs,
+		path:       path,
+		apiReader:  apiReader,
+		kubeClient: apiReader,
+		inventory:  inventory,
+		cleaner:    cleanup.New(afero.Afero{Fs: fs}, apiReader, path, mount.NewFakeMounter(nil), inventory, quota.Quota{}),
 	}
 }
 
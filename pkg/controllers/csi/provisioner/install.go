@@ -4,14 +4,18 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"path/filepath"
 	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/quota"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/processmoduleconfigsecret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/image"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/integrity"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/job"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/symlink"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
@@ -23,14 +27,23 @@ const notReadyRequeueDuration = 30 * time.Second
 var errNotReady = errors.New("download job is not ready yet")
 
 func (provisioner *OneAgentProvisioner) installAgent(ctx context.Context, dk dynakube.DynaKube) error {
-	agentInstaller, err := provisioner.getInstaller(ctx, dk)
+	if dk.OneAgent().GetCodeModulesBundle() != nil {
+		return provisioner.installAgentFromBundle(ctx, dk)
+	}
+
+	targetDir := provisioner.getTargetDir(dk)
+
+	agentInstaller, verification, err := provisioner.getInstaller(ctx, dk, provisioner.quotaSizeCheck(ctx, dk, targetDir))
 	if err != nil {
 		log.Info("failed to create CodeModule installer", "dk", dk.GetName())
 
 		return err
 	}
 
-	targetDir := provisioner.getTargetDir(dk)
+	err = provisioner.checkQuota(ctx, dk, targetDir)
+	if err != nil {
+		return err
+	}
 
 	ready, err := agentInstaller.InstallAgent(ctx, targetDir)
 	if err != nil {
@@ -41,36 +54,57 @@ func (provisioner *OneAgentProvisioner) installAgent(ctx context.Context, dk dyn
 		return errNotReady
 	}
 
+	err = provisioner.addToInventory(ctx, dk, targetDir, verification)
+	if err != nil {
+		return err
+	}
+
 	err = provisioner.createLatestVersionSymlink(dk, targetDir)
 	if err != nil {
 		return err
 	}
 
-	return provisioner.setupAgentConfigDir(ctx, dk, targetDir)
+	err = provisioner.setupAgentConfigDir(ctx, dk, targetDir)
+	if err != nil {
+		return err
+	}
+
+	return provisioner.installCanary(ctx, dk)
 }
 
-func (provisioner *OneAgentProvisioner) getInstaller(ctx context.Context, dk dynakube.DynaKube) (installer.Installer, error) {
+// getInstaller also returns the verification policy the installer enforces, which is nil if the CodeModules are not verified during the install.
+// The sizeCheck is passed to the installers that know the size of the CodeModules before unpacking them.
+func (provisioner *OneAgentProvisioner) getInstaller(ctx context.Context, dk dynakube.DynaKube, sizeCheck installer.SizeCheck) (installer.Installer, *integrity.Policy, error) {
 	switch {
 	case dk.FF().IsNodeImagePull():
-		return provisioner.getJobInstaller(ctx, dk), nil
+		jobInstaller, err := provisioner.getJobInstaller(ctx, dk)
+
+		return jobInstaller, nil, err
 	case dk.OneAgent().GetCustomCodeModulesImage() != "":
+		// the image of the status already passed the verification and is pinned to its digest
 		props := &image.Properties{
 			ImageUri:     dk.OneAgent().GetCodeModulesImage(),
 			ApiReader:    provisioner.apiReader,
 			Dynakube:     &dk,
 			PathResolver: provisioner.path,
+			SizeCheck:    sizeCheck,
 		}
 
 		imageInstaller, err := provisioner.imageInstallerBuilder(ctx, provisioner.fs, props)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		return imageInstaller, nil
+		return imageInstaller, nil, nil
 	default:
 		dtc, err := buildDtc(provisioner, ctx, dk)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		verification, err := provisioner.getVerificationPolicy(ctx, dk, dk.OneAgent().GetCodeModulesVersion())
+		if err != nil {
+			return nil, nil, err
 		}
 
 		props := &url.Properties{
@@ -82,18 +116,21 @@ func (provisioner *OneAgentProvisioner) getInstaller(ctx context.Context, dk dyn
 			TargetVersion: dk.OneAgent().GetCodeModulesVersion(),
 			SkipMetadata:  true,
 			PathResolver:  provisioner.path,
+			Verification:  verification,
+			SizeCheck:     sizeCheck,
 		}
 
 		urlInstaller := provisioner.urlInstallerBuilder(provisioner.fs, dtc, props)
 
-		return urlInstaller, nil
+		return urlInstaller, verification, nil
 	}
 }
 
-func (provisioner *OneAgentProvisioner) getJobInstaller(ctx context.Context, dk dynakube.DynaKube) installer.Installer {
-	imageUri := dk.OneAgent().GetCustomCodeModulesImage()
+// getJobInstaller pulls the image of the status on the node, which already passed the verification and is pinned to its digest.
+func (provisioner *OneAgentProvisioner) getJobInstaller(ctx context.Context, dk dynakube.DynaKube) (installer.Installer, error) {
+	imageUri := dk.OneAgent().GetCodeModulesImage()
 	if imageUri == "" {
-		imageUri = "public.ecr.aws/dynatrace/dynatrace-codemodules:" + dk.OneAgent().GetCodeModulesVersion()
+		return nil, errors.New("the verified code modules image is not yet available in the status")
 	}
 
 	props := &job.Properties{
@@ -105,7 +142,7 @@ func (provisioner *OneAgentProvisioner) getJobInstaller(ctx context.Context, dk
 		PathResolver: provisioner.path,
 	}
 
-	return provisioner.jobInstallerBuilder(ctx, provisioner.fs, props)
+	return provisioner.jobInstallerBuilder(ctx, provisioner.fs, props), nil
 }
 
 func (provisioner *OneAgentProvisioner) getTargetDir(dk dynakube.DynaKube) string {
@@ -136,6 +173,49 @@ func (provisioner *OneAgentProvisioner) createLatestVersionSymlink(dk dynakube.D
 	return err
 }
 
+// addToInventory records the installed CodeModules as the latest of the DynaKube, so the cleanup keeps them as long as they are referenced.
+// If the CodeModules turn out to not fit into the disk quota, they are removed again and the install is refused.
+// The verification is only recorded for newly installed CodeModules, as the installer skips the verification of the ones already on disk.
+func (provisioner *OneAgentProvisioner) addToInventory(ctx context.Context, dk dynakube.DynaKube, targetDir string, verification *integrity.Policy) error {
+	agent := filepath.Base(targetDir)
+
+	var sizeBytes int64
+
+	err := provisioner.inventory.Update(func(inventory *metadata.Inventory) error {
+		if !inventory.HasAgent(agent) {
+			size, err := metadata.DirSize(provisioner.fs, targetDir)
+			if err != nil {
+				return err
+			}
+
+			sizeBytes = size
+
+			if !provisioner.fitsAsLatest(inventory, dk, agent, size) {
+				return quota.ErrExceeded
+			}
+
+			if verification != nil {
+				inventory.SetVerified(agent, verification.SHA256)
+			}
+		}
+
+		inventory.SetLatest(dk.GetName(), agent)
+		inventory.MarkUsed(agent, time.Now())
+		inventory.ClearRefused(dk.GetName())
+
+		return nil
+	})
+	if errors.Is(err, quota.ErrExceeded) {
+		if err := provisioner.fs.RemoveAll(targetDir); err != nil {
+			log.Error(err, "failed to remove CodeModules that exceed the disk quota", "path", targetDir)
+		}
+
+		return provisioner.refuseInstall(ctx, dk, agent, sizeBytes)
+	}
+
+	return err
+}
+
 func (provisioner *OneAgentProvisioner) setupAgentConfigDir(ctx context.Context, dk dynakube.DynaKube, targetDir string) error {
 	latestProcessModuleConfig, err := processmoduleconfigsecret.GetSecretData(ctx, provisioner.apiReader, dk.Name, dk.Namespace)
 	if err != nil {
@@ -1,9 +1,15 @@
 package csiprovisioner
 
 import (
+	"context"
 	"encoding/base64"
 	"testing"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/image"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/job"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,3 +31,52 @@ func TestGetTargetDir(t *testing.T) {
 		require.Contains(t, targetDir, expectedDir)
 	})
 }
+
+func TestGetInstaller(t *testing.T) {
+	const verifiedImage = "test-image@sha256:7173b809ca12ec5dee4506cd86be934c4596dd234ee82c0662eac04a8c2c71dc"
+
+	t.Run("image installer pulls the verified image of the status", func(t *testing.T) {
+		prov := createProvisioner(t)
+		dk := createDynaKubeWithImage(t)
+		dk.Status.CodeModules.ImageID = verifiedImage
+
+		var imageUri string
+
+		prov.imageInstallerBuilder = func(_ context.Context, _ afero.Fs, props *image.Properties) (installer.Installer, error) {
+			imageUri = props.ImageUri
+
+			return nil, nil
+		}
+
+		_, _, err := prov.getInstaller(t.Context(), *dk, nil)
+		require.NoError(t, err)
+		assert.Equal(t, verifiedImage, imageUri)
+	})
+
+	t.Run("job installer pulls the verified image of the status", func(t *testing.T) {
+		prov := createProvisioner(t)
+		dk := createDynaKubeWithJobFF(t)
+		dk.Status.CodeModules.ImageID = verifiedImage
+
+		var imageUri string
+
+		prov.jobInstallerBuilder = func(_ context.Context, _ afero.Fs, props *job.Properties) installer.Installer {
+			imageUri = props.ImageUri
+
+			return nil
+		}
+
+		_, _, err := prov.getInstaller(t.Context(), *dk, nil)
+		require.NoError(t, err)
+		assert.Equal(t, verifiedImage, imageUri)
+	})
+
+	t.Run("job installer without image in the status => error", func(t *testing.T) {
+		prov := createProvisioner(t)
+		dk := createDynaKubeWithJobFF(t)
+		dk.Status.CodeModules.ImageID = ""
+
+		_, _, err := prov.getInstaller(t.Context(), *dk, nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,113 @@
+package csiprovisioner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/quota"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const quotaExceededEvent = "CodeModulesQuotaExceeded"
+
+// checkQuota refuses the install before anything is downloaded, if the CodeModules would not fit into the disk quota.
+// The size of the new CodeModules is not known yet, so the size of the largest installed ones is used as an estimate.
+func (provisioner *OneAgentProvisioner) checkQuota(ctx context.Context, dk dynakube.DynaKube, targetDir string) error {
+	return provisioner.checkQuotaForSize(ctx, dk, targetDir, (*metadata.Inventory).LargestAgentSize)
+}
+
+// quotaSizeCheck checks the quota again, once the installer knows the size of the CodeModules from the download or the image manifest.
+// On a node without CodeModules, there is nothing to estimate the size from, so this is the first check that can refuse the install.
+func (provisioner *OneAgentProvisioner) quotaSizeCheck(ctx context.Context, dk dynakube.DynaKube, targetDir string) installer.SizeCheck {
+	return func(sizeBytes int64) error {
+		return provisioner.checkQuotaForSize(ctx, dk, targetDir, fixedSize(sizeBytes))
+	}
+}
+
+func (provisioner *OneAgentProvisioner) checkQuotaForSize(ctx context.Context, dk dynakube.DynaKube, targetDir string, size func(*metadata.Inventory) int64) error {
+	if !provisioner.quota.IsEnabled() {
+		return nil
+	}
+
+	agent := filepath.Base(targetDir)
+
+	inventory, err := provisioner.inventory.Read()
+	if err != nil {
+		return err
+	}
+
+	if inventory.HasAgent(agent) {
+		return nil
+	}
+
+	sizeBytes := size(inventory)
+	if provisioner.fitsAsLatest(inventory, dk, agent, sizeBytes) {
+		return nil
+	}
+
+	return provisioner.refuseInstall(ctx, dk, agent, sizeBytes)
+}
+
+func fixedSize(sizeBytes int64) func(*metadata.Inventory) int64 {
+	return func(*metadata.Inventory) int64 {
+		return sizeBytes
+	}
+}
+
+// fitsAsLatest checks if the CodeModules fit into the disk quota, once they replace the current latest of the DynaKube.
+// The inventory is modified, so it must not be persisted afterwards, unless the CodeModules are installed.
+func (provisioner *OneAgentProvisioner) fitsAsLatest(inventory *metadata.Inventory, dk dynakube.DynaKube, agent string, sizeBytes int64) bool {
+	inventory.AddAgent(agent, sizeBytes, time.Now())
+	inventory.SetLatest(dk.GetName(), agent)
+
+	return provisioner.quota.Fits(inventory, 0)
+}
+
+// refuseInstall records the refused install in the inventory, so the csi-server can fall back to letting the init-container download the CodeModules,
+// and informs about it via events on the node and the DynaKube.
+func (provisioner *OneAgentProvisioner) refuseInstall(ctx context.Context, dk dynakube.DynaKube, agent string, sizeBytes int64) error {
+	err := provisioner.inventory.Update(func(inventory *metadata.Inventory) error {
+		inventory.SetRefused(dk.GetName(), agent)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("CodeModules %s (about %d bytes) do not fit into the disk quota of %d bytes of the CSI driver on node %s, "+
+		"pods keep using the previous CodeModules or download them in the init-container",
+		agent, sizeBytes, provisioner.quota.LimitBytes, provisioner.nodeName)
+
+	log.Info("refused to install CodeModules", "dynakube", dk.GetName(), "reason", message)
+
+	if provisioner.recorder != nil {
+		if node, err := provisioner.getNode(ctx); err != nil {
+			log.Info("failed to get the node, no event is recorded for it", "node", provisioner.nodeName, "err", err.Error())
+		} else {
+			provisioner.recorder.Event(node, corev1.EventTypeWarning, quotaExceededEvent, message)
+		}
+
+		provisioner.recorder.Event(&dk, corev1.EventTypeWarning, quotaExceededEvent, message)
+	}
+
+	return errors.WithMessage(quota.ErrExceeded, message)
+}
+
+// getNode returns the node the csi-provisioner runs on, the events need its UID to show up when describing the node.
+func (provisioner *OneAgentProvisioner) getNode(ctx context.Context) (*corev1.Node, error) {
+	var node corev1.Node
+
+	if err := provisioner.apiReader.Get(ctx, types.NamespacedName{Name: provisioner.nodeName}, &node); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &node, nil
+}
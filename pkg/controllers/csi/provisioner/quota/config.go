@@ -0,0 +1,9 @@
+package quota
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+)
+
+var (
+	log = logd.Get().WithName("csi-quota")
+)
@@ -0,0 +1,124 @@
+package quota
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	limitEnv         = "DATA_QUOTA"
+	highWatermarkEnv = "EVICTION_HIGH_WATERMARK"
+	lowWatermarkEnv  = "EVICTION_LOW_WATERMARK"
+
+	defaultHighWatermark = 90
+	defaultLowWatermark  = 75
+)
+
+var ErrExceeded = errors.New("CodeModules would not fit into the disk quota of the CSI driver")
+
+// Quota limits the disk space the data directory of the CSI driver may use, only the CodeModules in it can be evicted.
+// Without a limit, the CodeModules are removed as soon as they are no longer referenced.
+// With a limit, the unreferenced CodeModules are kept, so they don't have to be downloaded again, until the usage crosses the high watermark,
+// then the least recently used ones are evicted until the usage is below the low watermark.
+type Quota struct {
+	LimitBytes int64
+	// HighWatermark and LowWatermark are percentages of the LimitBytes
+	HighWatermark int64
+	LowWatermark  int64
+}
+
+// FromEnv creates the Quota from the environment of the csi-provisioner, invalid values are ignored.
+func FromEnv() Quota {
+	quota := Quota{
+		HighWatermark: defaultHighWatermark,
+		LowWatermark:  defaultLowWatermark,
+	}
+
+	if rawLimit := os.Getenv(limitEnv); rawLimit != "" {
+		limit, err := resource.ParseQuantity(rawLimit)
+		if err != nil {
+			log.Info("disk quota could not be parsed, no quota is enforced", "env", limitEnv, "value", rawLimit)
+		} else {
+			quota.LimitBytes = limit.Value()
+		}
+	}
+
+	high := readPercentage(highWatermarkEnv, defaultHighWatermark)
+	low := readPercentage(lowWatermarkEnv, defaultLowWatermark)
+
+	if low > high {
+		log.Info("low watermark is above the high watermark, falling back to defaults", "high", high, "low", low)
+
+		return quota
+	}
+
+	quota.HighWatermark = high
+	quota.LowWatermark = low
+
+	return quota
+}
+
+func readPercentage(envName string, defaultValue int64) int64 {
+	rawValue := os.Getenv(envName)
+	if rawValue == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseInt(rawValue, 10, 64)
+	if err != nil || value <= 0 || value > 100 {
+		log.Info("watermark has to be a percentage between 1 and 100, falling back to default", "env", envName, "value", rawValue, "default", defaultValue)
+
+		return defaultValue
+	}
+
+	return value
+}
+
+func (quota Quota) IsEnabled() bool {
+	return quota.LimitBytes > 0
+}
+
+// Fits checks if CodeModules of the given size can be added, if all the CodeModules that are not referenced get evicted.
+// The rest of the data directory can't be evicted, so it counts against the quota as it is.
+func (quota Quota) Fits(inventory *metadata.Inventory, sizeBytes int64) bool {
+	if !quota.IsEnabled() {
+		return true
+	}
+
+	return inventory.ReferencedSize()+inventory.OtherSizeBytes+sizeBytes <= quota.LimitBytes
+}
+
+// Evictable returns the CodeModules that should be removed from the node.
+func (quota Quota) Evictable(inventory *metadata.Inventory) []string {
+	if !quota.IsEnabled() {
+		return inventory.UnreferencedAgents()
+	}
+
+	usage := inventory.TotalSize() + inventory.OtherSizeBytes
+	if usage <= quota.watermark(quota.HighWatermark) {
+		return nil
+	}
+
+	target := quota.watermark(quota.LowWatermark)
+
+	var evictable []string
+
+	for _, agent := range inventory.LeastRecentlyUsedAgents() {
+		if usage <= target {
+			break
+		}
+
+		evictable = append(evictable, agent)
+		usage -= inventory.Agents[agent].SizeBytes
+	}
+
+	return evictable
+}
+
+func (quota Quota) watermark(percentage int64) int64 {
+	return quota.LimitBytes * percentage / 100
+}
@@ -0,0 +1,105 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Run("nothing set -> disabled", func(t *testing.T) {
+		quota := FromEnv()
+
+		assert.False(t, quota.IsEnabled())
+		assert.Equal(t, int64(defaultHighWatermark), quota.HighWatermark)
+		assert.Equal(t, int64(defaultLowWatermark), quota.LowWatermark)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		t.Setenv(limitEnv, "2Gi")
+		t.Setenv(highWatermarkEnv, "80")
+		t.Setenv(lowWatermarkEnv, "50")
+
+		quota := FromEnv()
+
+		assert.True(t, quota.IsEnabled())
+		assert.Equal(t, int64(2*1024*1024*1024), quota.LimitBytes)
+		assert.Equal(t, int64(80), quota.HighWatermark)
+		assert.Equal(t, int64(50), quota.LowWatermark)
+	})
+
+	t.Run("invalid values -> defaults", func(t *testing.T) {
+		t.Setenv(limitEnv, "a lot")
+		t.Setenv(highWatermarkEnv, "120")
+		t.Setenv(lowWatermarkEnv, "-1")
+
+		quota := FromEnv()
+
+		assert.False(t, quota.IsEnabled())
+		assert.Equal(t, int64(defaultHighWatermark), quota.HighWatermark)
+		assert.Equal(t, int64(defaultLowWatermark), quota.LowWatermark)
+	})
+
+	t.Run("low above high -> defaults", func(t *testing.T) {
+		t.Setenv(highWatermarkEnv, "50")
+		t.Setenv(lowWatermarkEnv, "60")
+
+		quota := FromEnv()
+
+		assert.Equal(t, int64(defaultHighWatermark), quota.HighWatermark)
+		assert.Equal(t, int64(defaultLowWatermark), quota.LowWatermark)
+	})
+}
+
+func TestEvictable(t *testing.T) {
+	now := time.Now()
+
+	createInventory := func() *metadata.Inventory {
+		inventory := metadata.NewInventory()
+		inventory.AddAgent("latest", 30, now)
+		inventory.AddAgent("oldest", 20, now.Add(-3*time.Hour))
+		inventory.AddAgent("older", 20, now.Add(-2*time.Hour))
+		inventory.AddAgent("recently-used", 20, now.Add(-4*time.Hour))
+		inventory.MarkUsed("recently-used", now.Add(-time.Hour))
+		inventory.SetLatest("dk", "latest")
+
+		return inventory
+	}
+
+	t.Run("disabled -> all unreferenced", func(t *testing.T) {
+		quota := Quota{}
+
+		assert.Equal(t, []string{"older", "oldest", "recently-used"}, quota.Evictable(createInventory()))
+	})
+
+	t.Run("below high watermark -> nothing", func(t *testing.T) {
+		quota := Quota{LimitBytes: 100, HighWatermark: 90, LowWatermark: 50}
+
+		assert.Empty(t, quota.Evictable(createInventory()))
+	})
+
+	t.Run("above high watermark -> least recently used until below low watermark", func(t *testing.T) {
+		quota := Quota{LimitBytes: 100, HighWatermark: 80, LowWatermark: 60}
+
+		assert.Equal(t, []string{"oldest", "older"}, quota.Evictable(createInventory()))
+	})
+
+	t.Run("referenced are never evicted", func(t *testing.T) {
+		quota := Quota{LimitBytes: 10, HighWatermark: 80, LowWatermark: 60}
+
+		assert.Equal(t, []string{"oldest", "older", "recently-used"}, quota.Evictable(createInventory()))
+	})
+}
+
+func TestFits(t *testing.T) {
+	inventory := metadata.NewInventory()
+	inventory.AddAgent("latest", 30, time.Now())
+	inventory.AddAgent("unreferenced", 50, time.Now())
+	inventory.SetLatest("dk", "latest")
+
+	assert.True(t, Quota{}.Fits(inventory, 1000))
+	assert.True(t, Quota{LimitBytes: 100}.Fits(inventory, 70))
+	assert.False(t, Quota{LimitBytes: 100}.Fits(inventory, 71))
+}
@@ -0,0 +1,197 @@
+package csiprovisioner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/provisioner/quota"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestCheckQuota(t *testing.T) {
+	t.Run("no quota => install allowed", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createProvisioner(t)
+
+		err := prov.checkQuota(t.Context(), *dk, prov.path.AgentSharedBinaryDirForAgent("1.2.3"))
+		require.NoError(t, err)
+	})
+
+	t.Run("replacing the latest of the dynakube fits => install allowed", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createQuotaProvisioner(t, 100)
+		setInventory(t, prov, func(inventory *metadata.Inventory) {
+			inventory.AddAgent("1.2.2", 80, time.Now())
+			inventory.SetLatest(dk.Name, "1.2.2")
+		})
+
+		err := prov.checkQuota(t.Context(), *dk, prov.path.AgentSharedBinaryDirForAgent("1.2.3"))
+		require.NoError(t, err)
+	})
+
+	t.Run("already installed => install allowed", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createQuotaProvisioner(t, 100)
+		setInventory(t, prov, func(inventory *metadata.Inventory) {
+			inventory.AddAgent("1.2.3", 80, time.Now())
+			inventory.SetLatest("other-dk", "1.2.3")
+		})
+
+		err := prov.checkQuota(t.Context(), *dk, prov.path.AgentSharedBinaryDirForAgent("1.2.3"))
+		require.NoError(t, err)
+	})
+
+	t.Run("does not fit next to the referenced CodeModules => refused, events sent", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createQuotaProvisioner(t, 100)
+		setInventory(t, prov, func(inventory *metadata.Inventory) {
+			inventory.AddAgent("1.2.2", 80, time.Now())
+			inventory.SetLatest("other-dk", "1.2.2")
+		})
+
+		err := prov.checkQuota(t.Context(), *dk, prov.path.AgentSharedBinaryDirForAgent("1.2.3"))
+		require.ErrorIs(t, err, quota.ErrExceeded)
+
+		inventory, err := prov.inventory.Read()
+		require.NoError(t, err)
+		assert.True(t, inventory.IsRefused(dk.Name))
+		assert.False(t, inventory.HasAgent("1.2.3"))
+
+		recorder := prov.recorder.(*record.FakeRecorder)
+		assert.Len(t, recorder.Events, 2)
+	})
+
+	t.Run("node not found => refused, only dynakube event sent", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createQuotaProvisioner(t, 100)
+		prov.nodeName = "unknown"
+		setInventory(t, prov, func(inventory *metadata.Inventory) {
+			inventory.AddAgent("1.2.2", 80, time.Now())
+			inventory.SetLatest("other-dk", "1.2.2")
+		})
+
+		err := prov.checkQuota(t.Context(), *dk, prov.path.AgentSharedBinaryDirForAgent("1.2.3"))
+		require.ErrorIs(t, err, quota.ErrExceeded)
+
+		recorder := prov.recorder.(*record.FakeRecorder)
+		assert.Len(t, recorder.Events, 1)
+	})
+
+	t.Run("rest of the data directory counts against the quota => refused", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createQuotaProvisioner(t, 100)
+		setInventory(t, prov, func(inventory *metadata.Inventory) {
+			inventory.AddAgent("1.2.2", 40, time.Now())
+			inventory.SetLatest(dk.Name, "1.2.2")
+			inventory.OtherSizeBytes = 70
+		})
+
+		err := prov.checkQuota(t.Context(), *dk, prov.path.AgentSharedBinaryDirForAgent("1.2.3"))
+		require.ErrorIs(t, err, quota.ErrExceeded)
+	})
+}
+
+func TestQuotaSizeCheck(t *testing.T) {
+	t.Run("nothing to estimate from => installer size is checked", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createQuotaProvisioner(t, 100)
+		targetDir := prov.path.AgentSharedBinaryDirForAgent("1.2.3")
+
+		require.NoError(t, prov.checkQuota(t.Context(), *dk, targetDir))
+
+		sizeCheck := prov.quotaSizeCheck(t.Context(), *dk, targetDir)
+		require.NoError(t, sizeCheck(80))
+
+		err := sizeCheck(150)
+		require.ErrorIs(t, err, quota.ErrExceeded)
+
+		inventory, err := prov.inventory.Read()
+		require.NoError(t, err)
+		assert.True(t, inventory.IsRefused(dk.Name))
+		assert.False(t, inventory.HasAgent("1.2.3"))
+	})
+
+	t.Run("no quota => install allowed", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createProvisioner(t)
+
+		err := prov.quotaSizeCheck(t.Context(), *dk, prov.path.AgentSharedBinaryDirForAgent("1.2.3"))(150)
+		require.NoError(t, err)
+	})
+}
+
+func TestAddToInventory(t *testing.T) {
+	t.Run("fits => recorded as latest, refusal cleared", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createQuotaProvisioner(t, 100)
+		targetDir := createAgentDir(t, prov, "1.2.3", 50)
+		setInventory(t, prov, func(inventory *metadata.Inventory) {
+			inventory.SetRefused(dk.Name, "1.2.3")
+		})
+
+		err := prov.addToInventory(t.Context(), *dk, targetDir, nil)
+		require.NoError(t, err)
+
+		inventory, err := prov.inventory.Read()
+		require.NoError(t, err)
+		assert.Equal(t, int64(50), inventory.Agents["1.2.3"].SizeBytes)
+		assert.Equal(t, "1.2.3", inventory.Latest[dk.Name])
+		assert.False(t, inventory.IsRefused(dk.Name))
+	})
+
+	t.Run("actual size does not fit => removed, refused", func(t *testing.T) {
+		dk := createDynaKubeBase(t)
+		prov := createQuotaProvisioner(t, 100)
+		targetDir := createAgentDir(t, prov, "1.2.3", 150)
+
+		err := prov.addToInventory(t.Context(), *dk, targetDir, nil)
+		require.ErrorIs(t, err, quota.ErrExceeded)
+
+		exists, _ := afero.Exists(prov.fs, targetDir)
+		assert.False(t, exists)
+
+		inventory, err := prov.inventory.Read()
+		require.NoError(t, err)
+		assert.False(t, inventory.HasAgent("1.2.3"))
+		assert.Empty(t, inventory.Latest)
+		assert.True(t, inventory.IsRefused(dk.Name))
+	})
+}
+
+func createQuotaProvisioner(t *testing.T, limitBytes int64) OneAgentProvisioner {
+	t.Helper()
+
+	prov := createProvisioner(t, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: testNodeName, UID: "node-uid"}})
+	prov.nodeName = testNodeName
+	prov.quota = quota.Quota{LimitBytes: limitBytes, HighWatermark: 90, LowWatermark: 75}
+	prov.recorder = record.NewFakeRecorder(10)
+
+	return prov
+}
+
+func setInventory(t *testing.T, prov OneAgentProvisioner, setup func(inventory *metadata.Inventory)) {
+	t.Helper()
+
+	err := prov.inventory.Update(func(inventory *metadata.Inventory) error {
+		setup(inventory)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func createAgentDir(t *testing.T, prov OneAgentProvisioner, agent string, sizeBytes int) string {
+	t.Helper()
+
+	targetDir := prov.path.AgentSharedBinaryDirForAgent(agent)
+	require.NoError(t, afero.WriteFile(prov.fs, filepath.Join(targetDir, "agent.so"), make([]byte, sizeBytes), 0600))
+
+	return targetDir
+}
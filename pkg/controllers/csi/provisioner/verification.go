@@ -0,0 +1,114 @@
+package csiprovisioner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/integrity"
+	k8sconfigmap "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/configmap"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	verificationDigestSuffix       = ".sha256"
+	verificationSignatureSuffix    = ".sig"
+	verificationCosignBundleSuffix = ".bundle"
+	verificationTrustedKeysKey     = "keys"
+)
+
+// getVerificationPolicy builds the policy the downloaded CodeModules archive of the given version has to match, based on the ConfigMap set in the DynaKube.
+// A version without a published digest is rejected right away, so nothing unverified is ever downloaded.
+func (provisioner *OneAgentProvisioner) getVerificationPolicy(ctx context.Context, dk dynakube.DynaKube, version string) (*integrity.Policy, error) {
+	verificationSpec := dk.OneAgent().GetCodeModulesVerification()
+	if verificationSpec == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	var configMap corev1.ConfigMap
+
+	err := provisioner.apiReader.Get(ctx, client.ObjectKey{Name: verificationSpec.ConfigMap, Namespace: dk.Namespace}, &configMap)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get CodeModules verification data from %s configmap", verificationSpec.ConfigMap)
+	}
+
+	digest := configMap.Data[version+verificationDigestSuffix]
+	if digest == "" {
+		return nil, errors.WithMessagef(integrity.ErrVerificationFailed, "no sha256 digest for CodeModules version %s in %s configmap", version, verificationSpec.ConfigMap)
+	}
+
+	policy := &integrity.Policy{
+		SHA256:           digest,
+		TrustedKeys:      []byte(configMap.Data[verificationTrustedKeysKey]),
+		RequireSignature: verificationSpec.RequireSignature,
+	}
+
+	if signature, ok := configMap.Data[version+verificationSignatureSuffix]; ok {
+		policy.Signature = []byte(strings.TrimSpace(signature))
+	} else if cosignBundle, ok := configMap.Data[version+verificationCosignBundleSuffix]; ok {
+		policy.Signature, err = integrity.SignatureFromCosignBundle([]byte(cosignBundle))
+		if err != nil {
+			return nil, errors.WithMessage(integrity.ErrVerificationFailed, err.Error())
+		}
+	}
+
+	return policy, nil
+}
+
+// reportVerification reports the outcome of the CodeModules verification on this node in the ConfigMap of the node.
+// Only the outcome of a verification is reported, other install errors don't change the report. The operator aggregates the reports in the DynaKube status.
+// CodeModules that were already on disk are not verified again, so their outcome is the one recorded in the inventory when they were installed.
+func (provisioner *OneAgentProvisioner) reportVerification(ctx context.Context, dk *dynakube.DynaKube, installErr error) error {
+	query := k8sconfigmap.Query(provisioner.kubeClient, provisioner.apiReader, log)
+
+	if dk.OneAgent().GetCodeModulesVerification() == nil {
+		report, err := query.Get(ctx, client.ObjectKey{Name: integrity.ReportName(dk.Name, provisioner.nodeName), Namespace: dk.Namespace})
+		if k8serrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		return query.Delete(ctx, report)
+	}
+
+	if installErr != nil && !errors.Is(installErr, integrity.ErrVerificationFailed) {
+		return nil
+	}
+
+	verificationErr := installErr
+	if verificationErr == nil {
+		agent, verified, err := provisioner.isLatestVerified(dk)
+		if err != nil {
+			return err
+		}
+
+		if !verified {
+			verificationErr = errors.WithMessagef(integrity.ErrVerificationFailed, "CodeModules %s on the node were installed without verification", agent)
+		}
+	}
+
+	report, err := integrity.BuildReport(dk, provisioner.nodeName, dk.OneAgent().GetCodeModulesVersion(), verificationErr)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.CreateOrUpdate(ctx, report)
+
+	return err
+}
+
+// isLatestVerified checks if the latest CodeModules of the DynaKube passed the verification when they were installed, as recorded in the inventory.
+func (provisioner *OneAgentProvisioner) isLatestVerified(dk *dynakube.DynaKube) (string, bool, error) {
+	inventory, err := provisioner.inventory.Read()
+	if err != nil {
+		return "", false, err
+	}
+
+	agent := inventory.Latest[dk.Name]
+
+	return agent, inventory.Agents[agent].VerifiedSHA256 != "", nil
+}
@@ -0,0 +1,279 @@
+package csiprovisioner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/integrity"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	installermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/injection/codemodule/installer"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	testVerificationConfigMap = "codemodules-digests"
+	testNodeName              = "test-node"
+	testDigest                = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+func TestGetVerificationPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no verification => no policy", func(t *testing.T) {
+		dk := createDynaKubeWithVersion(t)
+		prov := createProvisioner(t, dk)
+
+		policy, err := prov.getVerificationPolicy(ctx, *dk, "test-version")
+
+		require.NoError(t, err)
+		assert.Nil(t, policy)
+	})
+
+	t.Run("digest and signature", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createProvisioner(t, dk, createVerificationConfigMap(t, dk, map[string]string{
+			"test-version.sha256": testDigest,
+			"test-version.sig":    "c2lnbmF0dXJl\n",
+			"keys":                "trusted-keys",
+		}))
+
+		policy, err := prov.getVerificationPolicy(ctx, *dk, "test-version")
+
+		require.NoError(t, err)
+		assert.Equal(t, testDigest, policy.SHA256)
+		assert.Equal(t, "c2lnbmF0dXJl", string(policy.Signature))
+		assert.Equal(t, "trusted-keys", string(policy.TrustedKeys))
+		assert.True(t, policy.RequireSignature)
+	})
+
+	t.Run("digest and cosign bundle", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createProvisioner(t, dk, createVerificationConfigMap(t, dk, map[string]string{
+			"test-version.sha256": testDigest,
+			"test-version.bundle": `{"base64Signature":"c2lnbmF0dXJl"}`,
+		}))
+
+		policy, err := prov.getVerificationPolicy(ctx, *dk, "test-version")
+
+		require.NoError(t, err)
+		assert.Equal(t, "c2lnbmF0dXJl", string(policy.Signature))
+	})
+
+	t.Run("invalid cosign bundle => verification failed", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createProvisioner(t, dk, createVerificationConfigMap(t, dk, map[string]string{
+			"test-version.sha256": testDigest,
+			"test-version.bundle": `{}`,
+		}))
+
+		_, err := prov.getVerificationPolicy(ctx, *dk, "test-version")
+
+		require.ErrorIs(t, err, integrity.ErrVerificationFailed)
+	})
+
+	t.Run("no digest for version => verification failed", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createProvisioner(t, dk, createVerificationConfigMap(t, dk, map[string]string{
+			"other-version.sha256": testDigest,
+		}))
+
+		_, err := prov.getVerificationPolicy(ctx, *dk, "test-version")
+
+		require.ErrorIs(t, err, integrity.ErrVerificationFailed)
+	})
+
+	t.Run("configmap missing => error", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createProvisioner(t, dk)
+
+		_, err := prov.getVerificationPolicy(ctx, *dk, "test-version")
+
+		require.Error(t, err)
+		require.NotErrorIs(t, err, integrity.ErrVerificationFailed)
+	})
+}
+
+func TestReconcileWithVerification(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("policy is passed to url installer, success is reported", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createVerifyingProvisioner(t, dk)
+
+		var props *url.Properties
+
+		successfulInstaller := createSuccessfulInstaller(t)
+		prov.urlInstallerBuilder = func(_ afero.Fs, _ dtclient.Client, p *url.Properties) installer.Installer {
+			props = p
+
+			return successfulInstaller
+		}
+		createPMCSourceFile(t, prov, dk)
+
+		_, err := prov.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(dk)})
+		require.NoError(t, err)
+
+		require.NotNil(t, props.Verification)
+		assert.Equal(t, testDigest, props.Verification.SHA256)
+
+		report := getVerificationReport(t, prov, dk)
+		assert.Equal(t, "true", report.Data[integrity.ReportVerifiedKey])
+		assert.Equal(t, testNodeName, report.Data[integrity.ReportNodeKey])
+		assert.Equal(t, dk.OneAgent().GetCodeModulesVersion(), report.Data[integrity.ReportVersionKey])
+		require.Len(t, report.OwnerReferences, 1)
+		assert.Equal(t, dk.Name, report.OwnerReferences[0].Name)
+	})
+
+	t.Run("CodeModules installed before the verification => failure is reported, they aren't verified again", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createVerifyingProvisioner(t, dk)
+		prov.urlInstallerBuilder = mockUrlInstallerBuilder(t, createSuccessfulInstaller(t))
+		createPMCSourceFile(t, prov, dk)
+
+		agent := filepath.Base(prov.getTargetDir(*dk))
+		require.NoError(t, prov.inventory.Update(func(inventory *metadata.Inventory) error {
+			inventory.AddAgent(agent, 0, time.Now())
+
+			return nil
+		}))
+
+		_, err := prov.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(dk)})
+		require.NoError(t, err)
+
+		report := getVerificationReport(t, prov, dk)
+		assert.Equal(t, "false", report.Data[integrity.ReportVerifiedKey])
+		assert.Contains(t, report.Data[integrity.ReportMessageKey], "installed without verification")
+	})
+
+	t.Run("verification fails => failure is reported for the node", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createVerifyingProvisioner(t, dk)
+		prov.urlInstallerBuilder = mockUrlInstallerBuilder(t, createVerificationFailingInstaller(t))
+
+		_, err := prov.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(dk)})
+		require.ErrorIs(t, err, integrity.ErrVerificationFailed)
+
+		report := getVerificationReport(t, prov, dk)
+		assert.Equal(t, "false", report.Data[integrity.ReportVerifiedKey])
+		assert.Contains(t, report.Data[integrity.ReportMessageKey], "sha256 digest mismatch")
+	})
+
+	t.Run("verification turned off => report of the node is deleted", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createReportingProvisioner(t, dk)
+		report, err := integrity.BuildReport(dk, testNodeName, "old-version", nil)
+		require.NoError(t, err)
+		require.NoError(t, prov.kubeClient.Create(ctx, report))
+
+		dk.Spec.OneAgent.CloudNativeFullStack.CodeModulesVerification = nil
+
+		require.NoError(t, prov.reportVerification(ctx, dk, nil))
+
+		err = prov.kubeClient.Get(ctx, client.ObjectKeyFromObject(report), &corev1.ConfigMap{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("other install errors don't change the report", func(t *testing.T) {
+		dk := createDynaKubeWithVerification(t)
+		prov := createReportingProvisioner(t, dk)
+
+		require.NoError(t, prov.reportVerification(ctx, dk, errors.New("BOOM")))
+
+		var reports corev1.ConfigMapList
+		require.NoError(t, prov.kubeClient.List(ctx, &reports, client.MatchingLabels{labels.AppComponentLabel: integrity.ReportComponentLabel}))
+		assert.Empty(t, reports.Items)
+	})
+}
+
+func createDynaKubeWithVerification(t *testing.T) *dynakube.DynaKube {
+	t.Helper()
+
+	dk := createDynaKubeWithVersion(t)
+	dk.Spec.OneAgent = oneagent.Spec{
+		CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{
+			AppInjectionSpec: oneagent.AppInjectionSpec{
+				CodeModulesVerification: &oneagent.CodeModulesVerificationSpec{
+					ConfigMap:        testVerificationConfigMap,
+					RequireSignature: true,
+				},
+			},
+		},
+	}
+
+	return dk
+}
+
+func createVerificationConfigMap(t *testing.T, dk *dynakube.DynaKube, data map[string]string) *corev1.ConfigMap {
+	t.Helper()
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testVerificationConfigMap,
+			Namespace: dk.Namespace,
+		},
+		Data: data,
+	}
+}
+
+func createVerifyingProvisioner(t *testing.T, dk *dynakube.DynaKube) OneAgentProvisioner {
+	t.Helper()
+
+	prov := createProvisioner(t)
+	kubeClient := fake.NewClient(dk, createToken(t, dk), createPMCSecret(t, dk), createVerificationConfigMap(t, dk, map[string]string{
+		dk.OneAgent().GetCodeModulesVersion() + ".sha256": testDigest,
+	}))
+	prov.apiReader = kubeClient
+	prov.kubeClient = kubeClient
+	prov.nodeName = testNodeName
+	prov.dynatraceClientBuilder = mockSuccessfulDtClientBuilder(t)
+
+	return prov
+}
+
+func createReportingProvisioner(t *testing.T, dk *dynakube.DynaKube) OneAgentProvisioner {
+	t.Helper()
+
+	prov := createProvisioner(t)
+	kubeClient := fake.NewClient(dk)
+	prov.apiReader = kubeClient
+	prov.kubeClient = kubeClient
+	prov.nodeName = testNodeName
+
+	return prov
+}
+
+func createVerificationFailingInstaller(t *testing.T) *installermock.Installer {
+	t.Helper()
+
+	m := installermock.NewInstaller(t)
+	m.On("InstallAgent", mock.Anything, mock.Anything).Return(false, errors.WithMessage(integrity.ErrVerificationFailed, "sha256 digest mismatch"))
+
+	return m
+}
+
+func getVerificationReport(t *testing.T, prov OneAgentProvisioner, dk *dynakube.DynaKube) *corev1.ConfigMap {
+	t.Helper()
+
+	var report corev1.ConfigMap
+	require.NoError(t, prov.kubeClient.Get(context.Background(), client.ObjectKey{Name: integrity.ReportName(dk.Name, testNodeName), Namespace: dk.Namespace}, &report))
+
+	return &report
+}
@@ -7,16 +7,20 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/value"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/internal/authtoken"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/internal/customproperties"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/internal/statefulset/builder"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates/issuer"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/secret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/statefulset"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -105,12 +109,17 @@ func (r *Reconciler) calculateActiveGateConfigurationHash(ctx context.Context) (
 		return "", err
 	}
 
-	if len(customPropertyData) < 1 && len(authTokenData) < 1 {
+	issuedCertificateData, err := r.getIssuedCertificateValue(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(customPropertyData) < 1 && len(authTokenData) < 1 && len(issuedCertificateData) < 1 {
 		return "", nil
 	}
 
 	hash := fnv.New32()
-	if _, err := hash.Write([]byte(customPropertyData + authTokenData)); err != nil {
+	if _, err := hash.Write([]byte(customPropertyData + authTokenData + issuedCertificateData)); err != nil {
 		return "", errors.WithStack(err)
 	}
 
@@ -143,6 +152,29 @@ func (r *Reconciler) getAuthTokenValue(ctx context.Context) (string, error) {
 	return authTokenData, nil
 }
 
+// getIssuedCertificateValue returns the TLS certificate if it was issued by the configured certificate source,
+// as those are rotated and the ActiveGate only reads it on startup.
+func (r *Reconciler) getIssuedCertificateValue(ctx context.Context) (string, error) {
+	if !r.dk.ActiveGate().IsAutomaticTlsSecretEnabled() || r.dk.ActiveGate().TlsSecretName != "" {
+		return "", nil
+	}
+
+	var tlsSecret corev1.Secret
+
+	err := r.apiReader.Get(ctx, types.NamespacedName{Namespace: r.dk.Namespace, Name: r.dk.ActiveGate().GetTLSSecretName()}, &tlsSecret)
+	if k8serrors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if len(tlsSecret.Data[issuer.CACrtDataName]) == 0 {
+		return "", nil
+	}
+
+	return string(tlsSecret.Data[consts.TLSCrtDataName]), nil
+}
+
 func (r *Reconciler) getDataFromCustomProperty(ctx context.Context, customProperties *value.Source) (string, error) {
 	if customProperties.ValueFrom != "" {
 		return secret.GetDataFromSecretName(ctx, r.apiReader, types.NamespacedName{Namespace: r.dk.Namespace, Name: customProperties.ValueFrom}, customproperties.DataKey, log)
@@ -4,11 +4,14 @@ import (
 	"context"
 	"crypto/x509"
 	"net"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates/issuer"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	k8slabels "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
 	k8ssecret "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/secret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
@@ -25,6 +28,9 @@ const (
 	activeGateSelfSignedTLSCommonNameSuffix = "activegate"
 
 	tlsCrtDataName = "server.crt"
+
+	issuedCertValidity    = 90 * 24 * time.Hour
+	issuedCertRenewBefore = 30 * 24 * time.Hour
 )
 
 type Reconciler struct {
@@ -47,6 +53,17 @@ func NewReconciler(client client.Client, apiReader client.Reader, dk *dynakube.D
 
 func (r *Reconciler) Reconcile(ctx context.Context) error {
 	if r.dk.ActiveGate().IsEnabled() && r.dk.ActiveGate().IsAutomaticTlsSecretEnabled() && r.dk.ActiveGate().TlsSecretName == "" {
+		certIssuer, err := issuer.New(r.client, r.apiReader, env.DefaultNamespace())
+		if err != nil {
+			conditions.SetSecretGenFailed(r.dk.Conditions(), conditionType, err)
+
+			return err
+		}
+
+		if certIssuer != nil {
+			return r.reconcileIssuedTLSSecret(ctx, certIssuer)
+		}
+
 		return r.reconcileSelfSignedTLSSecret(ctx)
 	}
 
@@ -61,7 +78,7 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 func (r *Reconciler) reconcileSelfSignedTLSSecret(ctx context.Context) error {
 	query := k8ssecret.Query(r.client, r.client, log)
 
-	_, err := query.Get(ctx, types.NamespacedName{
+	secret, err := query.Get(ctx, types.NamespacedName{
 		Name:      r.dk.ActiveGate().GetTLSSecretName(),
 		Namespace: r.dk.Namespace,
 	})
@@ -76,6 +93,76 @@ func (r *Reconciler) reconcileSelfSignedTLSSecret(ctx context.Context) error {
 		return err
 	}
 
+	if len(secret.Data[issuer.CACrtDataName]) > 0 {
+		log.Info("replacing the certificate of the previous certificate source with a self-signed one", "secret", secret.Name)
+
+		err = query.Delete(ctx, secret)
+		if err != nil {
+			conditions.SetKubeApiError(r.dk.Conditions(), conditionType, err)
+
+			return err
+		}
+
+		return r.createSelfSignedTLSSecret(ctx)
+	}
+
+	return nil
+}
+
+// reconcileIssuedTLSSecret keeps the TLS secret up to date with the certificate of the configured certificate source.
+// The trust bundle of the CA is provided as server.crt, as that's what the other components trust the ActiveGate with.
+func (r *Reconciler) reconcileIssuedTLSSecret(ctx context.Context, certIssuer issuer.Issuer) error {
+	ipAddresses, err := getCertificateAltIPs(r.dk.Status.ActiveGate.ServiceIPs)
+	if err != nil {
+		conditions.SetSecretGenFailed(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	secretData, err := issuer.IssueSecretData(ctx, certIssuer, r.apiReader, issuer.Request{
+		Owner:       r.dk,
+		Namespace:   r.dk.Namespace,
+		SecretName:  r.dk.ActiveGate().GetTLSSecretName(),
+		CommonName:  certificates.CommonName(r.dk.Name, r.dk.Namespace, activeGateSelfSignedTLSCommonNameSuffix),
+		DNSNames:    certificates.AltNames(r.dk.Name, r.dk.Namespace, activeGateSelfSignedTLSCommonNameSuffix),
+		IPAddresses: ipAddresses,
+		Duration:    issuedCertValidity,
+		RenewBefore: issuedCertRenewBefore,
+	})
+	if errors.Is(err, issuer.ErrNotIssued) {
+		log.Info("waiting for the TLS certificate to be issued", "secret", r.dk.ActiveGate().GetTLSSecretName())
+
+		return nil
+	} else if err != nil {
+		conditions.SetSecretGenFailed(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	secretData[tlsCrtDataName] = issuer.TrustBundle(secretData)
+
+	coreLabels := k8slabels.NewCoreLabels(r.dk.Name, k8slabels.ActiveGateComponentLabel)
+
+	secret, err := k8ssecret.Build(r.dk, r.dk.ActiveGate().GetTLSSecretName(), secretData, k8ssecret.SetLabels(coreLabels.BuildLabels()))
+	if err != nil {
+		conditions.SetSecretGenFailed(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	secret.Type = corev1.SecretTypeOpaque
+
+	query := k8ssecret.Query(r.client, r.apiReader, log)
+
+	_, err = query.CreateOrUpdate(ctx, secret)
+	if err != nil {
+		conditions.SetKubeApiError(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	conditions.SetSecretCreatedOrUpdated(r.dk.Conditions(), conditionType, secret.Name)
+
 	return nil
 }
 
@@ -9,7 +9,10 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates/issuer"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -129,4 +132,61 @@ func TestReconciler_Reconcile(t *testing.T) {
 		assert.Equal(t, conditions.SecretCreatedReason, condition.Reason)
 		assert.Equal(t, fmt.Sprintf("%s created", agTLSSecret.Name), condition.Message)
 	})
+	t.Run(`secret of the configured certificate source`, func(t *testing.T) {
+		installconfig.SetCertificateSourceOverride(t, installconfig.CertificateSource{
+			Type:   installconfig.CertManagerCertificateSource,
+			Issuer: &installconfig.IssuerRef{Name: "corporate"},
+		})
+
+		dk := &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      testDynakubeName,
+			},
+			Spec: dynakube.DynaKubeSpec{
+				ActiveGate: activegate.Spec{
+					Capabilities: []activegate.CapabilityDisplayName{
+						activegate.RoutingCapability.DisplayName,
+					},
+				},
+			},
+		}
+		fakeClient := fake.NewClient()
+		r := NewReconciler(fakeClient, fakeClient, dk)
+
+		err := r.Reconcile(context.Background())
+		require.NoError(t, err)
+
+		agTLSSecret := corev1.Secret{}
+		err = r.client.Get(context.Background(), client.ObjectKey{Name: r.dk.ActiveGate().GetTLSSecretName(), Namespace: r.dk.Namespace}, &agTLSSecret)
+		require.True(t, k8serrors.IsNotFound(err))
+
+		err = fakeClient.Create(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: r.dk.ActiveGate().GetTLSSecretName() + issuer.IssuedSecretSuffix, Namespace: testNamespace},
+			Data: map[string][]byte{
+				consts.TLSCrtDataName: []byte("cert"),
+				consts.TLSKeyDataName: []byte("key"),
+				issuer.CACrtDataName:  []byte("ca"),
+			},
+		})
+		require.NoError(t, err)
+
+		err = r.Reconcile(context.Background())
+		require.NoError(t, err)
+
+		err = r.client.Get(context.Background(), client.ObjectKey{Name: r.dk.ActiveGate().GetTLSSecretName(), Namespace: r.dk.Namespace}, &agTLSSecret)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("cert"), agTLSSecret.Data[consts.TLSCrtDataName])
+		assert.Equal(t, []byte("ca"), agTLSSecret.Data[tlsCrtDataName])
+
+		installconfig.SetCertificateSourceOverride(t, installconfig.CertificateSource{})
+
+		err = r.Reconcile(context.Background())
+		require.NoError(t, err)
+
+		err = r.client.Get(context.Background(), client.ObjectKey{Name: r.dk.ActiveGate().GetTLSSecretName(), Namespace: r.dk.Namespace}, &agTLSSecret)
+		require.NoError(t, err)
+		assert.NotContains(t, agTLSSecret.Data, issuer.CACrtDataName)
+		assert.NotEqual(t, []byte("cert"), agTLSSecret.Data[consts.TLSCrtDataName])
+	})
 }
@@ -0,0 +1,293 @@
+package dynakube
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	componentConditionTypeSuffix = "Reconciled"
+
+	ComponentReconciledReason       = "Reconciled"
+	ComponentReconcileFailedReason  = "ReconcileFailed"
+	ComponentPostponedReason        = "Postponed"
+	ComponentDependencyFailedReason = "DependencyFailed"
+)
+
+var errDependencyNotReconciled = errors.New("dependency was not reconciled")
+
+// component is a single node of the DynaKube component graph.
+// A component is only reconciled after all of its dependencies were reconciled successfully,
+// components without a dependency between each other are reconciled concurrently.
+type component struct {
+	reconcile func(ctx context.Context, dk *dynakube.DynaKube) error
+	// nextUpdate returns when the component needs to be reconciled again, based on the state after the last reconcile.
+	// The zero time means that the component has no preference, optional.
+	nextUpdate func(dk *dynakube.DynaKube) time.Time
+	name       string
+	dependsOn  []string
+}
+
+type componentResult struct {
+	err error
+	// ran is false if the component was skipped, either because it was not due or because a dependency failed
+	ran bool
+}
+
+func componentConditionType(name string) string {
+	return name + componentConditionTypeSuffix
+}
+
+// validateComponents makes sure that every dependency is known and that there is no cycle in the graph,
+// otherwise runComponents would wait forever.
+func validateComponents(components []component) error {
+	byName := make(map[string]component, len(components))
+	for _, c := range components {
+		if _, ok := byName[c.name]; ok {
+			return errors.Errorf("component %s is defined more than once", c.name)
+		}
+
+		byName[c.name] = c
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(components))
+
+	var visit func(name string, path []string) error
+
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return errors.Errorf("dependency cycle between components: %s", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+
+		c, ok := byName[name]
+		if !ok {
+			return errors.Errorf("component %s depends on unknown component %s", path[len(path)-1], name)
+		}
+
+		state[name] = visiting
+
+		for _, dependency := range c.dependsOn {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, c := range components {
+		if err := visit(c.name, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runComponents reconciles the given components according to their dependencies and returns the result of each one.
+// Every component works on its own copy of the DynaKube, the changes it made to the status are merged back into dk once it finished.
+// Components that were skipped, because one of their dependencies failed, get an error wrapping errDependencyNotReconciled.
+// Components that are not due are skipped as well, unless one of their dependencies was reconciled in this pass.
+func runComponents(ctx context.Context, dk *dynakube.DynaKube, components []component, isDue func(name string) bool) (map[string]componentResult, error) {
+	if err := validateComponents(components); err != nil {
+		return nil, err
+	}
+
+	done := make(map[string]chan struct{}, len(components))
+	for _, c := range components {
+		done[c.name] = make(chan struct{})
+	}
+
+	var (
+		mutex   sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]componentResult, len(components))
+	)
+
+	for _, c := range components {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer close(done[c.name])
+
+			for _, dependency := range c.dependsOn {
+				<-done[dependency]
+			}
+
+			mutex.Lock()
+
+			var failedDependencies []string
+
+			due := isDue(c.name)
+
+			for _, dependency := range c.dependsOn {
+				if results[dependency].err != nil {
+					failedDependencies = append(failedDependencies, dependency)
+				}
+
+				due = due || results[dependency].ran
+			}
+
+			if len(failedDependencies) > 0 {
+				results[c.name] = componentResult{err: errors.WithMessage(errDependencyNotReconciled, strings.Join(failedDependencies, ", "))}
+				mutex.Unlock()
+
+				log.Info("skipping component, dependency was not reconciled", "component", c.name, "dependencies", failedDependencies)
+
+				return
+			}
+
+			if !due {
+				results[c.name] = componentResult{}
+				mutex.Unlock()
+
+				log.Info("skipping component, not yet due", "component", c.name)
+
+				return
+			}
+
+			base := dk.DeepCopy()
+			mutex.Unlock()
+
+			log.Info("start reconciling component", "component", c.name)
+
+			working := base.DeepCopy()
+			err := c.reconcile(ctx, working)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			mergeDynaKubeChanges(dk, base, working)
+
+			results[c.name] = componentResult{err: err, ran: true}
+		}()
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+// mergeDynaKubeChanges applies every status field that was changed in changed (compared to base) to dst.
+// Conditions are merged by type, so components can update their own conditions independent of each other.
+func mergeDynaKubeChanges(dst, base, changed *dynakube.DynaKube) {
+	if base.ResourceVersion != changed.ResourceVersion {
+		dst.ResourceVersion = changed.ResourceVersion
+	}
+
+	mergeChangedFields(reflect.ValueOf(&dst.Status).Elem(), reflect.ValueOf(&base.Status).Elem(), reflect.ValueOf(&changed.Status).Elem())
+}
+
+var conditionsType = reflect.TypeOf([]metav1.Condition{})
+
+func mergeChangedFields(dst, base, changed reflect.Value) {
+	if dst.Type() == conditionsType {
+		mergeChangedConditions(dst.Addr().Interface().(*[]metav1.Condition), base.Interface().([]metav1.Condition), changed.Interface().([]metav1.Condition))
+
+		return
+	}
+
+	if dst.Kind() == reflect.Struct && hasOnlyExportedFields(dst.Type()) {
+		for i := range dst.NumField() {
+			mergeChangedFields(dst.Field(i), base.Field(i), changed.Field(i))
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(base.Interface(), changed.Interface()) {
+		dst.Set(changed)
+	}
+}
+
+func hasOnlyExportedFields(t reflect.Type) bool {
+	for i := range t.NumField() {
+		if !t.Field(i).IsExported() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mergeChangedConditions(dst *[]metav1.Condition, base, changed []metav1.Condition) {
+	for _, condition := range changed {
+		old := meta.FindStatusCondition(base, condition.Type)
+		if old != nil && *old == condition {
+			continue
+		}
+
+		if existing := meta.FindStatusCondition(*dst, condition.Type); existing != nil {
+			*existing = condition
+		} else {
+			*dst = append(*dst, condition)
+		}
+	}
+
+	for _, condition := range base {
+		if meta.FindStatusCondition(changed, condition.Type) == nil {
+			meta.RemoveStatusCondition(dst, condition.Type)
+		}
+	}
+}
+
+func setComponentReconciledCondition(dk *dynakube.DynaKube, name string) {
+	condition := metav1.Condition{
+		Type:    componentConditionType(name),
+		Status:  metav1.ConditionTrue,
+		Reason:  ComponentReconciledReason,
+		Message: name + " reconciled",
+	}
+	_ = meta.SetStatusCondition(dk.Conditions(), condition)
+}
+
+func setComponentFailedCondition(dk *dynakube.DynaKube, name string, err error) {
+	condition := metav1.Condition{
+		Type:    componentConditionType(name),
+		Status:  metav1.ConditionFalse,
+		Reason:  ComponentReconcileFailedReason,
+		Message: err.Error(),
+	}
+	_ = meta.SetStatusCondition(dk.Conditions(), condition)
+}
+
+func setComponentPostponedCondition(dk *dynakube.DynaKube, name string, err error) {
+	condition := metav1.Condition{
+		Type:    componentConditionType(name),
+		Status:  metav1.ConditionFalse,
+		Reason:  ComponentPostponedReason,
+		Message: err.Error(),
+	}
+	_ = meta.SetStatusCondition(dk.Conditions(), condition)
+}
+
+func setComponentDependencyFailedCondition(dk *dynakube.DynaKube, name string, err error) {
+	condition := metav1.Condition{
+		Type:    componentConditionType(name),
+		Status:  metav1.ConditionFalse,
+		Reason:  ComponentDependencyFailedReason,
+		Message: fmt.Sprintf("%s not reconciled: %s", name, err.Error()),
+	}
+	_ = meta.SetStatusCondition(dk.Conditions(), condition)
+}
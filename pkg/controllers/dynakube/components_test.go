@@ -0,0 +1,247 @@
+package dynakube
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func noopComponent(name string, dependsOn ...string) component {
+	return component{
+		name:      name,
+		dependsOn: dependsOn,
+		reconcile: func(_ context.Context, _ *dynakube.DynaKube) error { return nil },
+	}
+}
+
+func alwaysDue(string) bool {
+	return true
+}
+
+func TestValidateComponents(t *testing.T) {
+	t.Run("valid graph", func(t *testing.T) {
+		err := validateComponents([]component{
+			noopComponent("a"),
+			noopComponent("b", "a"),
+			noopComponent("c", "a", "b"),
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown dependency", func(t *testing.T) {
+		err := validateComponents([]component{
+			noopComponent("a", "missing"),
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown component missing")
+	})
+
+	t.Run("duplicate component", func(t *testing.T) {
+		err := validateComponents([]component{
+			noopComponent("a"),
+			noopComponent("a"),
+		})
+
+		require.Error(t, err)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		err := validateComponents([]component{
+			noopComponent("a", "c"),
+			noopComponent("b", "a"),
+			noopComponent("c", "b"),
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cycle")
+	})
+}
+
+func TestRunComponents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("independent components run concurrently", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		components := []component{
+			{
+				name: "slow",
+				reconcile: func(_ context.Context, _ *dynakube.DynaKube) error {
+					close(started)
+					<-release
+
+					return nil
+				},
+			},
+			{
+				name: "fast",
+				reconcile: func(_ context.Context, _ *dynakube.DynaKube) error {
+					<-started
+					close(release)
+
+					return nil
+				},
+			},
+		}
+
+		results, err := runComponents(ctx, dk, components, alwaysDue)
+
+		require.NoError(t, err)
+		require.NoError(t, results["slow"].err)
+		require.NoError(t, results["fast"].err)
+	})
+
+	t.Run("dependents see the status of their dependencies", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+
+		components := []component{
+			{
+				name: "connection-info",
+				reconcile: func(_ context.Context, dk *dynakube.DynaKube) error {
+					dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID = "tenant"
+
+					return nil
+				},
+			},
+			{
+				name:      "injection",
+				dependsOn: []string{"connection-info"},
+				reconcile: func(_ context.Context, dk *dynakube.DynaKube) error {
+					if dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID != "tenant" {
+						return errors.New("connection info missing")
+					}
+
+					dk.Status.CodeModules.Version = "1.2.3"
+
+					return nil
+				},
+			},
+		}
+
+		results, err := runComponents(ctx, dk, components, alwaysDue)
+
+		require.NoError(t, err)
+		require.NoError(t, results["injection"].err)
+		assert.Equal(t, "tenant", dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID)
+		assert.Equal(t, "1.2.3", dk.Status.CodeModules.Version)
+	})
+
+	t.Run("failed dependency skips only its dependents", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+
+		var called atomic.Int32
+
+		components := []component{
+			{
+				name: "failing",
+				reconcile: func(_ context.Context, _ *dynakube.DynaKube) error {
+					return errors.New("BOOM")
+				},
+			},
+			{
+				name:      "dependent",
+				dependsOn: []string{"failing"},
+				reconcile: func(_ context.Context, _ *dynakube.DynaKube) error {
+					called.Add(1)
+
+					return nil
+				},
+			},
+			{
+				name: "independent",
+				reconcile: func(_ context.Context, _ *dynakube.DynaKube) error {
+					called.Add(1)
+
+					return nil
+				},
+			},
+		}
+
+		results, err := runComponents(ctx, dk, components, alwaysDue)
+
+		require.NoError(t, err)
+		require.Error(t, results["failing"].err)
+		require.ErrorIs(t, results["dependent"].err, errDependencyNotReconciled)
+		require.NoError(t, results["independent"].err)
+		assert.Equal(t, int32(1), called.Load())
+	})
+
+	t.Run("components that are not due are skipped, unless a dependency ran", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+
+		components := []component{
+			noopComponent("due"),
+			noopComponent("not-due"),
+			noopComponent("not-due-dependent", "due"),
+		}
+
+		results, err := runComponents(ctx, dk, components, func(name string) bool { return name == "due" })
+
+		require.NoError(t, err)
+		assert.True(t, results["due"].ran)
+		assert.False(t, results["not-due"].ran)
+		require.NoError(t, results["not-due"].err)
+		assert.True(t, results["not-due-dependent"].ran)
+	})
+
+	t.Run("invalid graph => error", func(t *testing.T) {
+		_, err := runComponents(ctx, &dynakube.DynaKube{}, []component{noopComponent("a", "a")}, alwaysDue)
+
+		require.Error(t, err)
+	})
+}
+
+func TestMergeDynaKubeChanges(t *testing.T) {
+	t.Run("only changed fields are merged", func(t *testing.T) {
+		base := &dynakube.DynaKube{}
+		base.Status.KubernetesClusterMEID = "meid"
+
+		dst := base.DeepCopy()
+		dst.Status.ActiveGate.Version = "ag-version"
+
+		changed := base.DeepCopy()
+		changed.Status.OneAgent.Version = "oa-version"
+		changed.ResourceVersion = "2"
+
+		mergeDynaKubeChanges(dst, base, changed)
+
+		assert.Equal(t, "ag-version", dst.Status.ActiveGate.Version)
+		assert.Equal(t, "oa-version", dst.Status.OneAgent.Version)
+		assert.Equal(t, "meid", dst.Status.KubernetesClusterMEID)
+		assert.Equal(t, "2", dst.ResourceVersion)
+	})
+
+	t.Run("conditions are merged by type", func(t *testing.T) {
+		base := &dynakube.DynaKube{}
+		meta.SetStatusCondition(base.Conditions(), metav1.Condition{Type: "kept", Status: metav1.ConditionTrue, Reason: "r"})
+		meta.SetStatusCondition(base.Conditions(), metav1.Condition{Type: "removed", Status: metav1.ConditionTrue, Reason: "r"})
+		meta.SetStatusCondition(base.Conditions(), metav1.Condition{Type: "updated", Status: metav1.ConditionTrue, Reason: "r"})
+
+		dst := base.DeepCopy()
+		meta.SetStatusCondition(dst.Conditions(), metav1.Condition{Type: "other", Status: metav1.ConditionTrue, Reason: "r"})
+
+		changed := base.DeepCopy()
+		meta.RemoveStatusCondition(changed.Conditions(), "removed")
+		meta.SetStatusCondition(changed.Conditions(), metav1.Condition{Type: "updated", Status: metav1.ConditionFalse, Reason: "new"})
+		meta.SetStatusCondition(changed.Conditions(), metav1.Condition{Type: "added", Status: metav1.ConditionTrue, Reason: "r"})
+
+		mergeDynaKubeChanges(dst, base, changed)
+
+		assert.NotNil(t, meta.FindStatusCondition(dst.Status.Conditions, "kept"))
+		assert.NotNil(t, meta.FindStatusCondition(dst.Status.Conditions, "other"))
+		assert.NotNil(t, meta.FindStatusCondition(dst.Status.Conditions, "added"))
+		assert.Nil(t, meta.FindStatusCondition(dst.Status.Conditions, "removed"))
+		assert.Equal(t, "new", meta.FindStatusCondition(dst.Status.Conditions, "updated").Reason)
+	})
+}
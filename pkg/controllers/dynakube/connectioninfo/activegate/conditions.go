@@ -1,3 +1,15 @@
 package activegate
 
+import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+)
+
 const activeGateConnectionInfoConditionType string = "ActiveGateConnectionInfo"
+
+// NextUpdate returns when the ActiveGate connection info has to be requested from the Dynatrace API again.
+func NextUpdate(dk *dynakube.DynaKube) time.Time {
+	return conditions.NextUpdate(dk, activeGateConnectionInfoConditionType)
+}
@@ -0,0 +1,121 @@
+package oaconnectioninfo
+
+import (
+	"context"
+	"strings"
+
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/bundle"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
+	k8ssecret "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/secret"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileBundleConnectionInfo sets the connection info without asking the tenant, as a CodeModules bundle is meant for clusters without access to it.
+// The user-supplied secret has precedence over the connection info the csi-provisioner published from the bundle.
+func (r *reconciler) reconcileBundleConnectionInfo(ctx context.Context) error {
+	secretName := r.dk.OneAgent().GetCodeModulesBundle().ConnectionInfoSecret
+	if secretName != "" {
+		return r.reconcileConnectionInfoFromSecret(ctx, secretName)
+	}
+
+	err := bundle.ApplyConnectionInfo(ctx, r.apiReader, r.dk)
+	if err != nil {
+		conditions.SetKubeApiError(r.dk.Conditions(), oaConnectionInfoConditionType, err)
+
+		return err
+	}
+
+	if r.dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID == "" {
+		conditions.SetStatusOutdated(r.dk.Conditions(), oaConnectionInfoConditionType, "waiting for the csi-provisioner to publish the connection info of the CodeModules bundle")
+
+		return nil
+	}
+
+	r.setCommunicationHostsFromEndpoints()
+	conditions.SetStatusUpdated(r.dk.Conditions(), oaConnectionInfoConditionType, "OneAgent connection info is taken from the CodeModules bundle")
+
+	return nil
+}
+
+func (r *reconciler) reconcileConnectionInfoFromSecret(ctx context.Context, secretName string) error {
+	query := k8ssecret.Query(r.client, r.apiReader, log)
+
+	secret, err := query.Get(ctx, types.NamespacedName{Name: secretName, Namespace: r.dk.Namespace})
+	if err != nil {
+		conditions.SetKubeApiError(r.dk.Conditions(), oaConnectionInfoConditionType, err)
+
+		return errors.WithMessagef(err, "failed to get OneAgent connection info from secret %s", secretName)
+	}
+
+	tenantUUID := string(secret.Data[connectioninfo.TenantUUIDKey])
+	endpoints := string(secret.Data[connectioninfo.CommunicationEndpointsKey])
+
+	if tenantUUID == "" || endpoints == "" {
+		err := errors.Errorf("secret %s is missing the %s or %s of the OneAgent connection info", secretName, connectioninfo.TenantUUIDKey, connectioninfo.CommunicationEndpointsKey)
+		conditions.SetSecretGenFailed(r.dk.Conditions(), oaConnectionInfoConditionType, err)
+
+		return err
+	}
+
+	r.dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID = tenantUUID
+	r.dk.Status.OneAgent.ConnectionInfoStatus.Endpoints = endpoints
+	r.setCommunicationHostsFromEndpoints()
+
+	tenantToken, ok := secret.Data[connectioninfo.TenantTokenKey]
+	if !ok {
+		r.dk.Status.OneAgent.ConnectionInfoStatus.TenantTokenHash = ""
+
+		return r.deleteTenantTokenSecret(ctx)
+	}
+
+	err = r.createTenantTokenSecret(ctx, r.dk.OneAgent().GetTenantSecret(), dtclient.ConnectionInfo{TenantToken: string(tenantToken)})
+	if err != nil {
+		return err
+	}
+
+	r.dk.Status.OneAgent.ConnectionInfoStatus.TenantTokenHash, err = hasher.GenerateHash(string(tenantToken))
+	if err != nil {
+		return errors.Wrap(err, "failed to generate TenantTokenHash")
+	}
+
+	return nil
+}
+
+func (r *reconciler) deleteTenantTokenSecret(ctx context.Context) error {
+	query := k8ssecret.Query(r.client, r.apiReader, log)
+
+	err := query.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: r.dk.OneAgent().GetTenantSecret(), Namespace: r.dk.Namespace}})
+	if err != nil {
+		conditions.SetKubeApiError(r.dk.Conditions(), oaConnectionInfoConditionType, err)
+
+		return err
+	}
+
+	conditions.SetStatusUpdated(r.dk.Conditions(), oaConnectionInfoConditionType, "OneAgent connection info is taken from the user-supplied secret")
+
+	return nil
+}
+
+// setCommunicationHostsFromEndpoints derives the communication hosts from the endpoints, as there is no tenant to list them.
+func (r *reconciler) setCommunicationHostsFromEndpoints() {
+	communicationHosts := make([]dtclient.CommunicationHost, 0)
+
+	for _, endpoint := range strings.Split(r.dk.Status.OneAgent.ConnectionInfoStatus.Endpoints, ",") {
+		communicationHost, err := dtclient.ParseEndpoint(strings.TrimSpace(endpoint))
+		if err != nil {
+			log.Info("skipping invalid OneAgent endpoint", "endpoint", endpoint)
+
+			continue
+		}
+
+		communicationHosts = append(communicationHosts, communicationHost)
+	}
+
+	copyCommunicationHosts(&r.dk.Status.OneAgent.ConnectionInfoStatus, communicationHosts)
+}
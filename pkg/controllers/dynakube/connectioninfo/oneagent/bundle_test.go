@@ -0,0 +1,138 @@
+package oaconnectioninfo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/bundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testBundleEndpoints      = "https://abc123.live.dynatrace.com:443/communication,https://10.0.0.1:9999/communication"
+	testConnectionInfoSecret = "bundle-connection-info"
+)
+
+func TestReconcileBundle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("connection info from the published bundle, without the tenant", func(t *testing.T) {
+		dk := getTestBundleDynakube("")
+		published := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: bundle.ConnectionInfoConfigMapName(dk.Name), Namespace: testNamespace},
+			Data: map[string]string{
+				connectioninfo.TenantUUIDKey:             testTenantUUID,
+				connectioninfo.CommunicationEndpointsKey: testBundleEndpoints,
+			},
+		}
+		fakeClient := fake.NewClient(dk, published)
+
+		err := NewReconciler(fakeClient, fakeClient, nil, dk).Reconcile(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, testTenantUUID, dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID)
+		assert.Equal(t, testBundleEndpoints, dk.Status.OneAgent.ConnectionInfoStatus.Endpoints)
+		assertBundleCommunicationHosts(t, dk)
+		assert.True(t, meta.IsStatusConditionTrue(*dk.Conditions(), oaConnectionInfoConditionType))
+
+		var tenantSecret corev1.Secret
+		err = fakeClient.Get(ctx, client.ObjectKey{Name: dk.OneAgent().GetTenantSecret(), Namespace: testNamespace}, &tenantSecret)
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("bundle not yet published => no error, outdated condition", func(t *testing.T) {
+		dk := getTestBundleDynakube("")
+		fakeClient := fake.NewClient(dk)
+
+		err := NewReconciler(fakeClient, fakeClient, nil, dk).Reconcile(ctx)
+		require.NoError(t, err)
+
+		assert.Empty(t, dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID)
+		assert.True(t, meta.IsStatusConditionFalse(*dk.Conditions(), oaConnectionInfoConditionType))
+	})
+
+	t.Run("connection info from the user-supplied secret", func(t *testing.T) {
+		dk := getTestBundleDynakube(testConnectionInfoSecret)
+		userSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: testConnectionInfoSecret, Namespace: testNamespace},
+			Data: map[string][]byte{
+				connectioninfo.TenantUUIDKey:             []byte(testTenantUUID),
+				connectioninfo.CommunicationEndpointsKey: []byte(testBundleEndpoints),
+				connectioninfo.TenantTokenKey:            []byte(testTenantToken),
+			},
+		}
+		fakeClient := fake.NewClient(dk, userSecret)
+
+		err := NewReconciler(fakeClient, fakeClient, nil, dk).Reconcile(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, testTenantUUID, dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID)
+		assert.Equal(t, testBundleEndpoints, dk.Status.OneAgent.ConnectionInfoStatus.Endpoints)
+		assert.NotEmpty(t, dk.Status.OneAgent.ConnectionInfoStatus.TenantTokenHash)
+		assertBundleCommunicationHosts(t, dk)
+
+		var tenantSecret corev1.Secret
+		err = fakeClient.Get(ctx, client.ObjectKey{Name: dk.OneAgent().GetTenantSecret(), Namespace: testNamespace}, &tenantSecret)
+		require.NoError(t, err)
+		assert.Equal(t, testTenantToken, string(tenantSecret.Data[connectioninfo.TenantTokenKey]))
+	})
+
+	t.Run("incomplete user-supplied secret => error", func(t *testing.T) {
+		dk := getTestBundleDynakube(testConnectionInfoSecret)
+		userSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: testConnectionInfoSecret, Namespace: testNamespace},
+			Data: map[string][]byte{
+				connectioninfo.TenantUUIDKey: []byte(testTenantUUID),
+			},
+		}
+		fakeClient := fake.NewClient(dk, userSecret)
+
+		err := NewReconciler(fakeClient, fakeClient, nil, dk).Reconcile(ctx)
+		require.Error(t, err)
+		assert.True(t, meta.IsStatusConditionFalse(*dk.Conditions(), oaConnectionInfoConditionType))
+	})
+
+	t.Run("missing user-supplied secret => error", func(t *testing.T) {
+		dk := getTestBundleDynakube(testConnectionInfoSecret)
+		fakeClient := fake.NewClient(dk)
+
+		err := NewReconciler(fakeClient, fakeClient, nil, dk).Reconcile(ctx)
+		require.Error(t, err)
+	})
+}
+
+func getTestBundleDynakube(connectionInfoSecret string) *dynakube.DynaKube {
+	dk := getTestDynakube()
+	dk.Spec.OneAgent = oneagent.Spec{
+		ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{
+			AppInjectionSpec: oneagent.AppInjectionSpec{
+				CodeModulesBundle: &oneagent.CodeModulesBundleSpec{
+					Path:                 "/bundles/codemodules.tar.gz",
+					TrustedKeys:          "bundle-keys",
+					ConnectionInfoSecret: connectionInfoSecret,
+				},
+			},
+		},
+	}
+
+	return dk
+}
+
+func assertBundleCommunicationHosts(t *testing.T, dk *dynakube.DynaKube) {
+	t.Helper()
+
+	assert.Equal(t, []oneagent.CommunicationHostStatus{
+		{Protocol: "https", Host: "abc123.live.dynatrace.com", Port: 443},
+		{Protocol: "https", Host: "10.0.0.1", Port: 9999},
+	}, dk.Status.OneAgent.ConnectionInfoStatus.CommunicationHosts)
+}
@@ -1,6 +1,10 @@
 package oaconnectioninfo
 
 import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -20,3 +24,8 @@ func setEmptyCommunicationHostsCondition(conditions *[]metav1.Condition) {
 	}
 	_ = meta.SetStatusCondition(conditions, condition)
 }
+
+// NextUpdate returns when the OneAgent connection info has to be requested from the Dynatrace API again.
+func NextUpdate(dk *dynakube.DynaKube) time.Time {
+	return conditions.NextUpdate(dk, oaConnectionInfoConditionType)
+}
@@ -65,7 +65,13 @@ func (r *reconciler) Reconcile(ctx context.Context) error {
 
 	oldStatus := r.dk.Status.DeepCopy()
 
-	err := r.reconcileConnectionInfo(ctx)
+	var err error
+	if r.dk.OneAgent().GetCodeModulesBundle() != nil {
+		err = r.reconcileBundleConnectionInfo(ctx)
+	} else {
+		err = r.reconcileConnectionInfo(ctx)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -121,6 +127,10 @@ func (r *reconciler) reconcileConnectionInfo(ctx context.Context) error {
 		log.Info("no OneAgent communication hosts received, tenant API requests not yet throttled")
 		setEmptyCommunicationHostsCondition(r.dk.Conditions())
 
+		if r.dk.Spec.NetworkZone != "" {
+			log.Info("A network zone has been configured for DynaKube, check that there a working ActiveGate ready for that network zone", "network zone", r.dk.Spec.NetworkZone, "dynakube", r.dk.Name)
+		}
+
 		return NoOneAgentCommunicationHostsError
 	}
 
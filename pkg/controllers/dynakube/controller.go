@@ -11,32 +11,46 @@ import (
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/apimonitoring"
+	agconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/activegate"
 	oaconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dtpullsecret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceapi"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceclient"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/egress"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/extension"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/injection"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/istio"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/kspm"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring"
 	logmondaemonset "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring/daemonset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring/logmonsettings"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/metadata/rules"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/monitoredentities"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/processmoduleconfigsecret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/proxy"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/releaselock"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/version"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubesystem"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -58,7 +72,10 @@ func Add(mgr manager.Manager, _ string) error {
 }
 
 func NewController(mgr manager.Manager, clusterID string) *Controller {
-	return NewDynaKubeController(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetConfig(), clusterID)
+	controller := NewDynaKubeController(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetConfig(), clusterID)
+	controller.eventRecorder = mgr.GetEventRecorderFor("dynatrace-operator")
+
+	return controller
 }
 
 func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, config *rest.Config, clusterID string) *Controller {
@@ -70,8 +87,11 @@ func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, co
 		operatorNamespace:      os.Getenv(env.PodNamespace),
 		clusterID:              clusterID,
 		dynatraceClientBuilder: dynatraceclient.NewBuilder(apiReader),
+		schedule:               newComponentSchedule(timeprovider.New()),
 		istioClientBuilder:     istio.NewClient,
 
+		oneAgentConnectionInfoReconcilerBuilder: oaconnectioninfo.NewReconciler,
+
 		deploymentMetadataReconcilerBuilder: deploymentmetadata.NewReconciler,
 		activeGateReconcilerBuilder:         activegate.NewReconciler,
 		oneAgentReconcilerBuilder:           oneagent.NewReconciler,
@@ -83,17 +103,24 @@ func NewDynaKubeController(kubeClient client.Client, apiReader client.Reader, co
 		logMonitoringReconcilerBuilder:      logmonitoring.NewReconciler,
 		proxyReconcilerBuilder:              proxy.NewReconciler,
 		kspmReconcilerBuilder:               kspm.NewReconciler,
+		egressReconcilerBuilder:             egress.NewReconciler,
 	}
 }
 
 func (controller *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	tokenWatcher := token.NewWatcher(mgr.GetAPIReader())
+	if err := mgr.Add(tokenWatcher); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dynakube.DynaKube{}).
 		Named("dynakube-controller").
-		Owns(&appsv1.StatefulSet{}).
-		Owns(&appsv1.DaemonSet{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&corev1.Secret{}).
+		WatchesRawSource(tokenWatcher.Source()).
+		Owns(&appsv1.StatefulSet{}, builder.WithPredicates(controller.schedule.invalidatingPredicate())).
+		Owns(&appsv1.DaemonSet{}, builder.WithPredicates(controller.schedule.invalidatingPredicate())).
+		Owns(&corev1.ConfigMap{}, builder.WithPredicates(controller.schedule.invalidatingPredicate())).
+		Owns(&corev1.Secret{}, builder.WithPredicates(controller.schedule.invalidatingPredicate())).
 		Complete(controller)
 }
 
@@ -120,12 +147,16 @@ type Controller struct {
 	logMonitoringReconcilerBuilder      logmonitoring.ReconcilerBuilder
 	proxyReconcilerBuilder              proxy.ReconcilerBuilder
 	kspmReconcilerBuilder               kspm.ReconcilerBuilder
+	egressReconcilerBuilder             egress.ReconcilerBuilder
+
+	oneAgentConnectionInfoReconcilerBuilder oaconnectioninfo.ReconcilerBuilder
+
+	schedule      *componentSchedule
+	eventRecorder record.EventRecorder
 
 	tokens            token.Tokens
 	operatorNamespace string
 	clusterID         string
-
-	requeueAfter time.Duration
 }
 
 // Reconcile reads that state of the cluster for a DynaKube object and makes changes based on the state read
@@ -147,8 +178,8 @@ func (controller *Controller) Reconcile(ctx context.Context, request reconcile.R
 	}
 
 	oldStatus := *dk.Status.DeepCopy()
-	controller.requeueAfter = defaultUpdateInterval
 	err = controller.reconcileDynaKube(ctx, dk)
+	controller.recordPendingUpdates(dk, oldStatus)
 	result, err := controller.handleError(ctx, dk, err, oldStatus)
 
 	log.Info("reconciling DynaKube finished", "namespace", request.Namespace, "name", request.Name, "result", result)
@@ -166,6 +197,9 @@ func (controller *Controller) getDynakubeOrCleanup(ctx context.Context, dkName,
 	err := controller.apiReader.Get(ctx, client.ObjectKey{Name: dk.Name, Namespace: dk.Namespace}, dk)
 
 	if k8serrors.IsNotFound(err) {
+		controller.schedule.invalidate(client.ObjectKeyFromObject(dk))
+		deleteTokenExpirationMetric(dkNamespace, dkName)
+
 		namespaces, err := mapper.GetNamespacesForDynakube(ctx, controller.apiReader, dkName)
 		if err != nil {
 			return nil, errors.WithMessagef(err, "failed to list namespaces for dynakube %s", dkName)
@@ -193,7 +227,6 @@ func (controller *Controller) handleError(
 		return reconcile.Result{RequeueAfter: fastUpdateInterval}, nil
 
 	case err != nil:
-		controller.setRequeueAfterIfNewIsShorter(fastUpdateInterval)
 		dk.Status.SetPhase(dynatracestatus.Error)
 		log.Error(err, "error reconciling DynaKube", "namespace", dk.Namespace, "name", dk.Name)
 
@@ -201,11 +234,14 @@ func (controller *Controller) handleError(
 		dk.Status.SetPhase(controller.determineDynaKubePhase(dk))
 	}
 
+	requeueAfter := controller.schedule.requeueAfter(dk)
+
 	if isStatusDifferent, err := hasher.IsDifferent(oldStatus, dk.Status); err != nil {
 		log.Error(err, "failed to generate hash for the status section")
 	} else if isStatusDifferent {
 		log.Info("status changed, updating DynaKube")
-		controller.setRequeueAfterIfNewIsShorter(changesUpdateInterval)
+
+		requeueAfter = min(requeueAfter, changesUpdateInterval)
 
 		if errClient := dk.UpdateStatus(ctx, controller.client); errClient != nil {
 			return reconcile.Result{}, errors.WithMessagef(errClient, "failed to update DynaKube after failure, original error: %s", err)
@@ -216,13 +252,7 @@ func (controller *Controller) handleError(
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{RequeueAfter: controller.requeueAfter}, nil
-}
-
-func (controller *Controller) setRequeueAfterIfNewIsShorter(requeueAfter time.Duration) {
-	if controller.requeueAfter > requeueAfter {
-		controller.requeueAfter = requeueAfter
-	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (controller *Controller) reconcileDynaKube(ctx context.Context, dk *dynakube.DynaKube) error {
@@ -267,6 +297,11 @@ func (controller *Controller) reconcileDynaKube(ctx context.Context, dk *dynakub
 		return err
 	}
 
+	err = releaselock.NewReconciler(controller.apiReader, dk).Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+
 	return controller.reconcileComponents(ctx, dynatraceClient, istioClient, dk)
 }
 
@@ -287,6 +322,18 @@ func (controller *Controller) setupIstioClient(dk *dynakube.DynaKube) (*istio.Cl
 }
 
 func (controller *Controller) setupTokensAndClient(ctx context.Context, dk *dynakube.DynaKube) (dtclient.Client, error) {
+	if dk.OneAgent().GetCodeModulesBundle() != nil {
+		// a bundle is meant for clusters without access to the tenant, the validation ensures no component needs the Dynatrace API
+		log.Info("code modules bundle is used, skipping the Dynatrace API setup", "dynakube", dk.Name)
+
+		controller.tokens = nil
+		meta.RemoveStatusCondition(&dk.Status.Conditions, dynakube.TokenConditionType)
+		meta.RemoveStatusCondition(&dk.Status.Conditions, TokenExpirationConditionType)
+		deleteTokenExpirationMetric(dk.Namespace, dk.Name)
+
+		return nil, nil //nolint:nilnil
+	}
+
 	tokenReader := token.NewReader(controller.apiReader, dk)
 
 	tokens, err := tokenReader.ReadTokens(ctx)
@@ -304,6 +351,8 @@ func (controller *Controller) setupTokensAndClient(ctx context.Context, dk *dyna
 		SetTokens(tokens)
 
 	dynatraceClient, err := dynatraceClientBuilder.BuildWithTokenVerification(&dk.Status)
+	controller.reconcileTokenExpiration(dk)
+
 	if err != nil {
 		controller.setConditionTokenError(dk, err)
 
@@ -315,114 +364,172 @@ func (controller *Controller) setupTokensAndClient(ctx context.Context, dk *dyna
 	return dynatraceClient, nil
 }
 
-func (controller *Controller) reconcileComponents(ctx context.Context, dynatraceClient dtclient.Client, istioClient *istio.Client, dk *dynakube.DynaKube) error {
-	var componentErrors []error
-
-	log.Info("start reconciling ActiveGate")
-
-	err := controller.reconcileActiveGate(ctx, dk, dynatraceClient, istioClient)
-	if err != nil {
-		log.Info("could not reconcile ActiveGate")
-
-		componentErrors = append(componentErrors, err)
-	}
-
-	extensionReconciler := controller.extensionReconcilerBuilder(controller.client, controller.apiReader, dk)
-
-	err = extensionReconciler.Reconcile(ctx)
-	if err != nil {
-		log.Info("could not reconcile Extensions")
-
-		componentErrors = append(componentErrors, err)
-	}
-
-	log.Info("start reconciling otel-collector")
-
-	otelcReconciler := controller.otelcReconcilerBuilder(controller.client, controller.apiReader, dk)
-
-	err = otelcReconciler.Reconcile(ctx)
-	if err != nil {
-		log.Info("could not reconcile otelc")
+const (
+	activeGateComponent             = "ActiveGate"
+	extensionComponent              = "Extensions"
+	otelcComponent                  = "OtelCollector"
+	oneAgentConnectionInfoComponent = "OneAgentConnectionInfo"
+	logMonitoringComponent          = "LogMonitoring"
+	injectionComponent              = "Injection"
+	oneAgentComponent               = "OneAgent"
+	kspmComponent                   = "KSPM"
+	egressComponent                 = "Egress"
+)
 
-		componentErrors = append(componentErrors, err)
+func (controller *Controller) components(dynatraceClient dtclient.Client, istioClient *istio.Client) []component {
+	return []component{
+		{
+			name: activeGateComponent,
+			reconcile: func(ctx context.Context, dk *dynakube.DynaKube) error {
+				return controller.reconcileActiveGate(ctx, dk, dynatraceClient, istioClient)
+			},
+			nextUpdate: func(dk *dynakube.DynaKube) time.Time {
+				return earliest(
+					agconnectioninfo.NextUpdate(dk),
+					version.NextUpdate(dk, dk.Status.ActiveGate.VersionStatus),
+				)
+			},
+		},
+		{
+			name:      extensionComponent,
+			dependsOn: []string{activeGateComponent}, // needs the tenantUUID from the ActiveGate connection info
+			reconcile: func(ctx context.Context, dk *dynakube.DynaKube) error {
+				return controller.extensionReconcilerBuilder(controller.client, controller.apiReader, dk).Reconcile(ctx)
+			},
+		},
+		{
+			name: otelcComponent,
+			reconcile: func(ctx context.Context, dk *dynakube.DynaKube) error {
+				return controller.otelcReconcilerBuilder(controller.client, controller.apiReader, dk).Reconcile(ctx)
+			},
+		},
+		{
+			name: oneAgentConnectionInfoComponent,
+			reconcile: func(ctx context.Context, dk *dynakube.DynaKube) error {
+				return controller.oneAgentConnectionInfoReconcilerBuilder(controller.client, controller.apiReader, dynatraceClient, dk).Reconcile(ctx)
+			},
+			nextUpdate: oaconnectioninfo.NextUpdate,
+		},
+		{
+			name:      logMonitoringComponent,
+			dependsOn: []string{oneAgentConnectionInfoComponent},
+			reconcile: func(ctx context.Context, dk *dynakube.DynaKube) error {
+				return controller.logMonitoringReconcilerBuilder(controller.client, controller.apiReader, dynatraceClient, dk).Reconcile(ctx)
+			},
+			nextUpdate: func(dk *dynakube.DynaKube) time.Time {
+				return earliest(
+					conditions.NextUpdate(dk, monitoredentities.MEIDConditionType),
+					conditions.NextUpdate(dk, logmonsettings.ConditionType),
+				)
+			},
+		},
+		{
+			name:      injectionComponent,
+			dependsOn: []string{oneAgentConnectionInfoComponent},
+			reconcile: func(ctx context.Context, dk *dynakube.DynaKube) error {
+				return controller.injectionReconcilerBuilder(controller.client, controller.apiReader, dynatraceClient, istioClient, dk).Reconcile(ctx)
+			},
+			nextUpdate: func(dk *dynakube.DynaKube) time.Time {
+				return earliest(
+					version.NextUpdate(dk, dk.Status.CodeModules.VersionStatus),
+					conditions.NextUpdate(dk, processmoduleconfigsecret.ConditionType),
+					conditions.NextUpdate(dk, monitoredentities.MEIDConditionType),
+					rules.NextUpdate(dk),
+					injection.NextCoverageUpdate(dk),
+				)
+			},
+		},
+		{
+			name:      oneAgentComponent,
+			dependsOn: []string{oneAgentConnectionInfoComponent},
+			reconcile: func(ctx context.Context, dk *dynakube.DynaKube) error {
+				return controller.oneAgentReconcilerBuilder(
+					controller.client,
+					controller.apiReader,
+					dynatraceClient,
+					dk,
+					controller.tokens,
+					controller.clusterID,
+				).Reconcile(ctx)
+			},
+			nextUpdate: func(dk *dynakube.DynaKube) time.Time {
+				return earliest(
+					version.NextUpdate(dk, dk.Status.OneAgent.VersionStatus),
+					conditions.NextUpdate(dk, dtpullsecret.PullSecretConditionType),
+				)
+			},
+		},
+		{
+			name: kspmComponent,
+			reconcile: func(ctx context.Context, dk *dynakube.DynaKube) error {
+				return controller.kspmReconcilerBuilder(controller.client, controller.apiReader, dk).Reconcile(ctx)
+			},
+		},
+		{
+			name:      egressComponent,
+			dependsOn: []string{activeGateComponent, oneAgentConnectionInfoComponent}, // renders the communication hosts of both connection infos
+			reconcile: func(ctx context.Context, dk *dynakube.DynaKube) error {
+				return controller.egressReconcilerBuilder(controller.client, controller.apiReader, dk).Reconcile(ctx)
+			},
+			nextUpdate: egress.NextUpdate,
+		},
 	}
+}
 
-	log.Info("start reconciling LogMonitoring")
-
-	logMonitoringReconciler := controller.logMonitoringReconcilerBuilder(controller.client, controller.apiReader, dynatraceClient, dk)
+func (controller *Controller) reconcileComponents(ctx context.Context, dynatraceClient dtclient.Client, istioClient *istio.Client, dk *dynakube.DynaKube) error {
+	components := controller.components(dynatraceClient, istioClient)
 
-	err = logMonitoringReconciler.Reconcile(ctx)
+	results, err := runComponents(ctx, dk, components, func(name string) bool {
+		return controller.schedule.isDue(dk, name)
+	})
 	if err != nil {
-		if errors.Is(err, oaconnectioninfo.NoOneAgentCommunicationHostsError) || errors.Is(err, logmondaemonset.KubernetesSettingsNotAvailableError) {
-			controller.setRequeueAfterIfNewIsShorter(fastUpdateInterval)
-
-			return goerrors.Join(componentErrors...)
-		}
-
-		log.Info("could not reconcile LogMonitoring")
-
-		componentErrors = append(componentErrors, err)
+		return err
 	}
 
-	log.Info("start reconciling app injection")
+	var componentErrors []error
 
-	err = controller.injectionReconcilerBuilder(controller.client,
-		controller.apiReader,
-		dynatraceClient,
-		istioClient,
-		dk).
-		Reconcile(ctx)
-	if err != nil {
-		if errors.Is(err, oaconnectioninfo.NoOneAgentCommunicationHostsError) {
-			// missing communication hosts is not an error per se, just make sure next the reconciliation is happening ASAP
-			// this situation will clear itself after AG has been started
-			controller.setRequeueAfterIfNewIsShorter(fastUpdateInterval)
+	now := controller.schedule.now()
 
-			return goerrors.Join(componentErrors...)
-		}
+	for _, c := range components {
+		result := results[c.name]
 
-		log.Info("could not reconcile app injection")
+		switch {
+		case result.err == nil && !result.ran:
+			// not due, the previous deadline stays in place
+		case result.err == nil:
+			setComponentReconciledCondition(dk, c.name)
 
-		componentErrors = append(componentErrors, err)
-	}
+			deadline := now.Add(defaultUpdateInterval)
+			if c.nextUpdate != nil {
+				deadline = earliest(c.nextUpdate(dk), deadline)
+			}
 
-	log.Info("start reconciling OneAgent")
-
-	err = controller.oneAgentReconcilerBuilder(
-		controller.client,
-		controller.apiReader,
-		dynatraceClient,
-		dk,
-		controller.tokens,
-		controller.clusterID,
-	).
-		Reconcile(ctx)
-	if err != nil {
-		if errors.Is(err, oaconnectioninfo.NoOneAgentCommunicationHostsError) {
+			controller.schedule.set(dk, c.name, deadline)
+		case errors.Is(result.err, errDependencyNotReconciled):
+			setComponentDependencyFailedCondition(dk, c.name, result.err)
+			controller.schedule.set(dk, c.name, now)
+		case isPostponed(result.err):
 			// missing communication hosts is not an error per se, just make sure next the reconciliation is happening ASAP
 			// this situation will clear itself after AG has been started
-			controller.setRequeueAfterIfNewIsShorter(fastUpdateInterval)
-
-			return goerrors.Join(componentErrors...)
+			log.Info("component postponed", "component", c.name, "reason", result.err.Error())
+			setComponentPostponedCondition(dk, c.name, result.err)
+			controller.schedule.set(dk, c.name, now.Add(fastUpdateInterval))
+		default:
+			log.Info("could not reconcile component", "component", c.name)
+			setComponentFailedCondition(dk, c.name, result.err)
+			controller.schedule.set(dk, c.name, now)
+
+			componentErrors = append(componentErrors, result.err)
 		}
-
-		log.Info("could not reconcile OneAgent")
-
-		componentErrors = append(componentErrors, err)
-	}
-
-	kspmReconciler := controller.kspmReconcilerBuilder(controller.client, controller.apiReader, dk)
-
-	err = kspmReconciler.Reconcile(ctx)
-	if err != nil {
-		log.Info("could not reconcile kspm")
-
-		componentErrors = append(componentErrors, err)
 	}
 
 	return goerrors.Join(componentErrors...)
 }
 
+func isPostponed(err error) bool {
+	return errors.Is(err, oaconnectioninfo.NoOneAgentCommunicationHostsError) || errors.Is(err, logmondaemonset.KubernetesSettingsNotAvailableError)
+}
+
 func (controller *Controller) createDynakubeMapper(ctx context.Context, dk *dynakube.DynaKube) *mapper.DynakubeMapper {
 	dkMapper := mapper.NewDynakubeMapper(ctx, controller.client, controller.apiReader, controller.operatorNamespace, dk)
 
@@ -17,7 +17,9 @@ import (
 	ag "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/apimonitoring"
+	oaconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/egress"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/extension"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/injection"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/kspm"
@@ -352,10 +354,10 @@ func createDTMockClient(t *testing.T, paasTokenScopes, apiTokenScopes dtclient.T
 			TenantUUID: testUUID,
 		},
 	}, nil).Maybe()
-	mockClient.On("GetTokenScopes", mock.AnythingOfType("context.backgroundCtx"), testPaasToken).
-		Return(paasTokenScopes, nil).Maybe()
-	mockClient.On("GetTokenScopes", mock.AnythingOfType("context.backgroundCtx"), testAPIToken).
-		Return(apiTokenScopes, nil).Maybe()
+	mockClient.On("GetTokenInfo", mock.AnythingOfType("context.backgroundCtx"), testPaasToken).
+		Return(dtclient.TokenInfo{Scopes: paasTokenScopes}, nil).Maybe()
+	mockClient.On("GetTokenInfo", mock.AnythingOfType("context.backgroundCtx"), testAPIToken).
+		Return(dtclient.TokenInfo{Scopes: apiTokenScopes}, nil).Maybe()
 	mockClient.On("GetOneAgentConnectionInfo").
 		Return(
 			mock.AnythingOfType("context.backgroundCtx"),
@@ -440,7 +442,10 @@ func createFakeClientAndReconciler(t *testing.T, mockClient dtclient.Client, dk
 		extensionReconcilerBuilder:          extension.NewReconciler,
 		otelcReconcilerBuilder:              otelc.NewReconciler,
 		kspmReconcilerBuilder:               kspm.NewReconciler,
+		egressReconcilerBuilder:             egress.NewReconciler,
 		clusterID:                           testUID,
+
+		oneAgentConnectionInfoReconcilerBuilder: oaconnectioninfo.NewReconciler,
 	}
 
 	return controller
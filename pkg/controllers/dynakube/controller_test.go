@@ -26,6 +26,7 @@ import (
 	oneagentcontroller "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	dtclientmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/clients/dynatrace"
 	controllermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/controllers"
@@ -150,11 +151,14 @@ func TestHandleError(t *testing.T) {
 	t.Run("no error => update status", func(t *testing.T) {
 		oldDynakube := dynakubeBase.DeepCopy()
 		fakeClient := fake.NewClientWithIndex(oldDynakube)
+		timeProvider := timeprovider.New().Freeze()
 		controller := &Controller{
-			client:       fakeClient,
-			apiReader:    fakeClient,
-			requeueAfter: 12345 * time.Second,
+			client:    fakeClient,
+			apiReader: fakeClient,
+			schedule:  newComponentSchedule(timeProvider),
 		}
+		controller.schedule.set(oldDynakube, "test", timeProvider.Now().Add(2*time.Minute))
+
 		expectedDynakube := dynakubeBase.DeepCopy()
 		expectedDynakube.Status = dynakube.DynaKubeStatus{
 			Phase: status.Running,
@@ -163,13 +167,26 @@ func TestHandleError(t *testing.T) {
 		result, err := controller.handleError(ctx, oldDynakube, nil, oldDynakube.Status)
 
 		require.NoError(t, err)
-		assert.Equal(t, controller.requeueAfter, result.RequeueAfter)
+		assert.Equal(t, 2*time.Minute, result.RequeueAfter)
 
 		dk := &dynakube.DynaKube{}
 		err = fakeClient.Get(ctx, types.NamespacedName{Name: expectedDynakube.Name, Namespace: expectedDynakube.Namespace}, dk)
 		require.NoError(t, err)
 		assert.Equal(t, expectedDynakube.Status.Phase, dk.Status.Phase)
 	})
+	t.Run("no error + status changed => requeue after changes interval at the latest", func(t *testing.T) {
+		oldDynakube := dynakubeBase.DeepCopy()
+		fakeClient := fake.NewClientWithIndex(oldDynakube)
+		controller := &Controller{
+			client:    fakeClient,
+			apiReader: fakeClient,
+		}
+
+		result, err := controller.handleError(ctx, oldDynakube, nil, oldDynakube.Status)
+
+		require.NoError(t, err)
+		assert.Equal(t, changesUpdateInterval, result.RequeueAfter)
+	})
 	t.Run("no error => fail update status => error", func(t *testing.T) {
 		oldDynakube := dynakubeBase.DeepCopy()
 		fakeClient := fake.NewClientWithIndex()
@@ -304,6 +321,29 @@ func TestSetupTokensAndClient(t *testing.T) {
 		assert.NotNil(t, dtc)
 		assertTokenCondition(t, dk, false)
 	})
+	t.Run("code modules bundle => no tokens and no dtclient needed", func(t *testing.T) {
+		dk := dkBase.DeepCopy()
+		dk.Spec.OneAgent.ApplicationMonitoring = &oneagent.ApplicationMonitoringSpec{
+			AppInjectionSpec: oneagent.AppInjectionSpec{
+				CodeModulesBundle: &oneagent.CodeModulesBundleSpec{Path: "/bundles/codemodules.tar.gz"},
+			},
+		}
+		fakeClient := fake.NewClientWithIndex(dk)
+
+		mockDtcBuilder := dtbuildermock.NewBuilder(t)
+
+		controller := &Controller{
+			client:                 fakeClient,
+			apiReader:              fakeClient,
+			dynatraceClientBuilder: mockDtcBuilder,
+		}
+		controller.setConditionTokenError(dk, errors.New("outdated"))
+
+		dtc, err := controller.setupTokensAndClient(ctx, dk)
+		require.NoError(t, err)
+		assert.Nil(t, dtc)
+		assert.Nil(t, meta.FindStatusCondition(dk.Status.Conditions, dynakube.TokenConditionType))
+	})
 }
 
 func assertTokenCondition(t *testing.T, dk *dynakube.DynaKube, hasError bool) {
@@ -343,6 +383,9 @@ func TestReconcileComponents(t *testing.T) {
 		mockActiveGateReconciler := controllermock.NewReconciler(t)
 		mockActiveGateReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
 
+		mockConnectionInfoReconciler := controllermock.NewReconciler(t)
+		mockConnectionInfoReconciler.On("Reconcile", mock.Anything).Return(nil)
+
 		mockInjectionReconciler := injectionmock.NewReconciler(t)
 		mockInjectionReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
 
@@ -350,7 +393,6 @@ func TestReconcileComponents(t *testing.T) {
 		mockLogMonitoringReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
 
 		mockExtensionReconciler := controllermock.NewReconciler(t)
-		mockExtensionReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
 
 		mockOtelcReconciler := controllermock.NewReconciler(t)
 		mockOtelcReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
@@ -363,13 +405,14 @@ func TestReconcileComponents(t *testing.T) {
 			apiReader: fakeClient,
 			fs:        afero.Afero{Fs: afero.NewMemMapFs()},
 
-			activeGateReconcilerBuilder:    createActivegateReconcilerBuilder(mockActiveGateReconciler),
-			injectionReconcilerBuilder:     createInjectionReconcilerBuilder(mockInjectionReconciler),
-			oneAgentReconcilerBuilder:      createOneAgentReconcilerBuilder(mockOneAgentReconciler),
-			logMonitoringReconcilerBuilder: createLogMonitoringReconcilerBuilder(mockLogMonitoringReconciler),
-			extensionReconcilerBuilder:     createExtensionReconcilerBuilder(mockExtensionReconciler),
-			otelcReconcilerBuilder:         createOtelcReconcilerBuilder(mockOtelcReconciler),
-			kspmReconcilerBuilder:          createKSPMReconcilerBuilder(mockKSPMReconciler),
+			activeGateReconcilerBuilder:             createActivegateReconcilerBuilder(mockActiveGateReconciler),
+			oneAgentConnectionInfoReconcilerBuilder: createConnectionInfoReconcilerBuilder(mockConnectionInfoReconciler),
+			injectionReconcilerBuilder:              createInjectionReconcilerBuilder(mockInjectionReconciler),
+			oneAgentReconcilerBuilder:               createOneAgentReconcilerBuilder(mockOneAgentReconciler),
+			logMonitoringReconcilerBuilder:          createLogMonitoringReconcilerBuilder(mockLogMonitoringReconciler),
+			extensionReconcilerBuilder:              createExtensionReconcilerBuilder(mockExtensionReconciler),
+			otelcReconcilerBuilder:                  createOtelcReconcilerBuilder(mockOtelcReconciler),
+			kspmReconcilerBuilder:                   createKSPMReconcilerBuilder(mockKSPMReconciler),
 		}
 		mockedDtc := dtclientmock.NewClient(t)
 
@@ -377,10 +420,24 @@ func TestReconcileComponents(t *testing.T) {
 
 		require.Error(t, err)
 		// goerrors.Join concats errors with \n
-		assert.Len(t, strings.Split(err.Error(), "\n"), 7) // ActiveGate, Extension, OtelC, OneAgent LogMonitoring, and Injection reconcilers
+		assert.Len(t, strings.Split(err.Error(), "\n"), 6) // ActiveGate, OtelC, OneAgent, LogMonitoring, Injection and KSPM reconcilers, Extensions depend on ActiveGate
+
+		for _, name := range []string{extensionComponent, egressComponent} {
+			condition := meta.FindStatusCondition(dk.Status.Conditions, componentConditionType(name))
+			require.NotNil(t, condition)
+			assert.Equal(t, ComponentDependencyFailedReason, condition.Reason)
+		}
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, componentConditionType(oneAgentConnectionInfoComponent))
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+
+		condition = meta.FindStatusCondition(dk.Status.Conditions, componentConditionType(kspmComponent))
+		require.NotNil(t, condition)
+		assert.Equal(t, ComponentReconcileFailedReason, condition.Reason)
 	})
 
-	t.Run("exit early in case of no oneagent conncection info", func(t *testing.T) {
+	t.Run("no oneagent connection info => only dependent components are skipped", func(t *testing.T) {
 		dk := dkBaser.DeepCopy()
 		fakeClient := fake.NewClientWithIndex(dk)
 
@@ -388,22 +445,27 @@ func TestReconcileComponents(t *testing.T) {
 		mockActiveGateReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
 
 		mockExtensionReconciler := controllermock.NewReconciler(t)
-		mockExtensionReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
 
 		mockOtelcReconciler := controllermock.NewReconciler(t)
 		mockOtelcReconciler.On("Reconcile", mock.Anything).Return(errors.New("BOOM"))
 
-		mockLogMonitoringReconciler := injectionmock.NewReconciler(t)
-		mockLogMonitoringReconciler.On("Reconcile", mock.Anything).Return(oaconnectioninfo.NoOneAgentCommunicationHostsError)
+		mockConnectionInfoReconciler := controllermock.NewReconciler(t)
+		mockConnectionInfoReconciler.On("Reconcile", mock.Anything).Return(oaconnectioninfo.NoOneAgentCommunicationHostsError)
+
+		mockKSPMReconciler := controllermock.NewReconciler(t)
+		mockKSPMReconciler.On("Reconcile", mock.Anything).Return(nil)
 
+		timeProvider := timeprovider.New().Freeze()
 		controller := &Controller{
-			client:                         fakeClient,
-			apiReader:                      fakeClient,
-			fs:                             afero.Afero{Fs: afero.NewMemMapFs()},
-			activeGateReconcilerBuilder:    createActivegateReconcilerBuilder(mockActiveGateReconciler),
-			logMonitoringReconcilerBuilder: createLogMonitoringReconcilerBuilder(mockLogMonitoringReconciler),
-			extensionReconcilerBuilder:     createExtensionReconcilerBuilder(mockExtensionReconciler),
-			otelcReconcilerBuilder:         createOtelcReconcilerBuilder(mockOtelcReconciler),
+			client:                                  fakeClient,
+			apiReader:                               fakeClient,
+			fs:                                      afero.Afero{Fs: afero.NewMemMapFs()},
+			schedule:                                newComponentSchedule(timeProvider),
+			activeGateReconcilerBuilder:             createActivegateReconcilerBuilder(mockActiveGateReconciler),
+			oneAgentConnectionInfoReconcilerBuilder: createConnectionInfoReconcilerBuilder(mockConnectionInfoReconciler),
+			extensionReconcilerBuilder:              createExtensionReconcilerBuilder(mockExtensionReconciler),
+			otelcReconcilerBuilder:                  createOtelcReconcilerBuilder(mockOtelcReconciler),
+			kspmReconcilerBuilder:                   createKSPMReconcilerBuilder(mockKSPMReconciler),
 		}
 		mockedDtc := dtclientmock.NewClient(t)
 
@@ -411,10 +473,91 @@ func TestReconcileComponents(t *testing.T) {
 
 		require.Error(t, err)
 		// goerrors.Join concats errors with \n
-		assert.Len(t, strings.Split(err.Error(), "\n"), 3) // ActiveGate, Extension, OtelC, no OneAgent connection info is not an error
+		assert.Len(t, strings.Split(err.Error(), "\n"), 2) // ActiveGate, OtelC, no OneAgent connection info is not an error
+		assert.Equal(t, fastUpdateInterval, controller.schedule.requeueAfter(dk))
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, componentConditionType(oneAgentConnectionInfoComponent))
+		require.NotNil(t, condition)
+		assert.Equal(t, ComponentPostponedReason, condition.Reason)
+
+		for _, name := range []string{logMonitoringComponent, injectionComponent, oneAgentComponent, egressComponent} {
+			condition := meta.FindStatusCondition(dk.Status.Conditions, componentConditionType(name))
+			require.NotNil(t, condition)
+			assert.Equal(t, ComponentDependencyFailedReason, condition.Reason)
+		}
+
+		condition = meta.FindStatusCondition(dk.Status.Conditions, componentConditionType(kspmComponent))
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
 	})
 }
 
+func TestReconcileComponentsSchedule(t *testing.T) {
+	ctx := context.Background()
+	dk := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "this-is-a-name",
+			Namespace:  "dynatrace",
+			Generation: 1,
+		},
+	}
+
+	mockActiveGateReconciler := controllermock.NewReconciler(t)
+	mockActiveGateReconciler.On("Reconcile", mock.Anything).Return(nil).Once()
+
+	mockExtensionReconciler := controllermock.NewReconciler(t)
+	mockExtensionReconciler.On("Reconcile", mock.Anything).Return(nil).Once()
+
+	mockOtelcReconciler := controllermock.NewReconciler(t)
+	mockOtelcReconciler.On("Reconcile", mock.Anything).Return(nil).Once()
+
+	mockConnectionInfoReconciler := controllermock.NewReconciler(t)
+	mockConnectionInfoReconciler.On("Reconcile", mock.Anything).Return(oaconnectioninfo.NoOneAgentCommunicationHostsError).Twice()
+
+	mockKSPMReconciler := controllermock.NewReconciler(t)
+	mockKSPMReconciler.On("Reconcile", mock.Anything).Return(nil).Once()
+
+	timeProvider := timeprovider.New().Freeze()
+	controller := &Controller{
+		schedule:                                newComponentSchedule(timeProvider),
+		activeGateReconcilerBuilder:             createActivegateReconcilerBuilder(mockActiveGateReconciler),
+		oneAgentConnectionInfoReconcilerBuilder: createConnectionInfoReconcilerBuilder(mockConnectionInfoReconciler),
+		extensionReconcilerBuilder:              createExtensionReconcilerBuilder(mockExtensionReconciler),
+		otelcReconcilerBuilder:                  createOtelcReconcilerBuilder(mockOtelcReconciler),
+		kspmReconcilerBuilder:                   createKSPMReconcilerBuilder(mockKSPMReconciler),
+	}
+
+	err := controller.reconcileComponents(ctx, nil, nil, dk)
+	require.NoError(t, err)
+	assert.Equal(t, fastUpdateInterval, controller.schedule.requeueAfter(dk))
+
+	// only the postponed component is due after the fast update interval, the rest is skipped
+	timeProvider.Set(timeProvider.Now().Add(fastUpdateInterval))
+
+	err = controller.reconcileComponents(ctx, nil, nil, dk)
+	require.NoError(t, err)
+
+	t.Run("changed generation => everything is due", func(t *testing.T) {
+		changed := dk.DeepCopy()
+		changed.Generation++
+
+		assert.True(t, controller.schedule.isDue(changed, activeGateComponent))
+		assert.False(t, controller.schedule.isDue(dk, activeGateComponent))
+	})
+
+	t.Run("invalidate => everything is due", func(t *testing.T) {
+		controller.schedule.invalidate(client.ObjectKeyFromObject(dk))
+
+		assert.True(t, controller.schedule.isDue(dk, activeGateComponent))
+	})
+}
+
+func createConnectionInfoReconcilerBuilder(reconciler controllers.Reconciler) oaconnectioninfo.ReconcilerBuilder {
+	return func(_ client.Client, _ client.Reader, _ dtclient.Client, _ *dynakube.DynaKube) controllers.Reconciler {
+		return reconciler
+	}
+}
+
 func createActivegateReconcilerBuilder(reconciler controllers.Reconciler) ag.ReconcilerBuilder {
 	return func(_ client.Client, _ client.Reader, _ *dynakube.DynaKube, _ dtclient.Client, _ *istio.Client, _ token.Tokens) controllers.Reconciler {
 		return reconciler
@@ -1,12 +1,15 @@
 package dtpullsecret
 
 import (
+	"context"
 	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/url"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -35,7 +38,7 @@ func newDockerConfigWithAuth(username string, password string, registry string,
 	}
 }
 
-func (r *Reconciler) GenerateData() (map[string][]byte, error) {
+func (r *Reconciler) GenerateData(ctx context.Context) (map[string][]byte, error) {
 	var registryToken string
 
 	registry, err := getImageRegistryFromAPIURL(r.dk.Spec.APIURL)
@@ -62,9 +65,43 @@ func (r *Reconciler) GenerateData() (map[string][]byte, error) {
 		registry,
 		r.buildAuthString(tenantUUID, registryToken))
 
+	err = r.addRegistryMirrorAuths(ctx, dockerCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return pullSecretDataFromDockerConfig(dockerCfg)
 }
 
+// addRegistryMirrorAuths merges the credentials of the registry mirror pull secrets into the docker config,
+// so every image redirected to a mirror can be pulled with the generated pull secret.
+// The credentials for the tenant registry are never overwritten.
+func (r *Reconciler) addRegistryMirrorAuths(ctx context.Context, dockerCfg *dockerConfig) error {
+	for _, secretName := range r.dk.RegistryMirrorPullSecrets() {
+		var mirrorSecret corev1.Secret
+
+		err := r.apiReader.Get(ctx, client.ObjectKey{Name: secretName, Namespace: r.dk.Namespace}, &mirrorSecret)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to get registry mirror pull secret %s", secretName)
+		}
+
+		var mirrorCfg dockerConfig
+
+		err = json.Unmarshal(mirrorSecret.Data[DockerConfigJson], &mirrorCfg)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to parse registry mirror pull secret %s", secretName)
+		}
+
+		for registry, auth := range mirrorCfg.Auths {
+			if _, ok := dockerCfg.Auths[registry]; !ok {
+				dockerCfg.Auths[registry] = auth
+			}
+		}
+	}
+
+	return nil
+}
+
 func (r *Reconciler) buildAuthString(tenantUUID string, registryToken string) string {
 	auth := fmt.Sprintf("%s:%s", tenantUUID, registryToken)
 
@@ -6,13 +6,17 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/communication"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/registrymirror"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -56,7 +60,7 @@ func TestReconciler_GenerateData(t *testing.T) {
 		},
 	}
 
-	data, err := r.GenerateData()
+	data, err := r.GenerateData(t.Context())
 
 	require.NoError(t, err)
 	assert.NotNil(t, data)
@@ -80,3 +84,73 @@ func TestReconciler_GenerateData(t *testing.T) {
 	assert.NotNil(t, actual)
 	assert.Equal(t, expected, actual)
 }
+
+func TestReconciler_GenerateDataWithRegistryMirror(t *testing.T) {
+	const mirrorHost = "registry.corp"
+
+	mirrorConfig := dockerConfig{
+		Auths: map[string]dockerAuthentication{
+			mirrorHost:     {Username: "mirror-user", Password: "mirror-password", Auth: "mirror-auth"},
+			testApiUrlHost: {Username: "other-user", Password: "other-password", Auth: "other-auth"},
+		},
+	}
+	mirrorConfigJson, err := json.Marshal(mirrorConfig)
+	require.NoError(t, err)
+
+	mirrorSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mirror-pull-secret", Namespace: "dynatrace"},
+		Data:       map[string][]byte{DockerConfigJson: mirrorConfigJson},
+	}
+
+	dk := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "dynatrace"},
+		Spec: dynakube.DynaKubeSpec{
+			APIURL: testApiUrl,
+			RegistryMirror: &registrymirror.Spec{
+				Rules: []registrymirror.Rule{{From: "public.ecr.aws/dynatrace", To: mirrorHost + "/dt", PullSecret: mirrorSecret.Name}},
+			},
+		},
+		Status: dynakube.DynaKubeStatus{
+			OneAgent: oneagent.Status{
+				ConnectionInfoStatus: oneagent.ConnectionInfoStatus{
+					ConnectionInfo: communication.ConnectionInfo{
+						TenantUUID: testTenant,
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("credentials of the mirror are added", func(t *testing.T) {
+		r := &Reconciler{
+			dk:        dk,
+			apiReader: fake.NewClient(mirrorSecret),
+			tokens: token.Tokens{
+				dtclient.PaasToken: &token.Token{Value: testPaasToken},
+			},
+		}
+
+		data, err := r.GenerateData(t.Context())
+		require.NoError(t, err)
+
+		var actual dockerConfig
+		require.NoError(t, json.Unmarshal(data[DockerConfigJson], &actual))
+
+		require.Len(t, actual.Auths, 2)
+		assert.Equal(t, mirrorConfig.Auths[mirrorHost], actual.Auths[mirrorHost])
+		assert.Equal(t, testTenant, actual.Auths[testApiUrlHost].Username)
+	})
+
+	t.Run("missing pull secret of the mirror is an error", func(t *testing.T) {
+		r := &Reconciler{
+			dk:        dk,
+			apiReader: fake.NewClient(),
+			tokens: token.Tokens{
+				dtclient.PaasToken: &token.Token{Value: testPaasToken},
+			},
+		}
+
+		_, err := r.GenerateData(t.Context())
+		require.Error(t, err)
+	})
+}
@@ -70,7 +70,7 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 }
 
 func (r *Reconciler) reconcilePullSecret(ctx context.Context) error {
-	pullSecretData, err := r.GenerateData()
+	pullSecretData, err := r.GenerateData(ctx)
 	if err != nil {
 		return errors.WithMessage(err, "could not generate pull secret data")
 	}
@@ -88,14 +88,16 @@ func (dynatraceClientBuilder builder) Build() (dtclient.Client, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	apiToken := dynatraceClientBuilder.getTokens().ApiToken().Value
-	paasToken := dynatraceClientBuilder.getTokens().PaasToken().Value
+	apiToken := dynatraceClientBuilder.getTokens().ApiToken()
+	paasToken := dynatraceClientBuilder.getTokens().PaasToken()
 
-	if paasToken == "" {
+	if paasToken.Value == "" {
 		paasToken = apiToken
 	}
 
-	return dtclient.NewClient(dynatraceClientBuilder.dk.Spec.APIURL, apiToken, paasToken, opts.Opts...)
+	opts.appendBearerTokens(apiToken.Bearer, paasToken.Bearer)
+
+	return dtclient.NewClient(dynatraceClientBuilder.dk.Spec.APIURL, apiToken.Value, paasToken.Value, opts.Opts...)
 }
 
 func (dynatraceClientBuilder builder) BuildWithTokenVerification(dkStatus *dynakube.DynaKubeStatus) (dtclient.Client, error) {
@@ -137,6 +139,7 @@ func (dynatraceClientBuilder builder) verifyTokenScopes(dynatraceClient dtclient
 	log.Info("token verified")
 
 	dkStatus.DynatraceApi.LastTokenScopeRequest = metav1.Now()
+	dkStatus.DynatraceApi.Tokens = dynatraceClientBuilder.tokens.ExpirationStatus()
 
 	return nil
 }
@@ -34,6 +34,16 @@ func (opts *options) appendHostGroup(hostGroup string) {
 	}
 }
 
+func (opts *options) appendBearerTokens(apiToken, paasToken bool) {
+	if apiToken {
+		opts.Opts = append(opts.Opts, dtclient.BearerApiToken())
+	}
+
+	if paasToken {
+		opts.Opts = append(opts.Opts, dtclient.BearerPaasToken())
+	}
+}
+
 func (opts *options) appendCertCheck(skipCertCheck bool) {
 	opts.Opts = append(opts.Opts, dtclient.SkipCertificateValidation(skipCertCheck))
 }
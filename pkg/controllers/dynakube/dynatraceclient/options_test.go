@@ -44,6 +44,18 @@ func TestOptions(t *testing.T) {
 
 		assert.NotEmpty(t, opts.Opts)
 	})
+	t.Run(`Test append bearer tokens`, func(t *testing.T) {
+		opts := newOptions(context.Background())
+
+		opts.appendBearerTokens(false, false)
+		assert.Empty(t, opts.Opts)
+
+		opts.appendBearerTokens(true, false)
+		assert.Len(t, opts.Opts, 1)
+
+		opts.appendBearerTokens(true, true)
+		assert.Len(t, opts.Opts, 3)
+	})
 	t.Run(`Test append cert check`, func(t *testing.T) {
 		opts := newOptions(context.Background())
 
@@ -0,0 +1,103 @@
+package egress
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// backend renders the egress allow-lists for one network layer.
+type backend interface {
+	build(ctx context.Context, dk *dynakube.DynaKube, hosts communicationHosts) ([]client.Object, error)
+}
+
+func buildObjectMeta(dk *dynakube.DynaKube, name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels:    labels.NewCoreLabels(dk.Name, ComponentLabel).BuildLabels(),
+	}
+}
+
+// buildPodSelector selects the pods that need to reach the communication hosts.
+// In the namespace of the DynaKube these are the pods of the operator and the Dynatrace components,
+// in the monitored namespaces the pods the OneAgent was injected into by the webhook, if the DynaKube opted in to select them.
+func buildPodSelector(dk *dynakube.DynaKube, namespace string) metav1.LabelSelector {
+	if namespace != dk.Namespace {
+		return metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				dtwebhook.InjectedPodLabel: dk.Name,
+			},
+		}
+	}
+
+	return metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      labels.AppNameLabel,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   dynatracePodNames,
+			},
+		},
+	}
+}
+
+// toSelectorMap converts the selector to the JSON compatible form used in the spec of unstructured objects.
+func toSelectorMap(selector metav1.LabelSelector) map[string]any {
+	result := map[string]any{}
+
+	if len(selector.MatchLabels) > 0 {
+		matchLabels := map[string]any{}
+		for key, value := range selector.MatchLabels {
+			matchLabels[key] = value
+		}
+
+		result["matchLabels"] = matchLabels
+	}
+
+	if len(selector.MatchExpressions) > 0 {
+		matchExpressions := make([]any, 0, len(selector.MatchExpressions))
+		for _, expression := range selector.MatchExpressions {
+			matchExpressions = append(matchExpressions, map[string]any{
+				"key":      expression.Key,
+				"operator": string(expression.Operator),
+				"values":   toAnySlice(expression.Values),
+			})
+		}
+
+		result["matchExpressions"] = matchExpressions
+	}
+
+	return result
+}
+
+// newUnstructured is used for the kinds of CRDs, that are not part of the operator's scheme.
+// The spec must only contain JSON compatible types, e.g. []any instead of []string and int64 instead of int.
+func newUnstructured(gvk schema.GroupVersionKind, objectMeta metav1.ObjectMeta, spec map[string]any) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{
+		Object: map[string]any{
+			"spec": spec,
+		},
+	}
+	object.SetGroupVersionKind(gvk)
+	object.SetName(objectMeta.Name)
+	object.SetNamespace(objectMeta.Namespace)
+	object.SetLabels(objectMeta.Labels)
+
+	return object
+}
+
+func toAnySlice(values []string) []any {
+	result := make([]any, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+
+	return result
+}
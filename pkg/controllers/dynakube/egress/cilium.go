@@ -0,0 +1,113 @@
+package egress
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ciliumBackend renders a CiliumNetworkPolicy per namespace, FQDN hosts are allowed by name using toFQDNs.
+// DNS, the kube-apiserver and the endpoints of the cluster are always allowed, so the selected pods don't lose their in-cluster egress.
+type ciliumBackend struct{}
+
+func (ciliumBackend) build(_ context.Context, dk *dynakube.DynaKube, hosts communicationHosts) ([]client.Object, error) {
+	objects := []client.Object{buildCiliumNetworkPolicy(dk, dk.Namespace, hosts.all)}
+
+	for _, namespace := range hosts.monitoredNamespaces {
+		objects = append(objects, buildCiliumNetworkPolicy(dk, namespace, hosts.codeModules))
+	}
+
+	return objects, nil
+}
+
+func buildCiliumNetworkPolicy(dk *dynakube.DynaKube, namespace string, hosts []dtclient.CommunicationHost) *unstructured.Unstructured {
+	ipHosts, fqdnHosts := splitCommunicationHosts(hosts)
+	rules := []any{}
+
+	ports, hostnames := groupByPort(fqdnHosts, func(host dtclient.CommunicationHost) string {
+		return host.Host
+	})
+	for _, port := range ports {
+		fqdnSelectors := make([]any, 0, len(hostnames[port]))
+		for _, hostname := range hostnames[port] {
+			fqdnSelectors = append(fqdnSelectors, map[string]any{"matchName": hostname})
+		}
+
+		rules = append(rules, map[string]any{
+			"toFQDNs": fqdnSelectors,
+			"toPorts": tcpPorts(port),
+		})
+	}
+
+	ports, cidrs := groupByPort(ipHosts, func(host dtclient.CommunicationHost) string {
+		return toCIDR(host.Host)
+	})
+	for _, port := range ports {
+		rules = append(rules, map[string]any{
+			"toCIDR":  toAnySlice(cidrs[port]),
+			"toPorts": tcpPorts(port),
+		})
+	}
+
+	// toFQDNs only works if the DNS lookups are visible to the Cilium DNS proxy
+	rules = append(rules, dnsRule(), map[string]any{
+		"toEntities": []any{"kube-apiserver", "cluster"},
+	})
+
+	return newUnstructured(ciliumNetworkPolicyGVK, buildObjectMeta(dk, dk.Egress().GetName(), namespace), map[string]any{
+		"endpointSelector": toSelectorMap(buildPodSelector(dk, namespace)),
+		"egress":           rules,
+	})
+}
+
+// dnsRule allows the lookups to the cluster DNS, which is matched by its k8s-app label in any namespace,
+// as not every distribution runs it in kube-system.
+func dnsRule() map[string]any {
+	return map[string]any{
+		"toEndpoints": []any{
+			map[string]any{
+				"matchLabels": map[string]any{
+					"k8s:k8s-app": "kube-dns",
+				},
+				"matchExpressions": []any{
+					map[string]any{
+						"key":      "k8s:io.kubernetes.pod.namespace",
+						"operator": "Exists",
+					},
+				},
+			},
+		},
+		"toPorts": []any{
+			map[string]any{
+				"ports": []any{
+					map[string]any{
+						"port":     "53",
+						"protocol": "ANY",
+					},
+				},
+				"rules": map[string]any{
+					"dns": []any{
+						map[string]any{"matchPattern": "*"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func tcpPorts(port uint32) []any {
+	return []any{
+		map[string]any{
+			"ports": []any{
+				map[string]any{
+					"port":     strconv.FormatUint(uint64(port), 10),
+					"protocol": "TCP",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package egress
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/egress"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ConditionType = "EgressPolicy"
+
+	conditionReasonConfigured = "EgressPolicyConfigured"
+	conditionReasonFailed     = "EgressPolicyFailed"
+
+	// resolvedHostsRefreshInterval limits how long the IP addresses of the NetworkPolicy backend are used, before the hosts are resolved again.
+	resolvedHostsRefreshInterval = 5 * time.Minute
+)
+
+// NextUpdate returns when the IP addresses the communication hosts were resolved to need to be refreshed,
+// the zero time is returned for the backends that allow the hosts by name.
+func NextUpdate(dk *dynakube.DynaKube) time.Time {
+	if !dk.Egress().IsEnabled() || dk.Egress().GetBackend() != egress.NetworkPolicyBackend {
+		return time.Time{}
+	}
+
+	condition := meta.FindStatusCondition(*dk.Conditions(), ConditionType)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return time.Time{}
+	}
+
+	return condition.LastTransitionTime.Add(resolvedHostsRefreshInterval)
+}
+
+// setResolvedCondition sets the configured condition with a new transition time, which marks when the hosts were resolved.
+func setResolvedCondition(conditions *[]metav1.Condition, backend egress.Backend, hostCount int) {
+	meta.RemoveStatusCondition(conditions, ConditionType)
+	setConfiguredCondition(conditions, backend, hostCount)
+}
+
+func setConfiguredCondition(conditions *[]metav1.Condition, backend egress.Backend, hostCount int) {
+	condition := metav1.Condition{
+		Type:    ConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  conditionReasonConfigured,
+		Message: fmt.Sprintf("Egress allow-lists for %d communication hosts have been configured using the %s backend.", hostCount, backend),
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
+
+func setFailedCondition(conditions *[]metav1.Condition, backend egress.Backend, err error) {
+	condition := metav1.Condition{
+		Type:    ConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  conditionReasonFailed,
+		Message: fmt.Sprintf("Failed to configure the egress allow-lists using the %s backend with error: %s", backend, err.Error()),
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
@@ -0,0 +1,44 @@
+package egress
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/Dynatrace/dynatrace-operator/pkg/version"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	log = logd.Get().WithName("dynakube-egress")
+)
+
+const (
+	ComponentLabel = "egress"
+
+	gatewayAPIGroup = "gateway.networking.k8s.io"
+	linkerdAPIGroup = "policy.linkerd.io"
+
+	apiServerServiceName      = "kubernetes"
+	apiServerServiceNamespace = "default"
+
+	dnsPort = 53
+)
+
+var (
+	// dynatracePodNames are the app.kubernetes.io/name labels of the pods of the operator and the Dynatrace components,
+	// the LogMonitoring and KSPM pods use the core labels, so they are covered by the name of the operator.
+	dynatracePodNames = []string{
+		version.AppName,
+		labels.OneAgentComponentLabel,
+		labels.ActiveGateComponentLabel,
+		labels.ExtensionComponentLabel,
+		labels.OtelCComponentLabel,
+	}
+
+	networkPolicyGVK       = schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}
+	ciliumNetworkPolicyGVK = schema.GroupVersionKind{Group: "cilium.io", Version: "v2", Kind: "CiliumNetworkPolicy"}
+	tlsRouteGVK            = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1alpha2", Kind: "TLSRoute"}
+	serviceGVK             = schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+
+	// managedKinds are all kinds created by any of the backends, checked for leftovers when the backend or the hosts change.
+	managedKinds = []schema.GroupVersionKind{networkPolicyGVK, ciliumNetworkPolicyGVK, tlsRouteGVK, serviceGVK}
+)
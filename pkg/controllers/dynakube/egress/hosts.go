@@ -0,0 +1,157 @@
+package egress
+
+import (
+	"cmp"
+	"context"
+	"net"
+	"slices"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/activegate"
+	oaconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
+	"github.com/pkg/errors"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// communicationHosts are the hosts the egress allow-lists are rendered for, the same hosts the istio reconciler uses.
+type communicationHosts struct {
+	// all hosts need to be reachable from the namespace of the DynaKube
+	all []dtclient.CommunicationHost
+
+	// codeModules hosts need to be reachable from the monitored namespaces
+	codeModules []dtclient.CommunicationHost
+
+	// monitoredNamespaces are only set, if the injected pods are selected by the policies, see egress.Egress.SelectsInjectedPods
+	monitoredNamespaces []string
+}
+
+func getCommunicationHosts(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube) (communicationHosts, error) {
+	apiHost, err := dtclient.ParseEndpoint(dk.Spec.APIURL)
+	if err != nil {
+		return communicationHosts{}, err
+	}
+
+	oneAgentHosts := oaconnectioninfo.GetCommunicationHosts(dk)
+
+	all := append([]dtclient.CommunicationHost{apiHost}, oneAgentHosts...)
+	if dk.ActiveGate().IsEnabled() {
+		all = append(all, activegate.GetEndpointsAsCommunicationHosts(dk)...)
+	}
+
+	hosts := communicationHosts{
+		all: sortHosts(all),
+	}
+
+	if !dk.Egress().SelectsInjectedPods() || !dk.OneAgent().IsAppInjectionNeeded() || len(oneAgentHosts) == 0 {
+		return hosts, nil
+	}
+
+	namespaces, err := mapper.GetNamespacesForDynakube(ctx, apiReader, dk.Name)
+	if err != nil {
+		return communicationHosts{}, errors.WithMessage(err, "failed to list the monitored namespaces")
+	}
+
+	for _, namespace := range namespaces {
+		if namespace.Name != dk.Namespace {
+			hosts.monitoredNamespaces = append(hosts.monitoredNamespaces, namespace.Name)
+		}
+	}
+
+	slices.Sort(hosts.monitoredNamespaces)
+	hosts.codeModules = sortHosts(oneAgentHosts)
+
+	return hosts, nil
+}
+
+// sortHosts removes duplicates and sorts the hosts, so the rendered objects don't change with the order of the connection info.
+func sortHosts(hosts []dtclient.CommunicationHost) []dtclient.CommunicationHost {
+	sorted := slices.Clone(hosts)
+	slices.SortFunc(sorted, func(a, b dtclient.CommunicationHost) int {
+		return cmp.Or(
+			cmp.Compare(a.Host, b.Host),
+			cmp.Compare(a.Port, b.Port),
+			cmp.Compare(a.Protocol, b.Protocol),
+		)
+	})
+
+	return slices.Compact(sorted)
+}
+
+func splitCommunicationHosts(hosts []dtclient.CommunicationHost) (ipHosts, fqdnHosts []dtclient.CommunicationHost) {
+	for _, host := range hosts {
+		if net.ParseIP(host.Host) != nil {
+			ipHosts = append(ipHosts, host)
+		} else {
+			fqdnHosts = append(fqdnHosts, host)
+		}
+	}
+
+	return
+}
+
+// groupByPort groups the values built from the hosts by port, the ports are returned in ascending order.
+func groupByPort(hosts []dtclient.CommunicationHost, value func(dtclient.CommunicationHost) string) ([]uint32, map[uint32][]string) {
+	grouped := map[uint32][]string{}
+
+	for _, host := range hosts {
+		if !slices.Contains(grouped[host.Port], value(host)) {
+			grouped[host.Port] = append(grouped[host.Port], value(host))
+		}
+	}
+
+	ports := make([]uint32, 0, len(grouped))
+	for port := range grouped {
+		ports = append(ports, port)
+	}
+
+	slices.Sort(ports)
+
+	return ports, grouped
+}
+
+func toCIDR(ip string) string {
+	if net.ParseIP(ip).To4() != nil {
+		return ip + "/32"
+	}
+
+	return ip + "/128"
+}
+
+// getAPIServerHosts returns the endpoints of the kube-apiserver, read from the EndpointSlices of the kubernetes Service,
+// as NetworkPolicies are applied after the Service IP was translated to one of them.
+func getAPIServerHosts(ctx context.Context, apiReader client.Reader) ([]dtclient.CommunicationHost, error) {
+	var endpointSlices discoveryv1.EndpointSliceList
+
+	err := apiReader.List(ctx, &endpointSlices,
+		client.InNamespace(apiServerServiceNamespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: apiServerServiceName},
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list the endpoints of the kube-apiserver")
+	}
+
+	var hosts []dtclient.CommunicationHost
+
+	for _, endpointSlice := range endpointSlices.Items {
+		for _, port := range endpointSlice.Ports {
+			if port.Port == nil {
+				continue
+			}
+
+			for _, endpoint := range endpointSlice.Endpoints {
+				for _, address := range endpoint.Addresses {
+					hosts = append(hosts, dtclient.CommunicationHost{
+						Protocol: "https",
+						Host:     address,
+						Port:     uint32(*port.Port), //nolint:gosec
+					})
+				}
+			}
+		}
+	}
+
+	return sortHosts(hosts), nil
+}
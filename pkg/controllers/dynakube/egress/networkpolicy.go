@@ -0,0 +1,162 @@
+package egress
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type lookupHostFunc func(ctx context.Context, host string) ([]string, error)
+
+// networkPolicyBackend renders a NetworkPolicy per namespace.
+// NetworkPolicies only know IP blocks, so the FQDN hosts are resolved to the IP addresses they point to at the time of the reconciliation.
+// Those IPs are pinned until the next reconciliation, which is scheduled at the latest after resolvedHostsRefreshInterval, see NextUpdate.
+// A host that changes its IPs in the meantime is blocked until then.
+// DNS, the kube-apiserver and the pods of the cluster are always allowed, so the selected pods don't lose their in-cluster egress.
+type networkPolicyBackend struct {
+	apiReader  client.Reader
+	lookupHost lookupHostFunc
+}
+
+func (b networkPolicyBackend) build(ctx context.Context, dk *dynakube.DynaKube, hosts communicationHosts) ([]client.Object, error) {
+	resolved := map[string][]string{}
+
+	allHosts, err := b.resolve(ctx, hosts.all, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterRules, err := b.buildClusterRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []client.Object{buildNetworkPolicy(dk, dk.Namespace, allHosts, clusterRules)}
+
+	if len(hosts.monitoredNamespaces) == 0 {
+		return objects, nil
+	}
+
+	codeModuleHosts, err := b.resolve(ctx, hosts.codeModules, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, namespace := range hosts.monitoredNamespaces {
+		objects = append(objects, buildNetworkPolicy(dk, namespace, codeModuleHosts, clusterRules))
+	}
+
+	return objects, nil
+}
+
+// resolve replaces the FQDN hosts with a host per IP address they resolve to, lookups are cached in resolved.
+func (b networkPolicyBackend) resolve(ctx context.Context, hosts []dtclient.CommunicationHost, resolved map[string][]string) ([]dtclient.CommunicationHost, error) {
+	ipHosts, fqdnHosts := splitCommunicationHosts(hosts)
+
+	for _, fqdnHost := range fqdnHosts {
+		ips, ok := resolved[fqdnHost.Host]
+		if !ok {
+			var err error
+
+			ips, err = b.lookupHost(ctx, fqdnHost.Host)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to resolve communication host %s", fqdnHost.Host)
+			}
+
+			resolved[fqdnHost.Host] = ips
+		}
+
+		for _, ip := range ips {
+			ipHosts = append(ipHosts, dtclient.CommunicationHost{
+				Protocol: fqdnHost.Protocol,
+				Host:     ip,
+				Port:     fqdnHost.Port,
+			})
+		}
+	}
+
+	return sortHosts(ipHosts), nil
+}
+
+// buildClusterRules allows DNS on any destination, as the cluster DNS isn't labeled the same way on every distribution and may run on the node,
+// all pods of the cluster and the endpoints of the kube-apiserver, which usually are outside the pod network.
+func (b networkPolicyBackend) buildClusterRules(ctx context.Context) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	rules := []networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				buildNetworkPolicyPort(corev1.ProtocolUDP, dnsPort),
+				buildNetworkPolicyPort(corev1.ProtocolTCP, dnsPort),
+			},
+		},
+		{
+			To: []networkingv1.NetworkPolicyPeer{
+				{NamespaceSelector: &metav1.LabelSelector{}},
+			},
+		},
+	}
+
+	apiServerHosts, err := getAPIServerHosts(ctx, b.apiReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(rules, buildIPBlockRules(apiServerHosts)...), nil
+}
+
+func buildNetworkPolicy(dk *dynakube.DynaKube, namespace string, ipHosts []dtclient.CommunicationHost, clusterRules []networkingv1.NetworkPolicyEgressRule) *networkingv1.NetworkPolicy {
+	rules := append(buildIPBlockRules(ipHosts), clusterRules...)
+
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkPolicyGVK.GroupVersion().String(),
+			Kind:       networkPolicyGVK.Kind,
+		},
+		ObjectMeta: buildObjectMeta(dk, dk.Egress().GetName(), namespace),
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: buildPodSelector(dk, namespace),
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      rules,
+		},
+	}
+}
+
+// buildIPBlockRules allows the IP hosts, one rule per port.
+func buildIPBlockRules(ipHosts []dtclient.CommunicationHost) []networkingv1.NetworkPolicyEgressRule {
+	ports, cidrs := groupByPort(ipHosts, func(host dtclient.CommunicationHost) string {
+		return toCIDR(host.Host)
+	})
+
+	rules := make([]networkingv1.NetworkPolicyEgressRule, 0, len(ports))
+
+	for _, port := range ports {
+		peers := make([]networkingv1.NetworkPolicyPeer, 0, len(cidrs[port]))
+		for _, cidr := range cidrs[port] {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+			})
+		}
+
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			To:    peers,
+			Ports: []networkingv1.NetworkPolicyPort{buildNetworkPolicyPort(corev1.ProtocolTCP, port)},
+		})
+	}
+
+	return rules
+}
+
+func buildNetworkPolicyPort(protocol corev1.Protocol, port uint32) networkingv1.NetworkPolicyPort {
+	portNumber := intstr.FromInt32(int32(port)) //nolint:gosec
+
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &portNumber,
+	}
+}
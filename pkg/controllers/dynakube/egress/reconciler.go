@@ -0,0 +1,219 @@
+package egress
+
+import (
+	"context"
+	goerrors "errors"
+	"net"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/egress"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type reconciler struct {
+	client     client.Client
+	apiReader  client.Reader
+	dk         *dynakube.DynaKube
+	lookupHost lookupHostFunc
+}
+
+type ReconcilerBuilder func(client client.Client, apiReader client.Reader, dk *dynakube.DynaKube) controllers.Reconciler
+
+func NewReconciler(client client.Client, apiReader client.Reader, dk *dynakube.DynaKube) controllers.Reconciler { //nolint
+	return &reconciler{
+		client:     client,
+		apiReader:  apiReader,
+		dk:         dk,
+		lookupHost: net.DefaultResolver.LookupHost,
+	}
+}
+
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+func (r *reconciler) Reconcile(ctx context.Context) error {
+	if !r.dk.Egress().IsEnabled() {
+		if meta.FindStatusCondition(*r.dk.Conditions(), ConditionType) == nil {
+			return nil
+		}
+
+		log.Info("egress disabled, cleaning up")
+
+		if err := r.deleteStaleObjects(ctx, nil); err != nil {
+			return err
+		}
+
+		meta.RemoveStatusCondition(r.dk.Conditions(), ConditionType)
+
+		return nil
+	}
+
+	backend := r.dk.Egress().GetBackend()
+
+	hostCount, err := r.reconcileBackend(ctx, backend)
+	if err != nil {
+		setFailedCondition(r.dk.Conditions(), backend, err)
+
+		return err
+	}
+
+	if backend == egress.NetworkPolicyBackend {
+		setResolvedCondition(r.dk.Conditions(), backend, hostCount)
+	} else {
+		setConfiguredCondition(r.dk.Conditions(), backend, hostCount)
+	}
+
+	return nil
+}
+
+func (r *reconciler) reconcileBackend(ctx context.Context, backendName egress.Backend) (int, error) {
+	backend, err := r.getBackend(backendName)
+	if err != nil {
+		return 0, err
+	}
+
+	hosts, err := getCommunicationHosts(ctx, r.apiReader, r.dk)
+	if err != nil {
+		return 0, err
+	}
+
+	objects, err := backend.build(ctx, r.dk, hosts)
+	if err != nil {
+		return 0, err
+	}
+
+	desired := map[objectKey]bool{}
+
+	for _, object := range objects {
+		desired[getObjectKey(object)] = true
+
+		if err := r.createOrUpdate(ctx, object); err != nil {
+			return 0, err
+		}
+	}
+
+	// objects of a previously used backend, or of hosts that are gone, are left over
+	if err := r.deleteStaleObjects(ctx, desired); err != nil {
+		return 0, err
+	}
+
+	log.Info("reconciled egress objects", "backend", backendName, "objects", len(objects))
+
+	return len(hosts.all), nil
+}
+
+func (r *reconciler) getBackend(backendName egress.Backend) (backend, error) {
+	switch backendName {
+	case egress.NetworkPolicyBackend:
+		return networkPolicyBackend{apiReader: r.apiReader, lookupHost: r.lookupHost}, nil
+	case egress.CiliumBackend:
+		return ciliumBackend{}, nil
+	case egress.LinkerdBackend:
+		return linkerdBackend{}, nil
+	case egress.GatewayAPIBackend:
+		return gatewayAPIBackend{}, nil
+	default:
+		return nil, errors.Errorf("unknown egress backend '%s'", backendName)
+	}
+}
+
+func (r *reconciler) createOrUpdate(ctx context.Context, object client.Object) error {
+	if object.GetNamespace() == r.dk.Namespace {
+		if err := controllerutil.SetControllerReference(r.dk, object, r.client.Scheme()); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if err := hasher.AddAnnotation(object); err != nil {
+		return errors.WithStack(err)
+	}
+
+	key := getObjectKey(object)
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(key.gvk)
+
+	err := r.apiReader.Get(ctx, client.ObjectKeyFromObject(object), current)
+
+	switch {
+	case meta.IsNoMatchError(err):
+		return errors.Errorf("%s is not available, make sure its CRD is installed in the cluster", key.gvk.Kind)
+	case k8serrors.IsNotFound(err):
+		log.Info("creating egress object", "kind", key.gvk.Kind, "namespace", key.namespace, "name", key.name)
+
+		return errors.WithStack(r.client.Create(ctx, object))
+	case err != nil:
+		return errors.WithStack(err)
+	case !hasher.IsAnnotationDifferent(current, object):
+		return nil
+	}
+
+	log.Info("updating egress object", "kind", key.gvk.Kind, "namespace", key.namespace, "name", key.name)
+
+	object.SetResourceVersion(current.GetResourceVersion())
+
+	return errors.WithStack(r.client.Update(ctx, object))
+}
+
+// deleteStaleObjects deletes all objects created for the DynaKube by any backend, that are not desired anymore.
+func (r *reconciler) deleteStaleObjects(ctx context.Context, desired map[objectKey]bool) error {
+	matchLabels := labels.NewCoreLabels(r.dk.Name, ComponentLabel).BuildMatchLabels()
+
+	var errs []error
+
+	for _, gvk := range managedKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		listOptions := []client.ListOption{client.MatchingLabels(matchLabels)}
+		if gvk == serviceGVK {
+			// services are only created next to the DynaKube, which the operator's role already covers
+			listOptions = append(listOptions, client.InNamespace(r.dk.Namespace))
+		}
+
+		err := r.apiReader.List(ctx, list, listOptions...)
+		if meta.IsNoMatchError(err) {
+			// the CRD is not installed, so there can't be any leftovers
+			continue
+		} else if err != nil {
+			errs = append(errs, errors.WithStack(err))
+
+			continue
+		}
+
+		for _, item := range list.Items {
+			if desired[getObjectKey(&item)] {
+				continue
+			}
+
+			log.Info("deleting stale egress object", "kind", gvk.Kind, "namespace", item.GetNamespace(), "name", item.GetName())
+
+			err := r.client.Delete(ctx, &item)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				errs = append(errs, errors.WithStack(err))
+			}
+		}
+	}
+
+	// try to clean up all objects even if one fails
+	return goerrors.Join(errs...)
+}
+
+func getObjectKey(object client.Object) objectKey {
+	return objectKey{
+		gvk:       object.GetObjectKind().GroupVersionKind(),
+		namespace: object.GetNamespace(),
+		name:      object.GetName(),
+	}
+}
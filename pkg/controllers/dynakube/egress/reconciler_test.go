@@ -0,0 +1,417 @@
+package egress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/communication"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/egress"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/Dynatrace/dynatrace-operator/pkg/version"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testNamespace          = "dynatrace"
+	testMonitoredNamespace = "monitored"
+	testDynakubeName       = "dynakube"
+
+	testAPIHost        = "tenant.dev.dynatracelabs.com"
+	testOneAgentHost   = "tenant.live.dynatrace.com"
+	testOneAgentIP     = "10.0.0.1"
+	testActiveGateHost = "ag.dynatrace.com"
+	testAPIServerIP    = "172.18.0.2"
+)
+
+var testResolvedHosts = map[string][]string{
+	testAPIHost:        {"1.1.1.1"},
+	testOneAgentHost:   {"2.2.2.2", "2001:db8::2"},
+	testActiveGateHost: {"3.3.3.3"},
+}
+
+func TestReconcileNetworkPolicy(t *testing.T) {
+	ctx := context.Background()
+	dk := createTestDynakube(egress.NetworkPolicyBackend)
+	clt := createTestClient(dk)
+
+	err := newTestReconciler(clt, dk).Reconcile(ctx)
+	require.NoError(t, err)
+	assertCondition(t, dk, metav1.ConditionTrue)
+
+	policy := &networkingv1.NetworkPolicy{}
+	require.NoError(t, clt.Get(ctx, client.ObjectKey{Name: "dynakube-egress", Namespace: testNamespace}, policy))
+
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+	assert.Equal(t, []metav1.LabelSelectorRequirement{{
+		Key:      labels.AppNameLabel,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{version.AppName, labels.OneAgentComponentLabel, labels.ActiveGateComponentLabel, labels.ExtensionComponentLabel, labels.OtelCComponentLabel},
+	}}, policy.Spec.PodSelector.MatchExpressions)
+	require.Len(t, policy.Spec.Egress, 5)
+	assert.Equal(t, 443, policy.Spec.Egress[0].Ports[0].Port.IntValue())
+	assert.Equal(t, []string{"1.1.1.1/32", "2.2.2.2/32", "2001:db8::2/128", "3.3.3.3/32"}, getIPBlocks(policy.Spec.Egress[0]))
+	assert.Equal(t, 9999, policy.Spec.Egress[1].Ports[0].Port.IntValue())
+	assert.Equal(t, []string{"10.0.0.1/32"}, getIPBlocks(policy.Spec.Egress[1]))
+	assertClusterRules(t, policy.Spec.Egress[2:])
+	require.Len(t, policy.OwnerReferences, 1)
+
+	// code modules only need to reach the OneAgent communication hosts
+	policy = &networkingv1.NetworkPolicy{}
+	require.NoError(t, clt.Get(ctx, client.ObjectKey{Name: "dynakube-egress", Namespace: testMonitoredNamespace}, policy))
+
+	require.Len(t, policy.Spec.Egress, 5)
+	assert.Equal(t, []string{"2.2.2.2/32", "2001:db8::2/128"}, getIPBlocks(policy.Spec.Egress[0]))
+	assert.Equal(t, []string{"10.0.0.1/32"}, getIPBlocks(policy.Spec.Egress[1]))
+	assertClusterRules(t, policy.Spec.Egress[2:])
+	assert.Empty(t, policy.OwnerReferences)
+	assert.Equal(t, map[string]string{dtwebhook.InjectedPodLabel: testDynakubeName}, policy.Spec.PodSelector.MatchLabels)
+
+	t.Run("resolved hosts are refreshed", func(t *testing.T) {
+		condition := meta.FindStatusCondition(dk.Status.Conditions, ConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, condition.LastTransitionTime.Add(resolvedHostsRefreshInterval), NextUpdate(dk))
+
+		condition.LastTransitionTime = metav1.NewTime(condition.LastTransitionTime.Add(-time.Hour))
+
+		require.NoError(t, newTestReconciler(clt, dk).Reconcile(ctx))
+
+		condition = meta.FindStatusCondition(dk.Status.Conditions, ConditionType)
+		require.NotNil(t, condition)
+		assert.True(t, NextUpdate(dk).After(time.Now()))
+	})
+
+	t.Run("injected pods not selected => no policies in the monitored namespaces", func(t *testing.T) {
+		dk := createTestDynakube(egress.NetworkPolicyBackend)
+		dk.Spec.Egress.InjectedPods = false
+		clt := createTestClient(dk)
+
+		require.NoError(t, newTestReconciler(clt, dk).Reconcile(ctx))
+
+		getUnstructured(t, clt, networkPolicyGVK, testNamespace, "dynakube-egress")
+		assertNotFound(t, clt, networkPolicyGVK, testMonitoredNamespace, "dynakube-egress")
+	})
+
+	t.Run("lookup failure", func(t *testing.T) {
+		r := newTestReconciler(clt, dk)
+		r.lookupHost = func(_ context.Context, _ string) ([]string, error) {
+			return nil, errors.New("no such host")
+		}
+
+		err := r.Reconcile(ctx)
+		require.Error(t, err)
+		assertCondition(t, dk, metav1.ConditionFalse)
+	})
+}
+
+func TestReconcileCilium(t *testing.T) {
+	ctx := context.Background()
+	dk := createTestDynakube(egress.CiliumBackend)
+	clt := createTestClient(dk)
+
+	err := newTestReconciler(clt, dk).Reconcile(ctx)
+	require.NoError(t, err)
+	assertCondition(t, dk, metav1.ConditionTrue)
+
+	policy := getUnstructured(t, clt, ciliumNetworkPolicyGVK, testNamespace, "dynakube-egress")
+	rules, _, _ := unstructured.NestedSlice(policy.Object, "spec", "egress")
+	require.Len(t, rules, 4)
+
+	fqdnRule := rules[0].(map[string]any)
+	assert.Equal(t, []any{
+		map[string]any{"matchName": testActiveGateHost},
+		map[string]any{"matchName": testAPIHost},
+		map[string]any{"matchName": testOneAgentHost},
+	}, fqdnRule["toFQDNs"])
+	assert.Equal(t, tcpPorts(443), fqdnRule["toPorts"])
+
+	cidrRule := rules[1].(map[string]any)
+	assert.Equal(t, []any{"10.0.0.1/32"}, cidrRule["toCIDR"])
+	assert.Equal(t, tcpPorts(9999), cidrRule["toPorts"])
+
+	assert.Equal(t, []any{
+		map[string]any{
+			"matchLabels": map[string]any{"k8s:k8s-app": "kube-dns"},
+			"matchExpressions": []any{
+				map[string]any{"key": "k8s:io.kubernetes.pod.namespace", "operator": "Exists"},
+			},
+		},
+	}, rules[2].(map[string]any)["toEndpoints"])
+	assert.Equal(t, []any{"kube-apiserver", "cluster"}, rules[3].(map[string]any)["toEntities"])
+
+	selector, _, _ := unstructured.NestedMap(policy.Object, "spec", "endpointSelector")
+	assert.Equal(t, map[string]any{
+		"matchExpressions": []any{
+			map[string]any{
+				"key":      labels.AppNameLabel,
+				"operator": string(metav1.LabelSelectorOpIn),
+				"values":   toAnySlice(dynatracePodNames),
+			},
+		},
+	}, selector)
+	assert.True(t, NextUpdate(dk).IsZero())
+
+	policy = getUnstructured(t, clt, ciliumNetworkPolicyGVK, testMonitoredNamespace, "dynakube-egress")
+	rules, _, _ = unstructured.NestedSlice(policy.Object, "spec", "egress")
+	require.Len(t, rules, 4)
+	assert.Equal(t, []any{map[string]any{"matchName": testOneAgentHost}}, rules[0].(map[string]any)["toFQDNs"])
+
+	selector, _, _ = unstructured.NestedMap(policy.Object, "spec", "endpointSelector")
+	assert.Equal(t, map[string]any{
+		"matchLabels": map[string]any{dtwebhook.InjectedPodLabel: testDynakubeName},
+	}, selector)
+}
+
+func TestReconcileLinkerd(t *testing.T) {
+	ctx := context.Background()
+	dk := createTestDynakube(egress.LinkerdBackend)
+	dk.Spec.Egress.ParentRef = &egress.ParentRef{Name: "all-egress", Namespace: "linkerd-egress"}
+	clt := createTestClient(dk)
+
+	err := newTestReconciler(clt, dk).Reconcile(ctx)
+	require.NoError(t, err)
+	assertCondition(t, dk, metav1.ConditionTrue)
+
+	route := getUnstructured(t, clt, tlsRouteGVK, "linkerd-egress", "dynakube-egress-443")
+	hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	assert.Equal(t, []string{testActiveGateHost, testAPIHost, testOneAgentHost}, hostnames)
+
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	assert.Equal(t, []any{map[string]any{
+		"group":     linkerdAPIGroup,
+		"kind":      "EgressNetwork",
+		"name":      "all-egress",
+		"namespace": "linkerd-egress",
+		"port":      int64(443),
+	}}, parentRefs)
+	assert.Empty(t, route.GetOwnerReferences())
+
+	// the IP host can't be routed by hostname
+	assertNotFound(t, clt, tlsRouteGVK, "linkerd-egress", "dynakube-egress-9999")
+}
+
+func TestReconcileGatewayAPI(t *testing.T) {
+	ctx := context.Background()
+	dk := createTestDynakube(egress.GatewayAPIBackend)
+	dk.Spec.Egress.ParentRef = &egress.ParentRef{Name: "egress-gateway"}
+	clt := createTestClient(dk)
+
+	err := newTestReconciler(clt, dk).Reconcile(ctx)
+	require.NoError(t, err)
+	assertCondition(t, dk, metav1.ConditionTrue)
+
+	for _, host := range []string{testAPIHost, testOneAgentHost, testActiveGateHost} {
+		name := "dynakube-egress-" + hashHost(createHost(host, 443))
+
+		service := &corev1.Service{}
+		require.NoError(t, clt.Get(ctx, client.ObjectKey{Name: name, Namespace: testNamespace}, service))
+		assert.Equal(t, corev1.ServiceTypeExternalName, service.Spec.Type)
+		assert.Equal(t, host, service.Spec.ExternalName)
+
+		route := getUnstructured(t, clt, tlsRouteGVK, testNamespace, name)
+		hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+		assert.Equal(t, []string{host}, hostnames)
+
+		parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		assert.Equal(t, testNamespace, parentRefs[0].(map[string]any)["namespace"])
+		assert.Equal(t, "Gateway", parentRefs[0].(map[string]any)["kind"])
+	}
+}
+
+func TestReconcileCleanup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("backend change removes the objects of the previous backend", func(t *testing.T) {
+		dk := createTestDynakube(egress.CiliumBackend)
+		clt := createTestClient(dk)
+
+		require.NoError(t, newTestReconciler(clt, dk).Reconcile(ctx))
+		getUnstructured(t, clt, ciliumNetworkPolicyGVK, testNamespace, "dynakube-egress")
+
+		dk.Spec.Egress.Backend = egress.NetworkPolicyBackend
+		require.NoError(t, newTestReconciler(clt, dk).Reconcile(ctx))
+
+		assertNotFound(t, clt, ciliumNetworkPolicyGVK, testNamespace, "dynakube-egress")
+		assertNotFound(t, clt, ciliumNetworkPolicyGVK, testMonitoredNamespace, "dynakube-egress")
+		getUnstructured(t, clt, networkPolicyGVK, testNamespace, "dynakube-egress")
+	})
+
+	t.Run("removed host removes its objects", func(t *testing.T) {
+		dk := createTestDynakube(egress.GatewayAPIBackend)
+		dk.Spec.Egress.ParentRef = &egress.ParentRef{Name: "egress-gateway"}
+		clt := createTestClient(dk)
+
+		require.NoError(t, newTestReconciler(clt, dk).Reconcile(ctx))
+
+		dk.Spec.ActiveGate = activegate.Spec{}
+		require.NoError(t, newTestReconciler(clt, dk).Reconcile(ctx))
+
+		name := "dynakube-egress-" + hashHost(createHost(testActiveGateHost, 443))
+		assertNotFound(t, clt, tlsRouteGVK, testNamespace, name)
+		assertNotFound(t, clt, serviceGVK, testNamespace, name)
+		getUnstructured(t, clt, tlsRouteGVK, testNamespace, "dynakube-egress-"+hashHost(createHost(testAPIHost, 443)))
+	})
+
+	t.Run("disabled egress removes everything", func(t *testing.T) {
+		dk := createTestDynakube(egress.NetworkPolicyBackend)
+		clt := createTestClient(dk)
+
+		require.NoError(t, newTestReconciler(clt, dk).Reconcile(ctx))
+
+		dk.Spec.Egress = nil
+		require.NoError(t, newTestReconciler(clt, dk).Reconcile(ctx))
+
+		assertNotFound(t, clt, networkPolicyGVK, testNamespace, "dynakube-egress")
+		assertNotFound(t, clt, networkPolicyGVK, testMonitoredNamespace, "dynakube-egress")
+		assert.Nil(t, meta.FindStatusCondition(dk.Status.Conditions, ConditionType))
+	})
+
+	t.Run("never enabled => nothing to do", func(t *testing.T) {
+		dk := createTestDynakube(egress.NetworkPolicyBackend)
+		dk.Spec.Egress = nil
+
+		require.NoError(t, newTestReconciler(nil, dk).Reconcile(ctx))
+	})
+}
+
+func newTestReconciler(clt client.Client, dk *dynakube.DynaKube) *reconciler {
+	r := NewReconciler(clt, clt, dk).(*reconciler)
+	r.lookupHost = func(_ context.Context, host string) ([]string, error) {
+		return testResolvedHosts[host], nil
+	}
+
+	return r
+}
+
+func createTestClient(dk *dynakube.DynaKube) client.Client {
+	return fake.NewClient(
+		dk,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   testMonitoredNamespace,
+			Labels: map[string]string{dtwebhook.InjectionInstanceLabel: dk.Name},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "not-monitored",
+		}},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      apiServerServiceName,
+				Namespace: apiServerServiceNamespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: apiServerServiceName},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{testAPIServerIP}}},
+			Ports:       []discoveryv1.EndpointPort{{Port: ptr.To[int32](6443)}},
+		},
+	)
+}
+
+func createTestDynakube(backend egress.Backend) *dynakube.DynaKube {
+	return &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testDynakubeName,
+			Namespace: testNamespace,
+		},
+		Spec: dynakube.DynaKubeSpec{
+			APIURL: "https://" + testAPIHost + "/api",
+			Egress: &egress.Spec{
+				Backend:      backend,
+				InjectedPods: true,
+			},
+			ActiveGate: activegate.Spec{
+				Capabilities: []activegate.CapabilityDisplayName{
+					activegate.RoutingCapability.DisplayName,
+				},
+			},
+			OneAgent: oneagent.Spec{
+				CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{},
+			},
+		},
+		Status: dynakube.DynaKubeStatus{
+			OneAgent: oneagent.Status{
+				ConnectionInfoStatus: oneagent.ConnectionInfoStatus{
+					CommunicationHosts: []oneagent.CommunicationHostStatus{
+						{Protocol: "https", Host: testOneAgentHost, Port: 443},
+						{Protocol: "https", Host: testOneAgentIP, Port: 9999},
+					},
+				},
+			},
+			ActiveGate: activegate.Status{
+				ConnectionInfo: communication.ConnectionInfo{
+					Endpoints: "https://" + testActiveGateHost + ":443/communication",
+				},
+			},
+		},
+	}
+}
+
+func createHost(host string, port uint32) dtclient.CommunicationHost {
+	return dtclient.CommunicationHost{Protocol: "https", Host: host, Port: port}
+}
+
+func getIPBlocks(rule networkingv1.NetworkPolicyEgressRule) []string {
+	cidrs := make([]string, 0, len(rule.To))
+	for _, peer := range rule.To {
+		cidrs = append(cidrs, peer.IPBlock.CIDR)
+	}
+
+	return cidrs
+}
+
+// assertClusterRules checks the rules that keep DNS, the pods of the cluster and the kube-apiserver reachable.
+func assertClusterRules(t *testing.T, rules []networkingv1.NetworkPolicyEgressRule) {
+	require.Len(t, rules, 3)
+
+	assert.Empty(t, rules[0].To)
+	require.Len(t, rules[0].Ports, 2)
+	assert.Equal(t, corev1.ProtocolUDP, *rules[0].Ports[0].Protocol)
+	assert.Equal(t, dnsPort, rules[0].Ports[0].Port.IntValue())
+	assert.Equal(t, corev1.ProtocolTCP, *rules[0].Ports[1].Protocol)
+
+	assert.Equal(t, []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}, rules[1].To)
+	assert.Empty(t, rules[1].Ports)
+
+	assert.Equal(t, []string{testAPIServerIP + "/32"}, getIPBlocks(rules[2]))
+	assert.Equal(t, 6443, rules[2].Ports[0].Port.IntValue())
+}
+
+func getUnstructured(t *testing.T, clt client.Client, gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+
+	require.NoError(t, clt.Get(context.Background(), client.ObjectKey{Name: name, Namespace: namespace}, object))
+
+	return object
+}
+
+func assertNotFound(t *testing.T, clt client.Client, gvk schema.GroupVersionKind, namespace, name string) {
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+
+	err := clt.Get(context.Background(), client.ObjectKey{Name: name, Namespace: namespace}, object)
+	assert.True(t, k8serrors.IsNotFound(err), "%s %s/%s should not exist", gvk.Kind, namespace, name)
+}
+
+func assertCondition(t *testing.T, dk *dynakube.DynaKube, status metav1.ConditionStatus) {
+	condition := meta.FindStatusCondition(dk.Status.Conditions, ConditionType)
+	require.NotNil(t, condition)
+	assert.Equal(t, status, condition.Status)
+}
@@ -0,0 +1,145 @@
+package egress
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// linkerdBackend renders a TLSRoute per port, attached to a Linkerd EgressNetwork.
+// The routes are created in the namespace of the EgressNetwork, so they apply to the same clients.
+type linkerdBackend struct{}
+
+func (linkerdBackend) build(_ context.Context, dk *dynakube.DynaKube, hosts communicationHosts) ([]client.Object, error) {
+	egress := dk.Egress()
+	parentRef := map[string]any{
+		"group":     linkerdAPIGroup,
+		"kind":      egress.GetParentKind(),
+		"name":      egress.GetParentName(),
+		"namespace": egress.GetParentNamespace(),
+	}
+
+	ports, hostnames := groupByPort(getTLSHosts(hosts.all), func(host dtclient.CommunicationHost) string {
+		return host.Host
+	})
+
+	objects := make([]client.Object, 0, len(ports))
+
+	for _, port := range ports {
+		portParentRef := withPort(parentRef, port)
+		objectMeta := buildObjectMeta(dk, fmt.Sprintf("%s-%d", egress.GetName(), port), egress.GetParentNamespace())
+
+		objects = append(objects, newUnstructured(tlsRouteGVK, objectMeta, map[string]any{
+			"hostnames":  toAnySlice(hostnames[port]),
+			"parentRefs": []any{portParentRef},
+			"rules": []any{
+				map[string]any{
+					"backendRefs": []any{portParentRef},
+				},
+			},
+		}))
+	}
+
+	return objects, nil
+}
+
+// gatewayAPIBackend renders a TLSRoute per FQDN host, attached to an egress Gateway,
+// and routes it to an ExternalName Service of the host.
+type gatewayAPIBackend struct{}
+
+func (gatewayAPIBackend) build(_ context.Context, dk *dynakube.DynaKube, hosts communicationHosts) ([]client.Object, error) {
+	egress := dk.Egress()
+	parentRef := map[string]any{
+		"group":     gatewayAPIGroup,
+		"kind":      egress.GetParentKind(),
+		"name":      egress.GetParentName(),
+		"namespace": egress.GetParentNamespace(),
+	}
+
+	tlsHosts := getTLSHosts(hosts.all)
+	objects := make([]client.Object, 0, 2*len(tlsHosts)) //nolint:mnd
+
+	for _, host := range tlsHosts {
+		objectMeta := buildObjectMeta(dk, fmt.Sprintf("%s-%s", egress.GetName(), hashHost(host)), dk.Namespace)
+
+		service := &corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: serviceGVK.GroupVersion().String(),
+				Kind:       serviceGVK.Kind,
+			},
+			ObjectMeta: objectMeta,
+			Spec: corev1.ServiceSpec{
+				Type:         corev1.ServiceTypeExternalName,
+				ExternalName: host.Host,
+				Ports: []corev1.ServicePort{
+					{
+						Name:     "tls",
+						Protocol: corev1.ProtocolTCP,
+						Port:     int32(host.Port), //nolint:gosec
+					},
+				},
+			},
+		}
+
+		route := newUnstructured(tlsRouteGVK, objectMeta, map[string]any{
+			"hostnames":  []any{host.Host},
+			"parentRefs": []any{parentRef},
+			"rules": []any{
+				map[string]any{
+					"backendRefs": []any{
+						map[string]any{
+							"name": service.Name,
+							"port": int64(host.Port),
+						},
+					},
+				},
+			},
+		})
+
+		objects = append(objects, service, route)
+	}
+
+	return objects, nil
+}
+
+// getTLSHosts returns the https FQDN hosts, TLSRoutes match on the SNI hostname, so IP and plain http hosts can't be routed.
+func getTLSHosts(hosts []dtclient.CommunicationHost) []dtclient.CommunicationHost {
+	ipHosts, fqdnHosts := splitCommunicationHosts(hosts)
+	if len(ipHosts) > 0 {
+		log.Info("communication hosts with an IP address can't be routed by hostname, skipping them", "count", len(ipHosts))
+	}
+
+	tlsHosts := make([]dtclient.CommunicationHost, 0, len(fqdnHosts))
+
+	for _, host := range fqdnHosts {
+		if host.Protocol == "https" {
+			tlsHosts = append(tlsHosts, host)
+		} else {
+			log.Info("communication host doesn't use https, skipping it", "host", host.Host, "protocol", host.Protocol)
+		}
+	}
+
+	return tlsHosts
+}
+
+func withPort(ref map[string]any, port uint32) map[string]any {
+	result := map[string]any{"port": int64(port)}
+	for key, value := range ref {
+		result[key] = value
+	}
+
+	return result
+}
+
+func hashHost(host dtclient.CommunicationHost) string {
+	hash := fnv.New32a()
+	_, _ = fmt.Fprintf(hash, "%s:%d", host.Host, host.Port)
+
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
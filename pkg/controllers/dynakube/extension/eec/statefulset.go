@@ -6,9 +6,11 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
 	eecConsts "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/extension/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/signature"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
@@ -111,6 +113,12 @@ func (r *reconciler) createOrUpdateStatefulset(ctx context.Context) error {
 		return err
 	}
 
+	if err := signature.VerifyPodSpecForDynaKube(ctx, r.apiReader, r.dk, &desiredSts.Spec.Template.Spec); err != nil {
+		conditions.SetImageVerificationFailed(r.dk.Conditions(), extensionsControllerStatefulSetConditionType, err)
+
+		return err
+	}
+
 	if err := hasher.AddAnnotation(desiredSts); err != nil {
 		conditions.SetKubeApiError(r.dk.Conditions(), extensionsControllerStatefulSetConditionType, err)
 
@@ -174,10 +182,17 @@ func setImagePullSecrets(imagePullSecrets []corev1.LocalObjectReference) func(o
 	}
 }
 
+// Image returns the image of the extension execution controller StatefulSet, as it is deployed for the DynaKube.
+func Image(dk *dynakube.DynaKube) string {
+	imageRef := dk.Spec.Templates.ExtensionExecutionController.ImageRef
+
+	return dk.RewriteImage(releaselock.PinOr(dk.ReleaseLock().ExtensionExecutionController, imageRef.Repository+":"+imageRef.Tag))
+}
+
 func buildContainer(dk *dynakube.DynaKube) corev1.Container {
 	return corev1.Container{
 		Name:            containerName,
-		Image:           dk.Spec.Templates.ExtensionExecutionController.ImageRef.Repository + ":" + dk.Spec.Templates.ExtensionExecutionController.ImageRef.Tag,
+		Image:           Image(dk),
 		ImagePullPolicy: corev1.PullAlways,
 		ReadinessProbe: &corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
@@ -3,15 +3,19 @@ package tls
 import (
 	"context"
 	"crypto/x509"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates/issuer"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	k8slabels "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
 	k8ssecret "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/secret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -22,6 +26,9 @@ import (
 
 const (
 	extensionsSelfSignedTLSCommonNameSuffix = "extensions-controller"
+
+	issuedCertValidity    = 90 * 24 * time.Hour
+	issuedCertRenewBefore = 30 * 24 * time.Hour
 )
 
 type reconciler struct {
@@ -43,6 +50,17 @@ func NewReconciler(clt client.Client, apiReader client.Reader, dk *dynakube.Dyna
 
 func (r *reconciler) Reconcile(ctx context.Context) error {
 	if r.dk.IsExtensionsEnabled() && r.dk.ExtensionsNeedsSelfSignedTLS() {
+		certIssuer, err := issuer.New(r.client, r.apiReader, env.DefaultNamespace())
+		if err != nil {
+			conditions.SetSecretGenFailed(r.dk.Conditions(), conditionType, err)
+
+			return err
+		}
+
+		if certIssuer != nil {
+			return r.reconcileIssuedTLSSecret(ctx, certIssuer)
+		}
+
 		return r.reconcileSelfSignedTLSSecret(ctx)
 	}
 
@@ -57,7 +75,7 @@ func (r *reconciler) Reconcile(ctx context.Context) error {
 func (r *reconciler) reconcileSelfSignedTLSSecret(ctx context.Context) error {
 	query := k8ssecret.Query(r.client, r.client, log)
 
-	_, err := query.Get(ctx, types.NamespacedName{
+	secret, err := query.Get(ctx, types.NamespacedName{
 		Name:      r.dk.ExtensionsSelfSignedTLSSecretName(),
 		Namespace: r.dk.Namespace,
 	})
@@ -72,6 +90,65 @@ func (r *reconciler) reconcileSelfSignedTLSSecret(ctx context.Context) error {
 		return err
 	}
 
+	if len(secret.Data[issuer.CACrtDataName]) > 0 {
+		log.Info("replacing the certificate of the previous certificate source with a self-signed one", "secret", secret.Name)
+
+		err = query.Delete(ctx, secret)
+		if err != nil {
+			conditions.SetKubeApiError(r.dk.Conditions(), conditionType, err)
+
+			return err
+		}
+
+		return r.createSelfSignedTLSSecret(ctx)
+	}
+
+	return nil
+}
+
+// reconcileIssuedTLSSecret keeps the TLS secret up to date with the certificate of the configured certificate source.
+func (r *reconciler) reconcileIssuedTLSSecret(ctx context.Context, certIssuer issuer.Issuer) error {
+	secretData, err := issuer.IssueSecretData(ctx, certIssuer, r.apiReader, issuer.Request{
+		Owner:       r.dk,
+		Namespace:   r.dk.Namespace,
+		SecretName:  r.dk.ExtensionsSelfSignedTLSSecretName(),
+		CommonName:  certificates.CommonName(r.dk.Name, r.dk.Namespace, extensionsSelfSignedTLSCommonNameSuffix),
+		DNSNames:    certificates.AltNames(r.dk.Name, r.dk.Namespace, extensionsSelfSignedTLSCommonNameSuffix),
+		Duration:    issuedCertValidity,
+		RenewBefore: issuedCertRenewBefore,
+	})
+	if errors.Is(err, issuer.ErrNotIssued) {
+		log.Info("waiting for the TLS certificate to be issued", "secret", r.dk.ExtensionsSelfSignedTLSSecretName())
+
+		return nil
+	} else if err != nil {
+		conditions.SetSecretGenFailed(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	coreLabels := k8slabels.NewCoreLabels(r.dk.Name, k8slabels.ExtensionComponentLabel)
+
+	secret, err := k8ssecret.Build(r.dk, r.dk.ExtensionsSelfSignedTLSSecretName(), secretData, k8ssecret.SetLabels(coreLabels.BuildLabels()))
+	if err != nil {
+		conditions.SetSecretGenFailed(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	secret.Type = corev1.SecretTypeTLS
+
+	query := k8ssecret.Query(r.client, r.apiReader, log)
+
+	_, err = query.CreateOrUpdate(ctx, secret)
+	if err != nil {
+		conditions.SetKubeApiError(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
+	conditions.SetSecretCreatedOrUpdated(r.dk.Conditions(), conditionType, secret.Name)
+
 	return nil
 }
 
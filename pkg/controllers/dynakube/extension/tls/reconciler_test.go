@@ -11,7 +11,9 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/certificates/issuer"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -140,6 +142,41 @@ func TestReconcile(t *testing.T) {
 		assert.Equal(t, corev1.Secret{}, secret)
 		assert.Empty(t, dk.Conditions())
 	})
+	t.Run("tls secret of the configured certificate source is generated", func(t *testing.T) {
+		installconfig.SetCertificateSourceOverride(t, installconfig.CertificateSource{
+			Type:   installconfig.CertManagerCertificateSource,
+			Issuer: &installconfig.IssuerRef{Name: "corporate"},
+		})
+
+		dk := getTestDynakube()
+		dk.Spec.Templates.ExtensionExecutionController.TlsRefName = ""
+
+		issuedData := map[string][]byte{
+			consts.TLSCrtDataName: []byte("cert"),
+			consts.TLSKeyDataName: []byte("key"),
+			issuer.CACrtDataName:  []byte("ca"),
+		}
+		fakeClient := fake.NewClient(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: dk.ExtensionsSelfSignedTLSSecretName() + issuer.IssuedSecretSuffix, Namespace: testNamespaceName},
+			Data:       issuedData,
+		})
+
+		reconciler := NewReconciler(fakeClient, fakeClient, dk)
+
+		err := reconciler.Reconcile(context.Background())
+		require.NoError(t, err)
+
+		var secret corev1.Secret
+
+		key := client.ObjectKey{Name: dk.ExtensionsSelfSignedTLSSecretName(), Namespace: testNamespaceName}
+		err = fakeClient.Get(context.Background(), key, &secret)
+
+		require.NoError(t, err)
+		assert.Equal(t, issuedData, secret.Data)
+		assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+		require.NotEmpty(t, dk.Conditions())
+		assert.Equal(t, conditions.SecretCreatedOrUpdatedReason, (*dk.Conditions())[0].Reason)
+	})
 }
 
 func TestGetTLSSecretName(t *testing.T) {
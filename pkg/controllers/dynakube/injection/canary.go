@@ -0,0 +1,87 @@
+package injection
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/version"
+	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// evaluateCodeModulesCanary checks the health of the pods, that were injected with the candidate in the canary namespaces,
+// so a verified candidate can be promoted by the version reconciler.
+func (r *reconciler) evaluateCodeModulesCanary(ctx context.Context) error {
+	rollout := r.dk.Status.CodeModules.Rollout
+	if !r.dk.OneAgent().IsCodeModulesRolloutEnabled() || rollout == nil || rollout.Phase != status.RolloutCanaryPhase {
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(r.dk.OneAgent().Spec.Rollout.NamespaceSelector)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	namespaceList := &corev1.NamespaceList{}
+
+	err = r.apiReader.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var canaryPods []corev1.Pod
+
+	for _, namespace := range namespaceList.Items {
+		podList := &corev1.PodList{}
+
+		err = r.apiReader.List(ctx, podList, client.InNamespace(namespace.Name))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		for _, pod := range podList.Items {
+			if isCanaryPod(pod, rollout) {
+				canaryPods = append(canaryPods, pod)
+			}
+		}
+	}
+
+	health := version.CanaryHealth{
+		Pods:  canaryPods,
+		Ready: len(canaryPods) > 0 && allPodsReady(canaryPods),
+	}
+
+	version.EvaluateCanary(rollout, r.dk.OneAgent(), health, r.timeProvider.Now().Time)
+
+	return nil
+}
+
+// isCanaryPod returns true for pods, that were injected after the rollout started, so they run the candidate.
+func isCanaryPod(pod corev1.Pod, rollout *status.RolloutStatus) bool {
+	if pod.Annotations[oacommon.AnnotationInjected] != "true" {
+		return false
+	}
+
+	return rollout.StartedAt == nil || !pod.CreationTimestamp.Before(rollout.StartedAt)
+}
+
+func allPodsReady(pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		ready := false
+
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady {
+				ready = condition.Status == corev1.ConditionTrue
+			}
+		}
+
+		if !ready {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,98 @@
+package injection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestEvaluateCodeModulesCanary(t *testing.T) {
+	ctx := context.Background()
+	startedAt := time.Now().Add(-time.Hour)
+
+	createDynakube := func() *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: "dynatrace"},
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent: oneagent.Spec{
+					ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{},
+					Rollout: &oneagent.RolloutSpec{
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+						SoakTime:          &metav1.Duration{Duration: time.Minute},
+						Timeout:           &metav1.Duration{Duration: 2 * time.Hour},
+					},
+				},
+			},
+		}
+		dk.Status.CodeModules.Rollout = &status.RolloutStatus{Phase: status.RolloutCanaryPhase, StartedAt: ptr.To(metav1.NewTime(startedAt))}
+
+		return dk
+	}
+
+	createPod := func(name, namespace string, createdAt time.Time, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				CreationTimestamp: metav1.NewTime(createdAt),
+				Annotations:       map[string]string{oacommon.AnnotationInjected: "true"},
+			},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+		}
+	}
+
+	canaryNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "canary", Labels: map[string]string{"canary": "true"}}}
+	otherNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+
+	t.Run("ready pods in the canary namespaces verify the rollout", func(t *testing.T) {
+		dk := createDynakube()
+		clt := fake.NewClient(canaryNamespace, otherNamespace,
+			createPod("new", canaryNamespace.Name, startedAt.Add(time.Minute), corev1.ConditionTrue),
+			createPod("old", canaryNamespace.Name, startedAt.Add(-time.Minute), corev1.ConditionFalse),
+			createPod("other", otherNamespace.Name, startedAt.Add(time.Minute), corev1.ConditionFalse),
+		)
+		r := reconciler{client: clt, apiReader: clt, dk: dk, timeProvider: timeprovider.New()}
+
+		err := r.evaluateCodeModulesCanary(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, status.RolloutVerifiedPhase, dk.Status.CodeModules.Rollout.Phase)
+	})
+
+	t.Run("no injected pods in the canary namespaces keep the rollout waiting", func(t *testing.T) {
+		dk := createDynakube()
+		clt := fake.NewClient(canaryNamespace, otherNamespace,
+			createPod("other", otherNamespace.Name, startedAt.Add(time.Minute), corev1.ConditionTrue),
+		)
+		r := reconciler{client: clt, apiReader: clt, dk: dk, timeProvider: timeprovider.New()}
+
+		err := r.evaluateCodeModulesCanary(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, status.RolloutCanaryPhase, dk.Status.CodeModules.Rollout.Phase)
+	})
+
+	t.Run("no injected pods in the canary namespaces until the timeout fail the rollout", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Spec.OneAgent.Rollout.Timeout = &metav1.Duration{Duration: 30 * time.Minute}
+		clt := fake.NewClient(canaryNamespace, otherNamespace)
+		r := reconciler{client: clt, apiReader: clt, dk: dk, timeProvider: timeprovider.New()}
+
+		err := r.evaluateCodeModulesCanary(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, status.RolloutPausedPhase, dk.Status.CodeModules.Rollout.Phase)
+	})
+}
@@ -1,6 +1,9 @@
 package injection
 
 import (
+	"fmt"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -9,8 +12,14 @@ const (
 	metaDataEnrichmentConditionType   = "MetadataEnrichment"
 	codeModulesInjectionConditionType = "CodeModulesInjection"
 
+	// codeModulesVerificationConditionType aggregates the verification reports of the csi-provisioners of all nodes
+	codeModulesVerificationConditionType = "CodeModulesVerification"
+
 	secretsCreatedReason  = "SecretsCreated"
 	secretsCreatedMessage = "Namespaces mapped and secrets created"
+
+	codeModulesVerifiedReason           = "Verified"
+	codeModulesVerificationFailedReason = "VerificationFailed"
 )
 
 func setMetadataEnrichmentCreatedCondition(conditions *[]metav1.Condition) {
@@ -32,3 +41,23 @@ func setCodeModulesInjectionCreatedCondition(conditions *[]metav1.Condition) {
 	}
 	_ = meta.SetStatusCondition(conditions, condition)
 }
+
+func setCodeModulesVerifiedCondition(conditions *[]metav1.Condition, versions []string, nodeCount int) {
+	condition := metav1.Condition{
+		Type:    codeModulesVerificationConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  codeModulesVerifiedReason,
+		Message: fmt.Sprintf("CodeModules version %s verified on %d nodes", strings.Join(versions, ", "), nodeCount),
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
+
+func setCodeModulesVerificationFailedCondition(conditions *[]metav1.Condition, failures []string) {
+	condition := metav1.Condition{
+		Type:    codeModulesVerificationConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  codeModulesVerificationFailedReason,
+		Message: strings.Join(failures, "; "),
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
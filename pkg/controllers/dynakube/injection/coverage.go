@@ -0,0 +1,182 @@
+package injection
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
+	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	coverageUpdateInterval = 5 * time.Minute
+
+	podPhaseField = "status.phase"
+
+	// coveragePageSize limits the pods listed per request, only their metadata is read
+	coveragePageSize = 500
+
+	oneAgentInjectionName = "oneagent"
+	metadataInjectionName = "metadata-enrichment"
+
+	// DisabledByAnnotationReason is used for pods the webhook did not annotate, because the injection was disabled by their annotations.
+	DisabledByAnnotationReason = "DisabledByAnnotation"
+)
+
+type injectionAnnotations struct {
+	name     string
+	inject   string
+	injected string
+	reason   string
+}
+
+// reconcileInjectionCoverage summarizes the annotations the webhook left on the pods of the monitored namespaces,
+// so it is visible in the status of the DynaKube why pods were not injected.
+func (r *reconciler) reconcileInjectionCoverage(ctx context.Context) {
+	injections := r.enabledInjections()
+	if len(injections) == 0 {
+		r.dk.Status.InjectionCoverage = nil
+
+		return
+	}
+
+	if r.dk.Status.InjectionCoverage != nil && !r.timeProvider.IsOutdated(r.dk.Status.InjectionCoverage.LastUpdated, coverageUpdateInterval) {
+		return
+	}
+
+	pods, err := r.listMonitoredPods(ctx)
+	if err != nil {
+		log.Error(err, "failed to summarize the injection coverage")
+
+		return
+	}
+
+	coverage := summarizeInjectionCoverage(pods, injections)
+	coverage.LastUpdated = r.timeProvider.Now()
+
+	r.dk.Status.InjectionCoverage = coverage
+}
+
+func (r *reconciler) enabledInjections() []injectionAnnotations {
+	var injections []injectionAnnotations
+
+	if r.dk.OneAgent().IsAppInjectionNeeded() {
+		injections = append(injections, injectionAnnotations{
+			name:     oneAgentInjectionName,
+			inject:   oacommon.AnnotationInject,
+			injected: oacommon.AnnotationInjected,
+			reason:   oacommon.AnnotationReason,
+		})
+	}
+
+	if r.dk.MetadataEnrichmentEnabled() {
+		injections = append(injections, injectionAnnotations{
+			name:     metadataInjectionName,
+			inject:   metacommon.AnnotationInject,
+			injected: metacommon.AnnotationInjected,
+			reason:   metacommon.AnnotationReason,
+		})
+	}
+
+	return injections
+}
+
+// listMonitoredPods reads the metadata of the running pods of the monitored namespaces page by page, it is all that is needed for the annotations.
+func (r *reconciler) listMonitoredPods(ctx context.Context) ([]metav1.PartialObjectMetadata, error) {
+	namespaces, err := mapper.GetNamespacesForDynakube(ctx, r.apiReader, r.dk.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []metav1.PartialObjectMetadata
+
+	for _, namespace := range namespaces {
+		continueToken := ""
+
+		for {
+			podList := &metav1.PartialObjectMetadataList{}
+			podList.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+
+			err = r.apiReader.List(ctx, podList,
+				client.InNamespace(namespace.Name),
+				client.MatchingFields{podPhaseField: string(corev1.PodRunning)},
+				client.Limit(coveragePageSize),
+				client.Continue(continueToken),
+			)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+
+			pods = append(pods, podList.Items...)
+
+			continueToken = podList.Continue
+			if continueToken == "" {
+				break
+			}
+		}
+	}
+
+	return pods, nil
+}
+
+func summarizeInjectionCoverage(pods []metav1.PartialObjectMetadata, injections []injectionAnnotations) *dynakube.InjectionCoverageStatus {
+	coverage := &dynakube.InjectionCoverageStatus{
+		Pods: int32(len(pods)), //nolint:gosec
+	}
+
+	for _, injection := range injections {
+		injectionCoverage := dynakube.InjectionCoverage{Name: injection.name}
+		reasons := map[string]int32{}
+
+		for _, pod := range pods {
+			injected, ok := pod.Annotations[injection.injected]
+
+			switch {
+			case ok && injected == "true":
+				injectionCoverage.Injected++
+			case ok:
+				injectionCoverage.NotInjected++
+				reasons[pod.Annotations[injection.reason]]++
+			case pod.Annotations[dtwebhook.AnnotationDynatraceInject] == "false" || pod.Annotations[injection.inject] == "false":
+				injectionCoverage.NotInjected++
+				reasons[DisabledByAnnotationReason]++
+			default:
+				injectionCoverage.Unknown++
+			}
+		}
+
+		for reason, count := range reasons {
+			injectionCoverage.Reasons = append(injectionCoverage.Reasons, dynakube.InjectionReasonCount{Reason: reason, Pods: count})
+		}
+
+		slices.SortFunc(injectionCoverage.Reasons, func(a, b dynakube.InjectionReasonCount) int {
+			if a.Pods != b.Pods {
+				return int(b.Pods - a.Pods)
+			}
+
+			return strings.Compare(a.Reason, b.Reason)
+		})
+
+		coverage.Injections = append(coverage.Injections, injectionCoverage)
+	}
+
+	return coverage
+}
+
+// NextCoverageUpdate returns when the injection coverage of the DynaKube is summarized again, the zero time if it is not summarized.
+func NextCoverageUpdate(dk *dynakube.DynaKube) time.Time {
+	if dk.Status.InjectionCoverage == nil || dk.Status.InjectionCoverage.LastUpdated == nil {
+		return time.Time{}
+	}
+
+	return dk.Status.InjectionCoverage.LastUpdated.Add(coverageUpdateInterval)
+}
@@ -0,0 +1,173 @@
+package injection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestSummarizeInjectionCoverage(t *testing.T) {
+	injections := []injectionAnnotations{{
+		name:     oneAgentInjectionName,
+		inject:   oacommon.AnnotationInject,
+		injected: oacommon.AnnotationInjected,
+		reason:   oacommon.AnnotationReason,
+	}}
+
+	pods := []metav1.PartialObjectMetadata{
+		createCoveragePod("injected", map[string]string{oacommon.AnnotationInjected: "true"}),
+		createCoveragePod("no-space-1", map[string]string{oacommon.AnnotationInjected: "false", oacommon.AnnotationReason: "NoSpace"}),
+		createCoveragePod("no-space-2", map[string]string{oacommon.AnnotationInjected: "false", oacommon.AnnotationReason: "NoSpace"}),
+		createCoveragePod("bad-config", map[string]string{oacommon.AnnotationInjected: "false", oacommon.AnnotationReason: "BadConfig"}),
+		createCoveragePod("disabled", map[string]string{dtwebhook.AnnotationDynatraceInject: "false"}),
+		createCoveragePod("unknown", nil),
+	}
+
+	coverage := summarizeInjectionCoverage(pods, injections)
+
+	assert.Equal(t, int32(6), coverage.Pods)
+	require.Len(t, coverage.Injections, 1)
+
+	oneAgentCoverage := coverage.Injections[0]
+	assert.Equal(t, oneAgentInjectionName, oneAgentCoverage.Name)
+	assert.Equal(t, int32(1), oneAgentCoverage.Injected)
+	assert.Equal(t, int32(4), oneAgentCoverage.NotInjected)
+	assert.Equal(t, int32(1), oneAgentCoverage.Unknown)
+	assert.Equal(t, []dynakube.InjectionReasonCount{
+		{Reason: "NoSpace", Pods: 2},
+		{Reason: "BadConfig", Pods: 1},
+		{Reason: DisabledByAnnotationReason, Pods: 1},
+	}, oneAgentCoverage.Reasons)
+}
+
+func TestReconcileInjectionCoverage(t *testing.T) {
+	ctx := context.Background()
+
+	createDynakube := func() *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: "dynatrace"},
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent: oneagent.Spec{ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{}},
+			},
+		}
+	}
+
+	monitoredNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "monitored",
+		Labels: map[string]string{dtwebhook.InjectionInstanceLabel: "dynakube"},
+	}}
+	otherNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+
+	createPod := func(name, namespace string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Annotations: map[string]string{oacommon.AnnotationInjected: "true"},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	t.Run("only running pods of monitored namespaces are counted", func(t *testing.T) {
+		dk := createDynakube()
+		clt := newCoverageClient(monitoredNamespace, otherNamespace,
+			createPod("running", monitoredNamespace.Name, corev1.PodRunning),
+			createPod("completed", monitoredNamespace.Name, corev1.PodSucceeded),
+			createPod("pending", monitoredNamespace.Name, corev1.PodPending),
+			createPod("other", otherNamespace.Name, corev1.PodRunning),
+		)
+		r := reconciler{client: clt, apiReader: clt, dk: dk, timeProvider: timeprovider.New()}
+
+		r.reconcileInjectionCoverage(ctx)
+
+		require.NotNil(t, dk.Status.InjectionCoverage)
+		assert.NotNil(t, dk.Status.InjectionCoverage.LastUpdated)
+		assert.Equal(t, int32(1), dk.Status.InjectionCoverage.Pods)
+		require.Len(t, dk.Status.InjectionCoverage.Injections, 1)
+		assert.Equal(t, int32(1), dk.Status.InjectionCoverage.Injections[0].Injected)
+		assert.Equal(t, dk.Status.InjectionCoverage.LastUpdated.Add(coverageUpdateInterval), NextCoverageUpdate(dk))
+	})
+
+	t.Run("recent coverage is not summarized again", func(t *testing.T) {
+		dk := createDynakube()
+		lastUpdated := metav1.NewTime(time.Now().Add(-time.Minute))
+		dk.Status.InjectionCoverage = &dynakube.InjectionCoverageStatus{LastUpdated: &lastUpdated}
+		clt := fake.NewClient(monitoredNamespace, createPod("running", monitoredNamespace.Name, corev1.PodRunning))
+		r := reconciler{client: clt, apiReader: clt, dk: dk, timeProvider: timeprovider.New()}
+
+		r.reconcileInjectionCoverage(ctx)
+
+		assert.Equal(t, int32(0), dk.Status.InjectionCoverage.Pods)
+		assert.Equal(t, lastUpdated, *dk.Status.InjectionCoverage.LastUpdated)
+	})
+
+	t.Run("coverage is removed if no injection is enabled", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Spec.OneAgent = oneagent.Spec{}
+		dk.Status.InjectionCoverage = &dynakube.InjectionCoverageStatus{}
+		clt := fake.NewClient()
+		r := reconciler{client: clt, apiReader: clt, dk: dk, timeProvider: timeprovider.New()}
+
+		r.reconcileInjectionCoverage(ctx)
+
+		assert.Nil(t, dk.Status.InjectionCoverage)
+		assert.True(t, NextCoverageUpdate(dk).IsZero())
+	})
+}
+
+func createCoveragePod(name string, annotations map[string]string) metav1.PartialObjectMetadata {
+	return metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "monitored",
+			Annotations: annotations,
+		},
+	}
+}
+
+// newCoverageClient returns a fake client, that selects the pods by their phase and strips them down to their metadata like the API server does.
+func newCoverageClient(objs ...client.Object) client.Client {
+	clt := clientfake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(objs...).
+		WithIndex(&corev1.Pod{}, podPhaseField, func(obj client.Object) []string {
+			return []string{string(obj.(*corev1.Pod).Status.Phase)}
+		}).
+		Build()
+
+	return interceptor.NewClient(clt, interceptor.Funcs{
+		List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			metadataList, ok := list.(*metav1.PartialObjectMetadataList)
+			if !ok {
+				return clt.List(ctx, list, opts...)
+			}
+
+			podList := &corev1.PodList{}
+			if err := clt.List(ctx, podList, opts...); err != nil {
+				return err
+			}
+
+			for _, pod := range podList.Items {
+				metadataList.Items = append(metadataList.Items, metav1.PartialObjectMetadata{ObjectMeta: pod.ObjectMeta})
+			}
+
+			return nil
+		},
+	})
+}
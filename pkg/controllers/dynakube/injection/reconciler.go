@@ -8,7 +8,6 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
-	oaconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/istio"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/metadata/rules"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/monitoredentities"
@@ -31,10 +30,10 @@ type reconciler struct {
 	istioReconciler             istio.Reconciler
 	versionReconciler           version.Reconciler
 	pmcSecretreconciler         controllers.Reconciler
-	connectionInfoReconciler    controllers.Reconciler
 	monitoredEntitiesReconciler controllers.Reconciler
 	enrichmentRulesReconciler   controllers.Reconciler
 	dynatraceClient             dynatrace.Client
+	timeProvider                *timeprovider.Provider
 }
 
 type ReconcilerBuilder func(
@@ -68,9 +67,9 @@ func NewReconciler(
 		versionReconciler: version.NewReconciler(apiReader, dynatraceClient, timeprovider.New().Freeze()),
 		pmcSecretreconciler: processmoduleconfigsecret.NewReconciler(
 			client, apiReader, dynatraceClient, dk, timeprovider.New().Freeze()),
-		connectionInfoReconciler:    oaconnectioninfo.NewReconciler(client, apiReader, dynatraceClient, dk),
 		enrichmentRulesReconciler:   rules.NewReconciler(dynatraceClient, dk),
 		monitoredEntitiesReconciler: monitoredentities.NewReconciler(dynatraceClient, dk),
+		timeProvider:                timeprovider.New(),
 	}
 }
 
@@ -102,6 +101,9 @@ func (r *reconciler) Reconcile(ctx context.Context) error {
 		return goerrors.Join(setupErrors...)
 	}
 
+	r.reconcileInjectionCoverage(ctx)
+	r.reconcileCodeModulesVerification(ctx)
+
 	log.Info("app injection reconciled")
 
 	return nil
@@ -125,12 +127,12 @@ func (r *reconciler) unmapDynakube(ctx context.Context) {
 }
 
 func (r *reconciler) setupOneAgentInjection(ctx context.Context) error {
-	err := r.versionReconciler.ReconcileCodeModules(ctx, r.dk)
+	err := r.evaluateCodeModulesCanary(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = r.connectionInfoReconciler.Reconcile(ctx)
+	err = r.versionReconciler.ReconcileCodeModules(ctx, r.dk)
 	if err != nil {
 		return err
 	}
@@ -13,12 +13,14 @@ import (
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
+	oaconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/istio"
 	versions "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/version"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/bootstrapperconfig"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/startup"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	dtclientmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/clients/dynatrace"
 	controllermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/controllers"
@@ -127,6 +129,9 @@ func TestReconciler(t *testing.T) {
 
 		istioClient := newIstioTestingClient(fakeistio.NewSimpleClientset(), dk)
 
+		// the connection info is a dependency of the injection, which the controller reconciles before
+		require.NoError(t, oaconnectioninfo.NewReconciler(clt, clt, dtClient, dk).Reconcile(context.Background()))
+
 		rec := NewReconciler(clt, clt, dtClient, istioClient, dk)
 		fakeReconciler := createGenericReconcilerMock(t)
 		rec.(*reconciler).monitoredEntitiesReconciler = fakeReconciler
@@ -250,7 +255,6 @@ func TestReconciler(t *testing.T) {
 		fakeVersionReconciler := createVersionReconcilerMock(t)
 
 		rec := NewReconciler(boomClient, boomClient, nil, istioClient, dk).(*reconciler)
-		rec.connectionInfoReconciler = fakeReconciler
 		rec.pmcSecretreconciler = fakeReconciler
 		rec.versionReconciler = fakeVersionReconciler
 		rec.monitoredEntitiesReconciler = fakeReconciler
@@ -270,7 +274,6 @@ func TestRemoveAppInjection(t *testing.T) {
 		CloudNativeFullStack: nil,
 	})
 	rec.versionReconciler = createVersionReconcilerMock(t)
-	rec.connectionInfoReconciler = createGenericReconcilerMock(t)
 	rec.pmcSecretreconciler = createGenericReconcilerMock(t)
 	rec.enrichmentRulesReconciler = createGenericReconcilerMock(t)
 	rec.monitoredEntitiesReconciler = createGenericReconcilerMock(t)
@@ -304,7 +307,6 @@ func TestSetupOneAgentInjection(t *testing.T) {
 			ClassicFullStack: &oneagent.HostInjectSpec{},
 		})
 		rec.versionReconciler = createVersionReconcilerMock(t)
-		rec.connectionInfoReconciler = createGenericReconcilerMock(t)
 		rec.pmcSecretreconciler = createGenericReconcilerMock(t)
 		rec.monitoredEntitiesReconciler = createGenericReconcilerMock(t)
 
@@ -321,7 +323,6 @@ func TestSetupOneAgentInjection(t *testing.T) {
 			HostMonitoring: &oneagent.HostInjectSpec{},
 		})
 		rec.versionReconciler = createVersionReconcilerMock(t)
-		rec.connectionInfoReconciler = createGenericReconcilerMock(t)
 		rec.pmcSecretreconciler = createGenericReconcilerMock(t)
 		rec.monitoredEntitiesReconciler = createGenericReconcilerMock(t)
 
@@ -338,7 +339,6 @@ func TestSetupOneAgentInjection(t *testing.T) {
 			ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{},
 		})
 		rec.versionReconciler = createVersionReconcilerMock(t)
-		rec.connectionInfoReconciler = createGenericReconcilerMock(t)
 		rec.pmcSecretreconciler = createGenericReconcilerMock(t)
 		rec.monitoredEntitiesReconciler = createGenericReconcilerMock(t)
 
@@ -364,7 +364,6 @@ func TestSetupOneAgentInjection(t *testing.T) {
 			CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{},
 		})
 		rec.versionReconciler = createVersionReconcilerMock(t)
-		rec.connectionInfoReconciler = createGenericReconcilerMock(t)
 		rec.pmcSecretreconciler = createGenericReconcilerMock(t)
 		rec.monitoredEntitiesReconciler = createGenericReconcilerMock(t)
 
@@ -400,7 +399,6 @@ func TestSetupOneAgentInjection(t *testing.T) {
 		rec.dk.Annotations = make(map[string]string)
 		rec.dk.Annotations[exp.OANodeImagePullKey] = "true"
 		rec.versionReconciler = createVersionReconcilerMock(t)
-		rec.connectionInfoReconciler = createGenericReconcilerMock(t)
 		rec.pmcSecretreconciler = createGenericReconcilerMock(t)
 		rec.monitoredEntitiesReconciler = createGenericReconcilerMock(t)
 
@@ -627,8 +625,9 @@ func newIstioTestingClient(fakeClient *fakeistio.Clientset, dk *dynakube.DynaKub
 
 func createReconciler(clt client.Client, dynakubeName string, dynakubeNamespace string, oneAgentSpec oneagent.Spec) reconciler {
 	return reconciler{
-		client:    clt,
-		apiReader: clt,
+		client:       clt,
+		apiReader:    clt,
+		timeProvider: timeprovider.New(),
 		dk: &dynakube.DynaKube{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      dynakubeName,
@@ -785,11 +784,11 @@ func assertSecretNotFound(t *testing.T, clt client.Client, secretName string, se
 }
 
 func createGenericReconcilerMock(t *testing.T) controllers.Reconciler {
-	connectionInfoReconciler := controllermock.NewReconciler(t)
-	connectionInfoReconciler.On("Reconcile",
+	reconciler := controllermock.NewReconciler(t)
+	reconciler.On("Reconcile",
 		mock.AnythingOfType("context.backgroundCtx")).Return(nil).Maybe()
 
-	return connectionInfoReconciler
+	return reconciler
 }
 
 func createVersionReconcilerMock(t *testing.T) versions.Reconciler {
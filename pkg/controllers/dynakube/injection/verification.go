@@ -0,0 +1,117 @@
+package injection
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/integrity"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileCodeModulesVerification aggregates the verification reports of the csi-provisioners in the CodeModulesVerification condition.
+// A single failing node fails the condition. Reports of nodes that are gone, or of a DynaKube that doesn't verify anymore, are deleted.
+func (r *reconciler) reconcileCodeModulesVerification(ctx context.Context) {
+	if r.dk.OneAgent().GetCodeModulesVerification() == nil && meta.FindStatusCondition(*r.dk.Conditions(), codeModulesVerificationConditionType) == nil {
+		// the csi-provisioners delete their reports, once the verification is turned off
+		return
+	}
+
+	reports, err := r.listVerificationReports(ctx)
+	if err != nil {
+		log.Error(err, "failed to aggregate the CodeModules verification")
+
+		return
+	}
+
+	if r.dk.OneAgent().GetCodeModulesVerification() == nil {
+		for _, report := range reports {
+			r.deleteVerificationReport(ctx, report)
+		}
+
+		meta.RemoveStatusCondition(r.dk.Conditions(), codeModulesVerificationConditionType)
+
+		return
+	}
+
+	var verifiedVersions, failures []string
+
+	nodeCount := 0
+
+	for _, report := range reports {
+		nodeName := report.Data[integrity.ReportNodeKey]
+
+		exists, err := r.nodeExists(ctx, nodeName)
+		if err != nil {
+			log.Error(err, "failed to aggregate the CodeModules verification")
+
+			return
+		} else if !exists {
+			r.deleteVerificationReport(ctx, report)
+
+			continue
+		}
+
+		nodeCount++
+
+		if verified, _ := strconv.ParseBool(report.Data[integrity.ReportVerifiedKey]); verified {
+			verifiedVersions = append(verifiedVersions, report.Data[integrity.ReportVersionKey])
+		} else {
+			failures = append(failures, fmt.Sprintf("node %s: %s", nodeName, report.Data[integrity.ReportMessageKey]))
+		}
+	}
+
+	switch {
+	case len(failures) > 0:
+		setCodeModulesVerificationFailedCondition(r.dk.Conditions(), failures)
+	case len(verifiedVersions) > 0:
+		slices.Sort(verifiedVersions)
+		setCodeModulesVerifiedCondition(r.dk.Conditions(), slices.Compact(verifiedVersions), nodeCount)
+	default:
+		meta.RemoveStatusCondition(r.dk.Conditions(), codeModulesVerificationConditionType)
+	}
+}
+
+func (r *reconciler) listVerificationReports(ctx context.Context) ([]corev1.ConfigMap, error) {
+	var reports corev1.ConfigMapList
+
+	err := r.apiReader.List(ctx, &reports,
+		client.InNamespace(r.dk.Namespace),
+		client.MatchingLabels(labels.NewCoreLabels(r.dk.Name, integrity.ReportComponentLabel).BuildMatchLabels()),
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list the CodeModules verification reports")
+	}
+
+	slices.SortFunc(reports.Items, func(a, b corev1.ConfigMap) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return reports.Items, nil
+}
+
+func (r *reconciler) nodeExists(ctx context.Context, nodeName string) (bool, error) {
+	err := r.apiReader.Get(ctx, client.ObjectKey{Name: nodeName}, &corev1.Node{})
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return true, nil
+}
+
+func (r *reconciler) deleteVerificationReport(ctx context.Context, report corev1.ConfigMap) {
+	log.Info("deleting CodeModules verification report", "name", report.Name)
+
+	if err := r.client.Delete(ctx, &report); err != nil && !k8serrors.IsNotFound(err) {
+		log.Error(err, "failed to delete CodeModules verification report", "name", report.Name)
+	}
+}
@@ -0,0 +1,121 @@
+package injection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/integrity"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileCodeModulesVerification(t *testing.T) {
+	ctx := context.Background()
+
+	createDynakube := func(verification *oneagent.CodeModulesVerificationSpec) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: "dynatrace"},
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent: oneagent.Spec{ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{
+					AppInjectionSpec: oneagent.AppInjectionSpec{CodeModulesVerification: verification},
+				}},
+			},
+		}
+	}
+
+	createReport := func(t *testing.T, dk *dynakube.DynaKube, nodeName string, verificationErr error) *corev1.ConfigMap {
+		report, err := integrity.BuildReport(dk, nodeName, "1.2.3", verificationErr)
+		require.NoError(t, err)
+
+		return report
+	}
+
+	createNode := func(name string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	reconcile := func(dk *dynakube.DynaKube, objects ...client.Object) client.Client {
+		clt := fake.NewClient(append(objects, dk)...)
+		r := &reconciler{client: clt, apiReader: clt, dk: dk}
+		r.reconcileCodeModulesVerification(ctx)
+
+		return clt
+	}
+
+	verification := &oneagent.CodeModulesVerificationSpec{ConfigMap: "digests"}
+
+	t.Run("all nodes verified", func(t *testing.T) {
+		dk := createDynakube(verification)
+
+		reconcile(dk, createNode("node-1"), createNode("node-2"), createReport(t, dk, "node-1", nil), createReport(t, dk, "node-2", nil))
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, codeModulesVerificationConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, "CodeModules version 1.2.3 verified on 2 nodes", condition.Message)
+	})
+
+	t.Run("a failing node is not hidden by the others", func(t *testing.T) {
+		dk := createDynakube(verification)
+
+		reconcile(dk,
+			createNode("node-1"), createNode("node-2"),
+			createReport(t, dk, "node-1", nil),
+			createReport(t, dk, "node-2", errors.WithMessage(integrity.ErrVerificationFailed, "sha256 digest mismatch")),
+		)
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, codeModulesVerificationConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, codeModulesVerificationFailedReason, condition.Reason)
+		assert.Equal(t, "node node-2: sha256 digest mismatch: "+integrity.ErrVerificationFailed.Error(), condition.Message)
+	})
+
+	t.Run("report of a removed node is deleted", func(t *testing.T) {
+		dk := createDynakube(verification)
+		staleReport := createReport(t, dk, "node-2", errors.WithMessage(integrity.ErrVerificationFailed, "sha256 digest mismatch"))
+
+		clt := reconcile(dk, createNode("node-1"), createReport(t, dk, "node-1", nil), staleReport)
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, codeModulesVerificationConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+
+		err := clt.Get(ctx, client.ObjectKeyFromObject(staleReport), &corev1.ConfigMap{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("verification turned off => reports and condition removed", func(t *testing.T) {
+		dk := createDynakube(nil)
+		report := createReport(t, dk, "node-1", nil)
+		meta.SetStatusCondition(dk.Conditions(), metav1.Condition{
+			Type:   codeModulesVerificationConditionType,
+			Status: metav1.ConditionTrue,
+			Reason: codeModulesVerifiedReason,
+		})
+
+		clt := reconcile(dk, createNode("node-1"), report)
+
+		assert.Nil(t, meta.FindStatusCondition(dk.Status.Conditions, codeModulesVerificationConditionType))
+
+		err := clt.Get(ctx, client.ObjectKeyFromObject(report), &corev1.ConfigMap{})
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("no reports yet => no condition", func(t *testing.T) {
+		dk := createDynakube(verification)
+
+		reconcile(dk, createNode("node-1"))
+
+		assert.Nil(t, meta.FindStatusCondition(dk.Status.Conditions, codeModulesVerificationConditionType))
+	})
+}
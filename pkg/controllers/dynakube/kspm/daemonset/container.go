@@ -2,6 +2,7 @@ package daemonset
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/resources"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
@@ -15,12 +16,17 @@ const (
 	runAs         int64 = 65532
 )
 
+// Image returns the image of the node configuration collector DaemonSet, as it is deployed for the DynaKube.
+func Image(dk *dynakube.DynaKube) string {
+	return dk.RewriteImage(releaselock.PinOr(dk.ReleaseLock().KSPM, dk.KSPM().ImageRef.StringWithDefaults(defaultImageRepo, defaultImageTag)))
+}
+
 func getContainer(dk dynakube.DynaKube, tenantUUID string) corev1.Container {
 	securityContext := getSecurityContext()
 
 	container := corev1.Container{
 		Name:            containerName,
-		Image:           dk.KSPM().ImageRef.StringWithDefaults(defaultImageRepo, defaultImageTag),
+		Image:           Image(&dk),
 		ImagePullPolicy: corev1.PullAlways,
 		VolumeMounts:    getMounts(dk),
 		Env:             getEnvs(dk, tenantUUID),
@@ -5,6 +5,7 @@ import (
 	"maps"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/signature"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/daemonset"
@@ -55,7 +56,7 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		return nil // clean-up shouldn't cause a failure
 	}
 
-	ds, err := r.generateDaemonSet()
+	ds, err := r.generateDaemonSet(ctx)
 	if err != nil {
 		return err
 	}
@@ -75,7 +76,7 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 	return nil
 }
 
-func (r *Reconciler) generateDaemonSet() (*appsv1.DaemonSet, error) {
+func (r *Reconciler) generateDaemonSet(ctx context.Context) (*appsv1.DaemonSet, error) {
 	tenantUUID, err := r.dk.TenantUUID()
 	if err != nil {
 		return nil, err
@@ -104,6 +105,13 @@ func (r *Reconciler) generateDaemonSet() (*appsv1.DaemonSet, error) {
 		return nil, err
 	}
 
+	// the images are pinned to the verified digests before hashing, so a changed digest rolls out the daemonset
+	if err := signature.VerifyPodSpecForDynaKube(ctx, r.apiReader, r.dk, &ds.Spec.Template.Spec); err != nil {
+		conditions.SetImageVerificationFailed(r.dk.Conditions(), conditionType, err)
+
+		return nil, err
+	}
+
 	err = hasher.AddAnnotation(ds)
 	if err != nil {
 		return nil, err
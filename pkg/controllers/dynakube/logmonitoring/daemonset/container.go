@@ -2,6 +2,7 @@ package daemonset
 
 import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/ptr"
 )
@@ -27,13 +28,18 @@ var (
 	}
 )
 
+// Image returns the image of the LogMonitoring DaemonSet, as it is deployed for the DynaKube.
+func Image(dk *dynakube.DynaKube) string {
+	return dk.RewriteImage(releaselock.PinOr(dk.ReleaseLock().LogMonitoring, dk.LogMonitoring().Template().ImageRef.StringWithDefaults(defaultImageRepo, defaultImageTag)))
+}
+
 func getContainer(dk dynakube.DynaKube, tenantUUID string) corev1.Container {
 	securityContext := getBaseSecurityContext(dk)
 	securityContext.Capabilities.Add = neededCapabilities
 
 	container := corev1.Container{
 		Name:            containerName,
-		Image:           dk.LogMonitoring().Template().ImageRef.StringWithDefaults(defaultImageRepo, defaultImageTag),
+		Image:           Image(&dk),
 		ImagePullPolicy: corev1.PullAlways,
 		VolumeMounts:    getVolumeMounts(tenantUUID),
 		Env:             getEnvs(),
@@ -49,7 +55,7 @@ func getInitContainer(dk dynakube.DynaKube, tenantUUID string) corev1.Container
 
 	container := corev1.Container{
 		Name:            initContainerName,
-		Image:           dk.LogMonitoring().Template().ImageRef.StringWithDefaults(defaultImageRepo, defaultImageTag),
+		Image:           Image(&dk),
 		ImagePullPolicy: corev1.PullAlways,
 		VolumeMounts:    []corev1.VolumeMount{getDTVolumeMounts(tenantUUID)},
 		Command:         []string{bootstrapCommand},
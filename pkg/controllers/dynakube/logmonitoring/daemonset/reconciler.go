@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/signature"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/daemonset"
@@ -63,7 +64,7 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		return KubernetesSettingsNotAvailableError
 	}
 
-	ds, err := r.generateDaemonSet()
+	ds, err := r.generateDaemonSet(ctx)
 	if err != nil {
 		return err
 	}
@@ -83,7 +84,7 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 	return nil
 }
 
-func (r *Reconciler) generateDaemonSet() (*appsv1.DaemonSet, error) {
+func (r *Reconciler) generateDaemonSet(ctx context.Context) (*appsv1.DaemonSet, error) {
 	tenantUUID, err := r.dk.TenantUUID()
 	if err != nil {
 		return nil, err
@@ -115,6 +116,13 @@ func (r *Reconciler) generateDaemonSet() (*appsv1.DaemonSet, error) {
 		return nil, err
 	}
 
+	// the images are pinned to the verified digests before hashing, so a changed digest rolls out the daemonset
+	if err := signature.VerifyPodSpecForDynaKube(ctx, r.apiReader, r.dk, &ds.Spec.Template.Spec); err != nil {
+		conditions.SetImageVerificationFailed(r.dk.Conditions(), ConditionType, err)
+
+		return nil, err
+	}
+
 	err = hasher.AddAnnotation(ds)
 	if err != nil {
 		return nil, err
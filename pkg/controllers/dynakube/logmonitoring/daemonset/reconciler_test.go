@@ -148,7 +148,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk := createDynakube(true)
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.generateDaemonSet(t.Context())
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -185,7 +185,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		}
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.generateDaemonSet(t.Context())
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -205,7 +205,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.Status.OneAgent.ConnectionInfoStatus.TenantTokenHash = testTokenHash
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.generateDaemonSet(t.Context())
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -222,7 +222,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		}
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.generateDaemonSet(t.Context())
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -238,7 +238,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		}
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.generateDaemonSet(t.Context())
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -252,7 +252,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 		dk.Spec.CustomPullSecret = customPullSecret
 
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.generateDaemonSet(t.Context())
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -273,7 +273,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 			Tolerations: customTolerations,
 		}
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.generateDaemonSet(t.Context())
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -289,7 +289,7 @@ func TestGenerateDaemonSet(t *testing.T) {
 			NodeSelector: customNodeSelector,
 		}
 		reconciler := NewReconciler(nil, fake.NewClient(), dk)
-		daemonset, err := reconciler.generateDaemonSet()
+		daemonset, err := reconciler.generateDaemonSet(t.Context())
 		require.NoError(t, err)
 		require.NotNil(t, daemonset)
 
@@ -6,7 +6,6 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
-	oaconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring/configsecret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring/daemonset"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring/logmonsettings"
@@ -20,11 +19,10 @@ type Reconciler struct {
 	dk        *dynakube.DynaKube
 	dtc       dtclient.Client
 
-	configSecretReconciler           controllers.Reconciler
-	daemonsetReconciler              controllers.Reconciler
-	oneAgentConnectionInfoReconciler controllers.Reconciler
-	monitoredEntitiesReconciler      controllers.Reconciler
-	logmonsettingsReconciler         controllers.Reconciler
+	configSecretReconciler      controllers.Reconciler
+	daemonsetReconciler         controllers.Reconciler
+	monitoredEntitiesReconciler controllers.Reconciler
+	logmonsettingsReconciler    controllers.Reconciler
 }
 
 type ReconcilerBuilder func(clt client.Client, apiReader client.Reader, dtc dtclient.Client, dk *dynakube.DynaKube) controllers.Reconciler
@@ -39,11 +37,10 @@ func NewReconciler(clt client.Client,
 		dk:        dk,
 		dtc:       dtc,
 
-		configSecretReconciler:           configsecret.NewReconciler(clt, apiReader, dk),
-		daemonsetReconciler:              daemonset.NewReconciler(clt, apiReader, dk),
-		oneAgentConnectionInfoReconciler: oaconnectioninfo.NewReconciler(clt, apiReader, dtc, dk),
-		monitoredEntitiesReconciler:      monitoredentities.NewReconciler(dtc, dk),
-		logmonsettingsReconciler:         logmonsettings.NewReconciler(dtc, dk),
+		configSecretReconciler:      configsecret.NewReconciler(clt, apiReader, dk),
+		daemonsetReconciler:         daemonset.NewReconciler(clt, apiReader, dk),
+		monitoredEntitiesReconciler: monitoredentities.NewReconciler(dtc, dk),
+		logmonsettingsReconciler:    logmonsettings.NewReconciler(dtc, dk),
 	}
 }
 
@@ -53,11 +50,6 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		return err
 	}
 
-	err = r.oneAgentConnectionInfoReconciler.Reconcile(ctx)
-	if err != nil {
-		return err
-	}
-
 	err = r.configSecretReconciler.Reconcile(ctx)
 	if err != nil {
 		return err
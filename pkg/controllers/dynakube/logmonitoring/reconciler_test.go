@@ -17,45 +17,37 @@ import (
 func TestReconcile(t *testing.T) {
 	ctx := context.Background()
 
-	t.Run("connection-info fail => error", func(t *testing.T) {
-		failOAConnectionInfo := createFailingReconciler(t)
-		dk := &dynakube.DynaKube{}
-		passMonitoredEntity := createPassingMonitoredEntityReconciler(t, dk)
+	t.Run("monitored-entities fail => error", func(t *testing.T) {
+		failMonitoredEntity := createFailingReconciler(t)
 		r := Reconciler{
-			dk:                               dk,
-			monitoredEntitiesReconciler:      passMonitoredEntity,
-			oneAgentConnectionInfoReconciler: failOAConnectionInfo,
+			dk:                          &dynakube.DynaKube{},
+			monitoredEntitiesReconciler: failMonitoredEntity,
 		}
 
 		err := r.Reconcile(ctx)
 		require.Error(t, err)
 
-		failOAConnectionInfo.AssertCalled(t, "Reconcile", ctx)
-		passMonitoredEntity.AssertCalled(t, "Reconcile", ctx)
+		failMonitoredEntity.AssertCalled(t, "Reconcile", ctx)
 	})
 
 	t.Run("config-secret fail => error", func(t *testing.T) {
 		failConfigSecret := createFailingReconciler(t)
-		passOAConnectionInfo := createPassingReconciler(t)
 		dk := &dynakube.DynaKube{}
 		passMonitoredEntity := createPassingMonitoredEntityReconciler(t, dk)
 		r := Reconciler{
-			dk:                               dk,
-			monitoredEntitiesReconciler:      passMonitoredEntity,
-			oneAgentConnectionInfoReconciler: passOAConnectionInfo,
-			configSecretReconciler:           failConfigSecret,
+			dk:                          dk,
+			monitoredEntitiesReconciler: passMonitoredEntity,
+			configSecretReconciler:      failConfigSecret,
 		}
 
 		err := r.Reconcile(ctx)
 		require.Error(t, err)
 
 		failConfigSecret.AssertCalled(t, "Reconcile", ctx)
-		passOAConnectionInfo.AssertCalled(t, "Reconcile", ctx)
 		passMonitoredEntity.AssertCalled(t, "Reconcile", ctx)
 	})
 
 	t.Run("all reconcilers pass", func(t *testing.T) {
-		passOAConnectionInfo := createPassingReconciler(t)
 		passConfigSecret := createPassingReconciler(t)
 		passDaemonSet := createPassingReconciler(t)
 		passLogMonSetting := createPassingReconciler(t)
@@ -72,20 +64,18 @@ func TestReconcile(t *testing.T) {
 
 		passMonitoredEntity := createPassingMonitoredEntityReconciler(t, dk)
 		r := Reconciler{
-			dk:                               dk,
-			monitoredEntitiesReconciler:      passMonitoredEntity,
-			oneAgentConnectionInfoReconciler: passOAConnectionInfo,
-			configSecretReconciler:           passConfigSecret,
-			daemonsetReconciler:              passDaemonSet,
-			logmonsettingsReconciler:         passLogMonSetting,
-			dtc:                              mockClient,
+			dk:                          dk,
+			monitoredEntitiesReconciler: passMonitoredEntity,
+			configSecretReconciler:      passConfigSecret,
+			daemonsetReconciler:         passDaemonSet,
+			logmonsettingsReconciler:    passLogMonSetting,
+			dtc:                         mockClient,
 		}
 
 		err := r.Reconcile(ctx)
 		require.NoError(t, err)
 
 		passConfigSecret.AssertCalled(t, "Reconcile", ctx)
-		passOAConnectionInfo.AssertCalled(t, "Reconcile", ctx)
 		passDaemonSet.AssertCalled(t, "Reconcile", ctx)
 		passMonitoredEntity.AssertCalled(t, "Reconcile", ctx)
 	})
@@ -1,5 +1,23 @@
 package rules
 
+import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
 const (
 	conditionType = "MetadataEnrichmentRules"
 )
+
+// NextUpdate returns when the enrichment rules have to be requested from the Dynatrace API again.
+func NextUpdate(dk *dynakube.DynaKube) time.Time {
+	queriedAt := dk.Status.MetadataEnrichment.TenantRulesQueriedAt
+	if queriedAt == nil || meta.IsStatusConditionFalse(*dk.Conditions(), conditionType) {
+		return conditions.NextUpdate(dk, conditionType)
+	}
+
+	return queriedAt.Add(dk.ApiRequestThreshold())
+}
@@ -3,6 +3,7 @@ package rules
 import (
 	"context"
 	"errors"
+	"slices"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
@@ -35,12 +36,28 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		}
 
 		r.dk.Status.MetadataEnrichment.Rules = nil
+		r.dk.Status.MetadataEnrichment.TenantRules = nil
+		r.dk.Status.MetadataEnrichment.TenantRulesQueriedAt = nil
 		meta.RemoveStatusCondition(r.dk.Conditions(), conditionType)
 
 		return nil
 	}
 
-	if !conditions.IsOutdated(r.timeProvider, r.dk, conditionType) {
+	// the rules of the DynaKube are merged every time, so changes to them don't have to wait for the next tenant query
+	defer func() {
+		r.dk.Status.MetadataEnrichment.Rules = mergeRules(r.dk.Status.MetadataEnrichment.TenantRules, r.dk.Spec.MetadataEnrichment.Rules)
+	}()
+
+	if r.dk.OneAgent().GetCodeModulesBundle() != nil {
+		// the tenant can't be queried with a bundle, so only the rules of the DynaKube are used
+		r.dk.Status.MetadataEnrichment.TenantRules = nil
+		r.dk.Status.MetadataEnrichment.TenantRulesQueriedAt = nil
+		conditions.SetStatusUpdated(r.dk.Conditions(), conditionType, "Metadata-enrichment rules of the DynaKube are up-to-date in the status")
+
+		return nil
+	}
+
+	if !r.isTenantRulesOutdated() {
 		return nil
 	}
 
@@ -51,13 +68,53 @@ func (r *Reconciler) Reconcile(ctx context.Context) error {
 		return err
 	}
 
-	r.dk.Status.MetadataEnrichment.Rules = rules
+	r.dk.Status.MetadataEnrichment.TenantRules = rules
+	r.dk.Status.MetadataEnrichment.TenantRulesQueriedAt = r.timeProvider.Now()
 	conditions.SetStatusUpdated(r.dk.Conditions(), conditionType, "Metadata-enrichment rules are up-to-date in the status")
 	log.Info("update rules in the status", "len(rules)", len(rules))
 
 	return nil
 }
 
+// isTenantRulesOutdated is true if the tenant was never queried or its rules are older than the api-request threshold.
+func (r *Reconciler) isTenantRulesOutdated() bool {
+	queriedAt := r.dk.Status.MetadataEnrichment.TenantRulesQueriedAt
+
+	return queriedAt == nil || r.timeProvider.IsOutdated(queriedAt, r.dk.ApiRequestThreshold())
+}
+
+// mergeRules returns the tenant rules, with the rules of the DynaKube replacing the tenant rules with the same target.
+// Rules of the DynaKube with a new target are appended.
+func mergeRules(tenantRules []dynakube.EnrichmentRule, dkRules []dynakube.MetadataEnrichmentRule) []dynakube.EnrichmentRule {
+	if len(tenantRules) == 0 && len(dkRules) == 0 {
+		return nil
+	}
+
+	merged := make([]dynakube.EnrichmentRule, 0, len(tenantRules)+len(dkRules))
+	merged = append(merged, tenantRules...)
+
+	for _, dkRule := range dkRules {
+		rule := dynakube.EnrichmentRule{
+			Type:    dkRule.Type,
+			Scope:   dkRule.Scope,
+			Source:  dkRule.Source,
+			Target:  dkRule.Target,
+			Enabled: true,
+		}
+
+		index := slices.IndexFunc(merged, func(tenantRule dynakube.EnrichmentRule) bool {
+			return tenantRule.Target == rule.Target
+		})
+		if index >= 0 {
+			merged[index] = rule
+		} else {
+			merged = append(merged, rule)
+		}
+	}
+
+	return merged
+}
+
 func (r *Reconciler) getEnrichmentRules(ctx context.Context) ([]dynakube.EnrichmentRule, error) {
 	rulesResponse, err := r.dtc.GetRulesSettings(ctx, r.dk.Status.KubeSystemUUID, r.dk.Status.KubernetesClusterMEID)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
@@ -36,6 +37,7 @@ func TestReconcile(t *testing.T) {
 		dk := createDynaKube()
 		dk.Spec.MetadataEnrichment.Enabled = ptr.To(false)
 		dk.Status.MetadataEnrichment.Rules = createRules()
+		dk.Status.MetadataEnrichment.TenantRulesQueriedAt = timeprovider.New().Now()
 		conditions.SetStatusUpdated(dk.Conditions(), conditionType, "TESTING")
 
 		dtc := dtclientmock.NewClient(t)
@@ -45,11 +47,79 @@ func TestReconcile(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Empty(t, dk.Status.MetadataEnrichment.Rules)
+		assert.Empty(t, dk.Status.MetadataEnrichment.TenantRules)
+		assert.Nil(t, dk.Status.MetadataEnrichment.TenantRulesQueriedAt)
 		assert.Empty(t, dk.Status.Conditions)
 	})
 
+	t.Run("query tenant if it was never queried, even with an up-to-date condition", func(t *testing.T) {
+		dk := createDynaKube()
+		dk.Status.MetadataEnrichment.Rules = createRules()
+		conditions.SetStatusUpdated(dk.Conditions(), conditionType, "TESTING")
+
+		dtc := dtclientmock.NewClient(t)
+		dtc.On("GetRulesSettings", mock.AnythingOfType("context.backgroundCtx"), dk.Status.KubeSystemUUID, dk.Status.KubernetesClusterMEID).Return(dtclient.GetRulesSettingsResponse{}, nil)
+		reconciler := NewReconciler(dtc, &dk)
+
+		err := reconciler.Reconcile(ctx)
+
+		require.NoError(t, err)
+		assert.NotNil(t, dk.Status.MetadataEnrichment.TenantRulesQueriedAt)
+		assert.Empty(t, dk.Status.MetadataEnrichment.TenantRules)
+		assert.Empty(t, dk.Status.MetadataEnrichment.Rules)
+	})
+
+	t.Run("merge rules of the dynakube without querying the tenant", func(t *testing.T) {
+		dk := createDynaKube()
+		dk.Status.MetadataEnrichment.TenantRules = createRules()
+		dk.Status.MetadataEnrichment.TenantRulesQueriedAt = timeprovider.New().Now()
+		dk.Spec.MetadataEnrichment.Rules = []dynakube.MetadataEnrichmentRule{
+			{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentNodeScope, Source: "topology.kubernetes.io/zone", Target: "zone"},
+		}
+		conditions.SetStatusUpdated(dk.Conditions(), conditionType, "TESTING")
+
+		dtc := dtclientmock.NewClient(t)
+		reconciler := NewReconciler(dtc, &dk)
+
+		err := reconciler.Reconcile(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, dk.Status.MetadataEnrichment.Rules, 3)
+		assert.Equal(t, createRules(), dk.Status.MetadataEnrichment.TenantRules)
+		assert.Equal(t, dynakube.EnrichmentRule{
+			Type:    dynakube.EnrichmentLabelRule,
+			Scope:   dynakube.EnrichmentNodeScope,
+			Source:  "topology.kubernetes.io/zone",
+			Target:  "zone",
+			Enabled: true,
+		}, dk.Status.MetadataEnrichment.Rules[2])
+	})
+
+	t.Run("only rules of the dynakube with a code modules bundle", func(t *testing.T) {
+		dk := createDynaKube()
+		dk.Spec.OneAgent.ApplicationMonitoring = &oneagent.ApplicationMonitoringSpec{
+			AppInjectionSpec: oneagent.AppInjectionSpec{
+				CodeModulesBundle: &oneagent.CodeModulesBundleSpec{Path: "/bundles/codemodules.tar.gz"},
+			},
+		}
+		dk.Spec.MetadataEnrichment.Rules = []dynakube.MetadataEnrichmentRule{
+			{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentNodeScope, Source: "topology.kubernetes.io/zone", Target: "zone"},
+		}
+
+		dtc := dtclientmock.NewClient(t)
+		reconciler := NewReconciler(dtc, &dk)
+
+		err := reconciler.Reconcile(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, dk.Status.MetadataEnrichment.Rules, 1)
+		assert.Nil(t, dk.Status.MetadataEnrichment.TenantRules)
+		assert.Nil(t, dk.Status.MetadataEnrichment.TenantRulesQueriedAt)
+	})
+
 	t.Run("no update if not outdated", func(t *testing.T) {
 		dk := createDynaKube()
+		dk.Status.MetadataEnrichment.TenantRulesQueriedAt = timeprovider.New().Now()
 		specialMessage := "TESTING" // if the special message does not change == condition didn't update
 		conditions.SetStatusUpdated(dk.Conditions(), conditionType, specialMessage)
 
@@ -68,6 +138,7 @@ func TestReconcile(t *testing.T) {
 		dk := createDynaKube()
 		expectedResponse := createRulesResponse()
 		specialMessage := "TESTING" // if the special message changes == condition updated
+		dk.Status.MetadataEnrichment.TenantRulesQueriedAt = timeprovider.New().Now()
 		conditions.SetStatusUpdated(dk.Conditions(), conditionType, specialMessage)
 
 		dtc := dtclientmock.NewClient(t)
@@ -120,6 +191,32 @@ func TestReconcile(t *testing.T) {
 	})
 }
 
+func TestMergeRules(t *testing.T) {
+	t.Run("nil if there are no rules", func(t *testing.T) {
+		assert.Nil(t, mergeRules([]dynakube.EnrichmentRule{}, nil))
+	})
+
+	t.Run("rules of the dynakube replace tenant rules with the same target", func(t *testing.T) {
+		tenantRules := []dynakube.EnrichmentRule{
+			{Type: dynakube.EnrichmentLabelRule, Source: "team", Target: "team"},
+			{Type: dynakube.EnrichmentAnnotationRule, Source: "cost-center", Target: "cost"},
+		}
+		dkRules := []dynakube.MetadataEnrichmentRule{
+			{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentWorkloadScope, Source: "app.kubernetes.io/team", Target: "team"},
+			{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentPodScope, Source: "version", Target: "version"},
+		}
+
+		merged := mergeRules(tenantRules, dkRules)
+
+		assert.Equal(t, []dynakube.EnrichmentRule{
+			{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentWorkloadScope, Source: "app.kubernetes.io/team", Target: "team", Enabled: true},
+			{Type: dynakube.EnrichmentAnnotationRule, Source: "cost-center", Target: "cost"},
+			{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentPodScope, Source: "version", Target: "version", Enabled: true},
+		}, merged)
+		assert.Equal(t, "team", tenantRules[0].Source)
+	})
+}
+
 func createDynaKube() dynakube.DynaKube {
 	return dynakube.DynaKube{
 		ObjectMeta: metav1.ObjectMeta{
@@ -154,3 +251,20 @@ func createRules() []dynakube.EnrichmentRule {
 		{Source: "test2"},
 	}
 }
+
+func TestNextUpdate(t *testing.T) {
+	t.Run("api-request threshold after the last query", func(t *testing.T) {
+		dk := createDynaKube()
+		queriedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		dk.Status.MetadataEnrichment.TenantRulesQueriedAt = &queriedAt
+		conditions.SetStatusUpdated(dk.Conditions(), conditionType, "TESTING")
+
+		assert.Equal(t, queriedAt.Add(dk.ApiRequestThreshold()), NextUpdate(&dk))
+	})
+
+	t.Run("no update without condition", func(t *testing.T) {
+		dk := createDynaKube()
+
+		assert.True(t, NextUpdate(&dk).IsZero())
+	})
+}
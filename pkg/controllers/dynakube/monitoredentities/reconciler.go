@@ -35,6 +35,12 @@ type Reconciler struct {
 func (r *Reconciler) Reconcile(ctx context.Context) error {
 	log.Info("start reconciling monitored entities")
 
+	if r.dk.OneAgent().GetCodeModulesBundle() != nil {
+		log.Info("code modules bundle is used, the Kubernetes Cluster MEID can't be queried from the tenant")
+
+		return nil
+	}
+
 	if !conditions.IsOutdated(r.timeProvider, r.dk, MEIDConditionType) {
 		log.Info("Kubernetes Cluster MEID not outdated, skipping reconciliation")
 
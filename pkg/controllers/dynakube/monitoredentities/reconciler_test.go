@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	dtclientmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/clients/dynatrace"
 	"github.com/stretchr/testify/mock"
@@ -54,6 +55,23 @@ func TestReconcile(t *testing.T) {
 
 		err := reconciler.Reconcile(ctx)
 
+		require.NoError(t, err)
+		require.Empty(t, dk.Status.KubernetesClusterMEID)
+	})
+	t.Run("no tenant request with a code modules bundle", func(t *testing.T) {
+		clt := dtclientmock.NewClient(t)
+
+		dk := createDynaKube()
+		dk.Spec.OneAgent.ApplicationMonitoring = &oneagent.ApplicationMonitoringSpec{
+			AppInjectionSpec: oneagent.AppInjectionSpec{
+				CodeModulesBundle: &oneagent.CodeModulesBundleSpec{Path: "/bundles/codemodules.tar.gz"},
+			},
+		}
+
+		reconciler := NewReconciler(clt, &dk)
+
+		err := reconciler.Reconcile(ctx)
+
 		require.NoError(t, err)
 		require.Empty(t, dk.Status.KubernetesClusterMEID)
 	})
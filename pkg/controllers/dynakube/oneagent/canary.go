@@ -0,0 +1,218 @@
+package oneagent
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/version"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
+	k8sdaemonset "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/daemonset"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	canaryLabel      = "oneagent.dynatrace.com/canary"
+	canaryLabelValue = "true"
+
+	nodeNameField = "metadata.name"
+)
+
+// evaluateCanary checks the health of the canary DaemonSet, so a verified candidate can be promoted by the version reconciler.
+func (r *Reconciler) evaluateCanary(ctx context.Context) error {
+	rollout := r.dk.Status.OneAgent.Rollout
+	if !r.dk.OneAgent().IsOneAgentRolloutEnabled() || rollout == nil || rollout.Phase != status.RolloutCanaryPhase {
+		return nil
+	}
+
+	canaryDs, err := k8sdaemonset.Query(r.client, r.apiReader, log).Get(ctx, client.ObjectKey{Name: r.dk.OneAgent().GetCanaryDaemonsetName(), Namespace: r.dk.Namespace})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+
+	podList := &corev1.PodList{}
+
+	err = r.client.List(ctx, podList,
+		client.InNamespace(r.dk.Namespace),
+		client.MatchingLabels(canaryDs.Spec.Selector.MatchLabels),
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	desired := canaryDs.Status.DesiredNumberScheduled
+	health := version.CanaryHealth{
+		Pods:  podList.Items,
+		Ready: desired > 0 && canaryDs.Status.NumberReady == desired && canaryDs.Status.UpdatedNumberScheduled == desired,
+	}
+
+	version.EvaluateCanary(rollout, r.dk.OneAgent(), health, r.timeProvider.Now().Time)
+
+	return nil
+}
+
+// reconcileCanary deploys the candidate of an active rollout to the canary nodes, or removes the canary DaemonSet if there is no active rollout.
+// It returns the nodes, which have to be excluded from the main DaemonSet.
+func (r *Reconciler) reconcileCanary(ctx context.Context) ([]string, error) {
+	rollout := r.dk.OneAgent().GetActiveRollout()
+	if rollout == nil {
+		return nil, r.removeCanaryDaemonSet(ctx)
+	}
+
+	if len(rollout.CanaryNodes) == 0 {
+		canaryNodes, err := r.selectCanaryNodes(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(canaryNodes) == 0 {
+			rollout.Message = "No node matches the canary selection of the rollout"
+
+			return nil, r.removeCanaryDaemonSet(ctx)
+		}
+
+		rollout.CanaryNodes = canaryNodes
+	}
+
+	canaryDs, err := r.buildCanaryDaemonSet(rollout)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = k8sdaemonset.Query(r.client, r.apiReader, log).WithOwner(r.dk).CreateOrUpdate(ctx, canaryDs)
+	if err != nil {
+		log.Info("failed to roll out canary OneAgent DaemonSet")
+
+		return nil, err
+	}
+
+	return rollout.CanaryNodes, nil
+}
+
+// selectCanaryNodes picks the nodes for the canary from the nodes the OneAgent is deployed to, either by the nodeSelector or the percentage of the rollout.
+func (r *Reconciler) selectCanaryNodes(ctx context.Context) ([]string, error) {
+	rolloutSpec := r.dk.OneAgent().Spec.Rollout
+
+	nodeList := &corev1.NodeList{}
+
+	err := r.apiReader.List(ctx, nodeList, client.MatchingLabels(r.dk.OneAgent().GetNodeSelector(nil)))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	canarySelector := k8slabels.SelectorFromSet(rolloutSpec.NodeSelector)
+
+	var candidates []string
+
+	for _, node := range nodeList.Items {
+		if canarySelector.Matches(k8slabels.Set(node.Labels)) {
+			candidates = append(candidates, node.Name)
+		}
+	}
+
+	slices.Sort(candidates)
+
+	if rolloutSpec.Percentage != nil && len(candidates) > 0 {
+		count := int(math.Ceil(float64(len(candidates)) * float64(*rolloutSpec.Percentage) / 100))
+		candidates = candidates[:max(1, min(count, len(candidates)))]
+	}
+
+	return candidates, nil
+}
+
+func (r *Reconciler) buildCanaryDaemonSet(rollout *status.RolloutStatus) (*appsv1.DaemonSet, error) {
+	canaryDk := r.dk.DeepCopy()
+	canaryDk.Status.OneAgent.VersionStatus = rollout.Candidate
+
+	canaryDs, err := r.buildDesiredDaemonSet(canaryDk)
+	if err != nil {
+		return nil, err
+	}
+
+	canaryDs.Name = r.dk.OneAgent().GetCanaryDaemonsetName()
+	canaryDs.Labels[canaryLabel] = canaryLabelValue
+	canaryDs.Spec.Selector.MatchLabels[canaryLabel] = canaryLabelValue
+	canaryDs.Spec.Template.Labels[canaryLabel] = canaryLabelValue
+	restrictToNodes(&canaryDs.Spec.Template.Spec, corev1.NodeSelectorOpIn, rollout.CanaryNodes)
+
+	err = rehash(canaryDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := controllerutil.SetControllerReference(r.dk, canaryDs, scheme.Scheme); err != nil {
+		return nil, err
+	}
+
+	return canaryDs, nil
+}
+
+func (r *Reconciler) removeCanaryDaemonSet(ctx context.Context) error {
+	canaryDaemonSet := appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: r.dk.OneAgent().GetCanaryDaemonsetName(), Namespace: r.dk.Namespace}}
+
+	return client.IgnoreNotFound(r.client.Delete(ctx, &canaryDaemonSet))
+}
+
+// excludeCanaryNodes keeps the main DaemonSet away from the canary nodes.
+func excludeCanaryNodes(ds *appsv1.DaemonSet, canaryNodes []string) error {
+	if len(canaryNodes) == 0 {
+		return nil
+	}
+
+	restrictToNodes(&ds.Spec.Template.Spec, corev1.NodeSelectorOpNotIn, canaryNodes)
+
+	return rehash(ds)
+}
+
+// restrictToNodes adds a requirement on the node names to every term of the required node affinity.
+func restrictToNodes(podSpec *corev1.PodSpec, operator corev1.NodeSelectorOperator, nodeNames []string) {
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      nodeNameField,
+		Operator: operator,
+		Values:   nodeNames,
+	}
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+
+	if podSpec.Affinity.NodeAffinity == nil {
+		podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+
+	nodeAffinity := podSpec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil || len(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{}},
+		}
+	}
+
+	terms := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for i := range terms {
+		terms[i].MatchFields = append(terms[i].MatchFields, requirement)
+	}
+}
+
+func rehash(ds *appsv1.DaemonSet) error {
+	delete(ds.Annotations, hasher.AnnotationHash)
+
+	dsHash, err := hasher.GenerateHash(ds)
+	if err != nil {
+		return err
+	}
+
+	ds.Annotations[hasher.AnnotationHash] = dsHash
+
+	return nil
+}
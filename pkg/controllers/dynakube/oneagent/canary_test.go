@@ -0,0 +1,125 @@
+package oneagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReconcileCanary(t *testing.T) {
+	ctx := context.Background()
+	previous := status.VersionStatus{Version: "1.2.3.4-5", ImageID: "registry/oneagent:1.2.3"}
+	candidate := status.VersionStatus{Version: "1.2.4.4-5", ImageID: "registry/oneagent:1.2.4"}
+
+	createDynakube := func(phase status.RolloutPhase) *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: "dynatrace"},
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+					Rollout:          &oneagent.RolloutSpec{Percentage: ptr.To(int32(50))},
+				},
+			},
+		}
+		dk.Status.OneAgent.VersionStatus = previous
+		dk.Status.OneAgent.Rollout = &status.RolloutStatus{Phase: phase, Candidate: candidate, Previous: previous}
+
+		return dk
+	}
+
+	createNodes := func(names ...string) []client.Object {
+		nodes := []client.Object{}
+		for _, name := range names {
+			nodes = append(nodes, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+
+		return nodes
+	}
+
+	t.Run("candidate is deployed to the canary nodes", func(t *testing.T) {
+		dk := createDynakube(status.RolloutCanaryPhase)
+		fakeClient := fake.NewClient(createNodes("node-c", "node-a", "node-b")...)
+		reconciler := &Reconciler{client: fakeClient, apiReader: fakeClient, dk: dk}
+
+		err := reconciler.reconcileRollout(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"node-a", "node-b"}, dk.Status.OneAgent.Rollout.CanaryNodes)
+
+		canaryDs := &appsv1.DaemonSet{}
+		err = fakeClient.Get(ctx, client.ObjectKey{Name: dk.OneAgent().GetCanaryDaemonsetName(), Namespace: dk.Namespace}, canaryDs)
+		require.NoError(t, err)
+		assert.Equal(t, candidate.ImageID, canaryDs.Spec.Template.Spec.Containers[0].Image)
+		assert.Equal(t, canaryLabelValue, canaryDs.Spec.Selector.MatchLabels[canaryLabel])
+		assertNodeRequirement(t, canaryDs, corev1.NodeSelectorOpIn, "node-a", "node-b")
+
+		mainDs := &appsv1.DaemonSet{}
+		err = fakeClient.Get(ctx, client.ObjectKey{Name: dk.OneAgent().GetDaemonsetName(), Namespace: dk.Namespace}, mainDs)
+		require.NoError(t, err)
+		assert.Equal(t, previous.ImageID, mainDs.Spec.Template.Spec.Containers[0].Image)
+		assertNodeRequirement(t, mainDs, corev1.NodeSelectorOpNotIn, "node-a", "node-b")
+	})
+
+	t.Run("canary is removed after promotion", func(t *testing.T) {
+		dk := createDynakube(status.RolloutPromotedPhase)
+		canaryDs := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: dk.OneAgent().GetCanaryDaemonsetName(), Namespace: dk.Namespace}}
+		fakeClient := fake.NewClient(canaryDs)
+		reconciler := &Reconciler{client: fakeClient, apiReader: fakeClient, dk: dk}
+
+		err := reconciler.reconcileRollout(ctx)
+		require.NoError(t, err)
+
+		err = fakeClient.Get(ctx, client.ObjectKeyFromObject(canaryDs), &appsv1.DaemonSet{})
+		assert.True(t, k8serrors.IsNotFound(err))
+
+		mainDs := &appsv1.DaemonSet{}
+		err = fakeClient.Get(ctx, client.ObjectKey{Name: dk.OneAgent().GetDaemonsetName(), Namespace: dk.Namespace}, mainDs)
+		require.NoError(t, err)
+		assert.Empty(t, mainDs.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchFields)
+	})
+
+	t.Run("ready canary is verified after the soak time", func(t *testing.T) {
+		dk := createDynakube(status.RolloutCanaryPhase)
+		dk.Spec.OneAgent.Rollout.SoakTime = &metav1.Duration{Duration: time.Minute}
+		dk.Status.OneAgent.Rollout.StartedAt = ptr.To(metav1.NewTime(time.Now().Add(-time.Hour)))
+		canaryDs := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: dk.OneAgent().GetCanaryDaemonsetName(), Namespace: dk.Namespace},
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{canaryLabel: canaryLabelValue}},
+			},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 1, NumberReady: 1, UpdatedNumberScheduled: 1},
+		}
+		fakeClient := fake.NewClient(canaryDs)
+		reconciler := &Reconciler{client: fakeClient, apiReader: fakeClient, dk: dk, timeProvider: timeprovider.New()}
+
+		err := reconciler.evaluateCanary(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, status.RolloutVerifiedPhase, dk.Status.OneAgent.Rollout.Phase)
+	})
+}
+
+func assertNodeRequirement(t *testing.T, ds *appsv1.DaemonSet, operator corev1.NodeSelectorOperator, nodeNames ...string) {
+	t.Helper()
+
+	terms := ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.NotEmpty(t, terms)
+
+	for _, term := range terms {
+		assert.Contains(t, term.MatchFields, corev1.NodeSelectorRequirement{Key: nodeNameField, Operator: operator, Values: nodeNames})
+	}
+}
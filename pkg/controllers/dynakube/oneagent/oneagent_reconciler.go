@@ -14,7 +14,6 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo"
-	oaconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dtpullsecret"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent/daemonset"
@@ -63,26 +62,26 @@ func NewReconciler( //nolint
 	clusterID string,
 ) controllers.Reconciler {
 	return &Reconciler{
-		client:                   client,
-		apiReader:                apiReader,
-		clusterID:                clusterID,
-		dk:                       dk,
-		connectionInfoReconciler: oaconnectioninfo.NewReconciler(client, apiReader, dtClient, dk),
-		versionReconciler:        version.NewReconciler(apiReader, dtClient, timeprovider.New().Freeze()),
-		tokens:                   tokens,
+		client:            client,
+		apiReader:         apiReader,
+		clusterID:         clusterID,
+		dk:                dk,
+		versionReconciler: version.NewReconciler(apiReader, dtClient, timeprovider.New().Freeze()),
+		tokens:            tokens,
+		timeProvider:      timeprovider.New(),
 	}
 }
 
 type Reconciler struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client                   client.Client
-	apiReader                client.Reader
-	connectionInfoReconciler controllers.Reconciler
-	versionReconciler        version.Reconciler
-	dk                       *dynakube.DynaKube
-	tokens                   token.Tokens
-	clusterID                string
+	client            client.Client
+	apiReader         client.Reader
+	versionReconciler version.Reconciler
+	dk                *dynakube.DynaKube
+	timeProvider      *timeprovider.Provider
+	tokens            token.Tokens
+	clusterID         string
 }
 
 // Reconcile reads that state of the cluster for a OneAgent object and makes changes based on the state read
@@ -93,20 +92,12 @@ type Reconciler struct {
 func (r *Reconciler) Reconcile(ctx context.Context) error {
 	log.Info("reconciling OneAgent")
 
-	err := r.versionReconciler.ReconcileOneAgent(ctx, r.dk)
+	err := r.evaluateCanary(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = r.connectionInfoReconciler.Reconcile(ctx)
-	if errors.Is(err, oaconnectioninfo.NoOneAgentCommunicationHostsError) { // This only informational
-		log.Info("OneAgent were not yet able to communicate with tenant, no direct route or ready ActiveGate available, postponing OneAgent deployment")
-
-		if r.dk.Spec.NetworkZone != "" {
-			log.Info("A network zone has been configured for DynaKube, check that there a working ActiveGate ready for that network zone", "network zone", r.dk.Spec.NetworkZone, "dynakube", r.dk.Name)
-		}
-	}
-
+	err = r.versionReconciler.ReconcileOneAgent(ctx, r.dk)
 	if err != nil {
 		return err
 	}
@@ -240,8 +231,19 @@ func extractPublicData(dk *dynakube.DynaKube) map[string]string {
 }
 
 func (r *Reconciler) reconcileRollout(ctx context.Context) error {
+	canaryNodes, err := r.reconcileCanary(ctx)
+	if err != nil {
+		conditions.SetKubeApiError(r.dk.Conditions(), oaConditionType, err)
+
+		return err
+	}
+
 	// Define a new DaemonSet object
 	dsDesired, err := r.buildDesiredDaemonSet(r.dk)
+	if err == nil {
+		err = excludeCanaryNodes(dsDesired, canaryNodes)
+	}
+
 	if err != nil {
 		log.Info("failed to get desired daemonset")
 		setDaemonSetGenerationFailedCondition(r.dk.Conditions(), err)
@@ -355,7 +357,12 @@ func (r *Reconciler) reconcileInstanceStatuses(ctx context.Context, dk *dynakube
 func (r *Reconciler) removeOneAgentDaemonSet(ctx context.Context, dk *dynakube.DynaKube) error {
 	oneAgentDaemonSet := appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: dk.OneAgent().GetDaemonsetName(), Namespace: dk.Namespace}}
 
-	return client.IgnoreNotFound(r.client.Delete(ctx, &oneAgentDaemonSet))
+	err := r.client.Delete(ctx, &oneAgentDaemonSet)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	return r.removeCanaryDaemonSet(ctx)
 }
 
 func getInstanceStatuses(pods []corev1.Pod) map[string]oneagent.Instance {
@@ -9,9 +9,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
-	"github.com/Dynatrace/dynatrace-operator/pkg/controllers"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo"
-	oaconnectioninfo "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/deploymentmetadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/oneagent/daemonset"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
@@ -20,7 +18,6 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
 	"github.com/Dynatrace/dynatrace-operator/pkg/version"
 	dtclientmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/clients/dynatrace"
-	controllermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/controllers"
 	versionmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/controllers/dynakube/version"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -58,12 +55,11 @@ func TestReconcile(t *testing.T) {
 		fakeClient := fake.NewClient(dk)
 
 		reconciler := &Reconciler{
-			client:                   fakeClient,
-			apiReader:                fakeClient,
-			dk:                       dk,
-			versionReconciler:        createVersionReconcilerMock(t),
-			connectionInfoReconciler: createConnectionInfoReconcilerMock(t),
-			tokens:                   createTokens(),
+			client:            fakeClient,
+			apiReader:         fakeClient,
+			dk:                dk,
+			versionReconciler: createVersionReconcilerMock(t),
+			tokens:            createTokens(),
 		}
 
 		err := reconciler.Reconcile(ctx)
@@ -84,11 +80,10 @@ func TestReconcile(t *testing.T) {
 		fakeClient := fake.NewClient(dk, &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: dk.OneAgent().GetDaemonsetName(), Namespace: dk.Namespace}})
 
 		reconciler := &Reconciler{
-			client:                   fakeClient,
-			apiReader:                fakeClient,
-			dk:                       dk,
-			versionReconciler:        createVersionReconcilerMock(t),
-			connectionInfoReconciler: createConnectionInfoReconcilerMock(t),
+			client:            fakeClient,
+			apiReader:         fakeClient,
+			dk:                dk,
+			versionReconciler: createVersionReconcilerMock(t),
 		}
 
 		err := reconciler.Reconcile(ctx)
@@ -107,46 +102,16 @@ func TestReconcile(t *testing.T) {
 		fakeClient := fake.NewClient(dk)
 
 		reconciler := &Reconciler{
-			client:                   fakeClient,
-			apiReader:                fakeClient,
-			dk:                       dk,
-			versionReconciler:        createVersionReconcilerMock(t),
-			connectionInfoReconciler: createConnectionInfoReconcilerMock(t),
+			client:            fakeClient,
+			apiReader:         fakeClient,
+			dk:                dk,
+			versionReconciler: createVersionReconcilerMock(t),
 		}
 
 		err := reconciler.Reconcile(ctx)
 		require.NoError(t, err)
 	})
 
-	t.Run("NoOneAgentCommunicationHostsError => bubble up error", func(t *testing.T) {
-		dk := dynakube.DynaKube{
-			ObjectMeta: metav1.ObjectMeta{Name: dkName, Namespace: namespace},
-			Spec: dynakube.DynaKubeSpec{
-				APIURL:      "https://ENVIRONMENTID.live.dynatrace.com/api",
-				NetworkZone: "test",
-				OneAgent: oneagent.Spec{
-					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{},
-				},
-			},
-		}
-
-		connectionInfoReconciler := controllermock.NewReconciler(t)
-		connectionInfoReconciler.On("Reconcile",
-			mock.AnythingOfType("context.backgroundCtx")).Return(oaconnectioninfo.NoOneAgentCommunicationHostsError).Once()
-
-		fakeClient := fake.NewClient()
-		reconciler := &Reconciler{
-			client:                   fakeClient,
-			apiReader:                fakeClient,
-			dk:                       &dk,
-			connectionInfoReconciler: connectionInfoReconciler,
-			versionReconciler:        createVersionReconcilerMock(t),
-		}
-
-		err := reconciler.Reconcile(ctx)
-		require.ErrorIs(t, err, oaconnectioninfo.NoOneAgentCommunicationHostsError)
-	})
-
 	t.Run("version reconcile fail => return immediately and bubble up error", func(t *testing.T) {
 		dk := dynakube.DynaKube{
 			ObjectMeta: metav1.ObjectMeta{Name: dkName, Namespace: namespace},
@@ -165,11 +130,10 @@ func TestReconcile(t *testing.T) {
 
 		fakeClient := fake.NewClient()
 		reconciler := &Reconciler{
-			client:                   fakeClient,
-			apiReader:                fakeClient,
-			dk:                       &dk,
-			connectionInfoReconciler: controllermock.NewReconciler(t),
-			versionReconciler:        versionReconciler,
+			client:            fakeClient,
+			apiReader:         fakeClient,
+			dk:                &dk,
+			versionReconciler: versionReconciler,
 		}
 
 		err := reconciler.Reconcile(ctx)
@@ -214,12 +178,11 @@ func TestReconcileOneAgent_ReconcileOnEmptyEnvironmentAndDNSPolicy(t *testing.T)
 	dtClient := dtclientmock.NewClient(t)
 
 	reconciler := &Reconciler{
-		client:                   fakeClient,
-		apiReader:                fakeClient,
-		dk:                       dk,
-		connectionInfoReconciler: createConnectionInfoReconcilerMock(t),
-		versionReconciler:        createVersionReconcilerMock(t),
-		tokens:                   createTokens(),
+		client:            fakeClient,
+		apiReader:         fakeClient,
+		dk:                dk,
+		versionReconciler: createVersionReconcilerMock(t),
+		tokens:            createTokens(),
 	}
 
 	err := reconciler.Reconcile(ctx)
@@ -286,7 +249,6 @@ func TestReconcile_InstancesSet(t *testing.T) {
 	t.Run("Status.OneAgent.Instances set, if autoUpdate is true", func(t *testing.T) {
 		dk := base.DeepCopy()
 		reconciler.dk = dk
-		reconciler.connectionInfoReconciler = createConnectionInfoReconcilerMock(t)
 		reconciler.versionReconciler = createVersionReconcilerMock(t)
 		reconciler.tokens = createTokens()
 		dk.Status.OneAgent.Version = oldComponentVersion
@@ -318,7 +280,6 @@ func TestReconcile_InstancesSet(t *testing.T) {
 		dk := base.DeepCopy()
 		autoUpdate := false
 		reconciler.dk = dk
-		reconciler.connectionInfoReconciler = createConnectionInfoReconcilerMock(t)
 		reconciler.versionReconciler = createVersionReconcilerMock(t)
 		reconciler.tokens = createTokens()
 		dk.Spec.OneAgent.ClassicFullStack.AutoUpdate = &autoUpdate
@@ -746,12 +707,11 @@ func TestReconcile_OneAgentConfigMap(t *testing.T) {
 
 	t.Run(`create OneAgent connection info ConfigMap`, func(t *testing.T) {
 		reconciler := Reconciler{
-			dk:                       dk,
-			client:                   fakeClient,
-			apiReader:                fakeClient,
-			versionReconciler:        createVersionReconcilerMock(t),
-			connectionInfoReconciler: createConnectionInfoReconcilerMock(t),
-			tokens:                   createTokens(),
+			dk:                dk,
+			client:            fakeClient,
+			apiReader:         fakeClient,
+			versionReconciler: createVersionReconcilerMock(t),
+			tokens:            createTokens(),
 		}
 
 		err := reconciler.Reconcile(ctx)
@@ -765,14 +725,6 @@ func TestReconcile_OneAgentConfigMap(t *testing.T) {
 	})
 }
 
-func createConnectionInfoReconcilerMock(t *testing.T) controllers.Reconciler {
-	connectionInfoReconciler := controllermock.NewReconciler(t)
-	connectionInfoReconciler.On("Reconcile",
-		mock.AnythingOfType("context.backgroundCtx")).Return(nil).Once()
-
-	return connectionInfoReconciler
-}
-
 func createVersionReconcilerMock(t *testing.T) versions.Reconciler {
 	versionReconciler := versionmock.NewReconciler(t)
 	versionReconciler.On("ReconcileOneAgent",
@@ -79,6 +79,8 @@ func (r *Reconciler) reconcileConfigMap(ctx context.Context) error {
 func (r *Reconciler) prepareConfigMap() (*corev1.ConfigMap, error) {
 	data, err := r.getData()
 	if err != nil {
+		conditions.SetConfigMapGenFailed(r.dk.Conditions(), conditionType, err)
+
 		return nil, err
 	}
 
@@ -117,6 +119,13 @@ func (r *Reconciler) getData() (map[string]string, error) {
 		options = append(options, otelcgen.WithTLS(filepath.Join(otelcconsts.CustomTlsCertMountPath, consts.TLSCrtDataName), filepath.Join(otelcconsts.CustomTlsCertMountPath, consts.TLSKeyDataName)))
 	}
 
+	pipelineOptions, err := r.dk.TelemetryIngest().GetPipelineOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	options = append(options, pipelineOptions...)
+
 	options = append(options,
 		otelcgen.WithExporters(),
 		otelcgen.WithProcessors(),
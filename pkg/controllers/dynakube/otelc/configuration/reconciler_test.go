@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -53,4 +54,35 @@ func TestConfigurationConfigMap(t *testing.T) {
 		assert.Equal(t, conditions.ConfigMapCreatedOrUpdatedReason, dk.Status.Conditions[0].Reason)
 		assert.Equal(t, metav1.ConditionTrue, dk.Status.Conditions[0].Status)
 	})
+	t.Run("invalid pipeline customizations are reported on the status", func(t *testing.T) {
+		mockK8sClient := fake.NewFakeClient()
+		dk := getTestDynakube(&telemetryingest.Spec{
+			Exporters: []telemetryingest.ComponentSpec{{ID: "otlphttp"}},
+		})
+		err := NewReconciler(mockK8sClient, mockK8sClient, dk).Reconcile(context.Background())
+		require.Error(t, err)
+
+		require.Len(t, dk.Status.Conditions, 1)
+		assert.Equal(t, conditionType, dk.Status.Conditions[0].Type)
+		assert.Equal(t, conditions.ConfigMapGenerationFailed, dk.Status.Conditions[0].Reason)
+		assert.Equal(t, metav1.ConditionFalse, dk.Status.Conditions[0].Status)
+		assert.Contains(t, dk.Status.Conditions[0].Message, "collides with a built-in component")
+	})
+
+	t.Run("pipeline customizations are part of the configmap", func(t *testing.T) {
+		mockK8sClient := fake.NewFakeClient()
+		dk := getTestDynakube(&telemetryingest.Spec{
+			Processors: []telemetryingest.ComponentSpec{
+				{ID: "attributes/team", Config: &apiextensionsv1.JSON{Raw: []byte(`{"actions":[{"key":"team","value":"checkout","action":"upsert"}]}`)}},
+			},
+		})
+		err := NewReconciler(mockK8sClient, mockK8sClient, dk).Reconcile(context.Background())
+		require.NoError(t, err)
+
+		configMap := &corev1.ConfigMap{}
+		err = mockK8sClient.Get(context.Background(), client.ObjectKey{Name: GetConfigMapName(dk.Name), Namespace: dk.Namespace}, configMap)
+		require.NoError(t, err)
+
+		assert.Contains(t, configMap.Data[consts.ConfigFieldName], "attributes/team")
+	})
 }
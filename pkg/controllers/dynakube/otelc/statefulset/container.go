@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -17,7 +18,8 @@ const (
 	otelcSecretTokenFilePath = secretsTokensPath + "/" + consts.OtelcTokenSecretKey
 )
 
-func getContainer(dk *dynakube.DynaKube) corev1.Container {
+// Image returns the image of the OpenTelemetry collector StatefulSet, as it is deployed for the DynaKube.
+func Image(dk *dynakube.DynaKube) string {
 	imageRepo := dk.Spec.Templates.OpenTelemetryCollector.ImageRef.Repository
 	imageTag := dk.Spec.Templates.OpenTelemetryCollector.ImageRef.Tag
 
@@ -29,9 +31,13 @@ func getContainer(dk *dynakube.DynaKube) corev1.Container {
 		imageTag = defaultImageTag
 	}
 
+	return dk.RewriteImage(releaselock.PinOr(dk.ReleaseLock().OpenTelemetryCollector, imageRepo+":"+imageTag))
+}
+
+func getContainer(dk *dynakube.DynaKube) corev1.Container {
 	return corev1.Container{
 		Name:            containerName,
-		Image:           imageRepo + ":" + imageTag,
+		Image:           Image(dk),
 		ImagePullPolicy: corev1.PullAlways,
 		SecurityContext: buildSecurityContext(),
 		Env:             getEnvs(dk),
@@ -7,6 +7,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/configuration"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/signature"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/configmap"
@@ -118,6 +119,12 @@ func (r *Reconciler) createOrUpdateStatefulset(ctx context.Context) error {
 		return err
 	}
 
+	if err := signature.VerifyPodSpecForDynaKube(ctx, r.apiReader, r.dk, &sts.Spec.Template.Spec); err != nil {
+		conditions.SetImageVerificationFailed(r.dk.Conditions(), conditionType, err)
+
+		return err
+	}
+
 	if err := hasher.AddAnnotation(sts); err != nil {
 		conditions.SetKubeApiError(r.dk.Conditions(), conditionType, err)
 
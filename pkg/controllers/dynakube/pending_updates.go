@@ -0,0 +1,49 @@
+package dynakube
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	updatePendingEvent = "UpdatePending"
+	updateAppliedEvent = "UpdateApplied"
+)
+
+// recordPendingUpdates creates events for updates, that were held back or applied according to the update schedules.
+func (controller *Controller) recordPendingUpdates(dk *dynakube.DynaKube, oldStatus dynakube.DynaKubeStatus) {
+	if controller.eventRecorder == nil {
+		return
+	}
+
+	controller.recordPendingUpdate(dk, "OneAgent", oldStatus.OneAgent.VersionStatus, dk.Status.OneAgent.VersionStatus)
+	controller.recordPendingUpdate(dk, "CodeModules", oldStatus.CodeModules.VersionStatus, dk.Status.CodeModules.VersionStatus)
+	controller.recordPendingUpdate(dk, "ActiveGate", oldStatus.ActiveGate.VersionStatus, dk.Status.ActiveGate.VersionStatus)
+}
+
+func (controller *Controller) recordPendingUpdate(dk *dynakube.DynaKube, component string, oldVersion, newVersion status.VersionStatus) {
+	switch {
+	case newVersion.Pending != nil && (oldVersion.Pending == nil || oldVersion.Pending.ImageID != newVersion.Pending.ImageID || oldVersion.Pending.Version != newVersion.Pending.Version):
+		message := fmt.Sprintf("%s update to %s is pending until the next maintenance window", component, pendingVersionName(*newVersion.Pending))
+		if newVersion.Pending.NotBefore != nil {
+			message += " at " + newVersion.Pending.NotBefore.UTC().Format(time.RFC3339)
+		}
+
+		controller.eventRecorder.Event(dk, corev1.EventTypeNormal, updatePendingEvent, message)
+	case oldVersion.Pending != nil && newVersion.Pending == nil && (oldVersion.ImageID != newVersion.ImageID || oldVersion.Version != newVersion.Version):
+		controller.eventRecorder.Event(dk, corev1.EventTypeNormal, updateAppliedEvent,
+			fmt.Sprintf("%s update to %s was applied in the maintenance window", component, newVersion.Version))
+	}
+}
+
+func pendingVersionName(pending status.PendingUpdate) string {
+	if pending.Version != "" {
+		return pending.Version
+	}
+
+	return pending.ImageID
+}
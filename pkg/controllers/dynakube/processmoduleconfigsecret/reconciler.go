@@ -49,7 +49,9 @@ func NewReconciler(clt client.Client,
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context) error {
+	// with a bundle, the csi-provisioner takes the process module config from the bundle itself
 	isNeeded := r.dk.OneAgent().IsCSIAvailable() &&
+		r.dk.OneAgent().GetCodeModulesBundle() == nil &&
 		(r.dk.OneAgent().IsCloudNativeFullstackMode() ||
 			r.dk.OneAgent().IsApplicationMonitoringMode())
 
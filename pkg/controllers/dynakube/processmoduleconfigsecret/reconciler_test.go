@@ -103,6 +103,21 @@ func TestReconcile(t *testing.T) {
 		assert.Empty(t, *dk.Conditions())
 		assert.Error(t, mockK8sClient.Get(context.Background(), client.ObjectKey{Name: extendWithSuffix(testName), Namespace: testNamespace}, &corev1.Secret{}))
 	})
+	t.Run("Not needed with a code modules bundle", func(t *testing.T) {
+		dk := createDynakube(oneagent.Spec{
+			ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{
+				AppInjectionSpec: oneagent.AppInjectionSpec{
+					CodeModulesBundle: &oneagent.CodeModulesBundleSpec{Path: "/bundles/codemodules.tar.gz"},
+				},
+			}})
+		mockK8sClient := fake.NewClient()
+
+		reconciler := NewReconciler(mockK8sClient, mockK8sClient, nil, dk, timeprovider.New())
+		err := reconciler.Reconcile(context.Background())
+
+		require.NoError(t, err)
+		assert.Empty(t, *dk.Conditions())
+	})
 	t.Run("No proxy is set when proxy enabled and custom no proxy set", func(t *testing.T) {
 		dk := createDynakube(oneagent.Spec{
 			CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{}})
@@ -0,0 +1,19 @@
+package releaselock
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+)
+
+const (
+	ConditionType = "ReleaseLock"
+
+	// ConfigMapSuffix is appended to the name of the DynaKube for the default name of an exported release lock.
+	ConfigMapSuffix = "-release-lock"
+
+	invalidReleaseLockReason = "InvalidReleaseLock"
+	pinnedReason             = "Pinned"
+)
+
+var (
+	log = logd.Get().WithName("dynakube-release-lock")
+)
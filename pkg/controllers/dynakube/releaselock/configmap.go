@@ -0,0 +1,78 @@
+package releaselock
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// codeModulesVersionKey is the only entry of a release lock that is a version instead of an image.
+const codeModulesVersionKey = "codeModulesVersion"
+
+// NewConfigMap creates the ConfigMap of a release lock, every pinned component is an entry of it.
+func NewConfigMap(name, namespace string, images releaselock.Images) (*corev1.ConfigMap, error) {
+	rawImages, err := json.Marshal(images)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	data := map[string]string{}
+
+	err = json.Unmarshal(rawImages, &data)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: data,
+	}, nil
+}
+
+// ImagesFromConfigMap reads the images of a release lock.
+// Every image has to be pinned by its digest, so the components run exactly the images the lock was exported with.
+// Code modules downloaded from the tenant are pinned by their version, as there is no image for them.
+func ImagesFromConfigMap(configMap *corev1.ConfigMap) (releaselock.Images, error) {
+	var images releaselock.Images
+
+	rawData, err := json.Marshal(configMap.Data)
+	if err != nil {
+		return images, errors.WithStack(err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(rawData))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(&images)
+	if err != nil {
+		return images, errors.WithMessagef(err, "release lock %s contains an unknown component", configMap.Name)
+	}
+
+	if images == (releaselock.Images{}) {
+		return images, errors.Errorf("release lock %s does not pin any component", configMap.Name)
+	}
+
+	for component, image := range configMap.Data {
+		if component == codeModulesVersionKey {
+			continue
+		}
+
+		if _, err := name.NewDigest(image); err != nil {
+			return images, errors.WithMessagef(err, "image of %s in release lock %s is not pinned by its digest", component, configMap.Name)
+		}
+	}
+
+	return images, nil
+}
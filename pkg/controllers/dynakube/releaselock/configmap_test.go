@@ -0,0 +1,66 @@
+package releaselock
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testNamespace = "dynatrace"
+	testLockName  = "prod-release-lock"
+
+	testOneAgentImage   = "public.ecr.aws/dynatrace/dynatrace-oneagent:1.303.0.20241112-123456@sha256:7ece13a07a20c77a31cc36906a10ebc90bd47970905ee61e8ed491b7f4c5d62f"
+	testActiveGateImage = "public.ecr.aws/dynatrace/dynatrace-activegate@sha256:7ece13a07a20c77a31cc36906a10ebc90bd47970905ee61e8ed491b7f4c5d62f"
+	testDigest          = "sha256:8b4c4c4b3a33c2f8e8ab48d4d5e0c85fd2b3a4f0a3f0a3e3e3f0e2f3a4b5c6d7"
+)
+
+func TestConfigMap(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		images := releaselock.Images{
+			OneAgent:   testOneAgentImage,
+			ActiveGate: testActiveGateImage,
+		}
+
+		configMap, err := NewConfigMap(testLockName, testNamespace, images)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"oneAgent": testOneAgentImage, "activeGate": testActiveGateImage}, configMap.Data)
+
+		readImages, err := ImagesFromConfigMap(configMap)
+		require.NoError(t, err)
+		assert.Equal(t, images, readImages)
+	})
+
+	t.Run("unknown component", func(t *testing.T) {
+		_, err := ImagesFromConfigMap(newLockConfigMap(map[string]string{"operator": testOneAgentImage}))
+		require.Error(t, err)
+	})
+
+	t.Run("empty lock", func(t *testing.T) {
+		_, err := ImagesFromConfigMap(newLockConfigMap(map[string]string{}))
+		require.Error(t, err)
+	})
+
+	t.Run("code modules version is not an image", func(t *testing.T) {
+		images, err := ImagesFromConfigMap(newLockConfigMap(map[string]string{"oneAgent": testOneAgentImage, "codeModulesVersion": "1.303.0.20241112-123456"}))
+		require.NoError(t, err)
+		assert.Equal(t, "1.303.0.20241112-123456", images.CodeModulesVersion)
+	})
+
+	t.Run("image without digest", func(t *testing.T) {
+		_, err := ImagesFromConfigMap(newLockConfigMap(map[string]string{"kspm": "public.ecr.aws/dynatrace/dynatrace-k8s-node-config-collector:latest"}))
+		require.ErrorContains(t, err, "not pinned by its digest")
+	})
+}
+
+func newLockConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: testLockName, Namespace: testNamespace},
+		Data:       data,
+	}
+}
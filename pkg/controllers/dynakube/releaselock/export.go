@@ -0,0 +1,139 @@
+package releaselock
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/extension/eec"
+	kspmdaemonset "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/kspm/daemonset"
+	logmondaemonset "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/logmonitoring/daemonset"
+	otelcstatefulset "github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/otelc/statefulset"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/dockerkeychain"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DigestResolver looks up the digest of the image in its registry.
+type DigestResolver func(ctx context.Context, image string) (digest.Digest, error)
+
+// Export collects the images of the enabled components of the DynaKube, as they are currently deployed, pinned by their digests.
+// The images are exported with their references before any registry mirror, so the lock can be applied on clusters with other mirrors or none.
+// OneAgent, code modules and ActiveGate are taken from the resolved versions in the status, so the DynaKube has to be reconciled before.
+// Code modules downloaded from the tenant are pinned by their version, a CodeModules bundle is not part of the lock.
+func Export(ctx context.Context, dk *dynakube.DynaKube, resolve DigestResolver) (releaselock.Images, error) {
+	var images releaselock.Images
+
+	components := []struct {
+		target  *string
+		name    string
+		image   string
+		enabled bool
+	}{
+		{&images.OneAgent, "oneAgent", dk.OneAgent().GetImage(), dk.OneAgent().IsDaemonsetRequired()},
+		{&images.CodeModules, "codeModules", dk.OneAgent().GetCodeModulesImage(), dk.OneAgent().IsAppInjectionNeeded()},
+		{&images.ActiveGate, "activeGate", dk.Status.ActiveGate.GetImage(), dk.ActiveGate().IsEnabled()},
+		{&images.ExtensionExecutionController, "extensionExecutionController", eec.Image(dk), dk.IsExtensionsEnabled()},
+		{&images.OpenTelemetryCollector, "openTelemetryCollector", otelcstatefulset.Image(dk), dk.IsExtensionsEnabled() || dk.TelemetryIngest().IsEnabled()},
+		{&images.LogMonitoring, "logMonitoring", logmondaemonset.Image(dk), dk.LogMonitoring().IsStandalone()},
+		{&images.KSPM, "kspm", kspmdaemonset.Image(dk), dk.KSPM().IsEnabled()},
+	}
+
+	for _, component := range components {
+		if !component.enabled || component.image == "" {
+			continue
+		}
+
+		pinned, err := pinImage(ctx, dk, component.image, resolve)
+		if err != nil {
+			return images, errors.WithMessagef(err, "failed to pin the image of %s", component.name)
+		}
+
+		*component.target = pinned
+	}
+
+	if isCodeModulesDownload(dk) {
+		images.CodeModulesVersion = dk.OneAgent().GetCodeModulesVersion()
+		if images.CodeModulesVersion == "" {
+			return images, errors.Errorf("code modules of DynaKube %s are not resolved yet", dk.Name)
+		}
+	}
+
+	if images == (releaselock.Images{}) {
+		return images, errors.Errorf("no image of DynaKube %s could be pinned, are its versions already resolved?", dk.Name)
+	}
+
+	return images, nil
+}
+
+// isCodeModulesDownload returns true if the code modules are downloaded from the tenant, instead of being pulled as an image or unpacked from a bundle.
+// Without a resolved image in the status, the code modules are downloaded.
+func isCodeModulesDownload(dk *dynakube.DynaKube) bool {
+	return dk.OneAgent().IsAppInjectionNeeded() && dk.OneAgent().GetCodeModulesImage() == "" && dk.OneAgent().GetCodeModulesBundle() == nil
+}
+
+// pinImage returns the reference of the image before the registry mirror with its digest.
+// The digest is looked up where the components pull the image from, as the mirror might be the only reachable registry, a mirror keeps the digests of the images.
+func pinImage(ctx context.Context, dk *dynakube.DynaKube, image string, resolve DigestResolver) (string, error) {
+	upstream, err := dk.RestoreImage(image)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if _, ok := ref.(name.Digest); ok {
+		return upstream, nil
+	}
+
+	if _, ok := ref.(name.Tag); !ok {
+		return "", errors.Errorf("image %s has neither a tag nor a digest", image)
+	}
+
+	upstreamRef, err := name.NewTag(upstream)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	imageDigest, err := resolve(ctx, image)
+	if err != nil {
+		return "", err
+	}
+
+	return registry.BuildImageIDWithTagAndDigest(upstreamRef, imageDigest), nil
+}
+
+// NewDigestResolverForDynaKube creates a DigestResolver accessing the registries with the proxy, trusted CAs and pull secrets of the DynaKube.
+func NewDigestResolverForDynaKube(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube) (DigestResolver, error) {
+	transport, err := registry.PrepareTransportForDynaKube(ctx, apiReader, http.DefaultTransport.(*http.Transport).Clone(), dk)
+	if err != nil {
+		return nil, err
+	}
+
+	keychain, err := dockerkeychain.NewDockerKeychains(ctx, apiReader, dk.Namespace, dk.PullSecretNames())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, image string) (digest.Digest, error) {
+		ref, err := name.ParseReference(image)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		descriptor, err := remote.Head(ref, remote.WithContext(ctx), remote.WithTransport(transport), remote.WithAuthFromKeychain(keychain))
+		if err != nil {
+			return "", errors.WithMessagef(err, "failed to get the digest of %s", image)
+		}
+
+		return digest.Digest(descriptor.Digest.String()), nil
+	}, nil
+}
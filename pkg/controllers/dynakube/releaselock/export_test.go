@@ -0,0 +1,120 @@
+package releaselock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/registrymirror"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/kspm"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExport(t *testing.T) {
+	resolve := func(_ context.Context, _ string) (digest.Digest, error) {
+		return testDigest, nil
+	}
+
+	createDynakube := func() *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: testNamespace},
+			Spec: dynakube.DynaKubeSpec{
+				APIURL:   "https://tenant.dev.dynatracelabs.com/api",
+				OneAgent: oneagent.Spec{HostMonitoring: &oneagent.HostInjectSpec{}},
+				ActiveGate: activegate.Spec{
+					Capabilities: []activegate.CapabilityDisplayName{activegate.KubeMonCapability.DisplayName},
+				},
+				Kspm: &kspm.Spec{},
+			},
+		}
+		dk.Status.OneAgent.VersionStatus = status.VersionStatus{ImageID: testOneAgentImage}
+		dk.Status.ActiveGate.VersionStatus = status.VersionStatus{ImageID: "tenant.dev.dynatracelabs.com/linux/activegate:1.303.0-raw"}
+
+		return dk
+	}
+
+	t.Run("pins the images of the enabled components", func(t *testing.T) {
+		images, err := Export(t.Context(), createDynakube(), resolve)
+		require.NoError(t, err)
+
+		assert.Equal(t, testOneAgentImage, images.OneAgent)
+		assert.Equal(t, "tenant.dev.dynatracelabs.com/linux/activegate:1.303.0-raw@"+testDigest, images.ActiveGate)
+		assert.Equal(t, "public.ecr.aws/dynatrace/dynatrace-k8s-node-config-collector:latest@"+testDigest, images.KSPM)
+		assert.Empty(t, images.CodeModules)
+		assert.Empty(t, images.ExtensionExecutionController)
+		assert.Empty(t, images.OpenTelemetryCollector)
+		assert.Empty(t, images.LogMonitoring)
+	})
+
+	t.Run("exported lock pins the same images", func(t *testing.T) {
+		dk := createDynakube()
+
+		images, err := Export(t.Context(), dk, resolve)
+		require.NoError(t, err)
+
+		configMap, err := NewConfigMap(testLockName, testNamespace, images)
+		require.NoError(t, err)
+
+		readImages, err := ImagesFromConfigMap(configMap)
+		require.NoError(t, err)
+		assert.Equal(t, images, readImages)
+	})
+
+	t.Run("images are exported without the registry mirror", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Spec.RegistryMirror = &registrymirror.Spec{Rules: []registrymirror.Rule{{From: "public.ecr.aws/dynatrace", To: "registry.corp/dt"}}}
+		dk.Status.OneAgent.VersionStatus = status.VersionStatus{ImageID: dk.RewriteImage(testOneAgentImage)}
+
+		var resolved []string
+
+		images, err := Export(t.Context(), dk, func(_ context.Context, image string) (digest.Digest, error) {
+			resolved = append(resolved, image)
+
+			return testDigest, nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, testOneAgentImage, images.OneAgent)
+		assert.Equal(t, "public.ecr.aws/dynatrace/dynatrace-k8s-node-config-collector:latest@"+testDigest, images.KSPM)
+		assert.Contains(t, resolved, "registry.corp/dt/dynatrace-k8s-node-config-collector:latest")
+	})
+
+	t.Run("code modules downloaded from the tenant are pinned by their version", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Spec.OneAgent = oneagent.Spec{ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{}}
+		dk.Status.CodeModules.VersionStatus = status.VersionStatus{Version: "1.303.0.20241112-123456"}
+
+		images, err := Export(t.Context(), dk, resolve)
+		require.NoError(t, err)
+
+		assert.Equal(t, "1.303.0.20241112-123456", images.CodeModulesVersion)
+		assert.Empty(t, images.CodeModules)
+	})
+
+	t.Run("code modules not resolved yet", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Spec.OneAgent = oneagent.Spec{ApplicationMonitoring: &oneagent.ApplicationMonitoringSpec{}}
+
+		_, err := Export(t.Context(), dk, resolve)
+		require.ErrorContains(t, err, "code modules")
+	})
+
+	t.Run("resolve error", func(t *testing.T) {
+		_, err := Export(t.Context(), createDynakube(), func(_ context.Context, _ string) (digest.Digest, error) {
+			return "", errors.New("registry unavailable")
+		})
+		require.ErrorContains(t, err, "registry unavailable")
+	})
+
+	t.Run("nothing to pin", func(t *testing.T) {
+		_, err := Export(t.Context(), &dynakube.DynaKube{}, resolve)
+		require.Error(t, err)
+	})
+}
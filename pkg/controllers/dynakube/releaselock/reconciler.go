@@ -0,0 +1,89 @@
+package releaselock
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reconciler reads the release lock referenced by the DynaKube into its status,
+// from where the pinned images are used by the version reconciler, the webhook and the CSI driver.
+type Reconciler struct {
+	apiReader client.Reader
+	dk        *dynakube.DynaKube
+}
+
+func NewReconciler(apiReader client.Reader, dk *dynakube.DynaKube) *Reconciler {
+	return &Reconciler{
+		apiReader: apiReader,
+		dk:        dk,
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	if r.dk.Spec.ReleaseLock == "" {
+		if r.dk.Status.ReleaseLock != nil {
+			log.Info("release lock removed, components are no longer pinned", "dynakube", r.dk.Name)
+		}
+
+		r.dk.Status.ReleaseLock = nil
+		meta.RemoveStatusCondition(r.dk.Conditions(), ConditionType)
+
+		return nil
+	}
+
+	var configMap corev1.ConfigMap
+
+	err := r.apiReader.Get(ctx, client.ObjectKey{Name: r.dk.Spec.ReleaseLock, Namespace: r.dk.Namespace}, &configMap)
+	if err != nil {
+		conditions.SetKubeApiError(r.dk.Conditions(), ConditionType, err)
+
+		return errors.WithMessagef(err, "failed to get release lock %s", r.dk.Spec.ReleaseLock)
+	}
+
+	images, err := ImagesFromConfigMap(&configMap)
+	if err != nil {
+		setInvalidReleaseLockCondition(r.dk.Conditions(), err)
+
+		return err
+	}
+
+	if r.dk.Status.ReleaseLock == nil || r.dk.Status.ReleaseLock.Images != images {
+		log.Info("components are pinned to release lock", "dynakube", r.dk.Name, "releaseLock", configMap.Name)
+	}
+
+	r.dk.Status.ReleaseLock = &releaselock.Status{
+		ConfigMap: configMap.Name,
+		Images:    images,
+	}
+	setPinnedCondition(r.dk.Conditions(), configMap.Name)
+
+	return nil
+}
+
+func setInvalidReleaseLockCondition(conditions *[]metav1.Condition, err error) {
+	condition := metav1.Condition{
+		Type:    ConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  invalidReleaseLockReason,
+		Message: err.Error(),
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
+
+func setPinnedCondition(conditions *[]metav1.Condition, configMapName string) {
+	condition := metav1.Condition{
+		Type:    ConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  pinnedReason,
+		Message: "Components are pinned to the images of release lock " + configMapName,
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
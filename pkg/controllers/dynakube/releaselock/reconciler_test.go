@@ -0,0 +1,95 @@
+package releaselock
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/releaselock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcile(t *testing.T) {
+	createDynakube := func(releaseLock string) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			ObjectMeta: metav1.ObjectMeta{Name: "dynakube", Namespace: testNamespace},
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent:    oneagent.Spec{CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{}},
+				ReleaseLock: releaseLock,
+			},
+		}
+	}
+
+	t.Run("pins the images of the lock", func(t *testing.T) {
+		dk := createDynakube(testLockName)
+		lock := newLockConfigMap(map[string]string{"oneAgent": testOneAgentImage, "codeModules": testActiveGateImage})
+
+		err := NewReconciler(fake.NewClient(lock), dk).Reconcile(t.Context())
+		require.NoError(t, err)
+
+		require.NotNil(t, dk.Status.ReleaseLock)
+		assert.Equal(t, testLockName, dk.Status.ReleaseLock.ConfigMap)
+		assert.Equal(t, testOneAgentImage, dk.OneAgent().GetCustomImage())
+		assert.Equal(t, testActiveGateImage, dk.OneAgent().GetCustomCodeModulesImage())
+		assert.Empty(t, dk.ActiveGate().GetCustomImage())
+
+		condition := meta.FindStatusCondition(*dk.Conditions(), ConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	})
+
+	t.Run("pins the version of the code modules of the lock", func(t *testing.T) {
+		dk := createDynakube(testLockName)
+		lock := newLockConfigMap(map[string]string{"codeModulesVersion": "1.303.0.20241112-123456"})
+
+		err := NewReconciler(fake.NewClient(lock), dk).Reconcile(t.Context())
+		require.NoError(t, err)
+
+		assert.Equal(t, "1.303.0.20241112-123456", dk.OneAgent().GetCustomCodeModulesVersion())
+		assert.Empty(t, dk.OneAgent().GetCustomCodeModulesImage())
+	})
+
+	t.Run("missing lock is an error", func(t *testing.T) {
+		dk := createDynakube(testLockName)
+
+		err := NewReconciler(fake.NewClient(), dk).Reconcile(t.Context())
+		require.Error(t, err)
+
+		assert.Nil(t, dk.Status.ReleaseLock)
+
+		condition := meta.FindStatusCondition(*dk.Conditions(), ConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	})
+
+	t.Run("invalid lock keeps the previous images", func(t *testing.T) {
+		dk := createDynakube(testLockName)
+		dk.Status.ReleaseLock = &releaselock.Status{ConfigMap: testLockName, Images: releaselock.Images{OneAgent: testOneAgentImage}}
+		lock := newLockConfigMap(map[string]string{"oneAgent": "public.ecr.aws/dynatrace/dynatrace-oneagent:latest"})
+
+		err := NewReconciler(fake.NewClient(lock), dk).Reconcile(t.Context())
+		require.Error(t, err)
+
+		assert.Equal(t, testOneAgentImage, dk.OneAgent().GetCustomImage())
+
+		condition := meta.FindStatusCondition(*dk.Conditions(), ConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, invalidReleaseLockReason, condition.Reason)
+	})
+
+	t.Run("removing the lock unpins the images", func(t *testing.T) {
+		dk := createDynakube("")
+		dk.Status.ReleaseLock = &releaselock.Status{ConfigMap: testLockName, Images: releaselock.Images{OneAgent: testOneAgentImage}}
+
+		err := NewReconciler(fake.NewClient(), dk).Reconcile(t.Context())
+		require.NoError(t, err)
+
+		assert.Nil(t, dk.Status.ReleaseLock)
+		assert.Empty(t, dk.OneAgent().GetCustomImage())
+		assert.Nil(t, meta.FindStatusCondition(*dk.Conditions(), ConditionType))
+	})
+}
@@ -0,0 +1,144 @@
+package dynakube
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// componentSchedule keeps track of when each component of a DynaKube needs to be reconciled next.
+// A nil schedule considers every component as due, so every component is reconciled on every pass.
+type componentSchedule struct {
+	timeProvider *timeprovider.Provider
+	entries      map[types.NamespacedName]scheduleEntry
+	mutex        sync.Mutex
+}
+
+type scheduleEntry struct {
+	deadlines  map[string]time.Time
+	generation int64
+}
+
+func newComponentSchedule(timeProvider *timeprovider.Provider) *componentSchedule {
+	return &componentSchedule{
+		timeProvider: timeProvider,
+		entries:      map[types.NamespacedName]scheduleEntry{},
+	}
+}
+
+func (schedule *componentSchedule) now() time.Time {
+	if schedule == nil {
+		return time.Now()
+	}
+
+	return schedule.timeProvider.Now().Time
+}
+
+// isDue returns true if the deadline of the component has been reached,
+// or if the DynaKube has changed since the deadline was set.
+func (schedule *componentSchedule) isDue(dk *dynakube.DynaKube, name string) bool {
+	if schedule == nil {
+		return true
+	}
+
+	schedule.mutex.Lock()
+	defer schedule.mutex.Unlock()
+
+	entry, ok := schedule.entries[client.ObjectKeyFromObject(dk)]
+	if !ok || entry.generation != dk.Generation {
+		return true
+	}
+
+	deadline, ok := entry.deadlines[name]
+
+	return !ok || !deadline.After(schedule.now())
+}
+
+func (schedule *componentSchedule) set(dk *dynakube.DynaKube, name string, deadline time.Time) {
+	if schedule == nil {
+		return
+	}
+
+	schedule.mutex.Lock()
+	defer schedule.mutex.Unlock()
+
+	key := client.ObjectKeyFromObject(dk)
+
+	entry, ok := schedule.entries[key]
+	if !ok || entry.generation != dk.Generation {
+		entry = scheduleEntry{
+			deadlines:  map[string]time.Time{},
+			generation: dk.Generation,
+		}
+	}
+
+	entry.deadlines[name] = deadline
+	schedule.entries[key] = entry
+}
+
+// requeueAfter returns the time until the earliest deadline of the DynaKube,
+// bounded by the fast and default update intervals.
+func (schedule *componentSchedule) requeueAfter(dk *dynakube.DynaKube) time.Duration {
+	if schedule == nil {
+		return defaultUpdateInterval
+	}
+
+	schedule.mutex.Lock()
+	defer schedule.mutex.Unlock()
+
+	requeueAfter := defaultUpdateInterval
+
+	for _, deadline := range schedule.entries[client.ObjectKeyFromObject(dk)].deadlines {
+		requeueAfter = min(requeueAfter, deadline.Sub(schedule.now()))
+	}
+
+	return max(requeueAfter, fastUpdateInterval)
+}
+
+// invalidate makes every component of the DynaKube due on the next reconcile.
+func (schedule *componentSchedule) invalidate(key types.NamespacedName) {
+	if schedule == nil {
+		return
+	}
+
+	schedule.mutex.Lock()
+	defer schedule.mutex.Unlock()
+
+	delete(schedule.entries, key)
+}
+
+// invalidatingPredicate invalidates the schedule of the owning DynaKube whenever an owned object changes,
+// so a drifted or deleted object is corrected right away instead of on the next deadline.
+func (schedule *componentSchedule) invalidatingPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		owner := metav1.GetControllerOf(object)
+		if owner != nil && owner.Kind == "DynaKube" {
+			schedule.invalidate(types.NamespacedName{Name: owner.Name, Namespace: object.GetNamespace()})
+		}
+
+		return true
+	})
+}
+
+// earliest returns the earliest non-zero time, or the zero time if there is none.
+func earliest(times ...time.Time) time.Time {
+	var result time.Time
+
+	for _, t := range times {
+		if t.IsZero() {
+			continue
+		}
+
+		if result.IsZero() || t.Before(result) {
+			result = t
+		}
+	}
+
+	return result
+}
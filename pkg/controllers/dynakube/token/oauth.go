@@ -0,0 +1,141 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/clients/utils"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/pkg/errors"
+)
+
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	jwtTokenType           = "urn:ietf:params:oauth:token-type:jwt"
+
+	// accessTokenRenewBefore keeps access tokens from expiring while they are in use.
+	accessTokenRenewBefore = time.Minute
+)
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	Scope       string `json:"scope"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type accessToken struct {
+	expiresAt time.Time
+	value     string
+	scopes    []string
+}
+
+// accessTokens caches the access tokens per OAuth source, so they are only exchanged again shortly before they expire.
+// The lock only guards the map, the exchange itself runs without it, so a slow token endpoint does not block the other sources.
+var accessTokens = struct {
+	entries map[string]accessToken
+	sync.Mutex
+}{entries: map[string]accessToken{}}
+
+// exchangeOAuthToken exchanges the projected service account token for a Dynatrace OAuth access token (RFC 8693).
+// The service account token is read on every exchange, as the kubelet rotates it.
+func exchangeOAuthToken(ctx context.Context, httpClient *http.Client, spec *tokensource.OAuth) (*Token, error) {
+	if !installconfig.GetTokenSource().IsAllowedOAuthTokenURL(spec.GetTokenURL()) {
+		return nil, errors.Errorf("token endpoint '%s' is neither the Dynatrace SSO nor allowed during install", spec.GetTokenURL())
+	}
+
+	key := strings.Join([]string{spec.GetTokenURL(), spec.ClientID, spec.Resource, strings.Join(spec.Scopes, " ")}, "|")
+
+	accessTokens.Lock()
+	cached, ok := accessTokens.entries[key]
+	accessTokens.Unlock()
+
+	if !ok || time.Now().Add(accessTokenRenewBefore).After(cached.expiresAt) {
+		exchanged, err := requestAccessToken(ctx, httpClient, spec)
+		if err != nil {
+			return nil, err
+		}
+
+		cached = exchanged
+
+		accessTokens.Lock()
+		accessTokens.entries[key] = cached
+		accessTokens.Unlock()
+	}
+
+	return &Token{
+		Type:     dtclient.ApiToken,
+		Value:    cached.value,
+		Features: make([]Feature, 0),
+		Scopes:   cached.scopes,
+		Bearer:   true,
+	}, nil
+}
+
+func requestAccessToken(ctx context.Context, httpClient *http.Client, spec *tokensource.OAuth) (accessToken, error) {
+	subjectToken, err := os.ReadFile(serviceAccountTokenPath)
+	if err != nil {
+		return accessToken{}, errors.WithMessage(err, "failed to read projected service account token")
+	}
+
+	form := url.Values{
+		"grant_type":         {tokenExchangeGrantType},
+		"subject_token":      {strings.TrimSpace(string(subjectToken))},
+		"subject_token_type": {jwtTokenType},
+		"client_id":          {spec.ClientID},
+	}
+
+	if len(spec.Scopes) > 0 {
+		form.Set("scope", strings.Join(spec.Scopes, " "))
+	}
+
+	if spec.Resource != "" {
+		form.Set("resource", spec.Resource)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, spec.GetTokenURL(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return accessToken{}, errors.WithMessage(err, "error initializing http request")
+	}
+
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return accessToken{}, errors.WithMessage(err, "failed to exchange service account token")
+	}
+
+	defer utils.CloseBodyAfterRequest(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return accessToken{}, errors.Errorf("failed to exchange service account token for client '%s', status code: %d", spec.ClientID, resp.StatusCode)
+	}
+
+	var token accessTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return accessToken{}, errors.WithMessage(err, "failed to parse access token response")
+	}
+
+	if token.AccessToken == "" {
+		return accessToken{}, errors.New("token endpoint returned no access token")
+	}
+
+	scopes := strings.Fields(token.Scope)
+	if len(scopes) == 0 {
+		// the granted scopes are only returned if they differ from the requested ones
+		scopes = spec.Scopes
+	}
+
+	return accessToken{
+		value:     token.AccessToken,
+		scopes:    scopes,
+		expiresAt: time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
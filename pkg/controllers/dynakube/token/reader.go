@@ -37,7 +37,17 @@ func (reader Reader) ReadTokens(ctx context.Context) (Tokens, error) {
 	return tokens, nil
 }
 
+// ReadUnverifiedTokens returns the tokens without verifying that the API token exists,
+// for consumers that only pass on some of the tokens.
+func (reader Reader) ReadUnverifiedTokens(ctx context.Context) (Tokens, error) {
+	return reader.readTokens(ctx)
+}
+
 func (reader Reader) readTokens(ctx context.Context) (Tokens, error) {
+	if reader.dk.Spec.TokenSource != nil {
+		return readFromSource(ctx, reader.dk.Spec.TokenSource)
+	}
+
 	var tokenSecret corev1.Secret
 
 	result := make(Tokens)
@@ -63,6 +73,10 @@ func (reader Reader) verifyApiTokenExists(tokens Tokens) error {
 	apiToken, hasApiToken := tokens[dtclient.ApiToken]
 
 	if !hasApiToken || len(apiToken.Value) == 0 {
+		if reader.dk.Spec.TokenSource != nil {
+			return errors.New("the API token is missing from the token source")
+		}
+
 		return errors.New(fmt.Sprintf("the API token is missing from the token secret '%s:%s'", reader.dk.Namespace, reader.dk.Tokens()))
 	}
 
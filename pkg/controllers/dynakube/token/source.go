@@ -0,0 +1,95 @@
+package token
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/pkg/errors"
+)
+
+const sourceRequestTimeout = 10 * time.Second
+
+var (
+	sourceHttpClient = &http.Client{Timeout: sourceRequestTimeout}
+
+	// fileSourceBaseDir limits the files that can be read by a file source, so a DynaKube can't read arbitrary files of the operator
+	fileSourceBaseDir = tokensource.FileBasePath
+
+	// serviceAccountTokenPath is only changed by tests, the token is always read from where the Helm chart mounts it
+	serviceAccountTokenPath = tokensource.ServiceAccountTokenPath
+)
+
+// readFromSource returns the tokens provided by the token source of the DynaKube.
+// The tokens are read from the file or vault source, the API token is replaced by the access token of the OAuth source.
+func readFromSource(ctx context.Context, spec *tokensource.Spec) (Tokens, error) {
+	result := make(Tokens)
+
+	var (
+		values map[string]string
+		err    error
+	)
+
+	switch {
+	case spec.File != nil:
+		values, err = readFileSource(spec.File)
+	case spec.Vault != nil:
+		values, err = readVaultSource(ctx, sourceHttpClient, spec.Vault)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for tokenType, value := range values {
+		token := newToken(tokenType, value)
+		result[tokenType] = &token
+	}
+
+	if spec.OAuth != nil {
+		accessToken, err := exchangeOAuthToken(ctx, sourceHttpClient, spec.OAuth)
+		if err != nil {
+			return nil, err
+		}
+
+		result[dtclient.ApiToken] = accessToken
+	}
+
+	return result, nil
+}
+
+// readFileSource reads one token per file of the directory.
+// Hidden files are skipped, they are the bookkeeping of the atomic writer used for projected and CSI volumes.
+func readFileSource(spec *tokensource.File) (map[string]string, error) {
+	if !strings.HasPrefix(filepath.Clean(spec.Path), fileSourceBaseDir+"/") {
+		return nil, errors.Errorf("token directory '%s' is not below '%s'", spec.Path, fileSourceBaseDir)
+	}
+
+	entries, err := os.ReadDir(spec.Path)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read token directory '%s'", spec.Path)
+	}
+
+	values := make(map[string]string, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(spec.Path, entry.Name()))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to read token file '%s'", entry.Name())
+		}
+
+		// templates of the Vault Agent and most editors end files with a newline, it is not part of the token
+		values[entry.Name()] = strings.TrimSuffix(strings.TrimSuffix(string(raw), "\n"), "\r")
+	}
+
+	return values, nil
+}
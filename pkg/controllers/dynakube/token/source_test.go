@@ -0,0 +1,242 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFromSource(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("file source", func(t *testing.T) {
+		dir := createTokenDir(t, map[string]string{
+			dtclient.ApiToken:  testApiToken + "\n",
+			dtclient.PaasToken: testPaasToken,
+			"..data":           "bookkeeping of the atomic writer",
+		})
+
+		tokens, err := readFromSource(ctx, &tokensource.Spec{File: &tokensource.File{Path: dir}})
+		require.NoError(t, err)
+
+		assert.Len(t, tokens, 2)
+		assert.Equal(t, testApiToken, tokens.ApiToken().Value)
+		assert.Equal(t, testPaasToken, tokens.PaasToken().Value)
+		assert.False(t, tokens.ApiToken().Bearer)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		dir := createTokenDir(t, nil)
+
+		_, err := readFromSource(ctx, &tokensource.Spec{File: &tokensource.File{Path: filepath.Join(dir, "missing")}})
+		require.Error(t, err)
+	})
+
+	t.Run("directory outside of the token volumes", func(t *testing.T) {
+		createTokenDir(t, nil)
+
+		_, err := readFromSource(ctx, &tokensource.Spec{File: &tokensource.File{Path: "/etc"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not below")
+	})
+
+	t.Run("vault source", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/kv/data/dynatrace/tokens" || r.Header.Get(vaultRequestHeader) != "true" {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"data": map[string]any{
+						dtclient.ApiToken:        testApiToken,
+						dtclient.DataIngestToken: testDataIngestToken,
+						"version":                1,
+					},
+				},
+			})
+		}))
+		defer server.Close()
+
+		installconfig.SetTokenSourceOverride(t, installconfig.TokenSource{VaultAddress: server.URL})
+
+		tokens, err := readFromSource(ctx, &tokensource.Spec{Vault: &tokensource.Vault{Mount: "kv", Path: "/dynatrace/tokens"}})
+		require.NoError(t, err)
+
+		assert.Len(t, tokens, 2)
+		assert.Equal(t, testApiToken, tokens.ApiToken().Value)
+		assert.Equal(t, testDataIngestToken, tokens.DataIngestToken().Value)
+
+		_, err = readFromSource(ctx, &tokensource.Spec{Vault: &tokensource.Vault{Path: "dynatrace/tokens"}})
+		require.Error(t, err)
+	})
+
+	t.Run("vault source without installed address", func(t *testing.T) {
+		_, err := readFromSource(ctx, &tokensource.Spec{Vault: &tokensource.Vault{Path: "dynatrace/tokens"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no vault address")
+	})
+
+	t.Run("oauth source replaces the api token", func(t *testing.T) {
+		exchanges := 0
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			exchanges++
+
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, tokenExchangeGrantType, r.PostForm.Get("grant_type"))
+			assert.Equal(t, "service-account-jwt", r.PostForm.Get("subject_token"))
+			assert.Equal(t, "dt0s02.client", r.PostForm.Get("client_id"))
+			assert.Equal(t, "DataExport entities.read", r.PostForm.Get("scope"))
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "access-token",
+				"expires_in":   300,
+			})
+		}))
+		defer server.Close()
+
+		dir := createTokenDir(t, map[string]string{
+			dtclient.ApiToken:  testApiToken,
+			dtclient.PaasToken: testPaasToken,
+		})
+		createServiceAccountToken(t)
+
+		allowTokenURLs(t, server.URL)
+
+		spec := &tokensource.Spec{
+			File: &tokensource.File{Path: dir},
+			OAuth: &tokensource.OAuth{
+				ClientID: "dt0s02.client",
+				Scopes:   []string{"DataExport", "entities.read"},
+				TokenURL: server.URL,
+			},
+		}
+
+		tokens, err := readFromSource(ctx, spec)
+		require.NoError(t, err)
+
+		assert.Equal(t, "access-token", tokens.ApiToken().Value)
+		assert.True(t, tokens.ApiToken().Bearer)
+		assert.Equal(t, dtclient.TokenScopes{"DataExport", "entities.read"}, tokens.ApiToken().Scopes)
+		assert.Equal(t, testPaasToken, tokens.PaasToken().Value)
+
+		_, err = readFromSource(ctx, spec)
+		require.NoError(t, err)
+		assert.Equal(t, 1, exchanges, "access token is cached until it expires")
+	})
+
+	t.Run("failed oauth exchange", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		createServiceAccountToken(t)
+
+		_, err := readFromSource(ctx, &tokensource.Spec{OAuth: &tokensource.OAuth{
+			ClientID: "dt0s02.other",
+			TokenURL: server.URL,
+		}})
+		require.Error(t, err)
+	})
+
+	t.Run("slow oauth exchange does not block other sources", func(t *testing.T) {
+		requested := make(chan struct{})
+		release := make(chan struct{})
+		slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(requested)
+			<-release
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer slowServer.Close()
+		defer close(release)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "fast-access-token",
+				"expires_in":   300,
+			})
+		}))
+		defer server.Close()
+
+		createServiceAccountToken(t)
+		allowTokenURLs(t, slowServer.URL, server.URL)
+
+		go func() {
+			_, _ = readFromSource(ctx, &tokensource.Spec{OAuth: &tokensource.OAuth{
+				ClientID: "dt0s02.slow",
+				TokenURL: slowServer.URL,
+			}})
+		}()
+
+		<-requested
+
+		tokens, err := readFromSource(ctx, &tokensource.Spec{OAuth: &tokensource.OAuth{
+			ClientID: "dt0s02.fast",
+			TokenURL: server.URL,
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, "fast-access-token", tokens.ApiToken().Value)
+	})
+
+	t.Run("token endpoint not allowed", func(t *testing.T) {
+		createServiceAccountToken(t)
+
+		_, err := readFromSource(ctx, &tokensource.Spec{OAuth: &tokensource.OAuth{
+			ClientID: "dt0s02.client",
+			TokenURL: "https://attacker.example.com/token",
+		}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "neither the Dynatrace SSO nor allowed")
+	})
+}
+
+func allowTokenURLs(t *testing.T, tokenURLs ...string) {
+	t.Helper()
+
+	installconfig.SetTokenSourceOverride(t, installconfig.TokenSource{OAuthTokenURLs: tokenURLs})
+}
+
+// createServiceAccountToken stands in for the projected service account token mounted by the Helm chart.
+func createServiceAccountToken(t *testing.T) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("service-account-jwt"), 0600))
+
+	previousPath := serviceAccountTokenPath
+	serviceAccountTokenPath = path
+
+	t.Cleanup(func() { serviceAccountTokenPath = previousPath })
+}
+
+func createTokenDir(t *testing.T, tokens map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	// the temporary directory stands in for the directory of the token volumes
+	previousBaseDir := fileSourceBaseDir
+	fileSourceBaseDir = filepath.Dir(dir)
+
+	t.Cleanup(func() { fileSourceBaseDir = previousBaseDir })
+
+	for name, value := range tokens {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(value), 0600))
+	}
+
+	return dir
+}
@@ -3,6 +3,7 @@ package token
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
@@ -13,6 +14,15 @@ type Token struct {
 	Type     string
 	Value    string
 	Features []Feature
+
+	// Scopes are set if they are known from the token source, e.g. for OAuth access tokens, instead of being looked up.
+	Scopes dtclient.TokenScopes
+
+	// ExpirationDate is set by verifyScopes, if the token expires.
+	ExpirationDate *time.Time
+
+	// Bearer is set for OAuth access tokens, which are sent as bearer tokens.
+	Bearer bool
 }
 
 func newToken(tokenType string, value string) Token {
@@ -32,9 +42,15 @@ func (token *Token) verifyScopes(ctx context.Context, dtClient dtclient.Client,
 		return nil
 	}
 
-	scopes, err := dtClient.GetTokenScopes(ctx, token.Value)
-	if err != nil {
-		return err
+	scopes := token.Scopes
+	if scopes == nil {
+		info, err := dtClient.GetTokenInfo(ctx, token.Value)
+		if err != nil {
+			return err
+		}
+
+		scopes = info.Scopes
+		token.ExpirationDate = info.ExpirationDate
 	}
 
 	collectedErrors := make([]error, 0)
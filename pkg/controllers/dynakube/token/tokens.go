@@ -3,10 +3,13 @@ package token
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/dynatraceapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Tokens map[string]*Token
@@ -65,6 +68,28 @@ func (tokens Tokens) VerifyScopes(ctx context.Context, dtClient dtclient.Client,
 	return nil
 }
 
+// ExpirationStatus returns the expiration dates of the tokens, that were looked up by VerifyScopes, sorted by type.
+// OAuth access tokens are left out, as they are renewed before they expire.
+func (tokens Tokens) ExpirationStatus() []dynakube.TokenStatus {
+	tokenStatus := []dynakube.TokenStatus{}
+
+	for _, tokenType := range slices.Sorted(maps.Keys(tokens)) {
+		token := tokens[tokenType]
+		if token.Bearer || len(token.Features) == 0 {
+			continue
+		}
+
+		status := dynakube.TokenStatus{Type: tokenType}
+		if token.ExpirationDate != nil {
+			status.ExpirationDate = &metav1.Time{Time: *token.ExpirationDate}
+		}
+
+		tokenStatus = append(tokenStatus, status)
+	}
+
+	return tokenStatus
+}
+
 func (tokens Tokens) VerifyValues() error {
 	valueErrors := make([]error, 0)
 
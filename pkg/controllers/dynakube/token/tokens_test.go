@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
@@ -43,8 +44,11 @@ const (
 	fakeTokenAllAPITokenPermissionsIncludingPaaS = "all-permissions-including-paas"
 	fakeTokenPaas                                = "paas-token"
 	fakeTokenAllDataIngestPermissions            = "all-data-ingest-permissions"
+	fakeTokenExpiring                            = "expiring"
 )
 
+var testExpirationDate = time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+
 func createFakeClient(t *testing.T) *dtclientmock.Client {
 	fakeClient := dtclientmock.NewClient(t)
 
@@ -59,9 +63,12 @@ func createFakeClient(t *testing.T) *dtclientmock.Client {
 		{fakeTokenAllDataIngestPermissions, getAllScopesForDataIngest()},
 	}
 
+	fakeClient.On("GetTokenInfo", mock.Anything, fakeTokenExpiring).
+		Return(dtclient.TokenInfo{Scopes: getAllScopesForPaaSToken(), ExpirationDate: &testExpirationDate}, nil).Maybe()
+
 	for _, tokenScope := range tokenScopes {
-		fakeClient.On("GetTokenScopes", mock.Anything, tokenScope.token).
-			Return(tokenScope.scopes, nil).Maybe()
+		fakeClient.On("GetTokenInfo", mock.Anything, tokenScope.token).
+			Return(dtclient.TokenInfo{Scopes: tokenScope.scopes}, nil).Maybe()
 	}
 
 	return fakeClient
@@ -109,6 +116,16 @@ func TestTokens(t *testing.T) {
 		assert.Empty(t, tokens.DataIngestToken().Features)
 		assert.NoError(t, err)
 	})
+	t.Run("oauth access token, scopes known from the token source => no lookup", func(t *testing.T) {
+		apiToken := Token{Type: dtclient.ApiToken, Value: "access-token", Scopes: getAllScopesForAPIToken(), Bearer: true}
+		tokens := Tokens{
+			dtclient.ApiToken: &apiToken,
+		}
+		tokens = tokens.AddFeatureScopesToTokens()
+		err := tokens.VerifyScopes(context.Background(), dtclientmock.NewClient(t), dynakube.DynaKube{})
+
+		assert.EqualError(t, err, "token 'apiToken' has scope errors: [feature 'Download Installer' is missing scope 'InstallerDownload']")
+	})
 	t.Run("activegate enabled dynakube, no permissions in api token => fail", func(t *testing.T) {
 		dk := dynakube.DynaKube{}
 		dk.Spec.ActiveGate.Capabilities = []activegate.CapabilityDisplayName{
@@ -282,3 +299,31 @@ func TestCheckForDataIngestToken(t *testing.T) {
 		assert.False(t, CheckForDataIngestToken(tokens))
 	})
 }
+
+func TestTokens_ExpirationStatus(t *testing.T) {
+	apiToken := newToken(dtclient.ApiToken, fakeTokenAllAPITokenPermissions)
+	paasToken := newToken(dtclient.PaasToken, fakeTokenExpiring)
+	tokens := Tokens{
+		dtclient.ApiToken:  &apiToken,
+		dtclient.PaasToken: &paasToken,
+	}
+	tokens = tokens.AddFeatureScopesToTokens()
+
+	require.NoError(t, tokens.VerifyScopes(context.Background(), createFakeClient(t), dynakube.DynaKube{}))
+
+	status := tokens.ExpirationStatus()
+	require.Len(t, status, 2)
+
+	assert.Equal(t, dtclient.ApiToken, status[0].Type)
+	assert.Nil(t, status[0].ExpirationDate)
+	assert.Equal(t, dtclient.PaasToken, status[1].Type)
+	assert.Equal(t, testExpirationDate, status[1].ExpirationDate.Time)
+
+	t.Run("oauth access tokens are left out", func(t *testing.T) {
+		apiToken.Bearer = true
+
+		status := tokens.ExpirationStatus()
+		require.Len(t, status, 1)
+		assert.Equal(t, dtclient.PaasToken, status[0].Type)
+	})
+}
@@ -0,0 +1,66 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
+	"github.com/Dynatrace/dynatrace-operator/pkg/clients/utils"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/pkg/errors"
+)
+
+// vaultRequestHeader is required by Vault Agents with require_request_header, it is ignored otherwise.
+const vaultRequestHeader = "X-Vault-Request"
+
+type vaultSecretResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+// readVaultSource reads the tokens from a KV v2 secret through the local Vault Agent, which authenticates the request.
+// The address of the agent is set during install, so a DynaKube can't make the operator send requests to a host of its choice.
+func readVaultSource(ctx context.Context, httpClient *http.Client, spec *tokensource.Vault) (map[string]string, error) {
+	address := installconfig.GetTokenSource().VaultAddress
+	if address == "" {
+		return nil, errors.New("no vault address was set during install")
+	}
+
+	url := strings.TrimSuffix(address, "/") + "/v1/" + strings.Trim(spec.GetMount(), "/") + "/data/" + strings.Trim(spec.Path, "/")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error initializing http request")
+	}
+
+	req.Header.Add(vaultRequestHeader, "true")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read tokens from vault secret '%s'", spec.Path)
+	}
+
+	defer utils.CloseBodyAfterRequest(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to read tokens from vault secret '%s', status code: %d", spec.Path, resp.StatusCode)
+	}
+
+	var secret vaultSecretResponse
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse vault secret '%s'", spec.Path)
+	}
+
+	values := make(map[string]string, len(secret.Data.Data))
+
+	for key, value := range secret.Data.Data {
+		if token, ok := value.(string); ok {
+			values[key] = token
+		}
+	}
+
+	return values, nil
+}
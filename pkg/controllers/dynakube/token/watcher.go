@@ -0,0 +1,126 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/hasher"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const defaultWatchInterval = 30 * time.Second
+
+// Watcher polls the token sources of the DynaKubes and triggers a reconcile of a DynaKube as soon as its tokens change,
+// instead of waiting for its next periodic reconcile.
+// Polling is used for all sources, as the files of projected and CSI volumes are replaced by swapping symlinks, which file watches don't follow reliably.
+// The access tokens of OAuth sources are not watched, they are exchanged on demand.
+type Watcher struct {
+	apiReader    client.Reader
+	events       chan event.GenericEvent
+	fingerprints map[types.NamespacedName]string
+	interval     time.Duration
+}
+
+func NewWatcher(apiReader client.Reader) *Watcher {
+	return &Watcher{
+		apiReader:    apiReader,
+		events:       make(chan event.GenericEvent),
+		fingerprints: map[types.NamespacedName]string{},
+		interval:     defaultWatchInterval,
+	}
+}
+
+// Source returns the source the DynaKube controller watches to get the DynaKubes with changed tokens.
+func (watcher *Watcher) Source() source.Source {
+	return source.Channel(watcher.events, &handler.EnqueueRequestForObject{})
+}
+
+// Start polls the token sources until the context is done, it implements manager.Runnable.
+func (watcher *Watcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, dk := range watcher.poll(ctx) {
+				select {
+				case watcher.events <- event.GenericEvent{Object: dk}:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// poll returns the DynaKubes whose tokens changed since the last poll.
+func (watcher *Watcher) poll(ctx context.Context) []*dynakube.DynaKube {
+	var dkList dynakube.DynaKubeList
+	if err := watcher.apiReader.List(ctx, &dkList); err != nil {
+		log.Info("failed to list dynakubes to watch their token sources", "error", err)
+
+		return nil
+	}
+
+	changed := make([]*dynakube.DynaKube, 0)
+	seen := make(map[types.NamespacedName]bool, len(dkList.Items))
+
+	for i := range dkList.Items {
+		dk := &dkList.Items[i]
+		if dk.Spec.TokenSource == nil || (dk.Spec.TokenSource.File == nil && dk.Spec.TokenSource.Vault == nil) {
+			continue
+		}
+
+		key := types.NamespacedName{Name: dk.Name, Namespace: dk.Namespace}
+		seen[key] = true
+
+		fingerprint, err := watcher.fingerprint(ctx, dk)
+		if err != nil {
+			log.Info("failed to read token source", "dynakube", dk.Name, "error", err)
+
+			continue
+		}
+
+		previous, known := watcher.fingerprints[key]
+		watcher.fingerprints[key] = fingerprint
+
+		if known && previous != fingerprint {
+			log.Info("tokens of token source changed, reconciling", "dynakube", dk.Name)
+
+			changed = append(changed, dk)
+		}
+	}
+
+	for key := range watcher.fingerprints {
+		if !seen[key] {
+			delete(watcher.fingerprints, key)
+		}
+	}
+
+	return changed
+}
+
+func (watcher *Watcher) fingerprint(ctx context.Context, dk *dynakube.DynaKube) (string, error) {
+	spec := *dk.Spec.TokenSource
+	spec.OAuth = nil
+
+	tokens, err := readFromSource(ctx, &spec)
+	if err != nil {
+		return "", err
+	}
+
+	values := make(map[string]string, len(tokens))
+	for tokenType, token := range tokens {
+		values[tokenType] = token.Value
+	}
+
+	return hasher.GenerateHash(values)
+}
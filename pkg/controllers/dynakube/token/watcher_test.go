@@ -0,0 +1,46 @@
+package token
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWatcher(t *testing.T) {
+	ctx := context.Background()
+
+	dir := createTokenDir(t, map[string]string{dtclient.ApiToken: testApiToken})
+
+	watched := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: dynakubeName, Namespace: dynatraceNamespace},
+		Spec: dynakube.DynaKubeSpec{
+			TokenSource: &tokensource.Spec{File: &tokensource.File{Path: dir}},
+		},
+	}
+	unwatched := &dynakube.DynaKube{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret-tokens", Namespace: dynatraceNamespace},
+	}
+
+	watcher := NewWatcher(fake.NewClient(watched, unwatched))
+
+	assert.Empty(t, watcher.poll(ctx), "first poll records the tokens")
+	assert.Empty(t, watcher.poll(ctx), "unchanged tokens")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, dtclient.ApiToken), []byte("rotated-api-token"), 0600))
+
+	changed := watcher.poll(ctx)
+	require.Len(t, changed, 1)
+	assert.Equal(t, watched.Name, changed[0].Name)
+
+	assert.Empty(t, watcher.poll(ctx))
+	assert.Len(t, watcher.fingerprints, 1)
+}
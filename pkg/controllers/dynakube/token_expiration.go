@@ -0,0 +1,139 @@
+package dynakube
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// TokenExpirationConditionType is set while a token expires within one of the warning thresholds or has expired.
+	TokenExpirationConditionType = "TokenExpiration"
+
+	ReasonTokenExpiresSoon = "TokenExpiresSoon"
+	ReasonTokenExpired     = "TokenExpired"
+)
+
+var tokenExpirationMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "dynatrace",
+	Subsystem: "operator",
+	Name:      "token_expiration_timestamp_seconds",
+	Help:      "Expiration date of the tokens of a DynaKube as unix timestamp, tokens that don't expire are left out",
+}, []string{"namespace", "dynakube", "token"})
+
+func init() {
+	metrics.Registry.MustRegister(tokenExpirationMetric)
+}
+
+// reconcileTokenExpiration warns about the tokens in the status of the DynaKube, that expire soon or have expired,
+// via a condition and an event each time a token crosses one of the warning thresholds.
+func (controller *Controller) reconcileTokenExpiration(dk *dynakube.DynaKube) {
+	setTokenExpirationMetric(dk)
+
+	newCondition := tokenExpirationCondition(dk, time.Now())
+	if newCondition == nil {
+		meta.RemoveStatusCondition(&dk.Status.Conditions, TokenExpirationConditionType)
+
+		return
+	}
+
+	oldCondition := meta.FindStatusCondition(dk.Status.Conditions, TokenExpirationConditionType)
+	if areStatusesEqual(oldCondition, *newCondition) {
+		return
+	}
+
+	log.Info("token expiration detected", "dynakube", dk.Name, "namespace", dk.Namespace, "message", newCondition.Message)
+
+	if controller.eventRecorder != nil {
+		controller.eventRecorder.Event(dk, corev1.EventTypeWarning, newCondition.Reason, newCondition.Message)
+	}
+
+	newCondition.LastTransitionTime = metav1.Now()
+	meta.SetStatusCondition(&dk.Status.Conditions, *newCondition)
+}
+
+// tokenExpirationCondition returns nil, if no token expires within the warning thresholds.
+// The message only names the crossed threshold, not the remaining time, so it only changes when the next threshold is crossed.
+func tokenExpirationCondition(dk *dynakube.DynaKube, now time.Time) *metav1.Condition {
+	thresholds := exp.NewFlags(dk.Annotations).GetTokenExpiryWarningThresholds()
+	reason := ReasonTokenExpiresSoon
+	messages := []string{}
+
+	for _, tokenStatus := range dk.Status.DynatraceApi.Tokens {
+		if tokenStatus.ExpirationDate == nil {
+			continue
+		}
+
+		expirationDate := tokenStatus.ExpirationDate.UTC().Format(time.RFC3339)
+		remaining := tokenStatus.ExpirationDate.Sub(now)
+
+		if remaining <= 0 {
+			reason = ReasonTokenExpired
+
+			messages = append(messages, fmt.Sprintf("token '%s' expired at %s", tokenStatus.Type, expirationDate))
+
+			continue
+		}
+
+		if threshold, ok := crossedThreshold(thresholds, remaining); ok {
+			messages = append(messages, fmt.Sprintf("token '%s' expires at %s, in less than %s", tokenStatus.Type, expirationDate, formatThreshold(threshold)))
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:    TokenExpirationConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: strings.Join(messages, "; "),
+	}
+}
+
+// crossedThreshold returns the shortest threshold, that is longer than the remaining time, the thresholds are sorted longest first.
+func crossedThreshold(thresholds []time.Duration, remaining time.Duration) (time.Duration, bool) {
+	for i := len(thresholds) - 1; i >= 0; i-- {
+		if remaining <= thresholds[i] {
+			return thresholds[i], true
+		}
+	}
+
+	return 0, false
+}
+
+func formatThreshold(threshold time.Duration) string {
+	const day = 24 * time.Hour
+
+	switch {
+	case threshold == day:
+		return "1 day"
+	case threshold%day == 0:
+		return fmt.Sprintf("%d days", threshold/day)
+	default:
+		return threshold.String()
+	}
+}
+
+func setTokenExpirationMetric(dk *dynakube.DynaKube) {
+	deleteTokenExpirationMetric(dk.Namespace, dk.Name)
+
+	for _, tokenStatus := range dk.Status.DynatraceApi.Tokens {
+		if tokenStatus.ExpirationDate != nil {
+			tokenExpirationMetric.WithLabelValues(dk.Namespace, dk.Name, tokenStatus.Type).Set(float64(tokenStatus.ExpirationDate.Unix()))
+		}
+	}
+}
+
+func deleteTokenExpirationMetric(namespace, name string) {
+	tokenExpirationMetric.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "dynakube": name})
+}
@@ -0,0 +1,120 @@
+package dynakube
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTokenExpirationCondition(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	createDynakube := func(expirationDates map[string]time.Time) *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace}}
+
+		for _, tokenType := range []string{dtclient.ApiToken, dtclient.PaasToken} {
+			tokenStatus := dynakube.TokenStatus{Type: tokenType}
+			if expirationDate, ok := expirationDates[tokenType]; ok {
+				tokenStatus.ExpirationDate = &metav1.Time{Time: expirationDate}
+			}
+
+			dk.Status.DynatraceApi.Tokens = append(dk.Status.DynatraceApi.Tokens, tokenStatus)
+		}
+
+		return dk
+	}
+
+	t.Run("no condition for tokens that don't expire soon", func(t *testing.T) {
+		dk := createDynakube(map[string]time.Time{dtclient.ApiToken: now.Add(60 * 24 * time.Hour)})
+
+		assert.Nil(t, tokenExpirationCondition(dk, now))
+	})
+
+	t.Run("shortest crossed threshold is named", func(t *testing.T) {
+		dk := createDynakube(map[string]time.Time{dtclient.ApiToken: now.Add(5 * 24 * time.Hour)})
+
+		condition := tokenExpirationCondition(dk, now)
+		require.NotNil(t, condition)
+
+		assert.Equal(t, ReasonTokenExpiresSoon, condition.Reason)
+		assert.Equal(t, "token 'apiToken' expires at 2026-10-06T12:00:00Z, in less than 7 days", condition.Message)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		dk := createDynakube(map[string]time.Time{
+			dtclient.ApiToken:  now.Add(20 * time.Hour),
+			dtclient.PaasToken: now.Add(-time.Hour),
+		})
+
+		condition := tokenExpirationCondition(dk, now)
+		require.NotNil(t, condition)
+
+		assert.Equal(t, ReasonTokenExpired, condition.Reason)
+		assert.Equal(t, "token 'apiToken' expires at 2026-10-02T08:00:00Z, in less than 1 day; token 'paasToken' expired at 2026-10-01T11:00:00Z", condition.Message)
+	})
+
+	t.Run("thresholds from the feature flag", func(t *testing.T) {
+		dk := createDynakube(map[string]time.Time{dtclient.ApiToken: now.Add(5 * 24 * time.Hour)})
+		dk.Annotations = map[string]string{exp.TokenExpiryWarningThresholdsKey: "90m"}
+
+		assert.Nil(t, tokenExpirationCondition(dk, now))
+
+		dk.Status.DynatraceApi.Tokens[0].ExpirationDate = &metav1.Time{Time: now.Add(time.Hour)}
+
+		condition := tokenExpirationCondition(dk, now)
+		require.NotNil(t, condition)
+		assert.Contains(t, condition.Message, "in less than 1h30m0s")
+	})
+}
+
+func TestReconcileTokenExpiration(t *testing.T) {
+	t.Run("event is only recorded when a threshold is crossed", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		controller := &Controller{eventRecorder: recorder}
+
+		dk := &dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace}}
+		dk.Status.DynatraceApi.Tokens = []dynakube.TokenStatus{
+			{Type: dtclient.ApiToken, ExpirationDate: &metav1.Time{Time: time.Now().Add(3 * 24 * time.Hour)}},
+		}
+
+		controller.reconcileTokenExpiration(dk)
+		controller.reconcileTokenExpiration(dk)
+
+		assert.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning TokenExpiresSoon token 'apiToken' expires at")
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, TokenExpirationConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+
+		assert.InDelta(t, float64(dk.Status.DynatraceApi.Tokens[0].ExpirationDate.Unix()),
+			testutil.ToFloat64(tokenExpirationMetric.WithLabelValues(testNamespace, testName, dtclient.ApiToken)), 0)
+	})
+
+	t.Run("condition and metric are removed with the expiration date", func(t *testing.T) {
+		controller := &Controller{}
+
+		dk := &dynakube.DynaKube{ObjectMeta: metav1.ObjectMeta{Name: testName, Namespace: testNamespace}}
+		dk.Status.DynatraceApi.Tokens = []dynakube.TokenStatus{
+			{Type: dtclient.ApiToken, ExpirationDate: &metav1.Time{Time: time.Now().Add(-time.Hour)}},
+		}
+
+		controller.reconcileTokenExpiration(dk)
+		require.NotNil(t, meta.FindStatusCondition(dk.Status.Conditions, TokenExpirationConditionType))
+
+		dk.Status.DynatraceApi.Tokens = []dynakube.TokenStatus{{Type: dtclient.ApiToken}}
+
+		controller.reconcileTokenExpiration(dk)
+		assert.Nil(t, meta.FindStatusCondition(dk.Status.Conditions, TokenExpirationConditionType))
+		assert.Equal(t, 0, testutil.CollectAndCount(tokenExpirationMetric))
+	})
+}
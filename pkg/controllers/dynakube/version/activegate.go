@@ -3,6 +3,7 @@ package version
 import (
 	"context"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
@@ -59,7 +60,7 @@ func (updater activeGateUpdater) CustomImage() string {
 		setVerificationSkippedReasonCondition(updater.dk.Conditions(), activeGateVersionConditionType)
 	}
 
-	return customImage
+	return updater.dk.RewriteImage(customImage)
 }
 
 func (updater activeGateUpdater) CustomVersion() string {
@@ -121,3 +122,7 @@ func (updater activeGateUpdater) ValidateStatus() error {
 
 	return nil
 }
+
+func (updater activeGateUpdater) UpdateSchedule() *maintenance.Schedule {
+	return updater.dk.ActiveGate().UpdateSchedule
+}
@@ -3,6 +3,7 @@ package version
 import (
 	"context"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
@@ -32,11 +33,13 @@ func (updater codeModulesUpdater) Name() string {
 }
 
 func (updater codeModulesUpdater) IsEnabled() bool {
-	if updater.dk.OneAgent().IsAppInjectionNeeded() {
+	// the version of a bundle is only known by the CSI driver, as it is part of the bundle itself
+	if updater.dk.OneAgent().IsAppInjectionNeeded() && updater.dk.OneAgent().GetCodeModulesBundle() == nil {
 		return true
 	}
 
 	updater.dk.Status.CodeModules.VersionStatus = status.VersionStatus{}
+	updater.dk.Status.CodeModules.Rollout = nil
 	_ = meta.RemoveStatusCondition(updater.dk.Conditions(), cmConditionType)
 
 	return false
@@ -52,7 +55,7 @@ func (updater codeModulesUpdater) CustomImage() string {
 		setVerificationSkippedReasonCondition(updater.dk.Conditions(), cmConditionType)
 	}
 
-	return customImage
+	return updater.dk.RewriteImage(customImage)
 }
 
 func (updater codeModulesUpdater) CustomVersion() string {
@@ -120,3 +123,19 @@ func (updater *codeModulesUpdater) UseTenantRegistry(ctx context.Context) error
 func (updater codeModulesUpdater) ValidateStatus() error {
 	return nil
 }
+
+func (updater codeModulesUpdater) IsRolloutEnabled() bool {
+	return updater.dk.OneAgent().IsCodeModulesRolloutEnabled()
+}
+
+func (updater codeModulesUpdater) Rollout() *status.RolloutStatus {
+	return updater.dk.Status.CodeModules.Rollout
+}
+
+func (updater codeModulesUpdater) SetRollout(rollout *status.RolloutStatus) {
+	updater.dk.Status.CodeModules.Rollout = rollout
+}
+
+func (updater codeModulesUpdater) UpdateSchedule() *maintenance.Schedule {
+	return updater.dk.OneAgent().UpdateSchedule
+}
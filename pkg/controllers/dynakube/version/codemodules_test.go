@@ -146,6 +146,34 @@ func TestCodeModulesIsEnabled(t *testing.T) {
 
 		assert.Empty(t, updater.Target())
 	})
+
+	t.Run("not enabled with bundle", func(t *testing.T) {
+		dk := &dynakube.DynaKube{
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent: oneagent.Spec{
+					CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{
+						AppInjectionSpec: oneagent.AppInjectionSpec{
+							CodeModulesBundle: &oneagent.CodeModulesBundleSpec{Path: "/bundles/codemodules"},
+						},
+					},
+				},
+			},
+			Status: dynakube.DynaKubeStatus{
+				CodeModules: oneagent.CodeModulesStatus{
+					VersionStatus: status.VersionStatus{
+						Version: "prev",
+					},
+				},
+			},
+		}
+		setVerifiedCondition(dk.Conditions(), cmConditionType)
+
+		updater := newCodeModulesUpdater(dk, nil)
+
+		require.False(t, updater.IsEnabled())
+		assert.Nil(t, meta.FindStatusCondition(*dk.Conditions(), cmConditionType))
+		assert.Empty(t, updater.Target())
+	})
 }
 
 func TestCodeModulesPublicRegistry(t *testing.T) {
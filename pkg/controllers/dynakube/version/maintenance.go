@@ -0,0 +1,89 @@
+package version
+
+import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// scheduledUpdater is implemented by the updaters, whose automatic updates can be restricted to maintenance windows.
+type scheduledUpdater interface {
+	UpdateSchedule() *maintenance.Schedule
+}
+
+func updateSchedule(updater StatusUpdater) *maintenance.Schedule {
+	if scheduled, ok := updater.(scheduledUpdater); ok {
+		return scheduled.UpdateSchedule()
+	}
+
+	return nil
+}
+
+// isAutomaticUpdate returns true if the version would change only because a new version was released, not because the spec changed.
+func isAutomaticUpdate(updater StatusUpdater, previous status.VersionStatus) bool {
+	if isEmptyVersion(previous) || previous.Source != determineSource(updater) {
+		return false
+	}
+
+	return previous.Source == status.TenantRegistryVersionSource || previous.Source == status.PublicRegistryVersionSource
+}
+
+// isInMaintenanceWindow returns true if the updater has no update schedule, or one of its windows is open.
+func (r *reconciler) isInMaintenanceWindow(updater StatusUpdater) bool {
+	return updateSchedule(updater).IsOpen(r.timeProvider.Now().Time)
+}
+
+// holdForMaintenance keeps the previous version in the target, if a new version was found outside of the maintenance windows.
+// The new version is kept as pending update, and applied with the first probe once a window is open.
+func (r *reconciler) holdForMaintenance(updater StatusUpdater, previous status.VersionStatus, isAutomatic bool) {
+	target := updater.Target()
+	schedule := updateSchedule(updater)
+
+	if schedule == nil || !isAutomatic || isSameVersion(previous, *target) || r.isInMaintenanceWindow(updater) {
+		target.Pending = nil
+
+		return
+	}
+
+	now := r.timeProvider.Now()
+	candidate := *target
+
+	*target = previous
+	target.LastProbeTimestamp = candidate.LastProbeTimestamp
+	target.Source = candidate.Source
+
+	detectedAt := now
+	if previous.Pending != nil && previous.Pending.ImageID == candidate.ImageID && previous.Pending.Version == candidate.Version {
+		detectedAt = previous.Pending.DetectedAt
+	}
+
+	var notBefore *metav1.Time
+	if nextOpening := schedule.NextOpening(now.Time); !nextOpening.IsZero() {
+		notBefore = &metav1.Time{Time: nextOpening}
+	}
+
+	log.Info("new version is pending until the next maintenance window", "updater", updater.Name(), "current", previous.Version, "pending", candidate.Version, "notBefore", notBefore)
+
+	target.Pending = &status.PendingUpdate{
+		DetectedAt: detectedAt,
+		NotBefore:  notBefore,
+		ImageID:    candidate.ImageID,
+		Version:    candidate.Version,
+	}
+}
+
+// hasDuePendingUpdate returns true if a pending update can be applied, because a maintenance window is open or the schedule was removed.
+func (r *reconciler) hasDuePendingUpdate(updater StatusUpdater) bool {
+	return updater.Target().Pending != nil && r.isInMaintenanceWindow(updater)
+}
+
+// nextPendingUpdate returns when the pending update of the version status can be applied, or the zero time if there is none.
+func nextPendingUpdate(versionStatus status.VersionStatus) time.Time {
+	if versionStatus.Pending == nil || versionStatus.Pending.NotBefore == nil {
+		return time.Time{}
+	}
+
+	return versionStatus.Pending.NotBefore.Time
+}
@@ -0,0 +1,165 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestHoldForMaintenance(t *testing.T) {
+	// Wednesday, the window opens at 22:00 UTC
+	now := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC)
+	windowStart := time.Date(2025, time.January, 15, 22, 0, 0, 0, time.UTC)
+
+	previous := status.VersionStatus{Version: "1.2.3.4-5", Source: status.TenantRegistryVersionSource}
+	candidate := status.VersionStatus{Version: "1.2.4.4-5", Source: status.TenantRegistryVersionSource, LastProbeTimestamp: ptr.To(metav1.NewTime(now))}
+
+	createDynakube := func() *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+					UpdateSchedule: &maintenance.Schedule{
+						Windows: []maintenance.Window{{Start: "0 22 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}}},
+					},
+				},
+			},
+		}
+	}
+
+	createReconciler := func(now time.Time) *reconciler {
+		timeProvider := timeprovider.New().Freeze()
+		timeProvider.Set(now)
+
+		return &reconciler{timeProvider: timeProvider}
+	}
+
+	t.Run("new version is pending outside of the window", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Status.OneAgent.VersionStatus = candidate
+		updater := newOneAgentUpdater(dk, nil, nil)
+
+		createReconciler(now).holdForMaintenance(updater, previous, isAutomaticUpdate(updater, previous))
+
+		assert.Equal(t, previous.Version, dk.Status.OneAgent.Version)
+		assert.Equal(t, candidate.LastProbeTimestamp, dk.Status.OneAgent.LastProbeTimestamp)
+		require.NotNil(t, dk.Status.OneAgent.Pending)
+		assert.Equal(t, candidate.Version, dk.Status.OneAgent.Pending.Version)
+		assert.Equal(t, now, dk.Status.OneAgent.Pending.DetectedAt.Time)
+		require.NotNil(t, dk.Status.OneAgent.Pending.NotBefore)
+		assert.Equal(t, windowStart, dk.Status.OneAgent.Pending.NotBefore.UTC())
+	})
+
+	t.Run("detection time is kept for the same pending version", func(t *testing.T) {
+		detectedAt := ptr.To(metav1.NewTime(now.Add(-time.Hour)))
+		dk := createDynakube()
+		dk.Status.OneAgent.VersionStatus = candidate
+		updater := newOneAgentUpdater(dk, nil, nil)
+
+		previousWithPending := previous
+		previousWithPending.Pending = &status.PendingUpdate{DetectedAt: detectedAt, Version: candidate.Version}
+
+		createReconciler(now).holdForMaintenance(updater, previousWithPending, isAutomaticUpdate(updater, previousWithPending))
+
+		require.NotNil(t, dk.Status.OneAgent.Pending)
+		assert.Equal(t, detectedAt, dk.Status.OneAgent.Pending.DetectedAt)
+	})
+
+	t.Run("new version is applied in the window", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Status.OneAgent.VersionStatus = candidate
+		updater := newOneAgentUpdater(dk, nil, nil)
+
+		createReconciler(windowStart.Add(time.Hour)).holdForMaintenance(updater, previous, isAutomaticUpdate(updater, previous))
+
+		assert.Equal(t, candidate.Version, dk.Status.OneAgent.Version)
+		assert.Nil(t, dk.Status.OneAgent.Pending)
+	})
+
+	t.Run("first version is not held back", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Status.OneAgent.VersionStatus = candidate
+		updater := newOneAgentUpdater(dk, nil, nil)
+
+		createReconciler(now).holdForMaintenance(updater, status.VersionStatus{}, isAutomaticUpdate(updater, status.VersionStatus{}))
+
+		assert.Equal(t, candidate.Version, dk.Status.OneAgent.Version)
+		assert.Nil(t, dk.Status.OneAgent.Pending)
+	})
+
+	t.Run("custom version is not held back", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Spec.OneAgent.ClassicFullStack.Version = candidate.Version
+		dk.Status.OneAgent.VersionStatus = candidate
+		dk.Status.OneAgent.Source = status.CustomVersionVersionSource
+		updater := newOneAgentUpdater(dk, nil, nil)
+
+		createReconciler(now).holdForMaintenance(updater, previous, isAutomaticUpdate(updater, previous))
+
+		assert.Equal(t, candidate.Version, dk.Status.OneAgent.Version)
+		assert.Nil(t, dk.Status.OneAgent.Pending)
+	})
+
+	t.Run("no schedule never holds back", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Spec.OneAgent.UpdateSchedule = nil
+		dk.Status.OneAgent.VersionStatus = candidate
+		updater := newOneAgentUpdater(dk, nil, nil)
+
+		createReconciler(now).holdForMaintenance(updater, previous, isAutomaticUpdate(updater, previous))
+
+		assert.Equal(t, candidate.Version, dk.Status.OneAgent.Version)
+		assert.Nil(t, dk.Status.OneAgent.Pending)
+	})
+}
+
+func TestHasDuePendingUpdate(t *testing.T) {
+	now := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	dk := &dynakube.DynaKube{
+		Spec: dynakube.DynaKubeSpec{
+			OneAgent: oneagent.Spec{
+				ClassicFullStack: &oneagent.HostInjectSpec{},
+				UpdateSchedule: &maintenance.Schedule{
+					Windows: []maintenance.Window{{Start: "0 22 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}}},
+				},
+			},
+		},
+	}
+	updater := newOneAgentUpdater(dk, nil, nil)
+	timeProvider := timeprovider.New().Freeze()
+	versionReconciler := reconciler{timeProvider: timeProvider}
+
+	timeProvider.Set(now)
+	assert.False(t, versionReconciler.hasDuePendingUpdate(updater))
+
+	dk.Status.OneAgent.Pending = &status.PendingUpdate{Version: "1.2.4.4-5"}
+	assert.False(t, versionReconciler.hasDuePendingUpdate(updater))
+
+	timeProvider.Set(now.Add(12 * time.Hour))
+	assert.True(t, versionReconciler.hasDuePendingUpdate(updater))
+}
+
+func TestNextUpdateWithPending(t *testing.T) {
+	now := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC)
+	dk := &dynakube.DynaKube{}
+	versionStatus := status.VersionStatus{LastProbeTimestamp: ptr.To(metav1.NewTime(now))}
+	nextProbe := now.Add(dk.ApiRequestThreshold())
+
+	assert.Equal(t, nextProbe, NextUpdate(dk, versionStatus))
+
+	versionStatus.Pending = &status.PendingUpdate{NotBefore: ptr.To(metav1.NewTime(now.Add(time.Minute)))}
+	assert.Equal(t, now.Add(time.Minute), NextUpdate(dk, versionStatus))
+
+	versionStatus.Pending = &status.PendingUpdate{NotBefore: ptr.To(metav1.NewTime(nextProbe.Add(time.Hour)))}
+	assert.Equal(t, nextProbe, NextUpdate(dk, versionStatus))
+}
@@ -0,0 +1,23 @@
+package version
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+)
+
+// rewriteImage redirects the image of the version status to the registry mirror, so every consumer of the status pulls from the mirror.
+func rewriteImage(updater StatusUpdater, dk *dynakube.DynaKube) {
+	target := updater.Target()
+	target.ImageID = dk.RewriteImage(target.ImageID)
+}
+
+// hasRegistryMirrorChanged returns true if the image of the version status is not redirected according to the current registry mirror.
+func hasRegistryMirrorChanged(updater StatusUpdater, dk *dynakube.DynaKube) bool {
+	imageID := updater.Target().ImageID
+	if imageID == "" || dk.RewriteImage(imageID) == imageID {
+		return false
+	}
+
+	log.Info("registry mirror changed, update for version status is needed", "updater", updater.Name())
+
+	return true
+}
@@ -0,0 +1,70 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/registrymirror"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryMirror(t *testing.T) {
+	publicImage := "public.ecr.aws/dynatrace/dynatrace-oneagent:1.2.3.4-5"
+	mirroredImage := "registry.corp/dt/dynatrace-oneagent:1.2.3.4-5"
+
+	createDynakube := func(mirror *registrymirror.Spec) *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent:       oneagent.Spec{CloudNativeFullStack: &oneagent.CloudNativeFullStackSpec{}},
+				RegistryMirror: mirror,
+			},
+		}
+		dk.Status.OneAgent.VersionStatus = status.VersionStatus{ImageID: publicImage}
+
+		return dk
+	}
+
+	mirror := &registrymirror.Spec{Rules: []registrymirror.Rule{{From: "public.ecr.aws/dynatrace", To: "registry.corp/dt"}}}
+
+	t.Run("image of the status is redirected", func(t *testing.T) {
+		dk := createDynakube(mirror)
+		updater := newOneAgentUpdater(dk, nil, nil)
+
+		assert.True(t, hasRegistryMirrorChanged(updater, dk))
+
+		rewriteImage(updater, dk)
+
+		assert.Equal(t, mirroredImage, dk.Status.OneAgent.ImageID)
+		assert.False(t, hasRegistryMirrorChanged(updater, dk))
+	})
+
+	t.Run("operator-wide mirror is used", func(t *testing.T) {
+		installconfig.SetRegistryMirrorOverride(t, *mirror)
+
+		dk := createDynakube(nil)
+		rewriteImage(newOneAgentUpdater(dk, nil, nil), dk)
+
+		assert.Equal(t, mirroredImage, dk.Status.OneAgent.ImageID)
+	})
+
+	t.Run("custom image is redirected", func(t *testing.T) {
+		dk := createDynakube(mirror)
+		dk.Spec.OneAgent.CloudNativeFullStack.Image = publicImage
+
+		assert.Equal(t, mirroredImage, newOneAgentUpdater(dk, nil, nil).CustomImage())
+	})
+
+	t.Run("no mirror", func(t *testing.T) {
+		dk := createDynakube(nil)
+		updater := newOneAgentUpdater(dk, nil, nil)
+
+		assert.False(t, hasRegistryMirrorChanged(updater, dk))
+
+		rewriteImage(updater, dk)
+
+		assert.Equal(t, publicImage, dk.Status.OneAgent.ImageID)
+	})
+}
@@ -3,6 +3,7 @@ package version
 import (
 	"context"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/maintenance"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
@@ -45,6 +46,7 @@ func (updater oneAgentUpdater) IsEnabled() bool {
 
 	updater.dk.Status.OneAgent.VersionStatus = status.VersionStatus{}
 	updater.dk.Status.OneAgent.Healthcheck = nil
+	updater.dk.Status.OneAgent.Rollout = nil
 	_ = meta.RemoveStatusCondition(updater.dk.Conditions(), oaConditionType)
 
 	return updater.dk.OneAgent().IsDaemonsetRequired()
@@ -60,7 +62,7 @@ func (updater oneAgentUpdater) CustomImage() string {
 		setVerificationSkippedReasonCondition(updater.dk.Conditions(), oaConditionType)
 	}
 
-	return customImage
+	return updater.dk.RewriteImage(customImage)
 }
 
 func (updater oneAgentUpdater) CustomVersion() string {
@@ -174,3 +176,19 @@ func (updater oneAgentUpdater) ValidateStatus() error {
 
 	return nil
 }
+
+func (updater oneAgentUpdater) IsRolloutEnabled() bool {
+	return updater.dk.OneAgent().IsOneAgentRolloutEnabled()
+}
+
+func (updater oneAgentUpdater) Rollout() *status.RolloutStatus {
+	return updater.dk.Status.OneAgent.Rollout
+}
+
+func (updater oneAgentUpdater) SetRollout(rollout *status.RolloutStatus) {
+	updater.dk.Status.OneAgent.Rollout = rollout
+}
+
+func (updater oneAgentUpdater) UpdateSchedule() *maintenance.Schedule {
+	return updater.dk.OneAgent().UpdateSchedule
+}
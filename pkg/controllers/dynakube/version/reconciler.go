@@ -3,10 +3,12 @@ package version
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/signature"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -18,8 +20,9 @@ type Reconciler interface {
 }
 
 type reconciler struct {
-	dtClient     dtclient.Client
-	timeProvider *timeprovider.Provider
+	dtClient        dtclient.Client
+	timeProvider    *timeprovider.Provider
+	verifierBuilder verifierBuilder
 
 	apiReader client.Reader
 }
@@ -28,14 +31,17 @@ type ReconcilerBuilder func(apiReader client.Reader, dtClient dtclient.Client, t
 
 func NewReconciler(apiReader client.Reader, dtClient dtclient.Client, timeProvider *timeprovider.Provider) Reconciler {
 	return &reconciler{
-		apiReader:    apiReader,
-		timeProvider: timeProvider,
-		dtClient:     dtClient,
+		apiReader:       apiReader,
+		timeProvider:    timeProvider,
+		dtClient:        dtClient,
+		verifierBuilder: signature.NewVerifierForDynaKube,
 	}
 }
 
 func (r *reconciler) ReconcileCodeModules(ctx context.Context, dk *dynakube.DynaKube) error {
 	updater := newCodeModulesUpdater(dk, r.dtClient)
+	r.promoteRollout(updater)
+
 	if r.needsUpdate(updater, dk) {
 		return r.updateVersionStatuses(ctx, updater, dk)
 	}
@@ -45,6 +51,10 @@ func (r *reconciler) ReconcileCodeModules(ctx context.Context, dk *dynakube.Dyna
 
 func (r *reconciler) ReconcileOneAgent(ctx context.Context, dk *dynakube.DynaKube) error {
 	updater := newOneAgentUpdater(dk, r.apiReader, r.dtClient)
+	if r.promoteRollout(updater) {
+		setOneAgentHealthcheck(dk)
+	}
+
 	if r.needsUpdate(updater, dk) {
 		return r.updateVersionStatuses(ctx, updater, dk)
 	}
@@ -66,6 +76,9 @@ func (r *reconciler) ReconcileActiveGate(ctx context.Context, dk *dynakube.DynaK
 func (r *reconciler) updateVersionStatuses(ctx context.Context, updater StatusUpdater, dk *dynakube.DynaKube) error {
 	log.Info("updating version status", "updater", updater.Name())
 
+	previous := *updater.Target()
+	isAutomatic := isAutomaticUpdate(updater, previous)
+
 	err := r.run(ctx, updater)
 	if err != nil {
 		if updater.Target().ImageID == "" && updater.Target().Version == "" {
@@ -77,19 +90,33 @@ func (r *reconciler) updateVersionStatuses(ctx context.Context, updater StatusUp
 		log.Error(err, "unable to refresh version info, moving on with version from previous run", "component", updater.Name())
 	}
 
+	rewriteImage(updater, dk)
+
+	err = r.verifyImage(ctx, updater, dk, previous)
+	if err != nil {
+		return err
+	}
+
+	r.holdForMaintenance(updater, previous, isAutomatic)
+	r.stageRollout(updater, previous)
+
 	_, ok := updater.(*oneAgentUpdater)
 	if ok {
-		healthConfig, err := getOneAgentHealthConfig(dk.OneAgent().GetVersion())
-		if err != nil {
-			log.Error(err, "could not set OneAgent healthcheck")
-		} else {
-			dk.Status.OneAgent.Healthcheck = healthConfig
-		}
+		setOneAgentHealthcheck(dk)
 	}
 
 	return nil
 }
 
+func setOneAgentHealthcheck(dk *dynakube.DynaKube) {
+	healthConfig, err := getOneAgentHealthConfig(dk.OneAgent().GetVersion())
+	if err != nil {
+		log.Error(err, "could not set OneAgent healthcheck")
+	} else {
+		dk.Status.OneAgent.Healthcheck = healthConfig
+	}
+}
+
 func (r *reconciler) needsUpdate(updater StatusUpdater, dk *dynakube.DynaKube) bool {
 	if !updater.IsEnabled() {
 		log.Info("skipping version status update for disabled section", "updater", updater.Name())
@@ -107,6 +134,16 @@ func (r *reconciler) needsUpdate(updater StatusUpdater, dk *dynakube.DynaKube) b
 		return true
 	}
 
+	if hasRegistryMirrorChanged(updater, dk) {
+		return true
+	}
+
+	if r.hasDuePendingUpdate(updater) {
+		log.Info("maintenance window is open, pending update is applied", "updater", updater.Name())
+
+		return true
+	}
+
 	if !r.timeProvider.IsOutdated(updater.Target().LastProbeTimestamp, dk.ApiRequestThreshold()) {
 		log.Info("status timestamp still valid, skipping version status updater", "updater", updater.Name())
 
@@ -116,9 +153,27 @@ func (r *reconciler) needsUpdate(updater StatusUpdater, dk *dynakube.DynaKube) b
 	return true
 }
 
+// NextUpdate returns when the given version status has to be probed again, or its pending update can be applied.
+// The zero time is returned if it was never probed, as then the section is most likely disabled.
+func NextUpdate(dk *dynakube.DynaKube, versionStatus status.VersionStatus) time.Time {
+	if versionStatus.LastProbeTimestamp == nil {
+		return time.Time{}
+	}
+
+	nextProbe := versionStatus.LastProbeTimestamp.Add(dk.ApiRequestThreshold())
+
+	if pendingUpdate := nextPendingUpdate(versionStatus); !pendingUpdate.IsZero() && pendingUpdate.Before(nextProbe) {
+		return pendingUpdate
+	}
+
+	return nextProbe
+}
+
 func hasCustomFieldChanged(updater StatusUpdater) bool {
+	target := customFieldTarget(updater)
+
 	if updater.Target().Source == status.CustomImageVersionSource {
-		oldImage := updater.Target().ImageID
+		oldImage := target.ImageID
 		newImage := updater.CustomImage()
 		// The old image is can be the same as the new image (if only digest was given, or a tag was given but couldn't get the digest)
 		// or the old image is the same as the new image but with the digest added to the end of it (if a tag was provide, and we could append the digest to the end)
@@ -129,7 +184,7 @@ func hasCustomFieldChanged(updater StatusUpdater) bool {
 			return true
 		}
 	} else if updater.Target().Source == status.CustomVersionVersionSource {
-		oldVersion := updater.Target().Version
+		oldVersion := target.Version
 		newVersion := updater.CustomVersion()
 
 		if oldVersion != newVersion {
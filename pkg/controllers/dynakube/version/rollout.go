@@ -0,0 +1,187 @@
+package version
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// rolloutUpdater is implemented by the updaters, whose new versions can be rolled out to a canary first.
+type rolloutUpdater interface {
+	IsRolloutEnabled() bool
+	Rollout() *status.RolloutStatus
+	SetRollout(rollout *status.RolloutStatus)
+}
+
+// CanaryHealth is the observed state of the canary of a rollout.
+type CanaryHealth struct {
+	// Pods of the canary, their restarts are checked against the maxRestarts of the rollout.
+	Pods []corev1.Pod
+	// Ready is true if the canary is completely deployed and all of its pods are ready.
+	Ready bool
+}
+
+// stageRollout holds back a new version in the target, while its rollout to the canary is in progress.
+// The new version becomes the candidate of the rollout, the previous version stays in the target until the candidate is promoted.
+func (r *reconciler) stageRollout(updater StatusUpdater, previous status.VersionStatus) {
+	rollouts, ok := updater.(rolloutUpdater)
+	if !ok {
+		return
+	}
+
+	if !rollouts.IsRolloutEnabled() {
+		rollouts.SetRollout(nil)
+
+		return
+	}
+
+	target := updater.Target()
+	if isEmptyVersion(previous) || isSameVersion(previous, *target) {
+		return
+	}
+
+	candidate := *target
+	*target = previous
+	target.LastProbeTimestamp = candidate.LastProbeTimestamp
+	target.Source = candidate.Source
+	target.Pending = candidate.Pending
+	candidate.Pending = nil
+
+	rollout := rollouts.Rollout()
+	if rollout != nil && rollout.Phase != status.RolloutPromotedPhase && isSameVersion(rollout.Candidate, candidate) {
+		// the candidate is already rolled out, or failed and must not be retried
+		return
+	}
+
+	log.Info("new version is rolled out to the canary first", "updater", updater.Name(), "previous", previous.Version, "candidate", candidate.Version)
+
+	rollouts.SetRollout(&status.RolloutStatus{
+		StartedAt: r.timeProvider.Now(),
+		Phase:     status.RolloutCanaryPhase,
+		Message:   "Candidate is rolled out to the canary",
+		Candidate: candidate,
+		Previous:  previous,
+	})
+}
+
+// promoteRollout uses the candidate of a verified rollout everywhere, returns true if it was promoted.
+// The promotion waits for the next maintenance window, if the updater has an update schedule.
+func (r *reconciler) promoteRollout(updater StatusUpdater) bool {
+	rollouts, ok := updater.(rolloutUpdater)
+	if !ok || !rollouts.IsRolloutEnabled() || !r.isInMaintenanceWindow(updater) {
+		return false
+	}
+
+	rollout := rollouts.Rollout()
+	if rollout == nil || rollout.Phase != status.RolloutVerifiedPhase {
+		return false
+	}
+
+	log.Info("promoting verified candidate", "updater", updater.Name(), "candidate", rollout.Candidate.Version)
+
+	target := updater.Target()
+	lastProbeTimestamp := target.LastProbeTimestamp
+	pending := target.Pending
+	*target = rollout.Candidate
+	target.LastProbeTimestamp = lastProbeTimestamp
+	target.Pending = pending
+
+	rollout.Phase = status.RolloutPromotedPhase
+	rollout.Message = "Candidate is used everywhere"
+	rollout.CanaryNodes = nil
+
+	return true
+}
+
+// EvaluateCanary checks the health gates of a rollout in the Canary phase.
+// Once the canary was ready for the soak time, without exceeding the restart limit, the rollout is Verified and promoted with the next version update.
+// A canary that doesn't become ready within the timeout, e.g. because no pods were started in the canary namespaces, fails the rollout.
+// If the restart limit is exceeded, the rollout is Paused or RolledBack according to the failure policy.
+func EvaluateCanary(rollout *status.RolloutStatus, oa *oneagent.OneAgent, health CanaryHealth, now time.Time) {
+	if rollout == nil || rollout.Phase != status.RolloutCanaryPhase {
+		return
+	}
+
+	restarts := countRestarts(health.Pods)
+	if restarts > oa.GetRolloutMaxRestarts() {
+		failRollout(rollout, oa.GetRolloutFailurePolicy(), fmt.Sprintf("Canary pods restarted %d times, more than the allowed %d", restarts, oa.GetRolloutMaxRestarts()))
+
+		return
+	}
+
+	if !health.Ready {
+		if rollout.StartedAt != nil && now.After(rollout.StartedAt.Add(oa.GetRolloutTimeout())) {
+			failRollout(rollout, oa.GetRolloutFailurePolicy(), fmt.Sprintf("Canary did not become ready within %s", oa.GetRolloutTimeout()))
+
+			return
+		}
+
+		rollout.Message = "Waiting for the canary to become ready"
+
+		return
+	}
+
+	soakedAt := now
+	if rollout.StartedAt != nil {
+		soakedAt = rollout.StartedAt.Add(oa.GetRolloutSoakTime())
+	}
+
+	if now.Before(soakedAt) {
+		rollout.Message = "Canary is healthy, soaking until " + soakedAt.UTC().Format(time.RFC3339)
+
+		return
+	}
+
+	rollout.Phase = status.RolloutVerifiedPhase
+	rollout.Message = "Canary was healthy for the soak time"
+}
+
+func failRollout(rollout *status.RolloutStatus, policy oneagent.RolloutFailurePolicy, message string) {
+	log.Info("canary failed the health gates", "candidate", rollout.Candidate.Version, "policy", policy, "reason", message)
+
+	rollout.Message = message
+	if policy == oneagent.RolloutRollbackFailurePolicy {
+		rollout.Phase = status.RolloutRolledBackPhase
+		rollout.CanaryNodes = nil
+	} else {
+		rollout.Phase = status.RolloutPausedPhase
+	}
+}
+
+func countRestarts(pods []corev1.Pod) int32 {
+	var restarts int32
+
+	for _, pod := range pods {
+		for _, containerStatus := range pod.Status.InitContainerStatuses {
+			restarts += containerStatus.RestartCount
+		}
+
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			restarts += containerStatus.RestartCount
+		}
+	}
+
+	return restarts
+}
+
+// customFieldTarget is the version status the custom image/version is compared against, which is the candidate while it is rolled out.
+func customFieldTarget(updater StatusUpdater) *status.VersionStatus {
+	if rollouts, ok := updater.(rolloutUpdater); ok {
+		if rollout := rollouts.Rollout(); rollout != nil && rollout.Phase != status.RolloutPromotedPhase {
+			return &rollout.Candidate
+		}
+	}
+
+	return updater.Target()
+}
+
+func isEmptyVersion(versionStatus status.VersionStatus) bool {
+	return versionStatus.ImageID == "" && versionStatus.Version == ""
+}
+
+func isSameVersion(a, b status.VersionStatus) bool {
+	return a.ImageID == b.ImageID && a.Version == b.Version
+}
@@ -0,0 +1,190 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestStageRollout(t *testing.T) {
+	previous := status.VersionStatus{Version: "1.2.3.4-5", Source: status.TenantRegistryVersionSource}
+	candidate := status.VersionStatus{Version: "1.2.4.4-5", Source: status.TenantRegistryVersionSource, LastProbeTimestamp: ptr.To(metav1.Now())}
+
+	createDynakube := func(rollout *oneagent.RolloutSpec) *dynakube.DynaKube {
+		return &dynakube.DynaKube{
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+					Rollout:          rollout,
+				},
+			},
+		}
+	}
+
+	t.Run("new version is held back as candidate", func(t *testing.T) {
+		dk := createDynakube(&oneagent.RolloutSpec{Percentage: ptr.To(int32(10))})
+		dk.Status.OneAgent.VersionStatus = candidate
+		versionReconciler := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		versionReconciler.stageRollout(newOneAgentUpdater(dk, nil, nil), previous)
+
+		assert.Equal(t, previous.Version, dk.Status.OneAgent.Version)
+		assert.Equal(t, candidate.LastProbeTimestamp, dk.Status.OneAgent.LastProbeTimestamp)
+		require.NotNil(t, dk.Status.OneAgent.Rollout)
+		assert.Equal(t, status.RolloutCanaryPhase, dk.Status.OneAgent.Rollout.Phase)
+		assert.Equal(t, candidate.Version, dk.Status.OneAgent.Rollout.Candidate.Version)
+		assert.Equal(t, previous.Version, dk.Status.OneAgent.Rollout.Previous.Version)
+	})
+
+	t.Run("first version is not held back", func(t *testing.T) {
+		dk := createDynakube(&oneagent.RolloutSpec{Percentage: ptr.To(int32(10))})
+		dk.Status.OneAgent.VersionStatus = candidate
+		versionReconciler := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		versionReconciler.stageRollout(newOneAgentUpdater(dk, nil, nil), status.VersionStatus{})
+
+		assert.Equal(t, candidate.Version, dk.Status.OneAgent.Version)
+		assert.Nil(t, dk.Status.OneAgent.Rollout)
+	})
+
+	t.Run("failed candidate is not retried", func(t *testing.T) {
+		dk := createDynakube(&oneagent.RolloutSpec{Percentage: ptr.To(int32(10))})
+		dk.Status.OneAgent.VersionStatus = candidate
+		dk.Status.OneAgent.Rollout = &status.RolloutStatus{Phase: status.RolloutRolledBackPhase, Candidate: candidate, Previous: previous}
+		versionReconciler := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		versionReconciler.stageRollout(newOneAgentUpdater(dk, nil, nil), previous)
+
+		assert.Equal(t, previous.Version, dk.Status.OneAgent.Version)
+		assert.Equal(t, status.RolloutRolledBackPhase, dk.Status.OneAgent.Rollout.Phase)
+	})
+
+	t.Run("rollout is removed if disabled", func(t *testing.T) {
+		dk := createDynakube(nil)
+		dk.Status.OneAgent.VersionStatus = candidate
+		dk.Status.OneAgent.Rollout = &status.RolloutStatus{Phase: status.RolloutPausedPhase}
+		versionReconciler := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+		versionReconciler.stageRollout(newOneAgentUpdater(dk, nil, nil), previous)
+
+		assert.Equal(t, candidate.Version, dk.Status.OneAgent.Version)
+		assert.Nil(t, dk.Status.OneAgent.Rollout)
+	})
+}
+
+func TestPromoteRollout(t *testing.T) {
+	previous := status.VersionStatus{Version: "1.2.3.4-5"}
+	candidate := status.VersionStatus{Version: "1.2.4.4-5"}
+
+	createDynakube := func(phase status.RolloutPhase) *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent: oneagent.Spec{
+					ClassicFullStack: &oneagent.HostInjectSpec{},
+					Rollout:          &oneagent.RolloutSpec{Percentage: ptr.To(int32(10))},
+				},
+			},
+		}
+		dk.Status.OneAgent.VersionStatus = previous
+		dk.Status.OneAgent.Rollout = &status.RolloutStatus{Phase: phase, Candidate: candidate, Previous: previous, CanaryNodes: []string{"node"}}
+
+		return dk
+	}
+
+	versionReconciler := reconciler{timeProvider: timeprovider.New().Freeze()}
+
+	t.Run("verified candidate is promoted", func(t *testing.T) {
+		dk := createDynakube(status.RolloutVerifiedPhase)
+
+		assert.True(t, versionReconciler.promoteRollout(newOneAgentUpdater(dk, nil, nil)))
+		assert.Equal(t, candidate.Version, dk.Status.OneAgent.Version)
+		assert.Equal(t, status.RolloutPromotedPhase, dk.Status.OneAgent.Rollout.Phase)
+		assert.Empty(t, dk.Status.OneAgent.Rollout.CanaryNodes)
+	})
+
+	t.Run("candidate in canary is not promoted", func(t *testing.T) {
+		dk := createDynakube(status.RolloutCanaryPhase)
+
+		assert.False(t, versionReconciler.promoteRollout(newOneAgentUpdater(dk, nil, nil)))
+		assert.Equal(t, previous.Version, dk.Status.OneAgent.Version)
+	})
+}
+
+func TestEvaluateCanary(t *testing.T) {
+	now := time.Now()
+	oa := oneagent.NewOneAgent(&oneagent.Spec{
+		Rollout: &oneagent.RolloutSpec{
+			Percentage:  ptr.To(int32(10)),
+			SoakTime:    &metav1.Duration{Duration: time.Hour},
+			MaxRestarts: ptr.To(int32(1)),
+		},
+	}, &oneagent.Status{}, &oneagent.CodeModulesStatus{}, "", "", false, false, false)
+
+	createRollout := func(startedAt time.Time) *status.RolloutStatus {
+		return &status.RolloutStatus{Phase: status.RolloutCanaryPhase, StartedAt: ptr.To(metav1.NewTime(startedAt))}
+	}
+	restartedPod := corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{RestartCount: 2}}}}
+
+	t.Run("healthy canary is verified after the soak time", func(t *testing.T) {
+		rollout := createRollout(now.Add(-2 * time.Hour))
+
+		EvaluateCanary(rollout, oa, CanaryHealth{Ready: true}, now)
+
+		assert.Equal(t, status.RolloutVerifiedPhase, rollout.Phase)
+	})
+
+	t.Run("healthy canary soaks", func(t *testing.T) {
+		rollout := createRollout(now.Add(-time.Minute))
+
+		EvaluateCanary(rollout, oa, CanaryHealth{Ready: true}, now)
+
+		assert.Equal(t, status.RolloutCanaryPhase, rollout.Phase)
+	})
+
+	t.Run("canary that is not ready waits", func(t *testing.T) {
+		rollout := createRollout(now.Add(-time.Minute))
+
+		EvaluateCanary(rollout, oa, CanaryHealth{}, now)
+
+		assert.Equal(t, status.RolloutCanaryPhase, rollout.Phase)
+	})
+
+	t.Run("canary that is not ready within the timeout is paused", func(t *testing.T) {
+		rollout := createRollout(now.Add(-2 * time.Hour))
+
+		EvaluateCanary(rollout, oa, CanaryHealth{}, now)
+
+		assert.Equal(t, status.RolloutPausedPhase, rollout.Phase)
+		assert.Contains(t, rollout.Message, "did not become ready within 30m0s")
+	})
+
+	t.Run("restarting canary is paused", func(t *testing.T) {
+		rollout := createRollout(now)
+
+		EvaluateCanary(rollout, oa, CanaryHealth{Pods: []corev1.Pod{restartedPod}, Ready: true}, now)
+
+		assert.Equal(t, status.RolloutPausedPhase, rollout.Phase)
+	})
+
+	t.Run("restarting canary is rolled back", func(t *testing.T) {
+		rollbackOa := oneagent.NewOneAgent(&oneagent.Spec{
+			Rollout: &oneagent.RolloutSpec{Percentage: ptr.To(int32(10)), FailurePolicy: oneagent.RolloutRollbackFailurePolicy, MaxRestarts: ptr.To(int32(1))},
+		}, &oneagent.Status{}, &oneagent.CodeModulesStatus{}, "", "", false, false, false)
+		rollout := createRollout(now)
+		rollout.CanaryNodes = []string{"node"}
+
+		EvaluateCanary(rollout, rollbackOa, CanaryHealth{Pods: []corev1.Pod{restartedPod}}, now)
+
+		assert.Equal(t, status.RolloutRolledBackPhase, rollout.Phase)
+		assert.Empty(t, rollout.CanaryNodes)
+	})
+}
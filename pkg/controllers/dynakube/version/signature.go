@@ -0,0 +1,68 @@
+package version
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/signature"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type verifierBuilder func(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube) (*signature.Verifier, error)
+
+// verifyImage checks the image of the version status against the image verification policy of the DynaKube.
+// A verified image is pinned to its digest, an image failing the verification never lands in the status, instead the previous image is kept.
+// If there is no other image to fall back to, an error is returned, so the component is not deployed with an unverified image.
+func (r *reconciler) verifyImage(ctx context.Context, updater StatusUpdater, dk *dynakube.DynaKube, previous status.VersionStatus) error {
+	target := updater.Target()
+	if !dk.ImageVerification().IsEnabled() || target.ImageID == "" {
+		return nil
+	}
+
+	verifier, err := r.verifierBuilder(ctx, r.apiReader, dk)
+	if err == nil {
+		var verifiedImage string
+
+		verifiedImage, err = verifier.Verify(ctx, target.ImageID)
+		if err == nil {
+			// the status points to the verified digest, so the components can't pull different content under the same tag
+			target.ImageID = verifiedImage
+
+			return nil
+		}
+	}
+
+	conditions.SetImageVerificationFailed(dk.Conditions(), versionConditionType(updater), err)
+
+	if previous.ImageID == "" || previous.ImageID == target.ImageID {
+		*target = previous
+
+		return errors.WithMessagef(err, "image of %s failed the verification", updater.Name())
+	}
+
+	log.Info("image failed the verification, moving on with the image from the previous run", "updater", updater.Name(), "image", target.ImageID, "error", err.Error())
+
+	candidate := *target
+
+	*target = previous
+	target.LastProbeTimestamp = candidate.LastProbeTimestamp
+	target.Source = candidate.Source
+
+	return nil
+}
+
+func versionConditionType(updater StatusUpdater) string {
+	switch updater.(type) {
+	case *oneAgentUpdater:
+		return oaConditionType
+	case *codeModulesUpdater:
+		return cmConditionType
+	case *activeGateUpdater:
+		return activeGateVersionConditionType
+	default:
+		return updater.Name()
+	}
+}
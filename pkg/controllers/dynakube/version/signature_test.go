@@ -0,0 +1,89 @@
+package version
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/imageverification"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/signature"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestVerifyImage(t *testing.T) {
+	ctx := context.Background()
+	trustedImage := "public.ecr.aws/dynatrace/dynatrace-oneagent:1.2.3.4-5"
+	untrustedImage := "untrusted.io/dynatrace/dynatrace-oneagent:1.2.4.4-5"
+
+	createDynakube := func(target status.VersionStatus) *dynakube.DynaKube {
+		dk := &dynakube.DynaKube{
+			Spec: dynakube.DynaKubeSpec{
+				OneAgent: oneagent.Spec{ClassicFullStack: &oneagent.HostInjectSpec{}},
+				ImageVerification: &imageverification.Spec{
+					TrustedRegistries: []string{"public.ecr.aws/dynatrace"},
+				},
+			},
+		}
+		dk.Status.OneAgent.VersionStatus = target
+
+		return dk
+	}
+
+	versionReconciler := reconciler{
+		timeProvider: timeprovider.New().Freeze(),
+		verifierBuilder: func(_ context.Context, _ client.Reader, dk *dynakube.DynaKube) (*signature.Verifier, error) {
+			return signature.NewVerifier(signature.Policy{TrustedRegistries: dk.Spec.ImageVerification.TrustedRegistries}), nil
+		},
+	}
+
+	t.Run("verified image is kept", func(t *testing.T) {
+		dk := createDynakube(status.VersionStatus{ImageID: trustedImage})
+
+		require.NoError(t, versionReconciler.verifyImage(ctx, newOneAgentUpdater(dk, nil, nil), dk, status.VersionStatus{}))
+
+		assert.Equal(t, trustedImage, dk.Status.OneAgent.ImageID)
+	})
+
+	t.Run("previous image is kept if the new one fails", func(t *testing.T) {
+		probe := ptr.To(metav1.Now())
+		dk := createDynakube(status.VersionStatus{ImageID: untrustedImage, Version: "1.2.4.4-5", LastProbeTimestamp: probe})
+		previous := status.VersionStatus{ImageID: trustedImage, Version: "1.2.3.4-5"}
+
+		require.NoError(t, versionReconciler.verifyImage(ctx, newOneAgentUpdater(dk, nil, nil), dk, previous))
+
+		assert.Equal(t, trustedImage, dk.Status.OneAgent.ImageID)
+		assert.Equal(t, "1.2.3.4-5", dk.Status.OneAgent.Version)
+		assert.Equal(t, probe, dk.Status.OneAgent.LastProbeTimestamp)
+
+		condition := meta.FindStatusCondition(dk.Status.Conditions, oaConditionType)
+		require.NotNil(t, condition)
+		assert.Equal(t, conditions.ImageVerificationFailedReason, condition.Reason)
+	})
+
+	t.Run("error if there is no verified image to fall back to", func(t *testing.T) {
+		dk := createDynakube(status.VersionStatus{ImageID: untrustedImage})
+
+		err := versionReconciler.verifyImage(ctx, newOneAgentUpdater(dk, nil, nil), dk, status.VersionStatus{})
+
+		require.Error(t, err)
+		assert.Empty(t, dk.Status.OneAgent.ImageID)
+	})
+
+	t.Run("no verification without policy", func(t *testing.T) {
+		dk := createDynakube(status.VersionStatus{ImageID: untrustedImage})
+		dk.Spec.ImageVerification = nil
+
+		require.NoError(t, versionReconciler.verifyImage(ctx, newOneAgentUpdater(dk, nil, nil), dk, status.VersionStatus{}))
+
+		assert.Equal(t, untrustedImage, dk.Status.OneAgent.ImageID)
+	})
+}
@@ -26,10 +26,12 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -42,6 +44,8 @@ const (
 	defaultUpdateInterval = 30 * time.Minute
 
 	finalizerName = "server"
+
+	updatePendingEvent = "UpdatePending"
 )
 
 var (
@@ -66,6 +70,7 @@ type Controller struct {
 	config                   *rest.Config
 	timeProvider             *timeprovider.Provider
 	edgeConnectClientBuilder edgeConnectClientBuilderType
+	eventRecorder            record.EventRecorder
 }
 
 func Add(mgr manager.Manager, _ string) error {
@@ -80,6 +85,7 @@ func NewController(mgr manager.Manager) *Controller {
 		config:                   mgr.GetConfig(),
 		timeProvider:             timeprovider.New(),
 		edgeConnectClientBuilder: newEdgeConnectClient(),
+		eventRecorder:            mgr.GetEventRecorderFor("dynatrace-operator"),
 	}
 }
 
@@ -332,6 +338,8 @@ func (controller *Controller) updateVersionInfo(ctx context.Context, ec *edgecon
 		return errors.WithStack(err)
 	}
 
+	pending := ec.Status.Version.Pending
+
 	versionReconciler := version.NewReconciler(controller.apiReader, registryClient, timeprovider.New(), ec)
 	if err = versionReconciler.Reconcile(ctx); err != nil {
 		_log.Debug("reconciliation of EdgeConnect version failed")
@@ -339,6 +347,8 @@ func (controller *Controller) updateVersionInfo(ctx context.Context, ec *edgecon
 		return err
 	}
 
+	controller.recordPendingUpdate(ec, pending)
+
 	_log.Debug("EdgeConnect version info updated")
 
 	return nil
@@ -877,3 +887,18 @@ func GetConnectionSetting(edgeConnectClient edgeconnectClient.Client, name, name
 
 	return edgeconnectClient.EnvironmentSetting{}, nil
 }
+
+// recordPendingUpdate creates an event, if an update was held back according to the update schedule.
+func (controller *Controller) recordPendingUpdate(ec *edgeconnect.EdgeConnect, oldPending *status.PendingUpdate) {
+	newPending := ec.Status.Version.Pending
+	if controller.eventRecorder == nil || newPending == nil || (oldPending != nil && oldPending.ImageID == newPending.ImageID) {
+		return
+	}
+
+	message := "EdgeConnect update to " + newPending.ImageID + " is pending until the next maintenance window"
+	if newPending.NotBefore != nil {
+		message += " at " + newPending.NotBefore.UTC().Format(time.RFC3339)
+	}
+
+	controller.eventRecorder.Event(ec, corev1.EventTypeNormal, updatePendingEvent, message)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -47,6 +48,12 @@ func (u updater) RequiresReconcile() bool {
 		return true
 	}
 
+	if version.Pending != nil && u.edgeConnect.Spec.UpdateSchedule.IsOpen(u.timeProvider.Now().Time) {
+		log.Info("maintenance window is open, pending update is applied", "updater", u.Name())
+
+		return true
+	}
+
 	return isRequestOutdated && u.IsAutoUpdateEnabled()
 }
 
@@ -75,6 +82,10 @@ func (u updater) Update(ctx context.Context) error {
 			return err
 		}
 
+		if u.holdForMaintenance(image) {
+			return nil
+		}
+
 		target.Source = status.PublicRegistryVersionSource
 	} else {
 		log.Debug("EdgeConnect custom image used")
@@ -83,10 +94,40 @@ func (u updater) Update(ctx context.Context) error {
 	}
 
 	target.ImageID = image
+	target.Pending = nil
 
 	return nil
 }
 
+// holdForMaintenance keeps the current image, if a new digest was found outside of the maintenance windows, and returns true if so.
+// The new image is kept as pending update, and applied with the first probe once a window is open.
+func (u updater) holdForMaintenance(image string) bool {
+	target := u.Target()
+	schedule := u.edgeConnect.Spec.UpdateSchedule
+	now := u.timeProvider.Now()
+
+	if target.ImageID == "" || target.ImageID == image || target.Source != status.PublicRegistryVersionSource || schedule.IsOpen(now.Time) {
+		return false
+	}
+
+	if target.Pending != nil && target.Pending.ImageID == image {
+		return true
+	}
+
+	log.Info("new image is pending until the next maintenance window", "updater", u.Name(), "current", target.ImageID, "pending", image)
+
+	target.Pending = &status.PendingUpdate{
+		DetectedAt: now,
+		ImageID:    image,
+	}
+
+	if nextOpening := schedule.NextOpening(now.Time); !nextOpening.IsZero() {
+		target.Pending.NotBefore = &metav1.Time{Time: nextOpening}
+	}
+
+	return true
+}
+
 func (u updater) combineImageWithDigest(digest digest.Digest) (string, error) {
 	imageRef, err := name.ParseReference(u.edgeConnect.Image())
 	if err != nil {
@@ -14,10 +14,11 @@ var ErrNotFound = errors.New("not found")
 
 // CacheEntry contains information about a Node.
 type CacheEntry struct {
-	LastSeen                 time.Time `json:"seen"`
-	LastMarkedForTermination time.Time `json:"marked"`
-	Instance                 string    `json:"instance"`
-	IPAddress                string    `json:"ip"`
+	LastSeen                 time.Time    `json:"seen"`
+	LastMarkedForTermination time.Time    `json:"marked"`
+	Drain                    *DrainStatus `json:"drain,omitempty"`
+	Instance                 string       `json:"instance"`
+	IPAddress                string       `json:"ip"`
 }
 
 // Cache manages information about Nodes.
@@ -114,6 +115,13 @@ func (cache *Cache) UpdateTimestamp() {
 
 func (cache *Cache) updateLastMarkedForTerminationTimestamp(nodeInfo CacheEntry, nodeName string) error {
 	nodeInfo.LastMarkedForTermination = cache.timeProvider.Now().UTC()
+	nodeInfo.Drain.record(NotifiedPhase, nodeInfo.Drain.Reason, nodeInfo.LastMarkedForTermination)
+
+	return cache.Set(nodeName, nodeInfo)
+}
+
+func (cache *Cache) recordDrainNotified(nodeInfo CacheEntry, nodeName string) error {
+	nodeInfo.Drain.record(NotifiedPhase, nodeInfo.Drain.Reason, cache.timeProvider.Now().UTC())
 
 	return cache.Set(nodeName, nodeInfo)
 }
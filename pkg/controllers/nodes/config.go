@@ -12,7 +12,4 @@ const (
 	lastUpdatedCacheAnnotation = "DTOperatorLastUpdated"
 )
 
-var (
-	log                 = logd.Get().WithName("nodes")
-	unschedulableTaints = []string{"ToBeDeletedByClusterAutoscaler"}
-)
+var log = logd.Get().WithName("nodes")
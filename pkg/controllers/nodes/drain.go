@@ -0,0 +1,199 @@
+package nodes
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	clusterAutoscalerTaint   = "ToBeDeletedByClusterAutoscaler"
+	karpenterDisruptionTaint = "karpenter.sh/disruption"
+
+	// maxDrainSteps limits the timeline of flapping nodes, the first step is always kept
+	maxDrainSteps = 16
+)
+
+// DrainReason is the signal a node is going to be removed from the cluster for.
+type DrainReason string
+
+const (
+	// planned removals, the OneAgent host is expected to go away
+	ClusterAutoscalerScaleDownReason DrainReason = "ClusterAutoscalerScaleDown"
+	KarpenterDisruptionReason        DrainReason = "KarpenterDisruption"
+	NodeDeletionReason               DrainReason = "NodeDeletion"
+	CordonedReason                   DrainReason = "Cordoned"
+
+	// failures, the OneAgent host is unexpectedly unavailable
+	NodeNotReadyReason     DrainReason = "NodeNotReady"
+	NodeUnreachableReason  DrainReason = "NodeUnreachable"
+	NodeOutOfServiceReason DrainReason = "NodeOutOfService"
+)
+
+// DrainPhase is a step of the timeline of a node that is being removed.
+type DrainPhase string
+
+const (
+	// SignaledPhase is recorded when the operator first sees a drain signal or the signal changes.
+	SignaledPhase DrainPhase = "Signaled"
+	// NotifiedPhase is recorded when the event was sent to the Dynatrace environment.
+	NotifiedPhase DrainPhase = "Notified"
+	// DeletedPhase is recorded when the node is gone from the cluster.
+	DeletedPhase DrainPhase = "Deleted"
+)
+
+// DrainStatus contains the drain timeline of a node, it is kept in the node cache until the node is gone.
+type DrainStatus struct {
+	Reason   DrainReason `json:"reason"`
+	Planned  bool        `json:"planned"`
+	Timeline []DrainStep `json:"timeline"`
+}
+
+// DrainStep is a single entry of the drain timeline.
+type DrainStep struct {
+	Time   time.Time   `json:"time"`
+	Phase  DrainPhase  `json:"phase"`
+	Reason DrainReason `json:"reason,omitempty"`
+}
+
+var (
+	// failureTaints are set by the node lifecycle controller, ordered by severity
+	failureTaints = []struct {
+		key    string
+		reason DrainReason
+	}{
+		{key: corev1.TaintNodeOutOfService, reason: NodeOutOfServiceReason},
+		{key: corev1.TaintNodeUnreachable, reason: NodeUnreachableReason},
+		{key: corev1.TaintNodeNotReady, reason: NodeNotReadyReason},
+	}
+
+	drainDescriptions = map[DrainReason]string{
+		ClusterAutoscalerScaleDownReason: "Kubernetes node is scaled down by the cluster-autoscaler. Node is drained and terminated.",
+		KarpenterDisruptionReason:        "Kubernetes node is disrupted by Karpenter. Node is drained and terminated.",
+		NodeDeletionReason:               "Kubernetes node is deleted. Node is drained and terminated.",
+		CordonedReason:                   "Kubernetes node cordoned. Node might be drained or terminated.",
+		NodeNotReadyReason:               "Kubernetes node is not ready. Host is unexpectedly unavailable.",
+		NodeUnreachableReason:            "Kubernetes node is unreachable. Host is unexpectedly unavailable.",
+		NodeOutOfServiceReason:           "Kubernetes node is out of service. Host is unexpectedly unavailable.",
+	}
+)
+
+// getDrainReason returns the reason the node is going to be removed for, false if there is no drain signal.
+// A planned removal takes precedence over failures, as a node that is being terminated usually becomes not ready or unreachable on its way out.
+// Failures take precedence over a cordon, which does not necessarily mean the node is removed.
+func getDrainReason(node *corev1.Node) (DrainReason, bool) {
+	switch {
+	case node.DeletionTimestamp != nil:
+		// a deleted NodeClaim or Machine deletes its node, the node is kept by finalizers until it is drained
+		return NodeDeletionReason, true
+	case hasTaint(node, karpenterDisruptionTaint):
+		return KarpenterDisruptionReason, true
+	case hasTaint(node, clusterAutoscalerTaint):
+		return ClusterAutoscalerScaleDownReason, true
+	}
+
+	for _, failureTaint := range failureTaints {
+		if hasTaint(node, failureTaint.key) {
+			return failureTaint.reason, true
+		}
+	}
+
+	if node.Spec.Unschedulable {
+		return CordonedReason, true
+	}
+
+	return "", false
+}
+
+func hasTaint(node *corev1.Node, key string) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isPlannedRemoval checks for the reasons that mean the node is going to be terminated, unlike a cordon.
+func isPlannedRemoval(reason DrainReason) bool {
+	return reason == NodeDeletionReason || reason == KarpenterDisruptionReason || reason == ClusterAutoscalerScaleDownReason
+}
+
+func isPlanned(reason DrainReason) bool {
+	for _, failureTaint := range failureTaints {
+		if failureTaint.reason == reason {
+			return false
+		}
+	}
+
+	return true
+}
+
+// signal returns the drain status updated with the reason, a step is recorded if the reason changed.
+// Once a removal was planned, failures are ignored, they are expected while the node is terminated.
+func (status *DrainStatus) signal(reason DrainReason, now time.Time) *DrainStatus {
+	if status == nil {
+		status = &DrainStatus{}
+	} else if status.Reason == reason || (!isPlanned(reason) && status.hasPlannedRemoval()) {
+		return status
+	}
+
+	status.Reason = reason
+	status.Planned = isPlanned(reason)
+	status.record(SignaledPhase, reason, now)
+
+	return status
+}
+
+func (status *DrainStatus) record(phase DrainPhase, reason DrainReason, now time.Time) {
+	if status == nil {
+		return
+	}
+
+	status.Timeline = append(status.Timeline, DrainStep{Time: now, Phase: phase, Reason: reason})
+
+	if len(status.Timeline) > maxDrainSteps {
+		status.Timeline = append(status.Timeline[:1], status.Timeline[len(status.Timeline)-maxDrainSteps+1:]...)
+	}
+}
+
+// hasPlannedRemoval checks if the timeline contains a signal that the node is going to be terminated.
+func (status *DrainStatus) hasPlannedRemoval() bool {
+	if isPlannedRemoval(status.Reason) {
+		return true
+	}
+
+	for _, step := range status.Timeline {
+		if step.Phase == SignaledPhase && isPlannedRemoval(step.Reason) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isNotified checks if the event for the current reason was already sent.
+func (status *DrainStatus) isNotified() bool {
+	for i := len(status.Timeline) - 1; i >= 0; i-- {
+		step := status.Timeline[i]
+		if step.Reason != status.Reason {
+			return false
+		}
+
+		if step.Phase == NotifiedPhase {
+			return true
+		}
+	}
+
+	return false
+}
+
+// duration returns the time passed since the first drain signal.
+func (status *DrainStatus) duration(now time.Time) time.Duration {
+	if len(status.Timeline) == 0 {
+		return 0
+	}
+
+	return now.Sub(status.Timeline[0].Time)
+}
@@ -0,0 +1,133 @@
+package nodes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDrainReason(t *testing.T) {
+	now := metav1.Now()
+
+	for _, testCase := range []struct {
+		name     string
+		node     corev1.Node
+		reason   DrainReason
+		draining bool
+	}{
+		{
+			name: "no signal",
+		},
+		{
+			name:     "cordoned",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true}},
+			reason:   CordonedReason,
+			draining: true,
+		},
+		{
+			name:     "cluster-autoscaler scale-down",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true, Taints: []corev1.Taint{{Key: clusterAutoscalerTaint}}}},
+			reason:   ClusterAutoscalerScaleDownReason,
+			draining: true,
+		},
+		{
+			name:     "karpenter disruption",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: karpenterDisruptionTaint, Value: "disrupting"}}}},
+			reason:   KarpenterDisruptionReason,
+			draining: true,
+		},
+		{
+			name: "deleted node claim",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: karpenterDisruptionTaint}}},
+			},
+			reason:   NodeDeletionReason,
+			draining: true,
+		},
+		{
+			name: "planned removal takes precedence over failure",
+			node: corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: corev1.TaintNodeNotReady}, {Key: corev1.TaintNodeUnreachable}}},
+			},
+			reason:   NodeDeletionReason,
+			draining: true,
+		},
+		{
+			name:     "failure takes precedence over cordon",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Unschedulable: true, Taints: []corev1.Taint{{Key: corev1.TaintNodeNotReady}, {Key: corev1.TaintNodeUnreachable}}}},
+			reason:   NodeUnreachableReason,
+			draining: true,
+		},
+		{
+			name:     "out of service",
+			node:     corev1.Node{Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: corev1.TaintNodeOutOfService}}}},
+			reason:   NodeOutOfServiceReason,
+			draining: true,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			reason, draining := getDrainReason(&testCase.node)
+
+			assert.Equal(t, testCase.reason, reason)
+			assert.Equal(t, testCase.draining, draining)
+		})
+	}
+}
+
+func TestDrainStatus(t *testing.T) {
+	now := time.Now().UTC()
+
+	t.Run("timeline records changed signals", func(t *testing.T) {
+		var status *DrainStatus
+
+		status = status.signal(CordonedReason, now)
+		status = status.signal(CordonedReason, now.Add(time.Minute))
+		status = status.signal(NodeUnreachableReason, now.Add(2*time.Minute))
+
+		assert.Equal(t, NodeUnreachableReason, status.Reason)
+		assert.False(t, status.Planned)
+		assert.Equal(t, []DrainStep{
+			{Time: now, Phase: SignaledPhase, Reason: CordonedReason},
+			{Time: now.Add(2 * time.Minute), Phase: SignaledPhase, Reason: NodeUnreachableReason},
+		}, status.Timeline)
+		assert.Equal(t, 2*time.Minute, status.duration(now.Add(2*time.Minute)))
+	})
+
+	t.Run("notification is tracked per reason", func(t *testing.T) {
+		status := (*DrainStatus)(nil).signal(NodeNotReadyReason, now)
+		assert.False(t, status.isNotified())
+
+		status.record(NotifiedPhase, status.Reason, now)
+		assert.True(t, status.isNotified())
+
+		status = status.signal(NodeUnreachableReason, now)
+		assert.False(t, status.isNotified())
+	})
+
+	t.Run("planned removal stays planned", func(t *testing.T) {
+		status := (*DrainStatus)(nil).signal(ClusterAutoscalerScaleDownReason, now)
+		status = status.signal(CordonedReason, now.Add(time.Minute))
+		status = status.signal(NodeNotReadyReason, now.Add(2*time.Minute))
+
+		assert.Equal(t, CordonedReason, status.Reason)
+		assert.True(t, status.Planned)
+		assert.Len(t, status.Timeline, 2)
+	})
+
+	t.Run("timeline is limited and keeps the first step", func(t *testing.T) {
+		status := (*DrainStatus)(nil).signal(CordonedReason, now)
+
+		for i := range 2 * maxDrainSteps {
+			status.record(NotifiedPhase, CordonedReason, now.Add(time.Duration(i+1)*time.Minute))
+		}
+
+		assert.Len(t, status.Timeline, maxDrainSteps)
+		assert.Equal(t, SignaledPhase, status.Timeline[0].Phase)
+		assert.Equal(t, now.Add(2*maxDrainSteps*time.Minute), status.Timeline[maxDrainSteps-1].Time)
+	})
+}
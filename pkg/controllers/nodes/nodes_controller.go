@@ -97,21 +97,32 @@ func (controller *Controller) Reconcile(ctx context.Context, request reconcile.R
 
 		if cached, err := nodeCache.Get(nodeName); err == nil {
 			cacheEntry.LastMarkedForTermination = cached.LastMarkedForTermination
+			cacheEntry.Drain = cached.Drain
+		}
+
+		reason, draining := getDrainReason(&node)
+		if draining {
+			cacheEntry.Drain = cacheEntry.Drain.signal(reason, cacheEntry.LastSeen)
+		} else if cacheEntry.Drain != nil {
+			log.Info("drain signal of node is gone, node is kept", "node", nodeName, "reason", cacheEntry.Drain.Reason,
+				"duration", cacheEntry.Drain.duration(cacheEntry.LastSeen))
+
+			cacheEntry.Drain = nil
 		}
 
 		if err := nodeCache.Set(nodeName, cacheEntry); err != nil {
 			return reconcile.Result{}, err
 		}
 
-		// Handle unschedulable Nodes, if they have a OneAgent instance
-		if controller.isUnschedulable(&node) {
+		// Handle draining Nodes, if they have a OneAgent instance
+		if draining {
 			cachedNodeData := CachedNodeInfo{
 				cachedNode: cacheEntry,
 				nodeCache:  nodeCache,
 				nodeName:   nodeName,
 			}
 
-			if err := controller.markForTermination(ctx, dk, cachedNodeData); err != nil {
+			if err := controller.notifyDrain(ctx, dk, cachedNodeData); err != nil {
 				return reconcile.Result{}, err
 			}
 		}
@@ -150,6 +161,14 @@ func (controller *Controller) reconcileNodeDeletion(ctx context.Context, nodeNam
 		return err
 	}
 
+	// a node that is removed without a prior drain signal, e.g. by deleting it directly, is a planned removal
+	now := controller.timeProvider.Now().UTC()
+	if cachedNodeInfo.Drain == nil {
+		cachedNodeInfo.Drain = cachedNodeInfo.Drain.signal(NodeDeletionReason, now)
+	}
+
+	cachedNodeInfo.Drain.record(DeletedPhase, cachedNodeInfo.Drain.Reason, now)
+
 	if dynakube != nil {
 		cachedNodeData := CachedNodeInfo{
 			cachedNode: cachedNodeInfo,
@@ -157,11 +176,14 @@ func (controller *Controller) reconcileNodeDeletion(ctx context.Context, nodeNam
 			nodeName:   nodeName,
 		}
 
-		if err := controller.markForTermination(ctx, dynakube, cachedNodeData); err != nil {
+		if err := controller.notifyDrain(ctx, dynakube, cachedNodeData); err != nil {
 			return err
 		}
 	}
 
+	log.Info("node removed from cluster", "node", nodeName, "reason", cachedNodeInfo.Drain.Reason, "planned", cachedNodeInfo.Drain.Planned,
+		"duration", cachedNodeInfo.Drain.duration(now), "timeline", cachedNodeInfo.Drain.Timeline)
+
 	nodeCache.Delete(nodeName)
 
 	if err := controller.updateCache(ctx, nodeCache); err != nil {
@@ -273,7 +295,7 @@ func (controller *Controller) isNodeDeletable(cachedNode CacheEntry) bool {
 	return false
 }
 
-func (controller *Controller) sendMarkedForTermination(ctx context.Context, dk *dynakube.DynaKube, cachedNode CacheEntry) error {
+func (controller *Controller) sendDrainEvent(ctx context.Context, dk *dynakube.DynaKube, cachedNode CacheEntry, nodeName string) error {
 	tokenReader := token.NewReader(controller.apiReader, dk)
 
 	tokens, err := tokenReader.ReadTokens(ctx)
@@ -292,29 +314,59 @@ func (controller *Controller) sendMarkedForTermination(ctx context.Context, dk *
 	entityID, err := dynatraceClient.GetEntityIDForIP(ctx, cachedNode.IPAddress)
 	if err != nil {
 		if errors.As(err, &dtclient.HostNotFoundErr{}) {
-			log.Info("skipping to send mark for termination event", "dynakube", dk.Name, "nodeIP", cachedNode.IPAddress, "reason", err.Error())
+			log.Info("skipping to send drain event", "dynakube", dk.Name, "nodeIP", cachedNode.IPAddress, "reason", err.Error())
 
 			return nil
 		}
 
-		log.Info("failed to send mark for termination event",
+		log.Info("failed to send drain event",
 			"reason", "failed to determine entity id", "dynakube", dk.Name, "nodeIP", cachedNode.IPAddress, "cause", err)
 
 		return err
 	}
 
-	ts := uint64(cachedNode.LastSeen.Add(-10*time.Minute).UnixNano()) / uint64(time.Millisecond) //nolint:gosec
+	return dynatraceClient.SendEvent(ctx, newDrainEvent(cachedNode, nodeName, entityID, controller.timeProvider.Now().UTC()))
+}
+
+func newDrainEvent(cachedNode CacheEntry, nodeName, entityID string, now time.Time) *dtclient.EventData {
+	drain := cachedNode.Drain
 
-	return dynatraceClient.SendEvent(ctx, &dtclient.EventData{
-		EventType:     dtclient.MarkedForTerminationEvent,
-		Source:        "Dynatrace Operator",
-		Description:   "Kubernetes node cordoned. Node might be drained or terminated.",
-		StartInMillis: ts,
-		EndInMillis:   ts,
+	event := &dtclient.EventData{
+		Source:      "Dynatrace Operator",
+		Description: drainDescriptions[drain.Reason],
 		AttachRules: dtclient.EventDataAttachRules{
 			EntityIDs: []string{entityID},
 		},
-	})
+		CustomProperties: map[string]string{
+			"Kubernetes node": nodeName,
+			"Drain reason":    string(drain.Reason),
+		},
+	}
+
+	if drain.Planned {
+		// the host is expected to go away, so the event is dated before the agent stops reporting
+		ts := uint64(cachedNode.LastSeen.Add(-10*time.Minute).UnixNano()) / uint64(time.Millisecond) //nolint:gosec
+		event.EventType = dtclient.MarkedForTerminationEvent
+		event.StartInMillis = ts
+		event.EndInMillis = ts
+	} else {
+		ts := uint64(now.UnixNano()) / uint64(time.Millisecond) //nolint:gosec
+		event.EventType = dtclient.AvailabilityEvent
+		event.Title = "Kubernetes node failure"
+		event.StartInMillis = ts
+		event.EndInMillis = ts
+	}
+
+	return event
+}
+
+// notifyDrain sends the event matching the drain reason of the node.
+func (controller *Controller) notifyDrain(ctx context.Context, dk *dynakube.DynaKube, cachedNodeData CachedNodeInfo) error {
+	if cachedNodeData.cachedNode.Drain.Planned {
+		return controller.markForTermination(ctx, dk, cachedNodeData)
+	}
+
+	return controller.reportFailure(ctx, dk, cachedNodeData)
 }
 
 func (controller *Controller) markForTermination(ctx context.Context, dk *dynakube.DynaKube, cachedNodeData CachedNodeInfo) error {
@@ -327,25 +379,26 @@ func (controller *Controller) markForTermination(ctx context.Context, dk *dynaku
 	}
 
 	log.Info("sending mark for termination event to dynatrace server", "dk", dk.Name, "ip", cachedNodeData.cachedNode.IPAddress,
-		"node", cachedNodeData.nodeName)
+		"node", cachedNodeData.nodeName, "reason", cachedNodeData.cachedNode.Drain.Reason)
 
-	return controller.sendMarkedForTermination(ctx, dk, cachedNodeData.cachedNode)
+	return controller.sendDrainEvent(ctx, dk, cachedNodeData.cachedNode, cachedNodeData.nodeName)
 }
 
-func (controller *Controller) isUnschedulable(node *corev1.Node) bool {
-	return node.Spec.Unschedulable || controller.hasUnschedulableTaint(node)
-}
+// reportFailure sends the failure event once per failure reason, so a node that stays unreachable doesn't flood the environment.
+func (controller *Controller) reportFailure(ctx context.Context, dk *dynakube.DynaKube, cachedNodeData CachedNodeInfo) error {
+	if cachedNodeData.cachedNode.Drain.isNotified() {
+		return nil
+	}
 
-func (controller *Controller) hasUnschedulableTaint(node *corev1.Node) bool {
-	for _, taint := range node.Spec.Taints {
-		for _, unschedulableTaint := range unschedulableTaints {
-			if taint.Key == unschedulableTaint {
-				return true
-			}
-		}
+	log.Info("sending node failure event to dynatrace server", "dk", dk.Name, "ip", cachedNodeData.cachedNode.IPAddress,
+		"node", cachedNodeData.nodeName, "reason", cachedNodeData.cachedNode.Drain.Reason)
+
+	if err := controller.sendDrainEvent(ctx, dk, cachedNodeData.cachedNode, cachedNodeData.nodeName); err != nil {
+		return err
 	}
 
-	return false
+	// only recorded once the event was sent, so a failed attempt is retried
+	return cachedNodeData.nodeCache.recordDrainNotified(cachedNodeData.cachedNode, cachedNodeData.nodeName)
 }
 
 // isMarkableForTermination checks if the timestamp from last mark is at least one hour old
@@ -150,6 +150,79 @@ func TestReconcile(t *testing.T) {
 		assert.True(t, node.LastMarkedForTermination.Add(time.Minute).After(now))
 	})
 
+	t.Run("Failing node sends failure event once", func(t *testing.T) {
+		fakeClient := createDefaultFakeClient()
+
+		dtClient := dtclientmock.NewClient(t)
+		dtClient.On("GetEntityIDForIP", mock.AnythingOfType("context.backgroundCtx"), "1.2.3.4").Return("HOST-42", nil)
+		dtClient.On("SendEvent", mock.AnythingOfType("context.backgroundCtx"), mock.MatchedBy(func(e *dtclient.EventData) bool {
+			return e.EventType == dtclient.AvailabilityEvent && e.CustomProperties["Drain reason"] == string(NodeUnreachableReason)
+		})).Return(nil).Once()
+
+		ctrl := createDefaultReconciler(fakeClient, dtClient)
+		reconcileAllNodes(t, ctrl, fakeClient)
+
+		node1 := &corev1.Node{}
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "node1"}, node1))
+
+		node1.Spec.Taints = []corev1.Taint{{Key: corev1.TaintNodeUnreachable}}
+		require.NoError(t, fakeClient.Update(ctx, node1))
+
+		_, err := ctrl.Reconcile(ctx, createReconcileRequest("node1"))
+		require.NoError(t, err)
+
+		_, err = ctrl.Reconcile(ctx, createReconcileRequest("node1"))
+		require.NoError(t, err)
+
+		c, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
+
+		node, err := c.Get("node1")
+		require.NoError(t, err)
+		require.NotNil(t, node.Drain)
+		assert.False(t, node.Drain.Planned)
+		assert.True(t, node.LastMarkedForTermination.IsZero())
+		assert.Equal(t, []DrainPhase{SignaledPhase, NotifiedPhase}, drainPhases(node.Drain))
+	})
+
+	t.Run("Drain status is cleared if the signal is gone", func(t *testing.T) {
+		fakeClient := createDefaultFakeClient()
+		dtClient := createDTMockClient(t, "1.2.3.4", "HOST-42")
+		ctrl := createDefaultReconciler(fakeClient, dtClient)
+
+		node1 := &corev1.Node{}
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "node1"}, node1))
+
+		node1.Spec.Taints = []corev1.Taint{{Key: karpenterDisruptionTaint, Value: "disrupting"}}
+		require.NoError(t, fakeClient.Update(ctx, node1))
+
+		_, err := ctrl.Reconcile(ctx, createReconcileRequest("node1"))
+		require.NoError(t, err)
+
+		c, err := ctrl.getCache(ctx)
+		require.NoError(t, err)
+
+		node, err := c.Get("node1")
+		require.NoError(t, err)
+		require.NotNil(t, node.Drain)
+		assert.Equal(t, KarpenterDisruptionReason, node.Drain.Reason)
+		assert.True(t, node.Drain.Planned)
+		assert.Equal(t, []DrainPhase{SignaledPhase, NotifiedPhase}, drainPhases(node.Drain))
+
+		node1.Spec.Taints = nil
+		require.NoError(t, fakeClient.Update(ctx, node1))
+
+		_, err = ctrl.Reconcile(ctx, createReconcileRequest("node1"))
+		require.NoError(t, err)
+
+		c, err = ctrl.getCache(ctx)
+		require.NoError(t, err)
+
+		node, err = c.Get("node1")
+		require.NoError(t, err)
+		assert.Nil(t, node.Drain)
+	})
+
 	t.Run("Server error when removing node", func(t *testing.T) {
 		fakeClient := createDefaultFakeClient()
 
@@ -268,6 +341,15 @@ func createDTMockClient(t *testing.T, ip, host string) *dtclientmock.Client {
 	return dtClient
 }
 
+func drainPhases(status *DrainStatus) []DrainPhase {
+	phases := make([]DrainPhase, 0, len(status.Timeline))
+	for _, step := range status.Timeline {
+		phases = append(phases, step.Phase)
+	}
+
+	return phases
+}
+
 func reconcileAllNodes(t *testing.T, ctrl *Controller, fakeClient client.Client) {
 	ctx := context.Background()
 
@@ -0,0 +1,23 @@
+package bundle
+
+import (
+	"path/filepath"
+
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+)
+
+const (
+	// ManifestFileName is the name of the manifest at the root of a bundle.
+	ManifestFileName = "manifest.json"
+	// SignatureFileName is the name of the base64 encoded detached signature of the manifest, next to the manifest.
+	SignatureFileName = ManifestFileName + ".sig"
+
+	// TrustedKeysKey is the key in the trusted keys ConfigMap that holds the PEM encoded public keys.
+	TrustedKeysKey = "keys"
+)
+
+var (
+	CacheDir = filepath.Join(dtcsi.DataPath, "bundle-cache")
+	log      = logd.Get().WithName("oneagent-bundle")
+)
@@ -0,0 +1,54 @@
+package bundle
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/connectioninfo"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/configmap"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConnectionInfoConfigMapName is the ConfigMap, in the namespace of the DynaKube, where the csi-provisioner publishes the connection info of the bundle.
+// The bundle is only available on the nodes, so this is how the webhook learns about the tenant. The tenant token never leaves the nodes.
+func ConnectionInfoConfigMapName(dkName string) string {
+	return dkName + "-codemodules-bundle"
+}
+
+// BuildConnectionInfoConfigMap creates the ConfigMap that publishes the connection info of the manifest, without the tenant token.
+func BuildConnectionInfoConfigMap(dk *dynakube.DynaKube, manifest Manifest) (*corev1.ConfigMap, error) {
+	data := map[string]string{
+		connectioninfo.TenantUUIDKey:             manifest.ConnectionInfo.TenantUUID,
+		connectioninfo.CommunicationEndpointsKey: manifest.ConnectionInfo.Endpoints,
+	}
+
+	return configmap.Build(dk, ConnectionInfoConfigMapName(dk.Name), data)
+}
+
+// ApplyConnectionInfo sets the connection info of the bundle in the status of the given DynaKube, if it uses a bundle.
+// It is meant for a copy of the DynaKube, that is not persisted, as the bundle replaces the connection info of the tenant.
+// Nothing is changed until the csi-provisioner published the connection info.
+func ApplyConnectionInfo(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube) error {
+	if dk.OneAgent().GetCodeModulesBundle() == nil {
+		return nil
+	}
+
+	var connectionInfo corev1.ConfigMap
+
+	err := apiReader.Get(ctx, client.ObjectKey{Name: ConnectionInfoConfigMapName(dk.Name), Namespace: dk.Namespace}, &connectionInfo)
+	if k8serrors.IsNotFound(err) {
+		log.Info("connection info of the CodeModules bundle is not yet published", "dynakube", dk.Name)
+
+		return nil
+	} else if err != nil {
+		return errors.WithMessage(err, "failed to get connection info of the CodeModules bundle")
+	}
+
+	dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID = connectionInfo.Data[connectioninfo.TenantUUIDKey]
+	dk.Status.OneAgent.ConnectionInfoStatus.Endpoints = connectionInfo.Data[connectioninfo.CommunicationEndpointsKey]
+
+	return nil
+}
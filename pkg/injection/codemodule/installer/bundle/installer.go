@@ -0,0 +1,247 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/common"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/symlink"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+type Properties struct {
+	// Manifest is the already verified manifest of the bundle, see ReadManifest.
+	Manifest     *Manifest
+	Path         string
+	PathResolver metadata.PathResolver
+
+	// SizeCheck is called with the size of the layers of the bundle before they are unpacked, optional.
+	SizeCheck installer.SizeCheck
+}
+
+func NewInstaller(fs afero.Fs, props *Properties) installer.Installer {
+	return &Installer{
+		fs:        fs,
+		extractor: zip.NewOneAgentExtractor(fs, props.PathResolver),
+		props:     props,
+	}
+}
+
+type Installer struct {
+	fs        afero.Fs
+	extractor zip.Extractor
+	props     *Properties
+}
+
+func (installer *Installer) InstallAgent(_ context.Context, targetDir string) (bool, error) {
+	log.Info("installing agent from bundle", "bundle", installer.props.Path, "version", installer.props.Manifest.Version)
+
+	if installer.isAlreadyPresent(targetDir) {
+		log.Info("agent already installed", "bundle", installer.props.Path, "target dir", targetDir)
+
+		return true, nil
+	}
+
+	err := installer.fs.MkdirAll(installer.props.PathResolver.AgentSharedBinaryDirBase(), common.MkDirFileMode)
+	if err != nil {
+		log.Info("failed to create the base shared agent directory", "err", err)
+
+		return false, errors.WithStack(err)
+	}
+
+	if err := installer.installAgentFromBundle(targetDir); err != nil {
+		_ = installer.fs.RemoveAll(targetDir)
+
+		log.Info("failed to install agent from bundle", "err", err)
+
+		return false, err
+	}
+
+	if err := symlink.CreateForCurrentVersionIfNotExists(installer.fs, targetDir); err != nil {
+		_ = installer.fs.RemoveAll(targetDir)
+
+		log.Info("failed to create symlink for agent installation", "err", err)
+
+		return false, errors.WithStack(err)
+	}
+
+	return true, nil
+}
+
+func (installer *Installer) installAgentFromBundle(targetDir string) error {
+	layoutDir := installer.props.Path
+
+	isDir, err := afero.IsDir(installer.fs, layoutDir)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if !isDir {
+		// the cache dir is shared by all DynaKubes on the node, so every installation unpacks into its own temporary dir
+		err = installer.fs.MkdirAll(CacheDir, common.MkDirFileMode)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		layoutDir, err = afero.TempDir(installer.fs, CacheDir, installer.props.Manifest.Version+"-")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		defer func() { _ = installer.fs.RemoveAll(layoutDir) }()
+
+		log.Info("unpacking bundle tarball", "bundle", installer.props.Path, "cache dir", layoutDir)
+
+		if err := extractTarball(installer.fs, installer.props.Path, layoutDir); err != nil {
+			return err
+		}
+	}
+
+	imageDigest, err := containerv1.NewHash(installer.props.Manifest.Image)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	layers, err := installer.resolveLayers(layoutDir, imageDigest)
+	if err != nil {
+		return err
+	}
+
+	if err := installer.checkSize(layers); err != nil {
+		return err
+	}
+
+	for _, layer := range layers {
+		if layer.MediaType != types.DockerLayer && layer.MediaType != types.OCILayer {
+			return errors.Errorf("media type %s is not implemented", layer.MediaType)
+		}
+
+		blobPath := getBlobPath(layoutDir, layer.Digest)
+
+		if err := installer.verifyBlob(blobPath, layer.Digest); err != nil {
+			return err
+		}
+
+		if err := installer.extractor.ExtractGzip(blobPath, targetDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (installer *Installer) checkSize(layers []containerv1.Descriptor) error {
+	if installer.props.SizeCheck == nil {
+		return nil
+	}
+
+	var sizeBytes int64
+
+	for _, layer := range layers {
+		sizeBytes += layer.Size
+	}
+
+	return installer.props.SizeCheck(sizeBytes)
+}
+
+// resolveLayers returns the layers of the image with the given digest, image indexes are resolved for the platform of the node.
+// Every blob is verified against its digest, which makes the signed manifest cover the whole content of the bundle.
+func (installer *Installer) resolveLayers(layoutDir string, digest containerv1.Hash) ([]containerv1.Descriptor, error) {
+	content, err := installer.readBlob(layoutDir, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	var descriptor struct {
+		MediaType types.MediaType `json:"mediaType"`
+	}
+
+	if err := json.Unmarshal(content, &descriptor); err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse blob %s", digest)
+	}
+
+	if descriptor.MediaType.IsIndex() {
+		index, err := containerv1.ParseIndexManifest(bytes.NewReader(content))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to parse image index %s", digest)
+		}
+
+		for _, manifest := range index.Manifests {
+			if manifest.Platform != nil && manifest.Platform.Satisfies(arch.ImagePlatform) {
+				return installer.resolveLayers(layoutDir, manifest.Digest)
+			}
+		}
+
+		return nil, errors.Errorf("image index %s contains no image for platform %s", digest, arch.ImagePlatform.String())
+	}
+
+	manifest, err := containerv1.ParseManifest(bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse image manifest %s", digest)
+	}
+
+	return manifest.Layers, nil
+}
+
+func (installer *Installer) readBlob(layoutDir string, digest containerv1.Hash) ([]byte, error) {
+	content, err := afero.ReadFile(installer.fs, getBlobPath(layoutDir, digest))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := checkDigest(bytes.NewReader(content), digest); err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+func (installer *Installer) verifyBlob(blobPath string, digest containerv1.Hash) error {
+	blob, err := installer.fs.Open(blobPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = blob.Close() }()
+
+	return checkDigest(blob, digest)
+}
+
+func checkDigest(reader io.Reader, digest containerv1.Hash) error {
+	if digest.Algorithm != "sha256" {
+		return errors.Errorf("digest algorithm %s is not supported", digest.Algorithm)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != digest.Hex {
+		return errors.Errorf("blob %s does not match its digest, got sha256:%s", digest, actual)
+	}
+
+	return nil
+}
+
+func (installer *Installer) isAlreadyPresent(targetDir string) bool {
+	_, err := installer.fs.Stat(targetDir)
+
+	return !os.IsNotExist(err)
+}
+
+func getBlobPath(layoutDir string, digest containerv1.Hash) string {
+	return filepath.Join(layoutDir, "blobs", digest.Algorithm, digest.Hex)
+}
@@ -0,0 +1,125 @@
+package bundle
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/common"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallAgent(t *testing.T) {
+	ctx := context.Background()
+	pathResolver := metadata.PathResolver{RootDir: "/data"}
+	targetDir := pathResolver.AgentSharedBinaryDirForAgent(testVersion)
+
+	newTestInstaller := func(fs afero.Fs, bundle *testBundle, bundlePath string) *Installer {
+		return NewInstaller(fs, &Properties{
+			Manifest:     &bundle.manifest,
+			Path:         bundlePath,
+			PathResolver: pathResolver,
+		}).(*Installer)
+	}
+
+	assertInstalled := func(t *testing.T, fs afero.Fs) {
+		t.Helper()
+
+		exists, err := afero.Exists(fs, filepath.Join(targetDir, zip.TestZipFilename))
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = afero.Exists(fs, filepath.Join(targetDir, common.AgentConfDirPath, common.RuxitConfFileName))
+		require.NoError(t, err)
+		assert.True(t, exists)
+	}
+
+	t.Run("install from directory", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundle.writeDir(t, fs)
+
+		ready, err := newTestInstaller(fs, bundle, testBundleDir).InstallAgent(ctx, targetDir)
+
+		require.NoError(t, err)
+		assert.True(t, ready)
+		assertInstalled(t, fs)
+	})
+
+	t.Run("install from tarball via image index", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, true)
+		bundlePath := bundle.writeTarball(t, fs, true)
+
+		ready, err := newTestInstaller(fs, bundle, bundlePath).InstallAgent(ctx, targetDir)
+
+		require.NoError(t, err)
+		assert.True(t, ready)
+		assertInstalled(t, fs)
+
+		entries, err := afero.ReadDir(fs, CacheDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("install from tarball keeps other unpacked bundles", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundlePath := bundle.writeTarball(t, fs, true)
+
+		otherBundleDir := filepath.Join(CacheDir, "other-bundle")
+		require.NoError(t, fs.MkdirAll(otherBundleDir, 0755))
+
+		ready, err := newTestInstaller(fs, bundle, bundlePath).InstallAgent(ctx, targetDir)
+
+		require.NoError(t, err)
+		assert.True(t, ready)
+		assertInstalled(t, fs)
+
+		exists, err := afero.DirExists(fs, otherBundleDir)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("already installed => nothing to do", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		require.NoError(t, fs.MkdirAll(targetDir, 0755))
+
+		ready, err := newTestInstaller(fs, newTestBundle(t, false), testBundleDir).InstallAgent(ctx, targetDir)
+
+		require.NoError(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("tampered layer => error", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundle.files[filepath.Join("blobs", bundle.layerBlob.Algorithm, bundle.layerBlob.Hex)] = []byte("tampered")
+		bundle.writeDir(t, fs)
+
+		ready, err := newTestInstaller(fs, bundle, testBundleDir).InstallAgent(ctx, targetDir)
+
+		require.Error(t, err)
+		assert.False(t, ready)
+
+		exists, err := afero.Exists(fs, targetDir)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("image not in bundle => error", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundle.manifest.Image = "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+		bundle.writeDir(t, fs)
+
+		ready, err := newTestInstaller(fs, bundle, testBundleDir).InstallAgent(ctx, targetDir)
+
+		require.Error(t, err)
+		assert.False(t, ready)
+	})
+}
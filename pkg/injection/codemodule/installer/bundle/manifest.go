@@ -0,0 +1,135 @@
+package bundle
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"path"
+	"path/filepath"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/communication"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/integrity"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Manifest describes the content of a bundle, it is signed so the bundle can be trusted without asking the tenant.
+type Manifest struct {
+	ProcessModuleConfig *dtclient.ProcessModuleConfig `json:"processModuleConfig,omitempty"`
+	ConnectionInfo      ConnectionInfo                `json:"connectionInfo"`
+	// Version of the CodeModules in the bundle.
+	Version string `json:"version"`
+	// Image is the digest of the image manifest (or image index) inside the bundle, that contains the CodeModules.
+	Image string `json:"image"`
+}
+
+type ConnectionInfo struct {
+	TenantUUID  string `json:"tenantUUID"`
+	TenantToken string `json:"tenantToken"`
+	Endpoints   string `json:"endpoints"`
+}
+
+// AgentProcessModuleConfig returns the process module config of the bundle, extended with its connection info.
+func (manifest Manifest) AgentProcessModuleConfig() *dtclient.ProcessModuleConfig {
+	processModuleConfig := &dtclient.ProcessModuleConfig{}
+	if manifest.ProcessModuleConfig != nil {
+		processModuleConfig.Properties = append(processModuleConfig.Properties, manifest.ProcessModuleConfig.Properties...)
+		processModuleConfig.Revision = manifest.ProcessModuleConfig.Revision
+	}
+
+	connectionInfo := oneagent.ConnectionInfoStatus{
+		ConnectionInfo: communication.ConnectionInfo{
+			TenantUUID: manifest.ConnectionInfo.TenantUUID,
+			Endpoints:  manifest.ConnectionInfo.Endpoints,
+		},
+	}
+
+	return processModuleConfig.AddConnectionInfo(connectionInfo, manifest.ConnectionInfo.TenantToken)
+}
+
+func (manifest Manifest) validate() error {
+	// the version is used as a directory name
+	if manifest.Version == "" || manifest.Version != filepath.Base(manifest.Version) || manifest.Version == ".." {
+		return errors.Errorf("invalid version %q in bundle manifest", manifest.Version)
+	}
+
+	if _, err := containerv1.NewHash(manifest.Image); err != nil {
+		return errors.WithMessagef(err, "invalid image digest %q in bundle manifest", manifest.Image)
+	}
+
+	if manifest.ConnectionInfo.TenantUUID == "" || manifest.ConnectionInfo.TenantToken == "" || manifest.ConnectionInfo.Endpoints == "" {
+		return errors.New("incomplete connection info in bundle manifest")
+	}
+
+	return nil
+}
+
+// ReadManifest reads the manifest of the bundle at bundlePath and verifies its signature against the given PEM encoded public keys.
+// The bundle can either be an OCI image layout directory, or a (gzipped) tarball of one.
+func ReadManifest(fs afero.Fs, bundlePath string, trustedKeys []byte) (*Manifest, error) {
+	content, signature, err := readManifestFiles(fs, bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := integrity.VerifySignature(content, signature, trustedKeys); err != nil {
+		return nil, errors.WithMessagef(err, "failed to verify the manifest of bundle %s", bundlePath)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse the manifest of bundle %s", bundlePath)
+	}
+
+	if err := manifest.validate(); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+func readManifestFiles(fs afero.Fs, bundlePath string) (content, signature []byte, err error) {
+	isDir, err := afero.IsDir(fs, bundlePath)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	if isDir {
+		content, err = afero.ReadFile(fs, filepath.Join(bundlePath, ManifestFileName))
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+
+		signature, err = afero.ReadFile(fs, filepath.Join(bundlePath, SignatureFileName))
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+
+		return content, signature, nil
+	}
+
+	err = walkTarball(fs, bundlePath, func(header *tar.Header, reader io.Reader) error {
+		var readErr error
+
+		switch path.Clean(header.Name) {
+		case ManifestFileName:
+			content, readErr = io.ReadAll(reader)
+		case SignatureFileName:
+			signature, readErr = io.ReadAll(reader)
+		}
+
+		return readErr
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if content == nil || signature == nil {
+		return nil, nil, errors.Errorf("bundle %s contains no signed %s", bundlePath, ManifestFileName)
+	}
+
+	return content, signature, nil
+}
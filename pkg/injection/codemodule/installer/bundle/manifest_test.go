@@ -0,0 +1,298 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/klauspost/compress/gzip"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBundleDir     = "/bundles/codemodules"
+	testBundleTarball = "/bundles/codemodules.tar.gz"
+	testVersion       = "1.2.3.20240101-000000"
+)
+
+func TestReadManifest(t *testing.T) {
+	t.Run("read from directory", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundle.writeDir(t, fs)
+
+		manifest, err := ReadManifest(fs, testBundleDir, bundle.trustedKey)
+
+		require.NoError(t, err)
+		assert.Equal(t, bundle.manifest, *manifest)
+	})
+
+	t.Run("read from gzipped tarball", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+
+		manifest, err := ReadManifest(fs, bundle.writeTarball(t, fs, true), bundle.trustedKey)
+
+		require.NoError(t, err)
+		assert.Equal(t, bundle.manifest, *manifest)
+	})
+
+	t.Run("read from plain tarball", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+
+		manifest, err := ReadManifest(fs, bundle.writeTarball(t, fs, false), bundle.trustedKey)
+
+		require.NoError(t, err)
+		assert.Equal(t, bundle.manifest, *manifest)
+	})
+
+	t.Run("any of the trusted keys is accepted", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundle.writeDir(t, fs)
+
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		trustedKeys := append(encodePublicKey(t, &otherKey.PublicKey), bundle.trustedKey...)
+
+		_, err = ReadManifest(fs, testBundleDir, trustedKeys)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("untrusted key => error", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundle.writeDir(t, fs)
+
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		_, err = ReadManifest(fs, testBundleDir, encodePublicKey(t, &otherKey.PublicKey))
+
+		require.Error(t, err)
+	})
+
+	t.Run("no trusted keys => error", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundle.writeDir(t, fs)
+
+		_, err := ReadManifest(fs, testBundleDir, nil)
+
+		require.Error(t, err)
+	})
+
+	t.Run("tampered manifest => error", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundle.manifest.ConnectionInfo.Endpoints = "https://evil"
+		content, err := json.Marshal(bundle.manifest)
+		require.NoError(t, err)
+
+		bundle.files[ManifestFileName] = content
+		bundle.writeDir(t, fs)
+
+		_, err = ReadManifest(fs, testBundleDir, bundle.trustedKey)
+
+		require.Error(t, err)
+	})
+
+	t.Run("missing signature => error", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		delete(bundle.files, SignatureFileName)
+
+		_, err := ReadManifest(fs, bundle.writeTarball(t, fs, true), bundle.trustedKey)
+
+		require.Error(t, err)
+	})
+
+	t.Run("invalid version => error", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		bundle := newTestBundle(t, false)
+		bundle.manifest.Version = "../escape"
+		bundle.sign(t)
+		bundle.writeDir(t, fs)
+
+		_, err := ReadManifest(fs, testBundleDir, bundle.trustedKey)
+
+		require.Error(t, err)
+	})
+}
+
+func TestAgentProcessModuleConfig(t *testing.T) {
+	bundle := newTestBundle(t, false)
+
+	processModuleConfig := bundle.manifest.AgentProcessModuleConfig()
+	confMap := processModuleConfig.ToMap()
+
+	assert.Equal(t, uint(3), processModuleConfig.Revision)
+	assert.Equal(t, "/storage", confMap["general"]["storage"])
+	assert.Equal(t, "tenant", confMap["general"]["tenant"])
+	assert.Equal(t, "token", confMap["general"]["tenantToken"])
+	assert.Equal(t, "{https://endpoint}", confMap["general"]["serverAddress"])
+	assert.Len(t, bundle.manifest.ProcessModuleConfig.Properties, 1)
+}
+
+type testBundle struct {
+	files      map[string][]byte
+	key        *ecdsa.PrivateKey
+	manifest   Manifest
+	layerBlob  containerv1.Hash
+	trustedKey []byte
+}
+
+// newTestBundle creates the content of a signed bundle, that contains a single image with the test gzip layer of the zip package.
+// With withIndex the image is referenced via an image index, next to an image for another platform.
+func newTestBundle(t *testing.T, withIndex bool) *testBundle {
+	t.Helper()
+
+	bundle := &testBundle{files: map[string][]byte{}}
+
+	layer, err := base64.StdEncoding.DecodeString(zip.TestRawGzip)
+	require.NoError(t, err)
+
+	bundle.layerBlob = bundle.addBlob(layer)
+
+	imageManifest, err := json.Marshal(containerv1.Manifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		Config:        containerv1.Descriptor{MediaType: types.OCIConfigJSON, Digest: bundle.addBlob([]byte("{}")), Size: 2},
+		Layers: []containerv1.Descriptor{
+			{MediaType: types.OCILayer, Digest: bundle.layerBlob, Size: int64(len(layer))},
+		},
+	})
+	require.NoError(t, err)
+
+	image := bundle.addBlob(imageManifest)
+
+	if withIndex {
+		otherPlatform := containerv1.Platform{OS: "other", Architecture: "other"}
+
+		index, err := json.Marshal(containerv1.IndexManifest{
+			SchemaVersion: 2,
+			MediaType:     types.OCIImageIndex,
+			Manifests: []containerv1.Descriptor{
+				{MediaType: types.OCIManifestSchema1, Digest: bundle.addBlob([]byte("{}")), Platform: &otherPlatform},
+				{MediaType: types.OCIManifestSchema1, Digest: image, Platform: &arch.ImagePlatform},
+			},
+		})
+		require.NoError(t, err)
+
+		image = bundle.addBlob(index)
+	}
+
+	bundle.manifest = Manifest{
+		Version: testVersion,
+		Image:   image.String(),
+		ConnectionInfo: ConnectionInfo{
+			TenantUUID:  "tenant",
+			TenantToken: "token",
+			Endpoints:   "https://endpoint",
+		},
+		ProcessModuleConfig: &dtclient.ProcessModuleConfig{
+			Revision: 3,
+			Properties: []dtclient.ProcessModuleProperty{
+				{Section: "general", Key: "storage", Value: "/storage"},
+			},
+		},
+	}
+
+	bundle.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	bundle.trustedKey = encodePublicKey(t, &bundle.key.PublicKey)
+	bundle.sign(t)
+
+	return bundle
+}
+
+func (bundle *testBundle) addBlob(content []byte) containerv1.Hash {
+	digest := sha256.Sum256(content)
+	hash := containerv1.Hash{Algorithm: "sha256", Hex: hex.EncodeToString(digest[:])}
+	bundle.files[filepath.Join("blobs", hash.Algorithm, hash.Hex)] = content
+
+	return hash
+}
+
+func (bundle *testBundle) sign(t *testing.T) {
+	t.Helper()
+
+	content, err := json.Marshal(bundle.manifest)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(content)
+	signature, err := ecdsa.SignASN1(rand.Reader, bundle.key, digest[:])
+	require.NoError(t, err)
+
+	bundle.files[ManifestFileName] = content
+	bundle.files[SignatureFileName] = []byte(base64.StdEncoding.EncodeToString(signature))
+}
+
+func (bundle *testBundle) writeDir(t *testing.T, fs afero.Fs) {
+	t.Helper()
+
+	for name, content := range bundle.files {
+		require.NoError(t, fs.MkdirAll(filepath.Dir(filepath.Join(testBundleDir, name)), 0755))
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(testBundleDir, name), content, 0644))
+	}
+}
+
+func (bundle *testBundle) writeTarball(t *testing.T, fs afero.Fs, compressed bool) string {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	if !compressed {
+		tarWriter = tar.NewWriter(&buffer)
+	}
+
+	for name, content := range bundle.files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "./" + name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}))
+		_, err := tarWriter.Write(content)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+
+	if compressed {
+		require.NoError(t, gzipWriter.Close())
+	}
+
+	require.NoError(t, fs.MkdirAll(filepath.Dir(testBundleTarball), 0755))
+	require.NoError(t, afero.WriteFile(fs, testBundleTarball, buffer.Bytes(), 0644))
+
+	return testBundleTarball
+}
+
+func encodePublicKey(t *testing.T, key any) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
@@ -0,0 +1,97 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/common"
+	"github.com/klauspost/compress/gzip"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// walkTarball calls fn for every entry of the (optionally gzipped) tarball at tarballPath.
+func walkTarball(fs afero.Fs, tarballPath string, fn func(header *tar.Header, reader io.Reader) error) error {
+	file, err := fs.Open(tarballPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	bufferedReader := bufio.NewReader(file)
+
+	var reader io.Reader = bufferedReader
+
+	magic, err := bufferedReader.Peek(len(gzipMagic))
+	if err == nil && string(magic) == string(gzipMagic) {
+		gzipReader, err := gzip.NewReader(bufferedReader)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer func() { _ = gzipReader.Close() }()
+
+		reader = gzipReader
+	}
+
+	tarReader := tar.NewReader(reader)
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return errors.WithMessagef(err, "failed to read bundle tarball %s", tarballPath)
+		}
+
+		if err := fn(header, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// extractTarball unpacks the directories and regular files of the tarball into targetDir, an OCI layout contains nothing else.
+func extractTarball(fs afero.Fs, tarballPath, targetDir string) error {
+	targetDir = filepath.Clean(targetDir)
+
+	return walkTarball(fs, tarballPath, func(header *tar.Header, reader io.Reader) error {
+		target := filepath.Join(targetDir, header.Name)
+
+		// Check for ZipSlip: https://snyk.io/research/zip-slip-vulnerability
+		if target != targetDir && !strings.HasPrefix(target, targetDir+string(filepath.Separator)) {
+			return errors.Errorf("illegal file path: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			return errors.WithStack(fs.MkdirAll(target, common.MkDirFileMode))
+		case tar.TypeReg:
+			return extractFile(fs, target, reader)
+		default:
+			log.Info("skipping special file in bundle", "name", header.Name)
+
+			return nil
+		}
+	})
+}
+
+func extractFile(fs afero.Fs, target string, reader io.Reader) error {
+	if err := fs.MkdirAll(filepath.Dir(target), common.MkDirFileMode); err != nil {
+		return errors.WithStack(err)
+	}
+
+	file, err := fs.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	_, err = io.Copy(file, reader)
+
+	return errors.WithStack(err)
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/dockerkeychain"
 	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/signature"
 	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -26,6 +28,9 @@ type Properties struct {
 	Dynakube     *dynakube.DynaKube
 	PathResolver metadata.PathResolver
 	ImageDigest  string
+
+	// SizeCheck is called with the size of the layers of the image before they are pulled, optional.
+	SizeCheck installer.SizeCheck
 }
 
 func NewImageInstaller(ctx context.Context, fs afero.Fs, props *Properties) (installer.Installer, error) {
@@ -41,12 +46,24 @@ func NewImageInstaller(ctx context.Context, fs afero.Fs, props *Properties) (ins
 		return nil, err
 	}
 
+	var verifier *signature.Verifier
+
+	if props.Dynakube.ImageVerification().IsEnabled() {
+		policy, err := signature.NewPolicyForDynaKube(ctx, props.ApiReader, props.Dynakube)
+		if err != nil {
+			return nil, err
+		}
+
+		verifier = signature.NewVerifier(policy, remote.WithTransport(transport), remote.WithAuthFromKeychain(keychain))
+	}
+
 	return &Installer{
 		fs:        fs,
 		extractor: zip.NewOneAgentExtractor(fs, props.PathResolver),
 		props:     props,
 		transport: transport,
 		keychain:  keychain,
+		verifier:  verifier,
 	}, nil
 }
 
@@ -56,9 +73,10 @@ type Installer struct {
 	props     *Properties
 	transport http.RoundTripper
 	keychain  authn.Keychain
+	verifier  *signature.Verifier
 }
 
-func (installer *Installer) InstallAgent(_ context.Context, targetDir string) (bool, error) {
+func (installer *Installer) InstallAgent(ctx context.Context, targetDir string) (bool, error) {
 	log.Info("installing agent from image")
 
 	if installer.isAlreadyPresent(targetDir) {
@@ -67,16 +85,28 @@ func (installer *Installer) InstallAgent(_ context.Context, targetDir string) (b
 		return true, nil
 	}
 
-	err := installer.fs.MkdirAll(installer.props.PathResolver.AgentSharedBinaryDirBase(), common.MkDirFileMode)
+	// the verified image is pinned to its digest, so the pulled content is the one that was verified
+	image, err := installer.verifier.Verify(ctx, installer.props.ImageUri)
+	if err != nil {
+		log.Info("image failed the verification, agent is not installed", "image", installer.props.ImageUri, "err", err)
+
+		return false, err
+	}
+
+	if err := installer.checkSize(image); err != nil {
+		return false, err
+	}
+
+	err = installer.fs.MkdirAll(installer.props.PathResolver.AgentSharedBinaryDirBase(), common.MkDirFileMode)
 	if err != nil {
 		log.Info("failed to create the base shared agent directory", "err", err)
 
 		return false, errors.WithStack(err)
 	}
 
-	log.Info("installing agent", "image", installer.props.ImageUri, "target dir", targetDir)
+	log.Info("installing agent", "image", image, "target dir", targetDir)
 
-	if err := installer.installAgentFromImage(targetDir); err != nil {
+	if err := installer.installAgentFromImage(targetDir, image); err != nil {
 		_ = installer.fs.RemoveAll(targetDir)
 
 		log.Info("failed to install agent from image", "err", err)
@@ -95,7 +125,7 @@ func (installer *Installer) InstallAgent(_ context.Context, targetDir string) (b
 	return true, nil
 }
 
-func (installer *Installer) installAgentFromImage(targetDir string) error {
+func (installer *Installer) installAgentFromImage(targetDir string, image string) error {
 	defer func() { _ = installer.fs.RemoveAll(CacheDir) }()
 
 	err := installer.fs.MkdirAll(CacheDir, common.MkDirFileMode)
@@ -105,7 +135,6 @@ func (installer *Installer) installAgentFromImage(targetDir string) error {
 		return errors.WithStack(err)
 	}
 
-	image := installer.props.ImageUri
 	imageCacheDir := getCacheDirPath(installer.props.ImageDigest)
 
 	err = installer.extractAgentBinariesFromImage(
@@ -113,7 +142,7 @@ func (installer *Installer) installAgentFromImage(targetDir string) error {
 			imageCacheDir: imageCacheDir,
 			targetDir:     targetDir,
 		},
-		installer.props.ImageUri,
+		image,
 	)
 	if err != nil {
 		log.Info("failed to extract agent binaries from image via proxy", "image", image, "imageCacheDir", imageCacheDir, "err", err)
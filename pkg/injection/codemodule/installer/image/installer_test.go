@@ -13,6 +13,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/signature"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -107,6 +108,7 @@ func TestInstaller_InstallAgent(t *testing.T) {
 		extractor zip.Extractor
 		props     *Properties
 		transport http.RoundTripper
+		verifier  *signature.Verifier
 	}
 
 	type args struct {
@@ -151,6 +153,22 @@ func TestInstaller_InstallAgent(t *testing.T) {
 			args: args{targetDir: consts.AgentBinDirMount},
 			want: true, wantErr: require.NoError,
 		},
+		{
+			name: "Image failing the verification is not installed",
+			fields: fields{
+				fs:        afero.NewMemMapFs(),
+				extractor: nil,
+				props: &Properties{
+					PathResolver: metadata.PathResolver{RootDir: "/tmp"},
+					ImageUri:     testImageURL,
+					ImageDigest:  testImageDigest,
+				},
+				transport: transport,
+				verifier:  signature.NewVerifier(signature.Policy{TrustedRegistries: []string{"public.ecr.aws/dynatrace"}}),
+			},
+			args: args{targetDir: consts.AgentBinDirMount},
+			want: false, wantErr: require.Error,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -159,6 +177,7 @@ func TestInstaller_InstallAgent(t *testing.T) {
 				extractor: tt.fields.extractor,
 				props:     tt.fields.props,
 				transport: tt.fields.transport,
+				verifier:  tt.fields.verifier,
 			}
 
 			got, err := installer.InstallAgent(ctx, tt.args.targetDir)
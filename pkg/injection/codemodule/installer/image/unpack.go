@@ -41,6 +41,31 @@ func (installer *Installer) extractAgentBinariesFromImage(pullInfo imagePullInfo
 	return nil
 }
 
+// checkSize passes the size of the layers of the image to the SizeCheck, only the manifest is pulled for it.
+func (installer *Installer) checkSize(imageName string) error {
+	if installer.props.SizeCheck == nil {
+		return nil
+	}
+
+	img, err := installer.pullImageInfo(imageName)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := (*img).Manifest()
+	if err != nil {
+		return errors.WithMessagef(err, "getting manifest of image %q", imageName)
+	}
+
+	var sizeBytes int64
+
+	for _, layer := range manifest.Layers {
+		sizeBytes += layer.Size
+	}
+
+	return installer.props.SizeCheck(sizeBytes)
+}
+
 func (installer *Installer) pullImageInfo(imageName string) (*containerv1.Image, error) {
 	ref, err := name.ParseReference(imageName)
 	if err != nil {
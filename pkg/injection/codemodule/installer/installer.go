@@ -5,3 +5,6 @@ import "context"
 type Installer interface {
 	InstallAgent(ctx context.Context, targetDir string) (bool, error)
 }
+
+// SizeCheck is called by an installer with the size of the CodeModules before they are unpacked, an error aborts the install.
+type SizeCheck func(sizeBytes int64) error
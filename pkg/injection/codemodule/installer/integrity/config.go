@@ -0,0 +1,9 @@
+package integrity
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+)
+
+var (
+	log = logd.Get().WithName("oneagent-integrity")
+)
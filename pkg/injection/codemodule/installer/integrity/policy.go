@@ -0,0 +1,64 @@
+package integrity
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrVerificationFailed is returned (wrapped) if a downloaded file doesn't match what was published, in contrast to errors while reading it.
+var ErrVerificationFailed = errors.New("integrity verification failed")
+
+// Policy describes what a downloaded file has to match before it may be used.
+type Policy struct {
+	// SHA256 is the hex encoded SHA-256 digest the file must have, it is mandatory.
+	SHA256 string
+	// Signature is the base64 encoded detached signature of the file, optional unless RequireSignature is set.
+	Signature []byte
+	// TrustedKeys are the PEM encoded public keys the Signature is checked against.
+	TrustedKeys []byte
+
+	RequireSignature bool
+}
+
+// Verify reads the whole content of reader and checks it against the policy.
+// Mismatches are reported as errors wrapping ErrVerificationFailed.
+func (policy Policy) Verify(reader io.Reader) error {
+	expectedDigest, err := hex.DecodeString(strings.TrimSpace(policy.SHA256))
+	if err != nil || len(expectedDigest) != sha256.Size {
+		return errors.WithMessagef(ErrVerificationFailed, "invalid expected sha256 digest %q", policy.SHA256)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return errors.WithStack(err)
+	}
+
+	digest := hash.Sum(nil)
+
+	if !bytes.Equal(digest, expectedDigest) {
+		return errors.WithMessagef(ErrVerificationFailed, "sha256 digest mismatch, expected %x but got %x", expectedDigest, digest)
+	}
+
+	if len(policy.Signature) == 0 {
+		if policy.RequireSignature {
+			return errors.WithMessage(ErrVerificationFailed, "signature is required but none was provided")
+		}
+
+		log.Info("sha256 digest verified, no signature provided")
+
+		return nil
+	}
+
+	if err := VerifyDigestSignature(digest, policy.Signature, policy.TrustedKeys); err != nil {
+		return errors.WithMessage(ErrVerificationFailed, err.Error())
+	}
+
+	log.Info("sha256 digest and signature verified")
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package integrity
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyVerify(t *testing.T) {
+	content := []byte("agent archive")
+	digest := sha256.Sum256(content)
+	validDigest := hex.EncodeToString(digest[:])
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signature := signECDSA(t, privateKey, content)
+	trustedKeys := encodePublicKey(t, &privateKey.PublicKey)
+
+	t.Run("matching digest", func(t *testing.T) {
+		policy := Policy{SHA256: validDigest + "\n"}
+
+		require.NoError(t, policy.Verify(bytes.NewReader(content)))
+	})
+
+	t.Run("matching digest and signature", func(t *testing.T) {
+		policy := Policy{SHA256: validDigest, Signature: signature, TrustedKeys: trustedKeys, RequireSignature: true}
+
+		require.NoError(t, policy.Verify(bytes.NewReader(content)))
+	})
+
+	t.Run("digest mismatch => verification failed", func(t *testing.T) {
+		policy := Policy{SHA256: validDigest}
+
+		err := policy.Verify(bytes.NewReader([]byte("tampered")))
+
+		require.ErrorIs(t, err, ErrVerificationFailed)
+	})
+
+	t.Run("missing digest => verification failed", func(t *testing.T) {
+		policy := Policy{}
+
+		err := policy.Verify(bytes.NewReader(content))
+
+		require.ErrorIs(t, err, ErrVerificationFailed)
+	})
+
+	t.Run("missing required signature => verification failed", func(t *testing.T) {
+		policy := Policy{SHA256: validDigest, RequireSignature: true}
+
+		err := policy.Verify(bytes.NewReader(content))
+
+		require.ErrorIs(t, err, ErrVerificationFailed)
+	})
+
+	t.Run("signature of untrusted key => verification failed", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		policy := Policy{SHA256: validDigest, Signature: signature, TrustedKeys: encodePublicKey(t, &otherKey.PublicKey)}
+
+		err = policy.Verify(bytes.NewReader(content))
+
+		require.ErrorIs(t, err, ErrVerificationFailed)
+	})
+}
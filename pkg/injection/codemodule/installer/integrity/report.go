@@ -0,0 +1,58 @@
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/configmap"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// ReportComponentLabel marks the ConfigMaps the csi-provisioners report the outcome of the CodeModules verification on their node with.
+	ReportComponentLabel = "codemodules-verification"
+
+	ReportNodeKey     = "node"
+	ReportVersionKey  = "version"
+	ReportVerifiedKey = "verified"
+	ReportMessageKey  = "message"
+
+	reportNameHashLength = 10
+)
+
+// ReportName is the ConfigMap, in the namespace of the DynaKube, where the csi-provisioner of the node reports the outcome of the verification.
+// Every node has its own ConfigMap, so the nodes never write the same object, the operator aggregates them in the status of the DynaKube.
+// Node names can be as long as the ConfigMap name is allowed to be, so a name that is too long is truncated and made unique again by the hash of the node name.
+func ReportName(dkName, nodeName string) string {
+	name := dkName + "-codemodules-verification-" + nodeName
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(nodeName))
+	suffix := "-" + hex.EncodeToString(hash[:])[:reportNameHashLength]
+
+	return strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.") + suffix
+}
+
+// BuildReport creates the ConfigMap that reports the outcome of the verification of the CodeModules version on the node.
+func BuildReport(dk *dynakube.DynaKube, nodeName, version string, verificationErr error) (*corev1.ConfigMap, error) {
+	data := map[string]string{
+		ReportNodeKey:     nodeName,
+		ReportVersionKey:  version,
+		ReportVerifiedKey: strconv.FormatBool(verificationErr == nil),
+	}
+
+	if verificationErr != nil {
+		data[ReportMessageKey] = verificationErr.Error()
+	}
+
+	return configmap.Build(dk, ReportName(dk.Name, nodeName), data,
+		configmap.SetLabels(labels.NewCoreLabels(dk.Name, ReportComponentLabel).BuildLabels()),
+	)
+}
@@ -0,0 +1,28 @@
+package integrity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestReportName(t *testing.T) {
+	t.Run("short node name is used as it is", func(t *testing.T) {
+		assert.Equal(t, "dynakube-codemodules-verification-node-1", ReportName("dynakube", "node-1"))
+	})
+
+	t.Run("long node name is truncated to a valid and unique name", func(t *testing.T) {
+		nodeName := strings.Repeat("a", 230) + ".compute.internal"
+		otherNodeName := strings.Repeat("a", 230) + ".compute.external"
+
+		name := ReportName("dynakube", nodeName)
+
+		assert.Len(t, name, validation.DNS1123SubdomainMaxLength)
+		assert.Empty(t, validation.IsDNS1123Subdomain(name))
+		assert.True(t, strings.HasPrefix(name, "dynakube-codemodules-verification-aaa"))
+		assert.NotEqual(t, name, ReportName("dynakube", otherNodeName))
+		assert.Equal(t, name, ReportName("dynakube", nodeName))
+	})
+}
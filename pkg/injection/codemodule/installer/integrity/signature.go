@@ -0,0 +1,99 @@
+package integrity
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// VerifySignature verifies the base64 encoded signature of content against the given PEM encoded public keys.
+// ECDSA and RSA (PKCS #1 v1.5) signatures are expected over the SHA-256 digest of content, ed25519 signatures over content itself.
+func VerifySignature(content, encodedSignature, trustedKeys []byte) error {
+	digest := sha256.Sum256(content)
+
+	return verifySignature(digest[:], content, encodedSignature, trustedKeys)
+}
+
+// VerifyDigestSignature works like VerifySignature, but only needs the SHA-256 digest of the content, so big files don't have to be kept in memory.
+// Because of that ed25519 keys are not supported.
+func VerifyDigestSignature(digest, encodedSignature, trustedKeys []byte) error {
+	return verifySignature(digest, nil, encodedSignature, trustedKeys)
+}
+
+// SignatureFromCosignBundle returns the base64 encoded signature from a bundle created by `cosign sign-blob --bundle`.
+// Only key based signatures are supported, the certificate and transparency log entry of keyless signatures are not checked.
+func SignatureFromCosignBundle(cosignBundle []byte) ([]byte, error) {
+	var parsed struct {
+		Base64Signature string `json:"base64Signature"`
+	}
+
+	if err := json.Unmarshal(cosignBundle, &parsed); err != nil {
+		return nil, errors.WithMessage(err, "failed to parse cosign bundle")
+	}
+
+	if parsed.Base64Signature == "" {
+		return nil, errors.New("cosign bundle contains no signature")
+	}
+
+	return []byte(parsed.Base64Signature), nil
+}
+
+func verifySignature(digest, content, encodedSignature, trustedKeys []byte) error {
+	signature, err := base64.StdEncoding.DecodeString(string(encodedSignature))
+	if err != nil {
+		return errors.WithMessage(err, "signature is not base64 encoded")
+	}
+
+	foundKey := false
+
+	for block, rest := pem.Decode(trustedKeys); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return errors.WithMessage(err, "failed to parse trusted key")
+		}
+
+		foundKey = true
+
+		switch key := publicKey.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, digest, signature) {
+				return nil
+			}
+		case ed25519.PublicKey:
+			if content == nil {
+				log.Info("ignoring ed25519 trusted key, it can't verify a signature by digest")
+
+				continue
+			}
+
+			if ed25519.Verify(key, content, signature) {
+				return nil
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature) == nil {
+				return nil
+			}
+		default:
+			log.Info("ignoring trusted key of unsupported type", "type", fmt.Sprintf("%T", publicKey))
+		}
+	}
+
+	if !foundKey {
+		return errors.New("no trusted keys available")
+	}
+
+	return errors.New("signature does not match any trusted key")
+}
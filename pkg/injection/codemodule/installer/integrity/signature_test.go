@@ -0,0 +1,145 @@
+package integrity
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	content := []byte("content")
+
+	t.Run("ecdsa", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		signature := signECDSA(t, privateKey, content)
+
+		require.NoError(t, VerifySignature(content, signature, encodePublicKey(t, &privateKey.PublicKey)))
+		require.Error(t, VerifySignature([]byte("other"), signature, encodePublicKey(t, &privateKey.PublicKey)))
+	})
+
+	t.Run("rsa", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		digest := sha256.Sum256(content)
+		rawSignature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+
+		signature := []byte(base64.StdEncoding.EncodeToString(rawSignature))
+
+		require.NoError(t, VerifySignature(content, signature, encodePublicKey(t, &privateKey.PublicKey)))
+	})
+
+	t.Run("ed25519", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, content))
+
+		require.NoError(t, VerifySignature(content, []byte(signature+"\n"), encodePublicKey(t, publicKey)))
+		require.Error(t, VerifySignature([]byte("other"), []byte(signature), encodePublicKey(t, publicKey)))
+	})
+
+	t.Run("any of multiple keys", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		trustedKeys := append(encodePublicKey(t, &otherKey.PublicKey), encodePublicKey(t, &privateKey.PublicKey)...)
+
+		require.NoError(t, VerifySignature(content, signECDSA(t, privateKey, content), trustedKeys))
+	})
+
+	t.Run("no trusted keys => error", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		err = VerifySignature(content, signECDSA(t, privateKey, content), nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no trusted keys")
+	})
+
+	t.Run("signature not base64 encoded", func(t *testing.T) {
+		publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		require.Error(t, VerifySignature(content, []byte("not base64!"), encodePublicKey(t, publicKey)))
+	})
+}
+
+func TestVerifyDigestSignature(t *testing.T) {
+	content := []byte("content")
+	digest := sha256.Sum256(content)
+
+	t.Run("ecdsa", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		require.NoError(t, VerifyDigestSignature(digest[:], signECDSA(t, privateKey, content), encodePublicKey(t, &privateKey.PublicKey)))
+	})
+
+	t.Run("ed25519 is not supported", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, content))
+
+		require.Error(t, VerifyDigestSignature(digest[:], []byte(signature), encodePublicKey(t, publicKey)))
+	})
+}
+
+func TestSignatureFromCosignBundle(t *testing.T) {
+	t.Run("signature is extracted", func(t *testing.T) {
+		signature, err := SignatureFromCosignBundle([]byte(`{"base64Signature":"c2lnbmF0dXJl","cert":"","rekorBundle":{}}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, "c2lnbmF0dXJl", string(signature))
+	})
+
+	t.Run("no signature => error", func(t *testing.T) {
+		_, err := SignatureFromCosignBundle([]byte(`{"cert":""}`))
+
+		require.Error(t, err)
+	})
+
+	t.Run("invalid json => error", func(t *testing.T) {
+		_, err := SignatureFromCosignBundle([]byte(`{`))
+
+		require.Error(t, err)
+	})
+}
+
+func signECDSA(t *testing.T, key *ecdsa.PrivateKey, content []byte) []byte {
+	t.Helper()
+
+	digest := sha256.Sum256(content)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	return []byte(base64.StdEncoding.EncodeToString(signature))
+}
+
+func encodePublicKey(t *testing.T, key any) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/common"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/integrity"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/symlink"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
 	"github.com/pkg/errors"
@@ -24,6 +25,12 @@ type Properties struct {
 	TargetVersion string
 	Url           string // if this is set all settings before it will be ignored
 
+	// Verification is checked against the downloaded archive before it is unpacked, optional.
+	Verification *integrity.Policy
+
+	// SizeCheck is called with the unpacked size of the downloaded archive, optional.
+	SizeCheck installer.SizeCheck
+
 	PathResolver metadata.PathResolver
 	Technologies []string
 	SkipMetadata bool
@@ -108,11 +115,14 @@ func (installer Installer) installAgent(ctx context.Context, targetDir string) e
 		return errors.WithStack(err)
 	}
 
+	// the name is kept, as in-memory filesystems report the new name after a rename, which would remove the quarantined file
+	tmpPath := tmpFile.Name()
+
 	defer func() {
 		_ = tmpFile.Close()
 
-		if err := fs.Remove(tmpFile.Name()); err != nil {
-			log.Error(err, "failed to delete downloaded file", "path", tmpFile.Name())
+		if err := fs.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+			log.Error(err, "failed to delete downloaded file", "path", tmpPath)
 		}
 	}()
 
@@ -120,6 +130,18 @@ func (installer Installer) installAgent(ctx context.Context, targetDir string) e
 		return err
 	}
 
+	if err := installer.verifyDownload(tmpFile); err != nil {
+		if errors.Is(err, integrity.ErrVerificationFailed) {
+			installer.quarantine(targetDir, tmpPath)
+		}
+
+		return err
+	}
+
+	if err := installer.checkSize(tmpFile); err != nil {
+		return err
+	}
+
 	return installer.unpackOneAgentZip(targetDir, tmpFile)
 }
 
@@ -148,6 +148,57 @@ func TestInstallAgentFromUrl(t *testing.T) {
 		err := installer.installAgent(ctx, testDir)
 		require.NoError(t, err)
 	})
+	t.Run(`size check refuses the download before it is unzipped`, func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		dtc := dtclientmock.NewClient(t)
+		dtc.On("GetAgent",
+			mock.AnythingOfType("context.backgroundCtx"),
+			dtclient.OsUnix,
+			dtclient.InstallerTypePaaS,
+			arch.FlavorMultidistro,
+			mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"),
+			mock.AnythingOfType("[]string"),
+			mock.AnythingOfType("bool"),
+			mock.AnythingOfType("*mem.File"),
+		).
+			Run(func(args mock.Arguments) {
+				writer, _ := args.Get(8).(io.Writer)
+
+				zipFile := zip.SetupTestArchive(t, fs, zip.TestRawZip)
+				defer func() { _ = zipFile.Close() }()
+
+				_, err := io.Copy(writer, zipFile)
+				require.NoError(t, err)
+			}).
+			Return(nil)
+
+		var checkedSize int64
+
+		installer := &Installer{
+			fs:        fs,
+			dtc:       dtc,
+			extractor: zip.NewOneAgentExtractor(fs, metadata.PathResolver{}),
+			props: &Properties{
+				Os:            dtclient.OsUnix,
+				Type:          dtclient.InstallerTypePaaS,
+				Flavor:        arch.FlavorMultidistro,
+				TargetVersion: testVersion,
+				SizeCheck: func(sizeBytes int64) error {
+					checkedSize = sizeBytes
+
+					return errors.New(testErrorMessage)
+				},
+			},
+		}
+
+		err := installer.installAgent(ctx, testDir)
+		require.ErrorContains(t, err, testErrorMessage)
+		assert.Positive(t, checkedSize)
+
+		exists, _ := afero.DirExists(fs, testDir)
+		assert.False(t, exists)
+	})
 	t.Run(`downloading and unzipping latest agent`, func(t *testing.T) {
 		fs := afero.NewMemMapFs()
 		dtc := dtclientmock.NewClient(t)
@@ -1,10 +1,37 @@
 package url
 
 import (
+	"archive/zip"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 )
 
+// checkSize passes the size of the unpacked archive to the SizeCheck, which is taken from the zip directory without unpacking anything.
+func (installer Installer) checkSize(tmpFile afero.File) error {
+	if installer.props.SizeCheck == nil {
+		return nil
+	}
+
+	stat, err := tmpFile.Stat()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	reader, err := zip.NewReader(tmpFile, stat.Size())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var sizeBytes int64
+
+	for _, file := range reader.File {
+		sizeBytes += int64(file.UncompressedSize64) //nolint:gosec
+	}
+
+	return installer.props.SizeCheck(sizeBytes)
+}
+
 func (installer Installer) unpackOneAgentZip(targetDir string, tmpFile afero.File) error {
 	var fileSize int64
 	if stat, err := tmpFile.Stat(); err == nil {
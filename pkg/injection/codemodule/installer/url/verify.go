@@ -0,0 +1,97 @@
+package url
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	quarantinedArchiveName = "agent.zip"
+	quarantinedPartialName = "partial"
+
+	// maxQuarantineEntries limits how many failed downloads are kept, so a persistent mismatch doesn't fill up the disk.
+	maxQuarantineEntries = 3
+)
+
+func (installer Installer) verifyDownload(tmpFile afero.File) error {
+	if installer.props.Verification == nil {
+		return nil
+	}
+
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := installer.props.Verification.Verify(tmpFile); err != nil {
+		log.Info("downloaded OneAgent package failed its integrity verification", "err", err.Error())
+
+		return err
+	}
+
+	_, err := tmpFile.Seek(0, io.SeekStart)
+
+	return errors.WithStack(err)
+}
+
+// quarantine moves the downloaded archive, and whatever already exists of targetDir, out of the way, so it can be inspected but never used.
+// Errors are only logged, as the failed verification is what needs to be reported.
+func (installer Installer) quarantine(targetDir, archivePath string) {
+	if installer.isInitContainerMode() {
+		return
+	}
+
+	fs := installer.fs
+	quarantineDir := installer.props.PathResolver.AgentQuarantineDirForAgent(fmt.Sprintf("%s-%d", filepath.Base(targetDir), time.Now().Unix()))
+
+	if err := fs.MkdirAll(quarantineDir, common.MkDirFileMode); err != nil {
+		log.Error(err, "failed to create quarantine directory", "path", quarantineDir)
+
+		return
+	}
+
+	if err := fs.Rename(archivePath, filepath.Join(quarantineDir, quarantinedArchiveName)); err != nil {
+		log.Error(err, "failed to quarantine downloaded OneAgent package", "path", archivePath)
+	}
+
+	if _, err := fs.Stat(targetDir); err == nil {
+		if err := fs.Rename(targetDir, filepath.Join(quarantineDir, quarantinedPartialName)); err != nil {
+			log.Error(err, "failed to quarantine partial OneAgent installation", "path", targetDir)
+		}
+	}
+
+	log.Info("quarantined OneAgent package", "path", quarantineDir)
+
+	installer.pruneQuarantine()
+}
+
+func (installer Installer) pruneQuarantine() {
+	entries, err := afero.ReadDir(installer.fs, installer.props.PathResolver.AgentQuarantineDir())
+	if err != nil {
+		log.Info("failed to list quarantined OneAgent packages", "err", err.Error())
+
+		return
+	}
+
+	if len(entries) <= maxQuarantineEntries {
+		return
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime().After(entries[j].ModTime())
+	})
+
+	for _, entry := range entries[maxQuarantineEntries:] {
+		path := installer.props.PathResolver.AgentQuarantineDirForAgent(entry.Name())
+		if err := installer.fs.RemoveAll(path); err != nil && !os.IsNotExist(err) {
+			log.Error(err, "failed to remove old quarantined OneAgent package", "path", path)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package url
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
+	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/integrity"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/zip"
+	dtclientmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/clients/dynatrace"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallAgentWithVerification(t *testing.T) {
+	ctx := context.Background()
+	pathResolver := metadata.PathResolver{RootDir: "/data"}
+	targetDir := pathResolver.AgentSharedBinaryDirForAgent(testVersion)
+
+	rawZip, err := base64.StdEncoding.DecodeString(zip.TestRawZip)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(rawZip)
+
+	t.Run("matching digest => agent is unpacked", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		installer := createVerifyingInstaller(t, fs, pathResolver, hex.EncodeToString(digest[:]))
+
+		err := installer.installAgent(ctx, targetDir)
+
+		require.NoError(t, err)
+		assertFileExists(t, fs, filepath.Join(targetDir, zip.TestZipFilename))
+		assertQuarantineEntries(t, fs, pathResolver, 0)
+	})
+
+	t.Run("digest mismatch => nothing is unpacked, download is quarantined", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		installer := createVerifyingInstaller(t, fs, pathResolver, hex.EncodeToString(make([]byte, sha256.Size)))
+
+		err := installer.installAgent(ctx, targetDir)
+
+		require.ErrorIs(t, err, integrity.ErrVerificationFailed)
+
+		exists, err := afero.Exists(fs, filepath.Join(targetDir, zip.TestZipFilename))
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		entries := assertQuarantineEntries(t, fs, pathResolver, 1)
+		assertFileExists(t, fs, filepath.Join(pathResolver.AgentQuarantineDirForAgent(entries[0].Name()), quarantinedArchiveName))
+
+		downloads, err := afero.Glob(fs, filepath.Join(pathResolver.AgentSharedBinaryDirBase(), "download*"))
+		require.NoError(t, err)
+		assert.Empty(t, downloads)
+	})
+}
+
+func TestPruneQuarantine(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	pathResolver := metadata.PathResolver{RootDir: "/data"}
+	installer := &Installer{
+		fs:    fs,
+		props: &Properties{PathResolver: pathResolver},
+	}
+
+	now := time.Now()
+
+	for i := range maxQuarantineEntries + 2 {
+		path := pathResolver.AgentQuarantineDirForAgent(fmt.Sprintf("entry-%d", i))
+		require.NoError(t, fs.MkdirAll(path, os.ModePerm))
+		require.NoError(t, fs.Chtimes(path, now, now.Add(time.Duration(i)*time.Minute)))
+	}
+
+	installer.pruneQuarantine()
+
+	entries := assertQuarantineEntries(t, fs, pathResolver, maxQuarantineEntries)
+	assert.Equal(t, "entry-2", entries[0].Name())
+}
+
+func createVerifyingInstaller(t *testing.T, fs afero.Fs, pathResolver metadata.PathResolver, sha256Digest string) *Installer {
+	t.Helper()
+
+	require.NoError(t, fs.MkdirAll(pathResolver.AgentSharedBinaryDirBase(), os.ModePerm))
+
+	dtc := dtclientmock.NewClient(t)
+	dtc.On("GetAgent",
+		mock.AnythingOfType("context.backgroundCtx"),
+		dtclient.OsUnix,
+		dtclient.InstallerTypePaaS,
+		arch.FlavorMultidistro,
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("[]string"),
+		mock.AnythingOfType("bool"),
+		mock.AnythingOfType("*mem.File"),
+	).
+		Run(func(args mock.Arguments) {
+			writer, _ := args.Get(8).(io.Writer)
+
+			zipFile := zip.SetupTestArchive(t, fs, zip.TestRawZip)
+			defer func() { _ = zipFile.Close() }()
+
+			_, err := io.Copy(writer, zipFile)
+			require.NoError(t, err)
+		}).
+		Return(nil)
+
+	return &Installer{
+		fs:        fs,
+		dtc:       dtc,
+		extractor: zip.NewOneAgentExtractor(fs, pathResolver),
+		props: &Properties{
+			Os:            dtclient.OsUnix,
+			Type:          dtclient.InstallerTypePaaS,
+			Flavor:        arch.FlavorMultidistro,
+			TargetVersion: testVersion,
+			PathResolver:  pathResolver,
+			Verification:  &integrity.Policy{SHA256: sha256Digest},
+		},
+	}
+}
+
+func assertFileExists(t *testing.T, fs afero.Fs, path string) {
+	t.Helper()
+
+	exists, err := afero.Exists(fs, path)
+	require.NoError(t, err)
+	assert.True(t, exists, path)
+}
+
+func assertQuarantineEntries(t *testing.T, fs afero.Fs, pathResolver metadata.PathResolver, expected int) []os.FileInfo {
+	t.Helper()
+
+	entries, err := afero.ReadDir(fs, pathResolver.AgentQuarantineDir())
+	if expected == 0 && os.IsNotExist(err) {
+		return nil
+	}
+
+	require.NoError(t, err)
+	require.Len(t, entries, expected)
+
+	return entries
+}
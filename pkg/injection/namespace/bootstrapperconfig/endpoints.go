@@ -7,11 +7,10 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/ingestendpoint"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/conditions"
-	k8ssecret "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/secret"
 	"github.com/pkg/errors"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (s *SecretGenerator) prepareEndpoints(ctx context.Context, dk *dynakube.DynaKube) (string, error) {
@@ -42,7 +41,7 @@ func (s *SecretGenerator) prepareEndpoints(ctx context.Context, dk *dynakube.Dyn
 func (s *SecretGenerator) prepareFieldsForEndpoints(ctx context.Context, dk *dynakube.DynaKube) (map[string]string, error) {
 	fields := make(map[string]string)
 
-	tokens, err := k8ssecret.Query(s.client, s.apiReader, log).Get(ctx, client.ObjectKey{Name: dk.Tokens(), Namespace: dk.Namespace})
+	tokens, err := token.NewReader(s.apiReader, dk).ReadUnverifiedTokens(ctx)
 	if err != nil {
 		conditions.SetKubeApiError(dk.Conditions(), ConditionType, err)
 
@@ -50,8 +49,8 @@ func (s *SecretGenerator) prepareFieldsForEndpoints(ctx context.Context, dk *dyn
 	}
 
 	if dk.MetadataEnrichmentEnabled() {
-		if token, ok := tokens.Data[dtclient.DataIngestToken]; ok {
-			fields[dtingestendpoint.MetricsTokenSecretField] = string(token)
+		if dataIngestToken, ok := tokens[dtclient.DataIngestToken]; ok {
+			fields[dtingestendpoint.MetricsTokenSecretField] = dataIngestToken.Value
 		} else {
 			log.Info("data ingest token not found in secret", "dk", dk.Name)
 		}
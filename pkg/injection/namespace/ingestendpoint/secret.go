@@ -10,6 +10,7 @@ import (
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
 	k8ssecret "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/secret"
@@ -150,14 +151,14 @@ func (g *SecretGenerator) prepare(ctx context.Context, dk *dynakube.DynaKube) (m
 func (g *SecretGenerator) PrepareFields(ctx context.Context, dk *dynakube.DynaKube) (map[string]string, error) {
 	fields := make(map[string]string)
 
-	tokens, err := k8ssecret.Query(g.client, g.apiReader, log).Get(ctx, client.ObjectKey{Name: dk.Tokens(), Namespace: g.namespace})
+	tokens, err := token.NewReader(g.apiReader, dk).ReadUnverifiedTokens(ctx)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to query tokens")
 	}
 
 	if dk.MetadataEnrichmentEnabled() { // TODO: why check here and not at the very beginning?
-		if token, ok := tokens.Data[dtclient.DataIngestToken]; ok {
-			fields[MetricsTokenSecretField] = string(token)
+		if dataIngestToken, ok := tokens[dtclient.DataIngestToken]; ok {
+			fields[MetricsTokenSecretField] = dataIngestToken.Value
 		} else {
 			log.Info("data ingest token not found in secret", "dk", dk.Name)
 		}
@@ -6,9 +6,10 @@ import (
 	"strings"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
-	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/activegate/capability"
+	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/dynakube/token"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/bundle"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/mapper"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/startup"
 	k8slabels "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/labels"
@@ -109,6 +110,12 @@ func (g *InitGenerator) Cleanup(ctx context.Context, namespaces []corev1.Namespa
 
 // generate gets the necessary info the create the init secret data
 func (g *InitGenerator) generate(ctx context.Context, dk *dynakube.DynaKube) (map[string][]byte, error) {
+	// the tenant UUID of a bundle is not in the status, so it is added to a copy that is never persisted
+	dk = dk.DeepCopy()
+	if err := bundle.ApplyConnectionInfo(ctx, g.apiReader, dk); err != nil {
+		return nil, err
+	}
+
 	hostMonitoringNodes, err := g.getHostMonitoringNodes(dk)
 	if err != nil {
 		return nil, err
@@ -138,14 +145,12 @@ func (g *InitGenerator) generate(ctx context.Context, dk *dynakube.DynaKube) (ma
 }
 
 func (g *InitGenerator) createSecretConfigForDynaKube(ctx context.Context, dk *dynakube.DynaKube, hostMonitoringNodes map[string]string) (*startup.SecretConfig, error) {
-	var tokens corev1.Secret
-	if err := g.client.Get(ctx, client.ObjectKey{Name: dk.Tokens(), Namespace: g.namespace}, &tokens); err != nil {
+	tokens, err := token.NewReader(g.apiReader, dk).ReadUnverifiedTokens(ctx)
+	if err != nil {
 		return nil, errors.WithMessage(err, "failed to query tokens")
 	}
 
 	var proxy string
-
-	var err error
 	if dk.NeedsOneAgentProxy() {
 		proxy, err = dk.Proxy(ctx, g.apiReader)
 		if err != nil {
@@ -184,16 +189,25 @@ func (g *InitGenerator) createSecretConfigForDynaKube(ctx context.Context, dk *d
 	}, nil
 }
 
-func getPaasToken(tokens corev1.Secret) string {
-	if len(tokens.Data[dtclient.PaasToken]) != 0 {
-		return string(tokens.Data[dtclient.PaasToken])
+func getPaasToken(tokens token.Tokens) string {
+	if paasToken := getAgentToken(tokens.PaasToken()); paasToken != "" {
+		return paasToken
 	}
 
-	return string(tokens.Data[dtclient.ApiToken])
+	return getAPIToken(tokens)
 }
 
-func getAPIToken(tokens corev1.Secret) string {
-	return string(tokens.Data[dtclient.ApiToken])
+func getAPIToken(tokens token.Tokens) string {
+	return getAgentToken(tokens.ApiToken())
+}
+
+// getAgentToken returns the value of the token, unless it is a short-lived OAuth access token that would expire in the secret.
+func getAgentToken(agentToken *token.Token) string {
+	if agentToken.Bearer {
+		return ""
+	}
+
+	return agentToken.Value
 }
 
 // getHostMonitoringNodes creates a mapping between all the nodes and the tenantUID for the host-monitoring dynakube on that node.
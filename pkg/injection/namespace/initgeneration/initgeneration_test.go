@@ -14,6 +14,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/bundle"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/startup"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	"github.com/stretchr/testify/assert"
@@ -83,6 +84,31 @@ func TestGenerateForNamespace(t *testing.T) {
 		checkSecretConfigExists(t, initSecret)
 		checkProxy(t, initSecret, "")
 	})
+	t.Run("Add secret for namespace (dynakube with bundle)", func(t *testing.T) {
+		dk := createDynakube()
+		dk.Status.OneAgent.ConnectionInfoStatus = oneagent.ConnectionInfoStatus{}
+		dk.Spec.OneAgent.CloudNativeFullStack.CodeModulesBundle = &oneagent.CodeModulesBundleSpec{Path: "/bundles/codemodules"}
+
+		bundleConnectionInfo, err := bundle.BuildConnectionInfoConfigMap(dk, bundle.Manifest{
+			ConnectionInfo: bundle.ConnectionInfo{TenantUUID: "bundle-tenant", Endpoints: "https://bundle.endpoint"},
+		})
+		require.NoError(t, err)
+
+		apiTokenSecret := createApiTokenSecret(dk, "api-test", "api-test")
+		testNamespace := createTestInjectedNamespace(dk, "test")
+		clt := fake.NewClient(dk, testNamespace, apiTokenSecret, bundleConnectionInfo, getKubeNamespace())
+		ig := NewInitGenerator(clt, clt, dk.Namespace)
+
+		err = ig.GenerateForNamespace(context.TODO(), *dk, testNamespace.Name)
+		require.NoError(t, err)
+
+		initSecret := retrieveInitSecret(t, clt, testNamespace.Name)
+
+		var secretConfig startup.SecretConfig
+		require.NoError(t, json.Unmarshal(initSecret.Data[consts.AgentInitSecretConfigField], &secretConfig))
+		assert.Equal(t, "bundle-tenant", secretConfig.TenantUUID)
+		assert.Empty(t, dk.Status.OneAgent.ConnectionInfoStatus.TenantUUID)
+	})
 }
 
 func TestGenerateForDynakube(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/arch"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
 	"github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/metadata"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/url"
@@ -112,7 +113,7 @@ func (runner *Runner) Run(ctx context.Context) (resultedError error) {
 			return err
 		}
 
-		if !runner.config.CSIMode {
+		if !runner.config.CSIMode || runner.isCSIFallback() {
 			if err := runner.installOneAgent(ctx); err != nil {
 				return err
 			}
@@ -129,6 +130,16 @@ func (runner *Runner) Run(ctx context.Context) (resultedError error) {
 	return err
 }
 
+// isCSIFallback checks if the CSI driver provided an empty volume, because the CodeModules could not be installed on the node
+func (runner *Runner) isCSIFallback() bool {
+	exists, _ := afero.Exists(runner.fs, filepath.Join(consts.AgentBinDirMount, dtcsi.FallbackMarkerFileName))
+	if exists {
+		log.Info("CSI driver could not provide the CodeModules, falling back to download")
+	}
+
+	return exists
+}
+
 func (runner *Runner) consumeErrorIfNecessary(resultedError *error) {
 	if runner.env.FailurePolicy == silentPhrase && *resultedError != nil {
 		log.Error(*resultedError, "This error has been masked to not fail the container.")
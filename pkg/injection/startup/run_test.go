@@ -11,6 +11,7 @@ import (
 
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	dtcsi "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi"
 	dtclientmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/clients/dynatrace"
 	installermock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/injection/codemodule/installer"
 	"github.com/spf13/afero"
@@ -214,6 +215,23 @@ func TestRun(t *testing.T) {
 		assertIfEnrichmentFilesExists(t, *runner)
 		assertIfReadOnlyCSIFilesExists(t, *runner)
 	})
+	t.Run("csi fallback => install + config generation", func(t *testing.T) {
+		runner.installer.(*installermock.Installer).
+			On("InstallAgent", mock.AnythingOfType("context.backgroundCtx"), consts.AgentBinDirMount).
+			Return(true, nil).Once()
+
+		runner.fs = prepReadOnlyCSIFilesystem(t, afero.NewMemMapFs())
+		runner.config.CSIMode = true
+		_, err := runner.fs.Create(filepath.Join(consts.AgentBinDirMount, dtcsi.FallbackMarkerFileName))
+		require.NoError(t, err)
+		_, err = runner.fs.Create(filepath.Join(consts.AgentBinDirMount, "agent/conf/ruxitagentproc.conf"))
+		require.NoError(t, err)
+
+		err = runner.Run(ctx)
+
+		require.NoError(t, err)
+		assertIfAgentFilesExists(t, *runner)
+	})
 	t.Run("install + config generation", func(t *testing.T) {
 		runner.installer.(*installermock.Installer).
 			On("InstallAgent", mock.AnythingOfType("context.backgroundCtx"), consts.AgentBinDirMount).
@@ -18,6 +18,7 @@ const (
 
 var (
 	baseLogger     Logger
+	baseLogWriter  *prettyLogWriter
 	baseLoggerOnce sync.Once
 )
 
@@ -26,12 +27,21 @@ var (
 func Get() Logger {
 	baseLoggerOnce.Do(func() {
 		logLevel := readLogLevelFromEnv()
-		baseLogger = createLogger(NewPrettyLogWriter(), logLevel)
+		baseLogWriter = &prettyLogWriter{out: os.Stdout}
+		baseLogger = createLogger(baseLogWriter, logLevel)
 	})
 
 	return baseLogger
 }
 
+// SetOutput redirects the logs of the base logger and all loggers derived from it, e.g. to keep stdout free for machine-readable output.
+// Meant to be called during startup, before any logs are written.
+func SetOutput(out io.Writer) {
+	Get()
+
+	baseLogWriter.out = out
+}
+
 func LogBaseLoggerSettings() {
 	logLevel := readLogLevelFromEnv()
 	baseLogger.Info("logging level", "logLevel", logLevel.String())
@@ -2,6 +2,7 @@ package logd
 
 import (
 	"bytes"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,3 +63,16 @@ func TestLogger(t *testing.T) {
 		assert.NotContains(t, logBuffer.String(), "dpanic")
 	})
 }
+
+func TestSetOutput(t *testing.T) {
+	logBuffer := bytes.Buffer{}
+	SetOutput(&logBuffer)
+
+	t.Cleanup(func() {
+		SetOutput(os.Stdout)
+	})
+
+	Get().WithName("derived").Info("redirected message")
+
+	assert.Contains(t, logBuffer.String(), "redirected message")
+}
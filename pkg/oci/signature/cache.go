@@ -0,0 +1,62 @@
+package signature
+
+import (
+	"sync"
+	"time"
+
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// verifiedDigestTTL limits how long a verified digest is trusted, after that the signatures are fetched and verified again.
+const verifiedDigestTTL = time.Hour
+
+// verifiedDigests remembers the digests that passed the verification, so the signatures of unchanged images are not fetched on every reconcile.
+var verifiedDigests = newDigestCache(verifiedDigestTTL)
+
+// digestCache stores verified digests per policy fingerprint, a policy without fingerprint is never cached.
+type digestCache struct {
+	verifiedAt map[string]time.Time
+	ttl        time.Duration
+	mutex      sync.Mutex
+}
+
+func newDigestCache(ttl time.Duration) *digestCache {
+	return &digestCache{
+		verifiedAt: map[string]time.Time{},
+		ttl:        ttl,
+	}
+}
+
+func (cache *digestCache) contains(fingerprint string, digest containerv1.Hash, now time.Time) bool {
+	if fingerprint == "" {
+		return false
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	verifiedAt, ok := cache.verifiedAt[cacheKey(fingerprint, digest)]
+
+	return ok && now.Sub(verifiedAt) < cache.ttl
+}
+
+func (cache *digestCache) add(fingerprint string, digest containerv1.Hash, now time.Time) {
+	if fingerprint == "" {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for key, verifiedAt := range cache.verifiedAt {
+		if now.Sub(verifiedAt) >= cache.ttl {
+			delete(cache.verifiedAt, key)
+		}
+	}
+
+	cache.verifiedAt[cacheKey(fingerprint, digest)] = now
+}
+
+func cacheKey(fingerprint string, digest containerv1.Hash) string {
+	return fingerprint + "/" + digest.String()
+}
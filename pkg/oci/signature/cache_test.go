@@ -0,0 +1,39 @@
+package signature
+
+import (
+	"testing"
+	"time"
+
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestCache(t *testing.T) {
+	digest := containerv1.Hash{Algorithm: "sha256", Hex: "0123456789abcdef"}
+	now := time.Now()
+
+	t.Run("verified digest expires", func(t *testing.T) {
+		cache := newDigestCache(time.Hour)
+		cache.add("policy", digest, now)
+
+		assert.True(t, cache.contains("policy", digest, now.Add(30*time.Minute)))
+		assert.False(t, cache.contains("other-policy", digest, now))
+		assert.False(t, cache.contains("policy", digest, now.Add(time.Hour)))
+	})
+
+	t.Run("policy without fingerprint is not cached", func(t *testing.T) {
+		cache := newDigestCache(time.Hour)
+		cache.add("", digest, now)
+
+		assert.False(t, cache.contains("", digest, now))
+		assert.Empty(t, cache.verifiedAt)
+	})
+
+	t.Run("expired entries are removed", func(t *testing.T) {
+		cache := newDigestCache(time.Hour)
+		cache.add("policy", digest, now)
+		cache.add("other-policy", digest, now.Add(2*time.Hour))
+
+		assert.Len(t, cache.verifiedAt, 1)
+	})
+}
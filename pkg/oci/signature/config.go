@@ -0,0 +1,9 @@
+package signature
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+)
+
+var (
+	log = logd.Get().WithName("image-signature")
+)
@@ -0,0 +1,165 @@
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/imageverification"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/dockerkeychain"
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewVerifierForDynaKube creates a Verifier for the image verification policy of the DynaKube.
+// The registries are accessed with the proxy, trusted CAs and pull secrets of the DynaKube.
+func NewVerifierForDynaKube(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube) (*Verifier, error) {
+	policy, err := NewPolicyForDynaKube(ctx, apiReader, dk)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := registry.PrepareTransportForDynaKube(ctx, apiReader, http.DefaultTransport.(*http.Transport).Clone(), dk)
+	if err != nil {
+		return nil, err
+	}
+
+	keychain, err := dockerkeychain.NewDockerKeychains(ctx, apiReader, dk.Namespace, dk.PullSecretNames())
+	if err != nil {
+		return nil, err
+	}
+
+	return NewVerifier(policy, remote.WithTransport(transport), remote.WithAuthFromKeychain(keychain)), nil
+}
+
+// NewPolicyForDynaKube resolves the keys and the trusted root referenced by the image verification policy of the DynaKube.
+func NewPolicyForDynaKube(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube) (Policy, error) {
+	imageVerification := dk.ImageVerification()
+	if !imageVerification.IsEnabled() {
+		return Policy{}, nil
+	}
+
+	policy := Policy{
+		TrustedRegistries: imageVerification.TrustedRegistries,
+	}
+
+	// the fingerprint covers the policy of the DynaKube and the content of the referenced secret and configmap
+	fingerprint := sha256.New()
+
+	spec, err := json.Marshal(imageVerification.Spec)
+	if err != nil {
+		return Policy{}, errors.WithStack(err)
+	}
+
+	fingerprint.Write(spec)
+
+	if secretName := imageVerification.GetPublicKeysSecret(); secretName != "" {
+		publicKeys, err := getPublicKeys(ctx, apiReader, client.ObjectKey{Name: secretName, Namespace: dk.Namespace}, fingerprint)
+		if err != nil {
+			return Policy{}, err
+		}
+
+		policy.PublicKeys = publicKeys
+	}
+
+	for _, keyless := range imageVerification.Keyless {
+		identity, err := NewIdentity(keyless.Issuer, keyless.Subject, keyless.SubjectRegExp)
+		if err != nil {
+			return Policy{}, err
+		}
+
+		policy.Identities = append(policy.Identities, identity)
+	}
+
+	if configMapName := imageVerification.GetTrustedRoot(); configMapName != "" {
+		err := setTrustedRoot(ctx, apiReader, client.ObjectKey{Name: configMapName, Namespace: dk.Namespace}, &policy, fingerprint)
+		if err != nil {
+			return Policy{}, err
+		}
+	}
+
+	policy.fingerprint = hex.EncodeToString(fingerprint.Sum(nil))
+
+	return policy, nil
+}
+
+func getPublicKeys(ctx context.Context, apiReader client.Reader, key client.ObjectKey, fingerprint hash.Hash) ([]crypto.PublicKey, error) {
+	var secret corev1.Secret
+
+	err := apiReader.Get(ctx, key, &secret)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get public keys from %s secret", key.Name))
+	}
+
+	var publicKeys []crypto.PublicKey
+
+	for _, entry := range slices.Sorted(maps.Keys(secret.Data)) {
+		data := secret.Data[entry]
+		fingerprint.Write(data)
+
+		entryKeys, err := ParsePublicKeys(data)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid public key %s in %s secret", entry, key.Name)
+		}
+
+		publicKeys = append(publicKeys, entryKeys...)
+	}
+
+	if len(publicKeys) == 0 {
+		return nil, errors.Errorf("no public keys found in %s secret", key.Name)
+	}
+
+	return publicKeys, nil
+}
+
+func setTrustedRoot(ctx context.Context, apiReader client.Reader, key client.ObjectKey, policy *Policy, fingerprint hash.Hash) error {
+	var configMap corev1.ConfigMap
+
+	err := apiReader.Get(ctx, key, &configMap)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to get trusted root from %s configmap", key.Name))
+	}
+
+	fingerprint.Write([]byte(configMap.Data[imageverification.FulcioRootsKey]))
+	fingerprint.Write([]byte(configMap.Data[imageverification.RekorPublicKeyKey]))
+
+	policy.FulcioRoots = x509.NewCertPool()
+	if !policy.FulcioRoots.AppendCertsFromPEM([]byte(configMap.Data[imageverification.FulcioRootsKey])) {
+		return errors.Errorf("no Fulcio certificates found under %s in %s configmap", imageverification.FulcioRootsKey, key.Name)
+	}
+
+	rekorPublicKeys, err := ParsePublicKeys([]byte(configMap.Data[imageverification.RekorPublicKeyKey]))
+	if err != nil {
+		return errors.WithMessagef(err, "invalid Rekor public key under %s in %s configmap", imageverification.RekorPublicKeyKey, key.Name)
+	}
+
+	policy.RekorPublicKey = rekorPublicKeys[0]
+
+	return nil
+}
+
+// VerifyPodSpecForDynaKube verifies the images of all containers of the pod spec and pins them to the verified digests, if the DynaKube has an image verification policy.
+func VerifyPodSpecForDynaKube(ctx context.Context, apiReader client.Reader, dk *dynakube.DynaKube, podSpec *corev1.PodSpec) error {
+	if !dk.ImageVerification().IsEnabled() {
+		return nil
+	}
+
+	verifier, err := NewVerifierForDynaKube(ctx, apiReader, dk)
+	if err != nil {
+		return err
+	}
+
+	return verifier.VerifyPodSpec(ctx, podSpec)
+}
@@ -0,0 +1,222 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// issuerV2OID holds the OIDC issuer of a Fulcio certificate as DER encoded string.
+	issuerV2OID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+
+	// issuerV1OID holds the OIDC issuer of a Fulcio certificate as raw string, it is deprecated but still set.
+	issuerV1OID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+)
+
+type rekorBundle struct {
+	SignedEntryTimestamp []byte       `json:"SignedEntryTimestamp"`
+	Payload              rekorPayload `json:"Payload"`
+}
+
+// rekorPayload is signed by Rekor in its canonical JSON form, so the fields have to be sorted by their names.
+type rekorPayload struct {
+	Body           string `json:"body"`
+	IntegratedTime int64  `json:"integratedTime"`
+	LogID          string `json:"logID"`
+	LogIndex       int64  `json:"logIndex"`
+}
+
+type hashedRekord struct {
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   string `json:"content"`
+			PublicKey struct {
+				Content string `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verifyKeyless verifies a signature created with a short-lived Fulcio certificate.
+// The certificate is checked at the time the signature was added to the Rekor transparency log.
+func (policy Policy) verifyKeyless(sig signature) error {
+	if policy.FulcioRoots == nil || policy.RekorPublicKey == nil {
+		return errors.New("keyless signatures require a trusted root")
+	}
+
+	certificate, err := parseCertificate(sig.certificate)
+	if err != nil {
+		return err
+	}
+
+	integratedTime, err := policy.verifyBundle(sig, certificate)
+	if err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM(sig.chain)
+
+	_, err = certificate.Verify(x509.VerifyOptions{
+		Roots:         policy.FulcioRoots,
+		Intermediates: intermediates,
+		CurrentTime:   integratedTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return errors.WithMessage(err, "signing certificate is not trusted")
+	}
+
+	issuer, subjects := certificateIdentity(certificate)
+
+	if !policy.matchesIdentity(issuer, subjects) {
+		return errors.Errorf("signing identity %v of issuer %q is not trusted", subjects, issuer)
+	}
+
+	return verifyWithKey(certificate.PublicKey, sig.payload, sig.signature)
+}
+
+func (policy Policy) matchesIdentity(issuer string, subjects []string) bool {
+	for _, identity := range policy.Identities {
+		if identity.matches(issuer, subjects) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyBundle verifies the signed entry timestamp of the Rekor bundle and returns the time the entry was integrated into the log.
+func (policy Policy) verifyBundle(sig signature, certificate *x509.Certificate) (time.Time, error) {
+	if len(sig.bundle) == 0 {
+		return time.Time{}, errors.New("keyless signature has no transparency log bundle")
+	}
+
+	var bundle rekorBundle
+	if err := json.Unmarshal(sig.bundle, &bundle); err != nil {
+		return time.Time{}, errors.WithMessage(err, "invalid transparency log bundle")
+	}
+
+	canonicalPayload, err := json.Marshal(bundle.Payload)
+	if err != nil {
+		return time.Time{}, errors.WithStack(err)
+	}
+
+	rekorPublicKey, ok := policy.RekorPublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return time.Time{}, errors.Errorf("unsupported Rekor public key type %T", policy.RekorPublicKey)
+	}
+
+	payloadDigest := sha256.Sum256(canonicalPayload)
+	if !ecdsa.VerifyASN1(rekorPublicKey, payloadDigest[:], bundle.SignedEntryTimestamp) {
+		return time.Time{}, errors.New("invalid signed entry timestamp in transparency log bundle")
+	}
+
+	if err := verifyBundleBody(bundle.Payload.Body, sig, certificate); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(bundle.Payload.IntegratedTime, 0), nil
+}
+
+// verifyBundleBody checks that the transparency log entry belongs to the signature and was logged for the key of the signing certificate.
+// Otherwise, the entry of any other signature over the same payload would vouch for the time the certificate was valid.
+func verifyBundleBody(body string, sig signature, certificate *x509.Certificate) error {
+	decodedBody, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return errors.WithMessage(err, "invalid transparency log entry")
+	}
+
+	var entry hashedRekord
+	if err := json.Unmarshal(decodedBody, &entry); err != nil {
+		return errors.WithMessage(err, "invalid transparency log entry")
+	}
+
+	payloadDigest := sha256.Sum256(sig.payload)
+
+	if entry.Spec.Data.Hash.Value != hex.EncodeToString(payloadDigest[:]) || entry.Spec.Signature.Content != base64.StdEncoding.EncodeToString(sig.signature) {
+		return errors.New("transparency log entry does not match the signature")
+	}
+
+	return verifyEntryPublicKey(entry.Spec.Signature.PublicKey.Content, certificate)
+}
+
+// verifyEntryPublicKey checks that the public key of the transparency log entry, either a PEM encoded certificate or public key, is the one of the signing certificate.
+func verifyEntryPublicKey(content string, certificate *x509.Certificate) error {
+	decodedContent, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return errors.WithMessage(err, "invalid public key in transparency log entry")
+	}
+
+	block, _ := pem.Decode(decodedContent)
+	if block == nil {
+		return errors.New("no PEM encoded public key found in transparency log entry")
+	}
+
+	var matches bool
+
+	switch block.Type {
+	case "CERTIFICATE":
+		matches = bytes.Equal(block.Bytes, certificate.Raw)
+	case "PUBLIC KEY":
+		matches = bytes.Equal(block.Bytes, certificate.RawSubjectPublicKeyInfo)
+	}
+
+	if !matches {
+		return errors.New("transparency log entry does not match the signing certificate")
+	}
+
+	return nil
+}
+
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded signing certificate found")
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse signing certificate")
+	}
+
+	return certificate, nil
+}
+
+func certificateIdentity(certificate *x509.Certificate) (string, []string) {
+	subjects := append([]string{}, certificate.EmailAddresses...)
+	for _, uri := range certificate.URIs {
+		subjects = append(subjects, uri.String())
+	}
+
+	var issuer string
+
+	for _, extension := range certificate.Extensions {
+		switch {
+		case extension.Id.Equal(issuerV2OID):
+			if _, err := asn1.Unmarshal(extension.Value, &issuer); err == nil {
+				return issuer, subjects
+			}
+		case extension.Id.Equal(issuerV1OID):
+			issuer = string(extension.Value)
+		}
+	}
+
+	return issuer, subjects
+}
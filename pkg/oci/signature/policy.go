@@ -0,0 +1,138 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+)
+
+const dockerHubRegistry = "index.docker.io/"
+
+// Policy defines which images are accepted by the Verifier.
+type Policy struct {
+	// FulcioRoots are the trusted CA certificates of keyless signatures.
+	FulcioRoots *x509.CertPool
+
+	// RekorPublicKey is used to verify the transparency log entries of keyless signatures.
+	RekorPublicKey crypto.PublicKey
+
+	// PublicKeys accepted for key based signatures.
+	PublicKeys []crypto.PublicKey
+
+	// Identities accepted for keyless signatures.
+	Identities []Identity
+
+	// TrustedRegistries limits the registries (and repository prefixes) images are pulled from, empty allows any.
+	TrustedRegistries []string
+
+	// fingerprint identifies the sources of the policy, verified digests are only cached if it is set.
+	fingerprint string
+}
+
+// Identity of a keyless signature, the subject is either matched exactly or by a regular expression.
+type Identity struct {
+	SubjectRegExp *regexp.Regexp
+	Issuer        string
+	Subject       string
+}
+
+// NewIdentity creates an Identity, the regular expression has to match the whole subject.
+func NewIdentity(issuer, subject, subjectRegExp string) (Identity, error) {
+	identity := Identity{
+		Issuer:  issuer,
+		Subject: subject,
+	}
+
+	if subjectRegExp != "" {
+		compiled, err := regexp.Compile("^(?:" + subjectRegExp + ")$")
+		if err != nil {
+			return Identity{}, errors.WithMessagef(err, "invalid subject regular expression %q", subjectRegExp)
+		}
+
+		identity.SubjectRegExp = compiled
+	}
+
+	return identity, nil
+}
+
+func (identity Identity) matches(issuer string, subjects []string) bool {
+	if identity.Issuer != issuer {
+		return false
+	}
+
+	for _, subject := range subjects {
+		switch {
+		case identity.Subject != "" && identity.Subject == subject:
+			return true
+		case identity.SubjectRegExp != nil && identity.SubjectRegExp.MatchString(subject):
+			return true
+		case identity.Subject == "" && identity.SubjectRegExp == nil:
+			return true
+		}
+	}
+
+	return false
+}
+
+// RequiresSignature returns true if images have to be signed, not only be pulled from a trusted registry.
+func (policy Policy) RequiresSignature() bool {
+	return len(policy.PublicKeys) > 0 || len(policy.Identities) > 0
+}
+
+// IsTrustedRegistry returns true if the repository of the reference is covered by the trusted registries.
+func (policy Policy) IsTrustedRegistry(ref name.Reference) bool {
+	if len(policy.TrustedRegistries) == 0 {
+		return true
+	}
+
+	repository := normalizeRepository(ref.Context().Name())
+
+	for _, trusted := range policy.TrustedRegistries {
+		trusted = normalizeRepository(strings.TrimSuffix(trusted, "/"))
+		if repository == trusted || strings.HasPrefix(repository, trusted+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeRepository(repository string) string {
+	if strings.HasPrefix(repository, dockerHubRegistry) {
+		return "docker.io/" + strings.TrimPrefix(repository, dockerHubRegistry)
+	}
+
+	return repository
+}
+
+// ParsePublicKeys parses all PEM encoded public keys of the given data.
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var publicKeys []crypto.PublicKey
+
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to parse public key")
+		}
+
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	if len(publicKeys) == 0 {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+
+	return publicKeys, nil
+}
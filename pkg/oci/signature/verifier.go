@@ -0,0 +1,282 @@
+// Package signature verifies cosign signatures of container images, either signed with a key or keyless by a Fulcio certificate.
+// The signatures are looked up with the tag based discovery of cosign (<repository>:sha256-<digest>.sig).
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/oci/registry"
+	"github.com/google/go-containerregistry/pkg/name"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	signatureAnnotation   = "dev.cosignproject.cosign/signature"
+	certificateAnnotation = "dev.sigstore.cosign/certificate"
+	chainAnnotation       = "dev.sigstore.cosign/chain"
+	bundleAnnotation      = "dev.sigstore.cosign/bundle"
+
+	simpleSigningType = "cosign container image signature"
+
+	// maxPayloadSize limits the size of a signature payload, they are usually a few hundred bytes.
+	maxPayloadSize = 1 << 20
+)
+
+// Verifier checks images against a Policy, a nil Verifier accepts all images.
+type Verifier struct {
+	policy  Policy
+	options []remote.Option
+}
+
+// NewVerifier creates a Verifier, the options are used to access the registries.
+func NewVerifier(policy Policy, options ...remote.Option) *Verifier {
+	return &Verifier{
+		policy:  policy,
+		options: options,
+	}
+}
+
+type signature struct {
+	payload     []byte
+	signature   []byte
+	certificate []byte
+	chain       []byte
+	bundle      []byte
+}
+
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// Verify returns the image pinned to the verified digest (<image>@<digest>), pulling it guarantees that the verified content is used.
+// An error is returned, if the image is not pulled from a trusted registry or has no valid signature according to the policy.
+// If the policy does not require a signature, the image is returned as is.
+func (verifier *Verifier) Verify(ctx context.Context, imageName string) (string, error) {
+	if verifier == nil {
+		return imageName, nil
+	}
+
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return "", errors.WithMessagef(err, "parsing reference %q", imageName)
+	}
+
+	if !verifier.policy.IsTrustedRegistry(ref) {
+		return "", errors.Errorf("image %q is not pulled from a trusted registry", imageName)
+	}
+
+	if !verifier.policy.RequiresSignature() {
+		return imageName, nil
+	}
+
+	options := append([]remote.Option{remote.WithContext(ctx)}, verifier.options...)
+
+	descriptor, err := remote.Head(ref, options...)
+	if err != nil {
+		return "", errors.WithMessagef(err, "getting digest of image %q", imageName)
+	}
+
+	pinnedImage := pinImage(ref, descriptor.Digest)
+
+	if verifiedDigests.contains(verifier.policy.fingerprint, descriptor.Digest, time.Now()) {
+		return pinnedImage, nil
+	}
+
+	signatures, err := fetchSignatures(ref.Context().Tag(signatureTag(descriptor.Digest)), options)
+	if err != nil {
+		return "", errors.WithMessagef(err, "no signature found for image %q (%s)", imageName, descriptor.Digest)
+	}
+
+	var reasons []string
+
+	for _, sig := range signatures {
+		err := verifier.policy.verify(sig, descriptor.Digest)
+		if err == nil {
+			log.Info("image signature verified", "image", imageName, "digest", descriptor.Digest.String())
+			verifiedDigests.add(verifier.policy.fingerprint, descriptor.Digest, time.Now())
+
+			return pinnedImage, nil
+		}
+
+		reasons = append(reasons, err.Error())
+	}
+
+	return "", errors.Errorf("no valid signature found for image %q (%s): %s", imageName, descriptor.Digest, strings.Join(reasons, "; "))
+}
+
+// pinImage adds the digest to the image, a tag is kept for readability, but is ignored when pulling.
+func pinImage(ref name.Reference, digest containerv1.Hash) string {
+	if digestRef, ok := ref.(name.Digest); ok {
+		return digestRef.String()
+	}
+
+	return ref.String() + registry.DigestDelimiter + digest.String()
+}
+
+func signatureTag(digest containerv1.Hash) string {
+	return fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex)
+}
+
+func fetchSignatures(tag name.Tag, options []remote.Option) ([]signature, error) {
+	image, err := remote.Image(tag, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := image.Manifest()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	layers, err := image.Layers()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	signatures := make([]signature, 0, len(layers))
+
+	for i, layer := range layers {
+		annotations := manifest.Layers[i].Annotations
+
+		encodedSignature, ok := annotations[signatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		decodedSignature, err := base64.StdEncoding.DecodeString(encodedSignature)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode signature")
+		}
+
+		payload, err := readPayload(layer)
+		if err != nil {
+			return nil, err
+		}
+
+		signatures = append(signatures, signature{
+			payload:     payload,
+			signature:   decodedSignature,
+			certificate: []byte(annotations[certificateAnnotation]),
+			chain:       []byte(annotations[chainAnnotation]),
+			bundle:      []byte(annotations[bundleAnnotation]),
+		})
+	}
+
+	if len(signatures) == 0 {
+		return nil, errors.New("signature manifest contains no signatures")
+	}
+
+	return signatures, nil
+}
+
+func readPayload(layer containerv1.Layer) ([]byte, error) {
+	reader, err := layer.Compressed()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	payload, err := io.ReadAll(io.LimitReader(reader, maxPayloadSize))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read signature payload")
+	}
+
+	return payload, nil
+}
+
+func (policy Policy) verify(sig signature, digest containerv1.Hash) error {
+	var payload simpleSigningPayload
+	if err := json.Unmarshal(sig.payload, &payload); err != nil {
+		return errors.WithMessage(err, "invalid signature payload")
+	}
+
+	if payload.Critical.Type != simpleSigningType {
+		return errors.Errorf("unexpected signature type %q", payload.Critical.Type)
+	}
+
+	if payload.Critical.Image.DockerManifestDigest != digest.String() {
+		return errors.Errorf("signature is for digest %s", payload.Critical.Image.DockerManifestDigest)
+	}
+
+	for _, publicKey := range policy.PublicKeys {
+		if verifyWithKey(publicKey, sig.payload, sig.signature) == nil {
+			return nil
+		}
+	}
+
+	if len(sig.certificate) > 0 && len(policy.Identities) > 0 {
+		return policy.verifyKeyless(sig)
+	}
+
+	return errors.New("signature does not match any of the trusted keys")
+}
+
+func verifyWithKey(publicKey crypto.PublicKey, payload, sig []byte) error {
+	digest := sha256.Sum256(payload)
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], sig) {
+			return errors.New("invalid ECDSA signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+			return errors.WithMessage(err, "invalid RSA signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, sig) {
+			return errors.New("invalid Ed25519 signature")
+		}
+	default:
+		return errors.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	return nil
+}
+
+// VerifyPodSpec verifies the images of all (init) containers of the pod spec and pins them to the verified digests.
+// Every image is only verified once.
+func (verifier *Verifier) VerifyPodSpec(ctx context.Context, podSpec *corev1.PodSpec) error {
+	pinnedImages := map[string]string{}
+
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			image := containers[i].Image
+
+			pinnedImage, ok := pinnedImages[image]
+			if !ok {
+				var err error
+
+				pinnedImage, err = verifier.Verify(ctx, image)
+				if err != nil {
+					return err
+				}
+
+				pinnedImages[image] = pinnedImage
+			}
+
+			containers[i].Image = pinnedImage
+		}
+	}
+
+	return nil
+}
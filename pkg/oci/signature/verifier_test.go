@@ -0,0 +1,387 @@
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	stdlog "log"
+	"math/big"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	containerv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	testIssuer  = "https://token.actions.githubusercontent.com"
+	testSubject = "https://github.com/Dynatrace/dynatrace-operator/.github/workflows/release.yaml@refs/heads/main"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	host := startRegistry(t)
+
+	signingKey := generateKey(t)
+	otherKey := generateKey(t)
+
+	t.Run("signed with trusted key", func(t *testing.T) {
+		imageName, digest := pushImage(t, host, "oneagent")
+		pushSignature(t, imageName, digest, signWithKey(t, signingKey, payloadFor(digest)))
+
+		verifier := NewVerifier(Policy{PublicKeys: []crypto.PublicKey{&otherKey.PublicKey, &signingKey.PublicKey}})
+
+		verifiedImage, err := verifier.Verify(ctx, imageName)
+		require.NoError(t, err)
+		assert.Equal(t, imageName+"@"+digest.String(), verifiedImage)
+	})
+
+	t.Run("verified digest is cached per policy", func(t *testing.T) {
+		imageName, digest := pushImage(t, host, "cached")
+		pushSignature(t, imageName, digest, signWithKey(t, signingKey, payloadFor(digest)))
+
+		_, err := NewVerifier(Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}, fingerprint: "trusted"}).Verify(ctx, imageName)
+		require.NoError(t, err)
+
+		// same policy sources, the signature is not verified again
+		_, err = NewVerifier(Policy{PublicKeys: []crypto.PublicKey{&otherKey.PublicKey}, fingerprint: "trusted"}).Verify(ctx, imageName)
+		require.NoError(t, err)
+
+		_, err = NewVerifier(Policy{PublicKeys: []crypto.PublicKey{&otherKey.PublicKey}, fingerprint: "changed"}).Verify(ctx, imageName)
+		require.Error(t, err)
+	})
+
+	t.Run("signed with untrusted key", func(t *testing.T) {
+		imageName, digest := pushImage(t, host, "activegate")
+		pushSignature(t, imageName, digest, signWithKey(t, otherKey, payloadFor(digest)))
+
+		verifier := NewVerifier(Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}})
+
+		_, err := verifier.Verify(ctx, imageName)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no valid signature found")
+	})
+
+	t.Run("signature of a different image", func(t *testing.T) {
+		imageName, digest := pushImage(t, host, "codemodules")
+		_, otherDigest := pushImage(t, host, "other")
+		pushSignature(t, imageName, digest, signWithKey(t, signingKey, payloadFor(otherDigest)))
+
+		verifier := NewVerifier(Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}})
+
+		_, err := verifier.Verify(ctx, imageName)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "signature is for digest")
+	})
+
+	t.Run("unsigned image", func(t *testing.T) {
+		imageName, _ := pushImage(t, host, "unsigned")
+
+		verifier := NewVerifier(Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}})
+
+		_, err := verifier.Verify(ctx, imageName)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no signature found")
+	})
+
+	t.Run("untrusted registry", func(t *testing.T) {
+		verifier := NewVerifier(Policy{TrustedRegistries: []string{"public.ecr.aws/dynatrace"}})
+
+		verifiedImage, err := verifier.Verify(ctx, "public.ecr.aws/dynatrace/dynatrace-oneagent:1.2.3")
+		require.NoError(t, err)
+		assert.Equal(t, "public.ecr.aws/dynatrace/dynatrace-oneagent:1.2.3", verifiedImage)
+
+		_, err = verifier.Verify(ctx, "public.ecr.aws/other/dynatrace-oneagent:1.2.3")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not pulled from a trusted registry")
+	})
+
+	t.Run("nil verifier accepts everything", func(t *testing.T) {
+		var verifier *Verifier
+
+		verifiedImage, err := verifier.Verify(ctx, "any/image:latest")
+		require.NoError(t, err)
+		assert.Equal(t, "any/image:latest", verifiedImage)
+	})
+}
+
+func TestVerifyKeyless(t *testing.T) {
+	ctx := context.Background()
+	host := startRegistry(t)
+
+	root, rootKey := generateCA(t)
+	rekorKey := generateKey(t)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	policyFor := func(t *testing.T, subjectRegExp string) Policy {
+		identity, err := NewIdentity(testIssuer, "", subjectRegExp)
+		require.NoError(t, err)
+
+		return Policy{
+			FulcioRoots:    roots,
+			RekorPublicKey: &rekorKey.PublicKey,
+			Identities:     []Identity{identity},
+		}
+	}
+
+	imageName, digest := pushImage(t, host, "keyless")
+	pushSignature(t, imageName, digest, signKeyless(t, root, rootKey, rekorKey, payloadFor(digest), false))
+
+	foreignEntryImageName, foreignEntryDigest := pushImage(t, host, "keyless-foreign-entry")
+	pushSignature(t, foreignEntryImageName, foreignEntryDigest, signKeyless(t, root, rootKey, rekorKey, payloadFor(foreignEntryDigest), true))
+
+	t.Run("trusted identity", func(t *testing.T) {
+		_, err := NewVerifier(policyFor(t, `https://github\.com/Dynatrace/.*`)).Verify(ctx, imageName)
+		require.NoError(t, err)
+	})
+
+	t.Run("untrusted identity", func(t *testing.T) {
+		_, err := NewVerifier(policyFor(t, `https://github\.com/other/.*`)).Verify(ctx, imageName)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not trusted")
+	})
+
+	t.Run("untrusted Rekor key", func(t *testing.T) {
+		policy := policyFor(t, `.*`)
+		policy.RekorPublicKey = &generateKey(t).PublicKey
+
+		_, err := NewVerifier(policy).Verify(ctx, imageName)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid signed entry timestamp")
+	})
+
+	t.Run("transparency log entry of another certificate", func(t *testing.T) {
+		_, err := NewVerifier(policyFor(t, `.*`)).Verify(ctx, foreignEntryImageName)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match the signing certificate")
+	})
+
+	t.Run("no trusted root", func(t *testing.T) {
+		policy := policyFor(t, `.*`)
+		policy.FulcioRoots = nil
+
+		_, err := NewVerifier(policy).Verify(ctx, imageName)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "require a trusted root")
+	})
+}
+
+func TestVerifyPodSpec(t *testing.T) {
+	ctx := context.Background()
+	host := startRegistry(t)
+	signingKey := generateKey(t)
+
+	imageName, digest := pushImage(t, host, "eec")
+	pushSignature(t, imageName, digest, signWithKey(t, signingKey, payloadFor(digest)))
+
+	podSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init", Image: imageName}},
+		Containers:     []corev1.Container{{Name: "main", Image: imageName}},
+	}
+
+	require.NoError(t, NewVerifier(Policy{PublicKeys: []crypto.PublicKey{&signingKey.PublicKey}}).VerifyPodSpec(ctx, &podSpec))
+
+	assert.Equal(t, imageName+"@"+digest.String(), podSpec.InitContainers[0].Image)
+	assert.Equal(t, imageName+"@"+digest.String(), podSpec.Containers[0].Image)
+}
+
+func TestIsTrustedRegistry(t *testing.T) {
+	policy := Policy{TrustedRegistries: []string{"docker.io/dynatrace/", "registry.example.com"}}
+
+	for imageName, expected := range map[string]bool{
+		"dynatrace/dynatrace-operator:v1.0.0":          true,
+		"docker.io/dynatrace/dynatrace-operator:1.0.0": true,
+		"registry.example.com/any/image:1.0.0":         true,
+		"docker.io/dynatrace-fake/operator:1.0.0":      false,
+		"registry.example.com.evil.io/image:1.0.0":     false,
+	} {
+		ref, err := name.ParseReference(imageName)
+		require.NoError(t, err)
+		assert.Equal(t, expected, policy.IsTrustedRegistry(ref), imageName)
+	}
+}
+
+func startRegistry(t *testing.T) string {
+	server := httptest.NewServer(registry.New(registry.Logger(stdlog.New(io.Discard, "", 0))))
+	t.Cleanup(server.Close)
+
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func pushImage(t *testing.T, host, repository string) (string, containerv1.Hash) {
+	image, err := random.Image(64, 1)
+	require.NoError(t, err)
+
+	ref, err := name.ParseReference(fmt.Sprintf("%s/%s:1.0.0", host, repository))
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, image))
+
+	digest, err := image.Digest()
+	require.NoError(t, err)
+
+	return ref.String(), digest
+}
+
+func payloadFor(digest containerv1.Hash) []byte {
+	return fmt.Appendf(nil, `{"critical":{"identity":{"docker-reference":"test"},"image":{"docker-manifest-digest":%q},"type":%q},"optional":null}`, digest.String(), simpleSigningType)
+}
+
+func pushSignature(t *testing.T, imageName string, digest containerv1.Hash, layer mutate.Addendum) {
+	ref, err := name.ParseReference(imageName)
+	require.NoError(t, err)
+
+	signatureImage, err := mutate.Append(empty.Image, layer)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref.Context().Tag(signatureTag(digest)), signatureImage))
+}
+
+func signWithKey(t *testing.T, key *ecdsa.PrivateKey, payload []byte) mutate.Addendum {
+	return mutate.Addendum{
+		Layer: static.NewLayer(payload, types.MediaType("application/vnd.dev.cosign.simplesigning.v1+json")),
+		Annotations: map[string]string{
+			signatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+		},
+	}
+}
+
+// signKeyless signs the payload with a certificate of the root, if foreignEntry is set, the transparency log entry is logged for the key of another certificate.
+func signKeyless(t *testing.T, root *x509.Certificate, rootKey, rekorKey *ecdsa.PrivateKey, payload []byte, foreignEntry bool) mutate.Addendum {
+	signingKey := generateKey(t)
+	certificate := createSigningCertificate(t, root, rootKey, signingKey)
+
+	entryCertificate := certificate
+	if foreignEntry {
+		entryCertificate = createSigningCertificate(t, root, rootKey, generateKey(t))
+	}
+
+	entryPublicKey := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: entryCertificate.Raw})
+
+	signature := sign(t, signingKey, payload)
+	payloadDigest := sha256.Sum256(payload)
+
+	body, err := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"data": map[string]any{"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(payloadDigest[:])}},
+			"signature": map[string]any{
+				"content":   base64.StdEncoding.EncodeToString(signature),
+				"publicKey": map[string]string{"content": base64.StdEncoding.EncodeToString(entryPublicKey)},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	bundle := rekorBundle{
+		Payload: rekorPayload{
+			Body:           base64.StdEncoding.EncodeToString(body),
+			IntegratedTime: time.Now().Unix(),
+			LogID:          "test",
+			LogIndex:       1,
+		},
+	}
+	canonicalPayload, err := json.Marshal(bundle.Payload)
+	require.NoError(t, err)
+
+	bundle.SignedEntryTimestamp = sign(t, rekorKey, canonicalPayload)
+	encodedBundle, err := json.Marshal(bundle)
+	require.NoError(t, err)
+
+	return mutate.Addendum{
+		Layer: static.NewLayer(payload, types.MediaType("application/vnd.dev.cosign.simplesigning.v1+json")),
+		Annotations: map[string]string{
+			signatureAnnotation:   base64.StdEncoding.EncodeToString(signature),
+			certificateAnnotation: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})),
+			bundleAnnotation:      string(encodedBundle),
+		},
+	}
+}
+
+func createSigningCertificate(t *testing.T, root *x509.Certificate, rootKey, signingKey *ecdsa.PrivateKey) *x509.Certificate {
+	issuerExtension, err := asn1.Marshal(testIssuer)
+	require.NoError(t, err)
+
+	return createCertificate(t, &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       time.Now().Add(-time.Minute),
+		NotAfter:        time.Now().Add(10 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{mustParseURL(t, testSubject)},
+		ExtraExtensions: []pkix.Extension{{Id: issuerV2OID, Value: issuerExtension}},
+	}, root, &signingKey.PublicKey, rootKey)
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, payload []byte) []byte {
+	digest := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	return signature
+}
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return key
+}
+
+func generateCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key := generateKey(t)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fulcio"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	return createCertificate(t, template, template, &key.PublicKey, key), key
+}
+
+func createCertificate(t *testing.T, template, parent *x509.Certificate, publicKey *ecdsa.PublicKey, parentKey *ecdsa.PrivateKey) *x509.Certificate {
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+
+	return certificate
+}
+
+func mustParseURL(t *testing.T, rawURL string) *url.URL {
+	parsed, err := url.Parse(rawURL)
+	require.NoError(t, err)
+
+	return parsed
+}
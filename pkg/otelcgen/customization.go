@@ -0,0 +1,304 @@
+package otelcgen
+
+import (
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// CustomComponent is a user provided processor or exporter, that is added to the generated pipelines.
+type CustomComponent struct {
+	// Config is passed to the collector as is.
+	Config map[string]any
+
+	// ID in the form type[/name], e.g. filter/drop-health-checks.
+	ID string
+
+	// Pipelines the component is added to, all pipelines supporting the component type if empty.
+	Pipelines []string
+}
+
+// BatchOverrides replace the defaults of all batch processors, unset fields keep the default.
+type BatchOverrides struct {
+	SendBatchSize    *uint32
+	SendBatchMaxSize *uint32
+	Timeout          string
+}
+
+// MemoryLimiterOverrides replace the defaults of the memory limiter processor, unset fields keep the default.
+type MemoryLimiterOverrides struct {
+	LimitPercentage      *uint32
+	SpikeLimitPercentage *uint32
+	CheckInterval        string
+}
+
+type customComponent struct {
+	config    map[string]any
+	id        component.ID
+	pipelines []pipeline.ID
+}
+
+var (
+	allPipelines = []pipeline.ID{traces, metrics, logs}
+
+	// allowedCustomProcessors are the processor types shipped with the Dynatrace collector distribution
+	// that can be added to the pipelines, mapped to the pipelines they support.
+	allowedCustomProcessors = map[string][]pipeline.ID{
+		"filter":                allPipelines,
+		"attributes":            allPipelines,
+		"resource":              allPipelines,
+		"tail_sampling":         {traces},
+		"probabilistic_sampler": {traces, logs},
+	}
+
+	// allowedCustomExporters are the exporter types shipped with the Dynatrace collector distribution,
+	// mapped to the pipelines they support.
+	allowedCustomExporters = map[string][]pipeline.ID{
+		"otlp":     allPipelines,
+		"otlphttp": allPipelines,
+		"debug":    allPipelines,
+	}
+)
+
+func WithCustomProcessors(processors ...CustomComponent) Option {
+	return func(c *Config) error {
+		c.customProcessors = append(c.customProcessors, processors...)
+
+		return nil
+	}
+}
+
+func WithCustomExporters(exporters ...CustomComponent) Option {
+	return func(c *Config) error {
+		c.customExporters = append(c.customExporters, exporters...)
+
+		return nil
+	}
+}
+
+func WithBatchOverrides(overrides BatchOverrides) Option {
+	return func(c *Config) error {
+		c.batchOverrides = &overrides
+
+		return nil
+	}
+}
+
+func WithMemoryLimiterOverrides(overrides MemoryLimiterOverrides) Option {
+	return func(c *Config) error {
+		c.memoryLimiterOverrides = &overrides
+
+		return nil
+	}
+}
+
+// applyCustomizations validates the user provided components and overrides and merges them into the generated config.
+// It runs after all other options, so the order of the options doesn't matter.
+func (c *Config) applyCustomizations() error {
+	builtinIDs := append(buildProcessors(), cumulativeToDelta, batchTraces, batchMetrics, batchLogs)
+	builtinIDs = append(builtinIDs, buildExporters()...)
+
+	processors, err := resolveCustomComponents("processor", c.customProcessors, allowedCustomProcessors, builtinIDs)
+	if err != nil {
+		return err
+	}
+
+	exporters, err := resolveCustomComponents("exporter", c.customExporters, allowedCustomExporters, builtinIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, processor := range processors {
+		if slices.ContainsFunc(exporters, func(exporter customComponent) bool { return exporter.id == processor.id }) {
+			return errors.Errorf("custom component '%s' is defined as processor and exporter", processor.id)
+		}
+	}
+
+	if err := c.validateOverrides(); err != nil {
+		return err
+	}
+
+	if c.Processors != nil {
+		// the overrides are part of the built-in processors, that might have been built before they were known
+		for _, id := range []component.ID{batchTraces, batchMetrics, batchLogs} {
+			c.Processors[id] = c.buildBatch(id)
+		}
+
+		c.Processors[memoryLimiter] = c.buildMemoryLimiter()
+
+		for _, processor := range processors {
+			c.Processors[processor.id] = processor.config
+		}
+	}
+
+	if c.Exporters != nil {
+		for _, exporter := range exporters {
+			c.Exporters[exporter.id] = exporter.config
+		}
+	}
+
+	for pipelineID, pipelineCfg := range c.Service.Pipelines {
+		for _, processor := range processors {
+			if slices.Contains(processor.pipelines, pipelineID) {
+				// the batch processor stays last
+				pipelineCfg.Processors = slices.Insert(pipelineCfg.Processors, len(pipelineCfg.Processors)-1, processor.id)
+			}
+		}
+
+		for _, exporter := range exporters {
+			if slices.Contains(exporter.pipelines, pipelineID) {
+				pipelineCfg.Exporters = append(pipelineCfg.Exporters, exporter.id)
+			}
+		}
+	}
+
+	return nil
+}
+
+func resolveCustomComponents(kind string, components []CustomComponent, allowed map[string][]pipeline.ID, builtinIDs []component.ID) ([]customComponent, error) {
+	resolved := make([]customComponent, 0, len(components))
+
+	for _, custom := range components {
+		var id component.ID
+		if err := id.UnmarshalText([]byte(custom.ID)); err != nil {
+			return nil, errors.WithMessagef(err, "invalid %s id '%s'", kind, custom.ID)
+		}
+
+		supportedPipelines, ok := allowed[id.Type().String()]
+		if !ok {
+			return nil, errors.Errorf("%s type '%s' of '%s' is not supported", kind, id.Type(), custom.ID)
+		}
+
+		if slices.Contains(builtinIDs, id) {
+			return nil, errors.Errorf("%s '%s' collides with a built-in component, use a name like '%s/custom'", kind, custom.ID, id.Type())
+		}
+
+		if slices.ContainsFunc(resolved, func(other customComponent) bool { return other.id == id }) {
+			return nil, errors.Errorf("%s '%s' is defined more than once", kind, custom.ID)
+		}
+
+		pipelineIDs, err := resolvePipelines(kind, custom, supportedPipelines)
+		if err != nil {
+			return nil, err
+		}
+
+		config := custom.Config
+		if config == nil {
+			config = map[string]any{}
+		}
+
+		resolved = append(resolved, customComponent{
+			id:        id,
+			config:    config,
+			pipelines: pipelineIDs,
+		})
+	}
+
+	return resolved, nil
+}
+
+func resolvePipelines(kind string, custom CustomComponent, supported []pipeline.ID) ([]pipeline.ID, error) {
+	if len(custom.Pipelines) == 0 {
+		return supported, nil
+	}
+
+	pipelineIDs := make([]pipeline.ID, 0, len(custom.Pipelines))
+
+	for _, name := range custom.Pipelines {
+		var pipelineID pipeline.ID
+		if err := pipelineID.UnmarshalText([]byte(name)); err != nil || !slices.Contains(allPipelines, pipelineID) {
+			return nil, errors.Errorf("%s '%s' references unknown pipeline '%s'", kind, custom.ID, name)
+		}
+
+		if !slices.Contains(supported, pipelineID) {
+			return nil, errors.Errorf("%s '%s' does not support the %s pipeline", kind, custom.ID, name)
+		}
+
+		pipelineIDs = append(pipelineIDs, pipelineID)
+	}
+
+	return pipelineIDs, nil
+}
+
+func (c *Config) validateOverrides() error {
+	if c.batchOverrides != nil {
+		if err := validateDuration("batch timeout", c.batchOverrides.Timeout); err != nil {
+			return err
+		}
+
+		for _, id := range []component.ID{batchTraces, batchMetrics, batchLogs} {
+			batchConfig := c.buildBatch(id)
+			if batchConfig.SendBatchMaxSize != 0 && batchConfig.SendBatchMaxSize < batchConfig.SendBatchSize {
+				return errors.Errorf("batch sendBatchMaxSize (%d) of '%s' must not be lower than sendBatchSize (%d)", batchConfig.SendBatchMaxSize, id, batchConfig.SendBatchSize)
+			}
+		}
+	}
+
+	if c.memoryLimiterOverrides != nil {
+		if err := validateDuration("memory limiter check interval", c.memoryLimiterOverrides.CheckInterval); err != nil {
+			return err
+		}
+
+		memoryLimiterConfig := c.buildMemoryLimiter()
+		if memoryLimiterConfig.MemoryLimitPercentage == 0 || memoryLimiterConfig.MemoryLimitPercentage > 100 { //nolint:mnd
+			return errors.Errorf("memory limiter limitPercentage (%d) must be between 1 and 100", memoryLimiterConfig.MemoryLimitPercentage)
+		}
+
+		if memoryLimiterConfig.MemorySpikePercentage >= memoryLimiterConfig.MemoryLimitPercentage {
+			return errors.Errorf("memory limiter spikeLimitPercentage (%d) must be lower than limitPercentage (%d)", memoryLimiterConfig.MemorySpikePercentage, memoryLimiterConfig.MemoryLimitPercentage)
+		}
+	}
+
+	return nil
+}
+
+func validateDuration(name, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if _, err := time.ParseDuration(value); err != nil {
+		return errors.WithMessagef(err, "invalid %s '%s'", name, value)
+	}
+
+	return nil
+}
+
+func (overrides *BatchOverrides) apply(batchConfig *BatchConfig) {
+	if overrides == nil {
+		return
+	}
+
+	if overrides.Timeout != "" {
+		batchConfig.Timeout = overrides.Timeout
+	}
+
+	if overrides.SendBatchSize != nil {
+		batchConfig.SendBatchSize = *overrides.SendBatchSize
+	}
+
+	if overrides.SendBatchMaxSize != nil {
+		batchConfig.SendBatchMaxSize = *overrides.SendBatchMaxSize
+	}
+}
+
+func (overrides *MemoryLimiterOverrides) apply(memoryLimiterConfig *MemoryLimiter) {
+	if overrides == nil {
+		return
+	}
+
+	if overrides.CheckInterval != "" {
+		memoryLimiterConfig.CheckInterval = overrides.CheckInterval
+	}
+
+	if overrides.LimitPercentage != nil {
+		memoryLimiterConfig.MemoryLimitPercentage = *overrides.LimitPercentage
+	}
+
+	if overrides.SpikeLimitPercentage != nil {
+		memoryLimiterConfig.MemorySpikePercentage = *overrides.SpikeLimitPercentage
+	}
+}
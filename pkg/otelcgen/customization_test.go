@@ -0,0 +1,122 @@
+package otelcgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewConfigWithCustomizations(t *testing.T) {
+	cfg, err := NewConfig(
+		"",
+		Protocols{OtlpProtocol},
+		WithCustomProcessors(
+			CustomComponent{
+				ID: "filter/drop-health-checks",
+				Config: map[string]any{
+					"traces": map[string]any{
+						"span": []string{`attributes["http.route"] == "/healthz"`},
+					},
+				},
+				Pipelines: []string{"traces"},
+			},
+			CustomComponent{
+				ID: "attributes/team",
+				Config: map[string]any{
+					"actions": []map[string]any{
+						{"key": "team", "value": "checkout", "action": "upsert"},
+					},
+				},
+			},
+		),
+		WithCustomExporters(CustomComponent{
+			ID:        "otlphttp/backup",
+			Config:    map[string]any{"endpoint": "https://backup.example.com"},
+			Pipelines: []string{"logs"},
+		}),
+		WithBatchOverrides(BatchOverrides{Timeout: "10s"}),
+		WithMemoryLimiterOverrides(MemoryLimiterOverrides{LimitPercentage: ptr.To[uint32](80)}),
+		WithProcessors(),
+		WithExporters(),
+		WithServices(),
+	)
+	require.NoError(t, err)
+	c, err := cfg.Marshal()
+	require.NoError(t, err)
+
+	expectedOutput, err := os.ReadFile(filepath.Join("testdata", "customizations.yaml"))
+	require.NoError(t, err)
+
+	assert.YAMLEq(t, string(expectedOutput), string(c))
+}
+
+func TestNewConfigWithInvalidCustomizations(t *testing.T) {
+	testCases := []struct {
+		name    string
+		option  Option
+		message string
+	}{
+		{
+			name:    "unsupported processor type",
+			option:  WithCustomProcessors(CustomComponent{ID: "groupbytrace"}),
+			message: "processor type 'groupbytrace' of 'groupbytrace' is not supported",
+		},
+		{
+			name:    "invalid id",
+			option:  WithCustomProcessors(CustomComponent{ID: "filter/"}),
+			message: "invalid processor id 'filter/'",
+		},
+		{
+			name:    "collides with built-in exporter",
+			option:  WithCustomExporters(CustomComponent{ID: "otlphttp"}),
+			message: "exporter 'otlphttp' collides with a built-in component",
+		},
+		{
+			name:    "duplicated processor",
+			option:  WithCustomProcessors(CustomComponent{ID: "filter/a"}, CustomComponent{ID: "filter/a"}),
+			message: "processor 'filter/a' is defined more than once",
+		},
+		{
+			name:    "unknown pipeline",
+			option:  WithCustomProcessors(CustomComponent{ID: "filter/a", Pipelines: []string{"profiles"}}),
+			message: "processor 'filter/a' references unknown pipeline 'profiles'",
+		},
+		{
+			name:    "unsupported pipeline",
+			option:  WithCustomProcessors(CustomComponent{ID: "tail_sampling", Pipelines: []string{"metrics"}}),
+			message: "processor 'tail_sampling' does not support the metrics pipeline",
+		},
+		{
+			name:    "invalid batch timeout",
+			option:  WithBatchOverrides(BatchOverrides{Timeout: "soon"}),
+			message: "invalid batch timeout 'soon'",
+		},
+		{
+			name:    "batch max size lower than default size",
+			option:  WithBatchOverrides(BatchOverrides{SendBatchMaxSize: ptr.To[uint32](1000)}),
+			message: "batch sendBatchMaxSize (1000) of 'batch/traces' must not be lower than sendBatchSize (5000)",
+		},
+		{
+			name:    "memory limit out of range",
+			option:  WithMemoryLimiterOverrides(MemoryLimiterOverrides{LimitPercentage: ptr.To[uint32](120)}),
+			message: "memory limiter limitPercentage (120) must be between 1 and 100",
+		},
+		{
+			name:    "spike limit above memory limit",
+			option:  WithMemoryLimiterOverrides(MemoryLimiterOverrides{LimitPercentage: ptr.To[uint32](20)}),
+			message: "memory limiter spikeLimitPercentage (30) must be lower than limitPercentage (20)",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewConfig("", RegisteredProtocols, testCase.option, WithProcessors(), WithExporters(), WithServices())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.message)
+		})
+	}
+}
@@ -93,8 +93,13 @@ type Config struct {
 	endpoint string
 	apiToken string
 
-	Service   ServiceConfig `mapstructure:"service"`
-	protocols Protocols
+	batchOverrides         *BatchOverrides
+	memoryLimiterOverrides *MemoryLimiterOverrides
+
+	Service          ServiceConfig `mapstructure:"service"`
+	protocols        Protocols
+	customProcessors []CustomComponent
+	customExporters  []CustomComponent
 
 	includeSystemCACertsPool bool
 }
@@ -113,6 +118,10 @@ func NewConfig(podIP string, protocols Protocols, options ...Option) (*Config, e
 		}
 	}
 
+	if err := c.applyCustomizations(); err != nil {
+		return nil, err
+	}
+
 	return &c, nil
 }
 
@@ -34,6 +34,30 @@ var (
 	memoryLimiter     = component.MustNewID("memory_limiter")
 	cumulativeToDelta = component.MustNewID("cumulativetodelta")
 
+	defaultBatchConfigs = map[component.ID]BatchConfig{
+		batchTraces: {
+			SendBatchSize:    5000,
+			SendBatchMaxSize: 5000,
+			Timeout:          "60s",
+		},
+		batchMetrics: {
+			SendBatchSize:    3000,
+			SendBatchMaxSize: 3000,
+			Timeout:          "60s",
+		},
+		batchLogs: {
+			SendBatchSize:    1800,
+			SendBatchMaxSize: 2000,
+			Timeout:          "60s",
+		},
+	}
+
+	defaultMemoryLimiter = MemoryLimiter{
+		CheckInterval:         "1s",
+		MemoryLimitPercentage: 70,
+		MemorySpikePercentage: 30,
+	}
+
 	defaultK8Sattributes = []string{
 		"k8s.cluster.uid",
 		"k8s.node.name",
@@ -88,30 +112,28 @@ func (c *Config) buildProcessors() map[component.ID]component.Config {
 				},
 			},
 		},
-		transform: c.buildTransform(),
-		batchTraces: &BatchConfig{
-			SendBatchSize:    5000,
-			SendBatchMaxSize: 5000,
-			Timeout:          "60s",
-		},
-		batchMetrics: &BatchConfig{
-			SendBatchSize:    3000,
-			SendBatchMaxSize: 3000,
-			Timeout:          "60s",
-		},
-		batchLogs: &BatchConfig{
-			SendBatchSize:    1800,
-			SendBatchMaxSize: 2000,
-			Timeout:          "60s",
-		},
-		memoryLimiter: &MemoryLimiter{
-			CheckInterval:         "1s",
-			MemoryLimitPercentage: 70,
-			MemorySpikePercentage: 30,
-		},
+		transform:     c.buildTransform(),
+		batchTraces:   c.buildBatch(batchTraces),
+		batchMetrics:  c.buildBatch(batchMetrics),
+		batchLogs:     c.buildBatch(batchLogs),
+		memoryLimiter: c.buildMemoryLimiter(),
 	}
 }
 
+func (c *Config) buildBatch(id component.ID) *BatchConfig {
+	batchConfig := defaultBatchConfigs[id]
+	c.batchOverrides.apply(&batchConfig)
+
+	return &batchConfig
+}
+
+func (c *Config) buildMemoryLimiter() *MemoryLimiter {
+	memoryLimiterConfig := defaultMemoryLimiter
+	c.memoryLimiterOverrides.apply(&memoryLimiterConfig)
+
+	return &memoryLimiterConfig
+}
+
 func (c *Config) buildTransform() map[string]any {
 	return map[string]any{
 		"error_mode":        "ignore",
@@ -0,0 +1,179 @@
+package issuer
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const intSerialNumberLimit = 128
+
+var serialNumberLimit = new(big.Int).Lsh(big.NewInt(1), intSerialNumberLimit)
+
+// caIssuer signs the certificates with a CA provided by the user.
+type caIssuer struct {
+	apiReader    client.Reader
+	timeProvider *timeprovider.Provider
+	namespace    string
+	secretName   string
+}
+
+type signingCA struct {
+	cert   *x509.Certificate
+	key    crypto.Signer
+	chain  []byte
+	bundle []byte
+}
+
+func (issuer *caIssuer) Issue(ctx context.Context, request Request, current map[string][]byte) (map[string][]byte, error) {
+	ca, err := issuer.loadCA(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := issuer.timeProvider.Now().Time
+
+	if issuer.isCurrentValid(ca, request, current) {
+		return current, nil
+	}
+
+	log.Info("signing certificate", "secret", request.SecretName, "ca", issuer.secretName)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate private key")
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate serial number")
+	}
+
+	notAfter := now.Add(request.Duration)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: request.CommonName},
+		DNSNames:     request.DNSNames,
+		IPAddresses:  request.IPAddresses,
+
+		NotBefore: now,
+		NotAfter:  notAfter,
+
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, tpl, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign certificate")
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	cert = append(cert, ca.chain...)
+
+	return mergeIssued(current, cert, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), ca.bundle), nil
+}
+
+func (issuer *caIssuer) isCurrentValid(ca *signingCA, request Request, current map[string][]byte) bool {
+	if len(current[consts.TLSKeyDataName]) == 0 || !bytes.Equal(current[CACrtDataName], ca.bundle) {
+		return false
+	}
+
+	cert, err := parseLeaf(current[consts.TLSCrtDataName])
+	if err != nil {
+		log.Info("failed to parse certificate, renewing", "secret", request.SecretName, "error", err)
+
+		return false
+	}
+
+	if cert.CheckSignatureFrom(ca.cert) != nil {
+		log.Info("certificate isn't signed by the CA, renewing", "secret", request.SecretName)
+
+		return false
+	}
+
+	return matchesRequest(cert, request, issuer.timeProvider.Now().Time)
+}
+
+func (issuer *caIssuer) loadCA(ctx context.Context) (*signingCA, error) {
+	var secret corev1.Secret
+
+	err := issuer.apiReader.Get(ctx, types.NamespacedName{Name: issuer.secretName, Namespace: issuer.namespace}, &secret)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get CA secret '%s'", issuer.secretName)
+	}
+
+	chain := secret.Data[consts.TLSCrtDataName]
+
+	cert, err := parseLeaf(chain)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse CA certificate of secret '%s'", issuer.secretName)
+	}
+
+	if !cert.IsCA {
+		return nil, errors.Errorf("certificate of secret '%s' is not a CA", issuer.secretName)
+	}
+
+	key, err := parsePrivateKey(secret.Data[consts.TLSKeyDataName])
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to parse CA key of secret '%s'", issuer.secretName)
+	}
+
+	bundle := secret.Data[CACrtDataName]
+	if len(bundle) == 0 {
+		bundle = chain
+	}
+
+	return &signingCA{cert: cert, key: key, chain: chain, bundle: bundle}, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("can't decode PEM file")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+
+		return signer, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return key, nil
+}
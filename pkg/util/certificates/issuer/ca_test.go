@@ -0,0 +1,209 @@
+package issuer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testNamespace    = "dynatrace"
+	testCASecretName = "corporate-ca"
+)
+
+func TestCAIssuer(t *testing.T) {
+	ctx := context.Background()
+
+	request := Request{
+		Namespace:   testNamespace,
+		SecretName:  "tls",
+		CommonName:  "dynakube-activegate.dynatrace",
+		DNSNames:    []string{"dynakube-activegate.dynatrace", "dynakube-activegate.dynatrace.svc"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+		Duration:    90 * 24 * time.Hour,
+		RenewBefore: 30 * 24 * time.Hour,
+	}
+
+	t.Run("certificate is signed by the CA", func(t *testing.T) {
+		caCert, caSecret := createCASecret(t, true)
+		issuer := createCAIssuer(caSecret)
+
+		data, err := issuer.Issue(ctx, request, map[string][]byte{selfSignedCAKeyDataName: []byte("old")})
+		require.NoError(t, err)
+
+		assert.Equal(t, caSecret.Data[consts.TLSCrtDataName], data[CACrtDataName])
+		assert.NotEmpty(t, data[consts.TLSKeyDataName])
+		assert.NotContains(t, data, selfSignedCAKeyDataName)
+		assert.NotContains(t, data, CACrtOldDataName)
+
+		cert, err := parseLeaf(data[consts.TLSCrtDataName])
+		require.NoError(t, err)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(caCert)
+
+		_, err = cert.Verify(x509.VerifyOptions{DNSName: request.DNSNames[1], Roots: roots})
+		require.NoError(t, err)
+		assert.Equal(t, request.CommonName, cert.Subject.CommonName)
+		assert.Equal(t, "10.0.0.1", cert.IPAddresses[0].String())
+	})
+
+	t.Run("valid certificate is kept", func(t *testing.T) {
+		_, caSecret := createCASecret(t, true)
+		issuer := createCAIssuer(caSecret)
+
+		data, err := issuer.Issue(ctx, request, nil)
+		require.NoError(t, err)
+
+		again, err := issuer.Issue(ctx, request, data)
+		require.NoError(t, err)
+
+		assert.Equal(t, data, again)
+	})
+
+	t.Run("certificate is renewed before it expires", func(t *testing.T) {
+		_, caSecret := createCASecret(t, true)
+		issuer := createCAIssuer(caSecret)
+
+		data, err := issuer.Issue(ctx, request, nil)
+		require.NoError(t, err)
+
+		issuer.timeProvider.Set(time.Now().Add(request.Duration - request.RenewBefore + time.Hour))
+
+		renewed, err := issuer.Issue(ctx, request, data)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, data[consts.TLSCrtDataName], renewed[consts.TLSCrtDataName])
+	})
+
+	t.Run("certificate is renewed if the names change", func(t *testing.T) {
+		_, caSecret := createCASecret(t, true)
+		issuer := createCAIssuer(caSecret)
+
+		data, err := issuer.Issue(ctx, request, nil)
+		require.NoError(t, err)
+
+		changed := request
+		changed.IPAddresses = []net.IP{net.ParseIP("10.0.0.2")}
+
+		renewed, err := issuer.Issue(ctx, changed, data)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, data[consts.TLSCrtDataName], renewed[consts.TLSCrtDataName])
+	})
+
+	t.Run("previous trust bundle is kept when the CA changes", func(t *testing.T) {
+		_, caSecret := createCASecret(t, true)
+		data, err := createCAIssuer(caSecret).Issue(ctx, request, nil)
+		require.NoError(t, err)
+
+		_, newCASecret := createCASecret(t, true)
+
+		renewed, err := createCAIssuer(newCASecret).Issue(ctx, request, data)
+		require.NoError(t, err)
+
+		assert.Equal(t, newCASecret.Data[consts.TLSCrtDataName], renewed[CACrtDataName])
+		assert.Equal(t, caSecret.Data[consts.TLSCrtDataName], renewed[CACrtOldDataName])
+		assert.Equal(t, append(newCASecret.Data[consts.TLSCrtDataName], caSecret.Data[consts.TLSCrtDataName]...), TrustBundle(renewed))
+	})
+
+	t.Run("previous trust bundle expires with the next renewal", func(t *testing.T) {
+		_, caSecret := createCASecret(t, true)
+		data, err := createCAIssuer(caSecret).Issue(ctx, request, nil)
+		require.NoError(t, err)
+
+		_, newCASecret := createCASecret(t, true)
+		newIssuer := createCAIssuer(newCASecret)
+
+		rotated, err := newIssuer.Issue(ctx, request, data)
+		require.NoError(t, err)
+		require.Contains(t, rotated, CACrtOldDataName)
+
+		kept, err := newIssuer.Issue(ctx, request, rotated)
+		require.NoError(t, err)
+		assert.Contains(t, kept, CACrtOldDataName)
+
+		changed := request
+		changed.IPAddresses = []net.IP{net.ParseIP("10.0.0.2")}
+
+		renewed, err := newIssuer.Issue(ctx, changed, rotated)
+		require.NoError(t, err)
+
+		assert.NotContains(t, renewed, CACrtOldDataName)
+		assert.Equal(t, newCASecret.Data[consts.TLSCrtDataName], TrustBundle(renewed))
+	})
+
+	t.Run("certificate that isn't a CA is rejected", func(t *testing.T) {
+		_, caSecret := createCASecret(t, false)
+
+		_, err := createCAIssuer(caSecret).Issue(ctx, request, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("missing CA secret is an error", func(t *testing.T) {
+		clt := fake.NewClient()
+		issuer := &caIssuer{apiReader: clt, namespace: testNamespace, secretName: testCASecretName, timeProvider: timeprovider.New()}
+
+		_, err := issuer.Issue(ctx, request, nil)
+		require.Error(t, err)
+	})
+}
+
+func createCAIssuer(caSecret *corev1.Secret) *caIssuer {
+	return &caIssuer{
+		apiReader:    fake.NewClient(caSecret),
+		namespace:    testNamespace,
+		secretName:   testCASecretName,
+		timeProvider: timeprovider.New(),
+	}
+}
+
+func createCASecret(t *testing.T, isCA bool) (*x509.Certificate, *corev1.Secret) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Corporate CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  isCA,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, tpl, tpl, key.Public(), key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return cert, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testCASecretName, Namespace: testNamespace},
+		Data: map[string][]byte{
+			consts.TLSCrtDataName: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+			consts.TLSKeyDataName: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}
+}
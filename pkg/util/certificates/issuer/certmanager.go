@@ -0,0 +1,149 @@
+package issuer
+
+import (
+	"context"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	certManagerGroup = "cert-manager.io"
+
+	// IssuedSecretSuffix is appended to the name of the secret of the request to get the name of the secret cert-manager writes the certificate to.
+	// The operator copies the certificate from there, so the secrets consumed by the components keep their format and owner.
+	IssuedSecretSuffix = "-issued"
+)
+
+var CertificateGVK = schema.GroupVersionKind{Group: certManagerGroup, Version: "v1", Kind: "Certificate"}
+
+// certManagerIssuer requests the certificates from cert-manager by creating Certificate objects.
+type certManagerIssuer struct {
+	client    client.Client
+	apiReader client.Reader
+	issuerRef installconfig.IssuerRef
+}
+
+func (issuer *certManagerIssuer) Issue(ctx context.Context, request Request, current map[string][]byte) (map[string][]byte, error) {
+	err := issuer.ensureCertificate(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var issued corev1.Secret
+
+	err = issuer.apiReader.Get(ctx, types.NamespacedName{Name: request.SecretName + IssuedSecretSuffix, Namespace: request.Namespace}, &issued)
+	if k8serrors.IsNotFound(err) {
+		return nil, ErrNotIssued
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	cert, key := issued.Data[consts.TLSCrtDataName], issued.Data[consts.TLSKeyDataName]
+	if len(cert) == 0 || len(key) == 0 {
+		return nil, ErrNotIssued
+	}
+
+	bundle := issued.Data[CACrtDataName]
+	if len(bundle) == 0 {
+		return nil, errors.Errorf("issuer '%s' doesn't provide the CA of the certificate in '%s'", issuer.issuerRef.Name, CACrtDataName)
+	}
+
+	return mergeIssued(current, cert, key, bundle), nil
+}
+
+func (issuer *certManagerIssuer) ensureCertificate(ctx context.Context, request Request) error {
+	desired := issuer.buildCertificate(request)
+
+	err := controllerutil.SetControllerReference(request.Owner, desired, scheme.Scheme)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(CertificateGVK)
+
+	err = issuer.apiReader.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if k8serrors.IsNotFound(err) {
+		log.Info("creating certificate", "name", desired.GetName(), "namespace", desired.GetNamespace())
+
+		return errors.WithStack(issuer.client.Create(ctx, desired))
+	} else if err != nil {
+		return errors.WithMessage(err, "failed to get cert-manager certificate, is cert-manager installed?")
+	}
+
+	if equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+
+	log.Info("updating certificate", "name", desired.GetName(), "namespace", desired.GetNamespace())
+
+	desired.SetResourceVersion(existing.GetResourceVersion())
+
+	return errors.WithStack(issuer.client.Update(ctx, desired))
+}
+
+func (issuer *certManagerIssuer) buildCertificate(request Request) *unstructured.Unstructured {
+	issuerRef := map[string]any{
+		"name":  issuer.issuerRef.Name,
+		"kind":  installconfig.IssuerKind,
+		"group": certManagerGroup,
+	}
+
+	if issuer.issuerRef.Kind != "" {
+		issuerRef["kind"] = issuer.issuerRef.Kind
+	}
+
+	if issuer.issuerRef.Group != "" {
+		issuerRef["group"] = issuer.issuerRef.Group
+	}
+
+	spec := map[string]any{
+		"secretName":  request.SecretName + IssuedSecretSuffix,
+		"commonName":  request.CommonName,
+		"duration":    request.Duration.String(),
+		"renewBefore": request.RenewBefore.String(),
+		"issuerRef":   issuerRef,
+		"privateKey": map[string]any{
+			"algorithm":      "ECDSA",
+			"size":           int64(256),
+			"rotationPolicy": "Always",
+		},
+		"usages": []any{"server auth", "digital signature", "key encipherment"},
+	}
+
+	if len(request.DNSNames) > 0 {
+		dnsNames := make([]any, 0, len(request.DNSNames))
+		for _, name := range request.DNSNames {
+			dnsNames = append(dnsNames, name)
+		}
+
+		spec["dnsNames"] = dnsNames
+	}
+
+	if len(request.IPAddresses) > 0 {
+		ipAddresses := make([]any, 0, len(request.IPAddresses))
+		for _, ip := range request.IPAddresses {
+			ipAddresses = append(ipAddresses, ip.String())
+		}
+
+		spec["ipAddresses"] = ipAddresses
+	}
+
+	certificate := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	certificate.SetGroupVersionKind(CertificateGVK)
+	certificate.SetName(request.SecretName)
+	certificate.SetNamespace(request.Namespace)
+
+	return certificate
+}
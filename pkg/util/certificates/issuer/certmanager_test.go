@@ -0,0 +1,186 @@
+package issuer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCertManagerIssuer(t *testing.T) {
+	ctx := context.Background()
+
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: testNamespace, UID: "uid"}}
+	request := Request{
+		Owner:       owner,
+		Namespace:   testNamespace,
+		SecretName:  "tls",
+		CommonName:  "dynakube-activegate.dynatrace",
+		DNSNames:    []string{"dynakube-activegate.dynatrace"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+		Duration:    90 * 24 * time.Hour,
+		RenewBefore: 30 * 24 * time.Hour,
+	}
+
+	getCertificate := func(t *testing.T, clt client.Client) *unstructured.Unstructured {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(CertificateGVK)
+
+		err := clt.Get(ctx, client.ObjectKey{Name: request.SecretName, Namespace: testNamespace}, certificate)
+		require.NoError(t, err)
+
+		return certificate
+	}
+
+	t.Run("certificate is requested and not issued yet", func(t *testing.T) {
+		clt := fake.NewClient()
+		issuer := &certManagerIssuer{client: clt, apiReader: clt, issuerRef: installconfig.IssuerRef{Name: "corporate", Kind: installconfig.ClusterIssuerKind}}
+
+		_, err := issuer.Issue(ctx, request, nil)
+		require.ErrorIs(t, err, ErrNotIssued)
+
+		certificate := getCertificate(t, clt)
+		assert.Equal(t, owner.Name, certificate.GetOwnerReferences()[0].Name)
+
+		secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+		assert.Equal(t, request.SecretName+IssuedSecretSuffix, secretName)
+
+		kind, _, _ := unstructured.NestedString(certificate.Object, "spec", "issuerRef", "kind")
+		assert.Equal(t, installconfig.ClusterIssuerKind, kind)
+
+		ipAddresses, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "ipAddresses")
+		assert.Equal(t, []string{"10.0.0.1"}, ipAddresses)
+	})
+
+	t.Run("certificate is updated if the request changes", func(t *testing.T) {
+		clt := fake.NewClient()
+		issuer := &certManagerIssuer{client: clt, apiReader: clt, issuerRef: installconfig.IssuerRef{Name: "corporate"}}
+
+		_, err := issuer.Issue(ctx, request, nil)
+		require.ErrorIs(t, err, ErrNotIssued)
+
+		changed := request
+		changed.DNSNames = []string{"other.dynatrace"}
+
+		_, err = issuer.Issue(ctx, changed, nil)
+		require.ErrorIs(t, err, ErrNotIssued)
+
+		dnsNames, _, _ := unstructured.NestedStringSlice(getCertificate(t, clt).Object, "spec", "dnsNames")
+		assert.Equal(t, changed.DNSNames, dnsNames)
+	})
+
+	t.Run("issued certificate is copied", func(t *testing.T) {
+		clt := fake.NewClient(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: request.SecretName + IssuedSecretSuffix, Namespace: testNamespace},
+			Data: map[string][]byte{
+				consts.TLSCrtDataName: []byte("cert"),
+				consts.TLSKeyDataName: []byte("key"),
+				CACrtDataName:         []byte("ca"),
+			},
+		})
+		issuer := &certManagerIssuer{client: clt, apiReader: clt, issuerRef: installconfig.IssuerRef{Name: "corporate"}}
+
+		data, err := issuer.Issue(ctx, request, map[string][]byte{"server.crt": []byte("ca")})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string][]byte{
+			consts.TLSCrtDataName: []byte("cert"),
+			consts.TLSKeyDataName: []byte("key"),
+			CACrtDataName:         []byte("ca"),
+			"server.crt":          []byte("ca"),
+		}, data)
+	})
+
+	t.Run("previous trust bundle expires with the next renewal", func(t *testing.T) {
+		clt := fake.NewClient(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: request.SecretName + IssuedSecretSuffix, Namespace: testNamespace},
+			Data: map[string][]byte{
+				consts.TLSCrtDataName: []byte("renewed-cert"),
+				consts.TLSKeyDataName: []byte("key"),
+				CACrtDataName:         []byte("ca"),
+			},
+		})
+		issuer := &certManagerIssuer{client: clt, apiReader: clt, issuerRef: installconfig.IssuerRef{Name: "corporate"}}
+
+		current := map[string][]byte{
+			consts.TLSCrtDataName: []byte("renewed-cert"),
+			CACrtDataName:         []byte("ca"),
+			CACrtOldDataName:      []byte("old-ca"),
+		}
+
+		data, err := issuer.Issue(ctx, request, current)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("old-ca"), data[CACrtOldDataName])
+
+		current[consts.TLSCrtDataName] = []byte("cert")
+
+		data, err = issuer.Issue(ctx, request, current)
+		require.NoError(t, err)
+		assert.NotContains(t, data, CACrtOldDataName)
+	})
+
+	t.Run("issuer without CA is rejected", func(t *testing.T) {
+		clt := fake.NewClient(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: request.SecretName + IssuedSecretSuffix, Namespace: testNamespace},
+			Data: map[string][]byte{
+				consts.TLSCrtDataName: []byte("cert"),
+				consts.TLSKeyDataName: []byte("key"),
+			},
+		})
+		issuer := &certManagerIssuer{client: clt, apiReader: clt, issuerRef: installconfig.IssuerRef{Name: "corporate"}}
+
+		_, err := issuer.Issue(ctx, request, nil)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrNotIssued)
+	})
+}
+
+func TestNew(t *testing.T) {
+	clt := fake.NewClient()
+
+	t.Run("self-signed has no issuer", func(t *testing.T) {
+		issuer, err := New(clt, clt, testNamespace)
+		require.NoError(t, err)
+		assert.Nil(t, issuer)
+	})
+
+	t.Run("CA secret", func(t *testing.T) {
+		installconfig.SetCertificateSourceOverride(t, installconfig.CertificateSource{Type: installconfig.CASecretCertificateSource, CASecret: testCASecretName})
+
+		issuer, err := New(clt, clt, testNamespace)
+		require.NoError(t, err)
+		assert.IsType(t, &caIssuer{}, issuer)
+	})
+
+	t.Run("cert-manager", func(t *testing.T) {
+		installconfig.SetCertificateSourceOverride(t, installconfig.CertificateSource{Type: installconfig.CertManagerCertificateSource, Issuer: &installconfig.IssuerRef{Name: "corporate"}})
+
+		issuer, err := New(clt, clt, testNamespace)
+		require.NoError(t, err)
+		assert.IsType(t, &certManagerIssuer{}, issuer)
+	})
+
+	t.Run("incomplete sources are rejected", func(t *testing.T) {
+		for _, source := range []installconfig.CertificateSource{
+			{Type: installconfig.CASecretCertificateSource},
+			{Type: installconfig.CertManagerCertificateSource},
+			{Type: installconfig.CertManagerCertificateSource, Issuer: &installconfig.IssuerRef{Name: "corporate", Kind: "Unknown"}},
+			{Type: "Unknown"},
+		} {
+			installconfig.SetCertificateSourceOverride(t, source)
+
+			_, err := New(clt, clt, testNamespace)
+			require.Error(t, err, source.Type)
+		}
+	})
+}
@@ -0,0 +1,9 @@
+package issuer
+
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+)
+
+var (
+	log = logd.Get().WithName("certificate-issuer")
+)
@@ -0,0 +1,190 @@
+package issuer
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"maps"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CACrtDataName is the key of the trust bundle of the issued certificate, it is used as caBundle of the webhook configurations.
+	CACrtDataName = "ca.crt"
+
+	// CACrtOldDataName is the key of the previous trust bundle, it is kept so clients trust both during a CA rotation.
+	CACrtOldDataName = "ca.crt.old"
+
+	// selfSignedCAKeyDataName is the key the built-in self-signed CA stores its private key with, it is removed once an issuer takes over.
+	selfSignedCAKeyDataName = "ca.key"
+)
+
+var ErrNotIssued = errors.New("certificate has not been issued yet")
+
+// Request describes the TLS server certificate stored in a secret.
+type Request struct {
+	// Owner of the objects created to issue the certificate.
+	Owner client.Object
+
+	Namespace  string
+	SecretName string
+
+	CommonName  string
+	DNSNames    []string
+	IPAddresses []net.IP
+
+	Duration    time.Duration
+	RenewBefore time.Duration
+}
+
+// Issuer issues TLS server certificates that chain to the CA of the certificate source configured during install.
+type Issuer interface {
+	// Issue returns the data of the secret of the request, based on its current data.
+	// The current certificate is kept as long as it is valid for the request and not due for renewal.
+	// ErrNotIssued is returned if the certificate is issued asynchronously and not available yet.
+	Issue(ctx context.Context, request Request, current map[string][]byte) (map[string][]byte, error)
+}
+
+// New returns the issuer of the certificate source configured during install,
+// nil if the certificates are signed by the built-in self-signed CA.
+func New(clt client.Client, apiReader client.Reader, operatorNamespace string) (Issuer, error) {
+	source := installconfig.GetCertificateSource()
+
+	switch {
+	case source.IsSelfSigned():
+		return nil, nil
+	case source.Type == installconfig.CASecretCertificateSource:
+		if source.CASecret == "" {
+			return nil, errors.New("certificate source CASecret requires the name of the CA secret")
+		}
+
+		return &caIssuer{
+			apiReader:    apiReader,
+			namespace:    operatorNamespace,
+			secretName:   source.CASecret,
+			timeProvider: timeprovider.New(),
+		}, nil
+	case source.Type == installconfig.CertManagerCertificateSource:
+		if source.Issuer == nil || source.Issuer.Name == "" {
+			return nil, errors.New("certificate source CertManager requires the name of the issuer")
+		}
+
+		if source.Issuer.Kind != "" && source.Issuer.Kind != installconfig.IssuerKind && source.Issuer.Kind != installconfig.ClusterIssuerKind {
+			return nil, errors.Errorf("unsupported kind '%s' of the cert-manager issuer", source.Issuer.Kind)
+		}
+
+		return &certManagerIssuer{
+			client:    clt,
+			apiReader: apiReader,
+			issuerRef: *source.Issuer,
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported certificate source '%s'", source.Type)
+	}
+}
+
+// mergeIssued returns the current data with the issued certificate, the previous trust bundle is kept if it changed.
+// The previous trust bundle expires with the next renewal of the certificate, as clients had a whole renewal period to pick up the new one by then.
+func mergeIssued(current map[string][]byte, cert, key, bundle []byte) map[string][]byte {
+	data := maps.Clone(current)
+	if data == nil {
+		data = map[string][]byte{}
+	}
+
+	delete(data, selfSignedCAKeyDataName)
+
+	previous := current[CACrtDataName]
+
+	switch {
+	case len(previous) > 0 && !bytes.Equal(previous, bundle):
+		data[CACrtOldDataName] = previous
+	case !bytes.Equal(current[consts.TLSCrtDataName], cert):
+		delete(data, CACrtOldDataName)
+	}
+
+	data[consts.TLSCrtDataName] = cert
+	data[consts.TLSKeyDataName] = key
+	data[CACrtDataName] = bundle
+
+	return data
+}
+
+// parseLeaf returns the first certificate of the PEM encoded chain.
+func parseLeaf(chain []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(chain)
+	if block == nil {
+		return nil, errors.New("can't decode PEM file")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+
+	return cert, errors.WithStack(err)
+}
+
+func matchesRequest(cert *x509.Certificate, request Request, now time.Time) bool {
+	if now.After(cert.NotAfter.Add(-request.RenewBefore)) {
+		log.Info("certificate is about to expire, renewing", "secret", request.SecretName, "expiration", cert.NotAfter)
+
+		return false
+	}
+
+	if !sameElements(cert.DNSNames, request.DNSNames) {
+		log.Info("certificate doesn't match the DNS names, renewing", "secret", request.SecretName)
+
+		return false
+	}
+
+	ips := make([]string, 0, len(cert.IPAddresses))
+	for _, ip := range cert.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+
+	requestedIPs := make([]string, 0, len(request.IPAddresses))
+	for _, ip := range request.IPAddresses {
+		requestedIPs = append(requestedIPs, ip.String())
+	}
+
+	if !sameElements(ips, requestedIPs) {
+		log.Info("certificate doesn't match the IP addresses, renewing", "secret", request.SecretName)
+
+		return false
+	}
+
+	return true
+}
+
+func sameElements(a, b []string) bool {
+	a = slices.Sorted(slices.Values(a))
+	b = slices.Sorted(slices.Values(b))
+
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// IssueSecretData returns the data of the secret of the request with the certificate issued by the issuer, based on the secret in the cluster.
+func IssueSecretData(ctx context.Context, certIssuer Issuer, apiReader client.Reader, request Request) (map[string][]byte, error) {
+	var current corev1.Secret
+
+	err := apiReader.Get(ctx, types.NamespacedName{Name: request.SecretName, Namespace: request.Namespace}, &current)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, errors.WithStack(err)
+	}
+
+	return certIssuer.Issue(ctx, request, current.Data)
+}
+
+// TrustBundle returns the current and the previous trust bundle of the data, so clients keep trusting the certificate during a CA rotation.
+func TrustBundle(data map[string][]byte) []byte {
+	return append(slices.Clone(data[CACrtDataName]), data[CACrtOldDataName]...)
+}
@@ -0,0 +1,24 @@
+package conditions
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ImageVerificationFailedReason = "ImageVerificationFailed"
+)
+
+func SetImageVerificationFailed(conditions *[]metav1.Condition, conditionType string, err error) {
+	if err == nil {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  ImageVerificationFailedReason,
+		Message: "Image verification failed: " + err.Error(),
+	}
+	_ = meta.SetStatusCondition(conditions, condition)
+}
@@ -1,6 +1,8 @@
 package conditions
 
 import (
+	"time"
+
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -17,3 +19,18 @@ func IsOutdated(timeProvider *timeprovider.Provider, dk *dynakube.DynaKube, cond
 
 	return condition.Status == metav1.ConditionFalse || timeProvider.IsOutdated(&condition.LastTransitionTime, dk.ApiRequestThreshold())
 }
+
+// NextUpdate returns the point in time after which the given condition is considered outdated, see IsOutdated.
+// The zero time is returned if the condition is not present, as then there is nothing to update.
+func NextUpdate(dk *dynakube.DynaKube, conditionType string) time.Time {
+	condition := meta.FindStatusCondition(*dk.Conditions(), conditionType)
+	if condition == nil {
+		return time.Time{}
+	}
+
+	if condition.Status == metav1.ConditionFalse {
+		return condition.LastTransitionTime.Time
+	}
+
+	return condition.LastTransitionTime.Add(dk.ApiRequestThreshold())
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/timeprovider"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
 )
 
 func TestIsOutdated(t *testing.T) {
@@ -50,3 +51,30 @@ func TestIsOutdated(t *testing.T) {
 		assert.True(t, IsOutdated(tp, dk, testingConditionType))
 	})
 }
+
+func TestNextUpdate(t *testing.T) {
+	testingConditionType := "testing "
+
+	t.Run("empty condition => zero time", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+
+		assert.True(t, NextUpdate(dk, testingConditionType).IsZero())
+	})
+
+	t.Run("False condition => due right away", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+
+		SetDynatraceApiError(dk.Conditions(), testingConditionType, errors.New("boom"))
+
+		assert.False(t, NextUpdate(dk, testingConditionType).After(time.Now()))
+	})
+
+	t.Run("True condition => due after threshold", func(t *testing.T) {
+		dk := &dynakube.DynaKube{}
+
+		SetSecretCreated(dk.Conditions(), testingConditionType, "")
+		condition := meta.FindStatusCondition(dk.Status.Conditions, testingConditionType)
+
+		assert.Equal(t, condition.LastTransitionTime.Add(dk.ApiRequestThreshold()), NextUpdate(dk, testingConditionType))
+	})
+}
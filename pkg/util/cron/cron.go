@@ -0,0 +1,221 @@
+// Package cron parses the standard 5 field cron expressions (minute, hour, day of month, month, day of week),
+// which are used to define the start of maintenance windows.
+// - Supported syntax per field: `*`, single values, ranges (`1-5`), lists (`1,3,5`) and steps (`*/15`, `0-30/10`).
+// - Months and weekdays are only supported as numbers, Sunday is 0 or 7.
+// - The macros @yearly, @monthly, @weekly, @daily and @hourly are supported as well.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxLookahead limits the search for the next activation, so impossible expressions (like the 31st of February) terminate.
+const maxLookahead = 5 * 366 * 24 * time.Hour
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type bounds struct {
+	min, max int
+}
+
+var (
+	minuteBounds     = bounds{0, 59}
+	hourBounds       = bounds{0, 23}
+	dayOfMonthBounds = bounds{1, 31}
+	monthBounds      = bounds{1, 12}
+	dayOfWeekBounds  = bounds{0, 7}
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// the day is matched by either the day of month or the day of week, if both are restricted
+	dayOfMonthStar, dayOfWeekStar bool
+}
+
+// Parse parses a 5 field cron expression or a macro.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := macros[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("expected 5 fields in cron expression %q, got %d", spec, len(fields))
+	}
+
+	schedule := &Schedule{
+		dayOfMonthStar: strings.HasPrefix(fields[2], "*"),
+		dayOfWeekStar:  strings.HasPrefix(fields[4], "*"),
+	}
+
+	var err error
+
+	for i, field := range []struct {
+		target *uint64
+		bounds bounds
+	}{
+		{&schedule.minute, minuteBounds},
+		{&schedule.hour, hourBounds},
+		{&schedule.dayOfMonth, dayOfMonthBounds},
+		{&schedule.month, monthBounds},
+		{&schedule.dayOfWeek, dayOfWeekBounds},
+	} {
+		*field.target, err = parseField(fields[i], field.bounds)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid cron expression %q", spec)
+		}
+	}
+
+	// Sunday can be given as 7, but time.Weekday uses 0
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+func parseField(field string, fieldBounds bounds) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		partBits, err := parsePart(part, fieldBounds)
+		if err != nil {
+			return 0, err
+		}
+
+		bits |= partBits
+	}
+
+	return bits, nil
+}
+
+func parsePart(part string, fieldBounds bounds) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+
+	if hasStep {
+		var err error
+
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step <= 0 {
+			return 0, errors.Errorf("invalid step %q", part)
+		}
+	}
+
+	start, end := fieldBounds.min, fieldBounds.max
+
+	switch {
+	case rangePart == "*":
+	case strings.Contains(rangePart, "-"):
+		low, high, _ := strings.Cut(rangePart, "-")
+
+		var err error
+
+		start, err = parseValue(low, fieldBounds)
+		if err != nil {
+			return 0, err
+		}
+
+		end, err = parseValue(high, fieldBounds)
+		if err != nil {
+			return 0, err
+		}
+
+		if start > end {
+			return 0, errors.Errorf("invalid range %q", part)
+		}
+	default:
+		value, err := parseValue(rangePart, fieldBounds)
+		if err != nil {
+			return 0, err
+		}
+
+		start = value
+		if !hasStep {
+			end = value
+		}
+	}
+
+	var bits uint64
+	for value := start; value <= end; value += step {
+		bits |= 1 << uint(value) //nolint:gosec
+	}
+
+	return bits, nil
+}
+
+func parseValue(value string, fieldBounds bounds) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q", value)
+	}
+
+	if number < fieldBounds.min || number > fieldBounds.max {
+		return 0, errors.Errorf("value %d out of range [%d-%d]", number, fieldBounds.min, fieldBounds.max)
+	}
+
+	return number, nil
+}
+
+// Next returns the first activation of the schedule strictly after the given time, in the location of the given time.
+// The zero time is returned if the schedule has no activation within the next 5 years.
+func (schedule *Schedule) Next(after time.Time) time.Time {
+	current := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxLookahead)
+
+	for current.Before(limit) {
+		switch {
+		case !schedule.matchesMonth(current):
+			current = time.Date(current.Year(), current.Month()+1, 1, 0, 0, 0, 0, current.Location())
+		case !schedule.matchesDay(current):
+			current = time.Date(current.Year(), current.Month(), current.Day()+1, 0, 0, 0, 0, current.Location())
+		case !schedule.matchesHour(current):
+			current = time.Date(current.Year(), current.Month(), current.Day(), current.Hour()+1, 0, 0, 0, current.Location())
+		case !schedule.matchesMinute(current):
+			current = current.Add(time.Minute)
+		default:
+			return current
+		}
+	}
+
+	return time.Time{}
+}
+
+func (schedule *Schedule) matchesMinute(t time.Time) bool {
+	return schedule.minute&(1<<uint(t.Minute())) != 0 //nolint:gosec
+}
+
+func (schedule *Schedule) matchesHour(t time.Time) bool {
+	return schedule.hour&(1<<uint(t.Hour())) != 0 //nolint:gosec
+}
+
+func (schedule *Schedule) matchesMonth(t time.Time) bool {
+	return schedule.month&(1<<uint(t.Month())) != 0
+}
+
+func (schedule *Schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := schedule.dayOfMonth&(1<<uint(t.Day())) != 0 //nolint:gosec
+	dayOfWeek := schedule.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if schedule.dayOfMonthStar || schedule.dayOfWeekStar {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,70 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("valid expressions", func(t *testing.T) {
+		for _, spec := range []string{"* * * * *", "0 22 * * 1-5", "*/15 0-6 1,15 * 0", "0 0 * * 7", "@daily", "30 2 * 1-3/2 *"} {
+			_, err := Parse(spec)
+			require.NoError(t, err, spec)
+		}
+	})
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every 1h"} {
+			_, err := Parse(spec)
+			require.Error(t, err, spec)
+		}
+	})
+}
+
+func TestNext(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	next := func(t *testing.T, spec string, after time.Time) time.Time {
+		schedule, err := Parse(spec)
+		require.NoError(t, err)
+
+		return schedule.Next(after)
+	}
+
+	t.Run("every minute", func(t *testing.T) {
+		assert.Equal(t, now.Add(time.Minute), next(t, "* * * * *", now))
+	})
+
+	t.Run("later the same day", func(t *testing.T) {
+		assert.Equal(t, time.Date(2025, time.January, 15, 22, 0, 0, 0, time.UTC), next(t, "0 22 * * *", now))
+	})
+
+	t.Run("next day", func(t *testing.T) {
+		assert.Equal(t, time.Date(2025, time.January, 16, 2, 0, 0, 0, time.UTC), next(t, "0 2 * * *", now))
+	})
+
+	t.Run("weekend", func(t *testing.T) {
+		assert.Equal(t, time.Date(2025, time.January, 18, 0, 0, 0, 0, time.UTC), next(t, "0 0 * * 6,7", now))
+		assert.Equal(t, time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC), next(t, "0 0 * * 7", time.Date(2025, time.January, 18, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("day of month or day of week", func(t *testing.T) {
+		assert.Equal(t, time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC), next(t, "0 0 20 * 5", now))
+	})
+
+	t.Run("keeps the location", func(t *testing.T) {
+		location, err := time.LoadLocation("Europe/Vienna")
+		require.NoError(t, err)
+
+		result := next(t, "0 22 * * *", now.In(location))
+		assert.Equal(t, time.Date(2025, time.January, 15, 22, 0, 0, 0, location), result)
+	})
+
+	t.Run("impossible date", func(t *testing.T) {
+		assert.True(t, next(t, "0 0 31 2 *", now).IsZero())
+	})
+}
@@ -0,0 +1,102 @@
+package installconfig
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+)
+
+const (
+	CertificateSourceJsonEnv = "certificate-source.json"
+
+	SelfSignedCertificateSource  CertificateSourceType = "SelfSigned"
+	CASecretCertificateSource    CertificateSourceType = "CASecret"
+	CertManagerCertificateSource CertificateSourceType = "CertManager"
+
+	IssuerKind        = "Issuer"
+	ClusterIssuerKind = "ClusterIssuer"
+)
+
+var (
+	certificateSourceOnce sync.Once
+
+	certificateSource = CertificateSource{Type: SelfSignedCertificateSource}
+
+	// needed for testing
+	certificateSourceOverride *CertificateSource
+)
+
+type CertificateSourceType string
+
+// CertificateSource configures where the TLS certificates of the webhook and the operator-managed components come from.
+type CertificateSource struct {
+	// Type of the source, the built-in self-signed CA is used if empty.
+	Type CertificateSourceType `json:"type"`
+
+	// CASecret is the name of a secret in the operator namespace with the CA certificate (tls.crt) and key (tls.key)
+	// the certificates are signed with. An optional ca.crt is used as trust bundle instead of tls.crt.
+	CASecret string `json:"caSecret,omitempty"`
+
+	// Issuer is the cert-manager issuer referenced by the created Certificate objects.
+	Issuer *IssuerRef `json:"issuer,omitempty"`
+}
+
+type IssuerRef struct {
+	Name string `json:"name"`
+
+	// Kind is either Issuer or ClusterIssuer, Issuer is used if empty.
+	Kind string `json:"kind,omitempty"`
+
+	// Group of external issuers, cert-manager.io is used if empty.
+	Group string `json:"group,omitempty"`
+}
+
+// IsSelfSigned tells whether the certificates are signed by the built-in self-signed CA of the operator.
+func (source CertificateSource) IsSelfSigned() bool {
+	return source.Type == "" || source.Type == SelfSignedCertificateSource
+}
+
+// GetCertificateSource returns the certificate source configured during install, the built-in self-signed CA if none is set.
+// An invalid configuration is not silently replaced by the self-signed CA, as that would violate the PKI policy it was meant to enforce,
+// it is returned as is and rejected when the certificates are issued.
+func GetCertificateSource() CertificateSource {
+	if certificateSourceOverride != nil {
+		return *certificateSourceOverride
+	}
+
+	certificateSourceOnce.Do(func() {
+		certificateSourceJson := os.Getenv(CertificateSourceJsonEnv)
+		if certificateSourceJson == "" {
+			return
+		}
+
+		var source CertificateSource
+
+		err := json.Unmarshal([]byte(certificateSourceJson), &source)
+		if err != nil {
+			log.Info("problem unmarshalling envvar content, certificates can't be issued", "envvar", CertificateSourceJsonEnv, "err", err)
+
+			certificateSource = CertificateSource{Type: "invalid"}
+
+			return
+		}
+
+		log.Info("envvar content read and set", "envvar", CertificateSourceJsonEnv, "value", certificateSourceJson)
+
+		certificateSource = source
+	})
+
+	return certificateSource
+}
+
+// SetCertificateSourceOverride is a testing function, so you can easily unittest functions using the GetCertificateSource() func
+func SetCertificateSourceOverride(t *testing.T, source CertificateSource) {
+	t.Helper()
+
+	certificateSourceOverride = &source
+
+	t.Cleanup(func() {
+		certificateSourceOverride = nil
+	})
+}
@@ -0,0 +1,67 @@
+package installconfig
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/shared/registrymirror"
+)
+
+const (
+	RegistryMirrorJsonEnv = "registry-mirror.json"
+)
+
+var (
+	registryMirrorOnce sync.Once
+
+	registryMirror *registrymirror.Spec
+
+	// needed for testing
+	registryMirrorOverride *registrymirror.Spec
+)
+
+// GetRegistryMirror returns the operator-wide registry mirror configured during install, or nil if none is set.
+func GetRegistryMirror() *registrymirror.Spec {
+	if registryMirrorOverride != nil {
+		return registryMirrorOverride
+	}
+
+	registryMirrorOnce.Do(func() {
+		registryMirrorJson := os.Getenv(RegistryMirrorJsonEnv)
+		if registryMirrorJson == "" {
+			return
+		}
+
+		var spec registrymirror.Spec
+
+		err := json.Unmarshal([]byte(registryMirrorJson), &spec)
+		if err != nil {
+			log.Info("problem unmarshalling envvar content, no registry mirror is used", "envvar", RegistryMirrorJsonEnv, "err", err)
+
+			return
+		}
+
+		if len(spec.Rules) == 0 {
+			return
+		}
+
+		log.Info("envvar content read and set", "envvar", RegistryMirrorJsonEnv, "value", registryMirrorJson)
+
+		registryMirror = &spec
+	})
+
+	return registryMirror
+}
+
+// SetRegistryMirrorOverride is a testing function, so you can easily unittest functions using the GetRegistryMirror() func
+func SetRegistryMirrorOverride(t *testing.T, spec registrymirror.Spec) {
+	t.Helper()
+
+	registryMirrorOverride = &spec
+
+	t.Cleanup(func() {
+		registryMirrorOverride = nil
+	})
+}
@@ -0,0 +1,91 @@
+package installconfig
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/tokensource"
+)
+
+const (
+	TokenSourceJsonEnv = "token-source.json"
+)
+
+var (
+	tokenSourceOnce sync.Once
+
+	tokenSource TokenSource
+
+	// needed for testing
+	tokenSourceOverride *TokenSource
+)
+
+// TokenSource pins the endpoints the token sources of the DynaKubes talk to during install,
+// so a DynaKube can't make the operator or webhook send the service account token, or read secrets, from a host of its choice.
+type TokenSource struct {
+	// VaultAddress of the Vault Agent running next to the operator and webhook, vault sources are rejected if it is empty.
+	VaultAddress string `json:"vaultAddress,omitempty"`
+
+	// OAuthTokenURLs are token endpoints allowed in addition to the Dynatrace SSO.
+	OAuthTokenURLs []string `json:"oauthTokenUrls,omitempty"`
+}
+
+// IsAllowedOAuthTokenURL checks if the token endpoint is on the host of the Dynatrace SSO, or one of the endpoints allowed during install.
+func (source TokenSource) IsAllowedOAuthTokenURL(tokenURL string) bool {
+	if slices.Contains(source.OAuthTokenURLs, tokenURL) {
+		return true
+	}
+
+	parsed, err := url.Parse(tokenURL)
+	if err != nil {
+		return false
+	}
+
+	ssoURL, _ := url.Parse(tokensource.DefaultOAuthTokenURL)
+
+	return parsed.Scheme == ssoURL.Scheme && parsed.Host == ssoURL.Host && parsed.User == nil
+}
+
+// GetTokenSource returns the token source endpoints configured during install.
+func GetTokenSource() TokenSource {
+	if tokenSourceOverride != nil {
+		return *tokenSourceOverride
+	}
+
+	tokenSourceOnce.Do(func() {
+		tokenSourceJson := os.Getenv(TokenSourceJsonEnv)
+		if tokenSourceJson == "" {
+			return
+		}
+
+		var source TokenSource
+
+		err := json.Unmarshal([]byte(tokenSourceJson), &source)
+		if err != nil {
+			log.Info("problem unmarshalling envvar content, only the Dynatrace SSO is allowed", "envvar", TokenSourceJsonEnv, "err", err)
+
+			return
+		}
+
+		log.Info("envvar content read and set", "envvar", TokenSourceJsonEnv, "value", tokenSourceJson)
+
+		tokenSource = source
+	})
+
+	return tokenSource
+}
+
+// SetTokenSourceOverride is a testing function, so you can easily unittest functions using the GetTokenSource() func
+func SetTokenSourceOverride(t *testing.T, source TokenSource) {
+	t.Helper()
+
+	tokenSourceOverride = &source
+
+	t.Cleanup(func() {
+		tokenSourceOverride = nil
+	})
+}
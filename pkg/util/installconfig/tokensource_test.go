@@ -0,0 +1,20 @@
+package installconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAllowedOAuthTokenURL(t *testing.T) {
+	source := TokenSource{OAuthTokenURLs: []string{"https://sso-dev.dynatracelabs.com/sso/oauth2/token"}}
+
+	assert.True(t, source.IsAllowedOAuthTokenURL("https://sso.dynatrace.com/sso/oauth2/token"))
+	assert.True(t, source.IsAllowedOAuthTokenURL("https://sso-dev.dynatracelabs.com/sso/oauth2/token"))
+
+	assert.False(t, source.IsAllowedOAuthTokenURL("http://sso.dynatrace.com/sso/oauth2/token"))
+	assert.False(t, source.IsAllowedOAuthTokenURL("https://sso.dynatrace.com.attacker.com/token"))
+	assert.False(t, source.IsAllowedOAuthTokenURL("https://user@sso.dynatrace.com/sso/oauth2/token"))
+	assert.False(t, source.IsAllowedOAuthTokenURL("https://sso-dev.dynatracelabs.com/other"))
+	assert.False(t, TokenSource{}.IsAllowedOAuthTokenURL("https://sso-dev.dynatracelabs.com/sso/oauth2/token"))
+}
@@ -4,6 +4,9 @@ const (
 	// InjectionInstanceLabel can be set in a Namespace and indicates the corresponding DynaKube object assigned to it.
 	InjectionInstanceLabel = "dynakube.internal.dynatrace.com/instance"
 
+	// InjectedPodLabel is set by the webhook to Pods the OneAgent was injected into, the value is the name of the corresponding DynaKube.
+	InjectedPodLabel = "dynakube.internal.dynatrace.com/oneagent-injected"
+
 	// AnnotationFailurePolicy can be set on a Pod to control what the init container does on failures. When set to
 	// "fail", the init container will exit with error code 1. Defaults to "silent".
 	AnnotationFailurePolicy = "oneagent.dynatrace.com/failure-policy"
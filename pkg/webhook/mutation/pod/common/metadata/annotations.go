@@ -5,6 +5,7 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func CopyMetadataFromNamespace(pod *corev1.Pod, namespace corev1.Namespace, dk dynakube.DynaKube) {
@@ -21,6 +22,33 @@ func copyAccordingToPrefix(pod *corev1.Pod, namespace corev1.Namespace) {
 }
 
 func copyAccordingToCustomRules(pod *corev1.Pod, namespace corev1.Namespace, dk dynakube.DynaKube) {
+	copyAccordingToScopedRules(pod, dk, func(scope dynakube.EnrichmentRuleScope) (metav1.ObjectMeta, bool) {
+		return namespace.ObjectMeta, scope == dynakube.EnrichmentNamespaceScope
+	})
+}
+
+// CopyMetadataFromRuleSources applies the rules, that don't read from the namespace, to the pod.
+func CopyMetadataFromRuleSources(pod *corev1.Pod, sources RuleSources, dk dynakube.DynaKube) {
+	copyAccordingToScopedRules(pod, dk, func(scope dynakube.EnrichmentRuleScope) (metav1.ObjectMeta, bool) {
+		switch scope {
+		case dynakube.EnrichmentPodScope:
+			return pod.ObjectMeta, true
+		case dynakube.EnrichmentWorkloadScope:
+			if sources.Workload == nil {
+				return metav1.ObjectMeta{}, false
+			}
+
+			return metav1.ObjectMeta{Labels: sources.Workload.Labels, Annotations: sources.Workload.Annotations}, true
+		case dynakube.EnrichmentNodeScope:
+			return sources.Node, true
+		default:
+			return metav1.ObjectMeta{}, false
+		}
+	})
+}
+
+// copyAccordingToScopedRules applies the rules, whose source object is returned by getSource.
+func copyAccordingToScopedRules(pod *corev1.Pod, dk dynakube.DynaKube, getSource func(scope dynakube.EnrichmentRuleScope) (metav1.ObjectMeta, bool)) {
 	for _, rule := range dk.Status.MetadataEnrichment.Rules {
 		if rule.Target == "" {
 			log.Info("rule without target set found, ignoring", "source", rule.Source, "type", rule.Type)
@@ -28,19 +56,24 @@ func copyAccordingToCustomRules(pod *corev1.Pod, namespace corev1.Namespace, dk
 			continue
 		}
 
-		var valueFromNamespace string
+		source, ok := getSource(rule.GetScope())
+		if !ok {
+			continue
+		}
+
+		var value string
 
 		var exists bool
 
 		switch rule.Type {
 		case dynakube.EnrichmentLabelRule:
-			valueFromNamespace, exists = namespace.Labels[rule.Source]
+			value, exists = source.Labels[rule.Source]
 		case dynakube.EnrichmentAnnotationRule:
-			valueFromNamespace, exists = namespace.Annotations[rule.Source]
+			value, exists = source.Annotations[rule.Source]
 		}
 
 		if exists {
-			setPodAnnotationIfNotExists(pod, rule.ToAnnotationKey(), valueFromNamespace)
+			setPodAnnotationIfNotExists(pod, rule.ToAnnotationKey(), value)
 		}
 	}
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -117,6 +118,45 @@ func TestCopyMetadataFromNamespace(t *testing.T) {
 	})
 }
 
+func TestCopyMetadataFromRuleSources(t *testing.T) {
+	request := createTestMutationRequest(nil, nil)
+	request.Namespace.Labels = map[string]string{"team": "namespace-team"}
+	request.Pod.Labels = map[string]string{"version": "1.2.3"}
+	request.DynaKube.Status.MetadataEnrichment.Rules = []dynakube.EnrichmentRule{
+		{Type: dynakube.EnrichmentLabelRule, Source: "team", Target: "namespace.team"},
+		{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentPodScope, Source: "version", Target: "version"},
+		{Type: dynakube.EnrichmentAnnotationRule, Scope: dynakube.EnrichmentWorkloadScope, Source: "owner", Target: "workload.owner"},
+		{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentNodeScope, Source: "topology.kubernetes.io/zone", Target: "zone"},
+		{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentNodeScope, Source: "node.kubernetes.io/instance-type", Target: "instance.type"},
+	}
+
+	sources := RuleSources{
+		Workload: &WorkloadInfo{Annotations: map[string]string{"owner": "payments"}},
+		Node:     metav1.ObjectMeta{Labels: map[string]string{"topology.kubernetes.io/zone": "eu-west-1a"}},
+	}
+
+	CopyMetadataFromRuleSources(request.Pod, sources, request.DynaKube)
+
+	assert.Equal(t, map[string]string{
+		dynakube.MetadataPrefix + "version":        "1.2.3",
+		dynakube.MetadataPrefix + "workload.owner": "payments",
+		dynakube.MetadataPrefix + "zone":           "eu-west-1a",
+	}, request.Pod.Annotations)
+
+	t.Run("namespace rules only read from the namespace", func(t *testing.T) {
+		request := createTestMutationRequest(nil, nil)
+		request.Namespace.Labels = map[string]string{"version": "namespace-version"}
+		request.Pod.Labels = map[string]string{"version": "1.2.3"}
+		request.DynaKube.Status.MetadataEnrichment.Rules = []dynakube.EnrichmentRule{
+			{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentPodScope, Source: "version", Target: "version"},
+		}
+
+		CopyMetadataFromNamespace(request.Pod, request.Namespace, request.DynaKube)
+
+		assert.Empty(t, request.Pod.Annotations)
+	})
+}
+
 func createTestMutationRequest(dk *dynakube.DynaKube, annotations map[string]string) *dtwebhook.MutationRequest {
 	if dk == nil {
 		dk = &dynakube.DynaKube{}
@@ -1,19 +1,40 @@
 package metadata
 
-import "github.com/Dynatrace/dynatrace-operator/pkg/logd"
+import (
+	"github.com/Dynatrace/dynatrace-operator/pkg/logd"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
 
 const (
 	AnnotationPrefix = "metadata-enrichment"
 	// AnnotationMetadataEnrichmentInject can be set at pod level to enable/disable metadata-enrichment injection.
 	AnnotationInject   = AnnotationPrefix + ".dynatrace.com/inject"
 	AnnotationInjected = AnnotationPrefix + ".dynatrace.com/injected"
+	AnnotationReason   = AnnotationPrefix + ".dynatrace.com/reason"
 
 	// AnnotationWorkloadKind is added to any injected pods when the metadata-enrichment feature is enabled
 	AnnotationWorkloadKind = "metadata.dynatrace.com/k8s.workload.kind"
 	// AnnotationWorkloadName is added to any injected pods when the metadata-enrichment feature is enabled
 	AnnotationWorkloadName = "metadata.dynatrace.com/k8s.workload.name"
+
+	LatencyBudgetExceededReason = "LatencyBudgetExceeded"
+
+	ownerCacheHit  = "hit"
+	ownerCacheMiss = "miss"
 )
 
 var (
 	log = logd.Get().WithName("metadata-enrichment-pod-common")
+
+	ownerCacheLookupsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "webhook",
+		Name:      "workload_owner_cache_lookups_total",
+		Help:      "Lookups of the workload owner cache, by kind of the looked up object and result (hit or miss)",
+	}, []string{"kind", "result"})
 )
+
+func init() {
+	metrics.Registry.MustRegister(ownerCacheLookupsMetric)
+}
@@ -36,6 +36,15 @@ func SetInjectedAnnotation(pod *corev1.Pod) {
 	pod.Annotations[AnnotationInjected] = "true"
 }
 
+func SetNotInjectedAnnotations(pod *corev1.Pod, reason string) {
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+
+	pod.Annotations[AnnotationInjected] = "false"
+	pod.Annotations[AnnotationReason] = reason
+}
+
 func SetWorkloadAnnotations(pod *corev1.Pod, workload *WorkloadInfo) {
 	if pod.Annotations == nil {
 		pod.Annotations = make(map[string]string)
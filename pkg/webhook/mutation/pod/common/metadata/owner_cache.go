@@ -0,0 +1,251 @@
+package metadata
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cachedOwnerKinds are the intermediate workloads, whose owners rarely change, but are looked up for almost every pod.
+var cachedOwnerKinds = []metav1.TypeMeta{
+	{Kind: "ReplicaSet", APIVersion: "apps/v1"},
+	{Kind: "Job", APIVersion: "batch/v1"},
+}
+
+type ownerCacheKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+type ownerCacheEntry struct {
+	expiresAt  time.Time
+	objectMeta metav1.ObjectMeta
+}
+
+// OwnerCache keeps the owner references, labels and annotations of ReplicaSets and Jobs, so the root owner of a pod can be found without querying the Kubernetes API.
+// Entries are added on lookup and expire after the TTL, the informers registered via Watch keep them up to date in the meantime.
+type OwnerCache struct {
+	now     func() time.Time
+	entries map[ownerCacheKey]ownerCacheEntry
+	synced  map[string]toolscache.InformerSynced
+	ttl     time.Duration
+	mutex   sync.RWMutex
+}
+
+func NewOwnerCache(ttl time.Duration) *OwnerCache {
+	return &OwnerCache{
+		now:     time.Now,
+		entries: map[ownerCacheKey]ownerCacheEntry{},
+		synced:  map[string]toolscache.InformerSynced{},
+		ttl:     ttl,
+	}
+}
+
+// Watch registers the EventHandler on the informer of the given kind, once the informer is synced, misses are served from it instead of the Kubernetes API.
+func (cache *OwnerCache) Watch(kind string, informer ctrlcache.Informer) error {
+	_, err := informer.AddEventHandler(cache.EventHandler(kind))
+	if err != nil {
+		return err
+	}
+
+	cache.synced[kind] = informer.HasSynced
+
+	return nil
+}
+
+func (cache *OwnerCache) isSynced(kind string) bool {
+	hasSynced, ok := cache.synced[kind]
+
+	return ok && hasSynced()
+}
+
+// CachedOwnerKinds returns the objects the informers of the OwnerCache have to be created for.
+func CachedOwnerKinds() []client.Object {
+	objects := make([]client.Object, 0, len(cachedOwnerKinds))
+	for _, typeMeta := range cachedOwnerKinds {
+		objects = append(objects, &metav1.PartialObjectMetadata{TypeMeta: typeMeta})
+	}
+
+	return objects
+}
+
+func isCachedOwnerKind(typeMeta metav1.TypeMeta) bool {
+	return slices.Contains(cachedOwnerKinds, typeMeta)
+}
+
+func (cache *OwnerCache) get(key ownerCacheKey) (metav1.ObjectMeta, bool) {
+	cache.mutex.RLock()
+	entry, ok := cache.entries[key]
+	cache.mutex.RUnlock()
+
+	if !ok || cache.now().After(entry.expiresAt) {
+		return metav1.ObjectMeta{}, false
+	}
+
+	return *entry.objectMeta.DeepCopy(), true
+}
+
+func (cache *OwnerCache) set(key ownerCacheKey, object metav1.Object) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries[key] = ownerCacheEntry{
+		expiresAt:  cache.now().Add(cache.ttl),
+		objectMeta: toCachedObjectMeta(key, object),
+	}
+}
+
+// update replaces the metadata of an entry, without adding new entries or extending the expiry.
+func (cache *OwnerCache) update(key ownerCacheKey, object metav1.Object) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok {
+		return
+	}
+
+	entry.objectMeta = toCachedObjectMeta(key, object)
+	cache.entries[key] = entry
+}
+
+// toCachedObjectMeta keeps what's needed to resolve the root owner of a pod and to enrich it with the labels/annotations of its workload.
+func toCachedObjectMeta(key ownerCacheKey, object metav1.Object) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            key.name,
+		Namespace:       key.namespace,
+		Labels:          maps.Clone(object.GetLabels()),
+		Annotations:     maps.Clone(object.GetAnnotations()),
+		OwnerReferences: slices.Clone(object.GetOwnerReferences()),
+	}
+}
+
+func (cache *OwnerCache) delete(key ownerCacheKey) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	delete(cache.entries, key)
+}
+
+func (cache *OwnerCache) prune() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	now := cache.now()
+	for key, entry := range cache.entries {
+		if now.After(entry.expiresAt) {
+			delete(cache.entries, key)
+		}
+	}
+}
+
+// Start removes the expired entries once per TTL, so the cache doesn't grow with objects that are never looked up again.
+func (cache *OwnerCache) Start(ctx context.Context) error {
+	ticker := time.NewTicker(cache.ttl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			cache.prune()
+		}
+	}
+}
+
+// NeedLeaderElection is false, every webhook replica needs its own cache.
+func (cache *OwnerCache) NeedLeaderElection() bool {
+	return false
+}
+
+// EventHandler returns the handler for the informer of the given kind, that propagates metadata changes and deletions to the cached entries.
+func (cache *OwnerCache) EventHandler(kind string) toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, newObj any) {
+			if object, err := meta.Accessor(newObj); err == nil {
+				cache.update(ownerCacheKey{kind: kind, namespace: object.GetNamespace(), name: object.GetName()}, object)
+			}
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			if object, err := meta.Accessor(obj); err == nil {
+				cache.delete(ownerCacheKey{kind: kind, namespace: object.GetNamespace(), name: object.GetName()})
+			}
+		},
+	}
+}
+
+// ownerCachingClient answers the metadata lookups of the cached kinds from the OwnerCache.
+// On a miss, it falls back to the synced informers and then to the wrapped client.
+type ownerCachingClient struct {
+	client.Client
+
+	informers client.Reader
+	cache     *OwnerCache
+}
+
+func NewOwnerCachingClient(clt client.Client, cache *OwnerCache, informers client.Reader) client.Client {
+	return &ownerCachingClient{
+		Client:    clt,
+		informers: informers,
+		cache:     cache,
+	}
+}
+
+func (clt *ownerCachingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	partialMetadata, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok || !isCachedOwnerKind(partialMetadata.TypeMeta) {
+		return clt.Client.Get(ctx, key, obj, opts...)
+	}
+
+	typeMeta := partialMetadata.TypeMeta
+	cacheKey := ownerCacheKey{kind: typeMeta.Kind, namespace: key.Namespace, name: key.Name}
+
+	if objectMeta, ok := clt.cache.get(cacheKey); ok {
+		ownerCacheLookupsMetric.WithLabelValues(typeMeta.Kind, ownerCacheHit).Inc()
+
+		partialMetadata.ObjectMeta = objectMeta
+
+		return nil
+	}
+
+	if clt.informers != nil && clt.cache.isSynced(typeMeta.Kind) {
+		// objects created moments ago might not have reached the informer yet, those are looked up live
+		if err := clt.informers.Get(ctx, key, obj, opts...); err == nil {
+			ownerCacheLookupsMetric.WithLabelValues(typeMeta.Kind, ownerCacheHit).Inc()
+			clt.store(cacheKey, typeMeta, partialMetadata)
+
+			return nil
+		}
+	}
+
+	ownerCacheLookupsMetric.WithLabelValues(typeMeta.Kind, ownerCacheMiss).Inc()
+
+	err := clt.Client.Get(ctx, key, obj, opts...)
+	if err != nil {
+		return err
+	}
+
+	clt.store(cacheKey, typeMeta, partialMetadata)
+
+	return nil
+}
+
+func (clt *ownerCachingClient) store(cacheKey ownerCacheKey, typeMeta metav1.TypeMeta, partialMetadata *metav1.PartialObjectMetadata) {
+	// the type meta is needed to resolve the workload kind, but might be cleared by the client
+	partialMetadata.TypeMeta = typeMeta
+	clt.cache.set(cacheKey, partialMetadata)
+}
@@ -0,0 +1,229 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	testNamespace      = "test-namespace"
+	testReplicaSetName = "test-replicaset"
+	testDeploymentName = "test-deployment"
+)
+
+func TestOwnerCachingClient(t *testing.T) {
+	ctx := context.Background()
+	replicaSetKey := client.ObjectKey{Name: testReplicaSetName, Namespace: testNamespace}
+
+	t.Run("serve repeated lookups from the cache", func(t *testing.T) {
+		apiCalls := 0
+		clt := NewOwnerCachingClient(createCountingClient(&apiCalls, createReplicaSet(testDeploymentName)), NewOwnerCache(time.Minute), nil)
+
+		for range 3 {
+			replicaSet := newPartialReplicaSet()
+			require.NoError(t, clt.Get(ctx, replicaSetKey, replicaSet))
+
+			assert.Equal(t, "ReplicaSet", replicaSet.Kind)
+			assert.Equal(t, testReplicaSetName, replicaSet.Name)
+			require.Len(t, replicaSet.OwnerReferences, 1)
+			assert.Equal(t, testDeploymentName, replicaSet.OwnerReferences[0].Name)
+		}
+
+		assert.Equal(t, 1, apiCalls)
+	})
+
+	t.Run("look up expired entries again", func(t *testing.T) {
+		apiCalls := 0
+		ownerCache := NewOwnerCache(time.Minute)
+		now := time.Now()
+		ownerCache.now = func() time.Time { return now }
+		clt := NewOwnerCachingClient(createCountingClient(&apiCalls, createReplicaSet(testDeploymentName)), ownerCache, nil)
+
+		require.NoError(t, clt.Get(ctx, replicaSetKey, newPartialReplicaSet()))
+
+		now = now.Add(2 * time.Minute)
+
+		require.NoError(t, clt.Get(ctx, replicaSetKey, newPartialReplicaSet()))
+		assert.Equal(t, 2, apiCalls)
+	})
+
+	t.Run("don't cache kinds other than ReplicaSets and Jobs", func(t *testing.T) {
+		apiCalls := 0
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: testDeploymentName, Namespace: testNamespace}}
+		clt := NewOwnerCachingClient(createCountingClient(&apiCalls, deployment), NewOwnerCache(time.Minute), nil)
+
+		for range 2 {
+			partialDeployment := &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}}
+			require.NoError(t, clt.Get(ctx, client.ObjectKeyFromObject(deployment), partialDeployment))
+		}
+
+		assert.Equal(t, 2, apiCalls)
+	})
+
+	t.Run("serve misses from synced informers", func(t *testing.T) {
+		apiCalls := 0
+		ownerCache := NewOwnerCache(time.Minute)
+		ownerCache.synced["ReplicaSet"] = func() bool { return true }
+		clt := NewOwnerCachingClient(createCountingClient(&apiCalls), ownerCache, fake.NewClient(createReplicaSet(testDeploymentName)))
+
+		replicaSet := newPartialReplicaSet()
+		require.NoError(t, clt.Get(ctx, replicaSetKey, replicaSet))
+		assert.Equal(t, testDeploymentName, replicaSet.OwnerReferences[0].Name)
+		assert.Equal(t, 0, apiCalls)
+
+		_, ok := ownerCache.get(ownerCacheKey{kind: "ReplicaSet", namespace: testNamespace, name: testReplicaSetName})
+		assert.True(t, ok)
+	})
+
+	t.Run("skip informers that are not synced", func(t *testing.T) {
+		apiCalls := 0
+		ownerCache := NewOwnerCache(time.Minute)
+		ownerCache.synced["ReplicaSet"] = func() bool { return false }
+		clt := NewOwnerCachingClient(createCountingClient(&apiCalls, createReplicaSet(testDeploymentName)), ownerCache, fake.NewClient())
+
+		require.NoError(t, clt.Get(ctx, replicaSetKey, newPartialReplicaSet()))
+		assert.Equal(t, 1, apiCalls)
+	})
+
+	t.Run("fall back to live lookups if the informer misses the object", func(t *testing.T) {
+		apiCalls := 0
+		ownerCache := NewOwnerCache(time.Minute)
+		ownerCache.synced["ReplicaSet"] = func() bool { return true }
+		clt := NewOwnerCachingClient(createCountingClient(&apiCalls, createReplicaSet(testDeploymentName)), ownerCache, fake.NewClient())
+
+		require.NoError(t, clt.Get(ctx, replicaSetKey, newPartialReplicaSet()))
+		assert.Equal(t, 1, apiCalls)
+	})
+
+	t.Run("don't cache failed lookups", func(t *testing.T) {
+		apiCalls := 0
+		clt := NewOwnerCachingClient(createCountingClient(&apiCalls), NewOwnerCache(time.Minute), nil)
+
+		require.Error(t, clt.Get(ctx, replicaSetKey, newPartialReplicaSet()))
+		require.Error(t, clt.Get(ctx, replicaSetKey, newPartialReplicaSet()))
+		assert.Equal(t, 2, apiCalls)
+	})
+}
+
+func TestOwnerCacheEventHandler(t *testing.T) {
+	key := ownerCacheKey{kind: "ReplicaSet", namespace: testNamespace, name: testReplicaSetName}
+
+	t.Run("update owners of cached entries", func(t *testing.T) {
+		ownerCache := NewOwnerCache(time.Minute)
+		ownerCache.set(key, createReplicaSet(testDeploymentName))
+
+		handler := ownerCache.EventHandler("ReplicaSet")
+		handler.OnUpdate(createReplicaSet(testDeploymentName), createReplicaSet("adopter"))
+
+		objectMeta, ok := ownerCache.get(key)
+		require.True(t, ok)
+		assert.Equal(t, "adopter", objectMeta.OwnerReferences[0].Name)
+	})
+
+	t.Run("don't add entries on update", func(t *testing.T) {
+		ownerCache := NewOwnerCache(time.Minute)
+
+		handler := ownerCache.EventHandler("ReplicaSet")
+		handler.OnUpdate(createReplicaSet(testDeploymentName), createReplicaSet(testDeploymentName))
+
+		_, ok := ownerCache.get(key)
+		assert.False(t, ok)
+	})
+
+	t.Run("remove deleted entries", func(t *testing.T) {
+		ownerCache := NewOwnerCache(time.Minute)
+		ownerCache.set(key, &metav1.ObjectMeta{})
+
+		handler := ownerCache.EventHandler("ReplicaSet")
+		handler.OnDelete(toolscache.DeletedFinalStateUnknown{Obj: createReplicaSet(testDeploymentName)})
+
+		_, ok := ownerCache.get(key)
+		assert.False(t, ok)
+	})
+}
+
+func TestOwnerCachePrune(t *testing.T) {
+	ownerCache := NewOwnerCache(time.Minute)
+	now := time.Now()
+	ownerCache.now = func() time.Time { return now }
+
+	ownerCache.set(ownerCacheKey{kind: "Job", name: "old"}, &metav1.ObjectMeta{})
+
+	now = now.Add(30 * time.Second)
+	ownerCache.set(ownerCacheKey{kind: "Job", name: "new"}, &metav1.ObjectMeta{})
+
+	now = now.Add(45 * time.Second)
+	ownerCache.prune()
+
+	assert.Len(t, ownerCache.entries, 1)
+	assert.Contains(t, ownerCache.entries, ownerCacheKey{kind: "Job", name: "new"})
+}
+
+func TestRetrieveWorkloadWithOwnerCache(t *testing.T) {
+	apiCalls := 0
+	clt := NewOwnerCachingClient(createCountingClient(&apiCalls,
+		createReplicaSet(testDeploymentName),
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: testDeploymentName, Namespace: testNamespace}},
+	), NewOwnerCache(time.Minute), nil)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-pod",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: testReplicaSetName, Controller: ptr.To(true)},
+			},
+		},
+	}
+
+	for range 2 {
+		workload, err := findRootOwnerOfPod(context.Background(), clt, pod, testNamespace)
+		require.NoError(t, err)
+		assert.Equal(t, &WorkloadInfo{Name: testDeploymentName, Kind: "deployment"}, workload)
+	}
+
+	// the ReplicaSet is looked up once, the Deployment is not cached
+	assert.Equal(t, 3, apiCalls)
+}
+
+func newPartialReplicaSet() *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{Kind: "ReplicaSet", APIVersion: "apps/v1"}}
+}
+
+func createReplicaSet(ownerName string) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testReplicaSetName,
+			Namespace: testNamespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: ownerName, Controller: ptr.To(true)},
+			},
+		},
+	}
+}
+
+type countingClient struct {
+	client.Client
+
+	apiCalls *int
+}
+
+func (clt countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	*clt.apiCalls++
+
+	return clt.Client.Get(ctx, key, obj, opts...)
+}
+
+func createCountingClient(apiCalls *int, objs ...client.Object) client.Client {
+	return countingClient{Client: fake.NewClient(objs...), apiCalls: apiCalls}
+}
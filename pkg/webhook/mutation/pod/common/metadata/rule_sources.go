@@ -0,0 +1,85 @@
+package metadata
+
+import (
+	"slices"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const nodeNameField = "metadata.name"
+
+// RuleSources are the objects the enrichment rules with a Workload or Node scope read from.
+type RuleSources struct {
+	Workload *WorkloadInfo
+	Node     metav1.ObjectMeta
+}
+
+// RetrieveRuleSources collects the objects needed by the enrichment rules of the DynaKube.
+// The node is only known at admission if the pod is bound to it (e.g. DaemonSet pods), otherwise the labels of the nodeSelector of the pod are used.
+// Failing to get the node doesn't fail the injection, the nodeSelector is used instead.
+func RetrieveRuleSources(metaClient client.Client, request *dtwebhook.MutationRequest, workload *WorkloadInfo) RuleSources {
+	sources := RuleSources{Workload: workload}
+
+	if !hasNodeScopedRules(request.DynaKube) {
+		return sources
+	}
+
+	sources.Node = metav1.ObjectMeta{Labels: request.Pod.Spec.NodeSelector}
+
+	nodeName := getNodeName(request.Pod)
+	if nodeName == "" {
+		return sources
+	}
+
+	ctx, cancel := withLatencyBudgetDeadline(request.Context)
+	defer cancel()
+
+	node := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Node",
+		},
+	}
+
+	err := metaClient.Get(ctx, client.ObjectKey{Name: nodeName}, node)
+	if err != nil {
+		log.Info("failed to get the node of the pod, using its nodeSelector for the enrichment rules", "podName", request.PodName(), "node", nodeName, "error", err.Error())
+
+		return sources
+	}
+
+	sources.Node = node.ObjectMeta
+
+	return sources
+}
+
+func hasNodeScopedRules(dk dynakube.DynaKube) bool {
+	return slices.ContainsFunc(dk.Status.MetadataEnrichment.Rules, func(rule dynakube.EnrichmentRule) bool {
+		return rule.GetScope() == dynakube.EnrichmentNodeScope
+	})
+}
+
+// getNodeName returns the node the pod is scheduled to, either set directly or via the node affinity the DaemonSet controller adds to its pods.
+func getNodeName(pod *corev1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
+	}
+
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil || pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return ""
+	}
+
+	for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, field := range term.MatchFields {
+			if field.Key == nodeNameField && field.Operator == corev1.NodeSelectorOpIn && len(field.Values) == 1 {
+				return field.Values[0]
+			}
+		}
+	}
+
+	return ""
+}
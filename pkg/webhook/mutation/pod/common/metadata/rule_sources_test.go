@@ -0,0 +1,87 @@
+package metadata
+
+import (
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRetrieveRuleSources(t *testing.T) {
+	const nodeName = "test-node"
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   nodeName,
+			Labels: map[string]string{"topology.kubernetes.io/zone": "eu-west-1a"},
+		},
+	}
+	nodeRule := dynakube.EnrichmentRule{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentNodeScope, Source: "topology.kubernetes.io/zone", Target: "zone"}
+	workload := &WorkloadInfo{Name: "test", Kind: "deployment"}
+
+	t.Run("don't query the node without node rules", func(t *testing.T) {
+		request := createTestMutationRequest(nil, nil)
+		request.Pod.Spec.NodeName = nodeName
+
+		sources := RetrieveRuleSources(fake.NewClient(node), request, workload)
+
+		assert.Equal(t, workload, sources.Workload)
+		assert.Empty(t, sources.Node)
+	})
+
+	t.Run("use the node the pod is bound to", func(t *testing.T) {
+		request := createTestMutationRequest(nil, nil)
+		request.DynaKube.Status.MetadataEnrichment.Rules = []dynakube.EnrichmentRule{nodeRule}
+		request.Pod.Spec.NodeName = nodeName
+
+		sources := RetrieveRuleSources(fake.NewClient(node), request, workload)
+
+		assert.Equal(t, "eu-west-1a", sources.Node.Labels["topology.kubernetes.io/zone"])
+	})
+
+	t.Run("use the node of the daemonset node affinity", func(t *testing.T) {
+		request := createTestMutationRequest(nil, nil)
+		request.DynaKube.Status.MetadataEnrichment.Rules = []dynakube.EnrichmentRule{nodeRule}
+		request.Pod.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchFields: []corev1.NodeSelectorRequirement{
+								{Key: nodeNameField, Operator: corev1.NodeSelectorOpIn, Values: []string{nodeName}},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		sources := RetrieveRuleSources(fake.NewClient(node), request, workload)
+
+		assert.Equal(t, "eu-west-1a", sources.Node.Labels["topology.kubernetes.io/zone"])
+	})
+
+	t.Run("fall back to the nodeSelector", func(t *testing.T) {
+		request := createTestMutationRequest(nil, nil)
+		request.DynaKube.Status.MetadataEnrichment.Rules = []dynakube.EnrichmentRule{nodeRule}
+		request.Pod.Spec.NodeSelector = map[string]string{"topology.kubernetes.io/zone": "eu-west-1b"}
+
+		sources := RetrieveRuleSources(fake.NewClient(), request, workload)
+
+		assert.Equal(t, "eu-west-1b", sources.Node.Labels["topology.kubernetes.io/zone"])
+	})
+
+	t.Run("fall back to the nodeSelector if the node can't be found", func(t *testing.T) {
+		request := createTestMutationRequest(nil, nil)
+		request.DynaKube.Status.MetadataEnrichment.Rules = []dynakube.EnrichmentRule{nodeRule}
+		request.Pod.Spec.NodeName = "missing"
+		request.Pod.Spec.NodeSelector = map[string]string{"topology.kubernetes.io/zone": "eu-west-1b"}
+
+		sources := RetrieveRuleSources(fake.NewClient(), request, workload)
+
+		assert.Equal(t, "eu-west-1b", sources.Node.Labels["topology.kubernetes.io/zone"])
+	})
+}
@@ -3,6 +3,7 @@ package metadata
 import (
 	"context"
 	"strings"
+	"time"
 
 	kubeobjects "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/pod"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
@@ -12,14 +13,42 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ErrLatencyBudgetExceeded is returned by RetrieveWorkload, if the owner lookup didn't finish within the latency budget of the admission.
+var ErrLatencyBudgetExceeded = errors.New("metadata-enrichment latency budget exceeded")
+
+type latencyBudgetKey struct{}
+
+// WithLatencyBudget marks the context of an admission with the point in time after which the metadata-enrichment is skipped.
+// A budget <= 0 disables the limit.
+func WithLatencyBudget(ctx context.Context, budget time.Duration) context.Context {
+	if budget <= 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, latencyBudgetKey{}, time.Now().Add(budget))
+}
+
+func withLatencyBudgetDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Value(latencyBudgetKey{}).(time.Time)
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithDeadline(ctx, deadline)
+}
+
 type WorkloadInfo struct {
-	Name string
-	Kind string
+	Labels      map[string]string
+	Annotations map[string]string
+	Name        string
+	Kind        string
 }
 
 func newWorkloadInfo(partialObjectMetadata *metav1.PartialObjectMetadata) *WorkloadInfo {
 	return &WorkloadInfo{
-		Name: partialObjectMetadata.ObjectMeta.Name,
+		Name:        partialObjectMetadata.ObjectMeta.Name,
+		Labels:      partialObjectMetadata.ObjectMeta.Labels,
+		Annotations: partialObjectMetadata.ObjectMeta.Annotations,
 
 		// workload kind in lower case according to dt semantic-dictionary
 		// https://docs.dynatrace.com/docs/discover-dynatrace/references/semantic-dictionary/fields#kubernetes
@@ -28,8 +57,15 @@ func newWorkloadInfo(partialObjectMetadata *metav1.PartialObjectMetadata) *Workl
 }
 
 func RetrieveWorkload(metaClient client.Client, request *dtwebhook.MutationRequest) (*WorkloadInfo, error) {
-	workload, err := findRootOwnerOfPod(request.Context, metaClient, request.Pod, request.Namespace.Name)
+	ctx, cancel := withLatencyBudgetDeadline(request.Context)
+	defer cancel()
+
+	workload, err := findRootOwnerOfPod(ctx, metaClient, request.Pod, request.Namespace.Name)
 	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && request.Context.Err() == nil {
+			return nil, errors.WithStack(ErrLatencyBudgetExceeded)
+		}
+
 		return nil, err
 	}
 
@@ -46,6 +82,8 @@ func findRootOwnerOfPod(ctx context.Context, clt client.Client, pod *corev1.Pod,
 			Name: kubeobjects.GetName(*pod),
 			// pod.ObjectMeta.Namespace is empty yet
 			Namespace:       namespace,
+			Labels:          pod.ObjectMeta.Labels,
+			Annotations:     pod.ObjectMeta.Annotations,
 			OwnerReferences: pod.ObjectMeta.OwnerReferences,
 		},
 	}
@@ -73,6 +111,10 @@ func findRootOwner(ctx context.Context, clt client.Client, childObjectMetadata *
 				return childObjectMetadata, nil
 			}
 
+			if err = ctx.Err(); err != nil {
+				return childObjectMetadata, err
+			}
+
 			err = clt.Get(ctx, client.ObjectKey{Name: owner.Name, Namespace: objectMetadata.Namespace}, parentObjectMetadata)
 			if err != nil {
 				log.Error(err, "failed to query the object",
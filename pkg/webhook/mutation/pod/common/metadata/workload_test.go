@@ -3,8 +3,11 @@ package metadata
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -241,3 +244,49 @@ func createFailK8sClient(t *testing.T) client.Client {
 
 	return boomClient
 }
+
+func TestRetrieveWorkloadLatencyBudget(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-pod",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: testReplicaSetName, Controller: ptr.To(true)},
+			},
+		},
+	}
+	namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}
+	clt := fake.NewClient(
+		createReplicaSet(testDeploymentName),
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: testDeploymentName, Namespace: testNamespace}},
+	)
+
+	t.Run("skip lookup if the budget is exceeded", func(t *testing.T) {
+		ctx := WithLatencyBudget(context.Background(), time.Nanosecond)
+		time.Sleep(time.Millisecond)
+
+		request := dtwebhook.NewMutationRequest(ctx, namespace, nil, pod, dynakube.DynaKube{})
+
+		_, err := RetrieveWorkload(clt, request)
+		require.ErrorIs(t, err, ErrLatencyBudgetExceeded)
+	})
+
+	t.Run("look up within the budget", func(t *testing.T) {
+		ctx := WithLatencyBudget(context.Background(), time.Minute)
+		request := dtwebhook.NewMutationRequest(ctx, namespace, nil, pod, dynakube.DynaKube{})
+
+		workload, err := RetrieveWorkload(clt, request)
+		require.NoError(t, err)
+		assert.Equal(t, testDeploymentName, workload.Name)
+	})
+
+	t.Run("don't report a cancelled admission as exceeded budget", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(WithLatencyBudget(context.Background(), time.Minute))
+		cancel()
+
+		request := dtwebhook.NewMutationRequest(ctx, namespace, nil, pod, dynakube.DynaKube{})
+
+		_, err := RetrieveWorkload(clt, request)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrLatencyBudgetExceeded)
+	})
+}
@@ -0,0 +1,141 @@
+package pod
+
+import (
+	"time"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/container"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
+	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	outcomeInjected   = "injected"
+	outcomeSkipped    = "skipped"
+	outcomeFailed     = "failed"
+	outcomeReinvoked  = "reinvoked"
+	injectionPod      = "pod"
+	injectionOneAgent = "oneagent"
+	injectionMetadata = "metadata-enrichment"
+
+	requestFailedReason     = "RequestFailed"
+	noDynaKubeReason        = "NoDynaKube"
+	injectionDisabledReason = "InjectionDisabled"
+	ocDebugPodReason        = "OcDebugPod"
+	mutationFailedReason    = "MutationFailed"
+)
+
+var (
+	admissionDurationMetric = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "dynatrace",
+		Subsystem: "webhook",
+		Name:      "pod_admission_duration_seconds",
+		Help:      "Duration of the pod admissions, by outcome (injected, skipped, failed or reinvoked)",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"outcome"})
+
+	injectionsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "webhook",
+		Name:      "pod_injections_total",
+		Help:      "Pod admissions by injection (oneagent, metadata-enrichment, or pod if the pod was not considered for any injection), outcome, reason, namespace and DynaKube",
+	}, []string{"injection", "outcome", "reason", "namespace", "dynakube"})
+
+	reinvocationsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "dynatrace",
+		Subsystem: "webhook",
+		Name:      "pod_reinvocations_total",
+		Help:      "Reinvocations of the webhook for already injected pods, by namespace and DynaKube",
+	}, []string{"namespace", "dynakube"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(admissionDurationMetric, injectionsMetric, reinvocationsMetric)
+}
+
+// admissionMetrics collects the outcome of a single admission, which is recorded once the admission is done.
+type admissionMetrics struct {
+	start     time.Time
+	pod       *corev1.Pod
+	namespace string
+	dynakube  string
+	outcome   string
+	reason    string
+
+	reinvocation bool
+}
+
+func newAdmissionMetrics(namespace string) *admissionMetrics {
+	return &admissionMetrics{
+		start:     time.Now(),
+		namespace: namespace,
+	}
+}
+
+func (m *admissionMetrics) skipped(reason string) {
+	m.outcome = outcomeSkipped
+	m.reason = reason
+}
+
+func (m *admissionMetrics) failed(reason string) {
+	m.outcome = outcomeFailed
+	m.reason = reason
+}
+
+// handled marks the pod as handled by the injectors, the outcome of every injection is taken from the annotations of the pod.
+func (m *admissionMetrics) handled(pod *corev1.Pod) {
+	m.pod = pod
+}
+
+// startMutation notes if the pod was already injected, so the webhook is only reinvoked.
+func (m *admissionMetrics) startMutation(dynakubeName string, pod *corev1.Pod) {
+	m.dynakube = dynakubeName
+	m.reinvocation = container.FindInitContainerInPodSpec(&pod.Spec, dtwebhook.InstallContainerName) != nil
+}
+
+func (m *admissionMetrics) record() {
+	if m.reinvocation && m.outcome == "" {
+		admissionDurationMetric.WithLabelValues(outcomeReinvoked).Observe(time.Since(m.start).Seconds())
+		reinvocationsMetric.WithLabelValues(m.namespace, m.dynakube).Inc()
+
+		return
+	}
+
+	if m.pod == nil {
+		admissionDurationMetric.WithLabelValues(m.outcome).Observe(time.Since(m.start).Seconds())
+		injectionsMetric.WithLabelValues(injectionPod, m.outcome, m.reason, m.namespace, m.dynakube).Inc()
+
+		return
+	}
+
+	outcome := outcomeSkipped
+
+	injections := []struct {
+		name               string
+		injectedAnnotation string
+		reasonAnnotation   string
+	}{
+		{injectionOneAgent, oacommon.AnnotationInjected, oacommon.AnnotationReason},
+		{injectionMetadata, metacommon.AnnotationInjected, metacommon.AnnotationReason},
+	}
+
+	for _, injection := range injections {
+		injected, ok := m.pod.Annotations[injection.injectedAnnotation]
+		if !ok {
+			continue
+		}
+
+		if injected == "true" {
+			outcome = outcomeInjected
+
+			injectionsMetric.WithLabelValues(injection.name, outcomeInjected, "", m.namespace, m.dynakube).Inc()
+		} else {
+			injectionsMetric.WithLabelValues(injection.name, outcomeSkipped, m.pod.Annotations[injection.reasonAnnotation], m.namespace, m.dynakube).Inc()
+		}
+	}
+
+	admissionDurationMetric.WithLabelValues(outcome).Observe(time.Since(m.start).Seconds())
+}
@@ -0,0 +1,79 @@
+package pod
+
+import (
+	"testing"
+
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
+	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAdmissionMetrics(t *testing.T) {
+	t.Run("injections are recorded by the annotations of the pod", func(t *testing.T) {
+		const namespace = "metrics-injected"
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					oacommon.AnnotationInjected:   "true",
+					metacommon.AnnotationInjected: "false",
+					metacommon.AnnotationReason:   metacommon.LatencyBudgetExceededReason,
+				},
+			},
+		}
+
+		metrics := newAdmissionMetrics(namespace)
+		metrics.startMutation(testDynakubeName, &corev1.Pod{})
+		metrics.handled(pod)
+		metrics.record()
+
+		assert.InDelta(t, 1, testutil.ToFloat64(injectionsMetric.WithLabelValues(injectionOneAgent, outcomeInjected, "", namespace, testDynakubeName)), 0)
+		assert.InDelta(t, 1, testutil.ToFloat64(injectionsMetric.WithLabelValues(injectionMetadata, outcomeSkipped, metacommon.LatencyBudgetExceededReason, namespace, testDynakubeName)), 0)
+	})
+
+	t.Run("skipped pods are recorded with the reason", func(t *testing.T) {
+		const namespace = "metrics-skipped"
+
+		metrics := newAdmissionMetrics(namespace)
+		metrics.startMutation(testDynakubeName, &corev1.Pod{})
+		metrics.skipped(injectionDisabledReason)
+		metrics.record()
+
+		assert.InDelta(t, 1, testutil.ToFloat64(injectionsMetric.WithLabelValues(injectionPod, outcomeSkipped, injectionDisabledReason, namespace, testDynakubeName)), 0)
+	})
+
+	t.Run("failed admissions are recorded", func(t *testing.T) {
+		const namespace = "metrics-failed"
+
+		metrics := newAdmissionMetrics(namespace)
+		metrics.failed(requestFailedReason)
+		metrics.record()
+
+		assert.InDelta(t, 1, testutil.ToFloat64(injectionsMetric.WithLabelValues(injectionPod, outcomeFailed, requestFailedReason, namespace, "")), 0)
+	})
+
+	t.Run("reinvocations are only counted as such", func(t *testing.T) {
+		const namespace = "metrics-reinvoked"
+
+		injectedPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{oacommon.AnnotationInjected: "true"},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: dtwebhook.InstallContainerName}},
+			},
+		}
+
+		metrics := newAdmissionMetrics(namespace)
+		metrics.startMutation(testDynakubeName, injectedPod)
+		metrics.handled(injectedPod)
+		metrics.record()
+
+		assert.InDelta(t, 1, testutil.ToFloat64(reinvocationsMetric.WithLabelValues(namespace, testDynakubeName)), 0)
+		assert.InDelta(t, 0, testutil.ToFloat64(injectionsMetric.WithLabelValues(injectionOneAgent, outcomeInjected, "", namespace, testDynakubeName)), 0)
+	})
+}
@@ -0,0 +1,103 @@
+package pod
+
+import (
+	"context"
+	"os"
+	"time"
+
+	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	workloadOwnerCacheTTLEnv     = "WORKLOAD_OWNER_CACHE_TTL"
+	defaultWorkloadOwnerCacheTTL = 5 * time.Minute
+
+	latencyBudgetEnv = "METADATA_ENRICHMENT_LATENCY_BUDGET"
+)
+
+// setupWorkloadOwnerCache wraps the metaClient with the workload owner cache, which is filled by informers for all namespaces.
+// The informers are started together with the manager, until they are synced (or if the TTL is set to 0) the lookups go to the Kubernetes API.
+func setupWorkloadOwnerCache(ctx context.Context, mgr manager.Manager, metaClient client.Client) (client.Client, error) {
+	ttl := getDurationFromEnv(workloadOwnerCacheTTLEnv, defaultWorkloadOwnerCacheTTL)
+	if ttl <= 0 {
+		log.Info("workload owner cache is disabled")
+
+		return metaClient, nil
+	}
+
+	informerCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:           mgr.GetScheme(),
+		Mapper:           mgr.GetRESTMapper(),
+		DefaultTransform: stripToOwnerReferences,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	ownerCache := metacommon.NewOwnerCache(ttl)
+
+	for _, obj := range metacommon.CachedOwnerKinds() {
+		informer, err := informerCache.GetInformer(ctx, obj)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		err = ownerCache.Watch(obj.GetObjectKind().GroupVersionKind().Kind, informer)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	if err := mgr.Add(informerCache); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := mgr.Add(ownerCache); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	log.Info("workload owner cache is enabled", "ttl", ttl)
+
+	return metacommon.NewOwnerCachingClient(metaClient, ownerCache, informerCache), nil
+}
+
+// stripToOwnerReferences drops everything but the identity, the owners, labels and annotations of the cached objects, to keep the memory footprint of the informers low.
+func stripToOwnerReferences(obj any) (any, error) {
+	partialMetadata, ok := obj.(*metav1.PartialObjectMetadata)
+	if !ok {
+		return obj, nil
+	}
+
+	partialMetadata.ObjectMeta = metav1.ObjectMeta{
+		Name:            partialMetadata.Name,
+		Namespace:       partialMetadata.Namespace,
+		UID:             partialMetadata.UID,
+		ResourceVersion: partialMetadata.ResourceVersion,
+		Labels:          partialMetadata.Labels,
+		Annotations:     partialMetadata.Annotations,
+		OwnerReferences: partialMetadata.OwnerReferences,
+	}
+
+	return partialMetadata, nil
+}
+
+func getDurationFromEnv(envName string, defaultValue time.Duration) time.Duration {
+	rawDuration := os.Getenv(envName)
+	if rawDuration == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(rawDuration)
+	if err != nil {
+		log.Info("custom duration could not be parsed, falling back to default", "env", envName, "value", rawDuration, "default", defaultValue)
+
+		return defaultValue
+	}
+
+	return duration
+}
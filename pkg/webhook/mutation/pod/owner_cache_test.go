@@ -0,0 +1,58 @@
+package pod
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDurationFromEnv(t *testing.T) {
+	const testEnv = "TEST_DURATION"
+
+	t.Run("default if not set", func(t *testing.T) {
+		assert.Equal(t, time.Minute, getDurationFromEnv(testEnv, time.Minute))
+	})
+
+	t.Run("custom value", func(t *testing.T) {
+		t.Setenv(testEnv, "2s")
+		assert.Equal(t, 2*time.Second, getDurationFromEnv(testEnv, time.Minute))
+	})
+
+	t.Run("default if invalid", func(t *testing.T) {
+		t.Setenv(testEnv, "soon")
+		assert.Equal(t, time.Minute, getDurationFromEnv(testEnv, time.Minute))
+	})
+}
+
+func TestStripToOwnerReferences(t *testing.T) {
+	ownerReferences := []metav1.OwnerReference{{Kind: "Deployment", Name: "owner"}}
+	obj := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test",
+			Namespace:       "test-namespace",
+			ResourceVersion: "1",
+			Labels:          map[string]string{"app": "test"},
+			Annotations:     map[string]string{"deployment.kubernetes.io/revision": "3"},
+			OwnerReferences: ownerReferences,
+			ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kube-controller-manager"}},
+			Finalizers:      []string{"test"},
+		},
+	}
+
+	stripped, err := stripToOwnerReferences(obj)
+	require.NoError(t, err)
+
+	assert.Equal(t, &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test",
+			Namespace:       "test-namespace",
+			ResourceVersion: "1",
+			Labels:          map[string]string{"app": "test"},
+			Annotations:     map[string]string{"deployment.kubernetes.io/revision": "3"},
+			OwnerReferences: ownerReferences,
+		},
+	}, stripped)
+}
@@ -0,0 +1,124 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/events"
+	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
+	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
+	podv1 "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/v1"
+	podv2 "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/v2"
+	evanjsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// PreviewConfig contains the values the webhook usually gets from its own deployment and the cluster.
+type PreviewConfig struct {
+	WebhookNamespace string
+	WebhookImage     string
+	ClusterID        string
+}
+
+// PreviewResult is the outcome of the pod mutation, as the webhook would return it for the admission.
+type PreviewResult struct {
+	// Reasons contains the reason annotations of the mutated pod, explaining why (a part of) the injection was skipped.
+	Reasons map[string]string `json:"reasons,omitempty"`
+
+	// Message is set by the webhook, if the pod is not mutated at all.
+	Message string `json:"message,omitempty"`
+
+	Patch    []jsonpatch.JsonPatchOperation `json:"patch"`
+	Injected bool                           `json:"injected"`
+}
+
+// Preview runs the pod mutation of the webhook for the given pod, without a webhook server.
+// All reads and writes (e.g. of the init secrets) go to the given client, so it is meant to be a fake client seeded with the needed objects.
+func Preview(ctx context.Context, clt client.Client, pod *corev1.Pod, podNamespace string, config PreviewConfig) (*PreviewResult, error) {
+	rawPod, err := json.Marshal(pod)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	recorder := events.NewRecorder(&record.FakeRecorder{})
+	wh := &webhook{
+		v1:               podv1.NewInjector(clt, clt, clt, recorder, config.ClusterID, config.WebhookImage, config.WebhookNamespace),
+		v2:               podv2.NewInjector(clt, clt, clt, recorder),
+		recorder:         recorder,
+		apiReader:        clt,
+		webhookNamespace: config.WebhookNamespace,
+		decoder:          admission.NewDecoder(scheme.Scheme),
+	}
+
+	response := wh.Handle(ctx, admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			UID:       "preview",
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			Resource:  metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			Namespace: podNamespace,
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: rawPod},
+		},
+	})
+
+	result := &PreviewResult{
+		Patch: response.Patches,
+	}
+
+	if response.Result != nil {
+		result.Message = response.Result.Message
+	}
+
+	mutatedPod, err := applyPatch(rawPod, response.Patches)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Injected = mutatedPod.Annotations[dtwebhook.AnnotationDynatraceInjected] == "true"
+
+	for _, reasonAnnotation := range []string{dtwebhook.AnnotationDynatraceReason, oacommon.AnnotationReason, metacommon.AnnotationReason} {
+		if reason, ok := mutatedPod.Annotations[reasonAnnotation]; ok {
+			if result.Reasons == nil {
+				result.Reasons = map[string]string{}
+			}
+
+			result.Reasons[reasonAnnotation] = reason
+		}
+	}
+
+	return result, nil
+}
+
+func applyPatch(rawPod []byte, patchOperations []jsonpatch.JsonPatchOperation) (*corev1.Pod, error) {
+	mutatedPod := &corev1.Pod{}
+	if len(patchOperations) == 0 {
+		return mutatedPod, errors.WithStack(json.Unmarshal(rawPod, mutatedPod))
+	}
+
+	rawPatch, err := json.Marshal(patchOperations)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	patch, err := evanjsonpatch.DecodePatch(rawPatch)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	rawMutatedPod, err := patch.Apply(rawPod)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return mutatedPod, errors.WithStack(json.Unmarshal(rawMutatedPod, mutatedPod))
+}
@@ -0,0 +1,116 @@
+package pod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
+	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const testPreviewPodNamespace = "test-pod-namespace"
+
+func TestPreview(t *testing.T) {
+	ctx := context.Background()
+	config := PreviewConfig{
+		WebhookNamespace: testNamespaceName,
+		WebhookImage:     testImage,
+		ClusterID:        testClusterID,
+	}
+
+	t.Run("return patch of the mutated pod", func(t *testing.T) {
+		dk := &dynakube.DynaKube{
+			ObjectMeta: getTestDynakubeMeta(),
+			Spec: dynakube.DynaKubeSpec{
+				APIURL: "https://test.dev.dynatracelabs.com/api",
+				MetadataEnrichment: dynakube.MetadataEnrichment{
+					Enabled: ptr.To(true),
+				},
+			},
+		}
+		clt := fake.NewClient(dk, createPreviewNamespace(testDynakubeName), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: testDynakubeName, Namespace: testNamespaceName},
+			Data:       map[string][]byte{"apiToken": []byte("test-token")},
+		})
+
+		result, err := Preview(ctx, clt, createPreviewPod(), testPreviewPodNamespace, config)
+		require.NoError(t, err)
+
+		assert.True(t, result.Injected)
+		assert.Empty(t, result.Reasons)
+		assert.NotEmpty(t, result.Patch)
+
+		annotationsPatch := findPatch(result, "/metadata/annotations")
+		require.NotNil(t, annotationsPatch)
+		assert.Equal(t, "true", annotationsPatch[metacommon.AnnotationInjected])
+		assert.Equal(t, "pod", annotationsPatch[metacommon.AnnotationWorkloadKind])
+		assert.NotNil(t, findPatch(result, "/spec/initContainers"))
+	})
+
+	t.Run("return message if the namespace is not assigned", func(t *testing.T) {
+		clt := fake.NewClient(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testPreviewPodNamespace}})
+
+		result, err := Preview(ctx, clt, createPreviewPod(), testPreviewPodNamespace, config)
+		require.NoError(t, err)
+
+		assert.False(t, result.Injected)
+		assert.Empty(t, result.Patch)
+		assert.Contains(t, result.Message, "no DynaKube instance set")
+	})
+
+	t.Run("return reasons of skipped injections", func(t *testing.T) {
+		dk := getTestDynakube()
+		clt := fake.NewClient(dk, createPreviewNamespace(testDynakubeName), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: testDynakubeName, Namespace: testNamespaceName},
+			Data:       map[string][]byte{"apiToken": []byte("test-token")},
+		})
+
+		result, err := Preview(ctx, clt, createPreviewPod(), testPreviewPodNamespace, config)
+		require.NoError(t, err)
+
+		assert.NotEmpty(t, result.Reasons)
+	})
+}
+
+func findPatch(result *PreviewResult, path string) map[string]any {
+	for _, operation := range result.Patch {
+		if operation.Path == path {
+			if value, ok := operation.Value.(map[string]any); ok {
+				return value
+			}
+
+			return map[string]any{}
+		}
+	}
+
+	return nil
+}
+
+func createPreviewNamespace(dkName string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   testPreviewPodNamespace,
+			Labels: map[string]string{dtwebhook.InjectionInstanceLabel: dkName},
+		},
+	}
+}
+
+func createPreviewPod() *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testPodName,
+			Namespace: testPreviewPodNamespace,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+		},
+	}
+}
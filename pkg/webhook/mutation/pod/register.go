@@ -48,6 +48,11 @@ func registerInjectEndpoint(ctx context.Context, mgr manager.Manager, webhookNam
 		return errors.WithStack(err)
 	}
 
+	metaClient, err = setupWorkloadOwnerCache(ctx, mgr, metaClient)
+	if err != nil {
+		return err
+	}
+
 	webhookPodImage, err := getWebhookContainerImage(*webhookPod)
 	if err != nil {
 		return err
@@ -64,6 +69,7 @@ func registerInjectEndpoint(ctx context.Context, mgr manager.Manager, webhookNam
 		apiReader:        apiReader,
 		webhookNamespace: webhookNamespace,
 		deployedViaOLM:   kubesystem.IsDeployedViaOlm(*webhookPod),
+		latencyBudget:    getDurationFromEnv(latencyBudgetEnv, 0),
 		decoder:          admission.NewDecoder(mgr.GetScheme()),
 	}})
 	log.Info("registered /inject endpoint")
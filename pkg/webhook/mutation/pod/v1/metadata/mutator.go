@@ -7,6 +7,7 @@ import (
 	dtingestendpoint "github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/ingestendpoint"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,7 +41,12 @@ func (mut *Mutator) Mutate(ctx context.Context, request *dtwebhook.MutationReque
 	log.Info("injecting metadata-enrichment into pod", "podName", request.PodName())
 
 	workload, err := metacommon.RetrieveWorkload(mut.metaClient, request)
-	if err != nil {
+	if errors.Is(err, metacommon.ErrLatencyBudgetExceeded) {
+		log.Info("skipping metadata-enrichment, the latency budget was exceeded", "podName", request.PodName())
+		metacommon.SetNotInjectedAnnotations(request.Pod, metacommon.LatencyBudgetExceededReason)
+
+		return nil
+	} else if err != nil {
 		return err
 	}
 
@@ -52,6 +58,7 @@ func (mut *Mutator) Mutate(ctx context.Context, request *dtwebhook.MutationReque
 	setupVolumes(request.Pod)
 	mutateUserContainers(request.BaseRequest)
 	updateInstallContainer(request.InstallContainer, workload, request.DynaKube.Status.KubernetesClusterName, request.DynaKube.Status.KubernetesClusterMEID)
+	metacommon.CopyMetadataFromRuleSources(request.Pod, metacommon.RetrieveRuleSources(mut.metaClient, request, workload), request.DynaKube)
 	propagateMetadataAnnotations(request)
 	metacommon.SetInjectedAnnotation(request.Pod)
 	metacommon.SetWorkloadAnnotations(request.Pod, workload)
@@ -12,6 +12,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	dtclient "github.com/Dynatrace/dynatrace-operator/pkg/clients/dynatrace"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
 	"github.com/stretchr/testify/assert"
@@ -148,6 +149,22 @@ func TestMutate(t *testing.T) {
 		assert.Len(t, request.InstallContainer.Env, 6)
 		assert.Len(t, request.InstallContainer.VolumeMounts, 1)
 	})
+
+	t.Run("should apply the rules reading from the pod", func(t *testing.T) {
+		mutator := createTestPodMutator([]client.Object{getTestInitSecret()})
+		dk := getTestDynakube()
+		dk.Status.MetadataEnrichment.Rules = []dynakube.EnrichmentRule{
+			{Type: dynakube.EnrichmentLabelRule, Scope: dynakube.EnrichmentPodScope, Source: "app", Target: "app"},
+		}
+		request := createTestMutationRequest(dk, nil, false)
+		request.Pod.Labels = map[string]string{"app": "test-app"}
+
+		err := mutator.Mutate(context.Background(), request)
+		require.NoError(t, err)
+
+		assert.Equal(t, "test-app", request.Pod.Annotations[dynakube.MetadataPrefix+"app"])
+		assert.Contains(t, env.FindEnvVar(request.InstallContainer.Env, consts.EnrichmentWorkloadAnnotationsEnv).Value, `"app":"test-app"`)
+	})
 }
 
 func TestReinvoke(t *testing.T) {
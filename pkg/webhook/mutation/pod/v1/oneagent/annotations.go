@@ -17,12 +17,12 @@ type installerInfo struct {
 	version      string
 }
 
-func getInstallerInfo(pod *corev1.Pod, dk dynakube.DynaKube) installerInfo {
+func getInstallerInfo(pod *corev1.Pod, namespace corev1.Namespace, dk dynakube.DynaKube) installerInfo {
 	return installerInfo{
 		flavor:       maputils.GetField(pod.Annotations, AnnotationFlavor, ""),
 		technologies: url.QueryEscape(maputils.GetField(pod.Annotations, oacommon.AnnotationTechnologies, "all")),
 		installPath:  maputils.GetField(pod.Annotations, oacommon.AnnotationInstallPath, oacommon.DefaultInstallPath),
 		installerURL: maputils.GetField(pod.Annotations, AnnotationInstallerUrl, ""),
-		version:      dk.OneAgent().GetCodeModulesVersion(),
+		version:      dk.OneAgent().GetCodeModulesVersionForNamespace(namespace),
 	}
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"strings"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/bundle"
 	"github.com/Dynatrace/dynatrace-operator/pkg/injection/namespace/initgeneration"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/mounts"
@@ -55,8 +57,8 @@ func (mut *Mutator) Mutate(ctx context.Context, request *dtwebhook.MutationReque
 		return err
 	}
 
-	installerInfo := getInstallerInfo(request.Pod, request.DynaKube)
-	mut.addVolumes(request.Pod, request.DynaKube)
+	installerInfo := getInstallerInfo(request.Pod, request.Namespace, request.DynaKube)
+	mut.addVolumes(request.Pod, request.Namespace, request.DynaKube)
 	mut.configureInitContainer(request, installerInfo)
 	mut.mutateUserContainers(request)
 	addInjectionConfigVolumeMount(request.InstallContainer)
@@ -104,6 +106,11 @@ func (mut *Mutator) ensureInitSecret(request *dtwebhook.MutationRequest) error {
 func (mut *Mutator) isInjectionPossible(request *dtwebhook.MutationRequest) (bool, string) {
 	reasons := []string{}
 
+	// the connection info of a bundle is not in the status, as the tenant is never asked for it
+	if err := bundle.ApplyConnectionInfo(request.Context, mut.apiReader, &request.DynaKube); err != nil {
+		log.Info("failed to get the connection info of the CodeModules bundle", "pod", request.PodName(), "err", err.Error())
+	}
+
 	dk := request.DynaKube
 
 	_, err := dk.TenantUUID()
@@ -113,14 +120,14 @@ func (mut *Mutator) isInjectionPossible(request *dtwebhook.MutationRequest) (boo
 		reasons = append(reasons, oacommon.EmptyTenantUUIDReason)
 	}
 
-	if !dk.OneAgent().IsCommunicationRouteClear() {
+	if !isCommunicationRouteClear(dk) {
 		log.Info("OneAgent communication route is not clear, OneAgent cannot be injected", "pod", request.PodName())
 
 		reasons = append(reasons, oacommon.EmptyConnectionInfoReason)
 	}
 
-	if dk.OneAgent().GetCodeModulesVersion() == "" && dk.OneAgent().GetCodeModulesImage() == "" {
-		log.Info("information about the codemodules (version or image) is not available, OneAgent cannot be injected", "pod", request.PodName())
+	if dk.OneAgent().GetCodeModulesBundle() == nil && dk.OneAgent().GetCodeModulesVersion() == "" && dk.OneAgent().GetCodeModulesImage() == "" {
+		log.Info("information about the codemodules (version, image or bundle) is not available, OneAgent cannot be injected", "pod", request.PodName())
 
 		reasons = append(reasons, oacommon.UnknownCodeModuleReason)
 	}
@@ -132,6 +139,15 @@ func (mut *Mutator) isInjectionPossible(request *dtwebhook.MutationRequest) (boo
 	return true, ""
 }
 
+// isCommunicationRouteClear checks for the communication hosts of the tenant, or the endpoints of the bundle, which only come with its connection info.
+func isCommunicationRouteClear(dk dynakube.DynaKube) bool {
+	if dk.OneAgent().GetCodeModulesBundle() != nil {
+		return dk.Status.OneAgent.ConnectionInfoStatus.Endpoints != ""
+	}
+
+	return dk.OneAgent().IsCommunicationRouteClear()
+}
+
 func ContainerIsInjected(container corev1.Container) bool {
 	return env.IsIn(container.Env, oacommon.DynatraceMetadataEnv) &&
 		env.IsIn(container.Env, oacommon.PreloadEnv) &&
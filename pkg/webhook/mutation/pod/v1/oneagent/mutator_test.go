@@ -13,6 +13,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	"github.com/Dynatrace/dynatrace-operator/pkg/injection/codemodule/installer/bundle"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
 	"github.com/stretchr/testify/assert"
@@ -291,6 +292,8 @@ func TestReinvoke(t *testing.T) {
 func TestIsInjectionPossible(t *testing.T) {
 	t.Run("possible with valid tenant UUID", injectionPossibleWithValidTenantUUID)
 	t.Run("possible without code modules version but with image", injectionPossibleWithCodeModulesImage)
+	t.Run("possible without code modules version but with bundle", injectionPossibleWithCodeModulesBundle)
+	t.Run("not possible with bundle before its connection info is published", injectionNotPossibleWithUnpublishedCodeModulesBundle)
 	t.Run("not possible without tenant UUID", injectionNotPossibleWithoutTenantUUID)
 	t.Run("not possible without communication route", injectionNotPossibleWithoutCommunicationRoute)
 	t.Run("not possible without code modules version", injectionNotPossibleWithoutCodeModulesVersion)
@@ -320,6 +323,40 @@ func injectionPossibleWithCodeModulesImage(t *testing.T) {
 	require.Empty(t, reason)
 }
 
+func injectionPossibleWithCodeModulesBundle(t *testing.T) {
+	dk := getTestDynakube()
+	dk.Status = dynakube.DynaKubeStatus{}
+	dk.Spec.OneAgent.ApplicationMonitoring.CodeModulesBundle = &oneagent.CodeModulesBundleSpec{Path: "/bundles/codemodules"}
+
+	connectionInfo, err := bundle.BuildConnectionInfoConfigMap(dk, bundle.Manifest{
+		ConnectionInfo: bundle.ConnectionInfo{TenantUUID: "bundle-tenant-uuid", Endpoints: "https://bundle.endpoint"},
+	})
+	require.NoError(t, err)
+
+	mutator := createTestPodMutator([]client.Object{connectionInfo})
+	request := createTestMutationRequest(dk, nil, getTestNamespace(nil))
+
+	ok, reason := mutator.isInjectionPossible(request)
+
+	require.True(t, ok)
+	require.Empty(t, reason)
+	assert.Equal(t, "bundle-tenant-uuid", request.DynaKube.Status.OneAgent.ConnectionInfoStatus.TenantUUID)
+}
+
+func injectionNotPossibleWithUnpublishedCodeModulesBundle(t *testing.T) {
+	mutator := createTestPodMutator(nil)
+	dk := getTestDynakube()
+	dk.Status = dynakube.DynaKubeStatus{}
+	dk.Spec.OneAgent.ApplicationMonitoring.CodeModulesBundle = &oneagent.CodeModulesBundleSpec{Path: "/bundles/codemodules"}
+	request := createTestMutationRequest(dk, nil, getTestNamespace(nil))
+
+	ok, reason := mutator.isInjectionPossible(request)
+
+	require.False(t, ok)
+	require.Contains(t, reason, oacommon.EmptyTenantUUIDReason)
+	require.Contains(t, reason, oacommon.EmptyConnectionInfoReason)
+}
+
 func injectionNotPossibleWithoutTenantUUID(t *testing.T) {
 	mutator := createTestPodMutator(nil)
 	dk := getTestDynakube()
@@ -13,9 +13,9 @@ import (
 	"k8s.io/utils/ptr"
 )
 
-func (mut *Mutator) addVolumes(pod *corev1.Pod, dk dynakube.DynaKube) {
+func (mut *Mutator) addVolumes(pod *corev1.Pod, namespace corev1.Namespace, dk dynakube.DynaKube) {
 	addInjectionConfigVolume(pod)
-	addOneAgentVolumes(pod, dk)
+	addOneAgentVolumes(pod, namespace, dk)
 
 	if dk.FF().IsCSIVolumeReadOnly() {
 		addVolumesForReadOnlyCSI(pod)
@@ -121,11 +121,11 @@ func addInjectionConfigVolumeMount(container *corev1.Container) {
 	)
 }
 
-func addOneAgentVolumes(pod *corev1.Pod, dk dynakube.DynaKube) {
+func addOneAgentVolumes(pod *corev1.Pod, namespace corev1.Namespace, dk dynakube.DynaKube) {
 	pod.Spec.Volumes = append(pod.Spec.Volumes,
 		corev1.Volume{
 			Name:         OneAgentBinVolumeName,
-			VolumeSource: getInstallerVolumeSource(dk),
+			VolumeSource: getInstallerVolumeSource(namespace, dk),
 		},
 		corev1.Volume{
 			Name: oneAgentShareVolumeName,
@@ -159,7 +159,7 @@ func addVolumesForReadOnlyCSI(pod *corev1.Pod) {
 	)
 }
 
-func getInstallerVolumeSource(dk dynakube.DynaKube) corev1.VolumeSource {
+func getInstallerVolumeSource(namespace corev1.Namespace, dk dynakube.DynaKube) corev1.VolumeSource {
 	volumeSource := corev1.VolumeSource{}
 	if dk.OneAgent().IsCSIAvailable() {
 		volumeSource.CSI = &corev1.CSIVolumeSource{
@@ -171,6 +171,11 @@ func getInstallerVolumeSource(dk dynakube.DynaKube) corev1.VolumeSource {
 				csivolumes.CSIVolumeAttributeRetryTimeout:  dk.FF().GetCSIMaxRetryTimeout().String(),
 			},
 		}
+
+		if dk.OneAgent().IsCodeModulesCanaryNamespace(namespace) {
+			// the csi-provisioner installs the candidate of the rollout next to the latest CodeModules
+			volumeSource.CSI.VolumeAttributes[csivolumes.CSIVolumeAttributeCanaryField] = "true"
+		}
 	} else {
 		volumeSource.EmptyDir = &corev1.EmptyDirVolumeSource{}
 	}
@@ -4,15 +4,19 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/activegate"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
+	csivolumes "github.com/Dynatrace/dynatrace-operator/pkg/controllers/csi/driver/volumes"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/mounts"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestAddOneAgentVolumeMounts(t *testing.T) {
@@ -122,7 +126,7 @@ func TestAddOneAgentVolumes(t *testing.T) {
 		pod := &corev1.Pod{}
 		dk := getTestCSIDynakube()
 
-		addOneAgentVolumes(pod, *dk)
+		addOneAgentVolumes(pod, corev1.Namespace{}, *dk)
 		require.Len(t, pod.Spec.Volumes, 2)
 		assert.NotNil(t, pod.Spec.Volumes[0].VolumeSource.CSI)
 		assert.False(t, *pod.Spec.Volumes[0].VolumeSource.CSI.ReadOnly)
@@ -132,19 +136,38 @@ func TestAddOneAgentVolumes(t *testing.T) {
 		pod := &corev1.Pod{}
 		dk := getTestReadOnlyCSIDynakube()
 
-		addOneAgentVolumes(pod, *dk)
+		addOneAgentVolumes(pod, corev1.Namespace{}, *dk)
 		require.Len(t, pod.Spec.Volumes, 2)
 		assert.NotNil(t, pod.Spec.Volumes[0].VolumeSource.CSI)
 		assert.True(t, *pod.Spec.Volumes[0].VolumeSource.CSI.ReadOnly)
 	})
 
+	t.Run("should mark the csi volume in the canary namespaces", func(t *testing.T) {
+		dk := getTestCSIDynakube()
+		dk.Spec.OneAgent.Rollout = &oneagent.RolloutSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+		}
+		dk.Status.CodeModules.Rollout = &status.RolloutStatus{Phase: status.RolloutCanaryPhase}
+		canaryNamespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"canary": "true"}}}
+
+		canaryPod := &corev1.Pod{}
+		addOneAgentVolumes(canaryPod, canaryNamespace, *dk)
+		require.NotNil(t, canaryPod.Spec.Volumes[0].VolumeSource.CSI)
+		assert.Equal(t, "true", canaryPod.Spec.Volumes[0].VolumeSource.CSI.VolumeAttributes[csivolumes.CSIVolumeAttributeCanaryField])
+
+		otherPod := &corev1.Pod{}
+		addOneAgentVolumes(otherPod, corev1.Namespace{}, *dk)
+		require.NotNil(t, otherPod.Spec.Volumes[0].VolumeSource.CSI)
+		assert.NotContains(t, otherPod.Spec.Volumes[0].VolumeSource.CSI.VolumeAttributes, csivolumes.CSIVolumeAttributeCanaryField)
+	})
+
 	t.Run("should add oneagent volumes, without csi", func(t *testing.T) {
 		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: false})
 
 		pod := &corev1.Pod{}
 		dk := getTestDynakube()
 
-		addOneAgentVolumes(pod, *dk)
+		addOneAgentVolumes(pod, corev1.Namespace{}, *dk)
 		require.Len(t, pod.Spec.Volumes, 2)
 		assert.NotNil(t, pod.Spec.Volumes[0].VolumeSource.EmptyDir)
 	})
@@ -1,6 +1,7 @@
 package v2
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -104,6 +105,7 @@ func TestAddPodAttributes(t *testing.T) {
 				},
 			},
 			InstallContainer: &initContainer,
+			Context:          context.Background(),
 		}
 
 		err := injector.addPodAttributes(&request)
@@ -161,6 +163,7 @@ func TestAddPodAttributes(t *testing.T) {
 				},
 			},
 			InstallContainer: &initContainer,
+			Context:          context.Background(),
 		}
 
 		err := injector.addPodAttributes(&request)
@@ -217,6 +220,7 @@ func TestAddPodAttributes(t *testing.T) {
 				},
 			},
 			InstallContainer: &initContainer,
+			Context:          context.Background(),
 		}
 
 		err := injector.addPodAttributes(&request)
@@ -13,7 +13,7 @@ import (
 	"k8s.io/utils/ptr"
 )
 
-func createInitContainerBase(pod *corev1.Pod, dk dynakube.DynaKube) *corev1.Container {
+func createInitContainerBase(pod *corev1.Pod, namespace corev1.Namespace, dk dynakube.DynaKube) *corev1.Container {
 	args := []arg.Arg{
 		{
 			Name:  configure.ConfigFolderFlag,
@@ -31,7 +31,7 @@ func createInitContainerBase(pod *corev1.Pod, dk dynakube.DynaKube) *corev1.Cont
 
 	initContainer := &corev1.Container{
 		Name:            dtwebhook.InstallContainerName,
-		Image:           dk.OneAgent().GetCustomCodeModulesImage(),
+		Image:           dk.OneAgent().GetCodeModulesImageForNamespace(namespace),
 		ImagePullPolicy: corev1.PullIfNotPresent,
 		SecurityContext: securityContextForInitContainer(pod, dk),
 		Resources:       initContainerResources(dk),
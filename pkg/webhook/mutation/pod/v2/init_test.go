@@ -5,6 +5,8 @@ import (
 
 	"github.com/Dynatrace/dynatrace-bootstrapper/cmd"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/mounts"
 	volumeutils "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/volumes"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 )
 
@@ -23,7 +26,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod.Spec.Containers[0].SecurityContext.RunAsUser = nil
 		pod.Spec.Containers[0].SecurityContext.RunAsGroup = nil
 
-		initContainer := createInitContainerBase(pod, *dk)
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		require.NotNil(t, initContainer)
 		assert.Equal(t, dtwebhook.InstallContainerName, initContainer.Name)
@@ -50,6 +53,32 @@ func TestCreateInitContainerBase(t *testing.T) {
 
 		assert.Nil(t, initContainer.SecurityContext.SeccompProfile)
 	})
+	t.Run("should use the verified image of the status instead of the custom image", func(t *testing.T) {
+		verifiedImage := customImage + "@sha256:7173b809ca12ec5dee4506cd86be934c4596dd234ee82c0662eac04a8c2c71dc"
+		dk := getTestDynakube()
+		dk.Status.CodeModules.ImageID = verifiedImage
+		pod := getTestPod()
+
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
+
+		assert.Equal(t, verifiedImage, initContainer.Image)
+	})
+	t.Run("should use the image of the candidate in the canary namespaces", func(t *testing.T) {
+		candidateImage := customImage + "@sha256:0c9a4e30b9b7e6b7b6a7dc5e1d9bd1e8a32f3c0e2f8b4c4e6d1ee3cce2b5d611"
+		dk := getTestDynakube()
+		dk.Spec.OneAgent.Rollout = &oneagent.RolloutSpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+		}
+		dk.Status.CodeModules.Rollout = &status.RolloutStatus{
+			Phase:     status.RolloutCanaryPhase,
+			Candidate: status.VersionStatus{ImageID: candidateImage},
+		}
+		pod := getTestPod()
+		canaryNamespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"canary": "true"}}}
+
+		assert.Equal(t, candidateImage, createInitContainerBase(pod, canaryNamespace, *dk).Image)
+		assert.Equal(t, customImage, createInitContainerBase(pod, corev1.Namespace{}, *dk).Image)
+	})
 	t.Run("do not take security context from user container", func(t *testing.T) {
 		dk := getTestDynakube()
 		pod := getTestPod()
@@ -57,7 +86,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod.Spec.Containers[0].SecurityContext.RunAsUser = testUser
 		pod.Spec.Containers[0].SecurityContext.RunAsGroup = testUser
 
-		initContainer := createInitContainerBase(pod, *dk)
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		require.NotNil(t, initContainer.SecurityContext.RunAsNonRoot)
 		assert.True(t, *initContainer.SecurityContext.RunAsNonRoot)
@@ -77,7 +106,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod.Spec.SecurityContext.RunAsUser = testUser
 		pod.Spec.SecurityContext.RunAsGroup = testUser
 
-		initContainer := createInitContainerBase(pod, *dk)
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		require.NotNil(t, initContainer.SecurityContext.RunAsNonRoot)
 		assert.True(t, *initContainer.SecurityContext.RunAsNonRoot)
@@ -95,7 +124,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod.Spec.SecurityContext.RunAsUser = ptr.To(oacommon.RootUserGroup)
 		pod.Spec.SecurityContext.RunAsGroup = ptr.To(oacommon.RootUserGroup)
 
-		initContainer := createInitContainerBase(pod, *dk)
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		assert.NotNil(t, initContainer.SecurityContext.RunAsNonRoot)
 		assert.False(t, *initContainer.SecurityContext.RunAsNonRoot)
@@ -112,7 +141,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod := getTestPod()
 		pod.Annotations = map[string]string{}
 
-		initContainer := createInitContainerBase(pod, *dk)
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, initContainer.SecurityContext.SeccompProfile.Type)
 	})
@@ -123,7 +152,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod := getTestPod()
 		pod.Annotations = map[string]string{}
 
-		initContainer := createInitContainerBase(pod, *dk)
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		assert.NotContains(t, initContainer.Args, "--"+cmd.SuppressErrorsFlag)
 	})
@@ -134,7 +163,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod := getTestPod()
 		pod.Annotations = map[string]string{dtwebhook.AnnotationFailurePolicy: "fail"}
 
-		initContainer := createInitContainerBase(pod, *dk)
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		assert.NotContains(t, initContainer.Args, "--"+cmd.SuppressErrorsFlag)
 	})
@@ -145,7 +174,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod := getTestPod()
 		pod.Annotations = map[string]string{}
 
-		initContainer := createInitContainerBase(pod, *dk)
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		assert.Contains(t, initContainer.Args, "--"+cmd.SuppressErrorsFlag)
 	})
@@ -156,7 +185,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod := getTestPod()
 		pod.Annotations = map[string]string{}
 
-		initContainer := createInitContainerBase(pod, *dk)
+		initContainer := createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		assert.Contains(t, initContainer.Args, "--"+cmd.SuppressErrorsFlag)
 
@@ -165,7 +194,7 @@ func TestCreateInitContainerBase(t *testing.T) {
 		pod = getTestPod()
 		pod.Annotations = map[string]string{dtwebhook.AnnotationFailurePolicy: "asd"}
 
-		initContainer = createInitContainerBase(pod, *dk)
+		initContainer = createInitContainerBase(pod, corev1.Namespace{}, *dk)
 
 		assert.Contains(t, initContainer.Args, "--"+cmd.SuppressErrorsFlag)
 	})
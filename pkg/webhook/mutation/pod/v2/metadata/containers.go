@@ -8,6 +8,7 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
+	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -19,7 +20,12 @@ func Mutate(metaClient client.Client, request *dtwebhook.MutationRequest, attrib
 	log.Info("adding metadata-enrichment to pod", "name", request.PodName())
 
 	workloadInfo, err := metacommon.RetrieveWorkload(metaClient, request)
-	if err != nil {
+	if errors.Is(err, metacommon.ErrLatencyBudgetExceeded) {
+		log.Info("skipping metadata-enrichment, the latency budget was exceeded", "name", request.PodName())
+		metacommon.SetNotInjectedAnnotations(request.Pod, metacommon.LatencyBudgetExceededReason)
+
+		return nil
+	} else if err != nil {
 		return err
 	}
 
@@ -28,6 +34,7 @@ func Mutate(metaClient client.Client, request *dtwebhook.MutationRequest, attrib
 		WorkloadName: workloadInfo.Name,
 	}
 
+	metacommon.CopyMetadataFromRuleSources(request.Pod, metacommon.RetrieveRuleSources(metaClient, request, workloadInfo), request.DynaKube)
 	addMetadataToInitArgs(request, attributes)
 
 	metacommon.SetInjectedAnnotation(request.Pod)
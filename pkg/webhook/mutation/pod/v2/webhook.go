@@ -56,7 +56,7 @@ func (wh *Injector) Handle(_ context.Context, mutationRequest *dtwebhook.Mutatio
 		return nil
 	}
 
-	if !isCustomImageSet(mutationRequest) {
+	if !isCodeModulesImageSet(mutationRequest) {
 		return nil
 	}
 
@@ -94,7 +94,7 @@ func (wh *Injector) isInjected(mutationRequest *dtwebhook.MutationRequest) bool
 }
 
 func (wh *Injector) handlePodMutation(mutationRequest *dtwebhook.MutationRequest) error {
-	mutationRequest.InstallContainer = createInitContainerBase(mutationRequest.Pod, mutationRequest.DynaKube)
+	mutationRequest.InstallContainer = createInitContainerBase(mutationRequest.Pod, mutationRequest.Namespace, mutationRequest.DynaKube)
 
 	err := addContainerAttributes(mutationRequest)
 	if err != nil {
@@ -138,9 +138,11 @@ func (wh *Injector) handlePodReinvocation(mutationRequest *dtwebhook.MutationReq
 	return updated
 }
 
-func isCustomImageSet(mutationRequest *dtwebhook.MutationRequest) bool {
-	customImage := mutationRequest.DynaKube.OneAgent().GetCustomCodeModulesImage()
-	if customImage == "" {
+// isCodeModulesImageSet checks for the image in the status, which is the custom image after it passed the verification, pinned to its digest.
+// Pods in the canary namespaces of a CodeModules rollout get the image of the candidate instead.
+func isCodeModulesImageSet(mutationRequest *dtwebhook.MutationRequest) bool {
+	image := mutationRequest.DynaKube.OneAgent().GetCodeModulesImageForNamespace(mutationRequest.Namespace)
+	if image == "" {
 		oacommon.SetNotInjectedAnnotations(mutationRequest.Pod, NoCodeModulesImageReason)
 
 		return false
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/exp"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/status"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/consts"
@@ -245,6 +246,7 @@ func getTestDynakube() *dynakube.DynaKube {
 			KubernetesClusterMEID: "meid",
 			KubeSystemUUID:        "systemuuid",
 			KubernetesClusterName: "meidname",
+			CodeModules:           getCodeModulesStatus(),
 		},
 	}
 }
@@ -262,6 +264,16 @@ func getTestDynakubeNoInitLimits() *dynakube.DynaKube {
 		Spec: dynakube.DynaKubeSpec{
 			OneAgent: getAppMonSpec(nil),
 		},
+		Status: dynakube.DynaKubeStatus{
+			CodeModules: getCodeModulesStatus(),
+		},
+	}
+}
+
+// getCodeModulesStatus returns the status of the custom image, as the version reconciler sets it after the verification.
+func getCodeModulesStatus() oneagent.CodeModulesStatus {
+	return oneagent.CodeModulesStatus{
+		VersionStatus: status.VersionStatus{ImageID: customImage},
 	}
 }
 
@@ -364,7 +376,7 @@ func getInjectedPod() *corev1.Pod {
 			},
 		},
 	}
-	installContainer := createInitContainerBase(pod, *getTestDynakube())
+	installContainer := createInitContainerBase(pod, corev1.Namespace{}, *getTestDynakube())
 	pod.Spec.InitContainers = append(pod.Spec.InitContainers, *installContainer)
 
 	return pod
@@ -381,7 +393,7 @@ func getTestNamespace() *corev1.Namespace {
 	}
 }
 
-func TestIsCustomImageSet(t *testing.T) {
+func TestIsCodeModulesImageSet(t *testing.T) {
 	t.Run("true", func(t *testing.T) {
 		request := dtwebhook.MutationRequest{
 			BaseRequest: &dtwebhook.BaseRequest{
@@ -389,7 +401,7 @@ func TestIsCustomImageSet(t *testing.T) {
 			},
 		}
 
-		assert.True(t, isCustomImageSet(&request))
+		assert.True(t, isCodeModulesImageSet(&request))
 	})
 	t.Run("false, set annotations", func(t *testing.T) {
 		request := dtwebhook.MutationRequest{
@@ -399,9 +411,9 @@ func TestIsCustomImageSet(t *testing.T) {
 			},
 		}
 
-		request.DynaKube.Spec.OneAgent.ApplicationMonitoring.CodeModulesImage = ""
+		request.DynaKube.Status.CodeModules.ImageID = ""
 
-		assert.False(t, isCustomImageSet(&request))
+		assert.False(t, isCodeModulesImageSet(&request))
 		assert.Equal(t, NoCodeModulesImageReason, request.Pod.Annotations[oacommon.AnnotationReason])
 		assert.Equal(t, "false", request.Pod.Annotations[oacommon.AnnotationInjected])
 	})
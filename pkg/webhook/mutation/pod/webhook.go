@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/env"
 	k8spod "github.com/Dynatrace/dynatrace-operator/pkg/util/kubeobjects/pod"
 	maputils "github.com/Dynatrace/dynatrace-operator/pkg/util/map"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/events"
+	metacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/metadata"
+	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
 	podv2 "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/v2"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,28 +52,47 @@ type webhook struct {
 
 	webhookNamespace string
 	deployedViaOLM   bool
+
+	// latencyBudget is the time after the start of the admission, after which the metadata-enrichment is skipped, 0 disables it.
+	latencyBudget time.Duration
 }
 
 func (wh *webhook) Handle(ctx context.Context, request admission.Request) admission.Response {
+	ctx = metacommon.WithLatencyBudget(ctx, wh.latencyBudget)
+
+	metrics := newAdmissionMetrics(request.Namespace)
+	defer metrics.record()
+
 	emptyPatch := admission.Patched("")
 	mutationRequest, err := wh.createMutationRequestBase(ctx, request)
 
 	if err != nil {
 		emptyPatch.Result.Message = fmt.Sprintf("unable to inject into pod (err=%s)", err.Error())
 		log.Error(err, "building mutation request base encountered an error")
+		metrics.failed(requestFailedReason)
 
 		return emptyPatch
 	}
 
 	if mutationRequest == nil {
 		emptyPatch.Result.Message = "injection into pod not required"
+		metrics.skipped(noDynaKubeReason)
 
 		return emptyPatch
 	}
 
 	podName := mutationRequest.PodName()
+	metrics.startMutation(mutationRequest.DynaKube.Name, mutationRequest.Pod)
+
+	if !mutationRequired(mutationRequest) {
+		metrics.skipped(injectionDisabledReason)
+
+		return emptyPatch
+	}
+
+	if wh.isOcDebugPod(mutationRequest.Pod) {
+		metrics.skipped(ocDebugPodReason)
 
-	if !mutationRequired(mutationRequest) || wh.isOcDebugPod(mutationRequest.Pod) {
 		return emptyPatch
 	}
 
@@ -79,16 +101,23 @@ func (wh *webhook) Handle(ctx context.Context, request admission.Request) admiss
 	if podv2.IsEnabled(mutationRequest) {
 		err := wh.v2.Handle(ctx, mutationRequest)
 		if err != nil {
+			metrics.failed(mutationFailedReason)
+
 			return silentErrorResponse(mutationRequest.Pod, err)
 		}
 	} else {
 		err := wh.v1.Handle(ctx, mutationRequest)
 		if err != nil {
+			metrics.failed(mutationFailedReason)
+
 			return silentErrorResponse(mutationRequest.Pod, err)
 		}
 	}
 
+	setInjectedPodLabel(mutationRequest)
+
 	log.Info("injection finished for pod", "podName", podName, "namespace", request.Namespace)
+	metrics.handled(mutationRequest.Pod)
 
 	return createResponseForPod(mutationRequest.Pod, request)
 }
@@ -108,6 +137,20 @@ func mutationRequired(mutationRequest *dtwebhook.MutationRequest) bool {
 	return enabledOnPod && enabledOnContainers
 }
 
+// setInjectedPodLabel marks the Pods the OneAgent was injected into, so they can be selected by the egress policies of the DynaKube.
+// Pods are only labeled, if the egress policies of the DynaKube select the injected pods.
+func setInjectedPodLabel(mutationRequest *dtwebhook.MutationRequest) {
+	if !mutationRequest.DynaKube.Egress().SelectsInjectedPods() || !oacommon.IsInjected(mutationRequest.BaseRequest) {
+		return
+	}
+
+	if mutationRequest.Pod.Labels == nil {
+		mutationRequest.Pod.Labels = make(map[string]string)
+	}
+
+	mutationRequest.Pod.Labels[dtwebhook.InjectedPodLabel] = mutationRequest.DynaKube.Name
+}
+
 func (wh *webhook) isOcDebugPod(pod *corev1.Pod) bool {
 	annotations := []string{ocDebugAnnotationsContainer, ocDebugAnnotationsResource}
 
@@ -9,10 +9,12 @@ import (
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/scheme/fake"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube"
+	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/egress"
 	"github.com/Dynatrace/dynatrace-operator/pkg/api/v1beta4/dynakube/oneagent"
 	"github.com/Dynatrace/dynatrace-operator/pkg/util/installconfig"
 	dtwebhook "github.com/Dynatrace/dynatrace-operator/pkg/webhook"
 	"github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/events"
+	oacommon "github.com/Dynatrace/dynatrace-operator/pkg/webhook/mutation/pod/common/oneagent"
 	webhookmock "github.com/Dynatrace/dynatrace-operator/test/mocks/pkg/webhook"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -258,6 +260,90 @@ func TestHandle(t *testing.T) {
 		assert.NotEqual(t, admission.Patched(""), resp)
 	})
 
+	t.Run("OneAgent injected, egress selects injected pods ==> pod labeled with DK name", func(t *testing.T) {
+		v1Injector := webhookmock.NewPodInjector(t)
+		v1Injector.On("Handle", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			oacommon.SetInjectedAnnotation(args.Get(1).(*dtwebhook.MutationRequest).Pod)
+		}).Return(nil)
+		dk := getTestDynakubeDefaultAppMon()
+		dk.Spec.Egress = &egress.Spec{Backend: egress.NetworkPolicyBackend, InjectedPods: true}
+		wh := createTestWebhook(
+			v1Injector,
+			webhookmock.NewPodInjector(t),
+			[]client.Object{
+				getTestNamespace(),
+				dk,
+			},
+		)
+
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: false})
+
+		request := createTestAdmissionRequest(getTestPod())
+
+		resp := wh.Handle(ctx, *request)
+		require.NotNil(t, resp)
+
+		var labels map[string]any
+
+		for _, patch := range resp.Patches {
+			if patch.Path == "/metadata/labels" {
+				labels, _ = patch.Value.(map[string]any)
+			}
+		}
+
+		assert.Equal(t, map[string]any{dtwebhook.InjectedPodLabel: testDynakubeName}, labels)
+	})
+
+	t.Run("OneAgent injected, egress disabled ==> pod not labeled", func(t *testing.T) {
+		v1Injector := webhookmock.NewPodInjector(t)
+		v1Injector.On("Handle", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			oacommon.SetInjectedAnnotation(args.Get(1).(*dtwebhook.MutationRequest).Pod)
+		}).Return(nil)
+		wh := createTestWebhook(
+			v1Injector,
+			webhookmock.NewPodInjector(t),
+			[]client.Object{
+				getTestNamespace(),
+				getTestDynakubeDefaultAppMon(),
+			},
+		)
+
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: false})
+
+		request := createTestAdmissionRequest(getTestPod())
+
+		resp := wh.Handle(ctx, *request)
+		require.NotNil(t, resp)
+
+		for _, patch := range resp.Patches {
+			assert.NotEqual(t, "/metadata/labels", patch.Path)
+		}
+	})
+
+	t.Run("OneAgent not injected ==> pod not labeled", func(t *testing.T) {
+		v1Injector := webhookmock.NewPodInjector(t)
+		v1Injector.On("Handle", mock.Anything, mock.Anything).Return(nil)
+		wh := createTestWebhook(
+			v1Injector,
+			webhookmock.NewPodInjector(t),
+			[]client.Object{
+				getTestNamespace(),
+				getTestDynakubeDefaultAppMon(),
+			},
+		)
+
+		installconfig.SetModulesOverride(t, installconfig.Modules{CSIDriver: false})
+
+		request := createTestAdmissionRequest(getTestPod())
+
+		resp := wh.Handle(ctx, *request)
+		require.NotNil(t, resp)
+
+		for _, patch := range resp.Patches {
+			assert.NotEqual(t, "/metadata/labels", patch.Path)
+		}
+	})
+
 	t.Run("v1 injector error => silent error", func(t *testing.T) {
 		v1Injector := webhookmock.NewPodInjector(t)
 		v1Injector.On("Handle", mock.Anything, mock.Anything).Return(errors.New("BOOM"))
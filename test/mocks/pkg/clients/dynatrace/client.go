@@ -1280,25 +1280,23 @@ func (_c *Client_GetSettingsForMonitoredEntity_Call) RunAndReturn(run func(conte
 	return _c
 }
 
-// GetTokenScopes provides a mock function with given fields: ctx, token
-func (_m *Client) GetTokenScopes(ctx context.Context, token string) (dynatrace.TokenScopes, error) {
+// GetTokenInfo provides a mock function with given fields: ctx, token
+func (_m *Client) GetTokenInfo(ctx context.Context, token string) (dynatrace.TokenInfo, error) {
 	ret := _m.Called(ctx, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTokenScopes")
+		panic("no return value specified for GetTokenInfo")
 	}
 
-	var r0 dynatrace.TokenScopes
+	var r0 dynatrace.TokenInfo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (dynatrace.TokenScopes, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (dynatrace.TokenInfo, error)); ok {
 		return rf(ctx, token)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) dynatrace.TokenScopes); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) dynatrace.TokenInfo); ok {
 		r0 = rf(ctx, token)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(dynatrace.TokenScopes)
-		}
+		r0 = ret.Get(0).(dynatrace.TokenInfo)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
@@ -1310,31 +1308,31 @@ func (_m *Client) GetTokenScopes(ctx context.Context, token string) (dynatrace.T
 	return r0, r1
 }
 
-// Client_GetTokenScopes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTokenScopes'
-type Client_GetTokenScopes_Call struct {
+// Client_GetTokenInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTokenInfo'
+type Client_GetTokenInfo_Call struct {
 	*mock.Call
 }
 
-// GetTokenScopes is a helper method to define mock.On call
+// GetTokenInfo is a helper method to define mock.On call
 //   - ctx context.Context
 //   - token string
-func (_e *Client_Expecter) GetTokenScopes(ctx interface{}, token interface{}) *Client_GetTokenScopes_Call {
-	return &Client_GetTokenScopes_Call{Call: _e.mock.On("GetTokenScopes", ctx, token)}
+func (_e *Client_Expecter) GetTokenInfo(ctx interface{}, token interface{}) *Client_GetTokenInfo_Call {
+	return &Client_GetTokenInfo_Call{Call: _e.mock.On("GetTokenInfo", ctx, token)}
 }
 
-func (_c *Client_GetTokenScopes_Call) Run(run func(ctx context.Context, token string)) *Client_GetTokenScopes_Call {
+func (_c *Client_GetTokenInfo_Call) Run(run func(ctx context.Context, token string)) *Client_GetTokenInfo_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *Client_GetTokenScopes_Call) Return(_a0 dynatrace.TokenScopes, _a1 error) *Client_GetTokenScopes_Call {
+func (_c *Client_GetTokenInfo_Call) Return(_a0 dynatrace.TokenInfo, _a1 error) *Client_GetTokenInfo_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Client_GetTokenScopes_Call) RunAndReturn(run func(context.Context, string) (dynatrace.TokenScopes, error)) *Client_GetTokenScopes_Call {
+func (_c *Client_GetTokenInfo_Call) RunAndReturn(run func(context.Context, string) (dynatrace.TokenInfo, error)) *Client_GetTokenInfo_Call {
 	_c.Call.Return(run)
 	return _c
 }